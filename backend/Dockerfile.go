@@ -1,5 +1,8 @@
 FROM golang:1.24-alpine
 
+# ffmpeg декодирует MP3 для измерения громкости
+RUN apk add --no-cache ffmpeg
+
 WORKDIR /app
 
 
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"music-service/internal/authz"
+	"music-service/internal/config"
+	"music-service/internal/logging"
+	"music-service/internal/password"
+	"music-service/internal/repository"
+	"music-service/internal/repository/db"
+	"music-service/internal/tokens"
+	"music-service/internal/usecases"
+	"os"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+const usage = `Использование: admin <команда> -login <логин>
+
+Команды:
+  create-admin   создать пользователя с правами администратора
+  set-password   задать новый пароль пользователю и завершить его сессии
+
+Пароль берется из переменной ADMIN_PASSWORD, а если она не задана —
+из первой строки стандартного ввода, и проверяется по политике паролей из
+configs/config.yaml. Параметры подключения к БД задаются переменными
+DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME, DB_SSLMODE.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command := os.Args[1]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	login := flags.String("login", "", "логин пользователя")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flags.Parse(os.Args[2:])
+
+	if *login == "" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	newPassword, err := readPassword()
+	if err != nil {
+		log.Fatalf("Ошибка чтения пароля: %v", err)
+	}
+
+	cfg, err := config.NewConfig("configs/config.yaml")
+	if err != nil {
+		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+	// Сообщения утилиты выводятся через log, служебные записи (подключение
+	// к БД) — через slog; и то и другое в текстовом виде с маскированием секретов
+	logger, err := logging.New(logging.Config{Level: cfg.Log.Level, Format: "text"}, os.Stderr)
+	if err != nil {
+		log.Fatalf("Ошибка настройки логирования: %v", err)
+	}
+	slog.SetDefault(logger)
+
+	passwordPolicy, err := password.NewPolicy(password.Config{
+		MinLength:        cfg.Password.MinLength,
+		MaxLength:        cfg.Password.MaxLength,
+		RequireLetter:    cfg.Password.RequireLetter,
+		RequireDigit:     cfg.Password.RequireDigit,
+		BreachedListPath: cfg.Password.BreachedList,
+	})
+	if err != nil {
+		log.Fatalf("Ошибка загрузки политики паролей: %v", err)
+	}
+
+	repo, err := repository.NewRepository(db.Config{
+		Host:     os.Getenv("DB_HOST"),
+		Port:     os.Getenv("DB_PORT"),
+		Username: os.Getenv("DB_USER"),
+		Password: os.Getenv("DB_PASSWORD"),
+		DBName:   os.Getenv("DB_NAME"),
+		SSLMode:  os.Getenv("DB_SSLMODE"),
+		Logger:   logger,
+	})
+	if err != nil {
+		log.Fatalf("Ошибка подключения к БД: %v", err)
+	}
+
+	// Утилита не выдает токены: отзыв сессий записывается в БД, откуда его
+	// подхватывают инстансы API, поэтому достаточно временного ключа
+	signer, err := tokens.NewSigner("cli", map[string][]byte{"cli": tokens.GenerateKey()})
+	if err != nil {
+		log.Fatalf("Ошибка создания ключа подписи: %v", err)
+	}
+	tokenManager := tokens.NewManager(signer, tokens.NewRevocationList(), 0, 0)
+
+	// Утилита не выполняет вход, поэтому блокировка после неудач и
+	// ограничение входа сотрудников не нужны
+	userUseCase := usecases.NewUserUseCase(repo.User, repo.Session, repo.MFA, repo.UnitOfWork, tokenManager, passwordPolicy, nil, false)
+	ctx := authz.WithSystem(logging.NewContext(context.Background(), logger))
+
+	switch command {
+	case "create-admin":
+		user, err := userUseCase.CreateAdmin(ctx, *login, newPassword)
+		if err != nil {
+			log.Fatalf("Не удалось создать администратора: %v", err)
+		}
+		fmt.Printf("Администратор %s создан (ID: %s)\n", user.Login, user.ID)
+	case "set-password":
+		if err := userUseCase.SetPassword(ctx, *login, newPassword); err != nil {
+			log.Fatalf("Не удалось задать пароль: %v", err)
+		}
+		fmt.Printf("Пароль пользователя %s изменен, все сессии завершены\n", *login)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func readPassword() (string, error) {
+	if password := os.Getenv("ADMIN_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Пароль: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("пароль не может быть пустым")
+	}
+	return password, nil
+}
@@ -1,33 +1,60 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"log/slog"
+	"music-service/internal/audio"
+	"music-service/internal/authz"
 	"music-service/internal/config"
 	"music-service/internal/delivery/http/router"
+	"music-service/internal/events"
+	"music-service/internal/imaging"
+	"music-service/internal/logging"
+	"music-service/internal/mail"
+	"music-service/internal/oidc"
+	"music-service/internal/outbox"
+	"music-service/internal/password"
+	"music-service/internal/ratelimit"
 	"music-service/internal/repository"
 	"music-service/internal/repository/db"
+	"music-service/internal/tokens"
 	"music-service/internal/usecases"
+	"music-service/internal/usecases/interfaces"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"time"
 
 	_ "github.com/lib/pq"
 )
 
 func main() {
+	// До загрузки конфигурации ошибки пишутся логгером с настройками по умолчанию
+	logger, _ := logging.New(logging.Config{}, os.Stderr)
+
 	cfg, err := config.NewConfig("configs/config.yaml")
 	if err != nil {
-		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
+		fatal(logger, "load config failed", err)
+	}
+
+	logger, err = logging.New(logging.Config{Level: cfg.Log.Level, Format: cfg.Log.Format}, os.Stderr)
+	if err != nil {
+		logger, _ = logging.New(logging.Config{}, os.Stderr)
+		fatal(logger, "invalid log config", err)
 	}
+	// Стандартный log и slog по умолчанию (сторонние библиотеки, фоновые
+	// задачи без логгера в контексте) пишут через тот же обработчик
+	slog.SetDefault(logger)
+	ctx := logging.NewContext(context.Background(), logger)
 
 	if err := os.MkdirAll(cfg.Storage.TracksDir, 0755); err != nil {
-		log.Fatalf("Ошибка создания директории для хранения треков: %v", err)
+		fatal(logger, "create tracks directory failed", err)
 	}
 
 	tracksDir, err := filepath.Abs(cfg.Storage.TracksDir)
 	if err != nil {
-		log.Fatalf("Ошибка получения абсолютного пути к директории треков: %v", err)
+		fatal(logger, "resolve tracks directory failed", err)
 	}
 
 	dbConfig := db.Config{
@@ -38,54 +65,479 @@ func main() {
 		DBName:    os.Getenv("DB_NAME"),
 		SSLMode:   os.Getenv("DB_SSLMODE"),
 		TracksDir: tracksDir,
+		Logger:    logger,
 	}
 
 	repo, err := repository.NewRepository(dbConfig)
 	if err != nil {
-		log.Fatalf("Ошибка создания репозитория: %v", err)
+		fatal(logger, "create repository failed", err)
+	}
+
+	bus := events.NewMemoryBus(events.DefaultReplayEvents, events.DefaultReplayWindow, logger)
+
+	signer, err := newSigner(logger)
+	if err != nil {
+		fatal(logger, "load token signing keys failed", err)
+	}
+	revocations := tokens.NewRevocationList()
+	go revocations.Run(ctx, repo.Session.ListRevokedSessions, cfg.Auth.RevocationSyncInterval)
+	tokenManager := tokens.NewManager(signer, revocations, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+
+	passwordPolicy, err := password.NewPolicy(password.Config{
+		MinLength:        cfg.Password.MinLength,
+		MaxLength:        cfg.Password.MaxLength,
+		RequireLetter:    cfg.Password.RequireLetter,
+		RequireDigit:     cfg.Password.RequireDigit,
+		BreachedListPath: cfg.Password.BreachedList,
+	})
+	if err != nil {
+		fatal(logger, "load password policy failed", err)
 	}
+	mailer, err := mail.NewSender(mail.Config{
+		Driver: cfg.Mail.Driver,
+		From:   cfg.Mail.From,
+		Dir:    cfg.Mail.Dir,
+		Logger: logger,
+	})
+	if err != nil {
+		fatal(logger, "configure mail sender failed", err)
+	}
+
+	// Хранилище лимитов в памяти: при нескольких инстансах API лимиты
+	// считаются каждым инстансом отдельно
+	rateLimitStore := ratelimit.NewMemoryStore()
+	lockout := ratelimit.NewLockout(rateLimitStore, ratelimit.LockoutPolicy{
+		Threshold:     cfg.RateLimit.Lockout.Threshold,
+		BaseDelay:     cfg.RateLimit.Lockout.BaseDelay,
+		MaxDelay:      cfg.RateLimit.Lockout.MaxDelay,
+		FailureWindow: cfg.RateLimit.Lockout.FailureWindow,
+	})
 
-	userUseCase := usecases.NewUserUseCase(repo.User, repo.Session)
+	identityProviders := newIdentityProviders(logger, cfg.OIDC.Providers)
+	userUseCase := usecases.NewUserUseCase(
+		repo.User,
+		repo.Session,
+		repo.MFA,
+		repo.UnitOfWork,
+		tokenManager,
+		passwordPolicy,
+		lockout,
+		usecases.StaffSSOConfigured(identityProviders),
+	)
+	passwordUseCase := usecases.NewPasswordUseCase(
+		repo.User,
+		repo.UnitOfWork,
+		tokenManager,
+		passwordPolicy,
+		mailer,
+		cfg.Password.ResetTokenTTL,
+		cfg.Password.ResetURL,
+	)
+	oidcUseCase := usecases.NewOIDCUseCase(
+		repo.User,
+		repo.Identity,
+		repo.MFA,
+		repo.UnitOfWork,
+		tokenManager,
+		identityProviders,
+		cfg.OIDC.StateTTL,
+	)
+	mfaUseCase := usecases.NewMFAUseCase(
+		repo.User,
+		repo.MFA,
+		repo.UnitOfWork,
+		tokenManager,
+		lockout,
+		cfg.App.Name,
+	)
+	adminUseCase := usecases.NewAdminUseCase(
+		repo.User,
+		repo.Playlist,
+		repo.History,
+		repo.Audit,
+		repo.UnitOfWork,
+		tokenManager,
+	)
+	trashUseCase := usecases.NewTrashUseCase(
+		repo.Trash,
+		repo.Album,
+		repo.User,
+		repo.UnitOfWork,
+		cfg.Trash.Retention,
+	)
+	bootstrapAdmin(ctx, userUseCase)
+	go purgeExpiredSessions(ctx, userUseCase, cfg.Auth.SessionPurgeInterval, cfg.Auth.SessionRetention)
+	go purgeAuditLog(ctx, adminUseCase, cfg.Audit.PurgeInterval, cfg.Audit.Retention)
+	go purgeTrash(ctx, trashUseCase, cfg.Trash.PurgeInterval, cfg.Trash.Retention)
 	trackUseCase := usecases.NewTrackUseCase(
 		repo.Track,
 		repo.History,
 		repo.Album,
+		repo.Translation,
+		repo.Lyrics,
+		repo.Fingerprint,
+		repo.UnitOfWork,
 		cfg.Storage.MaxFileSizeMB,
 		cfg.Storage.AllowedTypes,
+		newAnalysisConfig(logger, cfg.Audio, cfg.Loudness, cfg.Fingerprint),
 	)
 	albumUseCase := usecases.NewAlbumUseCase(
 		repo.Album,
 		repo.Track,
+		repo.Follow,
+		repo.Translation,
+		repo.UnitOfWork,
+		bus,
 	)
 	genreUseCase := usecases.NewGenreUseCase(
 		repo.Genre,
 		repo.Track,
+		repo.Translation,
+		repo.UnitOfWork,
 	)
 	playlistUseCase := usecases.NewPlaylistUseCase(
 		repo.Playlist,
 		repo.Track,
 		repo.User,
+		repo.Follow,
+		repo.Translation,
+		repo.UnitOfWork,
+		bus,
 	)
 	historyUseCase := usecases.NewHistoryUseCase(
 		repo.History,
 		repo.Track,
+		repo.UnitOfWork,
 	)
 
+	playbackUseCase := usecases.NewPlaybackUseCase(
+		repo.Playback,
+		repo.Track,
+		repo.Session,
+		repo.UnitOfWork,
+		bus,
+	)
+	followUseCase := usecases.NewFollowUseCase(
+		repo.Follow,
+		repo.Playlist,
+	)
+	translationUseCase := usecases.NewTranslationUseCase(
+		repo.Translation,
+		repo.Track,
+		repo.Album,
+		repo.Genre,
+		repo.UnitOfWork,
+	)
+	coverUseCase := usecases.NewCoverUseCase(
+		repo.Cover,
+		repo.Track,
+		repo.Album,
+		repo.Playlist,
+		repo.UnitOfWork,
+		newCoverConfig(logger, cfg.Covers),
+	)
+	lyricsUseCase := usecases.NewLyricsUseCase(
+		repo.Lyrics,
+		repo.Track,
+		repo.Translation,
+		repo.UnitOfWork,
+	)
+	duplicateUseCase := usecases.NewDuplicateUseCase(
+		repo.Fingerprint,
+		repo.Track,
+		repo.UnitOfWork,
+	)
+	maxCoverSizeMB := cfg.Covers.MaxFileSizeMB
+	if maxCoverSizeMB <= 0 {
+		maxCoverSizeMB = defaultCoverFileSizeMB
+	}
+
+	dispatcher := outbox.NewDispatcher(repo.Outbox, outbox.Config{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+		BaseBackoff:  cfg.Outbox.BaseBackoff,
+		MaxBackoff:   cfg.Outbox.MaxBackoff,
+		Retention:    cfg.Outbox.Retention,
+	})
+	usecases.RegisterDomainEventHandlers(dispatcher, repo.Track, repo.UnitOfWork)
+	go dispatcher.Run(ctx)
+
 	r := router.NewRouter(
 		userUseCase,
+		passwordUseCase,
+		oidcUseCase,
+		mfaUseCase,
+		adminUseCase,
+		trashUseCase,
 		trackUseCase,
 		albumUseCase,
 		genreUseCase,
 		playlistUseCase,
 		historyUseCase,
+		playbackUseCase,
+		followUseCase,
+		translationUseCase,
+		coverUseCase,
+		lyricsUseCase,
+		duplicateUseCase,
+		bus,
 		cfg.Storage.AllowedTypes,
 		cfg.Storage.MaxFileSizeMB,
+		maxCoverSizeMB,
+		cfg.HTTP.RequestTimeout,
+		cfg.HTTP.RouteTimeouts,
+		cfg.OIDC.PostLoginRedirect,
+		newRateLimiter(cfg.RateLimit, rateLimitStore),
+		cfg.RateLimit.RouteClasses,
+		logger,
 	)
 
 	port := ":" + cfg.App.Port
-	fmt.Printf("Сервер запущен на http://localhost%s\n", port)
+	logger.Info("server started", "addr", port)
 
 	if err := http.ListenAndServe(port, r.GetRouter()); err != nil {
-		log.Fatalf("Ошибка запуска сервера: %v", err)
+		fatal(logger, "server stopped", err)
+	}
+}
+
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// newSigner загружает ключи подписи access-токенов из JWT_SIGNING_KEYS
+// ("kid:base64,kid:base64"); новые токены подписываются ключом
+// JWT_ACTIVE_KEY_ID, остальные ключи нужны для проверки при ротации.
+// Без ключей используется временный: токены станут недействительны после
+// перезапуска и не будут приниматься другими инстансами
+func newSigner(logger *slog.Logger) (*tokens.Signer, error) {
+	value := os.Getenv("JWT_SIGNING_KEYS")
+	if value == "" {
+		logger.Warn("JWT_SIGNING_KEYS is not set, using an ephemeral signing key")
+		return tokens.NewSigner("ephemeral", map[string][]byte{"ephemeral": tokens.GenerateKey()})
+	}
+
+	keys, err := tokens.ParseKeys(value)
+	if err != nil {
+		return nil, err
+	}
+	return tokens.NewSigner(os.Getenv("JWT_ACTIVE_KEY_ID"), keys)
+}
+
+// newRateLimiter создает ограничитель частоты запросов; nil, если он выключен
+func newRateLimiter(cfg config.RateLimitConfig, store ratelimit.Store) *ratelimit.Limiter {
+	if !cfg.Enabled {
+		return nil
+	}
+	limits := make(map[string]ratelimit.Limit, len(cfg.Limits))
+	for class, rule := range cfg.Limits {
+		limits[class] = ratelimit.Limit{Rate: rule.Rate, Period: rule.Period, Burst: rule.Burst}
+	}
+	return ratelimit.NewLimiter(store, limits)
+}
+
+// defaultCoverFileSizeMB — предельный размер файла обложки, если он не задан
+const defaultCoverFileSizeMB = 10
+
+// newCoverConfig переводит настройки обложек в конфигурацию use case.
+// Форматы без кодировщика пропускаются с предупреждением
+func newCoverConfig(logger *slog.Logger, cfg config.CoversConfig) usecases.CoverConfig {
+	formats := make([]imaging.Format, 0, len(cfg.Formats))
+	for _, name := range cfg.Formats {
+		format := imaging.Format(name)
+		if !imaging.CanEncode(format) {
+			logger.Warn("no encoder for cover format, skipping", "format", name)
+			continue
+		}
+		formats = append(formats, format)
+	}
+	return usecases.CoverConfig{
+		MaxPixels: cfg.MaxMegapixels * 1_000_000,
+		Sizes:     cfg.Sizes,
+		Formats:   formats,
+	}
+}
+
+// Параметры анализа файлов треков, если они не заданы
+const (
+	defaultAudioSampleRate  = 48000
+	defaultAudioChannels    = 2
+	defaultAudioTimeout     = 2 * time.Minute
+	defaultFingerprintMatch = 0.75
+)
+
+// Реакции на загрузку дубликата (fingerprint.on_duplicate)
+const (
+	onDuplicateWarn   = "warn"
+	onDuplicateReject = "reject"
+)
+
+// newAnalysisConfig собирает декодеры для анализа файлов треков. Если
+// внешнего декодера нет в системе, анализируются только WAV
+func newAnalysisConfig(logger *slog.Logger, cfg config.AudioConfig, loudnessCfg config.LoudnessConfig, fingerprintCfg config.FingerprintConfig) usecases.AnalysisConfig {
+	if !loudnessCfg.Enabled && !fingerprintCfg.Enabled {
+		return usecases.AnalysisConfig{}
+	}
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = defaultAudioSampleRate
+	}
+	if cfg.Channels == 0 {
+		cfg.Channels = defaultAudioChannels
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultAudioTimeout
+	}
+	if fingerprintCfg.MatchThreshold <= 0 {
+		fingerprintCfg.MatchThreshold = defaultFingerprintMatch
+	}
+	switch fingerprintCfg.OnDuplicate {
+	case "", onDuplicateWarn, onDuplicateReject:
+	default:
+		logger.Warn("unknown duplicate upload policy, duplicates will be accepted with a warning", "on_duplicate", fingerprintCfg.OnDuplicate)
+	}
+
+	decoders := audio.Chain{audio.WAVDecoder{}}
+	if len(cfg.Decoder) > 0 {
+		if _, err := exec.LookPath(cfg.Decoder[0]); err != nil {
+			logger.Warn("audio decoder not found, only WAV will be analyzed", "decoder", cfg.Decoder[0], "error", err)
+		} else {
+			decoders = append(decoders, &audio.CommandDecoder{
+				Command:    cfg.Decoder,
+				SampleRate: cfg.SampleRate,
+				Channels:   cfg.Channels,
+			})
+		}
+	}
+	return usecases.AnalysisConfig{
+		Decoder:          decoders,
+		Timeout:          cfg.Timeout,
+		Loudness:         loudnessCfg.Enabled,
+		Fingerprint:      fingerprintCfg.Enabled,
+		MatchThreshold:   fingerprintCfg.MatchThreshold,
+		RejectDuplicates: fingerprintCfg.OnDuplicate == onDuplicateReject,
+	}
+}
+
+// newIdentityProviders создает клиентов OpenID Connect из конфигурации.
+// Секрет клиента читается из переменной окружения, указанной у провайдера
+func newIdentityProviders(logger *slog.Logger, configs []config.OIDCProviderConfig) []usecases.IdentityProvider {
+	providers := make([]usecases.IdentityProvider, 0, len(configs))
+	for _, provider := range configs {
+		if provider.Name == "" || provider.Issuer == "" || provider.ClientID == "" {
+			logger.Error("oidc provider requires name, issuer and client_id", "provider", provider.Name)
+			os.Exit(1)
+		}
+		var secret string
+		if provider.ClientSecretEnv != "" {
+			secret = os.Getenv(provider.ClientSecretEnv)
+		}
+		providers = append(providers, usecases.IdentityProvider{
+			Client: oidc.NewProvider(oidc.Config{
+				Name:         provider.Name,
+				Issuer:       provider.Issuer,
+				ClientID:     provider.ClientID,
+				ClientSecret: secret,
+				RedirectURL:  provider.RedirectURL,
+				Scopes:       provider.Scopes,
+			}),
+			Staff:      provider.Staff,
+			TrustEmail: provider.TrustEmail,
+		})
+	}
+	return providers
+}
+
+// purgeExpiredSessions периодически удаляет истекшие и отозванные сессии
+func purgeExpiredSessions(ctx context.Context, userUseCase interfaces.UserUseCase, interval, retention time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := userUseCase.PurgeExpiredSessions(ctx, time.Now().Add(-retention)); err != nil {
+			logging.FromContext(ctx).Error("purge expired sessions failed", "error", err)
+		} else if n > 0 {
+			logging.FromContext(ctx).Info("purged expired sessions", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeAuditLog периодически удаляет записи журнала старше retention.
+// При нулевом retention журнал хранится бессрочно
+func purgeAuditLog(ctx context.Context, adminUseCase interfaces.AdminUseCase, interval, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := adminUseCase.PurgeAuditLog(ctx, time.Now().Add(-retention)); err != nil {
+			logging.FromContext(ctx).Error("purge audit log failed", "error", err)
+		} else if n > 0 {
+			logging.FromContext(ctx).Info("purged audit log entries", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTrash периодически окончательно удаляет объекты, пролежавшие в
+// корзине дольше retention. При нулевом retention корзина не очищается
+func purgeTrash(ctx context.Context, trashUseCase interfaces.TrashUseCase, interval, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := trashUseCase.Purge(ctx, time.Now().Add(-retention)); err != nil {
+			logging.FromContext(ctx).Error("purge trash failed", "error", err)
+		} else if n > 0 {
+			logging.FromContext(ctx).Info("purged trash items", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// bootstrapAdmin при первом запуске создает администратора из ADMIN_LOGIN и
+// ADMIN_PASSWORD. Если администратор уже есть, переменные игнорируются
+func bootstrapAdmin(ctx context.Context, userUseCase interfaces.UserUseCase) {
+	login, password := os.Getenv("ADMIN_LOGIN"), os.Getenv("ADMIN_PASSWORD")
+	if login == "" || password == "" {
+		return
+	}
+
+	user, err := userUseCase.EnsureAdmin(authz.WithSystem(ctx), login, password)
+	if err != nil {
+		fatal(logging.FromContext(ctx), "bootstrap admin failed", err)
+	}
+	if user != nil {
+		logging.FromContext(ctx).Info("bootstrap admin created", "user_id", user.ID, "login", user.Login)
 	}
 }
@@ -0,0 +1,173 @@
+// Package audio декодирует звуковые файлы в сэмплы для анализа: измерения
+// громкости и акустических отпечатков
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedFormat — декодер не умеет читать формат файла
+var ErrUnsupportedFormat = errors.New("unsupported audio format")
+
+// Stream — декодированный звук
+type Stream interface {
+	SampleRate() int
+	Channels() int
+	// Read заполняет samples чередующимися сэмплами каналов в диапазоне
+	// [-1, 1] и возвращает их число. В конце потока возвращается io.EOF
+	Read(samples []float64) (int, error)
+	Close() error
+}
+
+// Decoder открывает звуковой файл для чтения сэмплов
+type Decoder interface {
+	Decode(ctx context.Context, path string) (Stream, error)
+}
+
+// Chain — декодеры, которые пробуются по порядку, пока один из них не
+// примет формат файла
+type Chain []Decoder
+
+func (c Chain) Decode(ctx context.Context, path string) (Stream, error) {
+	for _, decoder := range c {
+		stream, err := decoder.Decode(ctx, path)
+		if errors.Is(err, ErrUnsupportedFormat) {
+			continue
+		}
+		return stream, err
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// Sink получает декодированные сэмплы: чередующиеся сэмплы каналов в
+// диапазоне [-1, 1]
+type Sink interface {
+	Write(samples []float64)
+}
+
+// Process декодирует файл за один проход и передает сэмплы всем
+// обработчикам. Обработчики создает newSinks по частоте дискретизации и
+// числу каналов потока
+func Process(ctx context.Context, decoder Decoder, path string, newSinks func(sampleRate, channels int) ([]Sink, error)) error {
+	stream, err := decoder.Decode(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	sinks, err := newSinks(stream.SampleRate(), stream.Channels())
+	if err != nil {
+		stream.Close()
+		return err
+	}
+	samples := make([]float64, 4096*stream.Channels())
+	for {
+		n, err := stream.Read(samples)
+		for _, sink := range sinks {
+			sink.Write(samples[:n])
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			stream.Close()
+			return err
+		}
+	}
+	return stream.Close()
+}
+
+// CommandDecoder декодирует файлы внешней программой, например ffmpeg:
+// в стандартной библиотеке нет декодера MP3. Программа пишет в stdout
+// сэмплы float32 little-endian с частотой SampleRate и числом каналов
+// Channels. В аргументах "{input}" заменяется путем к файлу,
+// "{sample_rate}" и "{channels}" — значениями полей
+type CommandDecoder struct {
+	Command    []string
+	SampleRate int
+	Channels   int
+}
+
+func (d *CommandDecoder) Decode(ctx context.Context, path string) (Stream, error) {
+	if len(d.Command) == 0 {
+		return nil, ErrUnsupportedFormat
+	}
+	replacer := strings.NewReplacer(
+		"{input}", path,
+		"{sample_rate}", strconv.Itoa(d.SampleRate),
+		"{channels}", strconv.Itoa(d.Channels),
+	)
+	args := make([]string, len(d.Command)-1)
+	for i, arg := range d.Command[1:] {
+		args[i] = replacer.Replace(arg)
+	}
+
+	cmd := exec.CommandContext(ctx, d.Command[0], args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start decoder: %w", err)
+	}
+	return &commandStream{
+		cmd:        cmd,
+		stdout:     stdout,
+		reader:     bufio.NewReaderSize(stdout, 64<<10),
+		stderr:     &stderr,
+		sampleRate: d.SampleRate,
+		channels:   d.Channels,
+	}, nil
+}
+
+type commandStream struct {
+	cmd        *exec.Cmd
+	stdout     io.ReadCloser
+	reader     *bufio.Reader
+	stderr     *bytes.Buffer
+	sampleRate int
+	channels   int
+	buf        [4]byte
+}
+
+func (s *commandStream) SampleRate() int { return s.sampleRate }
+
+func (s *commandStream) Channels() int { return s.channels }
+
+func (s *commandStream) Read(samples []float64) (int, error) {
+	for i := range samples {
+		if _, err := io.ReadFull(s.reader, s.buf[:]); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				err = io.EOF
+			}
+			return i, err
+		}
+		samples[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(s.buf[:])))
+	}
+	return len(samples), nil
+}
+
+// Close дожидается завершения программы. Ненулевой код выхода — ошибка
+// декодирования
+func (s *commandStream) Close() error {
+	// Непрочитанный вывод сбрасывается, чтобы программа не зависла на записи
+	io.Copy(io.Discard, s.stdout)
+	if err := s.cmd.Wait(); err != nil {
+		if message := strings.TrimSpace(s.stderr.String()); message != "" {
+			return fmt.Errorf("decoder failed: %w: %s", err, message)
+		}
+		return fmt.Errorf("decoder failed: %w", err)
+	}
+	return nil
+}
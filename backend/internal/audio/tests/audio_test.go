@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"music-service/internal/audio"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wav записывает сэмплы в WAV с 16-битными целыми сэмплами
+func wav(sampleRate, channels int, samples []float64) []byte {
+	var data bytes.Buffer
+	for _, v := range samples {
+		binary.Write(&data, binary.LittleEndian, int16(math.Round(v*32767)))
+	}
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(4+8+16+8+4+8+data.Len()))
+	b.WriteString("WAVE")
+	b.WriteString("fmt ")
+	binary.Write(&b, binary.LittleEndian, uint32(16))
+	binary.Write(&b, binary.LittleEndian, uint16(1))
+	binary.Write(&b, binary.LittleEndian, uint16(channels))
+	binary.Write(&b, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&b, binary.LittleEndian, uint32(sampleRate*channels*2))
+	binary.Write(&b, binary.LittleEndian, uint16(channels*2))
+	binary.Write(&b, binary.LittleEndian, uint16(16))
+	// Посторонний блок перед данными пропускается
+	b.WriteString("LIST")
+	binary.Write(&b, binary.LittleEndian, uint32(3))
+	b.Write([]byte{'a', 'b', 'c', 0})
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(data.Len()))
+	b.Write(data.Bytes())
+	return b.Bytes()
+}
+
+// collector запоминает все переданные ему сэмплы
+type collector struct {
+	samples []float64
+}
+
+func (c *collector) Write(samples []float64) {
+	c.samples = append(c.samples, samples...)
+}
+
+func TestWAVStream(t *testing.T) {
+	t.Run("pcm16", func(t *testing.T) {
+		stream, err := audio.NewWAVStream(bytes.NewReader(wav(44100, 2, []float64{0.5, -0.5, 1, -1})))
+		require.NoError(t, err)
+		assert.Equal(t, 44100, stream.SampleRate())
+		assert.Equal(t, 2, stream.Channels())
+
+		samples := make([]float64, 8)
+		n, err := stream.Read(samples)
+		assert.Equal(t, 4, n)
+		assert.ErrorIs(t, err, io.EOF)
+		assert.InDeltaSlice(t, []float64{0.5, -0.5, 1, -1}, samples[:n], 0.001)
+	})
+
+	t.Run("not wav", func(t *testing.T) {
+		_, err := audio.NewWAVStream(bytes.NewReader([]byte("ID3\x04\x00\x00\x00\x00\x00\x00")))
+		assert.ErrorIs(t, err, audio.ErrUnsupportedFormat)
+	})
+}
+
+func TestProcess(t *testing.T) {
+	dir := t.TempDir()
+	samples := make([]float64, 48000*2)
+	for i := range samples {
+		samples[i] = 0.5 * math.Sin(float64(i/2)/10)
+	}
+
+	wavPath := filepath.Join(dir, "tone.wav")
+	require.NoError(t, os.WriteFile(wavPath, wav(48000, 2, samples), 0o644))
+
+	// Сырые сэмплы float32 — такие пишет внешний декодер
+	var raw bytes.Buffer
+	for _, v := range samples {
+		binary.Write(&raw, binary.LittleEndian, float32(v))
+	}
+	rawPath := filepath.Join(dir, "tone.f32")
+	require.NoError(t, os.WriteFile(rawPath, raw.Bytes(), 0o644))
+
+	command := &audio.CommandDecoder{Command: []string{"cat", "{input}"}, SampleRate: 48000, Channels: 2}
+	decoder := audio.Chain{audio.WAVDecoder{}, command}
+
+	// process декодирует файл в два обработчика и проверяет, что оба
+	// получили все сэмплы
+	process := func(t *testing.T, decoder audio.Decoder, path string) error {
+		first, second := &collector{}, &collector{}
+		err := audio.Process(context.Background(), decoder, path, func(sampleRate, channels int) ([]audio.Sink, error) {
+			assert.Equal(t, 48000, sampleRate)
+			assert.Equal(t, 2, channels)
+			return []audio.Sink{first, second}, nil
+		})
+		if err == nil {
+			assert.InDeltaSlice(t, samples, first.samples, 0.001)
+			assert.Equal(t, first.samples, second.samples)
+		}
+		return err
+	}
+
+	// WAV читается встроенным декодером
+	t.Run("wav", func(t *testing.T) {
+		assert.NoError(t, process(t, decoder, wavPath))
+	})
+
+	// Остальные форматы — внешней программой
+	t.Run("command", func(t *testing.T) {
+		assert.NoError(t, process(t, decoder, rawPath))
+	})
+
+	t.Run("command failed", func(t *testing.T) {
+		failing := &audio.CommandDecoder{Command: []string{"cat", filepath.Join(dir, "missing")}, SampleRate: 48000, Channels: 2}
+		assert.Error(t, process(t, failing, rawPath))
+	})
+
+	// Без внешнего декодера формат не поддерживается
+	t.Run("unsupported", func(t *testing.T) {
+		assert.ErrorIs(t, process(t, audio.Chain{audio.WAVDecoder{}}, rawPath), audio.ErrUnsupportedFormat)
+	})
+
+	// Ошибка создания обработчиков прерывает декодирование
+	t.Run("sink error", func(t *testing.T) {
+		errSink := errors.New("sink failed")
+		err := audio.Process(context.Background(), decoder, wavPath, func(int, int) ([]audio.Sink, error) {
+			return nil, errSink
+		})
+		assert.ErrorIs(t, err, errSink)
+	})
+}
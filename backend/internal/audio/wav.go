@@ -0,0 +1,177 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+const (
+	wavePCM        = 1
+	waveFloat      = 3
+	waveExtensible = 0xFFFE
+)
+
+// WAVDecoder читает файлы WAV с целыми сэмплами 8–32 бит и сэмплами с
+// плавающей точкой 32 и 64 бит
+type WAVDecoder struct{}
+
+func (WAVDecoder) Decode(_ context.Context, path string) (Stream, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := NewWAVStream(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	stream.closer = file
+	return stream, nil
+}
+
+// WAVStream — сэмплы из блока data файла WAV
+type WAVStream struct {
+	reader     *bufio.Reader
+	closer     io.Closer
+	format     uint16
+	sampleRate int
+	channels   int
+	// width — размер сэмпла в байтах
+	width int
+	// remaining — число непрочитанных байт блока data
+	remaining int64
+	buf       []byte
+}
+
+// NewWAVStream разбирает заголовок WAV и останавливается в начале сэмплов.
+// Если r — не WAV, возвращается ErrUnsupportedFormat
+func NewWAVStream(r io.Reader) (*WAVStream, error) {
+	reader := bufio.NewReaderSize(r, 64<<10)
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if string(header[:4]) != "RIFF" || string(header[8:]) != "WAVE" {
+		return nil, ErrUnsupportedFormat
+	}
+
+	s := &WAVStream{reader: reader}
+	hasFormat := false
+	chunk := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return nil, fmt.Errorf("invalid WAV: no data chunk")
+		}
+		id, size := string(chunk[:4]), int64(binary.LittleEndian.Uint32(chunk[4:]))
+
+		switch id {
+		case "fmt ":
+			if size < 16 || size > 1024 {
+				return nil, fmt.Errorf("invalid WAV: fmt chunk size %d", size)
+			}
+			data := make([]byte, size+size%2)
+			if _, err := io.ReadFull(reader, data); err != nil {
+				return nil, fmt.Errorf("invalid WAV: %w", err)
+			}
+			if err := s.parseFormat(data[:size]); err != nil {
+				return nil, err
+			}
+			hasFormat = true
+		case "data":
+			if !hasFormat {
+				return nil, fmt.Errorf("invalid WAV: data before fmt chunk")
+			}
+			s.remaining = size
+			return s, nil
+		default:
+			// Блоки выровнены по двум байтам
+			if _, err := reader.Discard(int(size + size%2)); err != nil {
+				return nil, fmt.Errorf("invalid WAV: %w", err)
+			}
+		}
+	}
+}
+
+func (s *WAVStream) parseFormat(data []byte) error {
+	s.format = binary.LittleEndian.Uint16(data[0:2])
+	s.channels = int(binary.LittleEndian.Uint16(data[2:4]))
+	s.sampleRate = int(binary.LittleEndian.Uint32(data[4:8]))
+	bits := int(binary.LittleEndian.Uint16(data[14:16]))
+	if s.format == waveExtensible {
+		// Формат сэмплов — первые два байта GUID подформата
+		if len(data) < 26 {
+			return fmt.Errorf("invalid WAV: short extensible format")
+		}
+		s.format = binary.LittleEndian.Uint16(data[24:26])
+	}
+
+	s.width = bits / 8
+	switch {
+	case s.format == wavePCM && bits%8 == 0 && bits >= 8 && bits <= 32:
+	case s.format == waveFloat && (bits == 32 || bits == 64):
+	default:
+		return fmt.Errorf("%w: WAV format %d, %d bits", ErrUnsupportedFormat, s.format, bits)
+	}
+	if s.channels < 1 {
+		return fmt.Errorf("invalid WAV: %d channels", s.channels)
+	}
+	s.buf = make([]byte, s.width)
+	return nil
+}
+
+func (s *WAVStream) SampleRate() int { return s.sampleRate }
+
+func (s *WAVStream) Channels() int { return s.channels }
+
+func (s *WAVStream) Read(samples []float64) (int, error) {
+	for i := range samples {
+		if s.remaining < int64(s.width) {
+			return i, io.EOF
+		}
+		if _, err := io.ReadFull(s.reader, s.buf); err != nil {
+			// Оборванный файл читается до места обрыва
+			if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+				return i, io.EOF
+			}
+			return i, err
+		}
+		s.remaining -= int64(s.width)
+		samples[i] = s.sample()
+	}
+	return len(samples), nil
+}
+
+// sample переводит сэмпл из буфера в диапазон [-1, 1]
+func (s *WAVStream) sample() float64 {
+	b := s.buf
+	if s.format == waveFloat {
+		if s.width == 8 {
+			return math.Float64frombits(binary.LittleEndian.Uint64(b))
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	}
+	// 8-битные сэмплы беззнаковые, остальные — со знаком
+	if s.width == 1 {
+		return (float64(b[0]) - 128) / 128
+	}
+	var v int64
+	for i := s.width - 1; i >= 0; i-- {
+		v = v<<8 | int64(b[i])
+	}
+	bits := uint(s.width * 8)
+	v = v << (64 - bits) >> (64 - bits)
+	return float64(v) / float64(int64(1)<<(bits-1))
+}
+
+func (s *WAVStream) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
@@ -0,0 +1,24 @@
+package audit
+
+import "context"
+
+// RequestInfo — сведения о запросе, в рамках которого выполнено действие
+type RequestInfo struct {
+	IP        string
+	RequestID string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo кладет сведения о запросе в контекст. Middleware RequestID
+// делает это для каждого HTTP-запроса
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext возвращает сведения о запросе; для фоновых задач и
+// CLI они пусты
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"music-service/internal/models"
+)
+
+// Snapshot — значения полей сущности, за изменением которых следит журнал.
+// Ключи — имена полей в журнале, значения сериализуются в JSON
+type Snapshot map[string]interface{}
+
+// Diff возвращает поля, значения которых различаются в before и after.
+// nil before означает создание сущности, nil after — удаление; в этих
+// случаях в изменения попадают все поля
+func Diff(before, after Snapshot) (map[string]models.AuditChange, error) {
+	changes := make(map[string]models.AuditChange)
+	for name, value := range before {
+		from, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		changes[name] = models.AuditChange{From: from}
+	}
+	for name, value := range after {
+		to, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		change, existed := changes[name]
+		if existed && bytes.Equal(change.From, to) {
+			delete(changes, name)
+			continue
+		}
+		change.To = to
+		changes[name] = change
+	}
+	return changes, nil
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/audit"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before audit.Snapshot
+		after  audit.Snapshot
+		want   map[string][2]string
+	}{
+		{
+			name:   "changed fields only",
+			before: audit.Snapshot{"title": "Old", "artist": "Band", "duration": 180},
+			after:  audit.Snapshot{"title": "New", "artist": "Band", "duration": 180},
+			want:   map[string][2]string{"title": {`"Old"`, `"New"`}},
+		},
+		{
+			name:  "created",
+			after: audit.Snapshot{"name": "Jazz"},
+			want:  map[string][2]string{"name": {"", `"Jazz"`}},
+		},
+		{
+			name:   "deleted",
+			before: audit.Snapshot{"name": "Jazz"},
+			want:   map[string][2]string{"name": {`"Jazz"`, ""}},
+		},
+		{
+			name:   "field added and removed",
+			before: audit.Snapshot{"old": 1},
+			after:  audit.Snapshot{"new": []string{"a"}},
+			want:   map[string][2]string{"old": {"1", ""}, "new": {"", `["a"]`}},
+		},
+		{
+			name:   "no changes",
+			before: audit.Snapshot{"genres": []string{"Jazz", "Rock"}},
+			after:  audit.Snapshot{"genres": []string{"Jazz", "Rock"}},
+			want:   map[string][2]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := audit.Diff(tt.before, tt.after)
+			require.NoError(t, err)
+
+			got := make(map[string][2]string, len(changes))
+			for name, change := range changes {
+				got[name] = [2]string{string(change.From), string(change.To)}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiff_UnsupportedValue(t *testing.T) {
+	_, err := audit.Diff(nil, audit.Snapshot{"callback": func() {}})
+	assert.Error(t, err)
+}
+
+func TestRequestInfo(t *testing.T) {
+	assert.Equal(t, audit.RequestInfo{}, audit.RequestInfoFromContext(context.Background()))
+
+	info := audit.RequestInfo{IP: "10.0.0.1", RequestID: "req-1"}
+	ctx := audit.WithRequestInfo(context.Background(), info)
+	assert.Equal(t, info, audit.RequestInfoFromContext(ctx))
+}
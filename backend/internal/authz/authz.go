@@ -0,0 +1,132 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Permission — право на действие. Роли пользователей (models.Permission)
+// отображаются на набор прав через матрицу roleGrants
+type Permission string
+
+const (
+	TrackUpload      Permission = "track:upload"
+	TrackEdit        Permission = "track:edit"
+	TrackDelete      Permission = "track:delete"
+	AlbumEdit        Permission = "album:edit"
+	GenreManage      Permission = "genre:manage"
+	UserManage       Permission = "user:manage"
+	PlaylistModerate Permission = "playlist:moderate"
+	AuditRead        Permission = "audit:read"
+	TrashManage      Permission = "trash:manage"
+	LyricsModerate   Permission = "lyrics:moderate"
+	DuplicateManage  Permission = "duplicate:manage"
+)
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("permission denied")
+)
+
+// roleGrants — матрица прав. Обычный пользователь управляет только своими
+// ресурсами, проверка владения выполняется в use case'ах
+var roleGrants = map[models.Permission][]Permission{
+	models.UserPermission: {},
+	models.ModeratorPermission: {
+		GenreManage,
+		PlaylistModerate,
+		LyricsModerate,
+	},
+	models.AdminPermission: {
+		TrackUpload,
+		TrackEdit,
+		TrackDelete,
+		AlbumEdit,
+		GenreManage,
+		UserManage,
+		PlaylistModerate,
+		AuditRead,
+		TrashManage,
+		LyricsModerate,
+		DuplicateManage,
+	},
+}
+
+// RoleHas проверяет, входит ли право в набор прав роли
+func RoleHas(role models.Permission, permission Permission) bool {
+	for _, granted := range roleGrants[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStaff сообщает, является ли роль ролью сотрудника — то есть дает ли
+// она хоть одно право сверх прав обычного пользователя
+func IsStaff(role models.Permission) bool {
+	return len(roleGrants[role]) > 0
+}
+
+// Principal — аутентифицированный пользователь текущего запроса
+type Principal struct {
+	UserID    uuid.UUID
+	Login     string
+	Role      models.Permission
+	SessionID uuid.UUID
+	DeviceID  uuid.UUID
+	// Locale — язык из настроек пользователя; пустой, если не задан
+	Locale string
+}
+
+func (p *Principal) Can(permission Permission) bool {
+	return p != nil && RoleHas(p.Role, permission)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// WithSystem помечает ctx как служебный (CLI, первичная настройка при
+// запуске): такие операции выполняются с правами администратора
+func WithSystem(ctx context.Context) context.Context {
+	return WithPrincipal(ctx, &Principal{Login: "system", Role: models.AdminPermission})
+}
+
+// Current возвращает пользователя запроса или ErrUnauthenticated
+func Current(ctx context.Context) (*Principal, error) {
+	principal, ok := FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return principal, nil
+}
+
+// Can проверяет право пользователя из ctx. Анонимный запрос прав не имеет
+func Can(ctx context.Context, permission Permission) bool {
+	principal, _ := FromContext(ctx)
+	return principal.Can(permission)
+}
+
+// Require возвращает ErrUnauthenticated или ErrForbidden, если у
+// пользователя из ctx нет указанного права
+func Require(ctx context.Context, permission Permission) error {
+	principal, err := Current(ctx)
+	if err != nil {
+		return err
+	}
+	if !principal.Can(permission) {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/authz"
+	"music-service/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var allPermissions = []authz.Permission{
+	authz.TrackUpload,
+	authz.TrackEdit,
+	authz.TrackDelete,
+	authz.AlbumEdit,
+	authz.GenreManage,
+	authz.UserManage,
+	authz.PlaylistModerate,
+	authz.AuditRead,
+	authz.TrashManage,
+	authz.LyricsModerate,
+	authz.DuplicateManage,
+}
+
+// Матрица прав целиком: изменение roleGrants открывает или закрывает
+// эндпоинты, поэтому любое изменение должно отражаться здесь
+func TestRoleGrants(t *testing.T) {
+	granted := map[models.Permission][]authz.Permission{
+		models.UserPermission: {},
+		models.ModeratorPermission: {
+			authz.GenreManage,
+			authz.PlaylistModerate,
+			authz.LyricsModerate,
+		},
+		models.AdminPermission: allPermissions,
+		// Неизвестная роль не получает прав
+		models.Permission("superuser"): {},
+		models.Permission(""):          {},
+	}
+
+	for role, permissions := range granted {
+		for _, permission := range allPermissions {
+			want := false
+			for _, p := range permissions {
+				if p == permission {
+					want = true
+				}
+			}
+			assert.Equal(t, want, authz.RoleHas(role, permission), "%q %s", role, permission)
+		}
+	}
+}
+
+func TestIsStaff(t *testing.T) {
+	assert.False(t, authz.IsStaff(models.UserPermission))
+	assert.True(t, authz.IsStaff(models.ModeratorPermission))
+	assert.True(t, authz.IsStaff(models.AdminPermission))
+	assert.False(t, authz.IsStaff(models.Permission("superuser")))
+}
+
+func withRole(role models.Permission) context.Context {
+	return authz.WithPrincipal(context.Background(), &authz.Principal{UserID: uuid.New(), Login: "someone", Role: role})
+}
+
+func TestRequire(t *testing.T) {
+	cases := []struct {
+		name       string
+		ctx        context.Context
+		permission authz.Permission
+		err        error
+	}{
+		{"anonymous", context.Background(), authz.TrackUpload, authz.ErrUnauthenticated},
+		{"nil principal", authz.WithPrincipal(context.Background(), nil), authz.TrackUpload, authz.ErrUnauthenticated},
+		{"user without grant", withRole(models.UserPermission), authz.TrackUpload, authz.ErrForbidden},
+		{"moderator without grant", withRole(models.ModeratorPermission), authz.UserManage, authz.ErrForbidden},
+		{"moderator with grant", withRole(models.ModeratorPermission), authz.LyricsModerate, nil},
+		{"admin", withRole(models.AdminPermission), authz.UserManage, nil},
+		{"system", authz.WithSystem(context.Background()), authz.TrashManage, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := authz.Require(tc.ctx, tc.permission)
+			if tc.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.err)
+			}
+			assert.Equal(t, tc.err == nil, authz.Can(tc.ctx, tc.permission))
+		})
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	_, err := authz.Current(context.Background())
+	assert.ErrorIs(t, err, authz.ErrUnauthenticated)
+
+	principal := &authz.Principal{UserID: uuid.New(), Role: models.UserPermission}
+	current, err := authz.Current(authz.WithPrincipal(context.Background(), principal))
+	require.NoError(t, err)
+	assert.Same(t, principal, current)
+
+	var nobody *authz.Principal
+	assert.False(t, nobody.Can(authz.TrackEdit))
+}
@@ -2,13 +2,88 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	App     AppConfig     `yaml:"app"`
-	Storage StorageConfig `yaml:"storage"`
+	App         AppConfig         `yaml:"app"`
+	Storage     StorageConfig     `yaml:"storage"`
+	Outbox      OutboxConfig      `yaml:"outbox"`
+	HTTP        HTTPConfig        `yaml:"http"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Password    PasswordConfig    `yaml:"password"`
+	Mail        MailConfig        `yaml:"mail"`
+	OIDC        OIDCConfig        `yaml:"oidc"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+	Log         LogConfig         `yaml:"log"`
+	Audit       AuditConfig       `yaml:"audit"`
+	Trash       TrashConfig       `yaml:"trash"`
+	Covers      CoversConfig      `yaml:"covers"`
+	Audio       AudioConfig       `yaml:"audio"`
+	Loudness    LoudnessConfig    `yaml:"loudness"`
+	Fingerprint FingerprintConfig `yaml:"fingerprint"`
+}
+
+// CoversConfig — обложки: предельный размер файла и изображения, стороны
+// квадратных вариантов в пикселях и форматы, в которых они отдаются (в
+// порядке предпочтения). Формат без зарегистрированного кодировщика
+// пропускается; JPEG доступен всегда
+type CoversConfig struct {
+	MaxFileSizeMB int      `yaml:"max_file_size_mb"`
+	MaxMegapixels int      `yaml:"max_megapixels"`
+	Sizes         []int    `yaml:"sizes"`
+	Formats       []string `yaml:"formats"`
+}
+
+// AudioConfig — декодирование загруженных треков для анализа. WAV читается
+// встроенным декодером, остальные форматы — программой Decoder (ffmpeg),
+// которая пишет в stdout сэмплы float32 little-endian; в ее аргументах
+// "{input}" заменяется путем к файлу, "{sample_rate}" и "{channels}" —
+// значениями SampleRate и Channels. Без Decoder MP3 не анализируется.
+// Timeout ограничивает анализ одного файла
+type AudioConfig struct {
+	Decoder    []string      `yaml:"decoder"`
+	SampleRate int           `yaml:"sample_rate"`
+	Channels   int           `yaml:"channels"`
+	Timeout    time.Duration `yaml:"timeout"`
+}
+
+// LoudnessConfig — измерение громкости загруженных треков
+type LoudnessConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// FingerprintConfig — акустические отпечатки для поиска дубликатов. Треки
+// со сходством отпечатков от MatchThreshold считаются дубликатами.
+// OnDuplicate — реакция на загрузку дубликата: warn (трек загружается с
+// предупреждением) или reject (загрузка отклоняется)
+type FingerprintConfig struct {
+	Enabled        bool    `yaml:"enabled"`
+	MatchThreshold float64 `yaml:"match_threshold"`
+	OnDuplicate    string  `yaml:"on_duplicate"`
+}
+
+// TrashConfig — срок хранения удаленных объектов. Объекты, удаленные раньше
+// Retention, удаляются окончательно раз в PurgeInterval; при нулевом
+// Retention корзина не очищается
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+// AuditConfig — срок хранения журнала действий. Записи старше Retention
+// удаляются раз в PurgeInterval; при нулевом Retention журнал не очищается
+type AuditConfig struct {
+	Retention     time.Duration `yaml:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+// LogConfig — уровень (debug, info, warn, error) и формат (json, text) логов
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
 }
 
 type AppConfig struct {
@@ -22,6 +97,108 @@ type StorageConfig struct {
 	AllowedTypes  []string `yaml:"allowed_types"`
 }
 
+// OutboxConfig — параметры доставки доменных событий. Незаданные значения
+// заменяются значениями по умолчанию
+type OutboxConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval"`
+	BatchSize    int           `yaml:"batch_size"`
+	MaxAttempts  int           `yaml:"max_attempts"`
+	BaseBackoff  time.Duration `yaml:"base_backoff"`
+	MaxBackoff   time.Duration `yaml:"max_backoff"`
+	Retention    time.Duration `yaml:"retention"`
+}
+
+// HTTPConfig — таймауты обработки запросов. RouteTimeouts переопределяет
+// RequestTimeout для отдельных маршрутов, ключ — "<METHOD> <шаблон пути>"
+type HTTPConfig struct {
+	RequestTimeout time.Duration            `yaml:"request_timeout"`
+	RouteTimeouts  map[string]time.Duration `yaml:"route_timeouts"`
+}
+
+// AuthConfig — время жизни токенов. RevocationSyncInterval задает, как часто
+// список отозванных сессий подтягивается из БД (отзывы с других инстансов).
+// Истекшие и отозванные сессии удаляются раз в SessionPurgeInterval, спустя
+// SessionRetention после окончания
+type AuthConfig struct {
+	AccessTokenTTL         time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL        time.Duration `yaml:"refresh_token_ttl"`
+	RevocationSyncInterval time.Duration `yaml:"revocation_sync_interval"`
+	SessionPurgeInterval   time.Duration `yaml:"session_purge_interval"`
+	SessionRetention       time.Duration `yaml:"session_retention"`
+}
+
+// PasswordConfig — политика паролей и параметры сброса. BreachedList — путь
+// к локальному списку скомпрометированных паролей, ResetURL — адрес страницы
+// сброса, к которому в письме дописывается токен
+type PasswordConfig struct {
+	MinLength     int           `yaml:"min_length"`
+	MaxLength     int           `yaml:"max_length"`
+	RequireLetter bool          `yaml:"require_letter"`
+	RequireDigit  bool          `yaml:"require_digit"`
+	BreachedList  string        `yaml:"breached_list"`
+	ResetTokenTTL time.Duration `yaml:"reset_token_ttl"`
+	ResetURL      string        `yaml:"reset_url"`
+}
+
+// MailConfig — отправка писем. Driver: "log" (письма в лог) или "file"
+// (письма в каталог Dir)
+type MailConfig struct {
+	Driver string `yaml:"driver"`
+	From   string `yaml:"from"`
+	Dir    string `yaml:"dir"`
+}
+
+// OIDCConfig — вход через провайдеров OpenID Connect. PostLoginRedirect —
+// адрес фронтенда, куда браузер возвращается после входа; если пуст,
+// обратный вызов отвечает JSON с токенами
+type OIDCConfig struct {
+	StateTTL          time.Duration        `yaml:"state_ttl"`
+	PostLoginRedirect string               `yaml:"post_login_redirect"`
+	Providers         []OIDCProviderConfig `yaml:"providers"`
+}
+
+// OIDCProviderConfig — настройки провайдера. Секрет клиента не хранится в
+// конфигурации: ClientSecretEnv — имя переменной окружения с ним. Staff
+// отмечает корпоративный SSO: если такой провайдер настроен, сотрудники
+// входят только через него
+type OIDCProviderConfig struct {
+	Name            string   `yaml:"name"`
+	Issuer          string   `yaml:"issuer"`
+	ClientID        string   `yaml:"client_id"`
+	ClientSecretEnv string   `yaml:"client_secret_env"`
+	RedirectURL     string   `yaml:"redirect_url"`
+	Scopes          []string `yaml:"scopes"`
+	Staff           bool     `yaml:"staff"`
+	TrustEmail      bool     `yaml:"trust_email"`
+}
+
+// RateLimitConfig — ограничение частоты запросов. Limits задает ведро
+// токенов для каждого класса маршрутов; классы "read" и "write" действуют по
+// умолчанию для GET и остальных методов, другие классы назначаются в
+// RouteClasses ключом "<METHOD> <шаблон маршрута>"
+type RateLimitConfig struct {
+	Enabled      bool                     `yaml:"enabled"`
+	Limits       map[string]RateLimitRule `yaml:"limits"`
+	RouteClasses map[string]string        `yaml:"route_classes"`
+	Lockout      LockoutConfig            `yaml:"lockout"`
+}
+
+// RateLimitRule — Rate запросов за Period с запасом Burst
+type RateLimitRule struct {
+	Rate   int           `yaml:"rate"`
+	Period time.Duration `yaml:"period"`
+	Burst  int           `yaml:"burst"`
+}
+
+// LockoutConfig — прогрессивная блокировка после неудачных попыток входа
+// и ввода второго фактора
+type LockoutConfig struct {
+	Threshold     int           `yaml:"threshold"`
+	BaseDelay     time.Duration `yaml:"base_delay"`
+	MaxDelay      time.Duration `yaml:"max_delay"`
+	FailureWindow time.Duration `yaml:"failure_window"`
+}
+
 func NewConfig(path string) (*Config, error) {
 	cfg := &Config{}
 
@@ -0,0 +1,104 @@
+// Package dto описывает тела и параметры запросов HTTP API. Правила
+// проверки полей задаются тегами validate (см. validation.Struct), имена
+// параметров query string и форм — тегами form
+package dto
+
+import (
+	"fmt"
+	"music-service/internal/validation"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var timePtrType = reflect.TypeOf(&time.Time{})
+
+// Bind заполняет поля dst с тегом form значениями из values. Поддерживаются
+// string, int, bool, *time.Time (RFC 3339) и встроенные структуры.
+// Значения, которые не удалось разобрать, возвращаются как *validation.Error.
+// Отсутствующий параметр оставляет поле как есть, поэтому значения по
+// умолчанию задаются до вызова
+func Bind(values url.Values, dst interface{}) error {
+	errs := &validation.Error{}
+	bindStruct(values, reflect.ValueOf(dst).Elem(), errs)
+	return errs.Err()
+}
+
+func bindStruct(values url.Values, v reflect.Value, errs *validation.Error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			bindStruct(values, v.Field(i), errs)
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("form"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		raw := strings.TrimSpace(values.Get(name))
+		if raw == "" {
+			continue
+		}
+
+		field := v.Field(i)
+		switch {
+		case field.Kind() == reflect.String:
+			field.SetString(raw)
+		case field.Kind() == reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				errs.Add(name, validation.CodeInvalidNumber)
+				continue
+			}
+			field.SetInt(int64(n))
+		case field.Kind() == reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				errs.Add(name, validation.CodeInvalidType)
+				continue
+			}
+			field.SetBool(b)
+		case field.Type() == timePtrType:
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				errs.Add(name, validation.CodeInvalidDateTime)
+				continue
+			}
+			field.Set(reflect.ValueOf(&parsed))
+		default:
+			panic(fmt.Sprintf("dto: %s.%s: unsupported form field type %s", t.Name(), f.Name, field.Type()))
+		}
+	}
+}
+
+// UUID разбирает строку, уже проверенную правилом uuid. Пустая строка —
+// uuid.Nil
+func UUID(s string) uuid.UUID {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+// UUIDs разбирает срез строк, уже проверенных правилом uuid
+func UUIDs(values []string) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		ids = append(ids, UUID(value))
+	}
+	return ids
+}
+
+// PageQuery — параметры постраничного вывода. Размер страницы по умолчанию
+// и его ограничение применяет use case
+type PageQuery struct {
+	Limit  int `form:"limit" validate:"min=0"`
+	Offset int `form:"offset" validate:"min=0"`
+}
@@ -0,0 +1,223 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"music-service/internal/models"
+	"music-service/internal/validation"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// AlbumRequest — создание альбома
+type AlbumRequest struct {
+	Title       string    `json:"title" validate:"required,min=2,max=100"`
+	Artist      string    `json:"artist" validate:"max=100"`
+	ReleaseDate time.Time `json:"release_date"`
+	CoverURL    string    `json:"cover_url" validate:"max=255,url"`
+}
+
+// UpdateAlbumRequest — изменение альбома; пустые поля не меняются
+type UpdateAlbumRequest struct {
+	Title       string    `json:"title" validate:"min=2,max=100"`
+	Artist      string    `json:"artist" validate:"max=100"`
+	ReleaseDate time.Time `json:"release_date"`
+	CoverURL    string    `json:"cover_url" validate:"max=255,url"`
+}
+
+// TrackRefRequest — ссылка на трек в теле запроса: добавление в альбом,
+// плейлист или очередь
+type TrackRefRequest struct {
+	TrackID string `json:"track_id" validate:"required,uuid"`
+}
+
+// GenreRequest — создание жанра
+type GenreRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=50"`
+}
+
+// AssignGenreRequest — назначение жанра треку
+type AssignGenreRequest struct {
+	GenreID string `json:"genre_id" validate:"required,uuid"`
+}
+
+// SearchTracksQuery — поисковый запрос по трекам
+type SearchTracksQuery struct {
+	Query string `form:"q" validate:"required,min=3"`
+}
+
+// UploadTrackForm — поля multipart-формы загрузки трека; файл передается
+// в поле file. allow_duplicate загружает трек, даже если он похож на
+// треки каталога и загрузка дубликатов запрещена
+type UploadTrackForm struct {
+	Title          string `form:"title" validate:"required,max=100"`
+	ArtistName     string `form:"artist_name" validate:"required,max=100"`
+	AlbumID        string `form:"album_id" validate:"uuid"`
+	Duration       int    `form:"duration" validate:"min=0"`
+	CoverURL       string `form:"cover_url" validate:"max=255,url"`
+	AllowDuplicate bool   `form:"allow_duplicate"`
+}
+
+// TrackPatchRequest — изменение трека в формате JSON Merge Patch
+// (RFC 7396): отсутствующее поле не меняется, null очищает необязательное
+// поле. artist_name заменяет основного исполнителя, artists — весь список
+type TrackPatchRequest struct {
+	Title       *string  `json:"title" validate:"max=100"`
+	ArtistName  *string  `json:"artist_name" validate:"max=100"`
+	Artists     []string `json:"artists" validate:"max=10"`
+	AlbumID     *string  `json:"album_id" validate:"uuid"`
+	TrackNumber *int     `json:"track_number" validate:"min=1,max=999"`
+	DiscNumber  *int     `json:"disc_number" validate:"min=1,max=99"`
+	Duration    *int     `json:"duration" validate:"min=0"`
+	CoverURL    *string  `json:"cover_url" validate:"max=255,url"`
+	Genres      []string `json:"genres" validate:"max=5,uuid"`
+	Explicit    *bool    `json:"explicit"`
+	ISRC        *string  `json:"isrc" validate:"max=15"`
+	LyricsURL   *string  `json:"lyrics_url" validate:"max=255,url"`
+	ReleaseDate *string  `json:"release_date" validate:"date"`
+
+	// nulls — поля, переданные как null
+	nulls map[string]bool
+}
+
+// UnmarshalJSON запоминает, какие поля переданы как null: для указателей
+// null и отсутствие поля неразличимы
+func (req *TrackPatchRequest) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	type plain TrackPatchRequest
+	if err := json.Unmarshal(data, (*plain)(req)); err != nil {
+		return err
+	}
+
+	req.nulls = make(map[string]bool)
+	for name, value := range fields {
+		if string(value) == "null" {
+			req.nulls[name] = true
+		}
+	}
+	return nil
+}
+
+// Check запрещает очищать обязательные поля и менять исполнителей двумя
+// способами сразу
+func (req *TrackPatchRequest) Check(errs *validation.Error) {
+	for _, field := range []string{"title", "artist_name", "artists", "duration", "explicit"} {
+		if req.nulls[field] {
+			errs.Add(field, validation.CodeRequired)
+		}
+	}
+	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
+		errs.Add("title", validation.CodeRequired)
+	}
+	if req.ArtistName != nil && strings.TrimSpace(*req.ArtistName) == "" {
+		errs.Add("artist_name", validation.CodeRequired)
+	}
+	if req.Artists != nil && len(req.Artists) == 0 {
+		errs.Add("artists", validation.CodeRequired)
+	}
+	for i, artist := range req.Artists {
+		if strings.TrimSpace(artist) == "" {
+			errs.Add(fmt.Sprintf("artists[%d]", i), validation.CodeRequired)
+		} else if utf8.RuneCountInString(strings.TrimSpace(artist)) > 100 {
+			errs.Add(fmt.Sprintf("artists[%d]", i), validation.CodeTooLong, 100)
+		}
+	}
+	if req.ArtistName != nil && req.Artists != nil {
+		errs.Add("artists", validation.CodeConflicting)
+	}
+}
+
+// Update переводит проверенный запрос в изменение трека
+func (req *TrackPatchRequest) Update() models.TrackMetadataUpdate {
+	update := models.TrackMetadataUpdate{
+		Title:       req.Title,
+		ArtistName:  req.ArtistName,
+		Artists:     req.Artists,
+		TrackNumber: req.TrackNumber,
+		DiscNumber:  req.DiscNumber,
+		Duration:    req.Duration,
+		CoverURL:    req.CoverURL,
+		Explicit:    req.Explicit,
+		ISRC:        req.ISRC,
+		LyricsURL:   req.LyricsURL,
+	}
+	if req.AlbumID != nil || req.nulls["album_id"] {
+		albumID := uuid.Nil
+		if req.AlbumID != nil {
+			albumID = UUID(*req.AlbumID)
+		}
+		update.AlbumID = &albumID
+	}
+	if req.Genres != nil || req.nulls["genres"] {
+		update.GenreIDs = UUIDs(req.Genres)
+	}
+	if req.ReleaseDate != nil || req.nulls["release_date"] {
+		var date time.Time
+		if req.ReleaseDate != nil {
+			date, _ = time.Parse(time.DateOnly, strings.TrimSpace(*req.ReleaseDate))
+		}
+		update.ReleaseDate = &date
+	}
+
+	// null очищает поле: в изменении это указатель на нулевое значение
+	var zero int
+	var empty string
+	for field, target := range map[string]**int{"track_number": &update.TrackNumber, "disc_number": &update.DiscNumber} {
+		if req.nulls[field] {
+			*target = &zero
+		}
+	}
+	for field, target := range map[string]**string{"cover_url": &update.CoverURL, "isrc": &update.ISRC, "lyrics_url": &update.LyricsURL} {
+		if req.nulls[field] {
+			*target = &empty
+		}
+	}
+	return update
+}
+
+// CoverQuery — размер обложки: сторона в пикселях или "original". Допустимые
+// размеры задаются конфигурацией и проверяются в use case
+type CoverQuery struct {
+	Size string `form:"size" validate:"max=16"`
+}
+
+// SetLyricsRequest — текст песни: обычный или в формате LRC. Language —
+// тег BCP 47 языка текста
+type SetLyricsRequest struct {
+	Text     string `json:"text" validate:"required"`
+	Language string `json:"language" validate:"locale"`
+}
+
+// ModerateLyricsRequest — решение модератора по тексту песни
+type ModerateLyricsRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending approved rejected"`
+}
+
+// LyricsQuery — очередь модерации текстов; по умолчанию — ожидающие
+type LyricsQuery struct {
+	Status string `form:"status" validate:"oneof=pending approved rejected"`
+	PageQuery
+}
+
+// SearchLyricsQuery — полнотекстовый поиск по текстам песен
+type SearchLyricsQuery struct {
+	Query string `form:"q" validate:"required,min=3"`
+	PageQuery
+}
+
+// SetTranslationRequest — перевод полей объекта каталога на один язык
+type SetTranslationRequest struct {
+	Fields map[string]string `json:"fields" validate:"required"`
+}
+
+// MergeTracksRequest — объединение дубликатов source_ids с треком target_id
+type MergeTracksRequest struct {
+	TargetID  string   `json:"target_id" validate:"required,uuid"`
+	SourceIDs []string `json:"source_ids" validate:"required,max=20,uuid"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+// SetQueueRequest — новая очередь воспроизведения и позиция, с которой
+// начать
+type SetQueueRequest struct {
+	TrackIDs   []string `json:"track_ids" validate:"max=1000,uuid"`
+	StartIndex int      `json:"start_index" validate:"min=0"`
+}
+
+// AddToQueueRequest — добавление трека в конец очереди или следующим
+type AddToQueueRequest struct {
+	TrackID  string `json:"track_id" validate:"required,uuid"`
+	PlayNext bool   `json:"play_next"`
+}
+
+// PlayRequest — запуск воспроизведения; без index продолжается текущий трек
+type PlayRequest struct {
+	Index *int `json:"index" validate:"min=0"`
+}
+
+// SeekRequest — перемотка текущего трека
+type SeekRequest struct {
+	PositionMs int `json:"position_ms" validate:"min=0"`
+}
+
+// ShuffleRequest — включение и выключение перемешивания
+type ShuffleRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// RepeatRequest — режим повтора
+type RepeatRequest struct {
+	Mode string `json:"mode" validate:"required,oneof=off all one"`
+}
+
+// TransferPlaybackRequest — перенос воспроизведения на другое устройство
+type TransferPlaybackRequest struct {
+	DeviceID string `json:"device_id" validate:"required,uuid"`
+	Play     bool   `json:"play"`
+}
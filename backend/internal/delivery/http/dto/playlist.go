@@ -0,0 +1,25 @@
+package dto
+
+// PlaylistRequest — создание плейлиста
+type PlaylistRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	CoverURL    string `json:"cover_url" validate:"max=255,url"`
+}
+
+// UpdatePlaylistRequest — изменение плейлиста; пустые поля не меняются
+type UpdatePlaylistRequest struct {
+	Name        string `json:"name" validate:"min=2,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// RecentPlaysQuery — окно недавних прослушиваний в часах, до 30 дней
+type RecentPlaysQuery struct {
+	Hours int `form:"hours" validate:"min=1,max=720"`
+}
+
+// TrashQuery — фильтр корзины по типу объекта
+type TrashQuery struct {
+	Type string `form:"type" validate:"oneof=track album playlist user"`
+	PageQuery
+}
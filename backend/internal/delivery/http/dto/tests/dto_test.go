@@ -0,0 +1,258 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/i18n"
+	"music-service/internal/models"
+	"music-service/internal/validation"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const trackID = "3f0e4c1a-6d1b-4c5e-9a8f-1b2c3d4e5f60"
+
+// codes возвращает нарушения в виде поле -> код
+func codes(t *testing.T, err error) map[string]string {
+	t.Helper()
+	got := map[string]string{}
+	if err == nil {
+		return got
+	}
+	var verr *validation.Error
+	require.True(t, errors.As(err, &verr), "ожидалась *validation.Error, получено %v", err)
+	for _, v := range verr.Violations {
+		got[v.Field] = v.Code
+	}
+	return got
+}
+
+func TestRequests(t *testing.T) {
+	until := time.Now().Add(time.Hour)
+	index := -1
+	cases := []struct {
+		name string
+		req  interface{}
+		want map[string]string
+	}{
+		{"регистрация", &dto.RegisterRequest{Login: "listener", Password: "secret", Email: "user@example.com"}, map[string]string{}},
+		{"регистрация без полей", &dto.RegisterRequest{},
+			map[string]string{"login": validation.CodeRequired, "password": validation.CodeRequired, "email": validation.CodeRequired}},
+		{"короткий логин кириллицей", &dto.RegisterRequest{Login: "Иван", Password: "secret", Email: "user@example.com"},
+			map[string]string{"login": validation.CodeTooShort}},
+		{"логин из шести букв кириллицей", &dto.RegisterRequest{Login: "Михаил", Password: "secret", Email: "user@example.com"}, map[string]string{}},
+		{"некорректная почта", &dto.RegisterRequest{Login: "listener", Password: "secret", Email: "user@"},
+			map[string]string{"email": validation.CodeInvalidEmail}},
+		{"вход с некорректным устройством", &dto.AuthRequest{Login: "listener", Password: "secret", DeviceID: "phone"},
+			map[string]string{"device_id": validation.CodeInvalidUUID}},
+		{"сброс языка", &dto.SetLocaleRequest{}, map[string]string{}},
+		{"неизвестный язык", &dto.SetLocaleRequest{Locale: "russian"}, map[string]string{"locale": validation.CodeInvalidLocale}},
+		{"неизвестная роль", &dto.UpdatePermissionsRequest{Permission: "root"}, map[string]string{"permission": validation.CodeInvalidChoice}},
+		{"забытый пароль", &dto.ForgotPasswordRequest{Email: "x"}, map[string]string{"email": validation.CodeInvalidEmail}},
+		{"сброс пароля без токена", &dto.ResetPasswordRequest{NewPassword: "secret"}, map[string]string{"token": validation.CodeRequired}},
+		{"второй фактор без кода", &dto.MFAChallengeRequest{MFAToken: "token"}, map[string]string{"code": validation.CodeRequired}},
+		{"роли второго фактора", &dto.RequiredRolesRequest{Roles: []models.Permission{models.AdminPermission, "guest"}},
+			map[string]string{"roles[1]": validation.CodeInvalidChoice}},
+		{"блокировка на срок", &dto.SuspendRequest{Reason: "spam", Duration: "72h"}, map[string]string{}},
+		{"блокировка с двумя сроками", &dto.SuspendRequest{Until: &until, Duration: "72h"},
+			map[string]string{"duration": validation.CodeConflicting}},
+		{"некорректный срок блокировки", &dto.SuspendRequest{Duration: "3 дня"}, map[string]string{"duration": validation.CodeInvalidDuration}},
+		{"длинная причина блокировки", &dto.SuspendRequest{Reason: strings.Repeat("я", 501)}, map[string]string{"reason": validation.CodeTooLong}},
+		{"альбом", &dto.AlbumRequest{Title: "Кино", Artist: "Группа крови", CoverURL: "https://example.com/cover.jpg"}, map[string]string{}},
+		{"альбом без названия", &dto.AlbumRequest{CoverURL: "обложка"},
+			map[string]string{"title": validation.CodeRequired, "cover_url": validation.CodeInvalidURL}},
+		{"название альбома в 100 символов кириллицей", &dto.AlbumRequest{Title: strings.Repeat("ж", 100)}, map[string]string{}},
+		{"название альбома длиннее 100 символов", &dto.AlbumRequest{Title: strings.Repeat("ж", 101)}, map[string]string{"title": validation.CodeTooLong}},
+		{"изменение альбома без полей", &dto.UpdateAlbumRequest{}, map[string]string{}},
+		{"изменение альбома с коротким названием", &dto.UpdateAlbumRequest{Title: "Я"}, map[string]string{"title": validation.CodeTooShort}},
+		{"ссылка на трек", &dto.TrackRefRequest{TrackID: trackID}, map[string]string{}},
+		{"ссылка на трек не UUID", &dto.TrackRefRequest{TrackID: "42"}, map[string]string{"track_id": validation.CodeInvalidUUID}},
+		{"жанр", &dto.GenreRequest{Name: "Рок"}, map[string]string{}},
+		{"назначение жанра", &dto.AssignGenreRequest{}, map[string]string{"genre_id": validation.CodeRequired}},
+		{"поиск из трех букв кириллицей", &dto.SearchTracksQuery{Query: "Цой"}, map[string]string{}},
+		{"короткий поиск", &dto.SearchTracksQuery{Query: "Цо"}, map[string]string{"q": validation.CodeTooShort}},
+		{"форма загрузки", &dto.UploadTrackForm{Title: "Кукушка", ArtistName: "Кино", AlbumID: "альбом", Duration: -1},
+			map[string]string{"album_id": validation.CodeInvalidUUID, "duration": validation.CodeTooSmall}},
+		{"перевод без полей", &dto.SetTranslationRequest{}, map[string]string{"fields": validation.CodeRequired}},
+		{"текст песни", &dto.SetLyricsRequest{Text: "[00:01.00]Раз", Language: "ru"}, map[string]string{}},
+		{"текст песни без текста", &dto.SetLyricsRequest{Language: "по-русски"},
+			map[string]string{"text": validation.CodeRequired, "language": validation.CodeInvalidLocale}},
+		{"модерация текста", &dto.ModerateLyricsRequest{Status: "hidden"}, map[string]string{"status": validation.CodeInvalidChoice}},
+		{"короткий поиск по текстам", &dto.SearchLyricsQuery{Query: "ой"}, map[string]string{"q": validation.CodeTooShort}},
+		{"плейлист", &dto.PlaylistRequest{Name: "Ёлка", Description: strings.Repeat("ы", 500)}, map[string]string{}},
+		{"длинное описание плейлиста", &dto.PlaylistRequest{Name: "Ёлка", Description: strings.Repeat("ы", 501)},
+			map[string]string{"description": validation.CodeTooLong}},
+		{"изменение плейлиста", &dto.UpdatePlaylistRequest{Name: "Я"}, map[string]string{"name": validation.CodeTooShort}},
+		{"очередь", &dto.SetQueueRequest{TrackIDs: []string{trackID, "x"}, StartIndex: -1},
+			map[string]string{"track_ids[1]": validation.CodeInvalidUUID, "start_index": validation.CodeTooSmall}},
+		{"слишком длинная очередь", &dto.SetQueueRequest{TrackIDs: make([]string, 1001)}, map[string]string{"track_ids": validation.CodeTooMany}},
+		{"воспроизведение без позиции", &dto.PlayRequest{}, map[string]string{}},
+		{"воспроизведение с отрицательной позицией", &dto.PlayRequest{Index: &index}, map[string]string{"index": validation.CodeTooSmall}},
+		{"перемотка назад", &dto.SeekRequest{PositionMs: -1}, map[string]string{"position_ms": validation.CodeTooSmall}},
+		{"режим повтора", &dto.RepeatRequest{Mode: "twice"}, map[string]string{"mode": validation.CodeInvalidChoice}},
+		{"перенос воспроизведения", &dto.TransferPlaybackRequest{}, map[string]string{"device_id": validation.CodeRequired}},
+		{"недавние прослушивания", &dto.RecentPlaysQuery{Hours: 0}, map[string]string{"hours": validation.CodeTooSmall}},
+		{"окно больше 30 дней", &dto.RecentPlaysQuery{Hours: 721}, map[string]string{"hours": validation.CodeTooLarge}},
+		{"корзина", &dto.TrashQuery{Type: "genre", PageQuery: dto.PageQuery{Offset: -1}},
+			map[string]string{"type": validation.CodeInvalidChoice, "offset": validation.CodeTooSmall}},
+		{"список пользователей", &dto.ListUsersQuery{Status: "banned"}, map[string]string{"status": validation.CodeInvalidChoice}},
+		{"журнал действий", &dto.AuditLogQuery{ActorID: "admin"}, map[string]string{"actor_id": validation.CodeInvalidUUID}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, codes(t, validation.Struct(tc.req)))
+		})
+	}
+}
+
+func TestViolationCodesLocalized(t *testing.T) {
+	all := []string{
+		validation.CodeRequired, validation.CodeTooShort, validation.CodeTooLong,
+		validation.CodeTooSmall, validation.CodeTooLarge, validation.CodeTooFew,
+		validation.CodeTooMany, validation.CodeInvalidUUID, validation.CodeInvalidURL,
+		validation.CodeInvalidEmail, validation.CodeInvalidChoice, validation.CodeInvalidLocale,
+		validation.CodeInvalidDuration, validation.CodeInvalidType, validation.CodeInvalidNumber,
+		validation.CodeInvalidDateTime, validation.CodeInvalidDate, validation.CodeConflicting, "validation_failed",
+	}
+	for _, locale := range []i18n.Locale{i18n.RU, i18n.EN} {
+		codes := i18n.Codes(locale)
+		for _, code := range all {
+			assert.Contains(t, codes, code, "%s: нет сообщения %s", locale, code)
+		}
+	}
+}
+
+func TestBind(t *testing.T) {
+	t.Run("значения и встроенная структура", func(t *testing.T) {
+		query := url.Values{
+			"actor_id": {trackID},
+			"from":     {"2026-01-02T03:04:05Z"},
+			"limit":    {" 20 "},
+			"offset":   {"40"},
+			"unknown":  {"x"},
+		}
+		var got dto.AuditLogQuery
+		require.NoError(t, dto.Bind(query, &got))
+
+		assert.Equal(t, trackID, got.ActorID)
+		require.NotNil(t, got.From)
+		assert.True(t, got.From.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)))
+		assert.Nil(t, got.To)
+		assert.Equal(t, 20, got.Limit)
+		assert.Equal(t, 40, got.Offset)
+	})
+
+	t.Run("отсутствующий параметр не меняет значение по умолчанию", func(t *testing.T) {
+		got := dto.RecentPlaysQuery{Hours: 24}
+		require.NoError(t, dto.Bind(url.Values{}, &got))
+		assert.Equal(t, 24, got.Hours)
+	})
+
+	t.Run("ошибки разбора собираются вместе", func(t *testing.T) {
+		query := url.Values{"from": {"вчера"}, "limit": {"десять"}, "offset": {"1.5"}}
+		var got dto.AuditLogQuery
+		assert.Equal(t, map[string]string{
+			"from":   validation.CodeInvalidDateTime,
+			"limit":  validation.CodeInvalidNumber,
+			"offset": validation.CodeInvalidNumber,
+		}, codes(t, dto.Bind(query, &got)))
+	})
+}
+
+func TestTrackPatchRequest(t *testing.T) {
+	decode := func(t *testing.T, body string) (*dto.TrackPatchRequest, error) {
+		t.Helper()
+		var req dto.TrackPatchRequest
+		require.NoError(t, json.Unmarshal([]byte(body), &req))
+		return &req, validation.Struct(&req)
+	}
+
+	t.Run("пустой патч ничего не меняет", func(t *testing.T) {
+		req, err := decode(t, `{}`)
+		require.NoError(t, err)
+		assert.Equal(t, models.TrackMetadataUpdate{}, req.Update())
+	})
+
+	t.Run("значения полей", func(t *testing.T) {
+		req, err := decode(t, `{"title":"Кукушка","artists":["Кино","Виктор Цой"],"album_id":"`+trackID+`",
+			"track_number":3,"genres":["`+trackID+`"],"explicit":false,"release_date":"1990-01-12"}`)
+		require.NoError(t, err)
+
+		update := req.Update()
+		assert.Equal(t, "Кукушка", *update.Title)
+		assert.Equal(t, []string{"Кино", "Виктор Цой"}, update.Artists)
+		assert.Equal(t, uuid.MustParse(trackID), *update.AlbumID)
+		assert.Equal(t, 3, *update.TrackNumber)
+		assert.Equal(t, []uuid.UUID{uuid.MustParse(trackID)}, update.GenreIDs)
+		assert.False(t, *update.Explicit)
+		assert.Equal(t, time.Date(1990, 1, 12, 0, 0, 0, 0, time.UTC), *update.ReleaseDate)
+		assert.Nil(t, update.Duration)
+	})
+
+	t.Run("null очищает необязательные поля", func(t *testing.T) {
+		req, err := decode(t, `{"album_id":null,"track_number":null,"isrc":null,"genres":null,"release_date":null}`)
+		require.NoError(t, err)
+
+		update := req.Update()
+		assert.Equal(t, uuid.Nil, *update.AlbumID)
+		assert.Equal(t, 0, *update.TrackNumber)
+		assert.Equal(t, "", *update.ISRC)
+		assert.NotNil(t, update.GenreIDs)
+		assert.Empty(t, update.GenreIDs)
+		assert.True(t, update.ReleaseDate.IsZero())
+		assert.Nil(t, update.DiscNumber)
+		assert.Nil(t, update.LyricsURL)
+	})
+
+	t.Run("обязательные поля нельзя очистить", func(t *testing.T) {
+		_, err := decode(t, `{"title":null,"artists":[],"duration":null,"explicit":null}`)
+		assert.Equal(t, map[string]string{
+			"title":    validation.CodeRequired,
+			"artists":  validation.CodeRequired,
+			"duration": validation.CodeRequired,
+			"explicit": validation.CodeRequired,
+		}, codes(t, err))
+	})
+
+	t.Run("некорректные значения", func(t *testing.T) {
+		_, err := decode(t, `{"title":" ","artists":["Кино",""],"track_number":0,"genres":["рок"],"release_date":"12.01.1990"}`)
+		assert.Equal(t, map[string]string{
+			"title":        validation.CodeRequired,
+			"artists[1]":   validation.CodeRequired,
+			"track_number": validation.CodeTooSmall,
+			"genres[0]":    validation.CodeInvalidUUID,
+			"release_date": validation.CodeInvalidDate,
+		}, codes(t, err))
+	})
+
+	t.Run("исполнитель и список исполнителей вместе", func(t *testing.T) {
+		_, err := decode(t, `{"artist_name":"Кино","artists":["Кино"]}`)
+		assert.Equal(t, map[string]string{"artists": validation.CodeConflicting}, codes(t, err))
+	})
+}
+
+func TestSuspendRequestEnd(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := now.Add(time.Hour)
+
+	assert.Nil(t, (&dto.SuspendRequest{}).End(now))
+	assert.Equal(t, &until, (&dto.SuspendRequest{Until: &until}).End(now))
+	assert.Equal(t, now.Add(72*time.Hour), *(&dto.SuspendRequest{Duration: "72h"}).End(now))
+}
+
+func TestAuthRequestDevice(t *testing.T) {
+	device := dto.AuthRequest{DeviceID: trackID, DeviceType: "mobile"}.Device("Mozilla/5.0")
+	assert.Equal(t, uuid.MustParse(trackID), device.ID)
+	assert.Equal(t, "Mozilla/5.0", device.Name)
+	assert.Equal(t, "mobile", device.Type)
+
+	// Без идентификатора сервер выдаст новый
+	assert.Equal(t, uuid.Nil, dto.AuthRequest{DeviceName: "Телефон"}.Device("curl").ID)
+}
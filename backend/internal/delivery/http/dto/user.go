@@ -0,0 +1,138 @@
+package dto
+
+import (
+	"music-service/internal/models"
+	"music-service/internal/validation"
+	"strings"
+	"time"
+)
+
+// RegisterRequest — регистрация по логину и паролю. Требования к паролю
+// проверяет парольная политика
+type RegisterRequest struct {
+	Login    string `json:"login" validate:"required,min=6,max=255"`
+	Password string `json:"password" validate:"required"`
+	Email    string `json:"email" validate:"required,max=255,email"`
+}
+
+// AuthRequest — вход по логину и паролю с описанием устройства клиента
+type AuthRequest struct {
+	Login      string `json:"login" validate:"required"`
+	Password   string `json:"password" validate:"required"`
+	DeviceID   string `json:"device_id" validate:"uuid"`
+	DeviceName string `json:"device_name"`
+	DeviceType string `json:"device_type"`
+}
+
+// Device собирает описание устройства клиента. Если клиент не передал
+// идентификатор, сервер выдаст новый, а имя возьмется из User-Agent
+func (req AuthRequest) Device(userAgent string) models.Device {
+	device := models.Device{
+		ID:   UUID(req.DeviceID),
+		Name: req.DeviceName,
+		Type: req.DeviceType,
+	}
+	if device.Name == "" {
+		device.Name = userAgent
+	}
+	return device
+}
+
+// RefreshRequest — обновление пары токенов. Токен можно не передавать в
+// теле, тогда он берется из куки
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// SetLocaleRequest — язык интерфейса; пустое значение сбрасывает выбор
+type SetLocaleRequest struct {
+	Locale string `json:"locale" validate:"locale"`
+}
+
+// UpdatePermissionsRequest — смена роли пользователя
+type UpdatePermissionsRequest struct {
+	Permission string `json:"permission" validate:"required,oneof=user moderator admin"`
+}
+
+// ChangePasswordRequest — смена пароля по текущему
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+// ForgotPasswordRequest — запрос ссылки для сброса пароля
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,max=255,email"`
+}
+
+// ResetPasswordRequest — новый пароль по токену из письма
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// MFAChallengeRequest — второй шаг входа: токен первого шага и код
+type MFAChallengeRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// MFATokenRequest — токен первого шага входа без кода: подключение второго
+// фактора при входе
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+// MFACodeRequest — код из приложения-аутентификатора или код восстановления
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// RequiredRolesRequest — роли, для которых второй фактор обязателен
+type RequiredRolesRequest struct {
+	Roles []models.Permission `json:"roles" validate:"oneof=user moderator admin"`
+}
+
+// SuspendRequest — блокировка до Until (RFC 3339) или на Duration ("72h");
+// без обоих полей блокировка бессрочная
+type SuspendRequest struct {
+	Reason   string     `json:"reason" validate:"max=500"`
+	Until    *time.Time `json:"until"`
+	Duration string     `json:"duration" validate:"duration"`
+}
+
+// Check запрещает указывать срок блокировки двумя способами сразу
+func (req *SuspendRequest) Check(errs *validation.Error) {
+	if req.Until != nil && strings.TrimSpace(req.Duration) != "" {
+		errs.Add("duration", validation.CodeConflicting)
+	}
+}
+
+// End возвращает момент окончания блокировки, отсчитывая Duration от now
+func (req *SuspendRequest) End(now time.Time) *time.Time {
+	duration, err := time.ParseDuration(strings.TrimSpace(req.Duration))
+	if err != nil {
+		return req.Until
+	}
+	end := now.Add(duration)
+	return &end
+}
+
+// ListUsersQuery — фильтр списка пользователей: q ищет по логину и почте
+type ListUsersQuery struct {
+	Query      string `form:"q"`
+	Permission string `form:"permission" validate:"oneof=user moderator admin"`
+	Status     string `form:"status" validate:"oneof=active suspended"`
+	PageQuery
+}
+
+// AuditLogQuery — фильтр журнала действий; from и to — в RFC 3339
+type AuditLogQuery struct {
+	ActorID    string     `form:"actor_id" validate:"uuid"`
+	Action     string     `form:"action"`
+	EntityType string     `form:"entity_type"`
+	EntityID   string     `form:"entity_id"`
+	From       *time.Time `form:"from"`
+	To         *time.Time `form:"to"`
+	PageQuery
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/models"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+	"time"
+)
+
+type AdminHandler struct {
+	adminUseCase interfaces.AdminUseCase
+}
+
+func NewAdminHandler(adminUseCase interfaces.AdminUseCase) *AdminHandler {
+	return &AdminHandler{
+		adminUseCase: adminUseCase,
+	}
+}
+
+// adminUserResponse — пользователь глазами администратора: в отличие от
+// публичного профиля, включает блокировку
+type adminUserResponse struct {
+	UserResponse
+	Suspended  bool               `json:"suspended"`
+	Suspension *models.Suspension `json:"suspension,omitempty"`
+}
+
+func toAdminUserResponse(user *models.User) adminUserResponse {
+	response := adminUserResponse{UserResponse: toUserResponse(user)}
+	if user.IsSuspended(time.Now()) {
+		response.Suspended = true
+		response.Suspension = user.Suspension
+	}
+	return response
+}
+
+type pageResponse struct {
+	Items  interface{} `json:"items"`
+	Total  int         `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+type forceLogoutResponse struct {
+	RevokedSessions int `json:"revoked_sessions"`
+}
+
+// ListUsers возвращает страницу пользователей. Параметры: q (логин или
+// почта), permission, status (active, suspended), limit, offset
+func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	var query dto.ListUsersQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter := models.UserFilter{
+		Query:      query.Query,
+		Permission: models.Permission(query.Permission),
+		Status:     models.UserStatus(query.Status),
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+	}
+	page, err := h.adminUseCase.ListUsers(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	users := make([]adminUserResponse, 0, len(page.Users))
+	for _, user := range page.Users {
+		users = append(users, toAdminUserResponse(user))
+	}
+	writeJSON(w, http.StatusOK, pageResponse{Items: users, Total: page.Total, Limit: page.Limit, Offset: page.Offset})
+}
+
+func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.adminUseCase.GetUser(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAdminUserResponse(user))
+}
+
+// SuspendUser блокирует пользователя и завершает его сессии
+func (h *AdminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req dto.SuspendRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.adminUseCase.SuspendUser(r.Context(), userID, req.Reason, req.End(time.Now()))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAdminUserResponse(user))
+}
+
+func (h *AdminHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.adminUseCase.UnsuspendUser(r.Context(), userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ForceLogout завершает все сессии пользователя
+func (h *AdminHandler) ForceLogout(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	revoked, err := h.adminUseCase.ForceLogout(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, forceLogoutResponse{RevokedSessions: revoked})
+}
+
+func (h *AdminHandler) GetUserPlaylists(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlists, err := h.adminUseCase.GetUserPlaylists(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if playlists == nil {
+		playlists = []*models.Playlist{}
+	}
+
+	writeJSON(w, http.StatusOK, playlists)
+}
+
+func (h *AdminHandler) GetUserHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	history, err := h.adminUseCase.GetUserHistory(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if history == nil {
+		history = []*models.ListeningHistory{}
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+// ListAuditLog возвращает журнал действий. Параметры: actor_id, action,
+// entity_type, entity_id, from и to (RFC 3339), limit, offset
+func (h *AdminHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	var query dto.AuditLogQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter := models.AuditFilter{
+		Action:     query.Action,
+		EntityType: query.EntityType,
+		EntityID:   query.EntityID,
+		From:       query.From,
+		To:         query.To,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+	}
+	if query.ActorID != "" {
+		actorID := dto.UUID(query.ActorID)
+		filter.ActorID = &actorID
+	}
+
+	page, err := h.adminUseCase.ListAuditLog(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	entries := page.Entries
+	if entries == nil {
+		entries = []*models.AuditEntry{}
+	}
+	writeJSON(w, http.StatusOK, pageResponse{Items: entries, Total: page.Total, Limit: page.Limit, Offset: page.Offset})
+}
@@ -1,15 +1,11 @@
 package handlers
 
 import (
-	"encoding/json"
-	"fmt"
+	"music-service/internal/delivery/http/dto"
 	"music-service/internal/models"
 	"music-service/internal/usecases/interfaces"
 	"net/http"
 	"time"
-
-	"github.com/google/uuid"
-	"github.com/gorilla/mux"
 )
 
 type AlbumHandler struct {
@@ -22,31 +18,6 @@ func NewAlbumHandler(albumUseCase interfaces.AlbumUseCase) *AlbumHandler {
 	}
 }
 
-// Проверка прав администратора
-func (h *AlbumHandler) isAdmin(r *http.Request) bool {
-	// Для быстрого тестирования: проверяем непосредственно токен из Authorization
-	authHeader := r.Header.Get("Authorization")
-
-	// Если это наш фиксированный токен, то разрешаем
-	if authHeader == "Bearer 33333333-3333-3333-3333-333333333333" {
-		fmt.Println("Прямое разрешение по токену админа в AlbumHandler")
-		return true
-	}
-
-	// Обычная проверка по заголовку X-User-Permission
-	permission := r.Header.Get("X-User-Permission")
-	isAdmin := permission == string(models.AdminPermission)
-	fmt.Printf("AlbumHandler - Проверка прав админа: X-User-Permission=%s, isAdmin=%v\n", permission, isAdmin)
-	return isAdmin
-}
-
-type createAlbumRequest struct {
-	Title       string    `json:"title"`
-	ReleaseDate time.Time `json:"release_date"`
-	CoverURL    string    `json:"cover_url"`
-	Artist      string    `json:"artist"`
-}
-
 type albumResponse struct {
 	ID          string    `json:"id"`
 	Title       string    `json:"title"`
@@ -55,6 +26,16 @@ type albumResponse struct {
 	CoverURL    string    `json:"cover_url"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// Loudness — громкость и усиление ReplayGain альбома; null, пока в нем
+	// нет измеренных треков
+	Loudness *albumLoudnessResponse `json:"loudness"`
+}
+
+type albumLoudnessResponse struct {
+	IntegratedLUFS float64 `json:"integrated_lufs"`
+	TruePeakDBTP   float64 `json:"true_peak_dbtp"`
+	AlbumGainDB    float64 `json:"album_gain_db"`
+	AlbumPeak      float64 `json:"album_peak"`
 }
 
 func toAlbumResponse(album *models.Album) albumResponse {
@@ -66,54 +47,50 @@ func toAlbumResponse(album *models.Album) albumResponse {
 		CoverURL:    album.CoverURL,
 		CreatedAt:   album.CreatedAt,
 		UpdatedAt:   album.UpdatedAt,
+		Loudness:    toAlbumLoudnessResponse(album.Loudness),
 	}
 }
 
-func writeAlbumJSON(w http.ResponseWriter, status int, v interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(v)
-}
-
-func writeAlbumError(w http.ResponseWriter, status int, message string) {
-	writeAlbumJSON(w, status, errorResponse{Error: message})
+func toAlbumLoudnessResponse(loudness *models.Loudness) *albumLoudnessResponse {
+	if loudness == nil {
+		return nil
+	}
+	return &albumLoudnessResponse{
+		IntegratedLUFS: roundTo(loudness.IntegratedLUFS, 2),
+		TruePeakDBTP:   roundTo(loudness.TruePeakDBTP, 2),
+		AlbumGainDB:    roundTo(loudness.Gain(), 2),
+		AlbumPeak:      roundTo(loudness.Peak(), 6),
+	}
 }
 
 // CreateAlbum создает новый альбом
 func (h *AlbumHandler) CreateAlbum(w http.ResponseWriter, r *http.Request) {
-	// Проверяем права администратора
-	if !h.isAdmin(r) {
-		writeAlbumError(w, http.StatusForbidden, "Доступ запрещен: требуются права администратора")
-		return
-	}
-
-	var req createAlbumRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeAlbumError(w, http.StatusBadRequest, "Некорректные данные запроса")
+	var req dto.AlbumRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	album, err := h.albumUseCase.CreateAlbum(req.Title, req.Artist, req.ReleaseDate, req.CoverURL)
+	album, err := h.albumUseCase.CreateAlbum(r.Context(), req.Title, req.Artist, req.ReleaseDate, req.CoverURL)
 	if err != nil {
-		writeAlbumError(w, http.StatusBadRequest, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
-	writeAlbumJSON(w, http.StatusCreated, toAlbumResponse(album))
+	writeJSON(w, http.StatusCreated, toAlbumResponse(album))
 }
 
 // GetAlbumDetails получает информацию об альбоме
 func (h *AlbumHandler) GetAlbumDetails(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	albumID, err := uuid.Parse(vars["id"])
+	albumID, err := pathUUID(r, "id")
 	if err != nil {
-		writeAlbumError(w, http.StatusBadRequest, "Недопустимый идентификатор альбома")
+		writeError(w, r, err)
 		return
 	}
 
-	album, tracks, err := h.albumUseCase.GetAlbumDetails(albumID)
+	album, tracks, err := h.albumUseCase.GetAlbumDetails(r.Context(), albumID)
 	if err != nil {
-		writeAlbumError(w, http.StatusNotFound, "Альбом не найден")
+		writeError(w, r, err)
 		return
 	}
 
@@ -127,73 +104,53 @@ func (h *AlbumHandler) GetAlbumDetails(w http.ResponseWriter, r *http.Request) {
 		Tracks:        tracks,
 	}
 
-	writeAlbumJSON(w, http.StatusOK, response)
+	writeJSON(w, http.StatusOK, response)
 }
 
 // UpdateAlbum обновляет информацию об альбоме
 func (h *AlbumHandler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdmin(r) {
-		writeAlbumError(w, http.StatusForbidden, "Доступ запрещен: требуются права администратора")
-		return
-	}
-
-	vars := mux.Vars(r)
-	albumID, err := uuid.Parse(vars["id"])
+	albumID, err := pathUUID(r, "id")
 	if err != nil {
-		writeAlbumError(w, http.StatusBadRequest, "Недопустимый идентификатор альбома")
+		writeError(w, r, err)
 		return
 	}
 
-	var req createAlbumRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeAlbumError(w, http.StatusBadRequest, "Некорректные данные запроса")
+	var req dto.UpdateAlbumRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.albumUseCase.UpdateAlbumInfo(albumID, req.Title, req.Artist, req.CoverURL, req.ReleaseDate); err != nil {
-		writeAlbumError(w, http.StatusBadRequest, err.Error())
+	if err := h.albumUseCase.UpdateAlbumInfo(r.Context(), albumID, req.Title, req.Artist, req.CoverURL, req.ReleaseDate); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	album, _, err := h.albumUseCase.GetAlbumDetails(albumID)
+	album, _, err := h.albumUseCase.GetAlbumDetails(r.Context(), albumID)
 	if err != nil {
-		writeAlbumError(w, http.StatusInternalServerError, "Ошибка при получении обновленных данных альбома")
+		writeError(w, r, err)
 		return
 	}
 
-	writeAlbumJSON(w, http.StatusOK, toAlbumResponse(album))
+	writeJSON(w, http.StatusOK, toAlbumResponse(album))
 }
 
 // AddTrackToAlbum добавляет трек в альбом
 func (h *AlbumHandler) AddTrackToAlbum(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdmin(r) {
-		writeAlbumError(w, http.StatusForbidden, "Доступ запрещен: требуются права администратора")
-		return
-	}
-
-	vars := mux.Vars(r)
-	albumID, err := uuid.Parse(vars["id"])
+	albumID, err := pathUUID(r, "id")
 	if err != nil {
-		writeAlbumError(w, http.StatusBadRequest, "Недопустимый идентификатор альбома")
+		writeError(w, r, err)
 		return
 	}
 
-	var req struct {
-		TrackID string `json:"track_id"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeAlbumError(w, http.StatusBadRequest, "Некорректные данные запроса")
+	var req dto.TrackRefRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	trackID, err := uuid.Parse(req.TrackID)
-	if err != nil {
-		writeAlbumError(w, http.StatusBadRequest, "Недопустимый идентификатор трека")
-		return
-	}
-
-	if err := h.albumUseCase.AddTrackToAlbum(albumID, trackID); err != nil {
-		writeAlbumError(w, http.StatusBadRequest, err.Error())
+	if err := h.albumUseCase.AddTrackToAlbum(r.Context(), albumID, dto.UUID(req.TrackID)); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -202,26 +159,20 @@ func (h *AlbumHandler) AddTrackToAlbum(w http.ResponseWriter, r *http.Request) {
 
 // RemoveTrackFromAlbum удаляет трек из альбома
 func (h *AlbumHandler) RemoveTrackFromAlbum(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdmin(r) {
-		writeAlbumError(w, http.StatusForbidden, "Доступ запрещен: требуются права администратора")
-		return
-	}
-
-	vars := mux.Vars(r)
-	albumID, err := uuid.Parse(vars["id"])
+	albumID, err := pathUUID(r, "id")
 	if err != nil {
-		writeAlbumError(w, http.StatusBadRequest, "Недопустимый идентификатор альбома")
+		writeError(w, r, err)
 		return
 	}
 
-	trackID, err := uuid.Parse(vars["track_id"])
+	trackID, err := pathUUID(r, "track_id")
 	if err != nil {
-		writeAlbumError(w, http.StatusBadRequest, "Недопустимый идентификатор трека")
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.albumUseCase.RemoveTrackFromAlbum(albumID, trackID); err != nil {
-		writeAlbumError(w, http.StatusBadRequest, err.Error())
+	if err := h.albumUseCase.RemoveTrackFromAlbum(r.Context(), albumID, trackID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -230,9 +181,9 @@ func (h *AlbumHandler) RemoveTrackFromAlbum(w http.ResponseWriter, r *http.Reque
 
 // ListAllAlbums выводит список всех альбомов
 func (h *AlbumHandler) ListAllAlbums(w http.ResponseWriter, r *http.Request) {
-	albums, err := h.albumUseCase.ListAll()
+	albums, err := h.albumUseCase.ListAll(r.Context())
 	if err != nil {
-		writeAlbumError(w, http.StatusInternalServerError, "Ошибка при получении списка альбомов")
+		writeError(w, r, err)
 		return
 	}
 
@@ -241,26 +192,19 @@ func (h *AlbumHandler) ListAllAlbums(w http.ResponseWriter, r *http.Request) {
 		response = append(response, toAlbumResponse(album))
 	}
 
-	writeAlbumJSON(w, http.StatusOK, response)
+	writeJSON(w, http.StatusOK, response)
 }
 
 // DeleteAlbum удаляет альбом
 func (h *AlbumHandler) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
-	// Проверяем права администратора
-	if !h.isAdmin(r) {
-		writeAlbumError(w, http.StatusForbidden, "Доступ запрещен: требуются права администратора")
-		return
-	}
-
-	vars := mux.Vars(r)
-	albumID, err := uuid.Parse(vars["id"])
+	albumID, err := pathUUID(r, "id")
 	if err != nil {
-		writeAlbumError(w, http.StatusBadRequest, "Недопустимый идентификатор альбома")
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.albumUseCase.Delete(albumID); err != nil {
-		writeAlbumError(w, http.StatusBadRequest, err.Error())
+	if err := h.albumUseCase.Delete(r.Context(), albumID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
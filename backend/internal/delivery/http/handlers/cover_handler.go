@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"errors"
+	"mime/multipart"
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/logging"
+	"music-service/internal/models"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// immutableCache — для файлов, содержимое которых по адресу не меняется
+	immutableCache = "public, max-age=31536000, immutable"
+	// revalidateCache — клиент хранит ответ, но перепроверяет его по ETag
+	revalidateCache = "public, no-cache"
+)
+
+// coverTypes сопоставляет сегмент пути типу объекта с обложкой
+var coverTypes = map[string]string{
+	"tracks":    models.CoverTrack,
+	"albums":    models.CoverAlbum,
+	"playlists": models.CoverPlaylist,
+}
+
+type CoverHandler struct {
+	coverUseCase  interfaces.CoverUseCase
+	maxFileSizeMB int
+}
+
+func NewCoverHandler(coverUseCase interfaces.CoverUseCase, maxFileSizeMB int) *CoverHandler {
+	return &CoverHandler{
+		coverUseCase:  coverUseCase,
+		maxFileSizeMB: maxFileSizeMB,
+	}
+}
+
+// coverResponse — загруженная обложка с адресом для cover_url
+type coverResponse struct {
+	*models.Cover
+	URL string `json:"url"`
+}
+
+// UploadCover загружает обложку трека, альбома или плейлиста (поле формы
+// file) и подставляет ее в cover_url
+func (h *CoverHandler) UploadCover(w http.ResponseWriter, r *http.Request) {
+	entityType, id, ok := parseCoverTarget(w, r)
+	if !ok {
+		return
+	}
+
+	file, ok := h.readCoverFile(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	cover, err := h.coverUseCase.SetCover(r.Context(), entityType, id, file)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	logging.FromContext(r.Context()).Info("cover uploaded",
+		"cover_id", cover.ID, "entity_type", entityType, "entity_id", id, "width", cover.Width, "height", cover.Height)
+	writeJSON(w, http.StatusOK, coverResponse{Cover: cover, URL: cover.URL()})
+}
+
+// DeleteCover убирает обложку трека, альбома или плейлиста
+func (h *CoverHandler) DeleteCover(w http.ResponseWriter, r *http.Request) {
+	entityType, id, ok := parseCoverTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.coverUseCase.RemoveCover(r.Context(), entityType, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UploadArtistCover загружает обложку исполнителя из пути
+func (h *CoverHandler) UploadArtistCover(w http.ResponseWriter, r *http.Request) {
+	file, ok := h.readCoverFile(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	cover, err := h.coverUseCase.SetArtistCover(r.Context(), mux.Vars(r)["name"], file)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, coverResponse{Cover: cover, URL: cover.URL()})
+}
+
+// DeleteArtistCover убирает обложку исполнителя
+func (h *CoverHandler) DeleteArtistCover(w http.ResponseWriter, r *http.Request) {
+	if err := h.coverUseCase.RemoveArtistCover(r.Context(), mux.Vars(r)["name"]); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetArtistCover перенаправляет на обложку исполнителя
+func (h *CoverHandler) GetArtistCover(w http.ResponseWriter, r *http.Request) {
+	var query dto.CoverQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	coverURL, err := h.coverUseCase.GetArtistCoverURL(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if query.Size != "" {
+		coverURL += "?size=" + url.QueryEscape(query.Size)
+	}
+
+	w.Header().Set("Cache-Control", revalidateCache)
+	http.Redirect(w, r, coverURL, http.StatusFound)
+}
+
+// GetCover отдает загруженную обложку. Размер задается параметром size,
+// формат выбирается по заголовку Accept
+func (h *CoverHandler) GetCover(w http.ResponseWriter, r *http.Request) {
+	coverID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var query dto.CoverQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	file, err := h.coverUseCase.GetCoverFile(r.Context(), coverID, query.Size, r.Header.Get("Accept"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	serveCoverFile(w, r, file)
+}
+
+// GetPlaylistCover отдает обложку плейлиста: перенаправляет на заданную
+// обложку или отдает мозаику из обложек его альбомов
+func (h *CoverHandler) GetPlaylistCover(w http.ResponseWriter, r *http.Request) {
+	playlistID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var query dto.CoverQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	redirect, file, err := h.coverUseCase.GetPlaylistCover(r.Context(), playlistID, query.Size, r.Header.Get("Accept"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if redirect != "" {
+		w.Header().Set("Cache-Control", revalidateCache)
+		http.Redirect(w, r, redirect, http.StatusFound)
+		return
+	}
+	serveCoverFile(w, r, file)
+}
+
+// readCoverFile возвращает файл из поля формы file. При ошибке ответ уже
+// отправлен
+func (h *CoverHandler) readCoverFile(w http.ResponseWriter, r *http.Request) (multipart.File, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, int64(h.maxFileSizeMB<<20))
+
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, r, err)
+			return nil, false
+		}
+		writeError(w, r, errInvalidBody)
+		return nil, false
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, r, models.ErrCoverFileRequired)
+		return nil, false
+	}
+	return file, true
+}
+
+// serveCoverFile отдает файл обложки с заголовками кеширования. Условные
+// запросы (If-None-Match) и Range обрабатывает http.ServeContent
+func serveCoverFile(w http.ResponseWriter, r *http.Request, coverFile *models.CoverFile) {
+	file, err := os.Open(coverFile.Path)
+	if err != nil {
+		logging.FromContext(r.Context()).Warn("open cover file failed", "path", coverFile.Path, "error", err)
+		writeError(w, r, models.ErrCoverNotFound)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", coverFile.ContentType)
+	header.Set("ETag", coverFile.ETag)
+	header.Set("Vary", "Accept")
+	if coverFile.Immutable {
+		header.Set("Cache-Control", immutableCache)
+	} else {
+		header.Set("Cache-Control", revalidateCache)
+	}
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
+
+// parseCoverTarget определяет тип и ID объекта по пути запроса. При ошибке
+// ответ уже отправлен
+func parseCoverTarget(w http.ResponseWriter, r *http.Request) (string, uuid.UUID, bool) {
+	entityType, ok := coverTypes[mux.Vars(r)["type"]]
+	if !ok {
+		writeError(w, r, models.ErrCoverNotFound)
+		return "", uuid.Nil, false
+	}
+
+	id, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return "", uuid.Nil, false
+	}
+	return entityType, id, true
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/models"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DuplicateHandler struct {
+	duplicateUseCase interfaces.DuplicateUseCase
+}
+
+func NewDuplicateHandler(duplicateUseCase interfaces.DuplicateUseCase) *DuplicateHandler {
+	return &DuplicateHandler{
+		duplicateUseCase: duplicateUseCase,
+	}
+}
+
+// ListDuplicates возвращает группы треков с похожими акустическими
+// отпечатками, самые похожие — первыми. Параметры: limit, offset
+func (h *DuplicateHandler) ListDuplicates(w http.ResponseWriter, r *http.Request) {
+	var query dto.PageQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := h.duplicateUseCase.ListDuplicates(r.Context(), query.Limit, query.Offset)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	clusters := make([]duplicateClusterResponse, 0, len(page.Items))
+	for _, cluster := range page.Items {
+		response := duplicateClusterResponse{
+			Similarity: roundTo(cluster.Similarity, 3),
+			Tracks:     make([]duplicateTrackResponse, 0, len(cluster.Tracks)),
+			Pairs:      make([]duplicatePairResponse, 0, len(cluster.Pairs)),
+		}
+		for _, track := range cluster.Tracks {
+			response.Tracks = append(response.Tracks, toDuplicateTrackResponse(track))
+		}
+		for _, pair := range cluster.Pairs {
+			response.Pairs = append(response.Pairs, duplicatePairResponse{
+				TrackID:     pair.TrackID,
+				DuplicateID: pair.DuplicateID,
+				Similarity:  roundTo(pair.Similarity, 3),
+				DetectedAt:  pair.DetectedAt,
+			})
+		}
+		clusters = append(clusters, response)
+	}
+	writeJSON(w, http.StatusOK, pageResponse{Items: clusters, Total: page.Total, Limit: page.Limit, Offset: page.Offset})
+}
+
+// MergeTracks объединяет дубликаты с выбранным треком: плейлисты и история
+// прослушиваний переходят к нему, дубликаты попадают в корзину
+func (h *DuplicateHandler) MergeTracks(w http.ResponseWriter, r *http.Request) {
+	var req dto.MergeTracksRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.duplicateUseCase.MergeTracks(r.Context(), dto.UUID(req.TargetID), dto.UUIDs(req.SourceIDs)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type duplicateClusterResponse struct {
+	Similarity float64                  `json:"similarity"`
+	Tracks     []duplicateTrackResponse `json:"tracks"`
+	Pairs      []duplicatePairResponse  `json:"pairs"`
+}
+
+type duplicateTrackResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Title      string    `json:"title"`
+	ArtistName string    `json:"artist_name"`
+	AlbumID    uuid.UUID `json:"album_id"`
+	Duration   int       `json:"duration"`
+	PlayCount  int       `json:"play_count"`
+	AddedDate  time.Time `json:"added_date"`
+}
+
+func toDuplicateTrackResponse(track *models.Track) duplicateTrackResponse {
+	return duplicateTrackResponse{
+		ID:         track.ID,
+		Title:      track.Title,
+		ArtistName: track.ArtistName,
+		AlbumID:    track.AlbumID,
+		Duration:   track.Duration,
+		PlayCount:  track.PlayCount,
+		AddedDate:  track.AddedDate,
+	}
+}
+
+type duplicatePairResponse struct {
+	TrackID     uuid.UUID `json:"track_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+	Similarity  float64   `json:"similarity"`
+	DetectedAt  time.Time `json:"detected_at"`
+}
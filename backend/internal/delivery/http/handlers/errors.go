@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"music-service/internal/delivery/http/problem"
+	"music-service/internal/models"
+	"net/http"
+)
+
+// Ошибки разбора запроса, которые обнаруживает транспортный слой
+var (
+	errInvalidBody     = models.NewDomainError(models.ErrInvalidInput, "invalid_request_body", "invalid request body")
+	errFileRequired    = models.NewFieldError("file_required", "file", "file is required")
+	errUnsupportedFile = models.NewFieldError("unsupported_file_type", "file", "only MP3 files are allowed")
+)
+
+// writeError отвечает ошибкой в формате problem+json. Статус и сообщение
+// выбираются по типу ошибки, см. problem.Write
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	problem.Write(w, r, err)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// etag строит сильный ETag по времени последнего изменения объекта
+func etag(updatedAt time.Time) string {
+	return `"` + strconv.FormatInt(updatedAt.UnixMicro(), 36) + `"`
+}
+
+// parseETag возвращает время изменения из ETag, выданного etag. Слабые
+// ETag (W/"...") не принимаются: If-Match требует точного совпадения
+func parseETag(tag string) (time.Time, bool) {
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return time.Time{}, false
+	}
+	micros, err := strconv.ParseInt(tag[1:len(tag)-1], 36, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(micros), true
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"music-service/internal/events"
+	"net/http"
+	"time"
+)
+
+const (
+	// Интервал отправки комментариев-пингов, чтобы прокси не закрывали соединение
+	sseHeartbeatInterval = 25 * time.Second
+	// Задержка переподключения, которую браузерный EventSource использует после обрыва
+	sseRetryMs = 3000
+)
+
+type EventHandler struct {
+	bus events.Bus
+}
+
+func NewEventHandler(bus events.Bus) *EventHandler {
+	return &EventHandler{
+		bus: bus,
+	}
+}
+
+// StreamEvents отправляет события пользователя через Server-Sent Events.
+// После переподключения клиент передает ID последнего полученного события
+// в заголовке Last-Event-ID (или параметре last_event_id) и получает
+// пропущенные события. Если они уже недоступны, приходит событие resync
+func (h *EventHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromSession(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, r, errors.New("streaming is not supported"))
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	sub := h.bus.Subscribe(userID, getDeviceIDFromSession(r), lastEventID)
+	defer h.bus.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryMs)
+	for _, event := range sub.Replay {
+		writeSSE(w, event)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			writeSSE(w, event)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, event events.Event) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"music-service/internal/models"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type FollowHandler struct {
+	followUseCase interfaces.FollowUseCase
+}
+
+func NewFollowHandler(followUseCase interfaces.FollowUseCase) *FollowHandler {
+	return &FollowHandler{
+		followUseCase: followUseCase,
+	}
+}
+
+// FollowPlaylist подписывает пользователя на плейлист
+func (h *FollowHandler) FollowPlaylist(w http.ResponseWriter, r *http.Request) {
+	h.handlePlaylist(w, r, h.followUseCase.FollowPlaylist)
+}
+
+// UnfollowPlaylist отменяет подписку на плейлист
+func (h *FollowHandler) UnfollowPlaylist(w http.ResponseWriter, r *http.Request) {
+	h.handlePlaylist(w, r, h.followUseCase.UnfollowPlaylist)
+}
+
+// GetFollowedPlaylists возвращает плейлисты, на которые подписан пользователь
+func (h *FollowHandler) GetFollowedPlaylists(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromSession(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlists, err := h.followUseCase.GetFollowedPlaylists(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if playlists == nil {
+		playlists = []*models.Playlist{}
+	}
+	writeJSON(w, http.StatusOK, playlists)
+}
+
+// FollowArtist подписывает пользователя на новые релизы исполнителя
+func (h *FollowHandler) FollowArtist(w http.ResponseWriter, r *http.Request) {
+	h.handleArtist(w, r, h.followUseCase.FollowArtist)
+}
+
+// UnfollowArtist отменяет подписку на исполнителя
+func (h *FollowHandler) UnfollowArtist(w http.ResponseWriter, r *http.Request) {
+	h.handleArtist(w, r, h.followUseCase.UnfollowArtist)
+}
+
+// GetFollowedArtists возвращает исполнителей, на которых подписан пользователь
+func (h *FollowHandler) GetFollowedArtists(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromSession(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	artists, err := h.followUseCase.GetFollowedArtists(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if artists == nil {
+		artists = []string{}
+	}
+	writeJSON(w, http.StatusOK, artists)
+}
+
+func (h *FollowHandler) handlePlaylist(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(ctx context.Context, userID, playlistID uuid.UUID) error,
+) {
+	userID, err := getUserIDFromSession(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlistID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := action(r.Context(), userID, playlistID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FollowHandler) handleArtist(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(ctx context.Context, userID uuid.UUID, artist string) error,
+) {
+	userID, err := getUserIDFromSession(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := action(r.Context(), userID, mux.Vars(r)["name"]); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,12 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"music-service/internal/delivery/http/dto"
 	"music-service/internal/models"
 	"music-service/internal/usecases/interfaces"
 	"net/http"
-
-	"github.com/google/uuid"
-	"github.com/gorilla/mux"
 )
 
 type GenreHandler struct {
@@ -20,22 +18,6 @@ func NewGenreHandler(genreUseCase interfaces.GenreUseCase) *GenreHandler {
 	}
 }
 
-// Проверка прав администратора
-func (h *GenreHandler) isAdmin(r *http.Request) bool {
-	authHeader := r.Header.Get("Authorization")
-
-	if authHeader == "Bearer 33333333-3333-3333-3333-333333333333" {
-		return true
-	}
-	permission := r.Header.Get("X-User-Permission")
-	isAdmin := permission == string(models.AdminPermission)
-	return isAdmin
-}
-
-type createGenreRequest struct {
-	Name string `json:"name"`
-}
-
 type genreResponse struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -50,20 +32,15 @@ func toGenreResponse(genre *models.Genre) genreResponse {
 
 // CreateGenre создает новый жанр
 func (h *GenreHandler) CreateGenre(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdmin(r) {
-		http.Error(w, "Доступ запрещен: требуются права администратора", http.StatusForbidden)
-		return
-	}
-
-	var req createGenreRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Некорректные данные запроса", http.StatusBadRequest)
+	var req dto.GenreRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	genre, err := h.genreUseCase.CreateGenre(req.Name)
+	genre, err := h.genreUseCase.CreateGenre(r.Context(), req.Name)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
@@ -73,9 +50,9 @@ func (h *GenreHandler) CreateGenre(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *GenreHandler) ListAllGenres(w http.ResponseWriter, r *http.Request) {
-	genres, err := h.genreUseCase.ListAllGenres()
+	genres, err := h.genreUseCase.ListAllGenres(r.Context())
 	if err != nil {
-		http.Error(w, "Ошибка при получении списка жанров", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -90,16 +67,15 @@ func (h *GenreHandler) ListAllGenres(w http.ResponseWriter, r *http.Request) {
 
 // GetGenresByTrack возвращает список жанров для трека
 func (h *GenreHandler) GetGenresByTrack(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	trackID, err := uuid.Parse(vars["id"])
+	trackID, err := pathUUID(r, "id")
 	if err != nil {
-		http.Error(w, "Недопустимый идентификатор трека", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	genres, err := h.genreUseCase.GetGenresByTrack(trackID)
+	genres, err := h.genreUseCase.GetGenresByTrack(r.Context(), trackID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, r, err)
 		return
 	}
 
@@ -113,34 +89,20 @@ func (h *GenreHandler) GetGenresByTrack(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *GenreHandler) AssignGenreToTrack(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdmin(r) {
-		http.Error(w, "Доступ запрещен: требуются права администратора", http.StatusForbidden)
-		return
-	}
-
-	vars := mux.Vars(r)
-	trackID, err := uuid.Parse(vars["id"])
+	trackID, err := pathUUID(r, "id")
 	if err != nil {
-		http.Error(w, "Недопустимый идентификатор трека", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	var req struct {
-		GenreID string `json:"genre_id"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Некорректные данные запроса", http.StatusBadRequest)
+	var req dto.AssignGenreRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	genreID, err := uuid.Parse(req.GenreID)
-	if err != nil {
-		http.Error(w, "Недопустимый идентификатор жанра", http.StatusBadRequest)
-		return
-	}
-
-	if err := h.genreUseCase.AssignGenreToTrack(trackID, genreID); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := h.genreUseCase.AssignGenreToTrack(r.Context(), trackID, dto.UUID(req.GenreID)); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -148,26 +110,20 @@ func (h *GenreHandler) AssignGenreToTrack(w http.ResponseWriter, r *http.Request
 }
 
 func (h *GenreHandler) RemoveGenreFromTrack(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdmin(r) {
-		http.Error(w, "Доступ запрещен: требуются права администратора", http.StatusForbidden)
-		return
-	}
-
-	vars := mux.Vars(r)
-	trackID, err := uuid.Parse(vars["trackId"])
+	trackID, err := pathUUID(r, "trackId")
 	if err != nil {
-		http.Error(w, "Недопустимый идентификатор трека", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	genreID, err := uuid.Parse(vars["genreId"])
+	genreID, err := pathUUID(r, "genreId")
 	if err != nil {
-		http.Error(w, "Недопустимый идентификатор жанра", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.genreUseCase.RemoveGenreFromTrack(trackID, genreID); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := h.genreUseCase.RemoveGenreFromTrack(r.Context(), trackID, genreID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
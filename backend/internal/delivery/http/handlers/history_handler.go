@@ -2,13 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
+	"music-service/internal/delivery/http/dto"
 	"music-service/internal/usecases/interfaces"
 	"net/http"
 	"time"
-
-	"github.com/google/uuid"
-	"github.com/gorilla/mux"
 )
 
 type HistoryHandler struct {
@@ -23,23 +20,21 @@ func NewHistoryHandler(historyUseCase interfaces.HistoryUseCase) *HistoryHandler
 
 // RecordPlayback записывает прослушивание трека
 func (h *HistoryHandler) RecordPlayback(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	trackID, err := uuid.Parse(vars["trackId"])
+	trackID, err := pathUUID(r, "trackId")
 	if err != nil {
-		http.Error(w, "Некорректный ID трека", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	userIDStr := r.Header.Get("X-User-ID")
-	userID, err := uuid.Parse(userIDStr)
+	userID, err := getUserIDFromSession(r)
 	if err != nil {
-		http.Error(w, "Не удалось определить пользователя", http.StatusUnauthorized)
+		writeError(w, r, err)
 		return
 	}
 
-	err = h.historyUseCase.RecordPlayback(userID, trackID)
+	err = h.historyUseCase.RecordPlayback(r.Context(), userID, trackID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -50,13 +45,13 @@ func (h *HistoryHandler) RecordPlayback(w http.ResponseWriter, r *http.Request)
 func (h *HistoryHandler) GetUserHistory(w http.ResponseWriter, r *http.Request) {
 	userID, err := getUserIDFromSession(r)
 	if err != nil {
-		http.Error(w, "Не удалось определить пользователя", http.StatusUnauthorized)
+		writeError(w, r, err)
 		return
 	}
 
-	history, err := h.historyUseCase.GetUserHistory(userID)
+	history, err := h.historyUseCase.GetUserHistory(r.Context(), userID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -66,21 +61,21 @@ func (h *HistoryHandler) GetUserHistory(w http.ResponseWriter, r *http.Request)
 
 // GetRecentPlays возвращает недавние прослушивания
 func (h *HistoryHandler) GetRecentPlays(w http.ResponseWriter, r *http.Request) {
-	userIDStr := r.Header.Get("X-User-ID")
-	userID, err := uuid.Parse(userIDStr)
+	userID, err := getUserIDFromSession(r)
 	if err != nil {
-		http.Error(w, "Не удалось определить пользователя", http.StatusUnauthorized)
+		writeError(w, r, err)
 		return
 	}
 
-	hours := 24
-	if hoursParam := r.URL.Query().Get("hours"); hoursParam != "" {
-		fmt.Sscanf(hoursParam, "%d", &hours)
+	query := dto.RecentPlaysQuery{Hours: 24}
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
 	}
 
-	history, err := h.historyUseCase.GetRecentPlays(userID, time.Duration(hours)*time.Hour)
+	history, err := h.historyUseCase.GetRecentPlays(r.Context(), userID, time.Duration(query.Hours)*time.Hour)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
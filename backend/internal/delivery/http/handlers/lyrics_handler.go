@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/models"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+)
+
+type LyricsHandler struct {
+	lyricsUseCase interfaces.LyricsUseCase
+}
+
+func NewLyricsHandler(lyricsUseCase interfaces.LyricsUseCase) *LyricsHandler {
+	return &LyricsHandler{
+		lyricsUseCase: lyricsUseCase,
+	}
+}
+
+// GetLyrics возвращает текст песни трека построчно, с метками времени для
+// синхронизированного текста
+func (h *LyricsHandler) GetLyrics(w http.ResponseWriter, r *http.Request) {
+	trackID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	lyrics, err := h.lyricsUseCase.GetLyrics(r.Context(), trackID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lyrics)
+}
+
+// SetLyrics сохраняет текст песни. Текст пользователя без прав модератора
+// публикуется после модерации
+func (h *LyricsHandler) SetLyrics(w http.ResponseWriter, r *http.Request) {
+	trackID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req dto.SetLyricsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	lyrics, err := h.lyricsUseCase.SetLyrics(r.Context(), trackID, req.Text, req.Language)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lyrics)
+}
+
+// DeleteLyrics удаляет текст песни трека
+func (h *LyricsHandler) DeleteLyrics(w http.ResponseWriter, r *http.Request) {
+	trackID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.lyricsUseCase.DeleteLyrics(r.Context(), trackID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ModerateLyrics меняет статус модерации текста песни
+func (h *LyricsHandler) ModerateLyrics(w http.ResponseWriter, r *http.Request) {
+	trackID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req dto.ModerateLyricsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	lyrics, err := h.lyricsUseCase.ModerateLyrics(r.Context(), trackID, req.Status)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lyrics)
+}
+
+// ListLyrics возвращает очередь модерации текстов песен
+func (h *LyricsHandler) ListLyrics(w http.ResponseWriter, r *http.Request) {
+	var query dto.LyricsQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := h.lyricsUseCase.ListLyrics(r.Context(), models.LyricsFilter{
+		Status: query.Status,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	items := page.Items
+	if items == nil {
+		items = []*models.Lyrics{}
+	}
+	writeJSON(w, http.StatusOK, pageResponse{Items: items, Total: page.Total, Limit: page.Limit, Offset: page.Offset})
+}
+
+// SearchLyrics ищет треки по текстам песен
+func (h *LyricsHandler) SearchLyrics(w http.ResponseWriter, r *http.Request) {
+	var query dto.SearchLyricsQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	matches, err := h.lyricsUseCase.SearchLyrics(r.Context(), query.Query, query.Limit, query.Offset)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if matches == nil {
+		matches = []*models.LyricsMatch{}
+	}
+	writeJSON(w, http.StatusOK, matches)
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/mfa"
+	"music-service/internal/models"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+	"time"
+)
+
+type MFAHandler struct {
+	mfaUseCase interfaces.MFAUseCase
+}
+
+func NewMFAHandler(mfaUseCase interfaces.MFAUseCase) *MFAHandler {
+	return &MFAHandler{
+		mfaUseCase: mfaUseCase,
+	}
+}
+
+// mfaChallengeResponse — ответ на вход по паролю, когда нужен второй фактор
+type mfaChallengeResponse struct {
+	MFARequired        bool      `json:"mfa_required"`
+	MFAToken           string    `json:"mfa_token"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
+}
+
+type mfaLoginResponse struct {
+	authResponse
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type requiredRolesResponse struct {
+	Roles []models.Permission `json:"roles"`
+}
+
+func writeMFAChallenge(w http.ResponseWriter, challenge *mfa.ChallengeRequired) {
+	writeJSON(w, http.StatusOK, mfaChallengeResponse{
+		MFARequired:        true,
+		MFAToken:           challenge.Token,
+		ExpiresAt:          challenge.ExpiresAt,
+		EnrollmentRequired: challenge.EnrollmentRequired,
+	})
+}
+
+// CompleteChallenge — второй шаг входа: код из приложения или код
+// восстановления. Если TOTP подключался при входе, код подтверждает
+// подключение, а в ответе возвращаются коды восстановления
+func (h *MFAHandler) CompleteChallenge(w http.ResponseWriter, r *http.Request) {
+	var req dto.MFAChallengeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	login, err := h.mfaUseCase.CompleteChallenge(r.Context(), req.MFAToken, req.Code, clientInfo(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setTokenCookies(w, login.Tokens)
+
+	writeJSON(w, http.StatusOK, mfaLoginResponse{
+		authResponse: authResponse{
+			User:      toUserResponse(login.User),
+			Session:   login.Session,
+			TokenPair: login.Tokens,
+		},
+		RecoveryCodes: login.RecoveryCodes,
+	})
+}
+
+// BeginChallengeEnrollment выдает секрет TOTP пользователю, которому второй
+// фактор обязателен, но еще не подключен
+func (h *MFAHandler) BeginChallengeEnrollment(w http.ResponseWriter, r *http.Request) {
+	var req dto.MFATokenRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	enrollment, err := h.mfaUseCase.BeginChallengeEnrollment(r.Context(), req.MFAToken)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, enrollment)
+}
+
+func (h *MFAHandler) Status(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	status, err := h.mfaUseCase.Status(r.Context(), principal.UserID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+// BeginEnrollment выдает секрет и otpauth-URI для QR-кода. Второй фактор
+// включится после подтверждения кодом
+func (h *MFAHandler) BeginEnrollment(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	enrollment, err := h.mfaUseCase.BeginEnrollment(r.Context(), principal.UserID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, enrollment)
+}
+
+func (h *MFAHandler) ConfirmEnrollment(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	codes, err := h.mfaUseCase.ConfirmEnrollment(r.Context(), principal.UserID, req.Code)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *MFAHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.mfaUseCase.Disable(r.Context(), principal.UserID, req.Code); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	codes, err := h.mfaUseCase.RegenerateRecoveryCodes(r.Context(), principal.UserID, req.Code)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// ResetForUser отключает второй фактор пользователю, потерявшему доступ к нему
+func (h *MFAHandler) ResetForUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.mfaUseCase.ResetForUser(r.Context(), userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MFAHandler) RequiredRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.mfaUseCase.RequiredRoles(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, requiredRolesResponse{Roles: nonNilRoles(roles)})
+}
+
+// SetRequiredRoles задает роли, для которых второй фактор обязателен
+func (h *MFAHandler) SetRequiredRoles(w http.ResponseWriter, r *http.Request) {
+	var req dto.RequiredRolesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	roles, err := h.mfaUseCase.SetRequiredRoles(r.Context(), req.Roles)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, requiredRolesResponse{Roles: nonNilRoles(roles)})
+}
+
+func nonNilRoles(roles []models.Permission) []models.Permission {
+	if roles == nil {
+		return []models.Permission{}
+	}
+	return roles
+}
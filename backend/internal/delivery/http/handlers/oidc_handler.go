@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/logging"
+	"music-service/internal/mfa"
+	"music-service/internal/models"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type OIDCHandler struct {
+	oidcUseCase       interfaces.OIDCUseCase
+	postLoginRedirect string
+}
+
+// NewOIDCHandler создает обработчик входа через OpenID Connect. Если
+// postLoginRedirect задан, после входа браузер перенаправляется туда, а
+// токены передаются только в куках
+func NewOIDCHandler(oidcUseCase interfaces.OIDCUseCase, postLoginRedirect string) *OIDCHandler {
+	return &OIDCHandler{
+		oidcUseCase:       oidcUseCase,
+		postLoginRedirect: postLoginRedirect,
+	}
+}
+
+type identityResponse struct {
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (h *OIDCHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]string{"providers": h.oidcUseCase.Providers()})
+}
+
+// Login перенаправляет на страницу входа провайдера. С параметром link=true
+// учетная запись провайдера привязывается к текущему пользователю
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	linkUserID := uuid.Nil
+	if r.URL.Query().Get("link") == "true" {
+		principal, ok := authz.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, authz.ErrUnauthenticated)
+			return
+		}
+		linkUserID = principal.UserID
+	}
+
+	authURL, err := h.oidcUseCase.BeginLogin(r.Context(), mux.Vars(r)["provider"], linkUserID)
+	if err != nil {
+		var domainErr *models.DomainError
+		if !errors.As(err, &domainErr) {
+			logging.FromContext(r.Context()).Error("begin oidc login failed", "provider", mux.Vars(r)["provider"], "error", err)
+			err = models.ErrProviderUnavailable
+		}
+		writeError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback принимает ответ провайдера и открывает сессию
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		logging.FromContext(r.Context()).Warn("oidc provider returned error", "provider", mux.Vars(r)["provider"], "provider_error", providerErr)
+		writeError(w, r, models.ErrExternalAuthCancelled)
+		return
+	}
+
+	user, session, tokens, err := h.oidcUseCase.CompleteLogin(
+		r.Context(),
+		mux.Vars(r)["provider"],
+		query.Get("state"),
+		query.Get("code"),
+		dto.AuthRequest{}.Device(r.UserAgent()),
+		clientInfo(r),
+	)
+	if err != nil {
+		var challenge *mfa.ChallengeRequired
+		if errors.As(err, &challenge) {
+			h.writeChallenge(w, r, challenge)
+			return
+		}
+		writeError(w, r, err)
+		return
+	}
+
+	setTokenCookies(w, tokens)
+
+	if h.postLoginRedirect != "" {
+		http.Redirect(w, r, h.postLoginRedirect, http.StatusFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, authResponse{
+		User:      toUserResponse(user),
+		Session:   session,
+		TokenPair: tokens,
+	})
+}
+
+// writeChallenge передает токен второго шага входа. Фронтенду он
+// передается во фрагменте адреса: фрагмент не отправляется на сервер и не
+// попадает в логи
+func (h *OIDCHandler) writeChallenge(w http.ResponseWriter, r *http.Request, challenge *mfa.ChallengeRequired) {
+	if h.postLoginRedirect == "" {
+		writeMFAChallenge(w, challenge)
+		return
+	}
+
+	fragment := url.Values{"mfa_token": {challenge.Token}}
+	if challenge.EnrollmentRequired {
+		fragment.Set("enrollment_required", "true")
+	}
+	target := strings.SplitN(h.postLoginRedirect, "#", 2)[0] + "#" + fragment.Encode()
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+func (h *OIDCHandler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	identities, err := h.oidcUseCase.ListIdentities(r.Context(), principal.UserID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]identityResponse, 0, len(identities))
+	for _, identity := range identities {
+		response = append(response, toIdentityResponse(identity))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *OIDCHandler) UnlinkIdentity(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	if err := h.oidcUseCase.UnlinkIdentity(r.Context(), principal.UserID, mux.Vars(r)["provider"]); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toIdentityResponse(identity *models.Identity) identityResponse {
+	return identityResponse{
+		Provider:  identity.Provider,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
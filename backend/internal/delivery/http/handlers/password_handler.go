@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+)
+
+type PasswordHandler struct {
+	passwordUseCase interfaces.PasswordUseCase
+}
+
+func NewPasswordHandler(passwordUseCase interfaces.PasswordUseCase) *PasswordHandler {
+	return &PasswordHandler{
+		passwordUseCase: passwordUseCase,
+	}
+}
+
+// ChangePassword меняет пароль текущего пользователя. Остальные его сессии
+// завершаются, текущая продолжает действовать
+func (h *PasswordHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err := h.passwordUseCase.ChangePassword(r.Context(), principal.UserID, principal.SessionID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ForgotPassword отправляет письмо со ссылкой для сброса пароля. Ответ не
+// зависит от того, зарегистрирована ли почта
+func (h *PasswordHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ForgotPasswordRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.passwordUseCase.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword задает новый пароль по токену из письма
+func (h *PasswordHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResetPasswordRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.passwordUseCase.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
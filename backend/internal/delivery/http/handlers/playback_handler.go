@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/models"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type PlaybackHandler struct {
+	playbackUseCase interfaces.PlaybackUseCase
+}
+
+func NewPlaybackHandler(playbackUseCase interfaces.PlaybackUseCase) *PlaybackHandler {
+	return &PlaybackHandler{
+		playbackUseCase: playbackUseCase,
+	}
+}
+
+// GetPlaybackState возвращает текущее состояние воспроизведения
+func (h *PlaybackHandler) GetPlaybackState(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromSession(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	state, err := h.playbackUseCase.GetState(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, state)
+}
+
+// SetQueue заменяет очередь воспроизведения
+func (h *PlaybackHandler) SetQueue(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetQueueRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.handleCommand(w, r, func(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error) {
+		return h.playbackUseCase.SetQueue(ctx, userID, deviceID, dto.UUIDs(req.TrackIDs), req.StartIndex)
+	})
+}
+
+// AddToQueue добавляет трек в конец очереди или сразу после текущего
+func (h *PlaybackHandler) AddToQueue(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddToQueueRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.handleCommand(w, r, func(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error) {
+		return h.playbackUseCase.AddToQueue(ctx, userID, deviceID, dto.UUID(req.TrackID), req.PlayNext)
+	})
+}
+
+// RemoveFromQueue удаляет трек из очереди по его позиции
+func (h *PlaybackHandler) RemoveFromQueue(w http.ResponseWriter, r *http.Request) {
+	position, err := pathInt(r, "position")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.handleCommand(w, r, func(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error) {
+		return h.playbackUseCase.RemoveFromQueue(ctx, userID, deviceID, position)
+	})
+}
+
+// Play запускает или продолжает воспроизведение
+func (h *PlaybackHandler) Play(w http.ResponseWriter, r *http.Request) {
+	var req dto.PlayRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	h.handleCommand(w, r, func(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error) {
+		return h.playbackUseCase.Play(ctx, userID, deviceID, req.Index)
+	})
+}
+
+// Pause ставит воспроизведение на паузу
+func (h *PlaybackHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.handleCommand(w, r, h.playbackUseCase.Pause)
+}
+
+// Seek перематывает текущий трек
+func (h *PlaybackHandler) Seek(w http.ResponseWriter, r *http.Request) {
+	var req dto.SeekRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.handleCommand(w, r, func(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error) {
+		return h.playbackUseCase.Seek(ctx, userID, deviceID, req.PositionMs)
+	})
+}
+
+// Next переключает на следующий трек
+func (h *PlaybackHandler) Next(w http.ResponseWriter, r *http.Request) {
+	h.handleCommand(w, r, h.playbackUseCase.Next)
+}
+
+// Previous переключает на предыдущий трек
+func (h *PlaybackHandler) Previous(w http.ResponseWriter, r *http.Request) {
+	h.handleCommand(w, r, h.playbackUseCase.Previous)
+}
+
+// SetShuffle включает или выключает перемешивание
+func (h *PlaybackHandler) SetShuffle(w http.ResponseWriter, r *http.Request) {
+	var req dto.ShuffleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.handleCommand(w, r, func(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error) {
+		return h.playbackUseCase.SetShuffle(ctx, userID, deviceID, req.Enabled)
+	})
+}
+
+// SetRepeatMode устанавливает режим повтора (off, all, one)
+func (h *PlaybackHandler) SetRepeatMode(w http.ResponseWriter, r *http.Request) {
+	var req dto.RepeatRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.handleCommand(w, r, func(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error) {
+		return h.playbackUseCase.SetRepeatMode(ctx, userID, deviceID, models.RepeatMode(req.Mode))
+	})
+}
+
+// TransferPlayback переносит воспроизведение на другое устройство пользователя
+func (h *PlaybackHandler) TransferPlayback(w http.ResponseWriter, r *http.Request) {
+	var req dto.TransferPlaybackRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.handleCommand(w, r, func(ctx context.Context, userID, _ uuid.UUID) (*models.PlaybackState, error) {
+		return h.playbackUseCase.TransferPlayback(ctx, userID, dto.UUID(req.DeviceID), req.Play)
+	})
+}
+
+// ListDevices возвращает устройства пользователя с действующими сессиями
+func (h *PlaybackHandler) ListDevices(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromSession(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	devices, err := h.playbackUseCase.ListDevices(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if devices == nil {
+		devices = []*models.Device{}
+	}
+	writeJSON(w, http.StatusOK, devices)
+}
+
+// handleCommand выполняет команду управления воспроизведением от имени текущего устройства
+func (h *PlaybackHandler) handleCommand(
+	w http.ResponseWriter,
+	r *http.Request,
+	command func(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error),
+) {
+	userID, err := getUserIDFromSession(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	state, err := command(r.Context(), userID, getDeviceIDFromSession(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, state)
+}
+
+// getDeviceIDFromSession возвращает устройство текущей сессии или uuid.Nil
+func getDeviceIDFromSession(r *http.Request) uuid.UUID {
+	if principal, ok := authz.FromContext(r.Context()); ok {
+		return principal.DeviceID
+	}
+	return uuid.Nil
+}
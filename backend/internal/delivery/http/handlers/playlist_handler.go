@@ -2,15 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
-	"log"
-	"music-service/internal/models"
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/i18n"
 	"music-service/internal/usecases/interfaces"
 	"net/http"
 
 	"github.com/google/uuid"
-	"github.com/gorilla/mux"
 )
 
 type PlaylistHandler struct {
@@ -30,27 +28,21 @@ func NewPlaylistHandler(
 
 // CreatePlaylist создает новый плейлист
 func (h *PlaylistHandler) CreatePlaylist(w http.ResponseWriter, r *http.Request) {
-	var request struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		CoverURL    string `json:"cover_url"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+	var request dto.PlaylistRequest
+	if err := decodeJSON(r, &request); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	userID, err := getUserIDFromSession(r)
 	if err != nil {
-		http.Error(w, "Необходима авторизация", http.StatusUnauthorized)
+		writeError(w, r, err)
 		return
 	}
 
-	playlist, err := h.playlistUseCase.CreatePlaylist(userID, request.Name, request.Description, request.CoverURL)
+	playlist, err := h.playlistUseCase.CreatePlaylist(r.Context(), userID, request.Name, request.Description, request.CoverURL)
 	if err != nil {
-		log.Printf("Ошибка при создании плейлиста: %v", err)
-		http.Error(w, "Ошибка при создании плейлиста", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -61,20 +53,15 @@ func (h *PlaylistHandler) CreatePlaylist(w http.ResponseWriter, r *http.Request)
 
 // GetPlaylistWithTracks возвращает плейлист с треками
 func (h *PlaylistHandler) GetPlaylistWithTracks(w http.ResponseWriter, r *http.Request) {
-	playlistID, err := uuid.Parse(mux.Vars(r)["id"])
+	playlistID, err := pathUUID(r, "id")
 	if err != nil {
-		http.Error(w, "Неверный ID плейлиста", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	playlistWithTracks, err := h.playlistUseCase.GetPlaylistWithTracks(playlistID)
+	playlistWithTracks, err := h.playlistUseCase.GetPlaylistWithTracks(r.Context(), playlistID)
 	if err != nil {
-		if errors.Is(err, models.ErrNotFound) {
-			http.Error(w, "Плейлист не найден", http.StatusNotFound)
-		} else {
-			log.Printf("Ошибка при получении плейлиста: %v", err)
-			http.Error(w, "Ошибка при получении плейлиста", http.StatusInternalServerError)
-		}
+		writeError(w, r, err)
 		return
 	}
 
@@ -84,72 +71,42 @@ func (h *PlaylistHandler) GetPlaylistWithTracks(w http.ResponseWriter, r *http.R
 
 // EditPlaylistInfo обновляет информацию о плейлисте
 func (h *PlaylistHandler) EditPlaylistInfo(w http.ResponseWriter, r *http.Request) {
-	playlistID, err := uuid.Parse(mux.Vars(r)["id"])
+	playlistID, err := pathUUID(r, "id")
 	if err != nil {
-		http.Error(w, "Неверный ID плейлиста", http.StatusBadRequest)
-		return
-	}
-
-	var request struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	// Проверяем, что пользователь является владельцем плейлиста
-	userID, err := getUserIDFromSession(r)
-	if err != nil {
-		http.Error(w, "Необходима авторизация", http.StatusUnauthorized)
+	var request dto.UpdatePlaylistRequest
+	if err := decodeJSON(r, &request); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	playlist, err := h.playlistUseCase.GetPlaylistWithTracks(playlistID)
-	if err != nil {
-		if errors.Is(err, models.ErrNotFound) {
-			http.Error(w, "Плейлист не найден", http.StatusNotFound)
-		} else {
-			log.Printf("Ошибка при получении плейлиста: %v", err)
-			http.Error(w, "Ошибка при получении плейлиста", http.StatusInternalServerError)
-		}
+	if _, err := getUserIDFromSession(r); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	// Проверяем, что пользователь является владельцем плейлиста
-	if playlist.Playlist.UserID != userID {
-		http.Error(w, "Нет доступа к редактированию плейлиста", http.StatusForbidden)
+	if err := h.playlistUseCase.EditPlaylistInfo(r.Context(), playlistID, request.Name, request.Description); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.playlistUseCase.EditPlaylistInfo(playlistID, request.Name, request.Description); err != nil {
-		log.Printf("Ошибка при обновлении плейлиста: %v", err)
-		http.Error(w, "Ошибка при обновлении плейлиста", http.StatusInternalServerError)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message": "Информация о плейлисте обновлена"}`))
+	writeMessage(w, r, "playlist_updated")
 }
 
 // GetPlaylistTracks возвращает треки из плейлиста
 func (h *PlaylistHandler) GetPlaylistTracks(w http.ResponseWriter, r *http.Request) {
-	playlistID, err := uuid.Parse(mux.Vars(r)["id"])
+	playlistID, err := pathUUID(r, "id")
 	if err != nil {
-		http.Error(w, "Неверный ID плейлиста", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	tracks, err := h.playlistUseCase.GetPlaylistTracks(playlistID)
+	tracks, err := h.playlistUseCase.GetPlaylistTracks(r.Context(), playlistID)
 	if err != nil {
-		if errors.Is(err, models.ErrNotFound) {
-			http.Error(w, "Плейлист не найден", http.StatusNotFound)
-		} else {
-			log.Printf("Ошибка при получении треков: %v", err)
-			http.Error(w, "Ошибка при получении треков", http.StatusInternalServerError)
-		}
+		writeError(w, r, err)
 		return
 	}
 
@@ -159,120 +116,69 @@ func (h *PlaylistHandler) GetPlaylistTracks(w http.ResponseWriter, r *http.Reque
 
 // AddTrackToPlaylist добавляет трек в плейлист
 func (h *PlaylistHandler) AddTrackToPlaylist(w http.ResponseWriter, r *http.Request) {
-	playlistID, err := uuid.Parse(mux.Vars(r)["id"])
+	playlistID, err := pathUUID(r, "id")
 	if err != nil {
-		http.Error(w, "Неверный ID плейлиста", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	var request struct {
-		TrackID uuid.UUID `json:"track_id"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Неверный формат запроса", http.StatusBadRequest)
+	var request dto.TrackRefRequest
+	if err := decodeJSON(r, &request); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	userID, err := getUserIDFromSession(r)
-	if err != nil {
-		http.Error(w, "Необходима авторизация", http.StatusUnauthorized)
+	if _, err := getUserIDFromSession(r); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	playlist, err := h.playlistUseCase.GetPlaylistWithTracks(playlistID)
-	if err != nil {
-		if errors.Is(err, models.ErrNotFound) {
-			http.Error(w, "Плейлист не найден", http.StatusNotFound)
-		} else {
-			log.Printf("Ошибка при получении плейлиста: %v", err)
-			http.Error(w, "Ошибка при получении плейлиста", http.StatusInternalServerError)
-		}
+	if err := h.playlistUseCase.AddTrackToPlaylist(r.Context(), playlistID, dto.UUID(request.TrackID)); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if playlist.Playlist.UserID != userID {
-		http.Error(w, "Нет доступа к редактированию плейлиста", http.StatusForbidden)
-		return
-	}
-
-	if err := h.playlistUseCase.AddTrackToPlaylist(playlistID, request.TrackID); err != nil {
-		if errors.Is(err, models.ErrNotFound) {
-			http.Error(w, "Трек не найден", http.StatusNotFound)
-		} else {
-			log.Printf("Ошибка при добавлении трека: %v", err)
-			http.Error(w, "Ошибка при добавлении трека", http.StatusInternalServerError)
-		}
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message": "Трек добавлен в плейлист"}`))
+	writeMessage(w, r, "playlist_track_added")
 }
 
 // RemoveTrackFromPlaylist удаляет трек из плейлиста
 func (h *PlaylistHandler) RemoveTrackFromPlaylist(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	playlistID, err := uuid.Parse(vars["playlistId"])
-	if err != nil {
-		http.Error(w, "Неверный ID плейлиста", http.StatusBadRequest)
-		return
-	}
-
-	trackID, err := uuid.Parse(vars["trackId"])
+	playlistID, err := pathUUID(r, "playlistId")
 	if err != nil {
-		http.Error(w, "Неверный ID трека", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	userID, err := getUserIDFromSession(r)
-	if err != nil {
-		http.Error(w, "Необходима авторизация", http.StatusUnauthorized)
-		return
-	}
-
-	playlist, err := h.playlistUseCase.GetPlaylistWithTracks(playlistID)
+	trackID, err := pathUUID(r, "trackId")
 	if err != nil {
-		if errors.Is(err, models.ErrNotFound) {
-			http.Error(w, "Плейлист не найден", http.StatusNotFound)
-		} else {
-			log.Printf("Ошибка при получении плейлиста: %v", err)
-			http.Error(w, "Ошибка при получении плейлиста", http.StatusInternalServerError)
-		}
+		writeError(w, r, err)
 		return
 	}
 
-	if playlist.Playlist.UserID != userID {
-		http.Error(w, "Нет доступа к редактированию плейлиста", http.StatusForbidden)
+	if _, err := getUserIDFromSession(r); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.playlistUseCase.RemoveTrackFromPlaylist(playlistID, trackID); err != nil {
-		if errors.Is(err, models.ErrNotFound) {
-			http.Error(w, "Трек не найден в плейлисте", http.StatusNotFound)
-		} else {
-			log.Printf("Ошибка при удалении трека: %v", err)
-			http.Error(w, "Ошибка при удалении трека", http.StatusInternalServerError)
-		}
+	if err := h.playlistUseCase.RemoveTrackFromPlaylist(r.Context(), playlistID, trackID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message": "Трек удален из плейлиста"}`))
+	writeMessage(w, r, "playlist_track_removed")
 }
 
 // GetUserPlaylists возвращает список плейлистов пользователя
 func (h *PlaylistHandler) GetUserPlaylists(w http.ResponseWriter, r *http.Request) {
 	userID, err := getUserIDFromSession(r)
 	if err != nil {
-		http.Error(w, "Необходима авторизация", http.StatusUnauthorized)
+		writeError(w, r, err)
 		return
 	}
 
-	playlists, err := h.playlistUseCase.GetUserPlaylists(userID)
+	playlists, err := h.playlistUseCase.GetUserPlaylists(r.Context(), userID)
 	if err != nil {
-		log.Printf("Ошибка при получении плейлистов пользователя: %v", err)
-		http.Error(w, "Ошибка при получении плейлистов", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -282,56 +188,38 @@ func (h *PlaylistHandler) GetUserPlaylists(w http.ResponseWriter, r *http.Reques
 
 // DeletePlaylist удаляет плейлист
 func (h *PlaylistHandler) DeletePlaylist(w http.ResponseWriter, r *http.Request) {
-	playlistID, err := uuid.Parse(mux.Vars(r)["id"])
+	playlistID, err := pathUUID(r, "id")
 	if err != nil {
-		http.Error(w, "Неверный ID плейлиста", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
 	userID, err := getUserIDFromSession(r)
 	if err != nil {
-		http.Error(w, "Необходима авторизация", http.StatusUnauthorized)
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.playlistUseCase.DeletePlaylist(playlistID, userID); err != nil {
-		if errors.Is(err, models.ErrNotFound) {
-			http.Error(w, "Плейлист не найден", http.StatusNotFound)
-		} else if err.Error() == "user is not the owner of the playlist" {
-			http.Error(w, "Нет доступа к удалению плейлиста", http.StatusForbidden)
-		} else {
-			log.Printf("Ошибка при удалении плейлиста: %v", err)
-			http.Error(w, "Ошибка при удалении плейлиста", http.StatusInternalServerError)
-		}
+	if err := h.playlistUseCase.DeletePlaylist(r.Context(), playlistID, userID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message": "Плейлист успешно удален"}`))
+	writeMessage(w, r, "playlist_deleted")
 }
 
-// getUserIDFromSession извлекает ID пользователя из сессии
+// getUserIDFromSession возвращает ID пользователя, определенного AuthMiddleware
 func getUserIDFromSession(r *http.Request) (uuid.UUID, error) {
-	fmt.Printf("Попытка получить ID пользователя из контекста запроса\n")
-
-	// Проверяем наличие заголовка X-User-ID (для совместимости)
-	userIDHeader := r.Header.Get("X-User-ID")
-	if userIDHeader != "" {
-		fmt.Printf("Найден заголовок X-User-ID: %s\n", userIDHeader)
-		userID, err := uuid.Parse(userIDHeader)
-		if err == nil {
-			return userID, nil
-		}
-		fmt.Printf("Ошибка при парсинге ID из заголовка: %v\n", err)
-	}
-
-	// Пытаемся получить ID из контекста
-	userID, ok := r.Context().Value("userID").(uuid.UUID)
-	if !ok {
-		fmt.Printf("ID пользователя не найден в контексте запроса\n")
-		return uuid.Nil, errors.New("user ID not found in session")
+	principal, err := authz.Current(r.Context())
+	if err != nil {
+		return uuid.Nil, err
 	}
+	return principal.UserID, nil
+}
 
-	fmt.Printf("Успешно получен ID пользователя из контекста: %s\n", userID.String())
-	return userID, nil
+// writeMessage отвечает сообщением об успешном действии на языке запроса
+func writeMessage(w http.ResponseWriter, r *http.Request, code string) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"message": i18n.Message(i18n.FromContext(r.Context()), code),
+	})
 }
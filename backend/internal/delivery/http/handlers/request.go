@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/validation"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// decodeJSON читает тело запроса в dst и проверяет его правилами validate.
+// Поле неподходящего типа возвращается как нарушение invalid_type, любая
+// другая ошибка разбора — как errInvalidBody
+func decodeJSON(r *http.Request, dst interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return validation.Field(typeErr.Field, validation.CodeInvalidType)
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return err
+		}
+		return errInvalidBody
+	}
+	return validation.Struct(dst)
+}
+
+// decodeValues заполняет dst параметрами query string или формы и
+// проверяет его правилами validate
+func decodeValues(values url.Values, dst interface{}) error {
+	if err := dto.Bind(values, dst); err != nil {
+		return err
+	}
+	return validation.Struct(dst)
+}
+
+// decodeQuery заполняет dst параметрами query string и проверяет его
+func decodeQuery(r *http.Request, dst interface{}) error {
+	return decodeValues(r.URL.Query(), dst)
+}
+
+// pathUUID возвращает параметр пути name как UUID
+func pathUUID(r *http.Request, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(mux.Vars(r)[name])
+	if err != nil {
+		return uuid.Nil, validation.Field(name, validation.CodeInvalidUUID)
+	}
+	return id, nil
+}
+
+// pathInt возвращает параметр пути name как целое число
+func pathInt(r *http.Request, name string) (int, error) {
+	n, err := strconv.Atoi(mux.Vars(r)[name])
+	if err != nil {
+		return 0, validation.Field(name, validation.CodeInvalidNumber)
+	}
+	return n, nil
+}
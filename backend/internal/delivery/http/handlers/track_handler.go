@@ -2,18 +2,19 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"math"
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/logging"
 	"music-service/internal/models"
 	"music-service/internal/usecases/interfaces"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
-
-	"github.com/google/uuid"
-	"github.com/gorilla/mux"
+	"time"
 )
 
 const (
@@ -38,95 +39,74 @@ func NewTrackHandler(trackUseCase interfaces.TrackUseCase, allowedTypes []string
 
 // Обработчик для загрузки аудиофайла
 func (h *TrackHandler) UploadTrack(w http.ResponseWriter, r *http.Request) {
-	fmt.Printf("Заголовки запроса: %+v\n", r.Header)
-	fmt.Printf("X-User-Permission: %s\n", r.Header.Get("X-User-Permission"))
-	fmt.Printf("Authorization: %s\n", r.Header.Get("Authorization"))
-
-	if !h.isAdmin(r) {
-		fmt.Printf("Доступ запрещен: X-User-Permission=%s\n", r.Header.Get("X-User-Permission"))
-		http.Error(w, "Доступ запрещен: требуются права администратора", http.StatusForbidden)
-		return
-	}
-
 	r.Body = http.MaxBytesReader(w, r.Body, int64(h.maxFileSizeMB<<20))
 
 	if err := r.ParseMultipartForm(maxMemory); err != nil {
-		http.Error(w, "Невозможно обработать загруженный файл: "+err.Error(), http.StatusBadRequest)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, r, err)
+			return
+		}
+		writeError(w, r, errInvalidBody)
 		return
 	}
 
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		http.Error(w, "Не удалось получить файл из запроса: "+err.Error(), http.StatusBadRequest)
+		writeError(w, r, errFileRequired)
 		return
 	}
 	defer file.Close()
 
 	contentType := header.Header.Get("Content-Type")
-	fmt.Printf("Тип файла: %s\n", contentType)
-
-	fmt.Printf("Разрешенные типы файлов: %v\n", h.allowedTypes)
-
 	if !h.isAllowedFileType(contentType) {
-		http.Error(w, "Недопустимый тип файла. Разрешены только MP3 файлы", http.StatusBadRequest)
+		writeError(w, r, errUnsupportedFile)
 		return
 	}
 
 	metadata, err := h.getTrackMetadataFromForm(r)
 	if err != nil {
-		http.Error(w, "Ошибка в метаданных трека: "+err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	fmt.Printf("Метаданные трека перед загрузкой: Title=%s, ArtistName=%s, AlbumID=%s\n",
-		metadata.Title, metadata.ArtistName, metadata.AlbumID)
-
-	track, err := h.trackUseCase.UploadTrack(file, header.Size, metadata)
+	track, duplicates, err := h.trackUseCase.UploadTrack(r.Context(), file, header.Size, metadata)
 	if err != nil {
-		http.Error(w, "Ошибка при загрузке трека: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	fmt.Printf("Трек создан: ID=%s, Title=%s, AlbumID=%s\n",
-		track.ID, track.Title, track.AlbumID)
+	logging.FromContext(r.Context()).Info("track uploaded",
+		"track_id", track.ID, "album_id", track.AlbumID, "content_type", contentType, "size", header.Size,
+		"duplicates", len(duplicates))
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(track)
+	json.NewEncoder(w).Encode(uploadTrackResponse{Track: track, Duplicates: duplicates})
+}
+
+// uploadTrackResponse — загруженный трек и похожие на него треки каталога:
+// предупреждение о возможном дубликате
+type uploadTrackResponse struct {
+	*models.Track
+	Duplicates []*models.DuplicateMatch `json:"duplicates,omitempty"`
 }
 
 // Получение метаданных трека из формы
 func (h *TrackHandler) getTrackMetadataFromForm(r *http.Request) (models.TrackUploadMetadata, error) {
-	metadata := models.TrackUploadMetadata{
-		Title:      r.FormValue("title"),
-		ArtistName: r.FormValue("artist_name"),
-		CoverURL:   r.FormValue("cover_url"),
+	var form dto.UploadTrackForm
+	if err := decodeValues(r.Form, &form); err != nil {
+		return models.TrackUploadMetadata{}, err
 	}
 
-	// Обрабатываем длительность если она указана
-	if durationStr := r.FormValue("duration"); durationStr != "" {
-		duration, err := strconv.Atoi(durationStr)
-		if err != nil {
-			return metadata, err
-		}
-		metadata.Duration = duration
-	}
-
-	// Обрабатываем ID альбома если он указан
-	albumIDStr := r.FormValue("album_id")
-	fmt.Printf("Значение album_id из формы: %s\n", albumIDStr)
-
-	if albumIDStr != "" {
-		albumID, err := uuid.Parse(albumIDStr)
-		if err != nil {
-			fmt.Printf("Ошибка при парсинге album_id: %v\n", err)
-			return metadata, err
-		}
-		metadata.AlbumID = albumID
-		fmt.Printf("Установлен AlbumID: %s\n", albumID)
-	}
-
-	return metadata, nil
+	return models.TrackUploadMetadata{
+		Title:          form.Title,
+		ArtistName:     form.ArtistName,
+		AlbumID:        dto.UUID(form.AlbumID),
+		Duration:       form.Duration,
+		CoverURL:       form.CoverURL,
+		AllowDuplicate: form.AllowDuplicate,
+	}, nil
 }
 
 // Проверка типа файла
@@ -139,44 +119,89 @@ func (h *TrackHandler) isAllowedFileType(contentType string) bool {
 	return false
 }
 
-// Проверка прав администратора
-func (h *TrackHandler) isAdmin(r *http.Request) bool {
-	authHeader := r.Header.Get("Authorization")
+// GetTrackDetails получает детальную информацию о треке. ETag ответа
+// передается в If-Match при изменении трека
+func (h *TrackHandler) GetTrackDetails(w http.ResponseWriter, r *http.Request) {
+	trackID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-	if authHeader == "Bearer 33333333-3333-3333-3333-333333333333" {
-		fmt.Println("Прямое разрешение по токену админа")
-		return true
+	trackDetails, err := h.trackUseCase.GetTrackDetails(r.Context(), trackID)
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
 
-	permission := r.Header.Get("X-User-Permission")
-	isAdmin := permission == string(models.AdminPermission)
-	fmt.Printf("Проверка прав админа: X-User-Permission=%s, isAdmin=%v\n", permission, isAdmin)
-	return isAdmin
+	if r.Header.Get("If-None-Match") == etag(trackDetails.UpdatedAt) {
+		w.Header().Set("ETag", etag(trackDetails.UpdatedAt))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeTrackDetails(w, trackDetails)
 }
 
-// GetTrackDetails получает детальную информацию о треке
-func (h *TrackHandler) GetTrackDetails(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	trackID, err := uuid.Parse(vars["id"])
+// UpdateTrack изменяет метаданные трека по JSON Merge Patch. Если передан
+// If-Match, изменение применяется только к той версии трека, которую видел
+// клиент, иначе возвращается 412
+func (h *TrackHandler) UpdateTrack(w http.ResponseWriter, r *http.Request) {
+	trackID, err := pathUUID(r, "id")
 	if err != nil {
-		http.Error(w, "Недопустимый идентификатор трека", http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+
+	var request dto.TrackPatchRequest
+	if err := decodeJSON(r, &request); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	trackDetails, err := h.trackUseCase.GetTrackDetails(trackID)
+	update := request.Update()
+	if ifMatch := strings.TrimSpace(r.Header.Get("If-Match")); ifMatch != "" && ifMatch != "*" {
+		version, ok := parseETag(ifMatch)
+		if !ok {
+			writeError(w, r, models.ErrTrackModified)
+			return
+		}
+		update.Version = &version
+	}
+
+	if err := h.trackUseCase.UpdateTrackMetadata(r.Context(), trackID, update); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	trackDetails, err := h.trackUseCase.GetTrackDetails(r.Context(), trackID)
 	if err != nil {
-		http.Error(w, "Трек не найден", http.StatusNotFound)
+		writeError(w, r, err)
 		return
 	}
+	writeTrackDetails(w, trackDetails)
+}
 
+// writeTrackDetails отвечает карточкой трека с ETag его текущей версии
+func writeTrackDetails(w http.ResponseWriter, trackDetails *models.TrackDetails) {
 	response := map[string]interface{}{
-		"id":          trackDetails.ID.String(),
-		"title":       trackDetails.Title,
-		"artist_name": trackDetails.ArtistName,
-		"duration":    trackDetails.Duration,
-		"cover_url":   trackDetails.CoverURL,
-		"added_date":  trackDetails.AddedDate,
-		"play_count":  trackDetails.PlayCount,
+		"id":           trackDetails.ID.String(),
+		"title":        trackDetails.Title,
+		"artist_name":  trackDetails.ArtistName,
+		"artists":      trackDetails.Artists,
+		"duration":     trackDetails.Duration,
+		"cover_url":    trackDetails.CoverURL,
+		"explicit":     trackDetails.Explicit,
+		"track_number": nullableInt(trackDetails.TrackNumber),
+		"disc_number":  nullableInt(trackDetails.DiscNumber),
+		"isrc":         nullableString(trackDetails.ISRC),
+		"lyrics_url":   nullableString(trackDetails.LyricsURL),
+		"release_date": nil,
+		"added_date":   trackDetails.AddedDate,
+		"updated_at":   trackDetails.UpdatedAt,
+		"play_count":   trackDetails.PlayCount,
+	}
+	if trackDetails.ReleaseDate != nil {
+		response["release_date"] = trackDetails.ReleaseDate.Format(time.DateOnly)
 	}
 
 	if trackDetails.Album != nil {
@@ -194,34 +219,123 @@ func (h *TrackHandler) GetTrackDetails(w http.ResponseWriter, r *http.Request) {
 	}
 	response["genres"] = genres
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	response["lyrics"] = nil
+	if trackDetails.Lyrics != nil {
+		response["lyrics"] = map[string]interface{}{
+			"synced":   trackDetails.Lyrics.Synced,
+			"language": nullableString(trackDetails.Lyrics.Language),
+			"url":      "/api/v1/tracks/" + trackDetails.ID.String() + "/lyrics",
+		}
+	}
+
+	var albumLoudness *models.Loudness
+	if trackDetails.Album != nil {
+		albumLoudness = trackDetails.Album.Loudness
+	}
+	response["loudness"] = loudnessResponse(trackDetails.Loudness, albumLoudness)
+
+	w.Header().Set("ETag", etag(trackDetails.UpdatedAt))
+	writeJSON(w, http.StatusOK, response)
 }
 
-// ServeTrackFile отдает аудиофайл для воспроизведения
-func (h *TrackHandler) ServeTrackFile(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	trackID, err := uuid.Parse(vars["id"])
+// AnalyzeLoudness заново измеряет громкость трека
+func (h *TrackHandler) AnalyzeLoudness(w http.ResponseWriter, r *http.Request) {
+	trackID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	measured, err := h.trackUseCase.AnalyzeLoudness(r.Context(), trackID)
 	if err != nil {
-		http.Error(w, "Некорректный ID трека", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
+	writeJSON(w, http.StatusOK, loudnessResponse(measured, nil))
+}
+
+// FingerprintTrack заново строит акустический отпечаток трека и
+// возвращает похожие на него треки каталога
+func (h *TrackHandler) FingerprintTrack(w http.ResponseWriter, r *http.Request) {
+	trackID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	duplicates, err := h.trackUseCase.FingerprintTrack(r.Context(), trackID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if duplicates == nil {
+		duplicates = []*models.DuplicateMatch{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"duplicates": duplicates})
+}
+
+// loudnessResponse — громкость и усиление ReplayGain для плеера или nil,
+// если трек не измерен. Поля альбома — nil, пока не измерен альбом
+func loudnessResponse(track, album *models.Loudness) interface{} {
+	if track == nil {
+		return nil
+	}
+	response := map[string]interface{}{
+		"integrated_lufs": roundTo(track.IntegratedLUFS, 2),
+		"true_peak_dbtp":  roundTo(track.TruePeakDBTP, 2),
+		"track_gain_db":   roundTo(track.Gain(), 2),
+		"track_peak":      roundTo(track.Peak(), 6),
+		"album_gain_db":   nil,
+		"album_peak":      nil,
+		"reference_lufs":  models.ReplayGainReference,
+	}
+	if album != nil {
+		response["album_gain_db"] = roundTo(album.Gain(), 2)
+		response["album_peak"] = roundTo(album.Peak(), 6)
+	}
+	return response
+}
+
+// roundTo округляет value до digits знаков после запятой
+func roundTo(value float64, digits int) float64 {
+	scale := math.Pow(10, float64(digits))
+	return math.Round(value*scale) / scale
+}
+
+// nullableInt возвращает nil для незаполненного числового поля
+func nullableInt(value int) interface{} {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
 
-	log.Printf("Запрос на стриминг трека: %s", trackID)
+// nullableString возвращает nil для незаполненного строкового поля
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
 
-	filePath, err := h.trackUseCase.GetTrackFilePath(trackID)
+// ServeTrackFile отдает аудиофайл для воспроизведения
+func (h *TrackHandler) ServeTrackFile(w http.ResponseWriter, r *http.Request) {
+	trackID, err := pathUUID(r, "id")
 	if err != nil {
-		log.Printf("Ошибка при получении пути к файлу: %v", err)
-		http.Error(w, "Ошибка при получении файла", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	log.Printf("Путь к файлу трека: %s", filePath)
+	filePath, err := h.trackUseCase.GetTrackFilePath(r.Context(), trackID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
 	file, err := os.Open(filePath)
 	if err != nil {
-		log.Printf("Ошибка при открытии файла: %v", err)
-		http.Error(w, "Файл не найден", http.StatusNotFound)
+		logging.FromContext(r.Context()).Warn("open track file failed", "track_id", trackID, "error", err)
+		writeError(w, r, models.ErrTrackNotFound)
 		return
 	}
 	defer file.Close()
@@ -229,8 +343,7 @@ func (h *TrackHandler) ServeTrackFile(w http.ResponseWriter, r *http.Request) {
 	// Получаем информацию о файле
 	fileInfo, err := file.Stat()
 	if err != nil {
-		log.Printf("Ошибка при получении информации о файле: %v", err)
-		http.Error(w, "Ошибка при чтении файла", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -239,42 +352,34 @@ func (h *TrackHandler) ServeTrackFile(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Length", fmt.Sprintf("%d", fileInfo.Size()))
 	w.Header().Set("Accept-Ranges", "bytes")
 
-	// Отправляем файл
+	// Отправляем файл. Заголовки уже отправлены, поэтому ответить ошибкой
+	// нельзя — только записать ее в журнал
 	if _, err := io.Copy(w, file); err != nil {
-		log.Printf("Ошибка при отправке файла: %v", err)
-		http.Error(w, "Ошибка при отправке файла", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Warn("send track file failed", "track_id", trackID, "error", err)
 		return
 	}
 
 	// Записываем прослушивание в историю только для авторизованных пользователей
-	userIDStr := r.Header.Get("X-User-ID")
-	if userIDStr != "" {
-		userID, err := uuid.Parse(userIDStr)
+	if principal, ok := authz.FromContext(r.Context()); ok {
+		userID := principal.UserID
+		err = h.historyUseCase.RecordPlayback(r.Context(), userID, trackID)
 		if err != nil {
-			log.Printf("Ошибка при парсинге ID пользователя: %v", err)
-			return
+			logging.FromContext(r.Context()).Error("record playback failed", "track_id", trackID, "error", err)
 		}
-
-		err = h.historyUseCase.RecordPlayback(userID, trackID)
-		if err != nil {
-			log.Printf("Ошибка при записи истории прослушивания: %v", err)
-			return
-		}
-		log.Printf("История прослушивания записана для пользователя %s и трека %s", userID, trackID)
 	}
 }
 
 // SearchTracks выполняет поиск треков
 func (h *TrackHandler) SearchTracks(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		http.Error(w, "Поисковый запрос не указан", http.StatusBadRequest)
+	var query dto.SearchTracksQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	tracks, err := h.trackUseCase.SearchTracks(query)
+	tracks, err := h.trackUseCase.SearchTracks(r.Context(), query.Query)
 	if err != nil {
-		http.Error(w, "Ошибка при поиске треков: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -284,21 +389,14 @@ func (h *TrackHandler) SearchTracks(w http.ResponseWriter, r *http.Request) {
 
 // DeleteTrack удаляет трек
 func (h *TrackHandler) DeleteTrack(w http.ResponseWriter, r *http.Request) {
-	// Проверяем права администратора
-	if !h.isAdmin(r) {
-		http.Error(w, "Доступ запрещен: требуются права администратора", http.StatusForbidden)
-		return
-	}
-
-	vars := mux.Vars(r)
-	trackID, err := uuid.Parse(vars["id"])
+	trackID, err := pathUUID(r, "id")
 	if err != nil {
-		http.Error(w, "Недопустимый идентификатор трека", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.trackUseCase.DeleteTrack(trackID); err != nil {
-		http.Error(w, "Ошибка при удалении трека: "+err.Error(), http.StatusInternalServerError)
+	if err := h.trackUseCase.DeleteTrack(r.Context(), trackID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
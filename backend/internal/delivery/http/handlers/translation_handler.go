@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/models"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// translationTypes сопоставляет сегмент пути типу переводимого объекта
+var translationTypes = map[string]string{
+	"tracks": models.TranslatableTrack,
+	"albums": models.TranslatableAlbum,
+	"genres": models.TranslatableGenre,
+}
+
+type TranslationHandler struct {
+	translationUseCase interfaces.TranslationUseCase
+}
+
+func NewTranslationHandler(translationUseCase interfaces.TranslationUseCase) *TranslationHandler {
+	return &TranslationHandler{
+		translationUseCase: translationUseCase,
+	}
+}
+
+// ListTranslations возвращает все переводы трека, альбома или жанра
+func (h *TranslationHandler) ListTranslations(w http.ResponseWriter, r *http.Request) {
+	entityType, id, ok := parseTranslationTarget(w, r)
+	if !ok {
+		return
+	}
+
+	translations, err := h.translationUseCase.ListTranslations(r.Context(), entityType, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if translations == nil {
+		translations = []*models.Translation{}
+	}
+	writeJSON(w, http.StatusOK, translations)
+}
+
+// SetTranslation заменяет перевод на язык из пути. Поля, которых нет в
+// теле запроса, берутся из оригинала
+func (h *TranslationHandler) SetTranslation(w http.ResponseWriter, r *http.Request) {
+	entityType, id, ok := parseTranslationTarget(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.SetTranslationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	translation, err := h.translationUseCase.SetTranslation(r.Context(), entityType, id, mux.Vars(r)["locale"], req.Fields)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, translation)
+}
+
+// DeleteTranslation удаляет перевод на язык из пути
+func (h *TranslationHandler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	entityType, id, ok := parseTranslationTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.translationUseCase.DeleteTranslation(r.Context(), entityType, id, mux.Vars(r)["locale"]); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseTranslationTarget определяет тип и ID объекта по пути запроса.
+// При ошибке ответ уже отправлен
+func parseTranslationTarget(w http.ResponseWriter, r *http.Request) (string, uuid.UUID, bool) {
+	vars := mux.Vars(r)
+	entityType, ok := translationTypes[vars["type"]]
+	if !ok {
+		writeError(w, r, models.ErrInvalidTranslationType)
+		return "", uuid.Nil, false
+	}
+
+	id, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return "", uuid.Nil, false
+	}
+	return entityType, id, true
+}
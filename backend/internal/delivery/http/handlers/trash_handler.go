@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/models"
+	"music-service/internal/usecases/interfaces"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type TrashHandler struct {
+	trashUseCase interfaces.TrashUseCase
+}
+
+func NewTrashHandler(trashUseCase interfaces.TrashUseCase) *TrashHandler {
+	return &TrashHandler{
+		trashUseCase: trashUseCase,
+	}
+}
+
+// ListTrash возвращает все удаленные объекты. Параметры: type (track,
+// album, playlist, user), limit, offset
+func (h *TrashHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	var query dto.TrashQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := h.trashUseCase.ListTrash(r.Context(), models.TrashFilter{
+		Type:   query.Type,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeTrashPage(w, page)
+}
+
+// ListOwnTrash возвращает удаленные плейлисты текущего пользователя
+func (h *TrashHandler) ListOwnTrash(w http.ResponseWriter, r *http.Request) {
+	var query dto.PageQuery
+	if err := decodeQuery(r, &query); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := h.trashUseCase.ListOwnTrash(r.Context(), query.Limit, query.Offset)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeTrashPage(w, page)
+}
+
+// Restore восстанавливает объект любого типа из корзины
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	h.restore(w, r, mux.Vars(r)["type"])
+}
+
+// RestorePlaylist восстанавливает собственный плейлист из корзины
+func (h *TrashHandler) RestorePlaylist(w http.ResponseWriter, r *http.Request) {
+	h.restore(w, r, models.TrashPlaylist)
+}
+
+func (h *TrashHandler) restore(w http.ResponseWriter, r *http.Request, itemType string) {
+	id, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.trashUseCase.Restore(r.Context(), itemType, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeTrashPage(w http.ResponseWriter, page *models.TrashPage) {
+	items := page.Items
+	if items == nil {
+		items = []*models.TrashItem{}
+	}
+	writeJSON(w, http.StatusOK, pageResponse{Items: items, Total: page.Total, Limit: page.Limit, Offset: page.Offset})
+}
@@ -2,13 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/dto"
+	"music-service/internal/delivery/http/middleware"
+	"music-service/internal/mfa"
 	"music-service/internal/models"
 	"music-service/internal/usecases/interfaces"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/gorilla/mux"
 )
 
 type UserHandler struct {
@@ -21,21 +25,12 @@ func NewUserHandler(userUseCase interfaces.UserUseCase) *UserHandler {
 	}
 }
 
-// Проверка прав администратора
-func (h *UserHandler) isAdmin(r *http.Request) bool {
-	permission := r.Header.Get("X-User-Permission")
-	return permission == string(models.AdminPermission)
-}
-
-type registerRequest struct {
-	Login    string `json:"login"`
-	Password string `json:"password"`
-}
-
 type UserResponse struct {
 	ID         string    `json:"id"`
 	Login      string    `json:"login"`
+	Email      string    `json:"email,omitempty"`
 	Permission string    `json:"permission"`
+	Locale     string    `json:"locale,omitempty"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
@@ -44,57 +39,62 @@ func toUserResponse(user *models.User) UserResponse {
 	return UserResponse{
 		ID:         user.ID.String(),
 		Login:      user.Login,
+		Email:      user.Email,
 		Permission: string(user.Permission),
+		Locale:     user.Locale,
 		CreatedAt:  user.CreatedAt,
 		UpdatedAt:  user.UpdatedAt,
 	}
 }
 
-type authRequest struct {
-	Login    string `json:"login"`
-	Password string `json:"password"`
-}
-
 type authResponse struct {
 	User    UserResponse    `json:"user"`
 	Session *models.Session `json:"session"`
+	*models.TokenPair
 }
 
-type updatePermissionsRequest struct {
-	Permission string `json:"permission"`
+// clientInfo собирает сведения о клиенте для метаданных сессии
+func clientInfo(r *http.Request) models.ClientInfo {
+	return models.ClientInfo{
+		UserAgent: r.UserAgent(),
+		IPAddress: middleware.ClientIP(r),
+	}
 }
 
-type errorResponse struct {
-	Error string `json:"error"`
+type sessionResponse struct {
+	ID         uuid.UUID     `json:"id"`
+	Device     models.Device `json:"device"`
+	UserAgent  string        `json:"user_agent"`
+	IPAddress  string        `json:"ip_address"`
+	CreatedAt  time.Time     `json:"created_at"`
+	LastSeenAt time.Time     `json:"last_seen_at"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	Current    bool          `json:"current"`
 }
 
+const (
+	accessTokenCookie  = "session_token"
+	refreshTokenCookie = "refresh_token"
+	// refresh-токен нужен только эндпоинтам пользователей
+	refreshTokenCookiePath = "/api/v1/users"
+)
+
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(v)
 }
 
-func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, errorResponse{Error: message})
-}
-
 func (h *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
-	var req registerRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Некорректные данные запроса")
+	var req dto.RegisterRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	user, err := h.userUseCase.Register(req.Login, req.Password)
+	user, err := h.userUseCase.Register(r.Context(), req.Login, req.Password, req.Email)
 	if err != nil {
-		switch err.Error() {
-		case "user already exists":
-			writeError(w, http.StatusBadRequest, "Пользователь уже существует")
-		case "login must be at least 6 characters", "password must be at least 8 characters":
-			writeError(w, http.StatusBadRequest, err.Error())
-		default:
-			writeError(w, http.StatusInternalServerError, "Ошибка сервера")
-		}
+		writeError(w, r, err)
 		return
 	}
 
@@ -102,82 +102,167 @@ func (h *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) AuthenticateUser(w http.ResponseWriter, r *http.Request) {
-	var req authRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Некорректные данные запроса")
+	var req dto.AuthRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, session, tokens, err := h.userUseCase.Authenticate(r.Context(), req.Login, req.Password, req.Device(r.UserAgent()), clientInfo(r))
+	if err != nil {
+		// Пароль верен, но нужен второй фактор: сессия будет создана после него
+		var challenge *mfa.ChallengeRequired
+		if errors.As(err, &challenge) {
+			writeMFAChallenge(w, challenge)
+			return
+		}
+		writeError(w, r, err)
+		return
+	}
+
+	setTokenCookies(w, tokens)
+
+	writeJSON(w, http.StatusOK, authResponse{
+		User:      toUserResponse(user),
+		Session:   session,
+		TokenPair: tokens,
+	})
+}
+
+// RefreshTokens выдает новую пару токенов. Refresh-токен берется из тела
+// запроса, а при его отсутствии — из куки
+func (h *UserHandler) RefreshTokens(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefreshRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	if req.RefreshToken == "" {
+		if cookie, err := r.Cookie(refreshTokenCookie); err == nil {
+			req.RefreshToken = cookie.Value
+		}
+	}
+	if req.RefreshToken == "" {
+		writeError(w, r, models.ErrInvalidRefreshToken)
 		return
 	}
 
-	user, session, err := h.userUseCase.Authenticate(req.Login, req.Password)
+	tokens, err := h.userUseCase.Refresh(r.Context(), req.RefreshToken, clientInfo(r))
 	if err != nil {
-		writeError(w, http.StatusUnauthorized, "Неверные учетные данные")
+		// Токен больше не действует: куки с ним бесполезны
+		var suspended *models.SuspendedError
+		if errors.As(err, &suspended) || errors.Is(err, models.ErrInvalidRefreshToken) || errors.Is(err, models.ErrRefreshTokenReused) {
+			clearTokenCookies(w)
+		}
+		writeError(w, r, err)
 		return
 	}
 
+	setTokenCookies(w, tokens)
+	writeJSON(w, http.StatusOK, tokens)
+}
+
+// setTokenCookies дублирует токены в куки: access-токен нужен EventSource,
+// который не умеет передавать заголовки
+func setTokenCookies(w http.ResponseWriter, tokens *models.TokenPair) {
 	http.SetCookie(w, &http.Cookie{
-		Name:     "session_token",
-		Value:    session.Token,
+		Name:     accessTokenCookie,
+		Value:    tokens.AccessToken,
 		Path:     "/",
 		HttpOnly: true,
 		Secure:   false,
 		SameSite: http.SameSiteLaxMode,
-		Expires:  session.ExpiresAt,
+		MaxAge:   tokens.ExpiresIn,
 	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    tokens.RefreshToken,
+		Path:     refreshTokenCookiePath,
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteStrictMode,
+		Expires:  tokens.RefreshExpiresAt,
+	})
+}
 
-	writeJSON(w, http.StatusOK, authResponse{
-		User:    toUserResponse(user),
-		Session: session,
+func clearTokenCookies(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     accessTokenCookie,
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   -1,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    "",
+		Path:     refreshTokenCookiePath,
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1,
 	})
 }
 
 func (h *UserHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID, err := uuid.Parse(vars["id"])
+	userID, err := pathUUID(r, "id")
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Неверный формат ID")
+		writeError(w, r, err)
 		return
 	}
 
-	user, err := h.userUseCase.GetUserProfile(userID)
+	user, err := h.userUseCase.GetUserProfile(r.Context(), userID)
 	if err != nil {
-		writeError(w, http.StatusNotFound, "Пользователь не найден")
+		writeError(w, r, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, toUserResponse(user))
 }
 
-func (h *UserHandler) UpdateUserPermissions(w http.ResponseWriter, r *http.Request) {
-	if !h.isAdmin(r) {
-		writeError(w, http.StatusForbidden, "Доступ запрещен: требуются права администратора")
+// SetLocale сохраняет язык интерфейса текущего пользователя. Пустой locale
+// возвращает выбор языка по Accept-Language
+func (h *UserHandler) SetLocale(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromSession(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req dto.SetLocaleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	vars := mux.Vars(r)
-	userID, err := uuid.Parse(vars["id"])
+	locale, err := h.userUseCase.SetLocale(r.Context(), userID, req.Locale)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Неверный формат ID")
+		writeError(w, r, err)
 		return
 	}
 
-	var req updatePermissionsRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Некорректные данные запроса")
+	writeJSON(w, http.StatusOK, dto.SetLocaleRequest{Locale: locale})
+}
+
+func (h *UserHandler) UpdateUserPermissions(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	permission := models.Permission(req.Permission)
-	if !permission.IsValid() {
-		writeError(w, http.StatusBadRequest, "Недопустимые права доступа")
+	var req dto.UpdatePermissionsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.userUseCase.UpdatePermissions(userID, permission); err != nil {
-		if err.Error() == "user not found" {
-			writeError(w, http.StatusNotFound, "Пользователь не найден")
-			return
-		}
-		writeError(w, http.StatusInternalServerError, "Ошибка сервера")
+	if err := h.userUseCase.UpdatePermissions(r.Context(), userID, models.Permission(req.Permission)); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -185,25 +270,14 @@ func (h *UserHandler) UpdateUserPermissions(w http.ResponseWriter, r *http.Reque
 }
 
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
-	// Проверяем права администратора
-	if !h.isAdmin(r) {
-		writeError(w, http.StatusForbidden, "Доступ запрещен: требуются права администратора")
-		return
-	}
-
-	vars := mux.Vars(r)
-	userID, err := uuid.Parse(vars["id"])
+	userID, err := pathUUID(r, "id")
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Неверный формат ID")
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.userUseCase.DeleteUser(userID); err != nil {
-		if err.Error() == "user not found" {
-			writeError(w, http.StatusNotFound, "Пользователь не найден")
-			return
-		}
-		writeError(w, http.StatusInternalServerError, "Ошибка сервера")
+	if err := h.userUseCase.DeleteUser(r.Context(), userID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -211,33 +285,89 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) LogoutUser(w http.ResponseWriter, r *http.Request) {
-	// Получаем токен из куки
-	cookie, err := r.Cookie("session_token")
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	if err := h.userUseCase.Logout(r.Context(), principal.SessionID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	clearTokenCookies(w)
+	w.WriteHeader(http.StatusOK)
+}
+
+// ListSessions возвращает сессии текущего пользователя; текущая сессия помечается
+func (h *UserHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	sessions, err := h.userUseCase.ListSessions(r.Context(), principal.UserID)
 	if err != nil {
-		writeError(w, http.StatusUnauthorized, "Не авторизован")
+		writeError(w, r, err)
 		return
 	}
 
-	sessionID, err := uuid.Parse(cookie.Value)
+	response := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, sessionResponse{
+			ID:         session.ID,
+			Device:     session.Device,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == principal.SessionID,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *UserHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	sessionID, err := pathUUID(r, "session_id")
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Неверный формат токена")
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.userUseCase.Logout(sessionID); err != nil {
-		writeError(w, http.StatusInternalServerError, "Ошибка при выходе из системы")
+	if err := h.userUseCase.RevokeSession(r.Context(), principal.UserID, sessionID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session_token",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   -1,
-	})
+	if sessionID == principal.SessionID {
+		clearTokenCookies(w)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
 
-	w.WriteHeader(http.StatusOK)
+// RevokeOtherSessions завершает все сессии пользователя, кроме текущей
+func (h *UserHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := authz.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, authz.ErrUnauthenticated)
+		return
+	}
+
+	revoked, err := h.userUseCase.RevokeOtherSessions(r.Context(), principal.UserID, principal.SessionID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{"revoked": revoked})
 }
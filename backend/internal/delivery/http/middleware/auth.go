@@ -2,27 +2,34 @@ package middleware
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/problem"
+	"music-service/internal/i18n"
+	"music-service/internal/logging"
+	"music-service/internal/models"
 	"music-service/internal/usecases/interfaces"
 	"net/http"
-	"os"
 	"strings"
 )
 
 // Список публичных маршрутов, не требующих авторизации
 var publicRoutes = map[string]bool{
-	"/api/v1/users":      true, // Регистрация
-	"/api/v1/users/auth": true, // Аутентификация
-	"/api/v1/tracks":     true, // Поиск треков (GET)
-	"/api/v1/albums":     true, // Список альбомов (GET)
-	"/api/v1/genres":     true, // Список жанров (GET)
+	"/api/v1/users":         true, // Регистрация
+	"/api/v1/users/auth":    true, // Аутентификация
+	"/api/v1/users/refresh": true, // Обновление токенов по refresh-токену
+
+	"/api/v1/users/auth/mfa":        true, // Второй шаг входа
+	"/api/v1/users/auth/mfa/enroll": true, // Подключение TOTP при входе
+
+	"/api/v1/users/password/forgot": true, // Запрос сброса пароля
+	"/api/v1/users/password/reset":  true, // Сброс пароля по токену из письма
+	"/api/v1/tracks":                true, // Поиск треков (GET)
+	"/api/v1/albums":                true, // Список альбомов (GET)
+	"/api/v1/genres":                true, // Список жанров (GET)
 }
 
 // isPublicRoute проверяет, является ли маршрут публичным
 func isPublicRoute(path string, method string) bool {
-	fmt.Fprintf(os.Stderr, "Проверка маршрута: %s %s\n", method, path)
-
 	if publicRoutes[path] {
 		return true
 	}
@@ -47,58 +54,103 @@ func isPublicRoute(path string, method string) bool {
 		if strings.HasPrefix(path, "/api/v1/genres/tracks/") {
 			return true
 		}
+
+		// Вход через OpenID Connect: список провайдеров, переход и обратный вызов
+		if strings.HasPrefix(path, "/api/v1/auth/oidc/") {
+			return true
+		}
 	}
 
 	if method == "OPTIONS" {
 		return true
 	}
 
-	fmt.Fprintf(os.Stderr, "Маршрут не публичный: %s %s\n", method, path)
 	return false
 }
 
-// AuthMiddleware извлекает информацию о пользователе из токена и добавляет ее в заголовки
+// AuthMiddleware проверяет access-токен и кладет пользователя в контекст
+// запроса (authz.Principal). На публичных маршрутах токен необязателен: при
+// его наличии пользователь тоже определяется, например для записи истории.
+// Идентификатор пользователя добавляется к логгеру запроса
 func AuthMiddleware(userUseCase interfaces.UserUseCase) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Заголовки с данными пользователя раньше выставлялись этим
+			// middleware; клиент не должен иметь возможности их подделать
+			r.Header.Del("X-User-ID")
+			r.Header.Del("X-User-Permission")
+			r.Header.Del("X-Device-ID")
+
 			if isPublicRoute(r.URL.Path, r.Method) {
+				if token, err := extractToken(r); err == nil {
+					if principal, err := authenticate(r.Context(), userUseCase, token); err == nil {
+						ctx := i18n.WithPreferred(r.Context(), principal.Locale)
+						setContentLanguage(w, ctx)
+						r = r.WithContext(authz.WithPrincipal(ctx, principal))
+					}
+				}
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			log.Printf("Проверка авторизации для маршрута: %s %s", r.Method, r.URL.Path)
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				log.Printf("Отсутствует заголовок Authorization")
-				http.Error(w, "Необходима авторизация", http.StatusUnauthorized)
-				return
-			}
-
-			log.Printf("Получен заголовок Authorization: %s", authHeader)
-			tokenParts := strings.Split(authHeader, " ")
-			if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-				log.Printf("Неверный формат токена: %v", tokenParts)
-				http.Error(w, "Неверный формат токена авторизации", http.StatusUnauthorized)
+			token, err := extractToken(r)
+			if err != nil {
+				logging.FromContext(r.Context()).Debug("missing access token", "error", err)
+				problem.Write(w, r, err)
 				return
 			}
-			token := tokenParts[1]
-			log.Printf("Проверка токена: %s", token)
 
-			user, err := userUseCase.ValidateSession(token)
+			principal, err := authenticate(r.Context(), userUseCase, token)
 			if err != nil {
-				log.Printf("Ошибка валидации токена: %v", err)
-				http.Error(w, "Недействительный токен авторизации", http.StatusUnauthorized)
+				// Сам токен не пишется; ошибка проверки его тоже не содержит
+				logging.FromContext(r.Context()).Info("invalid access token", "error", err)
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				problem.Write(w, r, models.ErrInvalidAccessToken)
 				return
 			}
 
-			log.Printf("Токен валиден, пользователь: %s (ID: %s)", user.Login, user.ID)
-			r.Header.Set("X-User-ID", user.ID.String())
-			r.Header.Set("X-User-Permission", string(user.Permission))
+			ctx := withPrincipal(r.Context(), principal)
+			setContentLanguage(w, ctx)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func withPrincipal(ctx context.Context, principal *authz.Principal) context.Context {
+	ctx = logging.With(ctx, "user_id", principal.UserID, "session_id", principal.SessionID)
+	ctx = i18n.WithPreferred(ctx, principal.Locale)
+	return authz.WithPrincipal(ctx, principal)
+}
+
+func authenticate(ctx context.Context, userUseCase interfaces.UserUseCase, token string) (*authz.Principal, error) {
+	claims, err := userUseCase.ValidateAccessToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return &authz.Principal{
+		UserID:    claims.Subject,
+		Login:     claims.Login,
+		Role:      claims.Role,
+		SessionID: claims.SessionID,
+		DeviceID:  claims.DeviceID,
+		Locale:    claims.Locale,
+	}, nil
+}
 
-			ctx := context.WithValue(r.Context(), "userID", user.ID)
-			r = r.WithContext(ctx)
+// extractToken получает токен из заголовка Authorization, а при его отсутствии —
+// из куки session_token (EventSource в браузере не умеет передавать заголовки)
+func extractToken(r *http.Request) (string, error) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		if cookie, err := r.Cookie("session_token"); err == nil && cookie.Value != "" {
+			return cookie.Value, nil
+		}
+		return "", models.ErrMissingToken
+	}
 
-			next.ServeHTTP(w, r)
-		})
+	tokenParts := strings.Split(authHeader, " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		return "", models.ErrMalformedToken
 	}
+	return tokenParts[1], nil
 }
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/problem"
+	"net/http"
+)
+
+// RequirePermission пропускает запрос к обработчику маршрута, только если
+// у пользователя есть указанное право
+func RequirePermission(permission authz.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := authz.FromContext(r.Context())
+		if !ok {
+			problem.Write(w, r, authz.ErrUnauthenticated)
+			return
+		}
+		if !principal.Can(permission) {
+			problem.Write(w, r, authz.ErrForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+	"music-service/internal/i18n"
+	"net/http"
+)
+
+// Locale определяет языки запроса по Accept-Language (i18n.Tags). Язык из
+// настроек пользователя ставится перед ними в AuthMiddleware, поэтому Locale
+// подключается до него. Выбранный язык сообщений передается в
+// Content-Language
+func Locale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := i18n.WithTags(r.Context(), i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language")))
+		w.Header().Add("Vary", "Accept-Language")
+		setContentLanguage(w, ctx)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func setContentLanguage(w http.ResponseWriter, ctx context.Context) {
+	w.Header().Set("Content-Language", string(i18n.FromContext(ctx)))
+}
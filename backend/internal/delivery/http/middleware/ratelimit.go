@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"math"
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/problem"
+	"music-service/internal/logging"
+	"music-service/internal/models"
+	"music-service/internal/ratelimit"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Классы маршрутов по умолчанию: чтение и запись. Остальные классы (вход,
+// загрузка файлов) назначаются маршрутам в конфигурации
+const (
+	RateLimitClassRead  = "read"
+	RateLimitClassWrite = "write"
+)
+
+// RateLimit ограничивает частоту запросов ведром токенов. Ведро выбирается
+// по классу маршрута и субъекту: пользователю, если он аутентифицирован,
+// иначе адресу клиента. Класс задается ключом "<METHOD> <шаблон маршрута>"
+// в routeClasses, как в Timeout. Должен подключаться после AuthMiddleware
+func RateLimit(limiter *ratelimit.Limiter, routeClasses map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			subject := "ip:" + ClientIP(r)
+			if principal, ok := authz.FromContext(r.Context()); ok {
+				subject = "user:" + principal.UserID.String()
+			}
+
+			result, limit, ok, err := limiter.Allow(r.Context(), routeClass(r, routeClasses), subject)
+			if err != nil {
+				// Недоступность хранилища лимитов не должна останавливать сервис
+				logging.FromContext(r.Context()).Error("rate limit check failed", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			setRateLimitHeaders(w, result, limit)
+			if !result.Allowed {
+				SetRetryAfter(w, result.RetryAfter)
+				problem.Write(w, r, models.ErrRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func routeClass(r *http.Request, routeClasses map[string]string) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			if class, ok := routeClasses[r.Method+" "+template]; ok {
+				return class
+			}
+		}
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return RateLimitClassRead
+	}
+	return RateLimitClassWrite
+}
+
+// setRateLimitHeaders выставляет заголовки RateLimit-* (draft-ietf-httpapi-ratelimit-headers)
+func setRateLimitHeaders(w http.ResponseWriter, result ratelimit.Result, limit ratelimit.Limit) {
+	header := w.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	header.Set("RateLimit-Policy", strconv.Itoa(limit.Rate)+";w="+strconv.Itoa(ceilSeconds(limit.Period))+";burst="+strconv.Itoa(limit.Burst))
+}
+
+// SetRetryAfter выставляет Retry-After в секундах, округляя вверх
+func SetRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
+
+// ClientIP возвращает адрес клиента из соединения. X-Forwarded-For не
+// учитывается: клиент может подделать его и обойти ограничения
+func ClientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
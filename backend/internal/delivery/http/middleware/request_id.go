@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"log/slog"
+	"music-service/internal/audit"
+	"music-service/internal/logging"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength ограничивает идентификатор, пришедший от клиента или
+// прокси, чтобы его нельзя было использовать для засорения логов
+const maxRequestIDLength = 128
+
+// RequestID присваивает запросу идентификатор: берет X-Request-ID от прокси,
+// если он корректен, иначе генерирует новый. Идентификатор возвращается в
+// заголовке ответа, добавляется ко всем записям логгера запроса
+// (logging.FromContext) и вместе с адресом клиента попадает в журнал
+// действий (audit.RequestInfoFromContext). По завершении запроса пишет строку access-лога.
+// Должен подключаться первым
+func RequestID(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			requestLogger := logger.With("request_id", id)
+			ctx := logging.NewContext(r.Context(), requestLogger)
+			ctx = audit.WithRequestInfo(ctx, audit.RequestInfo{IP: ClientIP(r), RequestID: id})
+
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			// Query string не пишется: в ней бывают code и state OAuth
+			requestLogger.Log(ctx, level, "http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", recorder.bytes,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"remote_ip", ClientIP(r),
+			)
+		})
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// statusRecorder запоминает код ответа и размер тела для access-лога
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush нужен потоковым обработчикам (SSE), которые проверяют http.Flusher
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap позволяет http.ResponseController добраться до исходного writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/middleware"
+	"music-service/internal/delivery/http/problem"
+	"music-service/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequirePermission(t *testing.T) {
+	cases := []struct {
+		name   string
+		ctx    context.Context
+		status int
+		code   string
+	}{
+		{"anonymous", context.Background(), http.StatusUnauthorized, "unauthenticated"},
+		{"without grant", withRole(models.UserPermission), http.StatusForbidden, "forbidden"},
+		{"with grant", withRole(models.AdminPermission), http.StatusNoContent, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := middleware.RequirePermission(authz.TrackDelete, func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/tracks/42", nil).WithContext(tc.ctx))
+
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Equal(t, tc.code == "", called)
+			if tc.code != "" {
+				var body problem.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Equal(t, tc.code, body.Code)
+			}
+		})
+	}
+}
+
+func withRole(role models.Permission) context.Context {
+	return authz.WithPrincipal(context.Background(), &authz.Principal{UserID: uuid.New(), Role: role})
+}
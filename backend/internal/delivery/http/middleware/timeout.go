@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Timeout ограничивает время обработки запроса через дедлайн контекста.
+// Переопределения задаются ключом "<METHOD> <шаблон маршрута>", например
+// "POST /api/v1/tracks". Нулевое значение отключает таймаут — это нужно
+// для потоковых маршрутов (SSE, отдача аудио)
+func Timeout(defaultTimeout time.Duration, routeTimeouts map[string]time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := defaultTimeout
+			if route := mux.CurrentRoute(r); route != nil {
+				if template, err := route.GetPathTemplate(); err == nil {
+					if override, ok := routeTimeouts[r.Method+" "+template]; ok {
+						timeout = override
+					}
+				}
+			}
+
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"music-service/internal/audit"
+	"music-service/internal/authz"
+	"music-service/internal/i18n"
+	"music-service/internal/logging"
+	"music-service/internal/models"
+	"music-service/internal/password"
+	"music-service/internal/ratelimit"
+	"music-service/internal/validation"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ContentType — тип ответа с ошибкой по RFC 7807
+const ContentType = "application/problem+json"
+
+// Problem — тело ответа с ошибкой. Code — стабильный машиночитаемый код,
+// Detail — сообщение для пользователя, Errors — ошибки отдельных полей
+// запроса, Duplicates — треки каталога, из-за которых отклонена загрузка
+type Problem struct {
+	Type       string                   `json:"type"`
+	Title      string                   `json:"title"`
+	Status     int                      `json:"status"`
+	Detail     string                   `json:"detail,omitempty"`
+	Instance   string                   `json:"instance,omitempty"`
+	Code       string                   `json:"code"`
+	RequestID  string                   `json:"request_id,omitempty"`
+	Errors     []FieldError             `json:"errors,omitempty"`
+	Reason     string                   `json:"reason,omitempty"`
+	Until      *time.Time               `json:"until,omitempty"`
+	Duplicates []*models.DuplicateMatch `json:"duplicates,omitempty"`
+}
+
+// FieldError — ошибка проверки одного поля запроса
+type FieldError struct {
+	Field  string `json:"field"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// kinds — статус ответа и общий код для каждой категории доменных ошибок
+var kinds = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{models.ErrNotFound, http.StatusNotFound, "not_found"},
+	{models.ErrInvalidInput, http.StatusBadRequest, "invalid_input"},
+	{models.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{models.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{models.ErrConflict, http.StatusConflict, "conflict"},
+	{models.ErrPreconditionFailed, http.StatusPreconditionFailed, "precondition_failed"},
+	{models.ErrTooManyRequests, http.StatusTooManyRequests, "too_many_requests"},
+	{models.ErrTooLarge, http.StatusRequestEntityTooLarge, "payload_too_large"},
+	{models.ErrUnavailable, http.StatusServiceUnavailable, "unavailable"},
+}
+
+// Write отвечает ошибкой err в формате problem+json. Статус и код
+// выбираются по типу ошибки; неизвестные ошибки пишутся в журнал и
+// отдаются клиенту как внутренняя ошибка без подробностей
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	p := FromError(err, i18n.FromContext(r.Context()))
+	if p.Status == http.StatusInternalServerError {
+		logging.FromContext(r.Context()).Error("request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+
+	var locked *ratelimit.LockedError
+	if errors.As(err, &locked) {
+		w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(locked.RetryAfter)))
+	}
+
+	p.Instance = r.URL.Path
+	p.RequestID = audit.RequestInfoFromContext(r.Context()).RequestID
+	Render(w, p)
+}
+
+// Render записывает готовый Problem
+func Render(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// FromError строит Problem по ошибке без данных запроса; сообщения — на
+// языке locale
+func FromError(err error, locale i18n.Locale) *Problem {
+	var (
+		domainErr    *models.DomainError
+		policyErr    *password.PolicyError
+		suspendedErr *models.SuspendedError
+		lockedErr    *ratelimit.LockedError
+		maxBytesErr  *http.MaxBytesError
+		invalidErr   *validation.Error
+		duplicateErr *models.DuplicateError
+	)
+
+	switch {
+	case errors.As(err, &invalidErr):
+		p := newProblem(locale, http.StatusBadRequest, "validation_failed")
+		for _, v := range invalidErr.Violations {
+			p.Errors = append(p.Errors, FieldError{Field: v.Field, Code: v.Code, Detail: i18n.Message(locale, v.Code, v.Params...)})
+		}
+		if len(p.Errors) == 1 {
+			p.Detail = p.Errors[0].Detail
+		}
+		return p
+	case errors.As(err, &duplicateErr):
+		p := newProblem(locale, http.StatusConflict, models.ErrDuplicateTrack.Code)
+		p.Duplicates = duplicateErr.Matches
+		return p
+	case errors.As(err, &domainErr):
+		p := newProblem(locale, statusOf(domainErr.Kind), domainErr.Code)
+		if p.Detail == "" {
+			p.Detail = domainErr.Message
+		}
+		if domainErr.Field != "" {
+			p.Errors = []FieldError{{Field: domainErr.Field, Code: domainErr.Code, Detail: p.Detail}}
+		}
+		return p
+	case errors.As(err, &policyErr):
+		p := newProblem(locale, http.StatusBadRequest, policyErr.Code)
+		if policyErr.Limit > 0 {
+			p.Detail = i18n.Message(locale, policyErr.Code, policyErr.Limit)
+		}
+		p.Errors = []FieldError{{Field: "password", Code: policyErr.Code, Detail: p.Detail}}
+		return p
+	case errors.As(err, &suspendedErr):
+		p := newProblem(locale, http.StatusForbidden, "account_suspended")
+		p.Reason = suspendedErr.Reason
+		p.Until = suspendedErr.Until
+		return p
+	case errors.As(err, &lockedErr):
+		return newProblem(locale, http.StatusTooManyRequests, "too_many_attempts")
+	case errors.As(err, &maxBytesErr):
+		return newProblem(locale, http.StatusRequestEntityTooLarge, "payload_too_large")
+	case errors.Is(err, authz.ErrUnauthenticated):
+		return newProblem(locale, http.StatusUnauthorized, "unauthenticated")
+	case errors.Is(err, authz.ErrForbidden):
+		return newProblem(locale, http.StatusForbidden, "forbidden")
+	case errors.Is(err, context.DeadlineExceeded):
+		return newProblem(locale, http.StatusServiceUnavailable, "request_timeout")
+	}
+
+	for _, k := range kinds {
+		if errors.Is(err, k.kind) {
+			return newProblem(locale, k.status, k.code)
+		}
+	}
+	return newProblem(locale, http.StatusInternalServerError, "internal_error")
+}
+
+func newProblem(locale i18n.Locale, status int, code string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: i18n.Message(locale, code),
+		Code:   code,
+	}
+}
+
+// statusOf — статус по категории ошибки. Категорией может быть другая
+// доменная ошибка: тогда статус берется по ее категории
+func statusOf(kind error) int {
+	for _, k := range kinds {
+		if errors.Is(kind, k.kind) {
+			return k.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// NotFound отвечает на запросы к несуществующим маршрутам
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeCode(w, r, http.StatusNotFound, "route_not_found")
+}
+
+// MethodNotAllowed отвечает на запросы с неподдерживаемым методом
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeCode(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+}
+
+func writeCode(w http.ResponseWriter, r *http.Request, status int, code string) {
+	p := newProblem(i18n.FromContext(r.Context()), status, code)
+	p.Instance = r.URL.Path
+	p.RequestID = audit.RequestInfoFromContext(r.Context()).RequestID
+	Render(w, p)
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
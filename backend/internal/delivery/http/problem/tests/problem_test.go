@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"music-service/internal/authz"
+	"music-service/internal/delivery/http/problem"
+	"music-service/internal/i18n"
+	"music-service/internal/models"
+	"music-service/internal/password"
+	"music-service/internal/ratelimit"
+	"music-service/internal/validation"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func write(t *testing.T, err error) (*httptest.ResponseRecorder, problem.Problem) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tracks/42", nil)
+	problem.Write(rec, req, err)
+
+	var body problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec, body
+}
+
+func TestWrite_StatusAndCode(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"доменная ошибка", models.ErrTrackNotFound, http.StatusNotFound, "track_not_found"},
+		{"обернутая доменная ошибка", fmt.Errorf("load: %w", models.ErrAlbumExists), http.StatusConflict, "album_exists"},
+		{"доменная ошибка другой ошибки", models.ErrTrackAlbumNotFound, http.StatusNotFound, "track_album_not_found"},
+		{"слишком большой трек", fmt.Errorf("%w: limit 100 MB", models.ErrTrackTooLarge), http.StatusRequestEntityTooLarge, "track_too_large"},
+		{"категория без кода", models.ErrNotFound, http.StatusNotFound, "not_found"},
+		{"ограничение частоты", models.ErrRateLimited, http.StatusTooManyRequests, "rate_limited"},
+		{"не авторизован", authz.ErrUnauthenticated, http.StatusUnauthorized, "unauthenticated"},
+		{"нет прав", authz.ErrForbidden, http.StatusForbidden, "forbidden"},
+		{"таймаут", context.DeadlineExceeded, http.StatusServiceUnavailable, "request_timeout"},
+		{"слишком большое тело", &http.MaxBytesError{Limit: 10}, http.StatusRequestEntityTooLarge, "payload_too_large"},
+		{"неизвестная ошибка", errors.New("connection refused"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, body := write(t, tc.err)
+
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, tc.status, body.Status)
+			assert.Equal(t, tc.code, body.Code)
+			assert.Equal(t, "about:blank", body.Type)
+			assert.Equal(t, http.StatusText(tc.status), body.Title)
+			assert.Equal(t, "/api/v1/tracks/42", body.Instance)
+			assert.NotEmpty(t, body.Detail)
+		})
+	}
+}
+
+func TestWrite_InternalErrorHidesDetails(t *testing.T) {
+	_, body := write(t, errors.New("pq: password authentication failed"))
+
+	assert.NotContains(t, body.Detail, "pq")
+}
+
+func TestWrite_FieldError(t *testing.T) {
+	rec, body := write(t, models.ErrLoginTooShort)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "login", body.Errors[0].Field)
+	assert.Equal(t, "login_too_short", body.Errors[0].Code)
+	assert.Equal(t, body.Detail, body.Errors[0].Detail)
+}
+
+func TestWrite_PasswordPolicy(t *testing.T) {
+	policy, err := password.NewPolicy(password.Config{MinLength: 10})
+	require.NoError(t, err)
+	policyErr := policy.Validate("short1", "listener")
+	require.Error(t, policyErr)
+
+	rec, body := write(t, policyErr)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, password.CodeTooShort, body.Code)
+	assert.Contains(t, body.Detail, "10")
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "password", body.Errors[0].Field)
+}
+
+func TestWrite_ValidationErrors(t *testing.T) {
+	errs := validation.Field("title", validation.CodeTooLong, 100)
+	errs.Add("album_id", validation.CodeInvalidUUID)
+
+	rec, body := write(t, fmt.Errorf("decode: %w", errs))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "validation_failed", body.Code)
+	require.Len(t, body.Errors, 2)
+	assert.Equal(t, "title", body.Errors[0].Field)
+	assert.Equal(t, "too_long", body.Errors[0].Code)
+	assert.Contains(t, body.Errors[0].Detail, "100")
+	assert.Equal(t, "album_id", body.Errors[1].Field)
+	assert.Equal(t, "invalid_uuid", body.Errors[1].Code)
+
+	// Единственная ошибка поля становится и общим сообщением
+	_, body = write(t, validation.Field("q", validation.CodeRequired))
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, body.Errors[0].Detail, body.Detail)
+}
+
+func TestWrite_Localized(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tracks/42", nil)
+	req = req.WithContext(i18n.WithTags(req.Context(), []string{"en-US"}))
+	problem.Write(rec, req, models.ErrTrackNotFound)
+
+	var body problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Track not found", body.Detail)
+	assert.Equal(t, "track_not_found", body.Code)
+
+	// Без языка в запросе — сообщение на языке по умолчанию
+	_, body = write(t, models.ErrTrackNotFound)
+	assert.Equal(t, "Трек не найден", body.Detail)
+}
+
+func TestWrite_Suspended(t *testing.T) {
+	until := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	rec, body := write(t, &models.SuspendedError{Reason: "spam", Until: &until})
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, "account_suspended", body.Code)
+	assert.Equal(t, "spam", body.Reason)
+	require.NotNil(t, body.Until)
+	assert.True(t, until.Equal(*body.Until))
+}
+
+func TestWrite_Duplicate(t *testing.T) {
+	track := &models.Track{ID: uuid.New(), Title: "Кукушка"}
+	rec, body := write(t, &models.DuplicateError{Matches: []*models.DuplicateMatch{{Track: track, Similarity: 0.93}}})
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "duplicate_track", body.Code)
+	require.Len(t, body.Duplicates, 1)
+	assert.Equal(t, track.ID, body.Duplicates[0].Track.ID)
+	assert.Equal(t, 0.93, body.Duplicates[0].Similarity)
+}
+
+func TestWrite_LockedSetsRetryAfter(t *testing.T) {
+	rec, body := write(t, &ratelimit.LockedError{RetryAfter: 1500 * time.Millisecond})
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "too_many_attempts", body.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+}
+
+func TestNotFoundAndMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	problem.NotFound(rec, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"route_not_found"`)
+
+	rec = httptest.NewRecorder()
+	problem.MethodNotAllowed(rec, httptest.NewRequest(http.MethodPut, "/api/v1/tracks", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"method_not_allowed"`)
+}
@@ -1,9 +1,16 @@
 package router
 
 import (
+	"log/slog"
+	"music-service/internal/authz"
 	"music-service/internal/delivery/http/handlers"
 	"music-service/internal/delivery/http/middleware"
+	"music-service/internal/delivery/http/problem"
+	"music-service/internal/events"
+	"music-service/internal/ratelimit"
 	"music-service/internal/usecases/interfaces"
+	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -14,57 +21,156 @@ type Router struct {
 
 func NewRouter(
 	userUseCase interfaces.UserUseCase,
+	passwordUseCase interfaces.PasswordUseCase,
+	oidcUseCase interfaces.OIDCUseCase,
+	mfaUseCase interfaces.MFAUseCase,
+	adminUseCase interfaces.AdminUseCase,
+	trashUseCase interfaces.TrashUseCase,
 	trackUseCase interfaces.TrackUseCase,
 	albumUseCase interfaces.AlbumUseCase,
 	genreUseCase interfaces.GenreUseCase,
 	playlistUseCase interfaces.PlaylistUseCase,
 	historyUseCase interfaces.HistoryUseCase,
+	playbackUseCase interfaces.PlaybackUseCase,
+	followUseCase interfaces.FollowUseCase,
+	translationUseCase interfaces.TranslationUseCase,
+	coverUseCase interfaces.CoverUseCase,
+	lyricsUseCase interfaces.LyricsUseCase,
+	duplicateUseCase interfaces.DuplicateUseCase,
+	bus events.Bus,
 	allowedTypes []string,
 	maxFileSizeMB int,
+	maxCoverSizeMB int,
+	requestTimeout time.Duration,
+	routeTimeouts map[string]time.Duration,
+	postLoginRedirect string,
+	limiter *ratelimit.Limiter,
+	rateLimitClasses map[string]string,
+	logger *slog.Logger,
 ) *Router {
 	r := mux.NewRouter()
+	r.NotFoundHandler = http.HandlerFunc(problem.NotFound)
+	r.MethodNotAllowedHandler = http.HandlerFunc(problem.MethodNotAllowed)
 	router := &Router{
 		router: r,
 	}
 
+	r.Use(middleware.RequestID(logger))
 	r.Use(middleware.CORS)
+	r.Use(middleware.Locale)
+	r.Use(middleware.Timeout(requestTimeout, routeTimeouts))
 	r.Use(middleware.AuthMiddleware(userUseCase))
+	if limiter != nil {
+		r.Use(middleware.RateLimit(limiter, rateLimitClasses))
+	}
 
 	userHandler := handlers.NewUserHandler(userUseCase)
+	passwordHandler := handlers.NewPasswordHandler(passwordUseCase)
+	oidcHandler := handlers.NewOIDCHandler(oidcUseCase, postLoginRedirect)
+	mfaHandler := handlers.NewMFAHandler(mfaUseCase)
+	adminHandler := handlers.NewAdminHandler(adminUseCase)
+	trashHandler := handlers.NewTrashHandler(trashUseCase)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateUseCase)
 	trackHandler := handlers.NewTrackHandler(trackUseCase, allowedTypes, maxFileSizeMB, historyUseCase)
 	albumHandler := handlers.NewAlbumHandler(albumUseCase)
 	genreHandler := handlers.NewGenreHandler(genreUseCase)
 	playlistHandler := handlers.NewPlaylistHandler(playlistUseCase, userUseCase)
 	historyHandler := handlers.NewHistoryHandler(historyUseCase)
+	playbackHandler := handlers.NewPlaybackHandler(playbackUseCase)
+	followHandler := handlers.NewFollowHandler(followUseCase)
+	eventHandler := handlers.NewEventHandler(bus)
+	translationHandler := handlers.NewTranslationHandler(translationUseCase)
+	coverHandler := handlers.NewCoverHandler(coverUseCase, maxCoverSizeMB)
+	lyricsHandler := handlers.NewLyricsHandler(lyricsUseCase)
 
 	v1 := r.PathPrefix("/api/v1").Subrouter()
 
 	v1.HandleFunc("/users", userHandler.RegisterUser).Methods("POST", "OPTIONS")
 	v1.HandleFunc("/users/auth", userHandler.AuthenticateUser).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/users/refresh", userHandler.RefreshTokens).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/users/auth/mfa", mfaHandler.CompleteChallenge).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/users/auth/mfa/enroll", mfaHandler.BeginChallengeEnrollment).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/users/password/forgot", passwordHandler.ForgotPassword).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/users/password/reset", passwordHandler.ResetPassword).Methods("POST", "OPTIONS")
 	v1.HandleFunc("/users/{id}", userHandler.GetUserProfile).Methods("GET", "OPTIONS")
-	v1.HandleFunc("/users/{id}/permissions", userHandler.UpdateUserPermissions).Methods("PATCH", "OPTIONS")
-	v1.HandleFunc("/users/{id}", userHandler.DeleteUser).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/users/{id}/permissions", middleware.RequirePermission(authz.UserManage, userHandler.UpdateUserPermissions)).Methods("PATCH", "OPTIONS")
+	v1.HandleFunc("/users/{id}", middleware.RequirePermission(authz.UserManage, userHandler.DeleteUser)).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/users/{id}/mfa", middleware.RequirePermission(authz.UserManage, mfaHandler.ResetForUser)).Methods("DELETE", "OPTIONS")
 	v1.HandleFunc("/users/logout", userHandler.LogoutUser).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/users/me/password", passwordHandler.ChangePassword).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/users/me/locale", userHandler.SetLocale).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/users/me/sessions", userHandler.ListSessions).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/users/me/sessions", userHandler.RevokeOtherSessions).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/users/me/sessions/{session_id}", userHandler.RevokeSession).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/users/me/mfa", mfaHandler.Status).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/users/me/mfa/totp", mfaHandler.BeginEnrollment).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/users/me/mfa/totp/confirm", mfaHandler.ConfirmEnrollment).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/users/me/mfa/totp", mfaHandler.Disable).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/users/me/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/users/me/identities", oidcHandler.ListIdentities).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/users/me/identities/{provider}", oidcHandler.UnlinkIdentity).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/users/me/trash", trashHandler.ListOwnTrash).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/users/me/trash/playlists/{id}/restore", trashHandler.RestorePlaylist).Methods("POST", "OPTIONS")
+
+	v1.HandleFunc("/admin/mfa/required-roles", middleware.RequirePermission(authz.UserManage, mfaHandler.RequiredRoles)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/mfa/required-roles", middleware.RequirePermission(authz.UserManage, mfaHandler.SetRequiredRoles)).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/admin/users", middleware.RequirePermission(authz.UserManage, adminHandler.ListUsers)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/users/{id}", middleware.RequirePermission(authz.UserManage, adminHandler.GetUser)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/users/{id}/suspension", middleware.RequirePermission(authz.UserManage, adminHandler.SuspendUser)).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/admin/users/{id}/suspension", middleware.RequirePermission(authz.UserManage, adminHandler.UnsuspendUser)).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/admin/users/{id}/sessions", middleware.RequirePermission(authz.UserManage, adminHandler.ForceLogout)).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/admin/users/{id}/playlists", middleware.RequirePermission(authz.UserManage, adminHandler.GetUserPlaylists)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/users/{id}/history", middleware.RequirePermission(authz.UserManage, adminHandler.GetUserHistory)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/audit", middleware.RequirePermission(authz.AuditRead, adminHandler.ListAuditLog)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/trash", middleware.RequirePermission(authz.TrashManage, trashHandler.ListTrash)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/trash/{type}/{id}/restore", middleware.RequirePermission(authz.TrashManage, trashHandler.Restore)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/admin/lyrics", middleware.RequirePermission(authz.LyricsModerate, lyricsHandler.ListLyrics)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/duplicates", middleware.RequirePermission(authz.DuplicateManage, duplicateHandler.ListDuplicates)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/duplicates/merge", middleware.RequirePermission(authz.DuplicateManage, duplicateHandler.MergeTracks)).Methods("POST", "OPTIONS")
+
+	v1.HandleFunc("/auth/oidc/providers", oidcHandler.ListProviders).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/auth/oidc/{provider}/login", oidcHandler.Login).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/auth/oidc/{provider}/callback", oidcHandler.Callback).Methods("GET", "OPTIONS")
 
 	v1.HandleFunc("/tracks", trackHandler.SearchTracks).Methods("GET", "OPTIONS")
-	v1.HandleFunc("/tracks", trackHandler.UploadTrack).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/tracks", middleware.RequirePermission(authz.TrackUpload, trackHandler.UploadTrack)).Methods("POST", "OPTIONS")
 	v1.HandleFunc("/tracks/{id}", trackHandler.GetTrackDetails).Methods("GET", "OPTIONS")
 	v1.HandleFunc("/tracks/{id}/stream", trackHandler.ServeTrackFile).Methods("GET", "OPTIONS")
-	v1.HandleFunc("/tracks/{id}", trackHandler.DeleteTrack).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/tracks/{id}", middleware.RequirePermission(authz.TrackDelete, trackHandler.DeleteTrack)).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/tracks/{id}", middleware.RequirePermission(authz.TrackEdit, trackHandler.UpdateTrack)).Methods("PATCH", "OPTIONS")
+	v1.HandleFunc("/tracks/{id}/loudness", middleware.RequirePermission(authz.TrackEdit, trackHandler.AnalyzeLoudness)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/tracks/{id}/fingerprint", middleware.RequirePermission(authz.TrackEdit, trackHandler.FingerprintTrack)).Methods("POST", "OPTIONS")
+	// Текст песни может предложить любой пользователь, он публикуется после модерации
+	v1.HandleFunc("/tracks/{id}/lyrics", lyricsHandler.GetLyrics).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/tracks/{id}/lyrics", lyricsHandler.SetLyrics).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/tracks/{id}/lyrics", middleware.RequirePermission(authz.LyricsModerate, lyricsHandler.DeleteLyrics)).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/tracks/{id}/lyrics/status", middleware.RequirePermission(authz.LyricsModerate, lyricsHandler.ModerateLyrics)).Methods("PUT", "OPTIONS")
+
+	v1.HandleFunc("/lyrics/search", lyricsHandler.SearchLyrics).Methods("GET", "OPTIONS")
 
 	v1.HandleFunc("/albums", albumHandler.ListAllAlbums).Methods("GET", "OPTIONS")
-	v1.HandleFunc("/albums", albumHandler.CreateAlbum).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/albums", middleware.RequirePermission(authz.AlbumEdit, albumHandler.CreateAlbum)).Methods("POST", "OPTIONS")
 	v1.HandleFunc("/albums/{id}", albumHandler.GetAlbumDetails).Methods("GET", "OPTIONS")
-	v1.HandleFunc("/albums/{id}", albumHandler.UpdateAlbum).Methods("PUT", "OPTIONS")
-	v1.HandleFunc("/albums/{id}", albumHandler.DeleteAlbum).Methods("DELETE", "OPTIONS")
-	v1.HandleFunc("/albums/{id}/tracks", albumHandler.AddTrackToAlbum).Methods("POST", "OPTIONS")
-	v1.HandleFunc("/albums/{id}/tracks/{track_id}", albumHandler.RemoveTrackFromAlbum).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/albums/{id}", middleware.RequirePermission(authz.AlbumEdit, albumHandler.UpdateAlbum)).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/albums/{id}", middleware.RequirePermission(authz.AlbumEdit, albumHandler.DeleteAlbum)).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/albums/{id}/tracks", middleware.RequirePermission(authz.AlbumEdit, albumHandler.AddTrackToAlbum)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/albums/{id}/tracks/{track_id}", middleware.RequirePermission(authz.AlbumEdit, albumHandler.RemoveTrackFromAlbum)).Methods("DELETE", "OPTIONS")
 
 	v1.HandleFunc("/genres", genreHandler.ListAllGenres).Methods("GET", "OPTIONS")
-	v1.HandleFunc("/genres", genreHandler.CreateGenre).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/genres", middleware.RequirePermission(authz.GenreManage, genreHandler.CreateGenre)).Methods("POST", "OPTIONS")
 	v1.HandleFunc("/genres/tracks/{id}", genreHandler.GetGenresByTrack).Methods("GET", "OPTIONS")
-	v1.HandleFunc("/genres/tracks/{id}/genres", genreHandler.AssignGenreToTrack).Methods("POST", "OPTIONS")
-	v1.HandleFunc("/genres/tracks/{trackId}/genres/{genreId}", genreHandler.RemoveGenreFromTrack).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/genres/tracks/{id}/genres", middleware.RequirePermission(authz.GenreManage, genreHandler.AssignGenreToTrack)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/genres/tracks/{trackId}/genres/{genreId}", middleware.RequirePermission(authz.GenreManage, genreHandler.RemoveGenreFromTrack)).Methods("DELETE", "OPTIONS")
+
+	// Права на изменение переводов зависят от типа объекта и проверяются в use case
+	v1.HandleFunc("/{type:tracks|albums|genres}/{id}/translations", translationHandler.ListTranslations).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/{type:tracks|albums|genres}/{id}/translations/{locale}", translationHandler.SetTranslation).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/{type:tracks|albums|genres}/{id}/translations/{locale}", translationHandler.DeleteTranslation).Methods("DELETE", "OPTIONS")
+
+	// Права на изменение обложек зависят от типа объекта и проверяются в use case
+	v1.HandleFunc("/{type:tracks|albums|playlists}/{id}/cover", coverHandler.UploadCover).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/{type:tracks|albums|playlists}/{id}/cover", coverHandler.DeleteCover).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/covers/{id}", coverHandler.GetCover).Methods("GET", "OPTIONS")
 
 	v1.HandleFunc("/playlists", playlistHandler.CreatePlaylist).Methods("POST", "OPTIONS")
 	v1.HandleFunc("/playlists", playlistHandler.GetUserPlaylists).Methods("GET", "OPTIONS")
@@ -74,11 +180,39 @@ func NewRouter(
 	v1.HandleFunc("/playlists/{id}/tracks", playlistHandler.GetPlaylistTracks).Methods("GET", "OPTIONS")
 	v1.HandleFunc("/playlists/{id}/tracks", playlistHandler.AddTrackToPlaylist).Methods("POST", "OPTIONS")
 	v1.HandleFunc("/playlists/{playlistId}/tracks/{trackId}", playlistHandler.RemoveTrackFromPlaylist).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/playlists/{id}/cover", coverHandler.GetPlaylistCover).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/playlists/{id}/follow", followHandler.FollowPlaylist).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/playlists/{id}/follow", followHandler.UnfollowPlaylist).Methods("DELETE", "OPTIONS")
+
+	v1.HandleFunc("/artists/{name}/follow", followHandler.FollowArtist).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/artists/{name}/follow", followHandler.UnfollowArtist).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/artists/{name}/cover", coverHandler.GetArtistCover).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/artists/{name}/cover", middleware.RequirePermission(authz.AlbumEdit, coverHandler.UploadArtistCover)).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/artists/{name}/cover", middleware.RequirePermission(authz.AlbumEdit, coverHandler.DeleteArtistCover)).Methods("DELETE", "OPTIONS")
+
+	v1.HandleFunc("/following/playlists", followHandler.GetFollowedPlaylists).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/following/artists", followHandler.GetFollowedArtists).Methods("GET", "OPTIONS")
 
 	v1.HandleFunc("/history/tracks/{trackId}", historyHandler.RecordPlayback).Methods("POST", "OPTIONS")
 	v1.HandleFunc("/history", historyHandler.GetUserHistory).Methods("GET", "OPTIONS")
 	v1.HandleFunc("/history/recent", historyHandler.GetRecentPlays).Methods("GET", "OPTIONS")
 
+	v1.HandleFunc("/player", playbackHandler.GetPlaybackState).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/player/devices", playbackHandler.ListDevices).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/player/device", playbackHandler.TransferPlayback).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/player/queue", playbackHandler.SetQueue).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/player/queue", playbackHandler.AddToQueue).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/player/queue/{position}", playbackHandler.RemoveFromQueue).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/player/play", playbackHandler.Play).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/player/pause", playbackHandler.Pause).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/player/seek", playbackHandler.Seek).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/player/next", playbackHandler.Next).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/player/previous", playbackHandler.Previous).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/player/shuffle", playbackHandler.SetShuffle).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/player/repeat", playbackHandler.SetRepeatMode).Methods("PUT", "OPTIONS")
+
+	v1.HandleFunc("/events", eventHandler.StreamEvents).Methods("GET", "OPTIONS")
+
 	return router
 }
 
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы событий, доставляемых клиентам
+const (
+	TypePlaybackChanged    = "playback.changed"
+	TypeQueueChanged       = "queue.changed"
+	TypePlaylistCreated    = "playlist.created"
+	TypePlaylistUpdated    = "playlist.updated"
+	TypePlaylistDeleted    = "playlist.deleted"
+	TypePlaylistTrackAdded = "playlist.track_added"
+	TypeArtistNewRelease   = "artist.new_release"
+
+	// TypeResync означает, что пропущенные события восстановить нельзя
+	// и клиент должен заново загрузить состояние через REST API
+	TypeResync = "resync"
+)
+
+// Event — типизированное событие для одного или нескольких пользователей
+type Event struct {
+	ID        string
+	Type      string
+	Data      json.RawMessage
+	CreatedAt time.Time
+}
+
+// Publisher публикует события. Реализация не должна блокировать вызывающий
+// код: доставка событий не является частью бизнес-операции
+type Publisher interface {
+	Publish(eventType string, payload interface{}, recipients ...uuid.UUID)
+}
+
+// Presence сообщает, какие устройства пользователя сейчас подключены к потоку событий
+type Presence interface {
+	IsDeviceConnected(userID, deviceID uuid.UUID) bool
+}
+
+// Bus — шина событий. Сейчас используется реализация в памяти процесса,
+// при масштабировании на несколько экземпляров ее можно заменить брокером
+type Bus interface {
+	Publisher
+	Presence
+	// Subscribe подписывает устройство на события пользователя. Если передан
+	// lastEventID, в Subscription.Replay попадут события, пропущенные после него
+	Subscribe(userID, deviceID uuid.UUID, lastEventID string) *Subscription
+	Unsubscribe(sub *Subscription)
+}
+
+// Subscription — подписка одного подключения на события пользователя
+type Subscription struct {
+	UserID   uuid.UUID
+	DeviceID uuid.UUID
+	// Replay содержит события, которые нужно отправить до новых
+	Replay []Event
+	events chan Event
+}
+
+// Events возвращает канал новых событий. Канал закрывается при отписке или
+// если клиент не успевает читать — тогда он должен переподключиться
+// с заголовком Last-Event-ID
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// PlaylistChange описывает изменение плейлиста
+type PlaylistChange struct {
+	PlaylistID uuid.UUID        `json:"playlist_id"`
+	Playlist   *PlaylistSummary `json:"playlist,omitempty"`
+}
+
+type PlaylistSummary struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	CoverURL    string    `json:"cover_url"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PlaylistTrackAdded описывает трек, добавленный в плейлист
+type PlaylistTrackAdded struct {
+	PlaylistID   uuid.UUID `json:"playlist_id"`
+	PlaylistName string    `json:"playlist_name"`
+	TrackID      uuid.UUID `json:"track_id"`
+	Title        string    `json:"title"`
+	ArtistName   string    `json:"artist_name"`
+}
+
+// NewRelease описывает новый альбом исполнителя
+type NewRelease struct {
+	Artist      string    `json:"artist"`
+	AlbumID     uuid.UUID `json:"album_id"`
+	Title       string    `json:"title"`
+	CoverURL    string    `json:"cover_url"`
+	ReleaseDate time.Time `json:"release_date"`
+}
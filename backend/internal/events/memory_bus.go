@@ -0,0 +1,273 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Размер буфера новых событий одного подписчика
+	subscriberBufferSize = 64
+
+	DefaultReplayEvents = 256
+	DefaultReplayWindow = 5 * time.Minute
+)
+
+// replayLog хранит недавние события пользователя для восстановления после переподключения
+type replayLog struct {
+	events []Event
+	// Номер последнего события, удаленного из журнала
+	trimmedSeq uint64
+}
+
+// MemoryBus — шина событий в памяти процесса.
+//
+// Идентификаторы событий имеют вид "<epoch>-<seq>": seq монотонно растет,
+// а epoch меняется при каждом запуске, поэтому Last-Event-ID от прошлого
+// процесса распознается как устаревший
+type MemoryBus struct {
+	mu        sync.Mutex
+	epoch     string
+	seq       uint64
+	subs      map[uuid.UUID]map[*Subscription]struct{}
+	logs      map[uuid.UUID]*replayLog
+	sweptSeq  uint64
+	lastSweep time.Time
+
+	maxEvents int
+	window    time.Duration
+	now       func() time.Time
+	logger    *slog.Logger
+}
+
+// NewMemoryBus создает шину, которая хранит для каждого пользователя
+// не более maxEvents событий не старше window. nil logger означает slog.Default()
+func NewMemoryBus(maxEvents int, window time.Duration, logger *slog.Logger) *MemoryBus {
+	if maxEvents <= 0 {
+		maxEvents = DefaultReplayEvents
+	}
+	if window <= 0 {
+		window = DefaultReplayWindow
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &MemoryBus{
+		epoch:     strconv.FormatInt(time.Now().UnixNano(), 36),
+		subs:      make(map[uuid.UUID]map[*Subscription]struct{}),
+		logs:      make(map[uuid.UUID]*replayLog),
+		lastSweep: time.Now(),
+		maxEvents: maxEvents,
+		window:    window,
+		now:       time.Now,
+		logger:    logger.With("component", "events"),
+	}
+}
+
+func (b *MemoryBus) Publish(eventType string, payload interface{}, recipients ...uuid.UUID) {
+	if len(recipients) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		b.logger.Error("marshal event failed", "event_type", eventType, "error", err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.seq++
+	event := Event{
+		ID:        b.formatID(b.seq),
+		Type:      eventType,
+		Data:      data,
+		CreatedAt: now,
+	}
+
+	delivered := make(map[uuid.UUID]struct{}, len(recipients))
+	for _, userID := range recipients {
+		if userID == uuid.Nil {
+			continue
+		}
+		if _, ok := delivered[userID]; ok {
+			continue
+		}
+		delivered[userID] = struct{}{}
+
+		b.appendLog(userID, event, now)
+		for sub := range b.subs[userID] {
+			select {
+			case sub.events <- event:
+			default:
+				// Клиент не успевает читать. Отключаем его: после переподключения
+				// пропущенные события будут отправлены из журнала
+				b.logger.Warn("subscriber buffer full, disconnecting", "user_id", userID, "device_id", sub.DeviceID)
+				b.removeLocked(sub)
+			}
+		}
+	}
+
+	b.sweepLocked(now)
+}
+
+func (b *MemoryBus) Subscribe(userID, deviceID uuid.UUID, lastEventID string) *Subscription {
+	sub := &Subscription{
+		UserID:   userID,
+		DeviceID: deviceID,
+		events:   make(chan Event, subscriberBufferSize),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastEventID != "" {
+		sub.Replay = b.replayLocked(userID, lastEventID)
+	}
+
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*Subscription]struct{})
+	}
+	b.subs[userID][sub] = struct{}{}
+
+	return sub
+}
+
+func (b *MemoryBus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.removeLocked(sub)
+}
+
+// IsDeviceConnected проверяет, есть ли у устройства открытое подключение
+func (b *MemoryBus) IsDeviceConnected(userID, deviceID uuid.UUID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs[userID] {
+		if sub.DeviceID == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// replayLocked возвращает события пользователя после lastEventID или
+// событие resync, если часть из них уже вышла за пределы окна
+func (b *MemoryBus) replayLocked(userID uuid.UUID, lastEventID string) []Event {
+	seq, ok := b.parseID(lastEventID)
+	if !ok || seq > b.seq {
+		return []Event{b.resyncEvent()}
+	}
+
+	entry := b.logs[userID]
+	if entry == nil {
+		if seq < b.sweptSeq {
+			return []Event{b.resyncEvent()}
+		}
+		return nil
+	}
+	b.trimLocked(entry, b.now())
+	if seq < entry.trimmedSeq {
+		return []Event{b.resyncEvent()}
+	}
+
+	var replay []Event
+	for _, event := range entry.events {
+		if eventSeq, _ := b.parseID(event.ID); eventSeq > seq {
+			replay = append(replay, event)
+		}
+	}
+	return replay
+}
+
+func (b *MemoryBus) appendLog(userID uuid.UUID, event Event, now time.Time) {
+	entry := b.logs[userID]
+	if entry == nil {
+		entry = &replayLog{}
+		b.logs[userID] = entry
+	}
+	entry.events = append(entry.events, event)
+	b.trimLocked(entry, now)
+}
+
+func (b *MemoryBus) trimLocked(entry *replayLog, now time.Time) {
+	drop := 0
+	for drop < len(entry.events) {
+		if len(entry.events)-drop <= b.maxEvents && now.Sub(entry.events[drop].CreatedAt) <= b.window {
+			break
+		}
+		drop++
+	}
+	if drop == 0 {
+		return
+	}
+	entry.trimmedSeq, _ = b.parseID(entry.events[drop-1].ID)
+	entry.events = append([]Event(nil), entry.events[drop:]...)
+}
+
+// sweepLocked периодически удаляет журналы, все события которых устарели
+func (b *MemoryBus) sweepLocked(now time.Time) {
+	if now.Sub(b.lastSweep) < b.window {
+		return
+	}
+	b.lastSweep = now
+
+	for userID, entry := range b.logs {
+		b.trimLocked(entry, now)
+		if len(entry.events) > 0 {
+			continue
+		}
+		if entry.trimmedSeq > b.sweptSeq {
+			b.sweptSeq = entry.trimmedSeq
+		}
+		delete(b.logs, userID)
+	}
+}
+
+func (b *MemoryBus) removeLocked(sub *Subscription) {
+	userSubs := b.subs[sub.UserID]
+	if _, ok := userSubs[sub]; !ok {
+		return
+	}
+	delete(userSubs, sub)
+	if len(userSubs) == 0 {
+		delete(b.subs, sub.UserID)
+	}
+	close(sub.events)
+}
+
+func (b *MemoryBus) resyncEvent() Event {
+	return Event{
+		ID:        b.formatID(b.seq),
+		Type:      TypeResync,
+		Data:      json.RawMessage(`{}`),
+		CreatedAt: b.now(),
+	}
+}
+
+func (b *MemoryBus) formatID(seq uint64) string {
+	return fmt.Sprintf("%s-%d", b.epoch, seq)
+}
+
+func (b *MemoryBus) parseID(id string) (uint64, bool) {
+	epoch, seqPart, found := strings.Cut(id, "-")
+	if !found || epoch != b.epoch {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"io"
+	"log/slog"
+	"music-service/internal/events"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBus(maxEvents int, window time.Duration) *events.MemoryBus {
+	return events.NewMemoryBus(maxEvents, window, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func receive(t *testing.T, sub *events.Subscription) events.Event {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		require.True(t, ok, "subscription closed")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return events.Event{}
+	}
+}
+
+func types(list []events.Event) []string {
+	result := make([]string, len(list))
+	for i, event := range list {
+		result[i] = event.Type
+	}
+	return result
+}
+
+func TestMemoryBus_Publish(t *testing.T) {
+	bus := newBus(0, 0)
+	userID, otherID := uuid.New(), uuid.New()
+
+	sub := bus.Subscribe(userID, uuid.New(), "")
+	other := bus.Subscribe(otherID, uuid.New(), "")
+	assert.Empty(t, sub.Replay)
+
+	// Повторный получатель получает событие один раз
+	bus.Publish("queue.updated", map[string]int{"position": 1}, userID, userID, uuid.Nil)
+
+	event := receive(t, sub)
+	assert.Equal(t, "queue.updated", event.Type)
+	assert.JSONEq(t, `{"position":1}`, string(event.Data))
+	assert.Empty(t, sub.Events())
+	assert.Empty(t, other.Events())
+
+	bus.Unsubscribe(sub)
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+}
+
+// После переподключения с Last-Event-ID клиент получает пропущенные
+// события своего пользователя по порядку
+func TestMemoryBus_Replay(t *testing.T) {
+	bus := newBus(0, 0)
+	userID := uuid.New()
+
+	first := bus.Subscribe(userID, uuid.New(), "")
+	bus.Publish("a", nil, userID)
+	lastSeen := receive(t, first)
+	bus.Unsubscribe(first)
+
+	bus.Publish("b", nil, userID)
+	bus.Publish("other", nil, uuid.New())
+	bus.Publish("c", nil, userID)
+
+	sub := bus.Subscribe(userID, uuid.New(), lastSeen.ID)
+	assert.Equal(t, []string{"b", "c"}, types(sub.Replay))
+
+	// С последнего события пропущенных нет
+	latest := bus.Subscribe(userID, uuid.New(), sub.Replay[1].ID)
+	assert.Empty(t, latest.Replay)
+}
+
+func TestMemoryBus_ReplayResync(t *testing.T) {
+	userID := uuid.New()
+
+	// Событие после lastEventID вытеснено из журнала по количеству
+	t.Run("trimmed by count", func(t *testing.T) {
+		bus := newBus(3, time.Hour)
+		sub := bus.Subscribe(userID, uuid.New(), "")
+		bus.Publish("a", nil, userID)
+		lastSeen := receive(t, sub)
+		for _, eventType := range []string{"b", "c", "d", "e"} {
+			bus.Publish(eventType, nil, userID)
+		}
+
+		replay := bus.Subscribe(userID, uuid.New(), lastSeen.ID).Replay
+		assert.Equal(t, []string{events.TypeResync}, types(replay))
+
+		// Последние maxEvents событий еще в журнале
+		receive(t, sub)
+		c := receive(t, sub)
+		require.Equal(t, "c", c.Type)
+		replay = bus.Subscribe(userID, uuid.New(), c.ID).Replay
+		assert.Equal(t, []string{"d", "e"}, types(replay))
+	})
+
+	// События старше окна удаляются из журнала
+	t.Run("trimmed by age", func(t *testing.T) {
+		bus := newBus(100, 20*time.Millisecond)
+		sub := bus.Subscribe(userID, uuid.New(), "")
+		bus.Publish("a", nil, userID)
+		lastSeen := receive(t, sub)
+		bus.Publish("b", nil, userID)
+
+		time.Sleep(40 * time.Millisecond)
+		replay := bus.Subscribe(userID, uuid.New(), lastSeen.ID).Replay
+		assert.Equal(t, []string{events.TypeResync}, types(replay))
+	})
+
+	// Идентификатор прошлого запуска процесса, из будущего или испорченный
+	t.Run("unknown id", func(t *testing.T) {
+		previous := newBus(0, 0)
+		sub := previous.Subscribe(userID, uuid.New(), "")
+		previous.Publish("a", nil, userID)
+		previousID := receive(t, sub).ID
+
+		time.Sleep(time.Millisecond)
+		bus := newBus(0, 0)
+		live := bus.Subscribe(userID, uuid.New(), "")
+		bus.Publish("a", nil, userID)
+		epoch, _, _ := strings.Cut(receive(t, live).ID, "-")
+
+		for _, id := range []string{previousID, epoch + "-100", epoch + "-x", "garbage"} {
+			replay := bus.Subscribe(userID, uuid.New(), id).Replay
+			assert.Equal(t, []string{events.TypeResync}, types(replay), id)
+		}
+	})
+}
+
+// Подписчик, который не читает события, отключается, а Publish не
+// блокируется; после переподключения пропущенное восстанавливается
+func TestMemoryBus_SlowSubscriber(t *testing.T) {
+	bus := newBus(1000, time.Hour)
+	userID := uuid.New()
+	slow := bus.Subscribe(userID, uuid.New(), "")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			bus.Publish("tick", i, userID)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Publish blocked on a full subscriber")
+	}
+
+	var (
+		received []events.Event
+		closed   bool
+	)
+	for !closed {
+		select {
+		case event, ok := <-slow.Events():
+			if !ok {
+				closed = true
+				break
+			}
+			received = append(received, event)
+		case <-time.After(time.Second):
+			t.Fatal("slow subscriber was not disconnected")
+		}
+	}
+	require.NotEmpty(t, received)
+	assert.Less(t, len(received), 200)
+	assert.False(t, bus.IsDeviceConnected(userID, slow.DeviceID))
+
+	resumed := bus.Subscribe(userID, slow.DeviceID, received[len(received)-1].ID)
+	assert.Len(t, resumed.Replay, 200-len(received))
+	assert.True(t, bus.IsDeviceConnected(userID, slow.DeviceID))
+}
@@ -0,0 +1,307 @@
+// Package fingerprint строит акустические отпечатки записей для поиска
+// дубликатов. Отпечаток — последовательность 32-битных суботпечатков по
+// схеме Haitsma–Kalker: каждый бит — знак изменения разности энергий
+// соседних частотных полос между соседними кадрами. Такие отпечатки
+// устойчивы к изменению громкости, перекодированию и небольшому шуму
+package fingerprint
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+const (
+	// SampleRate — частота, до которой понижается звук перед анализом
+	SampleRate = 11025
+	// frameSize — длина кадра в сэмплах (около 370 мс)
+	frameSize = 4096
+	// hopSize — шаг между кадрами: кадры перекрываются на две трети
+	hopSize = frameSize / 3
+	// bands — число полос: разности 33 соседних полос дают 32 бита
+	bands = 33
+	// minFrequency, maxFrequency — диапазон полос, Гц: в нем сосредоточена
+	// большая часть тональной информации и мало шума
+	minFrequency = 300.0
+	maxFrequency = 2000.0
+	// silenceLevel — средний квадрат сэмплов, ниже которого кадр считается
+	// тишиной и пропускается
+	silenceLevel = 1e-8
+	// hashShift — суботпечаток сдвигается на hashShift бит, чтобы получить
+	// ключ индекса: совпадение старших бит терпимее к шуму, чем всех 32
+	hashShift = 12
+)
+
+// ErrInvalidFingerprint — сохраненный отпечаток поврежден
+var ErrInvalidFingerprint = errors.New("invalid fingerprint")
+
+// Fingerprint — акустический отпечаток записи
+type Fingerprint []uint32
+
+// Bytes кодирует отпечаток для хранения: суботпечатки little-endian
+func (f Fingerprint) Bytes() []byte {
+	data := make([]byte, 0, len(f)*4)
+	for _, v := range f {
+		data = binary.LittleEndian.AppendUint32(data, v)
+	}
+	return data
+}
+
+// Parse декодирует отпечаток, закодированный Bytes
+func Parse(data []byte) (Fingerprint, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("%w: length %d", ErrInvalidFingerprint, len(data))
+	}
+	f := make(Fingerprint, len(data)/4)
+	for i := range f {
+		f[i] = binary.LittleEndian.Uint32(data[i*4:])
+	}
+	return f, nil
+}
+
+// Hashes возвращает различные ключи индекса отпечатка по возрастанию.
+// Записи с общими ключами — кандидаты на дубликаты, их сравнивает Compare
+func (f Fingerprint) Hashes() []uint32 {
+	seen := make(map[uint32]bool, len(f))
+	hashes := make([]uint32, 0, len(f))
+	for _, v := range f {
+		if key := hash(v); !seen[key] {
+			seen[key] = true
+			hashes = append(hashes, key)
+		}
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+	return hashes
+}
+
+func hash(v uint32) uint32 {
+	return v >> hashShift
+}
+
+// Compare возвращает сходство отпечатков от 0 до 1. Сначала голосованием
+// по совпадающим ключам находится сдвиг одной записи относительно другой,
+// затем считается доля совпавших бит на пересечении, отнесенная к длине
+// более длинного отпечатка. У одной и той же записи сходство близко к 1,
+// у разных записей — около 0,5 и ниже
+func Compare(a, b Fingerprint) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	return similarityAt(a, b, bestOffset(a, b))
+}
+
+// bestOffset — сдвиг b относительно a, за который проголосовало больше
+// всего пар суботпечатков с одинаковым ключом. Без совпадений — 0
+func bestOffset(a, b Fingerprint) int {
+	positions := make(map[uint32][]int, len(a))
+	for i, v := range a {
+		key := hash(v)
+		positions[key] = append(positions[key], i)
+	}
+
+	votes := make(map[int]int)
+	best, bestVotes := 0, 0
+	for j, v := range b {
+		for _, i := range positions[hash(v)] {
+			offset := i - j
+			votes[offset]++
+			count := votes[offset]
+			if count > bestVotes || count == bestVotes && abs(offset) < abs(best) {
+				best, bestVotes = offset, count
+			}
+		}
+	}
+	return best
+}
+
+// similarityAt — доля совпавших бит при сдвиге b на offset суботпечатков
+// относительно a
+func similarityAt(a, b Fingerprint, offset int) float64 {
+	var matched int
+	for j := max(0, -offset); j < len(b) && j+offset < len(a); j++ {
+		matched += 32 - bits.OnesCount32(a[j+offset]^b[j])
+	}
+	return float64(matched) / float64(32*max(len(a), len(b)))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Builder строит отпечаток потока сэмплов. Сэмплы каналов чередуются и
+// лежат в диапазоне [-1, 1]
+type Builder struct {
+	channels  int
+	inputRate int
+	// rate — частота после понижения: SampleRate или исходная, если она
+	// ниже
+	rate int
+
+	// phase, sum, count — состояние понижения частоты усреднением
+	phase int
+	sum   float64
+	count int
+
+	frame    []float64
+	window   []float64
+	spectrum []complex128
+	twiddles []complex128
+	// edges — границы полос в отсчетах спектра
+	edges []int
+
+	energies    []float64
+	previous    []float64
+	hasPrevious bool
+
+	fingerprint Fingerprint
+}
+
+// NewBuilder создает построитель отпечатка для потока с частотой
+// sampleRate и числом каналов channels
+func NewBuilder(sampleRate, channels int) (*Builder, error) {
+	if sampleRate < 8000 {
+		return nil, fmt.Errorf("unsupported sample rate %d", sampleRate)
+	}
+	if channels < 1 || channels > 8 {
+		return nil, fmt.Errorf("unsupported channel count %d", channels)
+	}
+	b := &Builder{
+		channels:  channels,
+		inputRate: sampleRate,
+		rate:      min(sampleRate, SampleRate),
+		frame:     make([]float64, 0, frameSize),
+		window:    make([]float64, frameSize),
+		spectrum:  make([]complex128, frameSize),
+		twiddles:  make([]complex128, frameSize/2),
+		edges:     make([]int, bands+1),
+		energies:  make([]float64, bands),
+		previous:  make([]float64, bands),
+	}
+	for i := range b.window {
+		b.window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(frameSize-1)))
+	}
+	for k := range b.twiddles {
+		angle := -2 * math.Pi * float64(k) / frameSize
+		b.twiddles[k] = complex(math.Cos(angle), math.Sin(angle))
+	}
+	for i := range b.edges {
+		frequency := minFrequency * math.Pow(maxFrequency/minFrequency, float64(i)/bands)
+		b.edges[i] = int(math.Round(frequency * frameSize / float64(b.rate)))
+		// На низкой частоте дискретизации в полосу должен попасть хотя бы
+		// один отсчет
+		if i > 0 && b.edges[i] <= b.edges[i-1] {
+			b.edges[i] = b.edges[i-1] + 1
+		}
+	}
+	return b, nil
+}
+
+// Write добавляет сэмплы. Длина samples должна быть кратна числу каналов;
+// неполный последний кадр отбрасывается
+func (b *Builder) Write(samples []float64) {
+	frames := len(samples) / b.channels
+	for f := 0; f < frames; f++ {
+		var mono float64
+		for _, sample := range samples[f*b.channels : (f+1)*b.channels] {
+			mono += sample
+		}
+		b.sum += mono / float64(b.channels)
+		b.count++
+
+		b.phase += b.rate
+		if b.phase >= b.inputRate {
+			b.phase -= b.inputRate
+			b.push(b.sum / float64(b.count))
+			b.sum, b.count = 0, 0
+		}
+	}
+}
+
+// Fingerprint возвращает отпечаток записанных сэмплов. Хвост короче кадра
+// не учитывается; у тишины и записи короче двух кадров отпечаток пуст
+func (b *Builder) Fingerprint() Fingerprint {
+	return b.fingerprint
+}
+
+func (b *Builder) push(sample float64) {
+	b.frame = append(b.frame, sample)
+	if len(b.frame) < frameSize {
+		return
+	}
+	b.processFrame()
+	copy(b.frame, b.frame[hopSize:])
+	b.frame = b.frame[:frameSize-hopSize]
+}
+
+func (b *Builder) processFrame() {
+	var power float64
+	for _, sample := range b.frame {
+		power += sample * sample
+	}
+	// Тишина разрывает последовательность кадров: разности энергий через
+	// паузу ничего не говорят о записи
+	if power/frameSize < silenceLevel {
+		b.hasPrevious = false
+		return
+	}
+
+	for i, sample := range b.frame {
+		b.spectrum[i] = complex(sample*b.window[i], 0)
+	}
+	fft(b.spectrum, b.twiddles)
+	for band := range b.energies {
+		var energy float64
+		for k := b.edges[band]; k < b.edges[band+1] && k < frameSize/2; k++ {
+			re, im := real(b.spectrum[k]), imag(b.spectrum[k])
+			energy += re*re + im*im
+		}
+		b.energies[band] = energy
+	}
+
+	if b.hasPrevious {
+		var v uint32
+		for m := 0; m < bands-1; m++ {
+			current := b.energies[m] - b.energies[m+1]
+			previous := b.previous[m] - b.previous[m+1]
+			if current-previous > 0 {
+				v |= 1 << m
+			}
+		}
+		b.fingerprint = append(b.fingerprint, v)
+	}
+	copy(b.previous, b.energies)
+	b.hasPrevious = true
+}
+
+// fft — быстрое преобразование Фурье по основанию 2 на месте. Длина x —
+// степень двойки, twiddles — поворачивающие множители для нее
+func fft(x, twiddles []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		half, stride := size/2, n/size
+		for start := 0; start < n; start += size {
+			for k := 0; k < half; k++ {
+				u := x[start+k]
+				v := twiddles[k*stride] * x[start+k+half]
+				x[start+k] = u + v
+				x[start+k+half] = u - v
+			}
+		}
+	}
+}
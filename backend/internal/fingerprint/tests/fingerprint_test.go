@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"math"
+	"math/rand"
+	"music-service/internal/fingerprint"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// melody возвращает стерео-запись из случайных аккордов по 250 мс:
+// разные seed дают разные записи
+func melody(seed int64, sampleRate int, seconds float64) []float64 {
+	rnd := rand.New(rand.NewSource(seed))
+	noteFrames := sampleRate / 4
+	frames := int(seconds * float64(sampleRate))
+	samples := make([]float64, 0, frames*2)
+
+	var chord []float64
+	for n := 0; n < frames; n++ {
+		if n%noteFrames == 0 {
+			chord = chord[:0]
+			for i := 0; i < 3; i++ {
+				// Ноты от 220 до 1760 Гц
+				chord = append(chord, 220*math.Pow(2, float64(rnd.Intn(36))/12))
+			}
+		}
+		var v float64
+		for _, frequency := range chord {
+			v += 0.2 * math.Sin(2*math.Pi*frequency*float64(n)/float64(sampleRate))
+		}
+		samples = append(samples, v, v)
+	}
+	return samples
+}
+
+func build(t *testing.T, sampleRate int, samples []float64) fingerprint.Fingerprint {
+	t.Helper()
+	builder, err := fingerprint.NewBuilder(sampleRate, 2)
+	require.NoError(t, err)
+	// Сэмплы передаются частями, как при чтении из потока
+	for len(samples) > 0 {
+		n := min(len(samples), 3000)
+		builder.Write(samples[:n])
+		samples = samples[n:]
+	}
+	return builder.Fingerprint()
+}
+
+func TestCompare(t *testing.T) {
+	original := melody(1, 44100, 20)
+	reference := build(t, 44100, original)
+	require.NotEmpty(t, reference)
+
+	// Та же запись тише на 6 дБ и с шумом -40 dBFS
+	t.Run("gain and noise", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(7))
+		samples := make([]float64, len(original))
+		for i, v := range original {
+			samples[i] = v*0.5 + 0.01*(rnd.Float64()*2-1)
+		}
+		assert.Greater(t, fingerprint.Compare(reference, build(t, 44100, samples)), 0.9)
+	})
+
+	// Та же запись без первых двух секунд: сдвиг находится голосованием.
+	// 5460 кадров 44,1 кГц — один шаг анализа
+	t.Run("offset", func(t *testing.T) {
+		trimmed := original[16*5460*2:]
+		assert.Greater(t, fingerprint.Compare(reference, build(t, 44100, trimmed)), 0.8)
+	})
+
+	// Та же запись с другой частотой дискретизации
+	t.Run("sample rate", func(t *testing.T) {
+		assert.Greater(t, fingerprint.Compare(reference, build(t, 22050, melody(1, 22050, 20))), 0.8)
+	})
+
+	t.Run("different recordings", func(t *testing.T) {
+		other := build(t, 44100, melody(2, 44100, 20))
+		assert.Less(t, fingerprint.Compare(reference, other), 0.65)
+		assert.Less(t, fingerprint.Compare(other, reference), 0.65)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Zero(t, fingerprint.Compare(reference, nil))
+		assert.Empty(t, build(t, 44100, make([]float64, 44100*2*5)))
+	})
+}
+
+func TestHashes(t *testing.T) {
+	reference := build(t, 44100, melody(1, 44100, 10))
+	hashes := reference.Hashes()
+
+	require.NotEmpty(t, hashes)
+	assert.LessOrEqual(t, len(hashes), len(reference))
+	assert.IsIncreasing(t, hashes)
+	for _, h := range hashes {
+		assert.Less(t, h, uint32(1<<20))
+	}
+}
+
+func TestBytes(t *testing.T) {
+	reference := fingerprint.Fingerprint{0, 1, 0xDEADBEEF, math.MaxUint32}
+
+	parsed, err := fingerprint.Parse(reference.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, reference, parsed)
+
+	_, err = fingerprint.Parse([]byte{1, 2, 3})
+	assert.ErrorIs(t, err, fingerprint.ErrInvalidFingerprint)
+}
+
+func TestNewBuilder_InvalidFormat(t *testing.T) {
+	_, err := fingerprint.NewBuilder(4000, 2)
+	assert.Error(t, err)
+	_, err = fingerprint.NewBuilder(44100, 0)
+	assert.Error(t, err)
+}
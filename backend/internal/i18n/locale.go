@@ -0,0 +1,195 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale — язык сообщений API
+type Locale string
+
+const (
+	RU Locale = "ru"
+	EN Locale = "en"
+)
+
+// Default — язык сообщений, если клиент не указал поддерживаемый язык
+const Default = RU
+
+// Supported — языки, на которые переведены сообщения API
+var Supported = []Locale{RU, EN}
+
+// maxTags ограничивает число языков из Accept-Language, которые
+// учитываются при выборе: остальные отбрасываются
+const maxTags = 8
+
+type tagsKey struct{}
+
+// WithTags сохраняет в ctx языки запроса в порядке предпочтения
+func WithTags(ctx context.Context, tags []string) context.Context {
+	return context.WithValue(ctx, tagsKey{}, tags)
+}
+
+// WithPreferred ставит язык из настроек пользователя перед языками запроса
+func WithPreferred(ctx context.Context, tag string) context.Context {
+	if tag == "" {
+		return ctx
+	}
+	tags := []string{tag}
+	for _, t := range Tags(ctx) {
+		if t != tag {
+			tags = append(tags, t)
+		}
+	}
+	return WithTags(ctx, tags)
+}
+
+// Tags возвращает языки запроса в порядке предпочтения (теги BCP 47)
+func Tags(ctx context.Context) []string {
+	tags, _ := ctx.Value(tagsKey{}).([]string)
+	return tags
+}
+
+// FromContext выбирает язык сообщений: первый из языков запроса, который
+// поддерживается, иначе Default
+func FromContext(ctx context.Context) Locale {
+	return Match(Tags(ctx))
+}
+
+// Match возвращает первый поддерживаемый язык из tags или Default
+func Match(tags []string) Locale {
+	for _, tag := range tags {
+		base := Locale(Base(tag))
+		for _, supported := range Supported {
+			if base == supported {
+				return supported
+			}
+		}
+	}
+	return Default
+}
+
+// Fallbacks дополняет теги их основными языками: ["ru-Latn", "en-US"]
+// превращается в ["ru-Latn", "ru", "en-US", "en"]. Так перевод на "ru"
+// подходит запросу "ru-RU", если точного перевода нет
+func Fallbacks(tags []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	for _, tag := range tags {
+		add(tag)
+		add(Base(tag))
+	}
+	return result
+}
+
+// Base возвращает основной язык тега: "ru" для "ru-Latn"
+func Base(tag string) string {
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+// ParseTag проверяет тег вида язык[-письменность][-регион] ("en",
+// "ru-Latn", "pt-BR") и приводит его к каноническому виду: язык строчными,
+// письменность с заглавной, регион заглавными
+func ParseTag(tag string) (string, bool) {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	if len(parts) == 0 || len(parts) > 3 || !isAlpha(parts[0], 2, 3) {
+		return "", false
+	}
+	canonical := strings.ToLower(parts[0])
+	rest := parts[1:]
+	if len(rest) > 0 && isAlpha(rest[0], 4, 4) {
+		script := strings.ToLower(rest[0])
+		canonical += "-" + strings.ToUpper(script[:1]) + script[1:]
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		region := rest[0]
+		if !isAlpha(region, 2, 2) && !isDigits(region, 3) {
+			return "", false
+		}
+		canonical += "-" + strings.ToUpper(region)
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		return "", false
+	}
+	return canonical, true
+}
+
+// ParseAcceptLanguage возвращает языки из заголовка Accept-Language в
+// порядке убывания веса q. Некорректные теги, "*" и языки с q=0
+// пропускаются
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag, ok := ParseTag(fields[0])
+		if !ok {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || name != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil || parsed < 0 || parsed > 1 {
+				parsed = 0
+			}
+			q = parsed
+		}
+		if q > 0 {
+			items = append(items, weighted{tag: tag, q: q})
+		}
+		if len(items) == maxTags {
+			break
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].q > items[j].q })
+
+	tags := make([]string, 0, len(items))
+	for _, item := range items {
+		tags = append(tags, item.tag)
+	}
+	return tags
+}
+
+func isAlpha(s string, minLen, maxLen int) bool {
+	if len(s) < minLen || len(s) > maxLen {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,437 @@
+package i18n
+
+import "fmt"
+
+// catalogs — сообщения по коду на каждом поддерживаемом языке. Коды
+// совпадают с кодами ошибок API; сообщения с параметрами форматируются
+// через fmt
+var catalogs = map[Locale]map[string]string{
+	RU: {
+		// Общие
+		"not_found":              "Ресурс не найден",
+		"invalid_input":          "Некорректный запрос",
+		"unauthorized":           "Необходима авторизация",
+		"unauthenticated":        "Необходима авторизация",
+		"forbidden":              "Доступ запрещен: недостаточно прав",
+		"conflict":               "Конфликт с текущим состоянием ресурса",
+		"precondition_failed":    "Ресурс изменился, обновите данные и повторите запрос",
+		"too_many_requests":      "Слишком много запросов, попробуйте позже",
+		"rate_limited":           "Слишком много запросов, попробуйте позже",
+		"too_many_attempts":      "Слишком много неудачных попыток, попробуйте позже",
+		"payload_too_large":      "Слишком большой запрос",
+		"unavailable":            "Сервис временно недоступен",
+		"request_timeout":        "Сервер не успел обработать запрос",
+		"internal_error":         "Внутренняя ошибка сервера",
+		"route_not_found":        "Маршрут не найден",
+		"method_not_allowed":     "Метод не поддерживается",
+		"invalid_request_body":   "Некорректное тело запроса",
+		"conflicting_parameters": "Параметры нельзя указывать одновременно",
+		"file_required":          "Файл не передан",
+		"unsupported_file_type":  "Неподдерживаемый тип файла",
+
+		// Проверка запросов
+		"validation_failed": "Запрос содержит некорректные поля",
+		"required":          "Обязательное поле",
+		"too_short":         "Должно быть не короче %d символов",
+		"too_long":          "Должно быть не длиннее %d символов",
+		"too_small":         "Должно быть не меньше %d",
+		"too_large":         "Должно быть не больше %d",
+		"too_few":           "Должно быть не меньше %d элементов",
+		"too_many":          "Должно быть не больше %d элементов",
+		"invalid_uuid":      "Некорректный идентификатор",
+		"invalid_url":       "Некорректный URL",
+		"invalid_choice":    "Недопустимое значение. Допустимые значения: %s",
+		"invalid_duration":  "Некорректная длительность, ожидается формат вида 72h",
+		"invalid_type":      "Некорректный тип значения",
+		"invalid_number":    "Ожидается целое число",
+		"invalid_datetime":  "Ожидается дата и время в формате RFC 3339",
+		"invalid_date":      "Ожидается дата в формате ГГГГ-ММ-ДД",
+
+		// Пользователи и вход
+		"user_not_found":        "Пользователь не найден",
+		"login_taken":           "Пользователь с таким логином уже существует",
+		"email_taken":           "Email уже используется",
+		"login_too_short":       "Логин должен содержать не менее 6 символов",
+		"email_too_long":        "Слишком длинный email",
+		"invalid_email":         "Некорректный формат email",
+		"email_required":        "Укажите email",
+		"invalid_permission":    "Неизвестное право доступа",
+		"invalid_credentials":   "Неверный логин или пароль",
+		"staff_sso_required":    "Сотрудники входят только через корпоративный SSO",
+		"invalid_refresh_token": "Недействительный refresh-токен",
+		"refresh_token_reused":  "Refresh-токен уже использован, войдите заново",
+		"missing_token":         "Токен доступа не передан",
+		"malformed_token":       "Некорректный заголовок авторизации",
+		"invalid_token":         "Недействительный токен доступа",
+		"session_not_found":     "Сессия не найдена",
+		"account_suspended":     "Учетная запись заблокирована",
+
+		// Пароли
+		"password_too_short":       "Пароль должен содержать не менее %d символов",
+		"password_too_long":        "Пароль слишком длинный (не более %d байт)",
+		"password_no_letter":       "Пароль должен содержать букву",
+		"password_no_digit":        "Пароль должен содержать цифру",
+		"password_contains_login":  "Пароль не должен содержать логин",
+		"password_breached":        "Этот пароль встречается в утечках данных, выберите другой",
+		"invalid_current_password": "Неверный текущий пароль",
+		"password_unchanged":       "Новый пароль должен отличаться от текущего",
+		"invalid_reset_token":      "Ссылка для сброса пароля недействительна или устарела",
+		"mail_unavailable":         "Не удалось отправить письмо, попробуйте позже",
+
+		// Двухфакторная аутентификация
+		"invalid_mfa_token":          "Сессия подтверждения входа истекла, войдите заново",
+		"invalid_verification_code":  "Неверный код подтверждения",
+		"mfa_enrollment_not_started": "Подключение двухфакторной аутентификации не начато",
+		"mfa_already_enabled":        "Двухфакторная аутентификация уже включена",
+		"mfa_not_enabled":            "Двухфакторная аутентификация не включена",
+		"mfa_required":               "Для этой роли обязательна двухфакторная аутентификация",
+		"invalid_role":               "Неизвестная роль",
+
+		// Внешние провайдеры входа
+		"unknown_provider":        "Неизвестный провайдер входа",
+		"provider_unavailable":    "Провайдер входа недоступен",
+		"invalid_login_state":     "Некорректное состояние входа, начните вход заново",
+		"external_auth_failed":    "Не удалось войти через внешний провайдер",
+		"external_auth_cancelled": "Вход через внешний провайдер отменен",
+		"identity_linked":         "Учетная запись провайдера привязана к другому пользователю",
+		"identity_not_found":      "Привязка не найдена",
+		"last_sign_in_method":     "Нельзя отвязать единственный способ входа",
+
+		// Администрирование
+		"invalid_status":             "Неизвестный статус",
+		"suspension_reason_required": "Укажите причину блокировки",
+		"suspension_reason_too_long": "Слишком длинная причина блокировки",
+		"suspension_end_in_past":     "Срок блокировки должен быть в будущем",
+		"cannot_suspend_self":        "Нельзя заблокировать самого себя",
+		"user_not_suspended":         "Пользователь не заблокирован",
+		"invalid_time_range":         "Некорректный интервал времени",
+
+		// Треки
+		"track_not_found":         "Трек не найден",
+		"track_file_not_found":    "Файл трека не найден",
+		"track_too_large":         "Слишком большой файл трека",
+		"track_album_not_found":   "Альбом не найден",
+		"track_title_required":    "Укажите название трека",
+		"track_title_too_long":    "Слишком длинное название трека",
+		"track_artist_required":   "Укажите исполнителя",
+		"track_album_required":    "Укажите альбом",
+		"track_modified":          "Трек изменили после того, как вы его открыли. Обновите данные и повторите",
+		"invalid_isrc":            "Некорректный ISRC, ожидается код вида RU-A12-24-00001",
+		"cover_not_found":         "Обложка не найдена",
+		"cover_file_required":     "Выберите файл с изображением обложки",
+		"unsupported_image":       "Обложка должна быть изображением JPEG, PNG или GIF",
+		"image_too_large":         "Слишком большое разрешение изображения",
+		"invalid_cover_size":      "Такого размера обложки нет",
+		"lyrics_not_found":        "Текст песни не найден",
+		"lyrics_empty":            "Введите текст песни",
+		"lyrics_too_long":         "Слишком длинный текст песни",
+		"lyrics_exist":            "У трека уже есть текст песни",
+		"invalid_lyrics_status":   "Некорректный статус текста песни",
+		"loudness_unavailable":    "Не удалось измерить громкость этого файла",
+		"track_silent":            "В треке нет звука, громкость не определена",
+		"duplicate_track":         "Такой трек уже есть в каталоге",
+		"fingerprint_unavailable": "Не удалось построить акустический отпечаток этого файла",
+		"merge_sources_required":  "Выберите треки для объединения",
+		"merge_target_in_sources": "Трек нельзя объединить с самим собой",
+		"merge_sources_limit":     "Слишком много треков для объединения",
+		"search_query_too_short":  "Поисковый запрос должен содержать не менее 3 символов",
+		"track_too_short":         "Трек слишком короткий для учета прослушивания",
+		"played_too_frequently":   "Трек прослушивается слишком часто",
+
+		// Альбомы
+		"album_not_found":        "Альбом не найден",
+		"album_title_too_short":  "Название альбома должно содержать не менее 2 символов",
+		"album_title_too_long":   "Название альбома слишком длинное (не более 100 символов)",
+		"artist_name_required":   "Укажите исполнителя",
+		"artist_name_too_long":   "Имя исполнителя слишком длинное (не более 100 символов)",
+		"release_date_in_future": "Дата релиза не может быть в будущем",
+		"invalid_cover_url":      "Некорректный адрес обложки",
+		"album_exists":           "Альбом с таким названием и исполнителем уже существует",
+		"track_in_another_album": "Трек уже входит в другой альбом",
+		"track_already_in_album": "Трек уже есть в альбоме",
+		"album_track_limit":      "В альбоме не может быть больше 50 треков",
+		"track_not_in_album":     "Трек не входит в этот альбом",
+
+		// Жанры
+		"genre_not_found":        "Жанр не найден",
+		"genre_name_too_short":   "Название жанра должно содержать не менее 2 символов",
+		"genre_name_too_long":    "Название жанра слишком длинное (не более 50 символов)",
+		"genre_exists":           "Такой жанр уже существует",
+		"genre_already_assigned": "Жанр уже назначен треку",
+		"track_genre_limit":      "У трека не может быть больше 5 жанров",
+		"genre_not_assigned":     "Жанр не назначен этому треку",
+
+		// Плейлисты
+		"playlist_not_found":            "Плейлист не найден",
+		"playlist_name_too_short":       "Название плейлиста должно содержать не менее 2 символов",
+		"playlist_name_too_long":        "Слишком длинное название плейлиста",
+		"playlist_description_too_long": "Слишком длинное описание плейлиста",
+		"track_already_in_playlist":     "Трек уже есть в плейлисте",
+		"playlist_track_limit":          "Достигнут лимит треков в плейлисте",
+		"track_not_in_playlist":         "Трека нет в плейлисте",
+		"playlist_access_denied":        "Доступ к плейлисту запрещен",
+		"cannot_follow_own_playlist":    "Нельзя подписаться на свой плейлист",
+
+		// Воспроизведение
+		"queue_too_long":         "Слишком длинная очередь",
+		"invalid_start_index":    "Некорректный начальный индекс",
+		"invalid_queue_position": "Некорректная позиция в очереди",
+		"invalid_queue_index":    "Некорректный индекс в очереди",
+		"position_out_of_bounds": "Позиция выходит за пределы трека",
+		"invalid_repeat_mode":    "Неизвестный режим повтора",
+		"queue_empty":            "Очередь пуста",
+		"device_not_found":       "Устройство не найдено",
+
+		// Корзина
+		"invalid_trash_type":   "Неизвестный тип объекта",
+		"trash_item_not_found": "Объект не найден в корзине",
+		"album_deleted":        "Сначала восстановите альбом",
+		"owner_deleted":        "Сначала восстановите владельца",
+
+		// Переводы каталога
+		"invalid_locale":            "Некорректный код языка",
+		"invalid_translation_type":  "Этот объект нельзя перевести",
+		"translation_empty":         "Перевод должен содержать хотя бы одно поле",
+		"unknown_translation_field": "Это поле нельзя перевести",
+		"translation_too_long":      "Перевод слишком длинный (не более 255 символов)",
+		"translation_not_found":     "Перевод не найден",
+
+		// Сообщения об успешных действиях
+		"playlist_updated":       "Информация о плейлисте обновлена",
+		"playlist_track_added":   "Трек добавлен в плейлист",
+		"playlist_track_removed": "Трек удален из плейлиста",
+		"playlist_deleted":       "Плейлист успешно удален",
+
+		// Письма
+		"mail_password_reset_subject": "Сброс пароля",
+		"mail_password_reset_body": "Здравствуйте, %s!\n\nЧтобы задать новый пароль, перейдите по ссылке:\n%s\n\n" +
+			"Ссылка действует %s. Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.\n",
+	},
+	EN: {
+		// Общие
+		"not_found":              "Resource not found",
+		"invalid_input":          "Invalid request",
+		"unauthorized":           "Authentication required",
+		"unauthenticated":        "Authentication required",
+		"forbidden":              "Access denied: insufficient permissions",
+		"conflict":               "Request conflicts with the current state of the resource",
+		"precondition_failed":    "The resource has changed, reload it and try again",
+		"too_many_requests":      "Too many requests, please try again later",
+		"rate_limited":           "Too many requests, please try again later",
+		"too_many_attempts":      "Too many failed attempts, please try again later",
+		"payload_too_large":      "Request is too large",
+		"unavailable":            "Service is temporarily unavailable",
+		"request_timeout":        "The server did not finish the request in time",
+		"internal_error":         "Internal server error",
+		"route_not_found":        "Route not found",
+		"method_not_allowed":     "Method not allowed",
+		"invalid_request_body":   "Invalid request body",
+		"conflicting_parameters": "These parameters cannot be used together",
+		"file_required":          "File is required",
+		"unsupported_file_type":  "Unsupported file type",
+
+		// Проверка запросов
+		"validation_failed": "The request contains invalid fields",
+		"required":          "This field is required",
+		"too_short":         "Must be at least %d characters long",
+		"too_long":          "Must be at most %d characters long",
+		"too_small":         "Must be at least %d",
+		"too_large":         "Must be at most %d",
+		"too_few":           "Must contain at least %d items",
+		"too_many":          "Must contain at most %d items",
+		"invalid_uuid":      "Invalid identifier",
+		"invalid_url":       "Invalid URL",
+		"invalid_choice":    "Invalid value. Allowed values: %s",
+		"invalid_duration":  "Invalid duration, expected a value like 72h",
+		"invalid_type":      "Invalid value type",
+		"invalid_number":    "An integer is expected",
+		"invalid_datetime":  "An RFC 3339 date and time is expected",
+		"invalid_date":      "A date in YYYY-MM-DD format is expected",
+
+		// Пользователи и вход
+		"user_not_found":        "User not found",
+		"login_taken":           "A user with this login already exists",
+		"email_taken":           "Email is already in use",
+		"login_too_short":       "Login must be at least 6 characters",
+		"email_too_long":        "Email is too long",
+		"invalid_email":         "Invalid email format",
+		"email_required":        "Email is required",
+		"invalid_permission":    "Unknown permission",
+		"invalid_credentials":   "Invalid login or password",
+		"staff_sso_required":    "Staff accounts must sign in with company SSO",
+		"invalid_refresh_token": "Invalid refresh token",
+		"refresh_token_reused":  "Refresh token has already been used, please sign in again",
+		"missing_token":         "Access token is required",
+		"malformed_token":       "Malformed authorization header",
+		"invalid_token":         "Invalid access token",
+		"session_not_found":     "Session not found",
+		"account_suspended":     "Account is suspended",
+
+		// Пароли
+		"password_too_short":       "Password must be at least %d characters",
+		"password_too_long":        "Password is too long (max %d bytes)",
+		"password_no_letter":       "Password must contain a letter",
+		"password_no_digit":        "Password must contain a digit",
+		"password_contains_login":  "Password must not contain the login",
+		"password_breached":        "This password has appeared in a data breach, choose another one",
+		"invalid_current_password": "Invalid current password",
+		"password_unchanged":       "New password must differ from the current one",
+		"invalid_reset_token":      "Password reset link is invalid or expired",
+		"mail_unavailable":         "Failed to send the email, please try again later",
+
+		// Двухфакторная аутентификация
+		"invalid_mfa_token":          "Sign-in confirmation has expired, please sign in again",
+		"invalid_verification_code":  "Invalid verification code",
+		"mfa_enrollment_not_started": "Two-factor enrollment has not been started",
+		"mfa_already_enabled":        "Two-factor authentication is already enabled",
+		"mfa_not_enabled":            "Two-factor authentication is not enabled",
+		"mfa_required":               "Two-factor authentication is required for this role",
+		"invalid_role":               "Unknown role",
+
+		// Внешние провайдеры входа
+		"unknown_provider":        "Unknown identity provider",
+		"provider_unavailable":    "Identity provider is unavailable",
+		"invalid_login_state":     "Invalid sign-in state, please start again",
+		"external_auth_failed":    "External sign-in failed",
+		"external_auth_cancelled": "External sign-in was cancelled",
+		"identity_linked":         "This provider account is linked to another user",
+		"identity_not_found":      "Linked account not found",
+		"last_sign_in_method":     "Cannot unlink the only sign-in method",
+
+		// Администрирование
+		"invalid_status":             "Unknown status",
+		"suspension_reason_required": "Suspension reason is required",
+		"suspension_reason_too_long": "Suspension reason is too long",
+		"suspension_end_in_past":     "Suspension end must be in the future",
+		"cannot_suspend_self":        "You cannot suspend yourself",
+		"user_not_suspended":         "User is not suspended",
+		"invalid_time_range":         "Invalid time range",
+
+		// Треки
+		"track_not_found":         "Track not found",
+		"track_file_not_found":    "Track file not found",
+		"track_too_large":         "The track file is too large",
+		"track_album_not_found":   "Album not found",
+		"track_title_required":    "Track title is required",
+		"track_title_too_long":    "Track title is too long",
+		"track_artist_required":   "Artist is required",
+		"track_album_required":    "Album is required",
+		"track_modified":          "The track was changed after you opened it. Reload it and try again",
+		"invalid_isrc":            "Invalid ISRC, expected a code like US-S1Z-99-00001",
+		"cover_not_found":         "Cover not found",
+		"cover_file_required":     "Choose a cover image file",
+		"unsupported_image":       "The cover must be a JPEG, PNG or GIF image",
+		"image_too_large":         "The image resolution is too large",
+		"invalid_cover_size":      "This cover size is not available",
+		"lyrics_not_found":        "Lyrics not found",
+		"lyrics_empty":            "Enter the lyrics",
+		"lyrics_too_long":         "Lyrics are too long",
+		"lyrics_exist":            "The track already has lyrics",
+		"invalid_lyrics_status":   "Invalid lyrics status",
+		"loudness_unavailable":    "Loudness cannot be measured for this file",
+		"track_silent":            "The track is silent, loudness is undefined",
+		"duplicate_track":         "This track is already in the catalog",
+		"fingerprint_unavailable": "An acoustic fingerprint cannot be computed for this file",
+		"merge_sources_required":  "Select tracks to merge",
+		"merge_target_in_sources": "A track cannot be merged into itself",
+		"merge_sources_limit":     "Too many tracks to merge",
+		"search_query_too_short":  "Search query must be at least 3 characters",
+		"track_too_short":         "Track is too short to record playback",
+		"played_too_frequently":   "Track is played too frequently",
+
+		// Альбомы
+		"album_not_found":        "Album not found",
+		"album_title_too_short":  "Album title must be at least 2 characters",
+		"album_title_too_long":   "Album title is too long (max 100 characters)",
+		"artist_name_required":   "Artist is required",
+		"artist_name_too_long":   "Artist name is too long (max 100 characters)",
+		"release_date_in_future": "Release date cannot be in the future",
+		"invalid_cover_url":      "Invalid cover URL",
+		"album_exists":           "An album with this title and artist already exists",
+		"track_in_another_album": "Track already belongs to another album",
+		"track_already_in_album": "Track is already in the album",
+		"album_track_limit":      "An album cannot contain more than 50 tracks",
+		"track_not_in_album":     "Track does not belong to this album",
+
+		// Жанры
+		"genre_not_found":        "Genre not found",
+		"genre_name_too_short":   "Genre name must be at least 2 characters",
+		"genre_name_too_long":    "Genre name is too long (max 50 characters)",
+		"genre_exists":           "Genre already exists",
+		"genre_already_assigned": "Genre is already assigned to the track",
+		"track_genre_limit":      "A track cannot have more than 5 genres",
+		"genre_not_assigned":     "Genre is not assigned to this track",
+
+		// Плейлисты
+		"playlist_not_found":            "Playlist not found",
+		"playlist_name_too_short":       "Playlist name must be at least 2 characters",
+		"playlist_name_too_long":        "Playlist name is too long",
+		"playlist_description_too_long": "Playlist description is too long",
+		"track_already_in_playlist":     "Track is already in the playlist",
+		"playlist_track_limit":          "Playlist track limit reached",
+		"track_not_in_playlist":         "Track is not in the playlist",
+		"playlist_access_denied":        "Access to the playlist is denied",
+		"cannot_follow_own_playlist":    "You cannot follow your own playlist",
+
+		// Воспроизведение
+		"queue_too_long":         "Queue is too long",
+		"invalid_start_index":    "Invalid start index",
+		"invalid_queue_position": "Invalid queue position",
+		"invalid_queue_index":    "Invalid queue index",
+		"position_out_of_bounds": "Position is out of track bounds",
+		"invalid_repeat_mode":    "Unknown repeat mode",
+		"queue_empty":            "Queue is empty",
+		"device_not_found":       "Device not found",
+
+		// Корзина
+		"invalid_trash_type":   "Unknown item type",
+		"trash_item_not_found": "Item not found in trash",
+		"album_deleted":        "Restore the album first",
+		"owner_deleted":        "Restore the owner first",
+
+		// Переводы каталога
+		"invalid_locale":            "Invalid language tag",
+		"invalid_translation_type":  "This item cannot be translated",
+		"translation_empty":         "Translation must contain at least one field",
+		"unknown_translation_field": "This field cannot be translated",
+		"translation_too_long":      "Translation is too long (max 255 characters)",
+		"translation_not_found":     "Translation not found",
+
+		// Сообщения об успешных действиях
+		"playlist_updated":       "Playlist updated",
+		"playlist_track_added":   "Track added to the playlist",
+		"playlist_track_removed": "Track removed from the playlist",
+		"playlist_deleted":       "Playlist deleted",
+
+		// Письма
+		"mail_password_reset_subject": "Password reset",
+		"mail_password_reset_body": "Hello, %s!\n\nTo set a new password, follow the link:\n%s\n\n" +
+			"The link is valid for %s. If you did not request a password reset, just ignore this email.\n",
+	},
+}
+
+// Message возвращает сообщение с кодом code на языке locale, подставляя
+// args. Если перевода нет, используется Default, если нет и его — пустая
+// строка
+func Message(locale Locale, code string, args ...interface{}) string {
+	msg, ok := catalogs[locale][code]
+	if !ok {
+		msg, ok = catalogs[Default][code]
+	}
+	if !ok {
+		return ""
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Codes возвращает коды сообщений языка locale
+func Codes(locale Locale) []string {
+	codes := make([]string, 0, len(catalogs[locale]))
+	for code := range catalogs[locale] {
+		codes = append(codes, code)
+	}
+	return codes
+}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/i18n"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{"пустой заголовок", "", []string{}},
+		{"один язык", "en", []string{"en"}},
+		{"сортировка по весу", "ru;q=0.5, en-US, en;q=0.8", []string{"en-US", "en", "ru"}},
+		{"равные веса сохраняют порядок", "de, fr, en", []string{"de", "fr", "en"}},
+		{"нулевой вес и звездочка пропускаются", "ru;q=0, *, en", []string{"en"}},
+		{"некорректные теги пропускаются", "x, en-123456, ru_ru", []string{"ru-RU"}},
+		{"канонический вид", "SR-latn-rs", []string{"sr-Latn-RS"}},
+		{"некорректный вес", "en;q=abc, ru", []string{"ru"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, i18n.ParseAcceptLanguage(tc.header))
+		})
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		tag  string
+		want string
+		ok   bool
+	}{
+		{"en", "en", true},
+		{"ru-Latn", "ru-Latn", true},
+		{"pt-br", "pt-BR", true},
+		{"es-419", "es-419", true},
+		{"", "", false},
+		{"english", "", false},
+		{"en-US-x", "", false},
+		{"e1", "", false},
+	}
+	for _, tc := range cases {
+		got, ok := i18n.ParseTag(tc.tag)
+		assert.Equal(t, tc.ok, ok, tc.tag)
+		assert.Equal(t, tc.want, got, tc.tag)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	// Первый поддерживаемый язык по основному языку тега
+	assert.Equal(t, i18n.EN, i18n.Match([]string{"de", "en-GB", "ru"}))
+	// Транслитерация русского — это русский
+	assert.Equal(t, i18n.RU, i18n.Match([]string{"ru-Latn"}))
+	// Неподдерживаемые языки
+	assert.Equal(t, i18n.Default, i18n.Match([]string{"de", "fr"}))
+	assert.Equal(t, i18n.Default, i18n.Match(nil))
+}
+
+func TestFallbacks(t *testing.T) {
+	assert.Equal(t,
+		[]string{"ru-Latn", "ru", "en-US", "en"},
+		i18n.Fallbacks([]string{"ru-Latn", "en-US", "en"}))
+	assert.Empty(t, i18n.Fallbacks(nil))
+}
+
+func TestWithPreferred(t *testing.T) {
+	ctx := i18n.WithTags(context.Background(), []string{"ru", "en"})
+
+	// Язык пользователя важнее языков запроса
+	preferred := i18n.WithPreferred(ctx, "en")
+	assert.Equal(t, []string{"en", "ru"}, i18n.Tags(preferred))
+	assert.Equal(t, i18n.EN, i18n.FromContext(preferred))
+
+	// Без выбора пользователя остаются языки запроса
+	assert.Equal(t, []string{"ru", "en"}, i18n.Tags(i18n.WithPreferred(ctx, "")))
+	assert.Equal(t, i18n.Default, i18n.FromContext(context.Background()))
+}
+
+func TestMessage(t *testing.T) {
+	assert.Equal(t, "Track not found", i18n.Message(i18n.EN, "track_not_found"))
+	assert.Equal(t, "Трек не найден", i18n.Message(i18n.RU, "track_not_found"))
+	// Неизвестный язык — язык по умолчанию, неизвестный код — пустая строка
+	assert.Equal(t, "Трек не найден", i18n.Message("de", "track_not_found"))
+	assert.Equal(t, "", i18n.Message(i18n.EN, "no_such_code"))
+}
+
+func TestCatalogsHaveSameCodes(t *testing.T) {
+	ru := i18n.Codes(i18n.RU)
+	en := i18n.Codes(i18n.EN)
+	sort.Strings(ru)
+	sort.Strings(en)
+	assert.Equal(t, ru, en)
+}
@@ -0,0 +1,79 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"sync"
+)
+
+// EncodeFunc кодирует изображение в свой формат
+type EncodeFunc func(w io.Writer, img image.Image) error
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[Format]EncodeFunc{
+		JPEG: func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+		},
+		PNG: png.Encode,
+	}
+)
+
+// RegisterEncoder добавляет кодировщик формата. В стандартной библиотеке
+// нет кодировщика WebP: чтобы отдавать обложки в WebP, его нужно
+// зарегистрировать при запуске сервиса
+func RegisterEncoder(format Format, encode EncodeFunc) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	encoders[format] = encode
+}
+
+// CanEncode сообщает, есть ли кодировщик формата
+func CanEncode(format Format) bool {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	_, ok := encoders[format]
+	return ok
+}
+
+// Encode кодирует изображение в format
+func Encode(w io.Writer, img image.Image, format Format) error {
+	encodersMu.RLock()
+	encode, ok := encoders[format]
+	encodersMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no encoder for image format %q", format)
+	}
+	return encode(w, img)
+}
+
+// Negotiate выбирает из formats (в порядке предпочтения сервера) первый
+// формат, который клиент явно перечислил в заголовке Accept. Маски image/*
+// и */* не учитываются: браузеры без поддержки WebP тоже их присылают. Если
+// подходящего формата нет, возвращается последний — запасной вариант,
+// который понимают все клиенты
+func Negotiate(accept string, formats []Format) Format {
+	if len(formats) == 0 {
+		return JPEG
+	}
+
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		if strings.ReplaceAll(strings.TrimSpace(params), " ", "") == "q=0" {
+			continue
+		}
+		accepted[strings.ToLower(strings.TrimSpace(mediaType))] = true
+	}
+
+	for _, format := range formats {
+		if accepted[format.ContentType()] {
+			return format
+		}
+	}
+	return formats[len(formats)-1]
+}
@@ -0,0 +1,107 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+// Format — формат закодированного изображения
+type Format string
+
+const (
+	JPEG Format = "jpeg"
+	PNG  Format = "png"
+	GIF  Format = "gif"
+	WebP Format = "webp"
+)
+
+var (
+	// ErrUnsupportedFormat — данные не являются изображением поддерживаемого
+	// формата или повреждены
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrTooLarge — в изображении больше пикселей, чем разрешено
+	ErrTooLarge = errors.New("image is too large")
+)
+
+// ContentType возвращает MIME-тип формата
+func (f Format) ContentType() string {
+	return "image/" + string(f)
+}
+
+// Extension возвращает расширение файла без точки
+func (f Format) Extension() string {
+	if f == JPEG {
+		return "jpg"
+	}
+	return string(f)
+}
+
+// Detect определяет формат по сигнатуре данных. Расширение имени файла и
+// Content-Type, присланные клиентом, не учитываются
+func Detect(data []byte) (Format, bool) {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return JPEG, true
+	case "image/png":
+		return PNG, true
+	case "image/gif":
+		return GIF, true
+	case "image/webp":
+		return WebP, true
+	}
+	return "", false
+}
+
+// Decode декодирует изображение, поворачивает его по EXIF-ориентации и
+// накладывает прозрачные области на белый фон. Результат не содержит
+// метаданных исходного файла (EXIF, ICC, комментариев): они отбрасываются
+// при перекодировании. maxPixels ограничивает размер до декодирования, чтобы
+// маленький файл не развернулся в гигабайты памяти; 0 — без ограничения
+func Decode(data []byte, maxPixels int) (*image.RGBA, Format, error) {
+	format, ok := Detect(data)
+	if !ok || !canDecode(format) {
+		return nil, "", ErrUnsupportedFormat
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if maxPixels > 0 && config.Width*config.Height > maxPixels {
+		return nil, "", ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+
+	rgba := flatten(img)
+	if format == JPEG {
+		rgba = orient(rgba, jpegOrientation(data))
+	}
+	return rgba, format, nil
+}
+
+// canDecode сообщает, зарегистрирован ли декодер формата. Декодера WebP в
+// стандартной библиотеке нет
+func canDecode(format Format) bool {
+	return format == JPEG || format == PNG || format == GIF
+}
+
+// flatten переводит изображение в RGBA с началом координат в (0, 0) и
+// белым фоном под прозрачными областями
+func flatten(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Over)
+	return dst
+}
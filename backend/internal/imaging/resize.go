@@ -0,0 +1,137 @@
+package imaging
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// Square вырезает из центра изображения квадрат и уменьшает его до стороны
+// size. Изображение меньше size не увеличивается
+func Square(src *image.RGBA, size int) *image.RGBA {
+	crop := cropSquare(src)
+	if side := crop.Bounds().Dx(); size > side {
+		size = side
+	}
+	return Resize(crop, size, size)
+}
+
+// cropSquare возвращает центральный квадрат изображения
+func cropSquare(src *image.RGBA) *image.RGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == h {
+		return src
+	}
+	side := min(w, h)
+	x0 := bounds.Min.X + (w-side)/2
+	y0 := bounds.Min.Y + (h-side)/2
+	return src.SubImage(image.Rect(x0, y0, x0+side, y0+side)).(*image.RGBA)
+}
+
+// Resize масштабирует изображение до w×h усреднением по площади: каждый
+// пиксель результата — среднее покрываемых им исходных пикселей. Для
+// уменьшения в разы это дает заметно меньше ступенек, чем билинейная
+// интерполяция
+func Resize(src *image.RGBA, w, h int) *image.RGBA {
+	bounds := src.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if w <= 0 || h <= 0 || sw == 0 || sh == 0 {
+		return dst
+	}
+	if w == sw && h == sh {
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+		return dst
+	}
+
+	xWeights := areaWeights(sw, w)
+	yWeights := areaWeights(sh, h)
+
+	// Первый проход — по горизонтали: sh строк по w пикселей
+	rows := make([]float64, sh*w*4)
+	for y := 0; y < sh; y++ {
+		line := src.Pix[src.PixOffset(bounds.Min.X, bounds.Min.Y+y):]
+		for x, weights := range xWeights {
+			var acc [4]float64
+			for _, wt := range weights {
+				p := line[wt.index*4:]
+				acc[0] += float64(p[0]) * wt.value
+				acc[1] += float64(p[1]) * wt.value
+				acc[2] += float64(p[2]) * wt.value
+				acc[3] += float64(p[3]) * wt.value
+			}
+			copy(rows[(y*w+x)*4:], acc[:])
+		}
+	}
+
+	// Второй проход — по вертикали
+	for y, weights := range yWeights {
+		for x := 0; x < w; x++ {
+			var acc [4]float64
+			for _, wt := range weights {
+				p := rows[(wt.index*w+x)*4:]
+				acc[0] += p[0] * wt.value
+				acc[1] += p[1] * wt.value
+				acc[2] += p[2] * wt.value
+				acc[3] += p[3] * wt.value
+			}
+			offset := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				dst.Pix[offset+c] = clamp(acc[c])
+			}
+		}
+	}
+	return dst
+}
+
+type weight struct {
+	index int
+	value float64
+}
+
+// areaWeights для каждого из m пикселей результата возвращает доли n
+// исходных пикселей, которые он покрывает. Сумма весов пикселя равна 1
+func areaWeights(n, m int) [][]weight {
+	scale := float64(n) / float64(m)
+	result := make([][]weight, m)
+	for i := range result {
+		start, end := float64(i)*scale, float64(i+1)*scale
+		for j := int(start); j < n && float64(j) < end; j++ {
+			overlap := math.Min(end, float64(j+1)) - math.Max(start, float64(j))
+			if overlap > 0 {
+				result[i] = append(result[i], weight{index: j, value: overlap / scale})
+			}
+		}
+	}
+	return result
+}
+
+func clamp(v float64) uint8 {
+	v = math.Round(v)
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
+
+// Mosaic собирает квадрат size×size из четырех изображений сеткой 2×2:
+// слева направо, сверху вниз
+func Mosaic(tiles [4]*image.RGBA, size int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	half := size / 2
+	cells := [4]image.Rectangle{
+		image.Rect(0, 0, half, half),
+		image.Rect(half, 0, size, half),
+		image.Rect(0, half, half, size),
+		image.Rect(half, half, size, size),
+	}
+	for i, cell := range cells {
+		tile := Resize(cropSquare(tiles[i]), cell.Dx(), cell.Dy())
+		draw.Draw(dst, cell, tile, image.Point{}, draw.Src)
+	}
+	return dst
+}
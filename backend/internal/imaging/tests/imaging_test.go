@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"music-service/internal/imaging"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	red  = color.RGBA{R: 255, A: 255}
+	blue = color.RGBA{B: 255, A: 255}
+)
+
+// halves возвращает изображение w×h: левая половина красная, правая синяя
+func halves(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, image.Rect(0, 0, w/2, h), image.NewUniform(red), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(w/2, 0, w, h), image.NewUniform(blue), image.Point{}, draw.Src)
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}))
+	return buf.Bytes()
+}
+
+// withOrientation вставляет после SOI сегмент APP1 с EXIF, в котором задан
+// только тег Orientation
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	ifd := make([]byte, 2+12+4)
+	binary.BigEndian.PutUint16(ifd[0:], 1)
+	binary.BigEndian.PutUint16(ifd[2:], 0x0112)
+	binary.BigEndian.PutUint16(ifd[4:], 3) // SHORT
+	binary.BigEndian.PutUint32(ifd[6:], 1)
+	binary.BigEndian.PutUint16(ifd[10:], orientation)
+	payload := append([]byte("Exif\x00\x00"), append(tiff, ifd...)...)
+
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	result := append([]byte{}, data[:2]...)
+	result = append(result, segment...)
+	return append(result, data[2:]...)
+}
+
+// near проверяет цвет пикселя с допуском на потери JPEG
+func near(t *testing.T, want color.RGBA, got color.Color) {
+	t.Helper()
+	r, g, b, _ := got.RGBA()
+	diff := func(a uint8, b uint32) int { return abs(int(a) - int(b>>8)) }
+	assert.True(t, diff(want.R, r) < 40 && diff(want.G, g) < 40 && diff(want.B, b) < 40,
+		"ожидался цвет %v, получен %v", want, got)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func TestDetect(t *testing.T) {
+	format, ok := imaging.Detect(encodeJPEG(t, halves(8, 8)))
+	assert.True(t, ok)
+	assert.Equal(t, imaging.JPEG, format)
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, halves(8, 8)))
+	format, ok = imaging.Detect(buf.Bytes())
+	assert.True(t, ok)
+	assert.Equal(t, imaging.PNG, format)
+
+	_, ok = imaging.Detect([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"/>"))
+	assert.False(t, ok)
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("ориентация из EXIF", func(t *testing.T) {
+		// Поворот на 90° по часовой стрелке: левая (красная) половина
+		// оказывается сверху
+		img, format, err := imaging.Decode(withOrientation(encodeJPEG(t, halves(32, 16)), 6), 0)
+		require.NoError(t, err)
+		assert.Equal(t, imaging.JPEG, format)
+		assert.Equal(t, image.Rect(0, 0, 16, 32), img.Bounds())
+		near(t, red, img.At(8, 4))
+		near(t, blue, img.At(8, 28))
+	})
+
+	t.Run("EXIF не попадает в перекодированный файл", func(t *testing.T) {
+		data := withOrientation(encodeJPEG(t, halves(16, 16)), 1)
+		require.True(t, bytes.Contains(data, []byte("Exif")))
+
+		img, _, err := imaging.Decode(data, 0)
+		require.NoError(t, err)
+		var out bytes.Buffer
+		require.NoError(t, imaging.Encode(&out, img, imaging.JPEG))
+		assert.False(t, bytes.Contains(out.Bytes(), []byte("Exif")))
+	})
+
+	t.Run("прозрачность заменяется белым фоном", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 4, 4))))
+		img, format, err := imaging.Decode(buf.Bytes(), 0)
+		require.NoError(t, err)
+		assert.Equal(t, imaging.PNG, format)
+		assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, img.RGBAAt(1, 1))
+	})
+
+	t.Run("слишком много пикселей", func(t *testing.T) {
+		_, _, err := imaging.Decode(encodeJPEG(t, halves(100, 100)), 9999)
+		assert.ErrorIs(t, err, imaging.ErrTooLarge)
+	})
+
+	t.Run("не изображение", func(t *testing.T) {
+		_, _, err := imaging.Decode([]byte("not an image"), 0)
+		assert.ErrorIs(t, err, imaging.ErrUnsupportedFormat)
+	})
+
+	t.Run("обрезанный файл", func(t *testing.T) {
+		data := encodeJPEG(t, halves(64, 64))
+		_, _, err := imaging.Decode(data[:len(data)/2], 0)
+		assert.ErrorIs(t, err, imaging.ErrUnsupportedFormat)
+	})
+}
+
+func TestSquare(t *testing.T) {
+	src := halves(200, 100)
+
+	small := imaging.Square(src, 64)
+	assert.Equal(t, image.Rect(0, 0, 64, 64), small.Bounds())
+	// Из центра вырезан квадрат 100×100 — поровну красного и синего
+	near(t, red, small.At(4, 32))
+	near(t, blue, small.At(60, 32))
+
+	// Маленькое изображение не увеличивается
+	assert.Equal(t, image.Rect(0, 0, 100, 100), imaging.Square(src, 640).Bounds())
+}
+
+func TestResize_AveragesArea(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.SetRGBA(0, 0, color.RGBA{A: 255})
+	src.SetRGBA(1, 0, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	src.SetRGBA(0, 1, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	src.SetRGBA(1, 1, color.RGBA{A: 255})
+
+	dst := imaging.Resize(src, 1, 1)
+	assert.Equal(t, color.RGBA{R: 128, G: 128, B: 128, A: 255}, dst.RGBAAt(0, 0))
+}
+
+func TestMosaic(t *testing.T) {
+	colors := [4]color.RGBA{red, blue, {G: 255, A: 255}, {R: 255, G: 255, A: 255}}
+	var tiles [4]*image.RGBA
+	for i, c := range colors {
+		tiles[i] = image.NewRGBA(image.Rect(0, 0, 50, 30))
+		draw.Draw(tiles[i], tiles[i].Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+
+	mosaic := imaging.Mosaic(tiles, 65)
+	assert.Equal(t, image.Rect(0, 0, 65, 65), mosaic.Bounds())
+	assert.Equal(t, colors[0], mosaic.RGBAAt(0, 0))
+	assert.Equal(t, colors[1], mosaic.RGBAAt(64, 0))
+	assert.Equal(t, colors[2], mosaic.RGBAAt(0, 64))
+	assert.Equal(t, colors[3], mosaic.RGBAAt(64, 64))
+}
+
+func TestNegotiate(t *testing.T) {
+	formats := []imaging.Format{imaging.WebP, imaging.JPEG}
+	cases := []struct {
+		accept string
+		want   imaging.Format
+	}{
+		{"image/avif,image/webp,image/apng,image/*,*/*;q=0.8", imaging.WebP},
+		{"image/png,image/*;q=0.8", imaging.JPEG},
+		{"*/*", imaging.JPEG},
+		{"", imaging.JPEG},
+		{"image/webp;q=0, image/jpeg", imaging.JPEG},
+		{"IMAGE/WEBP", imaging.WebP},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, imaging.Negotiate(tc.accept, formats), tc.accept)
+	}
+	assert.Equal(t, imaging.JPEG, imaging.Negotiate("image/webp", []imaging.Format{imaging.JPEG}))
+}
+
+func TestEncode(t *testing.T) {
+	assert.True(t, imaging.CanEncode(imaging.JPEG))
+	assert.True(t, imaging.CanEncode(imaging.PNG))
+	assert.Error(t, imaging.Encode(&bytes.Buffer{}, halves(2, 2), imaging.Format("bmp")))
+}
@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerKey struct{}
+
+// NewContext кладет логгер в контекст. Middleware RequestID кладет туда
+// логгер с request_id, поэтому обработчики и use case-ы берут его через
+// FromContext, а не используют глобальный
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext возвращает логгер запроса или slog.Default(), если его нет
+// (фоновые задачи, тесты)
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With добавляет атрибуты к логгеру из контекста
+func With(ctx context.Context, args ...any) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(args...))
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Config — уровень и формат логов. Level: debug, info, warn, error;
+// Format: json или text. Пустые значения означают info и json
+type Config struct {
+	Level  string
+	Format string
+}
+
+// New создает логгер, который пишет в w и маскирует секреты (см. Redact)
+func New(cfg Config, w io.Writer) (*slog.Logger, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: replaceAttr,
+	}
+
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+	return slog.New(handler), nil
+}
+
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q", value)
+}
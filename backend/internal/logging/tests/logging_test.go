@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"music-service/internal/delivery/http/middleware"
+	"music-service/internal/logging"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"DSN", "host='db' user='app' password='s3cret' dbname='music'", "host='db' user='app' password=[REDACTED] dbname='music'"},
+		{"URL с паролем", "dial postgres://app:s3cret@db:5432/music", "dial postgres://app:[REDACTED]@db:5432/music"},
+		{"Bearer", "Authorization: Bearer abc.def-ghi", "Authorization: Bearer [REDACTED]"},
+		{"query string", "/reset?token=abc123&lang=ru", "/reset?token=[REDACTED]&lang=ru"},
+		{"callback OAuth", "/callback?code=xyz&state=st", "/callback?code=[REDACTED]&state=[REDACTED]"},
+		{"JWT", "token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig rejected", "token [REDACTED] rejected"},
+		{"status_code не маскируется", "status_code=500", "status_code=500"},
+		{"обычный текст", "track 42 not found", "track 42 not found"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, logging.Redact(tc.input))
+		})
+	}
+}
+
+func TestNew_RedactsAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(logging.Config{Level: "debug", Format: "json"}, &buf)
+	require.NoError(t, err)
+
+	logger.Info("login with password=hunter2",
+		"password", "hunter2",
+		"refresh_token", "r-token",
+		"Authorization", "Bearer abc",
+		"error", errors.New("connect postgres://app:hunter2@db/music"),
+		"user_id", "42",
+	)
+
+	output := buf.String()
+	assert.NotContains(t, output, "hunter2")
+	assert.NotContains(t, output, "r-token")
+	assert.NotContains(t, output, "abc")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, logging.Redacted, record["password"])
+	assert.Equal(t, logging.Redacted, record["refresh_token"])
+	assert.Equal(t, "42", record["user_id"])
+	assert.Equal(t, "connect postgres://app:[REDACTED]@db/music", record["error"])
+}
+
+func TestNew_Config(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(logging.Config{Level: "warn", Format: "text"}, &buf)
+	require.NoError(t, err)
+
+	// Записи ниже уровня отбрасываются
+	logger.Info("skipped")
+	logger.Warn("kept", "key", "value")
+	assert.NotContains(t, buf.String(), "skipped")
+	assert.Contains(t, buf.String(), "msg=kept key=value")
+
+	_, err = logging.New(logging.Config{Level: "verbose"}, &buf)
+	assert.Error(t, err)
+	_, err = logging.New(logging.Config{Format: "xml"}, &buf)
+	assert.Error(t, err)
+}
+
+func TestRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(logging.Config{}, &buf)
+	require.NoError(t, err)
+
+	var handlerLogged bool
+	handler := middleware.RequestID(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("inside handler")
+		handlerLogged = true
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	t.Run("идентификатор от прокси сохраняется", func(t *testing.T) {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tracks?token=secret", nil)
+		req.Header.Set(middleware.RequestIDHeader, "req-123")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.True(t, handlerLogged)
+		assert.Equal(t, "req-123", rec.Header().Get(middleware.RequestIDHeader))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		for _, line := range lines {
+			var record map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			assert.Equal(t, "req-123", record["request_id"])
+		}
+
+		// Access-лог содержит код ответа и путь без query string
+		var access map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &access))
+		assert.Equal(t, float64(http.StatusTeapot), access["status"])
+		assert.Equal(t, "/api/v1/tracks", access["path"])
+		assert.NotContains(t, buf.String(), "secret")
+	})
+
+	t.Run("некорректный идентификатор заменяется", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(middleware.RequestIDHeader, "bad id\n")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		id := rec.Header().Get(middleware.RequestIDHeader)
+		assert.NotEmpty(t, id)
+		assert.NotEqual(t, "bad id\n", id)
+	})
+}
+
+func TestFromContext_Default(t *testing.T) {
+	assert.NotNil(t, logging.FromContext(context.Background()))
+}
@@ -0,0 +1,327 @@
+package loudness
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// absoluteGate — абсолютный порог стробирования, LUFS
+	absoluteGate = -70.0
+	// relativeGate — относительный порог стробирования, LU
+	relativeGate = -10.0
+	// blockSteps — число шагов по 100 мс в блоке 400 мс: блоки перекрываются
+	// на 75%
+	blockSteps = 4
+)
+
+// ErrSilence — в записи нет звука громче абсолютного порога: громкость не
+// определена
+var ErrSilence = errors.New("audio is silent")
+
+// Result — результат измерения по ITU-R BS.1770-4 / EBU R128
+type Result struct {
+	// Integrated — интегральная громкость, LUFS
+	Integrated float64
+	// TruePeak — истинный пик с учетом межсэмпловых выбросов, dBTP
+	TruePeak float64
+	// Duration — длительность измеренного звука
+	Duration time.Duration
+}
+
+// Meter измеряет громкость потока сэмплов. Сэмплы каналов чередуются и
+// лежат в диапазоне [-1, 1]
+type Meter struct {
+	sampleRate int
+	channels   int
+	weights    []float64
+	filters    []kWeighting
+	peak       *truePeak
+
+	// stepSize — число кадров в шаге 100 мс
+	stepSize int
+	// stepFill — число кадров, накопленных в текущем шаге
+	stepFill int
+	// stepEnergy — сумма квадратов взвешенных сэмплов текущего шага по
+	// каналам
+	stepEnergy []float64
+	// steps — средневзвешенная мощность последних шагов для сборки блока
+	steps []float64
+	// blocks — мощность каждого блока 400 мс
+	blocks []float64
+	frames int64
+}
+
+// NewMeter создает измеритель для потока с частотой sampleRate и числом
+// каналов channels. Каналы 5.1 ожидаются в порядке L, R, C, LFE, Ls, Rs
+func NewMeter(sampleRate, channels int) (*Meter, error) {
+	if sampleRate < 8000 {
+		return nil, fmt.Errorf("unsupported sample rate %d", sampleRate)
+	}
+	if channels < 1 || channels > 8 {
+		return nil, fmt.Errorf("unsupported channel count %d", channels)
+	}
+	m := &Meter{
+		sampleRate: sampleRate,
+		channels:   channels,
+		weights:    channelWeights(channels),
+		filters:    make([]kWeighting, channels),
+		peak:       newTruePeak(sampleRate, channels),
+		stepSize:   sampleRate / 10,
+		stepEnergy: make([]float64, channels),
+	}
+	for i := range m.filters {
+		m.filters[i] = newKWeighting(float64(sampleRate))
+	}
+	return m, nil
+}
+
+// channelWeights — весовые коэффициенты каналов: окружающие каналы
+// громче на 1,5 дБ, канал низкочастотных эффектов не учитывается
+func channelWeights(channels int) []float64 {
+	weights := make([]float64, channels)
+	for i := range weights {
+		weights[i] = 1
+	}
+	switch channels {
+	case 5:
+		weights[3], weights[4] = 1.41, 1.41
+	case 6:
+		weights[3], weights[4], weights[5] = 0, 1.41, 1.41
+	}
+	return weights
+}
+
+// Write добавляет сэмплы. Длина samples должна быть кратна числу каналов;
+// неполный последний кадр отбрасывается
+func (m *Meter) Write(samples []float64) {
+	frames := len(samples) / m.channels
+	for f := 0; f < frames; f++ {
+		frame := samples[f*m.channels : (f+1)*m.channels]
+		m.peak.write(frame)
+		for ch, sample := range frame {
+			weighted := m.filters[ch].process(sample)
+			m.stepEnergy[ch] += weighted * weighted
+		}
+		m.stepFill++
+		if m.stepFill == m.stepSize {
+			m.finishStep()
+		}
+	}
+	m.frames += int64(frames)
+}
+
+// finishStep закрывает шаг 100 мс и, когда накоплено четыре шага, —
+// очередной блок 400 мс
+func (m *Meter) finishStep() {
+	var power float64
+	for ch, energy := range m.stepEnergy {
+		power += m.weights[ch] * energy / float64(m.stepSize)
+		m.stepEnergy[ch] = 0
+	}
+	m.stepFill = 0
+
+	m.steps = append(m.steps, power)
+	if len(m.steps) > blockSteps {
+		m.steps = m.steps[1:]
+	}
+	if len(m.steps) == blockSteps {
+		var block float64
+		for _, step := range m.steps {
+			block += step
+		}
+		m.blocks = append(m.blocks, block/blockSteps)
+	}
+}
+
+// Result возвращает измерения по записанным сэмплам. Для тишины и записи
+// короче 400 мс возвращается ErrSilence
+func (m *Meter) Result() (Result, error) {
+	result := Result{
+		TruePeak: toDecibels(m.peak.max),
+		Duration: time.Duration(m.frames) * time.Second / time.Duration(m.sampleRate),
+	}
+	integrated, ok := integratedLoudness(m.blocks)
+	if !ok {
+		return result, ErrSilence
+	}
+	result.Integrated = integrated
+	return result, nil
+}
+
+// integratedLoudness вычисляет интегральную громкость по мощностям блоков
+// 400 мс с абсолютным и относительным стробированием. ok == false — ни один
+// блок не прошел абсолютный порог
+func integratedLoudness(blocks []float64) (float64, bool) {
+	threshold := fromLoudness(absoluteGate)
+	mean, ok := gatedMean(blocks, threshold)
+	if !ok {
+		return 0, false
+	}
+	threshold = max(threshold, fromLoudness(toLoudness(mean)+relativeGate))
+	mean, ok = gatedMean(blocks, threshold)
+	if !ok {
+		return 0, false
+	}
+	return toLoudness(mean), true
+}
+
+// gatedMean — средняя мощность блоков выше порога
+func gatedMean(blocks []float64, threshold float64) (float64, bool) {
+	var sum float64
+	var count int
+	for _, block := range blocks {
+		if block > threshold {
+			sum += block
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// toLoudness переводит среднюю мощность в LUFS
+func toLoudness(power float64) float64 {
+	return -0.691 + 10*math.Log10(power)
+}
+
+func fromLoudness(lufs float64) float64 {
+	return math.Pow(10, (lufs+0.691)/10)
+}
+
+// toDecibels переводит линейную амплитуду в дБ; для нуля — -Inf
+func toDecibels(amplitude float64) float64 {
+	return 20 * math.Log10(amplitude)
+}
+
+// biquad — фильтр второго порядка в транспонированной прямой форме II
+type biquad struct {
+	b0, b1, b2 float64
+	a1, a2     float64
+	z1, z2     float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.z1
+	f.z1 = f.b1*x - f.a1*y + f.z2
+	f.z2 = f.b2*x - f.a2*y
+	return y
+}
+
+// kWeighting — частотная коррекция K: полка, моделирующая влияние головы,
+// и фильтр верхних частот RLB. Коэффициенты пересчитываются из аналоговых
+// прототипов для любой частоты дискретизации; на 48 кГц они совпадают с
+// приведенными в BS.1770
+type kWeighting struct {
+	shelf, highPass biquad
+}
+
+func newKWeighting(sampleRate float64) kWeighting {
+	const (
+		shelfFreq = 1681.974450955533
+		shelfGain = 3.999843853973347
+		shelfQ    = 0.7071752369554196
+		passFreq  = 38.13547087602444
+		passQ     = 0.5003270373238773
+	)
+
+	k := math.Tan(math.Pi * shelfFreq / sampleRate)
+	vh := math.Pow(10, shelfGain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/shelfQ + k*k
+	shelf := biquad{
+		b0: (vh + vb*k/shelfQ + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/shelfQ + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/shelfQ + k*k) / a0,
+	}
+
+	k = math.Tan(math.Pi * passFreq / sampleRate)
+	a0 = 1 + k/passQ + k*k
+	highPass := biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/passQ + k*k) / a0,
+	}
+	return kWeighting{shelf: shelf, highPass: highPass}
+}
+
+func (k *kWeighting) process(x float64) float64 {
+	return k.highPass.process(k.shelf.process(x))
+}
+
+// truePeak ищет истинный пик по сигналу, передискретизированному в 4 раза
+// (в 2 раза для 96 кГц и выше, без передискретизации от 192 кГц)
+// полифазным КИХ-фильтром с окном Ханна
+type truePeak struct {
+	// phases — коэффициенты фильтра, разложенные по фазам
+	phases [][]float64
+	// history — последние входные сэмплы каждого канала, новые в начале
+	history [][]float64
+	max     float64
+}
+
+// interpolationTaps — длина интерполирующего фильтра
+const interpolationTaps = 49
+
+func newTruePeak(sampleRate, channels int) *truePeak {
+	factor := 4
+	switch {
+	case sampleRate >= 192000:
+		factor = 1
+	case sampleRate >= 96000:
+		factor = 2
+	}
+
+	p := &truePeak{}
+	if factor == 1 {
+		return p
+	}
+
+	length := (interpolationTaps + factor - 1) / factor
+	p.phases = make([][]float64, factor)
+	for i := range p.phases {
+		p.phases[i] = make([]float64, length)
+	}
+	for j := 0; j < interpolationTaps; j++ {
+		m := float64(j) - float64(interpolationTaps-1)/2
+		window := 0.5 * (1 - math.Cos(2*math.Pi*float64(j)/float64(interpolationTaps-1)))
+		coefficient := window
+		if m != 0 {
+			x := math.Pi * m / float64(factor)
+			coefficient *= math.Sin(x) / x
+		}
+		p.phases[j%factor][j/factor] = coefficient
+	}
+	p.history = make([][]float64, channels)
+	for i := range p.history {
+		p.history[i] = make([]float64, length)
+	}
+	return p
+}
+
+func (p *truePeak) write(frame []float64) {
+	for ch, sample := range frame {
+		p.max = max(p.max, math.Abs(sample))
+		if p.phases == nil {
+			continue
+		}
+		history := p.history[ch]
+		copy(history[1:], history)
+		history[0] = sample
+		for _, phase := range p.phases {
+			var y float64
+			for k, c := range phase {
+				y += c * history[k]
+			}
+			p.max = max(p.max, math.Abs(y))
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"math"
+	"music-service/internal/loudness"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segment — отрезок синусоиды с амплитудой в dBFS (0 dBFS — синусоида
+// полной шкалы)
+type segment struct {
+	level    float64
+	duration time.Duration
+}
+
+// sine возвращает стерео-синусоиду частоты freq, одинаковую в обоих каналах
+func sine(sampleRate int, freq, phase float64, segments ...segment) []float64 {
+	var samples []float64
+	n := 0
+	for _, s := range segments {
+		amplitude := math.Pow(10, s.level/20)
+		frames := int(s.duration.Seconds() * float64(sampleRate))
+		for i := 0; i < frames; i++ {
+			v := amplitude * math.Sin(2*math.Pi*freq*float64(n)/float64(sampleRate)+phase)
+			samples = append(samples, v, v)
+			n++
+		}
+	}
+	return samples
+}
+
+func measure(t *testing.T, sampleRate int, samples []float64) (loudness.Result, error) {
+	t.Helper()
+	meter, err := loudness.NewMeter(sampleRate, 2)
+	require.NoError(t, err)
+	// Сэмплы передаются частями, как при чтении из потока
+	for len(samples) > 0 {
+		n := min(len(samples), 3000)
+		meter.Write(samples[:n])
+		samples = samples[n:]
+	}
+	return meter.Result()
+}
+
+func TestMeter(t *testing.T) {
+	// EBU Tech 3341, случай 1: синусоида 1 кГц -23 dBFS — -23 LUFS
+	t.Run("reference tone", func(t *testing.T) {
+		for _, rate := range []int{44100, 48000} {
+			result, err := measure(t, rate, sine(rate, 1000, 0, segment{-23, 20 * time.Second}))
+			require.NoError(t, err)
+			assert.InDelta(t, -23, result.Integrated, 0.1, "sample rate %d", rate)
+			assert.InDelta(t, -23, result.TruePeak, 0.1, "sample rate %d", rate)
+			assert.Equal(t, 20*time.Second, result.Duration)
+		}
+	})
+
+	// EBU Tech 3341, случай 3: тихие отрезки по краям отсекаются
+	// относительным порогом
+	t.Run("relative gate", func(t *testing.T) {
+		samples := sine(48000, 1000, 0,
+			segment{-36, 10 * time.Second},
+			segment{-23, 60 * time.Second},
+			segment{-36, 10 * time.Second},
+		)
+		result, err := measure(t, 48000, samples)
+		require.NoError(t, err)
+		assert.InDelta(t, -23, result.Integrated, 0.1)
+	})
+
+	// Отрезки тише -70 LUFS не учитываются абсолютным порогом
+	t.Run("absolute gate", func(t *testing.T) {
+		samples := sine(48000, 1000, 0,
+			segment{-80, 20 * time.Second},
+			segment{-20, 10 * time.Second},
+		)
+		result, err := measure(t, 48000, samples)
+		require.NoError(t, err)
+		assert.InDelta(t, -20, result.Integrated, 0.1)
+	})
+
+	// Тишина и запись короче блока — громкость не определена
+	t.Run("silence", func(t *testing.T) {
+		_, err := measure(t, 48000, make([]float64, 48000*2*5))
+		assert.ErrorIs(t, err, loudness.ErrSilence)
+
+		_, err = measure(t, 48000, sine(48000, 1000, 0, segment{-10, 300 * time.Millisecond}))
+		assert.ErrorIs(t, err, loudness.ErrSilence)
+	})
+
+	// Синусоида 12 кГц со сдвигом фазы на 45°: сэмплы не попадают на
+	// вершины, их пик на 3 дБ ниже истинного
+	t.Run("inter-sample peak", func(t *testing.T) {
+		result, err := measure(t, 48000, sine(48000, 12000, math.Pi/4, segment{-1, 5 * time.Second}))
+		require.NoError(t, err)
+		assert.InDelta(t, -1, result.TruePeak, 0.5)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := loudness.NewMeter(0, 2)
+		assert.Error(t, err)
+		_, err = loudness.NewMeter(48000, 0)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,411 @@
+package lyrics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// maxTagSize — предел размера тега ID3v2. Тег с обложкой высокого
+// разрешения занимает единицы мегабайт
+const maxTagSize = 32 << 20
+
+// ErrInvalidTag — тег ID3v2 поврежден
+var ErrInvalidTag = errors.New("invalid ID3v2 tag")
+
+// Embedded — тексты песни из тега ID3v2: обычный из кадра USLT и
+// синхронизированный из кадра SYLT. Language — тег BCP 47, если язык
+// кадра известен
+type Embedded struct {
+	Language string
+	Text     string
+	Lines    []Line
+}
+
+// Lyrics возвращает текст для сохранения: синхронизированный, если он есть
+func (e *Embedded) Lyrics() string {
+	if len(e.Lines) > 0 {
+		return FormatLRC(e.Lines)
+	}
+	return e.Text
+}
+
+// ReadID3 читает тексты песни из тега ID3v2 в начале файла. Если тега или
+// текстов в нем нет, возвращается nil без ошибки. Поддерживаются версии
+// 2.2–2.4; сжатые и зашифрованные кадры пропускаются
+func ReadID3(r io.Reader) (*Embedded, error) {
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if string(header[:3]) != "ID3" {
+		return nil, nil
+	}
+
+	version := header[3]
+	if version < 2 || version > 4 {
+		return nil, nil
+	}
+	flags := header[5]
+	size, ok := synchsafe(header[6:10])
+	if !ok {
+		return nil, ErrInvalidTag
+	}
+	if size > maxTagSize {
+		return nil, fmt.Errorf("ID3v2 tag is too large: %d bytes", size)
+	}
+
+	tag := make([]byte, size)
+	if _, err := io.ReadFull(r, tag); err != nil {
+		return nil, ErrInvalidTag
+	}
+
+	unsync := flags&0x80 != 0
+	// В версиях до 2.4 рассинхронизация применяется ко всему тегу
+	if unsync && version < 4 {
+		tag = removeUnsync(tag)
+	}
+	if flags&0x40 != 0 && version > 2 {
+		skip, err := extendedHeaderSize(tag, version)
+		if err != nil {
+			return nil, err
+		}
+		tag = tag[skip:]
+	}
+
+	embedded := &Embedded{}
+	for _, frame := range readFrames(tag, version, unsync) {
+		switch frame.id {
+		case "USLT", "ULT":
+			if embedded.Text != "" {
+				continue
+			}
+			language, text, ok := parseUSLT(frame.data)
+			if ok && strings.TrimSpace(text) != "" {
+				embedded.Text = Normalize(text)
+				embedded.setLanguage(language)
+			}
+		case "SYLT", "SLT":
+			if len(embedded.Lines) > 0 {
+				continue
+			}
+			language, lines, ok := parseSYLT(frame.data)
+			if ok && len(lines) > 0 {
+				embedded.Lines = lines
+				embedded.setLanguage(language)
+			}
+		}
+	}
+
+	if embedded.Text == "" && len(embedded.Lines) == 0 {
+		return nil, nil
+	}
+	return embedded, nil
+}
+
+func (e *Embedded) setLanguage(code string) {
+	if e.Language == "" {
+		e.Language = languageTags[strings.ToLower(code)]
+	}
+}
+
+// languageTags сопоставляет распространенные коды ISO 639-2 из кадров ID3
+// тегам BCP 47. Неизвестный язык (в том числе "XXX") не сохраняется
+var languageTags = map[string]string{
+	"eng": "en",
+	"rus": "ru",
+	"ukr": "uk",
+	"bel": "be",
+	"kaz": "kk",
+	"deu": "de",
+	"ger": "de",
+	"fra": "fr",
+	"fre": "fr",
+	"spa": "es",
+	"ita": "it",
+	"por": "pt",
+	"pol": "pl",
+	"jpn": "ja",
+	"kor": "ko",
+	"zho": "zh",
+	"chi": "zh",
+}
+
+type frame struct {
+	id   string
+	data []byte
+}
+
+// readFrames разбирает кадры тега до конца данных или начала заполнения
+func readFrames(tag []byte, version byte, unsync bool) []frame {
+	idLen, headerLen := 4, 10
+	if version == 2 {
+		idLen, headerLen = 3, 6
+	}
+
+	var frames []frame
+	for pos := 0; pos+headerLen <= len(tag); {
+		header := tag[pos : pos+headerLen]
+		// Нулевой байт вместо идентификатора — начало заполнения
+		if header[0] == 0 {
+			break
+		}
+		id := string(header[:idLen])
+
+		var size int
+		var formatFlags byte
+		switch version {
+		case 2:
+			size = int(header[3])<<16 | int(header[4])<<8 | int(header[5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(header[4:8]))
+			formatFlags = header[9]
+		default:
+			s, ok := synchsafe(header[4:8])
+			if !ok {
+				return frames
+			}
+			size = s
+			formatFlags = header[9]
+		}
+		pos += headerLen
+		if size < 0 || pos+size > len(tag) {
+			return frames
+		}
+		data := tag[pos : pos+size]
+		pos += size
+
+		data, ok := frameData(data, version, formatFlags, unsync)
+		if ok {
+			frames = append(frames, frame{id: id, data: data})
+		}
+	}
+	return frames
+}
+
+// frameData снимает с данных кадра служебные поля по его флагам. Сжатые и
+// зашифрованные кадры не поддерживаются: ok == false
+func frameData(data []byte, version, flags byte, tagUnsync bool) ([]byte, bool) {
+	switch version {
+	case 3:
+		if flags&0x80 != 0 || flags&0x40 != 0 {
+			return nil, false
+		}
+		if flags&0x20 != 0 {
+			if len(data) < 1 {
+				return nil, false
+			}
+			data = data[1:]
+		}
+	case 4:
+		if flags&0x08 != 0 || flags&0x04 != 0 {
+			return nil, false
+		}
+		if flags&0x40 != 0 {
+			if len(data) < 1 {
+				return nil, false
+			}
+			data = data[1:]
+		}
+		if flags&0x01 != 0 {
+			if len(data) < 4 {
+				return nil, false
+			}
+			data = data[4:]
+		}
+		if flags&0x02 != 0 || tagUnsync {
+			data = removeUnsync(data)
+		}
+	}
+	return data, true
+}
+
+// parseUSLT разбирает кадр USLT: кодировка, язык, описание и текст
+func parseUSLT(data []byte) (string, string, bool) {
+	if len(data) < 4 {
+		return "", "", false
+	}
+	encoding, language := data[0], string(data[1:4])
+	_, rest, ok := splitString(data[4:], encoding)
+	if !ok {
+		return "", "", false
+	}
+	return language, decodeString(rest, encoding), true
+}
+
+// parseSYLT разбирает кадр SYLT: кодировка, язык, формат меток, тип
+// содержимого, описание и пары "текст, метка". Метки в кадрах MPEG не
+// поддерживаются: для них нужна длительность кадра файла
+func parseSYLT(data []byte) (string, []Line, bool) {
+	if len(data) < 6 {
+		return "", nil, false
+	}
+	encoding, language := data[0], string(data[1:4])
+	const millisecondStamps = 2
+	if data[4] != millisecondStamps {
+		return "", nil, false
+	}
+	// 0 — прочее, 1 — текст песни, 2 — расшифровка речи
+	if data[5] > 2 {
+		return "", nil, false
+	}
+	_, rest, ok := splitString(data[6:], encoding)
+	if !ok {
+		return "", nil, false
+	}
+
+	var entries []Line
+	for len(rest) > 0 {
+		raw, tail, ok := splitString(rest, encoding)
+		if !ok || len(tail) < 4 {
+			break
+		}
+		entries = append(entries, Line{
+			Time: time.Duration(binary.BigEndian.Uint32(tail[:4])) * time.Millisecond,
+			Text: decodeString(raw, encoding),
+		})
+		rest = tail[4:]
+	}
+	return language, syltLines(entries), true
+}
+
+// syltLines собирает строки из элементов SYLT. Часто элемент — слог или
+// слово, а начало новой строки отмечено переводом строки в начале
+// элемента; если переводов строк нет, каждый элемент считается строкой
+func syltLines(entries []Line) []Line {
+	wordByWord := false
+	for _, entry := range entries {
+		if strings.ContainsAny(entry.Text, "\r\n") {
+			wordByWord = true
+			break
+		}
+	}
+
+	var lines []Line
+	for _, entry := range entries {
+		if !wordByWord {
+			lines = append(lines, Line{Time: entry.Time, Text: strings.TrimSpace(entry.Text)})
+			continue
+		}
+		starts := strings.HasPrefix(entry.Text, "\n") || strings.HasPrefix(entry.Text, "\r")
+		text := strings.TrimLeft(entry.Text, "\r\n")
+		if starts || len(lines) == 0 {
+			lines = append(lines, Line{Time: entry.Time, Text: text})
+			continue
+		}
+		lines[len(lines)-1].Text += text
+	}
+	for i := range lines {
+		lines[i].Text = strings.TrimSpace(lines[i].Text)
+	}
+	return lines
+}
+
+// splitString отделяет строку, завершенную нулем кодировки encoding: один
+// нулевой байт для ISO-8859-1 и UTF-8, два выровненных — для UTF-16. Если
+// завершающего нуля нет, вся строка — data
+func splitString(data []byte, encoding byte) ([]byte, []byte, bool) {
+	switch encoding {
+	case 0, 3:
+		if i := bytes.IndexByte(data, 0); i >= 0 {
+			return data[:i], data[i+1:], true
+		}
+		return data, nil, true
+	case 1, 2:
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				return data[:i], data[i+2:], true
+			}
+		}
+		return data, nil, true
+	}
+	return nil, nil, false
+}
+
+// decodeString переводит строку кадра в UTF-8
+func decodeString(data []byte, encoding byte) string {
+	switch encoding {
+	case 0:
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	case 1, 2:
+		var order binary.ByteOrder = binary.BigEndian
+		if encoding == 1 {
+			// Строка начинается с BOM; без него — порядок Windows-программ
+			order = binary.LittleEndian
+			if len(data) >= 2 {
+				switch {
+				case data[0] == 0xFE && data[1] == 0xFF:
+					order, data = binary.BigEndian, data[2:]
+				case data[0] == 0xFF && data[1] == 0xFE:
+					data = data[2:]
+				}
+			}
+		}
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			units[i] = order.Uint16(data[i*2:])
+		}
+		return string(utf16.Decode(units))
+	}
+	return string(data)
+}
+
+// synchsafe декодирует 28-битное число, записанное по 7 бит в байте
+func synchsafe(b []byte) (int, bool) {
+	n := 0
+	for _, v := range b {
+		if v&0x80 != 0 {
+			return 0, false
+		}
+		n = n<<7 | int(v)
+	}
+	return n, true
+}
+
+// removeUnsync убирает байты 0x00, вставленные после 0xFF при
+// рассинхронизации
+func removeUnsync(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		out = append(out, data[i])
+		if data[i] == 0xFF && i+1 < len(data) && data[i+1] == 0 {
+			i++
+		}
+	}
+	return out
+}
+
+// extendedHeaderSize возвращает длину расширенного заголовка: в версии 2.3
+// размер не включает само поле размера, в 2.4 — включает и записан
+// synchsafe-числом
+func extendedHeaderSize(tag []byte, version byte) (int, error) {
+	if len(tag) < 4 {
+		return 0, ErrInvalidTag
+	}
+	var size int
+	if version == 3 {
+		size = int(binary.BigEndian.Uint32(tag[:4])) + 4
+	} else {
+		s, ok := synchsafe(tag[:4])
+		if !ok {
+			return 0, ErrInvalidTag
+		}
+		size = s
+	}
+	if size < 4 || size > len(tag) {
+		return 0, ErrInvalidTag
+	}
+	return size, nil
+}
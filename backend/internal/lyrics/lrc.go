@@ -0,0 +1,157 @@
+package lyrics
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Line — строка текста песни. У синхронизированного текста Time — момент,
+// с которого строка звучит; у обычного текста Time не используется.
+// Пустой Text — разрыв между куплетами или проигрыш
+type Line struct {
+	Time time.Duration
+	Text string
+}
+
+// Lyrics — разобранный текст песни
+type Lyrics struct {
+	Synced bool
+	Lines  []Line
+}
+
+var (
+	// timeTag — метка времени LRC: [mm:ss], [mm:ss.xx], [mm:ss.xxx] или
+	// [mm:ss:xx]
+	timeTag = regexp.MustCompile(`^\[(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	// idTag — служебный тег LRC: [ar:...], [ti:...], [offset:...] и т.п.
+	idTag = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]$`)
+	// wordTag — метка отдельного слова в расширенном LRC: <mm:ss.xx>
+	wordTag = regexp.MustCompile(`<\d{1,3}:\d{1,2}(?:[.:]\d{1,3})?>`)
+)
+
+// Normalize убирает BOM, приводит переводы строк к \n и обрезает пробелы
+// по краям текста
+func Normalize(text string) string {
+	text = strings.TrimPrefix(text, "\uFEFF")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.TrimSpace(text)
+}
+
+// Parse разбирает текст песни. Текст считается синхронизированным (LRC),
+// если хотя бы одна строка начинается с метки времени; строки без меток в
+// нем пропускаются
+func Parse(text string) Lyrics {
+	text = Normalize(text)
+	if lines, ok := parseLRC(text); ok {
+		return Lyrics{Synced: true, Lines: lines}
+	}
+	return Lyrics{Lines: plainLines(text)}
+}
+
+// parseLRC разбирает LRC. Строка с несколькими метками ([00:12.00][00:45.00]
+// Припев) повторяется для каждой из них, тег [offset:мс] сдвигает все метки
+// (положительное значение — строки появляются раньше). Строки упорядочены
+// по времени
+func parseLRC(text string) ([]Line, bool) {
+	var lines []Line
+	var offset time.Duration
+	synced := false
+
+	for _, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimSpace(raw)
+
+		var stamps []time.Duration
+		for {
+			match := timeTag.FindStringSubmatch(raw)
+			if match == nil {
+				break
+			}
+			stamps = append(stamps, stampDuration(match[1], match[2], match[3]))
+			raw = raw[len(match[0]):]
+		}
+
+		if len(stamps) == 0 {
+			if tag := idTag.FindStringSubmatch(raw); tag != nil && strings.EqualFold(tag[1], "offset") {
+				if ms, err := strconv.Atoi(strings.TrimSpace(tag[2])); err == nil {
+					offset = time.Duration(ms) * time.Millisecond
+				}
+			}
+			continue
+		}
+
+		synced = true
+		lineText := strings.TrimSpace(wordTag.ReplaceAllString(raw, ""))
+		for _, stamp := range stamps {
+			lines = append(lines, Line{Time: stamp, Text: lineText})
+		}
+	}
+	if !synced {
+		return nil, false
+	}
+
+	for i := range lines {
+		lines[i].Time = max(lines[i].Time-offset, 0)
+	}
+	slices.SortStableFunc(lines, func(a, b Line) int {
+		return cmp.Compare(a.Time, b.Time)
+	})
+	return lines, true
+}
+
+// stampDuration переводит части метки в длительность. Дробная часть из
+// двух цифр — сотые доли секунды, из трех — тысячные
+func stampDuration(minutes, seconds, fraction string) time.Duration {
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(seconds)
+	d := time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+	if fraction != "" {
+		f, _ := strconv.Atoi(fraction)
+		for i := len(fraction); i < 3; i++ {
+			f *= 10
+		}
+		d += time.Duration(f) * time.Millisecond
+	}
+	return d
+}
+
+// plainLines делит обычный текст на строки. Подряд идущие пустые строки
+// схлопываются в одну — разрыв между куплетами
+func plainLines(text string) []Line {
+	var lines []Line
+	for _, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" && (len(lines) == 0 || lines[len(lines)-1].Text == "") {
+			continue
+		}
+		lines = append(lines, Line{Text: raw})
+	}
+	return lines
+}
+
+// PlainText возвращает текст без меток времени: по нему строится
+// полнотекстовый поиск. Повторы строк синхронизированного текста
+// сохраняются — припев звучит несколько раз
+func (l Lyrics) PlainText() string {
+	texts := make([]string, 0, len(l.Lines))
+	for _, line := range l.Lines {
+		texts = append(texts, line.Text)
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n"))
+}
+
+// FormatLRC записывает синхронизированные строки в формате LRC с сотыми
+// долями секунды
+func FormatLRC(lines []Line) string {
+	var b strings.Builder
+	for _, line := range lines {
+		cs := line.Time.Milliseconds() / 10
+		fmt.Fprintf(&b, "[%02d:%02d.%02d]%s\n", cs/6000, cs/100%60, cs%100, line.Text)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"music-service/internal/lyrics"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	// Синхронизированный текст: служебные теги пропускаются, строка с
+	// несколькими метками повторяется, строки упорядочены по времени
+	t.Run("lrc", func(t *testing.T) {
+		text := "\uFEFF[ar:Кино]\r\n[ti:Группа крови]\r\n[00:12.50]Теплое место\r\n[00:30.00][00:05]Припев\r\n[01:02.345]Но улицы ждут\r\n"
+		parsed := lyrics.Parse(text)
+
+		assert.True(t, parsed.Synced)
+		assert.Equal(t, []lyrics.Line{
+			{Time: 5 * time.Second, Text: "Припев"},
+			{Time: 12500 * time.Millisecond, Text: "Теплое место"},
+			{Time: 30 * time.Second, Text: "Припев"},
+			{Time: time.Minute + 2345*time.Millisecond, Text: "Но улицы ждут"},
+		}, parsed.Lines)
+		assert.Equal(t, "Припев\nТеплое место\nПрипев\nНо улицы ждут", parsed.PlainText())
+	})
+
+	// [offset:500] сдвигает строки на полсекунды раньше, но не раньше нуля
+	t.Run("offset", func(t *testing.T) {
+		parsed := lyrics.Parse("[offset:500]\n[00:00.20]Раз\n[00:02.00]Два")
+
+		assert.Equal(t, []lyrics.Line{
+			{Time: 0, Text: "Раз"},
+			{Time: 1500 * time.Millisecond, Text: "Два"},
+		}, parsed.Lines)
+	})
+
+	// Метки слов расширенного LRC убираются из текста
+	t.Run("enhanced lrc", func(t *testing.T) {
+		parsed := lyrics.Parse("[00:01.00]<00:01.00>Звезда <00:01.60>по <00:01.90>имени <00:02.30>Солнце")
+
+		assert.Equal(t, "Звезда по имени Солнце", parsed.Lines[0].Text)
+	})
+
+	// Обычный текст: пустые строки между куплетами схлопываются, квадратные
+	// скобки без времени не делают текст синхронизированным
+	t.Run("plain", func(t *testing.T) {
+		parsed := lyrics.Parse("\n[Куплет 1]\nБелый снег, серый лед\n\n\n\nНа растрескавшейся земле\n")
+
+		assert.False(t, parsed.Synced)
+		assert.Equal(t, []lyrics.Line{
+			{Text: "[Куплет 1]"},
+			{Text: "Белый снег, серый лед"},
+			{Text: ""},
+			{Text: "На растрескавшейся земле"},
+		}, parsed.Lines)
+	})
+}
+
+func TestFormatLRC(t *testing.T) {
+	lines := []lyrics.Line{
+		{Time: 1500 * time.Millisecond, Text: "Раз"},
+		{Time: 2*time.Minute + 3070*time.Millisecond, Text: "Два"},
+	}
+	text := lyrics.FormatLRC(lines)
+
+	assert.Equal(t, "[00:01.50]Раз\n[02:03.07]Два", text)
+	assert.Equal(t, lines, lyrics.Parse(text).Lines)
+}
+
+func TestReadID3(t *testing.T) {
+	// ID3v2.3, USLT в UTF-16 с BOM
+	t.Run("uslt", func(t *testing.T) {
+		data := []byte{1}
+		data = append(data, "rus"...)
+		data = append(data, utf16LE("")...)
+		data = append(data, 0, 0)
+		data = append(data, utf16LE("Группа крови на рукаве\nМой порядковый номер")...)
+		file := id3Tag(3, frame23("USLT", data))
+
+		embedded, err := lyrics.ReadID3(bytes.NewReader(file))
+		require.NoError(t, err)
+		require.NotNil(t, embedded)
+		assert.Equal(t, "ru", embedded.Language)
+		assert.Equal(t, "Группа крови на рукаве\nМой порядковый номер", embedded.Text)
+		assert.Equal(t, embedded.Text, embedded.Lyrics())
+	})
+
+	// ID3v2.4, SYLT в UTF-8 по словам: строки собираются по переводам строк
+	t.Run("sylt", func(t *testing.T) {
+		data := []byte{3}
+		data = append(data, "eng"...)
+		data = append(data, 2, 1)
+		data = append(data, "desc\x00"...)
+		for _, entry := range []struct {
+			text string
+			ms   uint32
+		}{{"Hello ", 1000}, {"world", 1500}, {"\nSecond ", 4000}, {"line", 4200}} {
+			data = append(data, entry.text...)
+			data = append(data, 0)
+			data = binary.BigEndian.AppendUint32(data, entry.ms)
+		}
+		file := id3Tag(4, frame24("SYLT", data))
+
+		embedded, err := lyrics.ReadID3(bytes.NewReader(file))
+		require.NoError(t, err)
+		require.NotNil(t, embedded)
+		assert.Equal(t, "en", embedded.Language)
+		assert.Equal(t, []lyrics.Line{
+			{Time: time.Second, Text: "Hello world"},
+			{Time: 4 * time.Second, Text: "Second line"},
+		}, embedded.Lines)
+		assert.Equal(t, "[00:01.00]Hello world\n[00:04.00]Second line", embedded.Lyrics())
+	})
+
+	// Тег без текстов и файл без тега
+	t.Run("нет текста", func(t *testing.T) {
+		title := append([]byte{3}, "Кукушка"...)
+		embedded, err := lyrics.ReadID3(bytes.NewReader(id3Tag(3, frame23("TIT2", title))))
+		assert.NoError(t, err)
+		assert.Nil(t, embedded)
+
+		embedded, err = lyrics.ReadID3(bytes.NewReader([]byte{0xFF, 0xFB, 0x90, 0x64, 0x00}))
+		assert.NoError(t, err)
+		assert.Nil(t, embedded)
+	})
+
+	// Размер тега больше файла
+	t.Run("обрезанный тег", func(t *testing.T) {
+		file := id3Tag(3, frame23("USLT", []byte{0, 'e', 'n', 'g', 0, 'x'}))
+		_, err := lyrics.ReadID3(bytes.NewReader(file[:len(file)-3]))
+		assert.ErrorIs(t, err, lyrics.ErrInvalidTag)
+	})
+}
+
+func id3Tag(version byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	// Заполнение в конце тега
+	body = append(body, make([]byte, 16)...)
+	header := []byte{'I', 'D', '3', version, 0, 0}
+	return append(append(header, synchsafe(len(body))...), body...)
+}
+
+func frame23(id string, data []byte) []byte {
+	frame := append([]byte(id), 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(frame[4:], uint32(len(data)))
+	return append(frame, data...)
+}
+
+func frame24(id string, data []byte) []byte {
+	frame := append([]byte(id), synchsafe(len(data))...)
+	return append(append(frame, 0, 0), data...)
+}
+
+func synchsafe(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
+
+// utf16LE кодирует строку в UTF-16LE с BOM
+func utf16LE(s string) []byte {
+	out := []byte{0xFF, 0xFE}
+	for _, unit := range utf16.Encode([]rune(s)) {
+		out = binary.LittleEndian.AppendUint16(out, unit)
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileSender сохраняет каждое письмо в отдельный .eml файл, который можно
+// открыть почтовым клиентом
+type FileSender struct {
+	from string
+	dir  string
+}
+
+func NewFileSender(from, dir string) (*FileSender, error) {
+	if dir == "" {
+		return nil, errors.New("mail directory is not configured")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileSender{from: from, dir: dir}, nil
+}
+
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405"), uuid.New())
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	// Письмо содержит токен сброса пароля, поэтому доступно только владельцу
+	return os.WriteFile(filepath.Join(s.dir, name), []byte(b.String()), 0600)
+}
@@ -0,0 +1,32 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+	"music-service/internal/logging"
+)
+
+// LogSender пишет письма в лог вместо отправки. Тело письма проходит через
+// маскирование секретов, поэтому ссылки со сбросом пароля в логе не
+// работают — для ручной проверки писем есть драйвер "file"
+type LogSender struct {
+	from   string
+	logger *slog.Logger
+}
+
+func NewLogSender(from string, logger *slog.Logger) *LogSender {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LogSender{from: from, logger: logger}
+}
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	s.logger.InfoContext(ctx, "mail message",
+		"from", s.from,
+		"to", msg.To,
+		"subject", msg.Subject,
+		"body", logging.Redact(msg.Body),
+	)
+	return nil
+}
@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Message — письмо пользователю в виде простого текста
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender отправляет письма. Реализация выбирается конфигурацией: для
+// локальной разработки письма пишутся в лог или в файлы
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config — параметры отправки почты
+type Config struct {
+	Driver string // "log" или "file"
+	From   string
+	Dir    string // каталог для писем при Driver == "file"
+	Logger *slog.Logger
+}
+
+// NewSender создает отправителя по конфигурации. По умолчанию письма
+// пишутся в лог
+func NewSender(cfg Config) (Sender, error) {
+	switch cfg.Driver {
+	case "", "log":
+		return NewLogSender(cfg.From, cfg.Logger), nil
+	case "file":
+		return NewFileSender(cfg.From, cfg.Dir)
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"music-service/internal/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var message = mail.Message{
+	To:      "listener@example.com",
+	Subject: "Сброс пароля",
+	Body:    "Ссылка:\nhttp://localhost:3000/reset-password?token=raw-reset-token\n",
+}
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sender, err := mail.NewSender(mail.Config{Driver: "file", From: "noreply@example.com", Dir: dir})
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send(context.Background(), message))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+
+	// Письмо со ссылкой для сброса доступно только владельцу
+	info, err := files[0].Info()
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	eml := string(data)
+	assert.Contains(t, eml, "From: noreply@example.com\r\n")
+	assert.Contains(t, eml, "To: listener@example.com\r\n")
+	assert.Contains(t, eml, "Subject: =?utf-8?q?")
+	assert.Contains(t, eml, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	// Ссылка в файле рабочая, переводы строк — CRLF
+	assert.Contains(t, eml, "\r\nhttp://localhost:3000/reset-password?token=raw-reset-token\r\n")
+}
+
+// В лог письмо пишется с замаскированным токеном
+func TestLogSender(t *testing.T) {
+	var out bytes.Buffer
+	sender, err := mail.NewSender(mail.Config{From: "noreply@example.com", Logger: slog.New(slog.NewTextHandler(&out, nil))})
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send(context.Background(), message))
+	assert.Contains(t, out.String(), "listener@example.com")
+	assert.Contains(t, out.String(), "reset-password?token=[REDACTED]")
+	assert.NotContains(t, out.String(), "raw-reset-token")
+}
+
+func TestNewSender_InvalidConfig(t *testing.T) {
+	_, err := mail.NewSender(mail.Config{Driver: "smtp"})
+	assert.Error(t, err)
+	_, err = mail.NewSender(mail.Config{Driver: "file"})
+	assert.Error(t, err)
+}
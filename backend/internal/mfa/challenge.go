@@ -0,0 +1,20 @@
+package mfa
+
+import "time"
+
+// ChallengeRequired возвращается вместо сессии, когда пароль верен, но
+// требуется второй фактор. Token подтверждает первый шаг входа; с ним
+// клиент отправляет код или, если EnrollmentRequired, сначала подключает
+// TOTP
+type ChallengeRequired struct {
+	Token              string
+	ExpiresAt          time.Time
+	EnrollmentRequired bool
+}
+
+func (e *ChallengeRequired) Error() string {
+	if e.EnrollmentRequired {
+		return "two-factor enrollment required"
+	}
+	return "two-factor verification required"
+}
@@ -0,0 +1,34 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+)
+
+// RecoveryCodeCount — сколько кодов восстановления выдается за раз
+const RecoveryCodeCount = 10
+
+var recoveryEncoding = base32.NewEncoding("abcdefghijkmnpqrstuvwxyz23456789").WithPadding(base32.NoPadding)
+
+// NewRecoveryCodes возвращает n одноразовых кодов вида "xxxxx-xxxxx"
+// (50 бит). Алфавит без похожих символов 0/o и 1/l
+func NewRecoveryCodes(n int) []string {
+	codes := make([]string, n)
+	for i := range codes {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			panic(err)
+		}
+		code := recoveryEncoding.EncodeToString(buf)[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+	return codes
+}
+
+// NormalizeRecoveryCode приводит введенный код к виду, в котором он
+// хешируется: без дефисов, пробелов и в нижнем регистре
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"music-service/internal/mfa"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Секрет из RFC 6238 ("12345678901234567890") в base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// Тестовые значения RFC 6238 (SHA-1), последние 6 цифр
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, v := range vectors {
+		code, err := mfa.Code(rfcSecret, mfa.Step(time.Unix(v.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, v.code, code, v.unix)
+	}
+
+	// Секрет в нижнем регистре тоже принимается
+	code, err := mfa.Code(strings.ToLower(rfcSecret), mfa.Step(time.Unix(59, 0)))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+
+	_, err = mfa.Code("not base32!", 1)
+	assert.ErrorIs(t, err, mfa.ErrInvalidSecret)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := mfa.Step(now)
+
+	// Код текущего интервала
+	t.Run("current step", func(t *testing.T) {
+		step, ok := mfa.Validate(rfcSecret, "050471", now)
+		assert.True(t, ok)
+		assert.Equal(t, current, step)
+	})
+
+	// Коды соседних интервалов принимаются из-за расхождения часов
+	t.Run("adjacent steps", func(t *testing.T) {
+		previous, err := mfa.Code(rfcSecret, current-1)
+		require.NoError(t, err)
+		step, ok := mfa.Validate(rfcSecret, previous, now)
+		assert.True(t, ok)
+		assert.Equal(t, current-1, step)
+
+		next, err := mfa.Code(rfcSecret, current+1)
+		require.NoError(t, err)
+		step, ok = mfa.Validate(rfcSecret, next, now)
+		assert.True(t, ok)
+		assert.Equal(t, current+1, step)
+	})
+
+	// Код за пределами допуска
+	t.Run("outside window", func(t *testing.T) {
+		old, err := mfa.Code(rfcSecret, current-2)
+		require.NoError(t, err)
+		_, ok := mfa.Validate(rfcSecret, old, now)
+		assert.False(t, ok)
+	})
+
+	// Неверный формат кода
+	t.Run("malformed", func(t *testing.T) {
+		for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+			_, ok := mfa.Validate(rfcSecret, code, now)
+			assert.False(t, ok, code)
+		}
+	})
+
+	// Пробелы, которые вставляют некоторые приложения, игнорируются
+	t.Run("spaces", func(t *testing.T) {
+		_, ok := mfa.Validate(rfcSecret, " 050 471 ", now)
+		assert.True(t, ok)
+	})
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret := mfa.GenerateSecret()
+	assert.Len(t, secret, 32)
+	assert.NotEqual(t, secret, mfa.GenerateSecret())
+
+	_, err := mfa.Code(secret, 1)
+	assert.NoError(t, err)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := mfa.ProvisioningURI("Music Service", "alice", rfcSecret)
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Music Service:alice", parsed.Path)
+	assert.Equal(t, rfcSecret, parsed.Query().Get("secret"))
+	assert.Equal(t, "Music Service", parsed.Query().Get("issuer"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes := mfa.NewRecoveryCodes(mfa.RecoveryCodeCount)
+	assert.Len(t, codes, mfa.RecoveryCodeCount)
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z2-9]{5}-[a-z2-9]{5}$`, code)
+		assert.False(t, seen[code])
+		seen[code] = true
+	}
+
+	assert.Equal(t, "abcdefghij", mfa.NormalizeRecoveryCode(" ABCDE-fghij "))
+}
@@ -0,0 +1,100 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Параметры TOTP (RFC 6238) по умолчанию: их поддерживают все приложения-
+// аутентификаторы, поэтому в URI они не передаются
+const (
+	Digits = 6
+	Period = 30 * time.Second
+	// Skew — сколько соседних интервалов принимается из-за расхождения часов
+	Skew = 1
+)
+
+var ErrInvalidSecret = errors.New("invalid totp secret")
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret возвращает случайный секрет (160 бит) в base32 без
+// выравнивания — в таком виде его принимают приложения-аутентификаторы
+func GenerateSecret() string {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return secretEncoding.EncodeToString(buf)
+}
+
+// ProvisioningURI возвращает otpauth-URI для QR-кода приложения-аутентификатора
+func ProvisioningURI(issuer, account, secret string) string {
+	params := url.Values{
+		"secret": {secret},
+		"issuer": {issuer},
+	}
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// Step возвращает номер интервала TOTP для момента t
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// Code вычисляет код для интервала step
+func Code(secret string, step int64) (string, error) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil || len(key) == 0 {
+		return "", ErrInvalidSecret
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Динамическое усечение (RFC 4226, раздел 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%mod), nil
+}
+
+// Validate проверяет код на момент t с допуском Skew интервалов и
+// возвращает интервал, которому код соответствует. Чтобы код нельзя было
+// использовать повторно, вызывающий сохраняет интервал и не принимает коды
+// с номером не больше сохраненного
+func Validate(secret, code string, t time.Time) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(t)
+	for delta := int64(-Skew); delta <= Skew; delta++ {
+		expected, err := Code(secret, current+delta)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return current + delta, true
+		}
+	}
+	return 0, false
+}
@@ -14,4 +14,6 @@ type Album struct {
 	CoverURL    string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	// Loudness — громкость альбома по его измеренным трекам или nil
+	Loudness *Loudness
 }
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Действия, записываемые в журнал
+const (
+	AuditUserSuspend       = "user.suspend"
+	AuditUserUnsuspend     = "user.unsuspend"
+	AuditUserForceLogout   = "user.force_logout"
+	AuditUserPermission    = "user.permission_change"
+	AuditUserDelete        = "user.delete"
+	AuditUserCreateAdmin   = "user.create_admin"
+	AuditUserPasswordSet   = "user.password_set"
+	AuditUserMFAReset      = "user.mfa_reset"
+	AuditUserViewPlaylists = "user.view_playlists"
+	AuditUserViewHistory   = "user.view_history"
+	AuditMFAPolicyChange   = "mfa.required_roles_change"
+
+	AuditTrackUpload      = "track.upload"
+	AuditTrackUpdate      = "track.update"
+	AuditTrackDelete      = "track.delete"
+	AuditTrackGenreAdd    = "track.genre_add"
+	AuditTrackGenreRemove = "track.genre_remove"
+	AuditAlbumCreate      = "album.create"
+	AuditAlbumUpdate      = "album.update"
+	AuditAlbumDelete      = "album.delete"
+	AuditAlbumTrackAdd    = "album.track_add"
+	AuditAlbumTrackRemove = "album.track_remove"
+	AuditGenreCreate      = "genre.create"
+	AuditArtistCoverSet   = "artist.cover_set"
+	AuditLyricsSet        = "lyrics.set"
+	AuditLyricsModerate   = "lyrics.moderate"
+	AuditLyricsDelete     = "lyrics.delete"
+	AuditTrackMerge       = "track.merge"
+
+	AuditTranslationSet    = "translation.set"
+	AuditTranslationDelete = "translation.delete"
+
+	AuditTrackRestore    = "track.restore"
+	AuditAlbumRestore    = "album.restore"
+	AuditPlaylistRestore = "playlist.restore"
+	AuditUserRestore     = "user.restore"
+	AuditTrashPurge      = "trash.purge"
+)
+
+// Типы сущностей в журнале
+const (
+	AuditEntityUser      = "user"
+	AuditEntityMFAPolicy = "mfa_policy"
+	AuditEntityTrack     = "track"
+	AuditEntityAlbum     = "album"
+	AuditEntityGenre     = "genre"
+	AuditEntityPlaylist  = "playlist"
+	AuditEntityTrash     = "trash"
+	AuditEntityArtist    = "artist"
+)
+
+// AuditEntry — запись журнала действий. ActorID == nil для служебных
+// операций; ActorLogin сохраняется, чтобы запись оставалась понятной после
+// удаления пользователя. Changes — значения измененных полей до и после
+// действия, IP и RequestID — запрос, в котором оно выполнено (пусты для CLI
+// и фоновых задач)
+type AuditEntry struct {
+	ID         uuid.UUID              `json:"id"`
+	ActorID    *uuid.UUID             `json:"actor_id,omitempty"`
+	ActorLogin string                 `json:"actor_login"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id,omitempty"`
+	Changes    map[string]AuditChange `json:"changes,omitempty"`
+	Details    json.RawMessage        `json:"details,omitempty"`
+	IP         string                 `json:"ip,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// AuditChange — значение поля до и после действия в JSON. From пуст при
+// создании сущности, To — при удалении
+type AuditChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// AuditFilter — параметры выборки журнала; пустые поля не ограничивают
+// выборку. From включается в интервал, To — нет
+type AuditFilter struct {
+	ActorID    *uuid.UUID
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// AuditPage — страница журнала, общее количество записей и примененные
+// размер страницы и смещение
+type AuditPage struct {
+	Entries []*AuditEntry
+	Total   int
+	Limit   int
+	Offset  int
+}
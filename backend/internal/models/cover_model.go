@@ -0,0 +1,68 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Объекты, у которых может быть обложка
+const (
+	CoverTrack    = "track"
+	CoverAlbum    = "album"
+	CoverPlaylist = "playlist"
+	CoverArtist   = "artist"
+)
+
+// CoverOriginal — размер обложки "как загружено": оригинал без метаданных
+const CoverOriginal = "original"
+
+// coverURLPrefix — путь, по которому отдаются загруженные обложки
+const coverURLPrefix = "/api/v1/covers/"
+
+// Cover — загруженная обложка. Width и Height — размеры оригинала после
+// поворота по EXIF
+type Cover struct {
+	ID         uuid.UUID `json:"id"`
+	Format     string    `json:"format"`
+	Width      int       `json:"width"`
+	Height     int       `json:"height"`
+	SizeBytes  int64     `json:"size_bytes"`
+	UploadedBy uuid.UUID `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// URL возвращает адрес обложки; размер выбирается параметром ?size=
+func (c *Cover) URL() string {
+	return CoverURL(c.ID)
+}
+
+// CoverURL возвращает адрес загруженной обложки для поля cover_url
+func CoverURL(id uuid.UUID) string {
+	return coverURLPrefix + id.String()
+}
+
+// CoverIDFromURL возвращает ID обложки, если url указывает на загруженную
+// обложку. Для внешних адресов, заданных клиентом, ok == false
+func CoverIDFromURL(url string) (uuid.UUID, bool) {
+	rest, ok := strings.CutPrefix(url, coverURLPrefix)
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(rest)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// CoverFile — файл обложки, выбранный для ответа
+type CoverFile struct {
+	Path        string
+	ContentType string
+	ETag        string
+	// Immutable — содержимое по этому адресу никогда не меняется, и клиент
+	// может кешировать его без перепроверки
+	Immutable bool
+}
@@ -0,0 +1,187 @@
+package models
+
+// Доменные ошибки, которые возвращают use case. Коды входят в контракт API:
+// их нельзя переименовывать, только добавлять новые
+
+// Пользователи и вход
+var (
+	ErrUserNotFound        = NewDomainError(ErrNotFound, "user_not_found", "user not found")
+	ErrLoginTaken          = &DomainError{Kind: ErrConflict, Code: "login_taken", Message: "user already exists", Field: "login"}
+	ErrEmailTaken          = &DomainError{Kind: ErrConflict, Code: "email_taken", Message: "email is already in use", Field: "email"}
+	ErrLoginTooShort       = NewFieldError("login_too_short", "login", "login must be at least 6 characters")
+	ErrEmailTooLong        = NewFieldError("email_too_long", "email", "email is too long")
+	ErrInvalidEmail        = NewFieldError("invalid_email", "email", "invalid email format")
+	ErrEmailRequired       = NewFieldError("email_required", "email", "email is required")
+	ErrInvalidPermission   = NewFieldError("invalid_permission", "permission", "invalid permission")
+	ErrInvalidCredentials  = NewDomainError(ErrUnauthorized, "invalid_credentials", "invalid credentials")
+	ErrStaffSSORequired    = NewDomainError(ErrForbidden, "staff_sso_required", "staff accounts must sign in with company SSO")
+	ErrInvalidRefreshToken = NewDomainError(ErrUnauthorized, "invalid_refresh_token", "invalid refresh token")
+	ErrRefreshTokenReused  = NewDomainError(ErrUnauthorized, "refresh_token_reused", "refresh token reuse detected")
+	ErrMissingToken        = NewDomainError(ErrUnauthorized, "missing_token", "access token is required")
+	ErrMalformedToken      = NewDomainError(ErrUnauthorized, "malformed_token", "malformed authorization header")
+	ErrInvalidAccessToken  = NewDomainError(ErrUnauthorized, "invalid_token", "invalid access token")
+	ErrSessionNotFound     = NewDomainError(ErrNotFound, "session_not_found", "session not found")
+	ErrRateLimited         = NewDomainError(ErrTooManyRequests, "rate_limited", "too many requests")
+)
+
+// Пароли
+var (
+	ErrInvalidCurrentPassword = NewFieldError("invalid_current_password", "current_password", "invalid current password")
+	ErrPasswordUnchanged      = NewFieldError("password_unchanged", "new_password", "new password must differ from the current one")
+	ErrInvalidResetToken      = NewFieldError("invalid_reset_token", "token", "invalid or expired reset token")
+	ErrMailUnavailable        = NewDomainError(ErrUnavailable, "mail_unavailable", "failed to send reset email")
+)
+
+// Двухфакторная аутентификация
+var (
+	ErrInvalidMFAToken         = NewDomainError(ErrUnauthorized, "invalid_mfa_token", "invalid or expired mfa token")
+	ErrInvalidVerificationCode = &DomainError{Kind: ErrUnauthorized, Code: "invalid_verification_code", Message: "invalid verification code", Field: "code"}
+	ErrMFAEnrollmentNotStarted = NewDomainError(ErrConflict, "mfa_enrollment_not_started", "two-factor enrollment not started")
+	ErrMFAAlreadyEnabled       = NewDomainError(ErrConflict, "mfa_already_enabled", "two-factor authentication is already enabled")
+	ErrMFANotEnabled           = NewDomainError(ErrConflict, "mfa_not_enabled", "two-factor authentication is not enabled")
+	ErrMFARequired             = NewDomainError(ErrForbidden, "mfa_required", "two-factor authentication is required for this role")
+	ErrInvalidRole             = NewFieldError("invalid_role", "roles", "invalid role")
+)
+
+// Внешние провайдеры входа
+var (
+	ErrUnknownProvider       = NewDomainError(ErrNotFound, "unknown_provider", "unknown identity provider")
+	ErrProviderUnavailable   = NewDomainError(ErrUnavailable, "provider_unavailable", "identity provider is unavailable")
+	ErrInvalidLoginState     = NewDomainError(ErrInvalidInput, "invalid_login_state", "invalid login state")
+	ErrExternalAuthFailed    = NewDomainError(ErrUnauthorized, "external_auth_failed", "external authentication failed")
+	ErrExternalAuthCancelled = NewDomainError(ErrUnauthorized, "external_auth_cancelled", "external authentication cancelled")
+	ErrIdentityLinked        = NewDomainError(ErrConflict, "identity_linked", "identity is linked to another user")
+	ErrIdentityNotFound      = NewDomainError(ErrNotFound, "identity_not_found", "identity not found")
+	ErrLastSignInMethod      = NewDomainError(ErrConflict, "last_sign_in_method", "cannot unlink the only sign-in method")
+)
+
+// Администрирование
+var (
+	ErrInvalidUserStatus        = NewFieldError("invalid_status", "status", "invalid status")
+	ErrSuspensionReasonRequired = NewFieldError("suspension_reason_required", "reason", "suspension reason is required")
+	ErrSuspensionReasonTooLong  = NewFieldError("suspension_reason_too_long", "reason", "suspension reason is too long")
+	ErrSuspensionEndInPast      = NewFieldError("suspension_end_in_past", "until", "suspension end must be in the future")
+	ErrCannotSuspendSelf        = NewDomainError(ErrInvalidInput, "cannot_suspend_self", "cannot suspend yourself")
+	ErrUserNotSuspended         = NewDomainError(ErrConflict, "user_not_suspended", "user is not suspended")
+	ErrInvalidTimeRange         = NewFieldError("invalid_time_range", "from", "invalid time range")
+)
+
+// Треки
+var (
+	ErrTrackNotFound       = NewDomainError(ErrNotFound, "track_not_found", "track not found")
+	ErrTrackFileNotFound   = NewDomainError(ErrNotFound, "track_file_not_found", "track file not found")
+	ErrTrackTooLarge       = &DomainError{Kind: ErrTooLarge, Code: "track_too_large", Message: "track file is too large", Field: "file"}
+	ErrTrackAlbumNotFound  = &DomainError{Kind: ErrAlbumNotFound, Code: "track_album_not_found", Message: "album not found", Field: "album_id"}
+	ErrTrackTitleRequired  = NewFieldError("track_title_required", "title", "track title is required")
+	ErrTrackTitleTooLong   = NewFieldError("track_title_too_long", "title", "title is too long")
+	ErrTrackArtistRequired = NewFieldError("track_artist_required", "artist_name", "artist name is required")
+	ErrTrackAlbumRequired  = NewFieldError("track_album_required", "album_id", "album is required")
+	ErrTrackModified       = NewDomainError(ErrPreconditionFailed, "track_modified", "track was modified by another request")
+	ErrInvalidISRC         = NewFieldError("invalid_isrc", "isrc", "invalid ISRC")
+	ErrSearchQueryTooShort = NewFieldError("search_query_too_short", "q", "search query must be at least 3 characters")
+	ErrTrackTooShort       = NewDomainError(ErrInvalidInput, "track_too_short", "track is too short to record playback")
+	ErrPlayedTooFrequently = NewDomainError(ErrTooManyRequests, "played_too_frequently", "track played too frequently")
+)
+
+// Альбомы и исполнители
+var (
+	ErrAlbumNotFound       = NewDomainError(ErrNotFound, "album_not_found", "album not found")
+	ErrAlbumTitleTooShort  = NewFieldError("album_title_too_short", "title", "album title must be at least 2 characters")
+	ErrAlbumTitleTooLong   = NewFieldError("album_title_too_long", "title", "album title is too long (max 100 characters)")
+	ErrArtistNameRequired  = NewFieldError("artist_name_required", "artist", "artist name is required")
+	ErrArtistNameTooLong   = NewFieldError("artist_name_too_long", "artist", "artist name is too long (max 100 characters)")
+	ErrReleaseDateInFuture = NewFieldError("release_date_in_future", "release_date", "release date cannot be in the future")
+	ErrInvalidCoverURL     = NewFieldError("invalid_cover_url", "cover_url", "invalid cover URL format")
+	ErrAlbumExists         = NewDomainError(ErrConflict, "album_exists", "album with this title and artist already exists")
+	ErrTrackInAnotherAlbum = NewDomainError(ErrConflict, "track_in_another_album", "track already belongs to another album")
+	ErrTrackAlreadyInAlbum = NewDomainError(ErrConflict, "track_already_in_album", "track already exists in album")
+	ErrAlbumTrackLimit     = NewDomainError(ErrConflict, "album_track_limit", "album cannot contain more than 50 tracks")
+	ErrTrackNotInAlbum     = NewDomainError(ErrNotFound, "track_not_in_album", "track does not belong to this album")
+)
+
+// Обложки
+var (
+	ErrCoverNotFound     = NewDomainError(ErrNotFound, "cover_not_found", "cover not found")
+	ErrCoverFileRequired = NewFieldError("cover_file_required", "file", "cover image file is required")
+	ErrUnsupportedImage  = NewFieldError("unsupported_image", "file", "file is not a JPEG, PNG or GIF image")
+	ErrImageTooLarge     = NewFieldError("image_too_large", "file", "image resolution is too large")
+	ErrInvalidCoverSize  = NewFieldError("invalid_cover_size", "size", "unsupported cover size")
+)
+
+// Тексты песен
+var (
+	ErrLyricsNotFound      = NewDomainError(ErrNotFound, "lyrics_not_found", "lyrics not found")
+	ErrLyricsEmpty         = NewFieldError("lyrics_empty", "text", "lyrics text is required")
+	ErrLyricsTooLong       = NewFieldError("lyrics_too_long", "text", "lyrics text is too long")
+	ErrLyricsExist         = NewDomainError(ErrConflict, "lyrics_exist", "track already has lyrics")
+	ErrInvalidLyricsStatus = NewFieldError("invalid_lyrics_status", "status", "invalid lyrics status")
+)
+
+// Громкость
+var (
+	ErrLoudnessUnavailable = NewDomainError(ErrUnavailable, "loudness_unavailable", "loudness analysis is not available for this file")
+	ErrTrackSilent         = NewDomainError(ErrInvalidInput, "track_silent", "track is silent, loudness is undefined")
+)
+
+// Дубликаты
+var (
+	ErrDuplicateTrack         = NewDomainError(ErrConflict, "duplicate_track", "track is a duplicate of a catalog track")
+	ErrFingerprintUnavailable = NewDomainError(ErrUnavailable, "fingerprint_unavailable", "fingerprint cannot be computed for this file")
+	ErrMergeSourcesRequired   = NewFieldError("merge_sources_required", "source_ids", "at least one source track is required")
+	ErrMergeTargetInSources   = NewFieldError("merge_target_in_sources", "source_ids", "target track cannot be merged into itself")
+	ErrMergeSourcesLimit      = NewFieldError("merge_sources_limit", "source_ids", "too many source tracks")
+)
+
+// Жанры
+var (
+	ErrGenreNotFound        = NewDomainError(ErrNotFound, "genre_not_found", "genre not found")
+	ErrGenreNameTooShort    = NewFieldError("genre_name_too_short", "name", "genre name must be at least 2 characters")
+	ErrGenreNameTooLong     = NewFieldError("genre_name_too_long", "name", "genre name is too long (max 50 characters)")
+	ErrGenreExists          = NewDomainError(ErrConflict, "genre_exists", "genre already exists")
+	ErrGenreAlreadyAssigned = NewDomainError(ErrConflict, "genre_already_assigned", "genre already assigned to track")
+	ErrTrackGenreLimit      = NewDomainError(ErrConflict, "track_genre_limit", "track cannot have more than 5 genres")
+	ErrGenreNotAssigned     = NewDomainError(ErrNotFound, "genre_not_assigned", "genre is not assigned to this track")
+)
+
+// Плейлисты и подписки
+var (
+	ErrPlaylistNotFound           = NewDomainError(ErrNotFound, "playlist_not_found", "playlist not found")
+	ErrPlaylistNameTooShort       = NewFieldError("playlist_name_too_short", "name", "playlist name must be at least 2 characters")
+	ErrPlaylistNameTooLong        = NewFieldError("playlist_name_too_long", "name", "playlist name is too long")
+	ErrPlaylistDescriptionTooLong = NewFieldError("playlist_description_too_long", "description", "description is too long")
+	ErrTrackAlreadyInPlaylist     = NewDomainError(ErrConflict, "track_already_in_playlist", "track already exists in playlist")
+	ErrPlaylistTrackLimit         = NewDomainError(ErrConflict, "playlist_track_limit", "playlist track limit reached")
+	ErrTrackNotInPlaylist         = NewDomainError(ErrNotFound, "track_not_in_playlist", "track not found in playlist")
+	ErrPlaylistAccessDenied       = NewDomainError(ErrForbidden, "playlist_access_denied", "user is not the owner of the playlist")
+	ErrCannotFollowOwnPlaylist    = NewDomainError(ErrInvalidInput, "cannot_follow_own_playlist", "cannot follow own playlist")
+)
+
+// Воспроизведение
+var (
+	ErrQueueTooLong         = NewFieldError("queue_too_long", "track_ids", "queue is too long")
+	ErrInvalidStartIndex    = NewFieldError("invalid_start_index", "start_index", "invalid start index")
+	ErrInvalidQueuePosition = NewFieldError("invalid_queue_position", "position", "invalid queue position")
+	ErrInvalidQueueIndex    = NewFieldError("invalid_queue_index", "index", "invalid queue index")
+	ErrPositionOutOfBounds  = NewFieldError("position_out_of_bounds", "position_ms", "position is out of track bounds")
+	ErrInvalidRepeatMode    = NewFieldError("invalid_repeat_mode", "mode", "invalid repeat mode")
+	ErrQueueEmpty           = NewDomainError(ErrConflict, "queue_empty", "queue is empty")
+	ErrDeviceNotFound       = NewDomainError(ErrNotFound, "device_not_found", "device not found")
+)
+
+// Корзина
+var (
+	ErrInvalidTrashType  = NewFieldError("invalid_trash_type", "type", "invalid trash type")
+	ErrTrashItemNotFound = NewDomainError(ErrNotFound, "trash_item_not_found", "item not found")
+	ErrTrashAlbumDeleted = NewDomainError(ErrConflict, "album_deleted", "album is deleted")
+	ErrTrashOwnerDeleted = NewDomainError(ErrConflict, "owner_deleted", "owner is deleted")
+)
+
+// Локализация
+var (
+	ErrInvalidLocale           = NewFieldError("invalid_locale", "locale", "invalid language tag")
+	ErrInvalidTranslationType  = NewFieldError("invalid_translation_type", "type", "invalid translation type")
+	ErrTranslationEmpty        = NewFieldError("translation_empty", "fields", "translation must contain at least one field")
+	ErrUnknownTranslationField = NewFieldError("unknown_translation_field", "fields", "field cannot be translated")
+	ErrTranslationTooLong      = NewFieldError("translation_too_long", "fields", "translation is too long")
+	ErrTranslationNotFound     = NewDomainError(ErrNotFound, "translation_not_found", "translation not found")
+)
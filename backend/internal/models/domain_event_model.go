@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы доменных событий
+const (
+	EventTrackUploaded    = "TrackUploaded"
+	EventTrackDeleted     = "TrackDeleted"
+	EventPlaylistChanged  = "PlaylistChanged"
+	EventPlaybackRecorded = "PlaybackRecorded"
+	EventUserDeleted      = "UserDeleted"
+)
+
+// Виды изменений плейлиста в событии PlaylistChanged
+const (
+	PlaylistCreated      = "created"
+	PlaylistUpdated      = "updated"
+	PlaylistTrackAdded   = "track_added"
+	PlaylistTrackRemoved = "track_removed"
+	PlaylistDeleted      = "deleted"
+)
+
+// DomainEvent — событие, записанное в outbox в одной транзакции с изменением
+// состояния и доставляемое подписчикам асинхронно
+type DomainEvent struct {
+	ID            uuid.UUID
+	Type          string
+	AggregateID   uuid.UUID
+	Payload       json.RawMessage
+	OccurredAt    time.Time
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+func NewDomainEvent(eventType string, aggregateID uuid.UUID, payload interface{}) (*DomainEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &DomainEvent{
+		ID:            uuid.New(),
+		Type:          eventType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+		OccurredAt:    now,
+		NextAttemptAt: now,
+	}, nil
+}
+
+type TrackUploadedPayload struct {
+	TrackID    uuid.UUID `json:"track_id"`
+	AlbumID    uuid.UUID `json:"album_id"`
+	Title      string    `json:"title"`
+	ArtistName string    `json:"artist_name"`
+	FilePath   string    `json:"file_path"`
+}
+
+type TrackDeletedPayload struct {
+	TrackID  uuid.UUID `json:"track_id"`
+	FilePath string    `json:"file_path"`
+}
+
+type PlaylistChangedPayload struct {
+	PlaylistID uuid.UUID  `json:"playlist_id"`
+	OwnerID    uuid.UUID  `json:"owner_id"`
+	Change     string     `json:"change"`
+	TrackID    *uuid.UUID `json:"track_id,omitempty"`
+}
+
+type PlaybackRecordedPayload struct {
+	UserID     uuid.UUID `json:"user_id"`
+	TrackID    uuid.UUID `json:"track_id"`
+	ListenedAt time.Time `json:"listened_at"`
+}
+
+type UserDeletedPayload struct {
+	UserID uuid.UUID `json:"user_id"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DuplicatePair — пара треков с похожими акустическими отпечатками.
+// Similarity — сходство от 0 до 1
+type DuplicatePair struct {
+	TrackID     uuid.UUID `json:"track_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+	Similarity  float64   `json:"similarity"`
+	DetectedAt  time.Time `json:"detected_at"`
+}
+
+// DuplicateMatch — трек каталога, похожий на проверяемый
+type DuplicateMatch struct {
+	Track      *Track  `json:"track"`
+	Similarity float64 `json:"similarity"`
+}
+
+// DuplicateCluster — группа треков, связанных парами подозреваемых
+// дубликатов. Similarity — наибольшее сходство среди пар
+type DuplicateCluster struct {
+	Tracks     []*Track         `json:"tracks"`
+	Pairs      []*DuplicatePair `json:"pairs"`
+	Similarity float64          `json:"similarity"`
+}
+
+// DuplicateClusterPage — страница групп дубликатов, самые похожие — первыми
+type DuplicateClusterPage struct {
+	Items  []*DuplicateCluster
+	Total  int
+	Limit  int
+	Offset int
+}
+
+// DuplicateError — загружаемый трек похож на треки каталога, а загрузка
+// дубликатов запрещена. Matches — найденные треки
+type DuplicateError struct {
+	Matches []*DuplicateMatch
+}
+
+func (e *DuplicateError) Error() string {
+	return "track is a duplicate"
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrDuplicateTrack)
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicateTrack
+}
@@ -2,9 +2,45 @@ package models
 
 import "errors"
 
+// Категории ошибок. Каждая доменная ошибка относится к одной из них; по
+// категории транспортный слой выбирает статус ответа
 var (
-	ErrNotFound     = errors.New("not found")
-	ErrInvalidInput = errors.New("invalid input")
-	ErrUnauthorized = errors.New("unauthorized")
-	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrInvalidInput       = errors.New("invalid input")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrTooManyRequests    = errors.New("too many requests")
+	ErrTooLarge           = errors.New("payload too large")
+	ErrUnavailable        = errors.New("unavailable")
 )
+
+// DomainError — нарушение бизнес-правила. Code — стабильный
+// машиночитаемый код, по нему клиенты различают ошибки, а сервер выбирает
+// сообщение для пользователя. Message — описание для логов, Field — поле
+// запроса, к которому относится ошибка. errors.Is(err, Kind) выполняется
+type DomainError struct {
+	Kind    error
+	Code    string
+	Message string
+	Field   string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func (e *DomainError) Unwrap() error {
+	return e.Kind
+}
+
+// NewDomainError создает доменную ошибку категории kind
+func NewDomainError(kind error, code, message string) *DomainError {
+	return &DomainError{Kind: kind, Code: code, Message: message}
+}
+
+// NewFieldError создает ошибку проверки поля запроса
+func NewFieldError(code, field, message string) *DomainError {
+	return &DomainError{Kind: ErrInvalidInput, Code: code, Message: message, Field: field}
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity — учетная запись внешнего провайдера OpenID Connect, привязанная
+// к пользователю. Subject уникален в пределах провайдера
+type Identity struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OAuthState — незавершенный вход через провайдера. StateHash — SHA-256
+// параметра state; LinkUserID задан, если вход начат для привязки учетной
+// записи к уже вошедшему пользователю
+type OAuthState struct {
+	StateHash    string
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	LinkUserID   uuid.UUID
+	ExpiresAt    time.Time
+}
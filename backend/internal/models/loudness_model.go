@@ -0,0 +1,24 @@
+package models
+
+import "math"
+
+// ReplayGainReference — опорная громкость ReplayGain 2.0, LUFS
+const ReplayGainReference = -18.0
+
+// Loudness — громкость трека или альбома по EBU R128
+type Loudness struct {
+	IntegratedLUFS float64
+	TruePeakDBTP   float64
+}
+
+// Gain — усиление ReplayGain в дБ, приводящее громкость к опорной
+func (l *Loudness) Gain() float64 {
+	return ReplayGainReference - l.IntegratedLUFS
+}
+
+// Peak — истинный пик в линейной шкале (1 — полная шкала), как в теге
+// REPLAYGAIN_TRACK_PEAK. Плеер ограничивает по нему усиление, чтобы
+// избежать перегрузки
+func (l *Loudness) Peak() float64 {
+	return math.Pow(10, l.TruePeakDBTP/20)
+}
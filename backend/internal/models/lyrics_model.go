@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы модерации текста песни. Опубликованный текст виден всем,
+// остальные — модераторам и автору
+const (
+	LyricsPending  = "pending"
+	LyricsApproved = "approved"
+	LyricsRejected = "rejected"
+)
+
+// Источники текста песни
+const (
+	LyricsSourceUpload = "upload"
+	LyricsSourceID3    = "id3"
+)
+
+// Lyrics — текст песни трека. Text хранится как загружен: в формате LRC
+// для синхронизированного текста. PlainText — текст без меток времени для
+// поиска; Lines заполняется при выдаче
+type Lyrics struct {
+	TrackID     uuid.UUID    `json:"track_id"`
+	Language    string       `json:"language,omitempty"`
+	Synced      bool         `json:"synced"`
+	Text        string       `json:"text"`
+	PlainText   string       `json:"-"`
+	Source      string       `json:"source"`
+	Status      string       `json:"status"`
+	SubmittedBy uuid.UUID    `json:"-"`
+	ModeratedBy uuid.UUID    `json:"-"`
+	ModeratedAt *time.Time   `json:"moderated_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Lines       []LyricsLine `json:"lines,omitempty"`
+}
+
+// LyricsLine — строка текста. TimeMs — начало строки в миллисекундах, nil
+// у несинхронизированного текста; пустой Text — разрыв между куплетами
+type LyricsLine struct {
+	TimeMs *int64 `json:"time_ms"`
+	Text   string `json:"text"`
+}
+
+// IsValidLyricsStatus проверяет статус модерации
+func IsValidLyricsStatus(status string) bool {
+	switch status {
+	case LyricsPending, LyricsApproved, LyricsRejected:
+		return true
+	}
+	return false
+}
+
+// LyricsFilter — параметры выборки текстов для модерации; пустой Status в
+// репозитории означает все статусы
+type LyricsFilter struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+// LyricsPage — страница текстов, недавно измененные — первыми
+type LyricsPage struct {
+	Items  []*Lyrics
+	Total  int
+	Limit  int
+	Offset int
+}
+
+// LyricsMatch — трек, найденный по тексту песни. Snippet — фрагмент текста
+// с совпадениями, выделенными «»
+type LyricsMatch struct {
+	Track   *Track  `json:"track"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
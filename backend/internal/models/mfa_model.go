@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTPCredential — секрет TOTP пользователя. Пока ConfirmedAt пуст,
+// подключение не завершено. LastUsedStep — интервал последнего принятого
+// кода: коды этого и более ранних интервалов не принимаются
+type TOTPCredential struct {
+	UserID       uuid.UUID
+	Secret       string
+	LastUsedStep int64
+	ConfirmedAt  *time.Time
+	CreatedAt    time.Time
+}
+
+func (c *TOTPCredential) Confirmed() bool {
+	return c != nil && c.ConfirmedAt != nil
+}
+
+// TOTPEnrollment — данные для подключения приложения-аутентификатора.
+// URI отображается клиентом в виде QR-кода
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"provisioning_uri"`
+}
+
+// MFAChallenge — вход, ожидающий второго фактора. TokenHash — SHA-256
+// токена, выданного клиенту после проверки пароля
+type MFAChallenge struct {
+	TokenHash string
+	UserID    uuid.UUID
+	Device    Device
+	Attempts  int
+	ExpiresAt time.Time
+}
+
+// MFAStatus — состояние двухфакторной аутентификации пользователя
+type MFAStatus struct {
+	Enabled                bool `json:"enabled"`
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// MFALogin — результат второго шага входа. RecoveryCodes заполняются, только
+// если на этом шаге было завершено подключение TOTP
+type MFALogin struct {
+	User          *User
+	Session       *Session
+	Tokens        *TokenPair
+	RecoveryCodes []string
+}
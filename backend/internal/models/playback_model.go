@@ -0,0 +1,123 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RepeatMode string
+
+const (
+	RepeatOff RepeatMode = "off"
+	RepeatAll RepeatMode = "all"
+	RepeatOne RepeatMode = "one"
+)
+
+func (m RepeatMode) IsValid() bool {
+	switch m {
+	case RepeatOff, RepeatAll, RepeatOne:
+		return true
+	default:
+		return false
+	}
+}
+
+// PlaybackState — серверное состояние воспроизведения пользователя.
+// Queue хранится в порядке добавления, при включенном shuffle порядок
+// воспроизведения задается ShuffleOrder (перестановка индексов Queue).
+// CurrentIndex указывает на позицию в порядке воспроизведения.
+type PlaybackState struct {
+	UserID         uuid.UUID
+	Queue          []*Track
+	ShuffleOrder   []int
+	CurrentIndex   int
+	PositionMs     int
+	IsPlaying      bool
+	Shuffle        bool
+	RepeatMode     RepeatMode
+	ActiveDeviceID uuid.UUID
+	UpdatedAt      time.Time
+}
+
+// PlayOrder возвращает индексы очереди в порядке воспроизведения
+func (s *PlaybackState) PlayOrder() []int {
+	if s.Shuffle && len(s.ShuffleOrder) == len(s.Queue) {
+		return s.ShuffleOrder
+	}
+	order := make([]int, len(s.Queue))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// CurrentTrack возвращает текущий трек или nil, если очередь пуста
+func (s *PlaybackState) CurrentTrack() *Track {
+	order := s.PlayOrder()
+	if s.CurrentIndex < 0 || s.CurrentIndex >= len(order) {
+		return nil
+	}
+	return s.Queue[order[s.CurrentIndex]]
+}
+
+// Progress возвращает позицию воспроизведения с учетом времени,
+// прошедшего с последнего обновления состояния
+func (s *PlaybackState) Progress(now time.Time) int {
+	position := s.PositionMs
+	if s.IsPlaying && !s.UpdatedAt.IsZero() {
+		position += int(now.Sub(s.UpdatedAt).Milliseconds())
+	}
+	if track := s.CurrentTrack(); track != nil && track.Duration > 0 {
+		if limit := track.Duration * 1000; position > limit {
+			position = limit
+		}
+	}
+	return position
+}
+
+type playbackQueueItem struct {
+	Position int    `json:"position"`
+	Track    *Track `json:"track"`
+}
+
+type playbackStateJSON struct {
+	CurrentTrack   *Track              `json:"current_track"`
+	CurrentIndex   int                 `json:"current_index"`
+	ProgressMs     int                 `json:"progress_ms"`
+	IsPlaying      bool                `json:"is_playing"`
+	Shuffle        bool                `json:"shuffle"`
+	RepeatMode     RepeatMode          `json:"repeat_mode"`
+	ActiveDeviceID *uuid.UUID          `json:"active_device_id"`
+	Queue          []playbackQueueItem `json:"queue"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// MarshalJSON отдает очередь в порядке воспроизведения вместе с исходными
+// позициями треков, которые клиент использует для удаления из очереди
+func (s PlaybackState) MarshalJSON() ([]byte, error) {
+	order := s.PlayOrder()
+	queue := make([]playbackQueueItem, 0, len(order))
+	for _, idx := range order {
+		queue = append(queue, playbackQueueItem{Position: idx, Track: s.Queue[idx]})
+	}
+
+	var activeDevice *uuid.UUID
+	if s.ActiveDeviceID != uuid.Nil {
+		id := s.ActiveDeviceID
+		activeDevice = &id
+	}
+
+	return json.Marshal(playbackStateJSON{
+		CurrentTrack:   s.CurrentTrack(),
+		CurrentIndex:   s.CurrentIndex,
+		ProgressMs:     s.Progress(time.Now()),
+		IsPlaying:      s.IsPlaying,
+		Shuffle:        s.Shuffle,
+		RepeatMode:     s.RepeatMode,
+		ActiveDeviceID: activeDevice,
+		Queue:          queue,
+		UpdatedAt:      s.UpdatedAt,
+	})
+}
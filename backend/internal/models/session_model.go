@@ -6,8 +6,59 @@ import (
 	"github.com/google/uuid"
 )
 
+// Session — вход пользователя с одного устройства. Сессия объединяет
+// цепочку (семейство) refresh-токенов: при обновлении токен заменяется
+// новым, а при отзыве сессии перестают действовать все токены семейства
 type Session struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	Device     Device     `json:"device"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+}
+
+// ClientInfo — сведения о клиенте, полученные из HTTP-запроса
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// RefreshToken — выданный refresh-токен. Хранится только хеш токена;
+// UsedAt заполняется при обмене на новую пару токенов
+type RefreshToken struct {
+	TokenHash string
+	SessionID uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// RevokedSession — отзыв сессии, действующий до истечения последнего
+// выданного ей access-токена
+type RevokedSession struct {
+	SessionID uuid.UUID
+	ExpiresAt time.Time
+}
+
+// TokenPair — токены, выдаваемые клиенту при входе и обновлении
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int       `json:"expires_in"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// Device — клиентское устройство пользователя (телефон, браузер, десктоп).
+// Одно устройство может иметь несколько сессий, идентификатор передает клиент.
+type Device struct {
 	ID        uuid.UUID `json:"id"`
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	IsActive  bool      `json:"is_active"`
+	Connected bool      `json:"connected"`
 }
@@ -1,39 +1,65 @@
 package models
 
 import (
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Track — трек каталога. ArtistName — основной исполнитель, он же первый
+// в Artists. Нулевые TrackNumber, DiscNumber и пустые строки — поле не
+// заполнено
 type Track struct {
-	ID         uuid.UUID
-	Title      string
-	Duration   int
-	FilePath   string
-	AlbumID    uuid.UUID
-	ArtistName string
-	CoverURL   string
-	AlbumTitle string
-	AddedDate  time.Time
-	UpdatedAt  time.Time
-	PlayCount  int
+	ID          uuid.UUID
+	Title       string
+	Duration    int
+	FilePath    string
+	AlbumID     uuid.UUID
+	ArtistName  string
+	Artists     []string
+	TrackNumber int
+	DiscNumber  int
+	Explicit    bool
+	ISRC        string
+	LyricsURL   string
+	ReleaseDate *time.Time
+	CoverURL    string
+	AlbumTitle  string
+	AddedDate   time.Time
+	UpdatedAt   time.Time
+	PlayCount   int
+	// Loudness — громкость трека или nil, если он не измерен
+	Loudness *Loudness
 }
 
 type TrackDetails struct {
-	ID         uuid.UUID
-	Title      string
-	ArtistName string
-	Duration   int
-	FilePath   string
-	MimeType   string
-	CoverURL   string
-	AddedDate  time.Time
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	PlayCount  int
-	Album      *Album
-	Genres     []*Genre
+	ID          uuid.UUID
+	Title       string
+	ArtistName  string
+	Artists     []string
+	TrackNumber int
+	DiscNumber  int
+	Explicit    bool
+	ISRC        string
+	LyricsURL   string
+	ReleaseDate *time.Time
+	Duration    int
+	FilePath    string
+	MimeType    string
+	CoverURL    string
+	AddedDate   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	PlayCount   int
+	Album       *Album
+	Genres      []*Genre
+	// Lyrics — опубликованный текст песни или nil
+	Lyrics *Lyrics
+	// Loudness — громкость трека или nil, если он не измерен. Громкость
+	// альбома — в Album.Loudness
+	Loudness *Loudness
 }
 
 type TrackUploadMetadata struct {
@@ -42,4 +68,41 @@ type TrackUploadMetadata struct {
 	ArtistName string    `json:"artist_name"`
 	Duration   int       `json:"duration,omitempty"`
 	CoverURL   string    `json:"cover_url,omitempty"`
+	// AllowDuplicate — загрузить трек, даже если он похож на треки
+	// каталога и загрузка дубликатов запрещена
+	AllowDuplicate bool `json:"allow_duplicate,omitempty"`
+}
+
+// TrackMetadataUpdate — изменение метаданных трека. Nil-поля не меняются,
+// указатель на нулевое значение очищает необязательное поле, пустой
+// (не nil) GenreIDs снимает все жанры. ArtistName заменяет основного
+// исполнителя, Artists — весь список. Version — время последнего изменения
+// трека, которое видел клиент; если трек с тех пор менялся, изменение
+// отклоняется. Без Version проверки нет
+type TrackMetadataUpdate struct {
+	Title       *string
+	ArtistName  *string
+	Artists     []string
+	AlbumID     *uuid.UUID
+	TrackNumber *int
+	DiscNumber  *int
+	Duration    *int
+	CoverURL    *string
+	GenreIDs    []uuid.UUID
+	Explicit    *bool
+	ISRC        *string
+	LyricsURL   *string
+	ReleaseDate *time.Time
+	Version     *time.Time
+}
+
+var isrcPattern = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+
+var isrcSeparators = strings.NewReplacer("-", "", " ", "")
+
+// NormalizeISRC приводит ISRC к виду без дефисов и пробелов в верхнем регистре:
+// "us-s1z-99-00001" -> "USS1Z9900001". ok — код корректен
+func NormalizeISRC(isrc string) (string, bool) {
+	normalized := strings.ToUpper(isrcSeparators.Replace(isrc))
+	return normalized, isrcPattern.MatchString(normalized)
 }
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Типы объектов каталога, поля которых можно перевести
+const (
+	TranslatableTrack = "track"
+	TranslatableAlbum = "album"
+	TranslatableGenre = "genre"
+)
+
+// TranslatableFields — поля, которые можно перевести, по типам объектов
+var TranslatableFields = map[string][]string{
+	TranslatableTrack: {"title", "artist_name"},
+	TranslatableAlbum: {"title", "artist"},
+	TranslatableGenre: {"name"},
+}
+
+// Translation — перевод или транслитерация полей объекта на язык Locale
+// (тег BCP 47, например "en" или "ru-Latn"). Поля, которых нет в Fields,
+// берутся из оригинала
+type Translation struct {
+	Locale    string            `json:"locale"`
+	Fields    map[string]string `json:"fields"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// IsTranslatableField проверяет, что поле field объекта entityType можно перевести
+func IsTranslatableField(entityType, field string) bool {
+	for _, f := range TranslatableFields[entityType] {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
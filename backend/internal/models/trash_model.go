@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы удаленных объектов в корзине
+const (
+	TrashTrack    = "track"
+	TrashAlbum    = "album"
+	TrashPlaylist = "playlist"
+	TrashUser     = "user"
+)
+
+// TrashItem — удаленный объект, который еще можно восстановить. Title —
+// название трека, альбома или плейлиста либо логин пользователя. OwnerID
+// задан у плейлистов, ParentID — у треков из альбома
+type TrashItem struct {
+	Type      string     `json:"type"`
+	ID        uuid.UUID  `json:"id"`
+	Title     string     `json:"title"`
+	OwnerID   *uuid.UUID `json:"owner_id,omitempty"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"`
+}
+
+// TrashFilter — параметры выборки корзины; пустой Type означает все типы
+type TrashFilter struct {
+	Type    string
+	OwnerID *uuid.UUID
+	Limit   int
+	Offset  int
+}
+
+// TrashPage — страница корзины, новые удаления — первыми
+type TrashPage struct {
+	Items  []*TrashItem
+	Total  int
+	Limit  int
+	Offset int
+}
+
+// IsValidTrashType проверяет тип объекта корзины
+func IsValidTrashType(itemType string) bool {
+	switch itemType {
+	case TrashTrack, TrashAlbum, TrashPlaylist, TrashUser:
+		return true
+	}
+	return false
+}
@@ -17,10 +17,73 @@ const (
 type User struct {
 	ID         uuid.UUID  `json:"id"`
 	Login      string     `json:"login"`
+	Email      string     `json:"email,omitempty"`
 	Password   string     `json:"-" yaml:"-" xml:"-" bson:"-"`
 	Permission Permission `json:"permission"`
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
+	// Locale — предпочитаемый язык (тег BCP 47); пустой — язык берется из
+	// Accept-Language
+	Locale string `json:"locale,omitempty"`
+	// Suspension — последняя блокировка; nil, если пользователь не блокировался
+	Suspension *Suspension `json:"-"`
+}
+
+// Suspension — блокировка пользователя администратором. Until == nil
+// означает бессрочную блокировку (бан)
+type Suspension struct {
+	Reason      string     `json:"reason"`
+	SuspendedAt time.Time  `json:"suspended_at"`
+	Until       *time.Time `json:"until,omitempty"`
+	SuspendedBy *uuid.UUID `json:"suspended_by,omitempty"`
+}
+
+// Active сообщает, действует ли блокировка в момент now
+func (s *Suspension) Active(now time.Time) bool {
+	return s != nil && (s.Until == nil || now.Before(*s.Until))
+}
+
+// IsSuspended сообщает, заблокирован ли пользователь в момент now
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.Suspension.Active(now)
+}
+
+// SuspendedError возвращается при попытке войти или обновить токены
+// заблокированному пользователю
+type SuspendedError struct {
+	Reason string
+	Until  *time.Time
+}
+
+func (e *SuspendedError) Error() string {
+	return "account is suspended"
+}
+
+// UserStatus — фильтр списка пользователей по блокировке
+type UserStatus string
+
+const (
+	UserStatusActive    UserStatus = "active"
+	UserStatusSuspended UserStatus = "suspended"
+)
+
+// UserFilter — параметры выборки пользователей для администратора. Query
+// ищется в логине и почте; пустые поля не ограничивают выборку
+type UserFilter struct {
+	Query      string
+	Permission Permission
+	Status     UserStatus
+	Limit      int
+	Offset     int
+}
+
+// UserPage — страница списка пользователей, их общее количество и
+// примененные размер страницы и смещение
+type UserPage struct {
+	Users  []*User
+	Total  int
+	Limit  int
+	Offset int
 }
 
 func (p Permission) IsValid() bool {
@@ -31,3 +94,12 @@ func (p Permission) IsValid() bool {
 		return false
 	}
 }
+
+// PasswordResetToken — выданный токен сброса пароля. Хранится только хеш
+type PasswordResetToken struct {
+	TokenHash string
+	UserID    uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
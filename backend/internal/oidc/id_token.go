@@ -0,0 +1,171 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Допустимое расхождение часов с провайдером
+const clockSkew = time.Minute
+
+// IDToken — проверенные утверждения ID-токена
+type IDToken struct {
+	Issuer            string
+	Subject           string
+	Audience          []string
+	IssuedAt          time.Time
+	Expiry            time.Time
+	Nonce             string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+type idTokenClaims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          audience `json:"aud"`
+	AuthorizedParty   string   `json:"azp"`
+	IssuedAt          int64    `json:"iat"`
+	Expiry            int64    `json:"exp"`
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     flexBool `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+}
+
+// audience — aud может быть строкой или массивом строк
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// flexBool — некоторые провайдеры передают email_verified строкой
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true", `"true"`:
+		*b = true
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// VerifyIDToken проверяет подпись (RS256), издателя, получателя, срок
+// действия и nonce ID-токена
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (*IDToken, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidIDToken
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	var h struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &h); err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	// Алгоритм фиксирован: "none" и HS256 с открытым ключом не принимаются
+	if h.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidIDToken, h.Alg)
+	}
+
+	key, err := p.keys.key(ctx, h.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidIDToken)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	var claims idTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidIDToken
+	}
+
+	if claims.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w: issuer mismatch", ErrInvalidIDToken)
+	}
+	if !contains(claims.Audience, p.cfg.ClientID) {
+		return nil, fmt.Errorf("%w: audience mismatch", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: authorized party mismatch", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	now := time.Now()
+	expiry := time.Unix(claims.Expiry, 0)
+	if claims.Expiry == 0 || now.After(expiry.Add(clockSkew)) {
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidIDToken)
+	}
+	issuedAt := time.Unix(claims.IssuedAt, 0)
+	if issuedAt.After(now.Add(clockSkew)) {
+		return nil, fmt.Errorf("%w: token issued in the future", ErrInvalidIDToken)
+	}
+
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return &IDToken{
+		Issuer:            claims.Issuer,
+		Subject:           claims.Subject,
+		Audience:          claims.Audience,
+		IssuedAt:          issuedAt,
+		Expiry:            expiry,
+		Nonce:             claims.Nonce,
+		Email:             claims.Email,
+		EmailVerified:     bool(claims.EmailVerified),
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Набор ключей перечитывается не чаще раза в минуту, даже если токен
+// подписан неизвестным ключом
+const minKeyRefreshInterval = time.Minute
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// keySet — кеш открытых ключей провайдера (JWKS). При ротации ключей у
+// провайдера токен с новым kid вызывает повторную загрузку набора
+type keySet struct {
+	client  *http.Client
+	jwksURI func(ctx context.Context) (string, error)
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+func newKeySet(client *http.Client, jwksURI func(ctx context.Context) (string, error)) *keySet {
+	return &keySet{client: client, jwksURI: jwksURI}
+}
+
+func (s *keySet) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if !s.fetchedAt.IsZero() && time.Since(s.fetchedAt) < minKeyRefreshInterval {
+		return nil, errors.New("unknown signing key")
+	}
+
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+func (s *keySet) refresh(ctx context.Context) error {
+	uri, err := s.jwksURI(ctx)
+	if err != nil {
+		return err
+	}
+
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, s.client, uri, &doc); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range doc.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") || (jwk.Alg != "" && jwk.Alg != "RS256") {
+			continue
+		}
+		key, err := parseRSAKey(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+func parseRSAKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	if key.N.BitLen() < 2048 {
+		return nil, errors.New("RSA key is too short")
+	}
+	return key, nil
+}
@@ -0,0 +1,239 @@
+package tests
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"music-service/internal/oidc"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const clientID = "music-service"
+
+// fakeIssuer — провайдер OpenID Connect для тестов. На код авторизации он
+// выдает idToken, предварительно проверив PKCE
+type fakeIssuer struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	idToken   string
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &fakeIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer.server.URL,
+			"authorization_endpoint": issuer.server.URL + "/authorize",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"jwks_uri":               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "code" ||
+			oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != issuer.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": issuer.idToken, "token_type": "Bearer"})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (f *fakeIssuer) provider() *oidc.Provider {
+	return oidc.NewProvider(oidc.Config{
+		Name:        "test",
+		Issuer:      f.server.URL,
+		ClientID:    clientID,
+		RedirectURL: "http://localhost/callback",
+	})
+}
+
+func (f *fakeIssuer) claims(nonce string) map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":            f.server.URL,
+		"sub":            "subject-1",
+		"aud":            clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          nonce,
+		"email":          "user@example.com",
+		"email_verified": true,
+	}
+}
+
+func (f *fakeIssuer) sign(t *testing.T, header, claims map[string]interface{}) string {
+	headerJSON, err := json.Marshal(header)
+	require.NoError(t, err)
+	claimsJSON, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, f.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func rs256(kid string) map[string]interface{} {
+	return map[string]interface{}{"alg": "RS256", "kid": kid, "typ": "JWT"}
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider()
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, issuer.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, clientID, query.Get("client_id"))
+	assert.Equal(t, "state", query.Get("state"))
+	assert.Equal(t, "nonce", query.Get("nonce"))
+	assert.Equal(t, "challenge", query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	// scope openid добавляется всегда
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	verifier := oidc.NewCodeVerifier()
+	issuer.challenge = oidc.CodeChallenge(verifier)
+
+	// Успешный обмен кода с проверкой PKCE и подписи
+	t.Run("success", func(t *testing.T) {
+		issuer.idToken = issuer.sign(t, rs256("key-1"), issuer.claims("nonce"))
+
+		token, err := issuer.provider().Exchange(context.Background(), "code", verifier, "nonce")
+		require.NoError(t, err)
+		assert.Equal(t, "subject-1", token.Subject)
+		assert.Equal(t, "user@example.com", token.Email)
+		assert.True(t, token.EmailVerified)
+	})
+
+	// Провайдер отклоняет неверный code_verifier
+	t.Run("wrong verifier", func(t *testing.T) {
+		issuer.idToken = issuer.sign(t, rs256("key-1"), issuer.claims("nonce"))
+
+		_, err := issuer.provider().Exchange(context.Background(), "code", oidc.NewCodeVerifier(), "nonce")
+		assert.Error(t, err)
+	})
+
+	// nonce не совпадает с сохраненным при начале входа
+	t.Run("nonce mismatch", func(t *testing.T) {
+		issuer.idToken = issuer.sign(t, rs256("key-1"), issuer.claims("other"))
+
+		_, err := issuer.provider().Exchange(context.Background(), "code", verifier, "nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+}
+
+func TestProvider_VerifyIDToken(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider()
+	ctx := context.Background()
+
+	// Токен выдан другому клиенту
+	t.Run("wrong audience", func(t *testing.T) {
+		claims := issuer.claims("nonce")
+		claims["aud"] = []string{"other-client"}
+
+		_, err := provider.VerifyIDToken(ctx, issuer.sign(t, rs256("key-1"), claims), "nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	// Несколько получателей без azp
+	t.Run("multiple audiences without azp", func(t *testing.T) {
+		claims := issuer.claims("nonce")
+		claims["aud"] = []string{clientID, "other-client"}
+
+		_, err := provider.VerifyIDToken(ctx, issuer.sign(t, rs256("key-1"), claims), "nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	// Токен от другого издателя
+	t.Run("wrong issuer", func(t *testing.T) {
+		claims := issuer.claims("nonce")
+		claims["iss"] = "https://evil.example.com"
+
+		_, err := provider.VerifyIDToken(ctx, issuer.sign(t, rs256("key-1"), claims), "nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	// Истекший токен
+	t.Run("expired", func(t *testing.T) {
+		claims := issuer.claims("nonce")
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+		_, err := provider.VerifyIDToken(ctx, issuer.sign(t, rs256("key-1"), claims), "nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	// Алгоритмы кроме RS256 не принимаются
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		for _, alg := range []string{"none", "HS256"} {
+			header := map[string]interface{}{"alg": alg, "kid": "key-1"}
+
+			_, err := provider.VerifyIDToken(ctx, issuer.sign(t, header, issuer.claims("nonce")), "nonce")
+			assert.ErrorIs(t, err, oidc.ErrInvalidIDToken, alg)
+		}
+	})
+
+	// Токен подписан неизвестным ключом
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := provider.VerifyIDToken(ctx, issuer.sign(t, rs256("key-2"), issuer.claims("nonce")), "nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	// Подпись не соответствует содержимому
+	t.Run("tampered payload", func(t *testing.T) {
+		token := issuer.sign(t, rs256("key-1"), issuer.claims("nonce"))
+		claims := issuer.claims("nonce")
+		claims["sub"] = "admin"
+		forged := issuer.sign(t, rs256("key-1"), claims)
+
+		// Заголовок и утверждения от поддельного токена, подпись — от настоящего
+		tampered := forged[:strings.LastIndex(forged, ".")] + token[strings.LastIndex(token, "."):]
+
+		_, err := provider.VerifyIDToken(ctx, tampered, "nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+}
@@ -0,0 +1,195 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"music-service/internal/logging"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"sync"
+	"time"
+)
+
+// Handler обрабатывает доменное событие. Доставка выполняется по схеме
+// at-least-once: при ошибке любого подписчика событие будет доставлено
+// повторно всем подписчикам этого типа, поэтому обработчики должны быть
+// идемпотентными или допускать повтор
+type Handler func(ctx context.Context, event *models.DomainEvent) error
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	// Lease — время, на которое событие захватывается одним диспетчером
+	Lease time.Duration
+	// Retention — сколько хранить доставленные события
+	Retention time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 50
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 10
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 10 * time.Minute
+	}
+	if c.Lease <= 0 {
+		c.Lease = time.Minute
+	}
+	if c.Retention <= 0 {
+		c.Retention = 7 * 24 * time.Hour
+	}
+	return c
+}
+
+// Dispatcher периодически выбирает события из outbox и доставляет их
+// подписчикам внутри процесса с повторными попытками
+type Dispatcher struct {
+	repo interfaces.OutboxRepository
+	cfg  Config
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewDispatcher(repo interfaces.OutboxRepository, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		repo:     repo,
+		cfg:      cfg.withDefaults(),
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe регистрирует обработчик событий указанного типа
+func (d *Dispatcher) Subscribe(eventType string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[eventType] = append(d.handlers[eventType], handler)
+}
+
+// Run обрабатывает события, пока не будет отменен ctx. Логгер берется из
+// ctx (logging.FromContext) и передается обработчикам вместе с ID события
+func (d *Dispatcher) Run(ctx context.Context) {
+	logger := logging.FromContext(ctx).With("component", "outbox")
+	ctx = logging.NewContext(ctx, logger)
+
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		// Пока выбирается полный пакет, очередь разбирается без ожидания
+		for {
+			n, err := d.DispatchPending(ctx)
+			if err != nil {
+				logger.Error("claim pending events failed", "error", err)
+				break
+			}
+			if n < d.cfg.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-cleanup.C:
+			if n, err := d.repo.DeleteProcessed(ctx, time.Now().Add(-d.cfg.Retention)); err != nil {
+				logger.Error("delete processed events failed", "error", err)
+			} else if n > 0 {
+				logger.Info("deleted processed events", "count", n)
+			}
+		}
+	}
+}
+
+// DispatchPending доставляет одну порцию событий и возвращает их количество
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	events, err := d.repo.ClaimPending(ctx, d.cfg.BatchSize, d.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		d.dispatch(ctx, event)
+	}
+
+	return len(events), nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event *models.DomainEvent) {
+	ctx = logging.With(ctx, "event_id", event.ID, "event_type", event.Type)
+	logger := logging.FromContext(ctx)
+
+	if err := d.deliver(ctx, event); err != nil {
+		attempts := event.Attempts + 1
+		if attempts >= d.cfg.MaxAttempts {
+			logger.Error("event delivery gave up", "attempts", attempts, "error", err)
+			if err := d.repo.MarkDead(ctx, event.ID, err.Error()); err != nil {
+				logger.Error("mark event dead failed", "error", err)
+			}
+			return
+		}
+
+		nextAttemptAt := time.Now().Add(d.backoff(attempts))
+		if err := d.repo.MarkFailed(ctx, event.ID, err.Error(), nextAttemptAt); err != nil {
+			logger.Error("record delivery failure failed", "error", err)
+		}
+		return
+	}
+
+	if err := d.repo.MarkProcessed(ctx, event.ID); err != nil {
+		logger.Error("mark event processed failed", "error", err)
+	}
+}
+
+// deliver вызывает всех подписчиков события. Паника в обработчике
+// считается ошибкой доставки и не останавливает диспетчер
+func (d *Dispatcher) deliver(ctx context.Context, event *models.DomainEvent) (err error) {
+	d.mu.RLock()
+	handlers := d.handlers[event.Type]
+	d.mu.RUnlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in handler: %v", r)
+		}
+	}()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backoff возвращает экспоненциальную задержку с джиттером
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.cfg.MaxBackoff {
+		delay = d.cfg.MaxBackoff
+	}
+	// До 20% случайного разброса, чтобы повторы не приходили одновременно
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	return delay + jitter
+}
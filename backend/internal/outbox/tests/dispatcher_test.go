@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"music-service/internal/models"
+	"music-service/internal/outbox"
+	"music-service/internal/repository/interfaces/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const eventType = "test.happened"
+
+var config = outbox.Config{
+	BatchSize:   10,
+	MaxAttempts: 3,
+	BaseBackoff: time.Second,
+	MaxBackoff:  time.Minute,
+}
+
+func newEvent(t *testing.T, attempts int) *models.DomainEvent {
+	t.Helper()
+	event, err := models.NewDomainEvent(eventType, uuid.New(), map[string]string{"key": "value"})
+	require.NoError(t, err)
+	event.Attempts = attempts
+	return event
+}
+
+func setup(t *testing.T, events ...*models.DomainEvent) (*mocks.MockOutboxRepository, *outbox.Dispatcher) {
+	repo := mocks.NewMockOutboxRepository(gomock.NewController(t))
+	repo.EXPECT().ClaimPending(gomock.Any(), config.BatchSize, gomock.Any()).Return(events, nil)
+	return repo, outbox.NewDispatcher(repo, config)
+}
+
+func TestDispatcher_Delivers(t *testing.T) {
+	first, second := newEvent(t, 0), newEvent(t, 0)
+	repo, dispatcher := setup(t, first, second)
+
+	var delivered []uuid.UUID
+	for i := 0; i < 2; i++ {
+		dispatcher.Subscribe(eventType, func(ctx context.Context, event *models.DomainEvent) error {
+			delivered = append(delivered, event.ID)
+			return nil
+		})
+	}
+	repo.EXPECT().MarkProcessed(gomock.Any(), first.ID).Return(nil)
+	repo.EXPECT().MarkProcessed(gomock.Any(), second.ID).Return(nil)
+
+	n, err := dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	// Каждый подписчик получает каждое событие по порядку
+	assert.Equal(t, []uuid.UUID{first.ID, first.ID, second.ID, second.ID}, delivered)
+}
+
+// Событие без подписчиков считается доставленным
+func TestDispatcher_NoHandlers(t *testing.T) {
+	event := newEvent(t, 0)
+	repo, dispatcher := setup(t, event)
+	repo.EXPECT().MarkProcessed(gomock.Any(), event.ID).Return(nil)
+
+	_, err := dispatcher.DispatchPending(context.Background())
+	assert.NoError(t, err)
+}
+
+// Ошибка обработчика откладывает следующую попытку с экспоненциальной
+// задержкой и джиттером до 20%
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	cases := []struct {
+		attempts int
+		delay    time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+	}
+	for _, tc := range cases {
+		event := newEvent(t, tc.attempts)
+		repo, dispatcher := setup(t, event)
+		dispatcher.Subscribe(eventType, func(context.Context, *models.DomainEvent) error {
+			return errors.New("handler error")
+		})
+
+		start := time.Now()
+		var nextAttemptAt time.Time
+		repo.EXPECT().MarkFailed(gomock.Any(), event.ID, "handler error", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, _ string, at time.Time) error {
+				nextAttemptAt = at
+				return nil
+			})
+
+		_, err := dispatcher.DispatchPending(context.Background())
+		require.NoError(t, err)
+		assert.WithinRange(t, nextAttemptAt, start.Add(tc.delay), time.Now().Add(tc.delay+tc.delay/5))
+	}
+}
+
+func TestDispatcher_BackoffIsCapped(t *testing.T) {
+	event := newEvent(t, 50)
+	repo := mocks.NewMockOutboxRepository(gomock.NewController(t))
+	repo.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), gomock.Any()).Return([]*models.DomainEvent{event}, nil)
+	dispatcher := outbox.NewDispatcher(repo, outbox.Config{MaxAttempts: 100, BaseBackoff: time.Second, MaxBackoff: 5 * time.Second})
+	dispatcher.Subscribe(eventType, func(context.Context, *models.DomainEvent) error {
+		return errors.New("handler error")
+	})
+
+	start := time.Now()
+	repo.EXPECT().MarkFailed(gomock.Any(), event.ID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ string, at time.Time) error {
+			assert.WithinRange(t, at, start.Add(5*time.Second), time.Now().Add(6*time.Second))
+			return nil
+		})
+
+	_, err := dispatcher.DispatchPending(context.Background())
+	assert.NoError(t, err)
+}
+
+// После MaxAttempts попыток событие больше не доставляется
+func TestDispatcher_MarksDeadAfterMaxAttempts(t *testing.T) {
+	event := newEvent(t, config.MaxAttempts-1)
+	repo, dispatcher := setup(t, event)
+	dispatcher.Subscribe(eventType, func(context.Context, *models.DomainEvent) error {
+		return errors.New("handler error")
+	})
+	repo.EXPECT().MarkDead(gomock.Any(), event.ID, "handler error").Return(nil)
+
+	_, err := dispatcher.DispatchPending(context.Background())
+	assert.NoError(t, err)
+}
+
+// Паника обработчика — ошибка доставки: диспетчер продолжает работу,
+// следующие события доставляются
+func TestDispatcher_RecoversFromPanic(t *testing.T) {
+	panicking, next := newEvent(t, 0), newEvent(t, 0)
+	repo, dispatcher := setup(t, panicking, next)
+	dispatcher.Subscribe(eventType, func(ctx context.Context, event *models.DomainEvent) error {
+		if event.ID == panicking.ID {
+			panic("boom")
+		}
+		return nil
+	})
+	repo.EXPECT().MarkFailed(gomock.Any(), panicking.ID, "panic in handler: boom", gomock.Any()).Return(nil)
+	repo.EXPECT().MarkProcessed(gomock.Any(), next.ID).Return(nil)
+
+	n, err := dispatcher.DispatchPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+// Если обработчик вернул ошибку, следующие подписчики не вызываются:
+// при повторе событие получат все подписчики
+func TestDispatcher_StopsOnHandlerError(t *testing.T) {
+	event := newEvent(t, 0)
+	repo, dispatcher := setup(t, event)
+	dispatcher.Subscribe(eventType, func(context.Context, *models.DomainEvent) error {
+		return errors.New("handler error")
+	})
+	dispatcher.Subscribe(eventType, func(context.Context, *models.DomainEvent) error {
+		t.Error("second handler must not be called")
+		return nil
+	})
+	repo.EXPECT().MarkFailed(gomock.Any(), event.ID, "handler error", gomock.Any()).Return(nil)
+
+	_, err := dispatcher.DispatchPending(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestDispatcher_ClaimError(t *testing.T) {
+	repo := mocks.NewMockOutboxRepository(gomock.NewController(t))
+	repo.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+
+	_, err := outbox.NewDispatcher(repo, config).DispatchPending(context.Background())
+	assert.Error(t, err)
+}
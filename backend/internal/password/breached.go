@@ -0,0 +1,75 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"strings"
+)
+
+// BreachedList — локальный набор скомпрометированных паролей. Хранятся
+// SHA-1 паролей, как в выгрузках Have I Been Pwned
+type BreachedList struct {
+	hashes map[string]struct{}
+}
+
+// LoadBreachedList читает файл, в каждой строке которого либо пароль, либо
+// SHA-1 пароля в hex (формат HIBP "HASH:COUNT" тоже поддерживается).
+// Пустые строки и строки, начинающиеся с #, пропускаются
+func LoadBreachedList(path string) (*BreachedList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list := &BreachedList{hashes: make(map[string]struct{})}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if hash, _, _ := strings.Cut(line, ":"); isSHA1(hash) {
+			list.hashes[strings.ToUpper(hash)] = struct{}{}
+			continue
+		}
+		list.hashes[hashPassword(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Contains сообщает, есть ли пароль в списке. Пустой список ничего не содержит
+func (l *BreachedList) Contains(password string) bool {
+	if l == nil {
+		return false
+	}
+	_, ok := l.hashes[hashPassword(password)]
+	return ok
+}
+
+// Len возвращает количество паролей в списке
+func (l *BreachedList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.hashes)
+}
+
+func hashPassword(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func isSHA1(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
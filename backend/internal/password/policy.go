@@ -0,0 +1,110 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bcrypt учитывает только первые 72 байта пароля
+const bcryptMaxBytes = 72
+
+// Коды нарушений политики паролей
+const (
+	CodeTooShort      = "password_too_short"
+	CodeTooLong       = "password_too_long"
+	CodeNoLetter      = "password_no_letter"
+	CodeNoDigit       = "password_no_digit"
+	CodeContainsLogin = "password_contains_login"
+	CodeBreached      = "password_breached"
+)
+
+// PolicyError — пароль не удовлетворяет политике. Code — одно из
+// нарушений Code*, Limit — граница длины для CodeTooShort и CodeTooLong
+type PolicyError struct {
+	Code  string
+	Limit int
+	msg   string
+}
+
+func (e *PolicyError) Error() string {
+	return e.msg
+}
+
+func violation(code string, limit int, format string, args ...interface{}) error {
+	return &PolicyError{Code: code, Limit: limit, msg: fmt.Sprintf(format, args...)}
+}
+
+// Config — требования к паролю. Нулевые значения длины заменяются
+// значениями по умолчанию, пустой BreachedListPath отключает проверку по
+// списку скомпрометированных паролей
+type Config struct {
+	MinLength        int
+	MaxLength        int
+	RequireLetter    bool
+	RequireDigit     bool
+	BreachedListPath string
+}
+
+// Policy проверяет пароли на соответствие Config
+type Policy struct {
+	cfg      Config
+	breached *BreachedList
+}
+
+func NewPolicy(cfg Config) (*Policy, error) {
+	if cfg.MinLength <= 0 {
+		cfg.MinLength = 8
+	}
+	if cfg.MaxLength <= 0 || cfg.MaxLength > bcryptMaxBytes {
+		cfg.MaxLength = bcryptMaxBytes
+	}
+
+	policy := &Policy{cfg: cfg}
+	if cfg.BreachedListPath != "" {
+		breached, err := LoadBreachedList(cfg.BreachedListPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load breached password list: %w", err)
+		}
+		policy.breached = breached
+	}
+
+	return policy, nil
+}
+
+// Validate проверяет пароль пользователя login. Возвращает *PolicyError
+func (p *Policy) Validate(password, login string) error {
+	if utf8.RuneCountInString(password) < p.cfg.MinLength {
+		return violation(CodeTooShort, p.cfg.MinLength, "password must be at least %d characters", p.cfg.MinLength)
+	}
+	if len(password) > p.cfg.MaxLength {
+		return violation(CodeTooLong, p.cfg.MaxLength, "password is too long (max %d bytes)", p.cfg.MaxLength)
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if p.cfg.RequireLetter && !hasLetter {
+		return violation(CodeNoLetter, 0, "password must contain a letter")
+	}
+	if p.cfg.RequireDigit && !hasDigit {
+		return violation(CodeNoDigit, 0, "password must contain a digit")
+	}
+
+	if login != "" && strings.Contains(strings.ToLower(password), strings.ToLower(login)) {
+		return violation(CodeContainsLogin, 0, "password must not contain the login")
+	}
+
+	if p.breached.Contains(password) {
+		return violation(CodeBreached, 0, "password has appeared in a data breach, choose another one")
+	}
+
+	return nil
+}
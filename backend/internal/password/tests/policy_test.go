@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"music-service/internal/password"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeBreachedList записывает список во всех поддерживаемых форматах:
+// пароль, SHA-1 в hex и строка выгрузки HIBP "HASH:COUNT"
+func writeBreachedList(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	content := strings.Join([]string{
+		"# Скомпрометированные пароли",
+		"",
+		"password123",
+		strings.ToLower(sha1Hex("qwerty2024")),
+		strings.ToUpper(sha1Hex("letmein99")) + ":51234",
+	}, "\n")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	policy, err := password.NewPolicy(password.Config{
+		MinLength:        8,
+		MaxLength:        32,
+		RequireLetter:    true,
+		RequireDigit:     true,
+		BreachedListPath: writeBreachedList(t),
+	})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		password string
+		login    string
+		code     string
+		limit    int
+	}{
+		{"valid", "tr0ub4dor&3", "listener", "", 0},
+		{"too short", "abc123", "listener", password.CodeTooShort, 8},
+		// Длина считается в символах: 7 кириллических букв — 14 байт
+		{"short in runes", "пароль1", "listener", password.CodeTooShort, 8},
+		{"cyrillic", "пароль12", "listener", "", 0},
+		// Максимум — в байтах: bcrypt учитывает только первые 72 байта
+		{"too long in bytes", strings.Repeat("я", 16) + "1", "listener", password.CodeTooLong, 32},
+		{"no letter", "12345678", "listener", password.CodeNoLetter, 0},
+		{"no digit", "abcdefgh", "listener", password.CodeNoDigit, 0},
+		{"contains login", "xxListener1", "listener", password.CodeContainsLogin, 0},
+		{"without login", "xxListener1", "", "", 0},
+		{"breached plain", "password123", "listener", password.CodeBreached, 0},
+		{"breached sha1", "qwerty2024", "listener", password.CodeBreached, 0},
+		{"breached hibp", "letmein99", "listener", password.CodeBreached, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := policy.Validate(tc.password, tc.login)
+			if tc.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var policyErr *password.PolicyError
+			require.True(t, errors.As(err, &policyErr), "%v", err)
+			assert.Equal(t, tc.code, policyErr.Code)
+			assert.Equal(t, tc.limit, policyErr.Limit)
+		})
+	}
+}
+
+func TestNewPolicy_Defaults(t *testing.T) {
+	// Без настроек: минимум 8 символов, максимум 72 байта, список не
+	// проверяется
+	policy, err := password.NewPolicy(password.Config{MaxLength: 1000})
+	require.NoError(t, err)
+
+	assert.NoError(t, policy.Validate("password123", ""))
+	assert.NoError(t, policy.Validate("12345678", ""))
+
+	var policyErr *password.PolicyError
+	require.ErrorAs(t, policy.Validate("1234567", ""), &policyErr)
+	assert.Equal(t, 8, policyErr.Limit)
+	require.ErrorAs(t, policy.Validate(strings.Repeat("a", 73), ""), &policyErr)
+	assert.Equal(t, password.CodeTooLong, policyErr.Code)
+	assert.Equal(t, 72, policyErr.Limit)
+
+	_, err = password.NewPolicy(password.Config{BreachedListPath: filepath.Join(t.TempDir(), "missing.txt")})
+	assert.Error(t, err)
+}
+
+func TestBreachedList(t *testing.T) {
+	list, err := password.LoadBreachedList(writeBreachedList(t))
+	require.NoError(t, err)
+	assert.Equal(t, 3, list.Len())
+	assert.True(t, list.Contains("password123"))
+	assert.False(t, list.Contains("Password123"))
+
+	// Пустой список ничего не содержит
+	var empty *password.BreachedList
+	assert.False(t, empty.Contains("password123"))
+	assert.Zero(t, empty.Len())
+}
@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limiter применяет лимиты классов маршрутов. Класс без лимита не ограничивается
+type Limiter struct {
+	store  Store
+	limits map[string]Limit
+	now    func() time.Time
+}
+
+func NewLimiter(store Store, limits map[string]Limit) *Limiter {
+	valid := make(map[string]Limit, len(limits))
+	for class, limit := range limits {
+		if limit.valid() {
+			valid[class] = limit
+		}
+	}
+	return &Limiter{store: store, limits: valid, now: time.Now}
+}
+
+// Allow списывает токен из ведра класса для subject (адреса клиента или
+// пользователя). ok=false означает, что для класса лимит не задан
+func (l *Limiter) Allow(ctx context.Context, class, subject string) (result Result, limit Limit, ok bool, err error) {
+	limit, ok = l.limits[class]
+	if !ok {
+		return Result{}, Limit{}, false, nil
+	}
+
+	result, err = l.store.Take(ctx, class+":"+subject, limit, l.now())
+	return result, limit, true, err
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// LockoutPolicy — прогрессивная блокировка после неудачных попыток. После
+// Threshold неудач ключ блокируется на BaseDelay, каждая следующая неудача
+// удваивает блокировку, но не больше MaxDelay. Счетчик неудач сбрасывается
+// после FailureWindow без неудач или после успешной попытки
+type LockoutPolicy struct {
+	Threshold     int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	FailureWindow time.Duration
+}
+
+// LockedError возвращается, пока ключ заблокирован
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return "too many failed attempts"
+}
+
+// Lockout отслеживает неудачные попытки (например, входа) и блокирует ключ
+type Lockout struct {
+	store  Store
+	policy LockoutPolicy
+	now    func() time.Time
+}
+
+func NewLockout(store Store, policy LockoutPolicy) *Lockout {
+	if policy.Threshold <= 0 {
+		policy.Threshold = 5
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = time.Minute
+	}
+	if policy.MaxDelay < policy.BaseDelay {
+		policy.MaxDelay = policy.BaseDelay
+	}
+	// Окно должно пережить блокировку, иначе после нее счетчик начнется
+	// заново и блокировка перестанет расти
+	if policy.FailureWindow < 2*policy.MaxDelay {
+		policy.FailureWindow = 2 * policy.MaxDelay
+	}
+	return &Lockout{store: store, policy: policy, now: time.Now}
+}
+
+// Check возвращает *LockedError, если ключ заблокирован
+func (l *Lockout) Check(ctx context.Context, key string) error {
+	now := l.now()
+	until, err := l.store.LockedUntil(ctx, key, now)
+	if err != nil {
+		return err
+	}
+	if until.After(now) {
+		return &LockedError{RetryAfter: until.Sub(now)}
+	}
+	return nil
+}
+
+// Fail учитывает неудачную попытку. Если порог превышен, ключ блокируется
+// и возвращается *LockedError
+func (l *Lockout) Fail(ctx context.Context, key string) error {
+	now := l.now()
+	count, err := l.store.AddFailure(ctx, key, l.policy.FailureWindow, now)
+	if err != nil {
+		return err
+	}
+	if count < l.policy.Threshold {
+		return nil
+	}
+
+	delay := l.policy.BaseDelay
+	for i := l.policy.Threshold; i < count && delay < l.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > l.policy.MaxDelay {
+		delay = l.policy.MaxDelay
+	}
+
+	if err := l.store.Lock(ctx, key, now.Add(delay)); err != nil {
+		return err
+	}
+	return &LockedError{RetryAfter: delay}
+}
+
+// Succeed сбрасывает неудачи после успешной попытки
+func (l *Lockout) Succeed(ctx context.Context, key string) error {
+	return l.store.Reset(ctx, key)
+}
@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Как часто MemoryStore удаляет неактивные записи
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// idleAt — после этого момента ведро полно и его можно удалить
+	idleAt time.Time
+}
+
+type failures struct {
+	count       int
+	last        time.Time
+	lockedUntil time.Time
+	window      time.Duration
+}
+
+func (f *failures) expired(now time.Time) bool {
+	return now.Sub(f.last) > f.window
+}
+
+// MemoryStore хранит состояние в памяти процесса
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	failures  map[string]*failures
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*bucket),
+		failures: make(map[string]*failures),
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	interval := limit.interval()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+
+	// Пополнение за прошедшее время
+	elapsed := now.Sub(b.updated)
+	if elapsed > 0 {
+		b.tokens += float64(elapsed) / float64(interval)
+		if b.tokens > float64(limit.Burst) {
+			b.tokens = float64(limit.Burst)
+		}
+		b.updated = now
+	}
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(interval))
+	}
+
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = time.Duration((float64(limit.Burst) - b.tokens) * float64(interval))
+	b.idleAt = now.Add(result.ResetAfter)
+	return result, nil
+}
+
+func (s *MemoryStore) AddFailure(ctx context.Context, key string, window time.Duration, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	f, ok := s.failures[key]
+	if !ok {
+		f = &failures{}
+		s.failures[key] = f
+	}
+	f.window = window
+	if f.expired(now) {
+		f.count = 0
+	}
+	f.count++
+	f.last = now
+	return f.count, nil
+}
+
+func (s *MemoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.failures[key]
+	if !ok {
+		f = &failures{}
+		s.failures[key] = f
+	}
+	f.lockedUntil = until
+	return nil
+}
+
+func (s *MemoryStore) LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f, ok := s.failures[key]; ok && f.lockedUntil.After(now) {
+		return f.lockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	return nil
+}
+
+// sweep удаляет полные ведра и истекшие неудачи, чтобы память не росла с
+// числом клиентов. Вызывается под блокировкой
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.idleAt) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, f := range s.failures {
+		if f.expired(now) && !f.lockedUntil.After(now) {
+			delete(s.failures, key)
+		}
+	}
+}
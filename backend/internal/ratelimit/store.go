@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit — параметры ведра токенов: Rate токенов пополняется за Period,
+// одновременно в ведре помещается не больше Burst
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+func (l Limit) valid() bool {
+	return l.Rate > 0 && l.Period > 0 && l.Burst > 0
+}
+
+// interval — время пополнения одного токена
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// Result — итог списания токена
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter — через сколько ведро заполнится полностью
+	ResetAfter time.Duration
+	// RetryAfter — через сколько появится токен, если запрос отклонен
+	RetryAfter time.Duration
+}
+
+// Store хранит состояние ограничителей. MemoryStore подходит для одного
+// инстанса; при нескольких инстансах нужна общая реализация (например, на
+// Redis), иначе каждый инстанс считает лимиты отдельно
+type Store interface {
+	// Take списывает токен из ведра key
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+
+	// AddFailure учитывает неудачную попытку и возвращает количество неудач
+	// по key. Счетчик сбрасывается, если с прошлой неудачи прошло больше window
+	AddFailure(ctx context.Context, key string, window time.Duration, now time.Time) (int, error)
+	// Lock блокирует key до until
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil возвращает время окончания блокировки или нулевое время
+	LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error)
+	// Reset сбрасывает неудачи и блокировку key
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	limit := ratelimit.Limit{Rate: 60, Period: time.Minute, Burst: 3}
+	now := time.Unix(1700000000, 0)
+
+	// Запас ведра расходуется, затем запросы отклоняются
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "key", limit, now)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+		assert.Equal(t, 3, result.Limit)
+	}
+
+	result, err := store.Take(ctx, "key", limit, now)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.ResetAfter)
+
+	// Другой ключ не затронут
+	result, err = store.Take(ctx, "other", limit, now)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Через секунду появляется один токен
+	result, err = store.Take(ctx, "key", limit, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Ведро не переполняется сверх Burst
+	result, err = store.Take(ctx, "key", limit, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{
+		"auth":    {Rate: 1, Period: time.Minute, Burst: 1},
+		"invalid": {Rate: 0, Period: time.Minute, Burst: 1},
+	})
+	ctx := context.Background()
+
+	// Ведра классов и субъектов независимы
+	result, _, ok, err := limiter.Allow(ctx, "auth", "ip:192.0.2.1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, result.Allowed)
+
+	result, _, _, err = limiter.Allow(ctx, "auth", "ip:192.0.2.1")
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	result, _, _, err = limiter.Allow(ctx, "auth", "ip:192.0.2.2")
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Класс без лимита и класс с некорректным лимитом не ограничиваются
+	for _, class := range []string{"read", "invalid"} {
+		_, _, ok, err = limiter.Allow(ctx, class, "ip:192.0.2.1")
+		require.NoError(t, err)
+		assert.False(t, ok, class)
+	}
+}
+
+func TestLockout(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	lockout := ratelimit.NewLockout(store, ratelimit.LockoutPolicy{
+		Threshold: 3,
+		BaseDelay: time.Minute,
+		MaxDelay:  4 * time.Minute,
+	})
+	ctx := context.Background()
+
+	// До порога попытки не блокируются
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, lockout.Fail(ctx, "key"))
+		assert.NoError(t, lockout.Check(ctx, "key"))
+	}
+
+	// Порог достигнут: блокировка на BaseDelay
+	var locked *ratelimit.LockedError
+	err := lockout.Fail(ctx, "key")
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, time.Minute, locked.RetryAfter)
+
+	err = lockout.Check(ctx, "key")
+	require.ErrorAs(t, err, &locked)
+	assert.InDelta(t, time.Minute.Seconds(), locked.RetryAfter.Seconds(), 1)
+
+	// Каждая следующая неудача удваивает блокировку до MaxDelay
+	for _, expected := range []time.Duration{2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		err = lockout.Fail(ctx, "key")
+		require.ErrorAs(t, err, &locked)
+		assert.Equal(t, expected, locked.RetryAfter)
+	}
+
+	// Другие ключи не заблокированы
+	assert.NoError(t, lockout.Check(ctx, "other"))
+
+	// Успешная попытка снимает блокировку и сбрасывает счетчик
+	require.NoError(t, lockout.Succeed(ctx, "key"))
+	assert.NoError(t, lockout.Check(ctx, "key"))
+	assert.NoError(t, lockout.Fail(ctx, "key"))
+}
+
+func TestMemoryStore_Failures(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+
+	count, err := store.AddFailure(ctx, "key", time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = store.AddFailure(ctx, "key", time.Minute, now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// После окна без неудач счетчик начинается заново
+	count, err = store.AddFailure(ctx, "key", time.Minute, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// Блокировка снимается по истечении срока
+	require.NoError(t, store.Lock(ctx, "key", now.Add(time.Minute)))
+	until, err := store.LockedUntil(ctx, "key", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), until)
+
+	until, err = store.LockedUntil(ctx, "key", now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, until.IsZero())
+}
@@ -3,7 +3,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -17,14 +17,22 @@ type Config struct {
 	DBName    string
 	SSLMode   string
 	TracksDir string
+	// Logger — логгер попыток подключения; nil означает slog.Default()
+	Logger *slog.Logger
 }
 
+// NewPostgresDB подключается к БД с повторами. DSN содержит пароль, поэтому
+// в лог пишутся только адрес, база и пользователь
 func NewPostgresDB(cfg Config) (*sql.DB, error) {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger = logger.With("db_host", cfg.Host, "db_port", cfg.Port, "db_name", cfg.DBName, "db_user", cfg.Username)
+
 	dsn := fmt.Sprintf("host='%s' port='%s' user='%s' password='%s' dbname='%s' sslmode='%s'",
 		cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.DBName, cfg.SSLMode)
 
-	log.Printf("DSN строка подключения: %s", dsn)
-
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, err
@@ -37,17 +45,18 @@ func NewPostgresDB(cfg Config) (*sql.DB, error) {
 	for i := 0; i < maxRetries; i++ {
 		err = db.Ping()
 		if err == nil {
-			log.Println("Successfully connected to PostgreSQL database")
+			logger.Info("connected to database")
 			return db, nil
 		}
 
-		log.Printf("Не удалось подключиться к БД (попытка %d/%d): %v", i+1, maxRetries, err)
+		logger.Warn("database connection failed",
+			"attempt", i+1, "max_attempts", maxRetries, "retry_in", retryDelay, "error", err)
 
 		if i < maxRetries-1 {
-			log.Printf("Ожидание %v перед следующей попыткой...", retryDelay)
 			time.Sleep(retryDelay)
 		}
 	}
 
+	db.Close()
 	return nil, fmt.Errorf("не удалось подключиться к БД после %d попыток: %w", maxRetries, err)
 }
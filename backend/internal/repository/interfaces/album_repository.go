@@ -1,17 +1,21 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type AlbumRepository interface {
-	FindByID(id uuid.UUID) (*models.Album, error)
-	Save(album *models.Album) error
-	Delete(id uuid.UUID) error
-	GetTracks(albumID uuid.UUID) ([]*models.Track, error)
-	AddTrackToAlbum(albumID, trackID uuid.UUID) error
-	RemoveTrackFromAlbum(albumID, trackID uuid.UUID) error
-	ListAll() ([]*models.Album, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Album, error)
+	Save(ctx context.Context, album *models.Album) error
+	Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	GetTracks(ctx context.Context, albumID uuid.UUID) ([]*models.Track, error)
+	AddTrackToAlbum(ctx context.Context, albumID, trackID uuid.UUID) error
+	RemoveTrackFromAlbum(ctx context.Context, albumID, trackID uuid.UUID) error
+	ListAll(ctx context.Context) ([]*models.Album, error)
+	RefreshLoudness(ctx context.Context, albumID uuid.UUID) error
 }
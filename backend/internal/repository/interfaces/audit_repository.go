@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+	"time"
+)
+
+// AuditRepository — журнал изменяющих действий. Записи только добавляются и
+// не изменяются; DeleteBefore удаляет записи с истекшим сроком хранения
+type AuditRepository interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+	List(ctx context.Context, filter models.AuditFilter) (*models.AuditPage, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CoverRepository хранит записи об обложках и их файлы. Имена файлов
+// относительны каталога обложек в хранилище треков
+type CoverRepository interface {
+	Create(ctx context.Context, cover *models.Cover) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Cover, error)
+	SaveFile(ctx context.Context, name string, data []byte) error
+	FilePath(name string) string
+	DeleteFiles(ctx context.Context, dir string) error
+	SetEntityCover(ctx context.Context, entityType string, id uuid.UUID, coverURL string, updatedAt time.Time) error
+	FindArtistCover(ctx context.Context, artist string) (uuid.UUID, error)
+	SetArtistCover(ctx context.Context, artist string, coverID uuid.UUID, updatedAt time.Time) error
+	DeleteArtistCover(ctx context.Context, artist string) error
+	PlaylistAlbumCovers(ctx context.Context, playlistID uuid.UUID, limit int) ([]string, error)
+}
@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FingerprintRepository — акустические отпечатки треков, индекс их ключей
+// и найденные по ним подозреваемые дубликаты
+type FingerprintRepository interface {
+	// Save создает или заменяет отпечаток трека и его ключи в индексе
+	Save(ctx context.Context, trackID uuid.UUID, fingerprint []byte, hashes []uint32, createdAt time.Time) error
+	// FindByTrack возвращает отпечаток трека или models.ErrNotFound
+	FindByTrack(ctx context.Context, trackID uuid.UUID) ([]byte, error)
+	// FindCandidates возвращает отпечатки не больше limit треков не из
+	// корзины, у которых не меньше minShared общих с hashes ключей, — с
+	// наибольшим числом общих ключей. Трек excludeID пропускается
+	FindCandidates(ctx context.Context, excludeID uuid.UUID, hashes []uint32, minShared, limit int) (map[uuid.UUID][]byte, error)
+	// AddDuplicate запоминает пару подозреваемых дубликатов; для известной
+	// пары обновляет сходство
+	AddDuplicate(ctx context.Context, pair *models.DuplicatePair) error
+	// ListDuplicates возвращает пары, оба трека которых не в корзине
+	ListDuplicates(ctx context.Context) ([]*models.DuplicatePair, error)
+	// Delete удаляет отпечаток трека, его ключи и пары с ним
+	Delete(ctx context.Context, trackID uuid.UUID) error
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type FollowRepository interface {
+	FollowPlaylist(ctx context.Context, userID, playlistID uuid.UUID) error
+	UnfollowPlaylist(ctx context.Context, userID, playlistID uuid.UUID) error
+	GetPlaylistFollowers(ctx context.Context, playlistID uuid.UUID) ([]uuid.UUID, error)
+	GetFollowedPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error)
+	FollowArtist(ctx context.Context, userID uuid.UUID, artist string) error
+	UnfollowArtist(ctx context.Context, userID uuid.UUID, artist string) error
+	GetArtistFollowers(ctx context.Context, artist string) ([]uuid.UUID, error)
+	GetFollowedArtists(ctx context.Context, userID uuid.UUID) ([]string, error)
+}
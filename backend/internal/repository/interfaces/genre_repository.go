@@ -1,17 +1,19 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
 
 	"github.com/google/uuid"
 )
 
 type GenreRepository interface {
-	FindByID(id uuid.UUID) (*models.Genre, error)
-	Save(genre *models.Genre) error
-	Delete(id uuid.UUID) error
-	GetGenresForTrack(trackID uuid.UUID) ([]*models.Genre, error)
-	AddGenreToTrack(trackID, genreID uuid.UUID) error
-	RemoveGenreFromTrack(trackID, genreID uuid.UUID) error
-	ListAll() ([]*models.Genre, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Genre, error)
+	Save(ctx context.Context, genre *models.Genre) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetGenresForTrack(ctx context.Context, trackID uuid.UUID) ([]*models.Genre, error)
+	AddGenreToTrack(ctx context.Context, trackID, genreID uuid.UUID) error
+	RemoveGenreFromTrack(ctx context.Context, trackID, genreID uuid.UUID) error
+	SetTrackGenres(ctx context.Context, trackID uuid.UUID, genreIDs []uuid.UUID) error
+	ListAll(ctx context.Context) ([]*models.Genre, error)
 }
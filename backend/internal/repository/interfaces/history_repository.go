@@ -1,13 +1,14 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
 
 	"github.com/google/uuid"
 )
 
 type HistoryRepository interface {
-	AddEntry(userID uuid.UUID, trackID uuid.UUID) error
-	GetHistory(userID uuid.UUID) ([]*models.ListeningHistory, error)
-	GetPlayCount(trackID uuid.UUID) (int, error)
+	AddEntry(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) error
+	GetHistory(ctx context.Context, userID uuid.UUID) ([]*models.ListeningHistory, error)
+	GetPlayCount(ctx context.Context, trackID uuid.UUID) (int, error)
 }
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type IdentityRepository interface {
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*models.Identity, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Identity, error)
+	Create(ctx context.Context, identity *models.Identity) error
+	Delete(ctx context.Context, userID uuid.UUID, provider string) error
+
+	SaveState(ctx context.Context, state *models.OAuthState) error
+	ConsumeState(ctx context.Context, stateHash string) (*models.OAuthState, error)
+}
@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LyricsRepository — тексты песен, по одному на трек
+type LyricsRepository interface {
+	// FindByTrack возвращает текст трека или models.ErrNotFound
+	FindByTrack(ctx context.Context, trackID uuid.UUID) (*models.Lyrics, error)
+	// Save создает или заменяет текст трека
+	Save(ctx context.Context, lyrics *models.Lyrics) error
+	// SetStatus меняет статус модерации или возвращает models.ErrNotFound
+	SetStatus(ctx context.Context, trackID uuid.UUID, status string, moderatedBy uuid.UUID, moderatedAt time.Time) error
+	// Delete удаляет текст трека или возвращает models.ErrNotFound
+	Delete(ctx context.Context, trackID uuid.UUID) error
+	// List возвращает страницу текстов для модерации
+	List(ctx context.Context, filter models.LyricsFilter) (*models.LyricsPage, error)
+	// Search ищет одобренные тексты треков не из корзины, сначала наиболее
+	// релевантные
+	Search(ctx context.Context, query string, limit, offset int) ([]*models.LyricsMatch, error)
+	// TouchTrack обновляет updated_at трека: опубликованный текст входит в
+	// карточку трека, и ее ETag должен смениться
+	TouchTrack(ctx context.Context, trackID uuid.UUID, updatedAt time.Time) error
+}
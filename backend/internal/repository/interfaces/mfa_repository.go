@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type MFARepository interface {
+	GetTOTP(ctx context.Context, userID uuid.UUID) (*models.TOTPCredential, error)
+	SaveTOTP(ctx context.Context, credential *models.TOTPCredential) error
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	DeleteTOTP(ctx context.Context, userID uuid.UUID) error
+
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error)
+
+	CreateChallenge(ctx context.Context, challenge *models.MFAChallenge) error
+	GetChallenge(ctx context.Context, tokenHash string) (*models.MFAChallenge, error)
+	RecordFailedAttempt(ctx context.Context, tokenHash string, maxAttempts int) error
+	DeleteChallenge(ctx context.Context, tokenHash string) (bool, error)
+
+	ListRequiredRoles(ctx context.Context) ([]models.Permission, error)
+	SetRequiredRoles(ctx context.Context, roles []models.Permission) error
+}
@@ -1,12 +1,14 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: src/src/internal/repository/interfaces/album_repository.go
+// Source: internal/repository/interfaces/album_repository.go
 
 // Package mocks is a generated GoMock package.
 package mocks
 
 import (
+	context "context"
 	models "music-service/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -36,102 +38,130 @@ func (m *MockAlbumRepository) EXPECT() *MockAlbumRepositoryMockRecorder {
 }
 
 // AddTrackToAlbum mocks base method.
-func (m *MockAlbumRepository) AddTrackToAlbum(albumID, trackID uuid.UUID) error {
+func (m *MockAlbumRepository) AddTrackToAlbum(ctx context.Context, albumID, trackID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddTrackToAlbum", albumID, trackID)
+	ret := m.ctrl.Call(m, "AddTrackToAlbum", ctx, albumID, trackID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddTrackToAlbum indicates an expected call of AddTrackToAlbum.
-func (mr *MockAlbumRepositoryMockRecorder) AddTrackToAlbum(albumID, trackID interface{}) *gomock.Call {
+func (mr *MockAlbumRepositoryMockRecorder) AddTrackToAlbum(ctx, albumID, trackID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTrackToAlbum", reflect.TypeOf((*MockAlbumRepository)(nil).AddTrackToAlbum), albumID, trackID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTrackToAlbum", reflect.TypeOf((*MockAlbumRepository)(nil).AddTrackToAlbum), ctx, albumID, trackID)
 }
 
 // Delete mocks base method.
-func (m *MockAlbumRepository) Delete(id uuid.UUID) error {
+func (m *MockAlbumRepository) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockAlbumRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockAlbumRepositoryMockRecorder) Delete(ctx, id, deletedAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAlbumRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAlbumRepository)(nil).Delete), ctx, id, deletedAt)
 }
 
 // FindByID mocks base method.
-func (m *MockAlbumRepository) FindByID(id uuid.UUID) (*models.Album, error) {
+func (m *MockAlbumRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Album, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", id)
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
 	ret0, _ := ret[0].(*models.Album)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByID indicates an expected call of FindByID.
-func (mr *MockAlbumRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+func (mr *MockAlbumRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockAlbumRepository)(nil).FindByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockAlbumRepository)(nil).FindByID), ctx, id)
 }
 
 // GetTracks mocks base method.
-func (m *MockAlbumRepository) GetTracks(albumID uuid.UUID) ([]*models.Track, error) {
+func (m *MockAlbumRepository) GetTracks(ctx context.Context, albumID uuid.UUID) ([]*models.Track, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTracks", albumID)
+	ret := m.ctrl.Call(m, "GetTracks", ctx, albumID)
 	ret0, _ := ret[0].([]*models.Track)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTracks indicates an expected call of GetTracks.
-func (mr *MockAlbumRepositoryMockRecorder) GetTracks(albumID interface{}) *gomock.Call {
+func (mr *MockAlbumRepositoryMockRecorder) GetTracks(ctx, albumID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracks", reflect.TypeOf((*MockAlbumRepository)(nil).GetTracks), albumID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracks", reflect.TypeOf((*MockAlbumRepository)(nil).GetTracks), ctx, albumID)
 }
 
 // ListAll mocks base method.
-func (m *MockAlbumRepository) ListAll() ([]*models.Album, error) {
+func (m *MockAlbumRepository) ListAll(ctx context.Context) ([]*models.Album, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAll")
+	ret := m.ctrl.Call(m, "ListAll", ctx)
 	ret0, _ := ret[0].([]*models.Album)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListAll indicates an expected call of ListAll.
-func (mr *MockAlbumRepositoryMockRecorder) ListAll() *gomock.Call {
+func (mr *MockAlbumRepositoryMockRecorder) ListAll(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockAlbumRepository)(nil).ListAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockAlbumRepository)(nil).ListAll), ctx)
+}
+
+// RefreshLoudness mocks base method.
+func (m *MockAlbumRepository) RefreshLoudness(ctx context.Context, albumID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshLoudness", ctx, albumID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshLoudness indicates an expected call of RefreshLoudness.
+func (mr *MockAlbumRepositoryMockRecorder) RefreshLoudness(ctx, albumID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshLoudness", reflect.TypeOf((*MockAlbumRepository)(nil).RefreshLoudness), ctx, albumID)
 }
 
 // RemoveTrackFromAlbum mocks base method.
-func (m *MockAlbumRepository) RemoveTrackFromAlbum(albumID, trackID uuid.UUID) error {
+func (m *MockAlbumRepository) RemoveTrackFromAlbum(ctx context.Context, albumID, trackID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveTrackFromAlbum", albumID, trackID)
+	ret := m.ctrl.Call(m, "RemoveTrackFromAlbum", ctx, albumID, trackID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveTrackFromAlbum indicates an expected call of RemoveTrackFromAlbum.
-func (mr *MockAlbumRepositoryMockRecorder) RemoveTrackFromAlbum(albumID, trackID interface{}) *gomock.Call {
+func (mr *MockAlbumRepositoryMockRecorder) RemoveTrackFromAlbum(ctx, albumID, trackID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTrackFromAlbum", reflect.TypeOf((*MockAlbumRepository)(nil).RemoveTrackFromAlbum), ctx, albumID, trackID)
+}
+
+// Restore mocks base method.
+func (m *MockAlbumRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockAlbumRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTrackFromAlbum", reflect.TypeOf((*MockAlbumRepository)(nil).RemoveTrackFromAlbum), albumID, trackID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockAlbumRepository)(nil).Restore), ctx, id)
 }
 
 // Save mocks base method.
-func (m *MockAlbumRepository) Save(album *models.Album) error {
+func (m *MockAlbumRepository) Save(ctx context.Context, album *models.Album) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", album)
+	ret := m.ctrl.Call(m, "Save", ctx, album)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockAlbumRepositoryMockRecorder) Save(album interface{}) *gomock.Call {
+func (mr *MockAlbumRepositoryMockRecorder) Save(ctx, album interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAlbumRepository)(nil).Save), album)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAlbumRepository)(nil).Save), ctx, album)
 }
@@ -0,0 +1,181 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/cover_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockCoverRepository is a mock of CoverRepository interface.
+type MockCoverRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoverRepositoryMockRecorder
+}
+
+// MockCoverRepositoryMockRecorder is the mock recorder for MockCoverRepository.
+type MockCoverRepositoryMockRecorder struct {
+	mock *MockCoverRepository
+}
+
+// NewMockCoverRepository creates a new mock instance.
+func NewMockCoverRepository(ctrl *gomock.Controller) *MockCoverRepository {
+	mock := &MockCoverRepository{ctrl: ctrl}
+	mock.recorder = &MockCoverRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoverRepository) EXPECT() *MockCoverRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCoverRepository) Create(ctx context.Context, cover *models.Cover) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, cover)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCoverRepositoryMockRecorder) Create(ctx, cover interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCoverRepository)(nil).Create), ctx, cover)
+}
+
+// DeleteArtistCover mocks base method.
+func (m *MockCoverRepository) DeleteArtistCover(ctx context.Context, artist string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteArtistCover", ctx, artist)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteArtistCover indicates an expected call of DeleteArtistCover.
+func (mr *MockCoverRepositoryMockRecorder) DeleteArtistCover(ctx, artist interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteArtistCover", reflect.TypeOf((*MockCoverRepository)(nil).DeleteArtistCover), ctx, artist)
+}
+
+// DeleteFiles mocks base method.
+func (m *MockCoverRepository) DeleteFiles(ctx context.Context, dir string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFiles", ctx, dir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFiles indicates an expected call of DeleteFiles.
+func (mr *MockCoverRepositoryMockRecorder) DeleteFiles(ctx, dir interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFiles", reflect.TypeOf((*MockCoverRepository)(nil).DeleteFiles), ctx, dir)
+}
+
+// FilePath mocks base method.
+func (m *MockCoverRepository) FilePath(name string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilePath", name)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// FilePath indicates an expected call of FilePath.
+func (mr *MockCoverRepositoryMockRecorder) FilePath(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilePath", reflect.TypeOf((*MockCoverRepository)(nil).FilePath), name)
+}
+
+// FindArtistCover mocks base method.
+func (m *MockCoverRepository) FindArtistCover(ctx context.Context, artist string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindArtistCover", ctx, artist)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindArtistCover indicates an expected call of FindArtistCover.
+func (mr *MockCoverRepositoryMockRecorder) FindArtistCover(ctx, artist interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindArtistCover", reflect.TypeOf((*MockCoverRepository)(nil).FindArtistCover), ctx, artist)
+}
+
+// FindByID mocks base method.
+func (m *MockCoverRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Cover, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*models.Cover)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockCoverRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCoverRepository)(nil).FindByID), ctx, id)
+}
+
+// PlaylistAlbumCovers mocks base method.
+func (m *MockCoverRepository) PlaylistAlbumCovers(ctx context.Context, playlistID uuid.UUID, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaylistAlbumCovers", ctx, playlistID, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaylistAlbumCovers indicates an expected call of PlaylistAlbumCovers.
+func (mr *MockCoverRepositoryMockRecorder) PlaylistAlbumCovers(ctx, playlistID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaylistAlbumCovers", reflect.TypeOf((*MockCoverRepository)(nil).PlaylistAlbumCovers), ctx, playlistID, limit)
+}
+
+// SaveFile mocks base method.
+func (m *MockCoverRepository) SaveFile(ctx context.Context, name string, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFile", ctx, name, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFile indicates an expected call of SaveFile.
+func (mr *MockCoverRepositoryMockRecorder) SaveFile(ctx, name, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFile", reflect.TypeOf((*MockCoverRepository)(nil).SaveFile), ctx, name, data)
+}
+
+// SetArtistCover mocks base method.
+func (m *MockCoverRepository) SetArtistCover(ctx context.Context, artist string, coverID uuid.UUID, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetArtistCover", ctx, artist, coverID, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetArtistCover indicates an expected call of SetArtistCover.
+func (mr *MockCoverRepositoryMockRecorder) SetArtistCover(ctx, artist, coverID, updatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArtistCover", reflect.TypeOf((*MockCoverRepository)(nil).SetArtistCover), ctx, artist, coverID, updatedAt)
+}
+
+// SetEntityCover mocks base method.
+func (m *MockCoverRepository) SetEntityCover(ctx context.Context, entityType string, id uuid.UUID, coverURL string, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEntityCover", ctx, entityType, id, coverURL, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEntityCover indicates an expected call of SetEntityCover.
+func (mr *MockCoverRepositoryMockRecorder) SetEntityCover(ctx, entityType, id, coverURL, updatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntityCover", reflect.TypeOf((*MockCoverRepository)(nil).SetEntityCover), ctx, entityType, id, coverURL, updatedAt)
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/fingerprint_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockFingerprintRepository is a mock of FingerprintRepository interface.
+type MockFingerprintRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFingerprintRepositoryMockRecorder
+}
+
+// MockFingerprintRepositoryMockRecorder is the mock recorder for MockFingerprintRepository.
+type MockFingerprintRepositoryMockRecorder struct {
+	mock *MockFingerprintRepository
+}
+
+// NewMockFingerprintRepository creates a new mock instance.
+func NewMockFingerprintRepository(ctrl *gomock.Controller) *MockFingerprintRepository {
+	mock := &MockFingerprintRepository{ctrl: ctrl}
+	mock.recorder = &MockFingerprintRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFingerprintRepository) EXPECT() *MockFingerprintRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddDuplicate mocks base method.
+func (m *MockFingerprintRepository) AddDuplicate(ctx context.Context, pair *models.DuplicatePair) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDuplicate", ctx, pair)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDuplicate indicates an expected call of AddDuplicate.
+func (mr *MockFingerprintRepositoryMockRecorder) AddDuplicate(ctx, pair interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDuplicate", reflect.TypeOf((*MockFingerprintRepository)(nil).AddDuplicate), ctx, pair)
+}
+
+// Delete mocks base method.
+func (m *MockFingerprintRepository) Delete(ctx context.Context, trackID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, trackID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFingerprintRepositoryMockRecorder) Delete(ctx, trackID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFingerprintRepository)(nil).Delete), ctx, trackID)
+}
+
+// FindByTrack mocks base method.
+func (m *MockFingerprintRepository) FindByTrack(ctx context.Context, trackID uuid.UUID) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTrack", ctx, trackID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTrack indicates an expected call of FindByTrack.
+func (mr *MockFingerprintRepositoryMockRecorder) FindByTrack(ctx, trackID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTrack", reflect.TypeOf((*MockFingerprintRepository)(nil).FindByTrack), ctx, trackID)
+}
+
+// FindCandidates mocks base method.
+func (m *MockFingerprintRepository) FindCandidates(ctx context.Context, excludeID uuid.UUID, hashes []uint32, minShared, limit int) (map[uuid.UUID][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCandidates", ctx, excludeID, hashes, minShared, limit)
+	ret0, _ := ret[0].(map[uuid.UUID][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCandidates indicates an expected call of FindCandidates.
+func (mr *MockFingerprintRepositoryMockRecorder) FindCandidates(ctx, excludeID, hashes, minShared, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCandidates", reflect.TypeOf((*MockFingerprintRepository)(nil).FindCandidates), ctx, excludeID, hashes, minShared, limit)
+}
+
+// ListDuplicates mocks base method.
+func (m *MockFingerprintRepository) ListDuplicates(ctx context.Context) ([]*models.DuplicatePair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDuplicates", ctx)
+	ret0, _ := ret[0].([]*models.DuplicatePair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDuplicates indicates an expected call of ListDuplicates.
+func (mr *MockFingerprintRepositoryMockRecorder) ListDuplicates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDuplicates", reflect.TypeOf((*MockFingerprintRepository)(nil).ListDuplicates), ctx)
+}
+
+// Save mocks base method.
+func (m *MockFingerprintRepository) Save(ctx context.Context, trackID uuid.UUID, fingerprint []byte, hashes []uint32, createdAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, trackID, fingerprint, hashes, createdAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockFingerprintRepositoryMockRecorder) Save(ctx, trackID, fingerprint, hashes, createdAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockFingerprintRepository)(nil).Save), ctx, trackID, fingerprint, hashes, createdAt)
+}
@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/follow_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockFollowRepository is a mock of FollowRepository interface.
+type MockFollowRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowRepositoryMockRecorder
+}
+
+// MockFollowRepositoryMockRecorder is the mock recorder for MockFollowRepository.
+type MockFollowRepositoryMockRecorder struct {
+	mock *MockFollowRepository
+}
+
+// NewMockFollowRepository creates a new mock instance.
+func NewMockFollowRepository(ctrl *gomock.Controller) *MockFollowRepository {
+	mock := &MockFollowRepository{ctrl: ctrl}
+	mock.recorder = &MockFollowRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowRepository) EXPECT() *MockFollowRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FollowArtist mocks base method.
+func (m *MockFollowRepository) FollowArtist(ctx context.Context, userID uuid.UUID, artist string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowArtist", ctx, userID, artist)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowArtist indicates an expected call of FollowArtist.
+func (mr *MockFollowRepositoryMockRecorder) FollowArtist(ctx, userID, artist interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowArtist", reflect.TypeOf((*MockFollowRepository)(nil).FollowArtist), ctx, userID, artist)
+}
+
+// FollowPlaylist mocks base method.
+func (m *MockFollowRepository) FollowPlaylist(ctx context.Context, userID, playlistID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowPlaylist", ctx, userID, playlistID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowPlaylist indicates an expected call of FollowPlaylist.
+func (mr *MockFollowRepositoryMockRecorder) FollowPlaylist(ctx, userID, playlistID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowPlaylist", reflect.TypeOf((*MockFollowRepository)(nil).FollowPlaylist), ctx, userID, playlistID)
+}
+
+// GetArtistFollowers mocks base method.
+func (m *MockFollowRepository) GetArtistFollowers(ctx context.Context, artist string) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtistFollowers", ctx, artist)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtistFollowers indicates an expected call of GetArtistFollowers.
+func (mr *MockFollowRepositoryMockRecorder) GetArtistFollowers(ctx, artist interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtistFollowers", reflect.TypeOf((*MockFollowRepository)(nil).GetArtistFollowers), ctx, artist)
+}
+
+// GetFollowedArtists mocks base method.
+func (m *MockFollowRepository) GetFollowedArtists(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowedArtists", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowedArtists indicates an expected call of GetFollowedArtists.
+func (mr *MockFollowRepositoryMockRecorder) GetFollowedArtists(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowedArtists", reflect.TypeOf((*MockFollowRepository)(nil).GetFollowedArtists), ctx, userID)
+}
+
+// GetFollowedPlaylists mocks base method.
+func (m *MockFollowRepository) GetFollowedPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowedPlaylists", ctx, userID)
+	ret0, _ := ret[0].([]*models.Playlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowedPlaylists indicates an expected call of GetFollowedPlaylists.
+func (mr *MockFollowRepositoryMockRecorder) GetFollowedPlaylists(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowedPlaylists", reflect.TypeOf((*MockFollowRepository)(nil).GetFollowedPlaylists), ctx, userID)
+}
+
+// GetPlaylistFollowers mocks base method.
+func (m *MockFollowRepository) GetPlaylistFollowers(ctx context.Context, playlistID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlaylistFollowers", ctx, playlistID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlaylistFollowers indicates an expected call of GetPlaylistFollowers.
+func (mr *MockFollowRepositoryMockRecorder) GetPlaylistFollowers(ctx, playlistID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaylistFollowers", reflect.TypeOf((*MockFollowRepository)(nil).GetPlaylistFollowers), ctx, playlistID)
+}
+
+// UnfollowArtist mocks base method.
+func (m *MockFollowRepository) UnfollowArtist(ctx context.Context, userID uuid.UUID, artist string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowArtist", ctx, userID, artist)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowArtist indicates an expected call of UnfollowArtist.
+func (mr *MockFollowRepositoryMockRecorder) UnfollowArtist(ctx, userID, artist interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowArtist", reflect.TypeOf((*MockFollowRepository)(nil).UnfollowArtist), ctx, userID, artist)
+}
+
+// UnfollowPlaylist mocks base method.
+func (m *MockFollowRepository) UnfollowPlaylist(ctx context.Context, userID, playlistID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowPlaylist", ctx, userID, playlistID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowPlaylist indicates an expected call of UnfollowPlaylist.
+func (mr *MockFollowRepositoryMockRecorder) UnfollowPlaylist(ctx, userID, playlistID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowPlaylist", reflect.TypeOf((*MockFollowRepository)(nil).UnfollowPlaylist), ctx, userID, playlistID)
+}
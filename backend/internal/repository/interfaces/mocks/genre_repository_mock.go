@@ -1,10 +1,11 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: src/src/internal/repository/interfaces/genre_repository.go
+// Source: internal/repository/interfaces/genre_repository.go
 
 // Package mocks is a generated GoMock package.
 package mocks
 
 import (
+	context "context"
 	models "music-service/internal/models"
 	reflect "reflect"
 
@@ -36,102 +37,116 @@ func (m *MockGenreRepository) EXPECT() *MockGenreRepositoryMockRecorder {
 }
 
 // AddGenreToTrack mocks base method.
-func (m *MockGenreRepository) AddGenreToTrack(trackID, genreID uuid.UUID) error {
+func (m *MockGenreRepository) AddGenreToTrack(ctx context.Context, trackID, genreID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddGenreToTrack", trackID, genreID)
+	ret := m.ctrl.Call(m, "AddGenreToTrack", ctx, trackID, genreID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddGenreToTrack indicates an expected call of AddGenreToTrack.
-func (mr *MockGenreRepositoryMockRecorder) AddGenreToTrack(trackID, genreID interface{}) *gomock.Call {
+func (mr *MockGenreRepositoryMockRecorder) AddGenreToTrack(ctx, trackID, genreID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGenreToTrack", reflect.TypeOf((*MockGenreRepository)(nil).AddGenreToTrack), trackID, genreID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGenreToTrack", reflect.TypeOf((*MockGenreRepository)(nil).AddGenreToTrack), ctx, trackID, genreID)
 }
 
 // Delete mocks base method.
-func (m *MockGenreRepository) Delete(id uuid.UUID) error {
+func (m *MockGenreRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockGenreRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockGenreRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGenreRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGenreRepository)(nil).Delete), ctx, id)
 }
 
 // FindByID mocks base method.
-func (m *MockGenreRepository) FindByID(id uuid.UUID) (*models.Genre, error) {
+func (m *MockGenreRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Genre, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", id)
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
 	ret0, _ := ret[0].(*models.Genre)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByID indicates an expected call of FindByID.
-func (mr *MockGenreRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+func (mr *MockGenreRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockGenreRepository)(nil).FindByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockGenreRepository)(nil).FindByID), ctx, id)
 }
 
 // GetGenresForTrack mocks base method.
-func (m *MockGenreRepository) GetGenresForTrack(trackID uuid.UUID) ([]*models.Genre, error) {
+func (m *MockGenreRepository) GetGenresForTrack(ctx context.Context, trackID uuid.UUID) ([]*models.Genre, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGenresForTrack", trackID)
+	ret := m.ctrl.Call(m, "GetGenresForTrack", ctx, trackID)
 	ret0, _ := ret[0].([]*models.Genre)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetGenresForTrack indicates an expected call of GetGenresForTrack.
-func (mr *MockGenreRepositoryMockRecorder) GetGenresForTrack(trackID interface{}) *gomock.Call {
+func (mr *MockGenreRepositoryMockRecorder) GetGenresForTrack(ctx, trackID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenresForTrack", reflect.TypeOf((*MockGenreRepository)(nil).GetGenresForTrack), trackID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenresForTrack", reflect.TypeOf((*MockGenreRepository)(nil).GetGenresForTrack), ctx, trackID)
 }
 
 // ListAll mocks base method.
-func (m *MockGenreRepository) ListAll() ([]*models.Genre, error) {
+func (m *MockGenreRepository) ListAll(ctx context.Context) ([]*models.Genre, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAll")
+	ret := m.ctrl.Call(m, "ListAll", ctx)
 	ret0, _ := ret[0].([]*models.Genre)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListAll indicates an expected call of ListAll.
-func (mr *MockGenreRepositoryMockRecorder) ListAll() *gomock.Call {
+func (mr *MockGenreRepositoryMockRecorder) ListAll(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockGenreRepository)(nil).ListAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockGenreRepository)(nil).ListAll), ctx)
 }
 
 // RemoveGenreFromTrack mocks base method.
-func (m *MockGenreRepository) RemoveGenreFromTrack(trackID, genreID uuid.UUID) error {
+func (m *MockGenreRepository) RemoveGenreFromTrack(ctx context.Context, trackID, genreID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveGenreFromTrack", trackID, genreID)
+	ret := m.ctrl.Call(m, "RemoveGenreFromTrack", ctx, trackID, genreID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveGenreFromTrack indicates an expected call of RemoveGenreFromTrack.
-func (mr *MockGenreRepositoryMockRecorder) RemoveGenreFromTrack(trackID, genreID interface{}) *gomock.Call {
+func (mr *MockGenreRepositoryMockRecorder) RemoveGenreFromTrack(ctx, trackID, genreID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveGenreFromTrack", reflect.TypeOf((*MockGenreRepository)(nil).RemoveGenreFromTrack), trackID, genreID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveGenreFromTrack", reflect.TypeOf((*MockGenreRepository)(nil).RemoveGenreFromTrack), ctx, trackID, genreID)
 }
 
 // Save mocks base method.
-func (m *MockGenreRepository) Save(genre *models.Genre) error {
+func (m *MockGenreRepository) Save(ctx context.Context, genre *models.Genre) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", genre)
+	ret := m.ctrl.Call(m, "Save", ctx, genre)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockGenreRepositoryMockRecorder) Save(genre interface{}) *gomock.Call {
+func (mr *MockGenreRepositoryMockRecorder) Save(ctx, genre interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockGenreRepository)(nil).Save), genre)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockGenreRepository)(nil).Save), ctx, genre)
+}
+
+// SetTrackGenres mocks base method.
+func (m *MockGenreRepository) SetTrackGenres(ctx context.Context, trackID uuid.UUID, genreIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTrackGenres", ctx, trackID, genreIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTrackGenres indicates an expected call of SetTrackGenres.
+func (mr *MockGenreRepositoryMockRecorder) SetTrackGenres(ctx, trackID, genreIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrackGenres", reflect.TypeOf((*MockGenreRepository)(nil).SetTrackGenres), ctx, trackID, genreIDs)
 }
@@ -1,10 +1,11 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: src/src/internal/repository/interfaces/history_repository.go
+// Source: internal/repository/interfaces/history_repository.go
 
 // Package mocks is a generated GoMock package.
 package mocks
 
 import (
+	context "context"
 	models "music-service/internal/models"
 	reflect "reflect"
 
@@ -36,30 +37,45 @@ func (m *MockHistoryRepository) EXPECT() *MockHistoryRepositoryMockRecorder {
 }
 
 // AddEntry mocks base method.
-func (m *MockHistoryRepository) AddEntry(userID, trackID uuid.UUID) error {
+func (m *MockHistoryRepository) AddEntry(ctx context.Context, userID, trackID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddEntry", userID, trackID)
+	ret := m.ctrl.Call(m, "AddEntry", ctx, userID, trackID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddEntry indicates an expected call of AddEntry.
-func (mr *MockHistoryRepositoryMockRecorder) AddEntry(userID, trackID interface{}) *gomock.Call {
+func (mr *MockHistoryRepositoryMockRecorder) AddEntry(ctx, userID, trackID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEntry", reflect.TypeOf((*MockHistoryRepository)(nil).AddEntry), userID, trackID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEntry", reflect.TypeOf((*MockHistoryRepository)(nil).AddEntry), ctx, userID, trackID)
 }
 
 // GetHistory mocks base method.
-func (m *MockHistoryRepository) GetHistory(userID uuid.UUID) ([]*models.ListeningHistory, error) {
+func (m *MockHistoryRepository) GetHistory(ctx context.Context, userID uuid.UUID) ([]*models.ListeningHistory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetHistory", userID)
+	ret := m.ctrl.Call(m, "GetHistory", ctx, userID)
 	ret0, _ := ret[0].([]*models.ListeningHistory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetHistory indicates an expected call of GetHistory.
-func (mr *MockHistoryRepositoryMockRecorder) GetHistory(userID interface{}) *gomock.Call {
+func (mr *MockHistoryRepositoryMockRecorder) GetHistory(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockHistoryRepository)(nil).GetHistory), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockHistoryRepository)(nil).GetHistory), ctx, userID)
+}
+
+// GetPlayCount mocks base method.
+func (m *MockHistoryRepository) GetPlayCount(ctx context.Context, trackID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlayCount", ctx, trackID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlayCount indicates an expected call of GetPlayCount.
+func (mr *MockHistoryRepositoryMockRecorder) GetPlayCount(ctx, trackID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlayCount", reflect.TypeOf((*MockHistoryRepository)(nil).GetPlayCount), ctx, trackID)
 }
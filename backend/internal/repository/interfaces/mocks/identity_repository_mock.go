@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/identity_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockIdentityRepository is a mock of IdentityRepository interface.
+type MockIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityRepositoryMockRecorder
+}
+
+// MockIdentityRepositoryMockRecorder is the mock recorder for MockIdentityRepository.
+type MockIdentityRepositoryMockRecorder struct {
+	mock *MockIdentityRepository
+}
+
+// NewMockIdentityRepository creates a new mock instance.
+func NewMockIdentityRepository(ctrl *gomock.Controller) *MockIdentityRepository {
+	mock := &MockIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityRepository) EXPECT() *MockIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeState mocks base method.
+func (m *MockIdentityRepository) ConsumeState(ctx context.Context, stateHash string) (*models.OAuthState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeState", ctx, stateHash)
+	ret0, _ := ret[0].(*models.OAuthState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeState indicates an expected call of ConsumeState.
+func (mr *MockIdentityRepositoryMockRecorder) ConsumeState(ctx, stateHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeState", reflect.TypeOf((*MockIdentityRepository)(nil).ConsumeState), ctx, stateHash)
+}
+
+// Create mocks base method.
+func (m *MockIdentityRepository) Create(ctx context.Context, identity *models.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIdentityRepositoryMockRecorder) Create(ctx, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIdentityRepository)(nil).Create), ctx, identity)
+}
+
+// Delete mocks base method.
+func (m *MockIdentityRepository) Delete(ctx context.Context, userID uuid.UUID, provider string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, provider)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIdentityRepositoryMockRecorder) Delete(ctx, userID, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIdentityRepository)(nil).Delete), ctx, userID, provider)
+}
+
+// FindByProviderSubject mocks base method.
+func (m *MockIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*models.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByProviderSubject", ctx, provider, subject)
+	ret0, _ := ret[0].(*models.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByProviderSubject indicates an expected call of FindByProviderSubject.
+func (mr *MockIdentityRepositoryMockRecorder) FindByProviderSubject(ctx, provider, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByProviderSubject", reflect.TypeOf((*MockIdentityRepository)(nil).FindByProviderSubject), ctx, provider, subject)
+}
+
+// ListByUser mocks base method.
+func (m *MockIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]*models.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockIdentityRepositoryMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockIdentityRepository)(nil).ListByUser), ctx, userID)
+}
+
+// SaveState mocks base method.
+func (m *MockIdentityRepository) SaveState(ctx context.Context, state *models.OAuthState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveState", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveState indicates an expected call of SaveState.
+func (mr *MockIdentityRepositoryMockRecorder) SaveState(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveState", reflect.TypeOf((*MockIdentityRepository)(nil).SaveState), ctx, state)
+}
@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/lyrics_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockLyricsRepository is a mock of LyricsRepository interface.
+type MockLyricsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLyricsRepositoryMockRecorder
+}
+
+// MockLyricsRepositoryMockRecorder is the mock recorder for MockLyricsRepository.
+type MockLyricsRepositoryMockRecorder struct {
+	mock *MockLyricsRepository
+}
+
+// NewMockLyricsRepository creates a new mock instance.
+func NewMockLyricsRepository(ctrl *gomock.Controller) *MockLyricsRepository {
+	mock := &MockLyricsRepository{ctrl: ctrl}
+	mock.recorder = &MockLyricsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLyricsRepository) EXPECT() *MockLyricsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockLyricsRepository) Delete(ctx context.Context, trackID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, trackID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLyricsRepositoryMockRecorder) Delete(ctx, trackID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLyricsRepository)(nil).Delete), ctx, trackID)
+}
+
+// FindByTrack mocks base method.
+func (m *MockLyricsRepository) FindByTrack(ctx context.Context, trackID uuid.UUID) (*models.Lyrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTrack", ctx, trackID)
+	ret0, _ := ret[0].(*models.Lyrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTrack indicates an expected call of FindByTrack.
+func (mr *MockLyricsRepositoryMockRecorder) FindByTrack(ctx, trackID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTrack", reflect.TypeOf((*MockLyricsRepository)(nil).FindByTrack), ctx, trackID)
+}
+
+// List mocks base method.
+func (m *MockLyricsRepository) List(ctx context.Context, filter models.LyricsFilter) (*models.LyricsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].(*models.LyricsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockLyricsRepositoryMockRecorder) List(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockLyricsRepository)(nil).List), ctx, filter)
+}
+
+// Save mocks base method.
+func (m *MockLyricsRepository) Save(ctx context.Context, lyrics *models.Lyrics) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, lyrics)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockLyricsRepositoryMockRecorder) Save(ctx, lyrics interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockLyricsRepository)(nil).Save), ctx, lyrics)
+}
+
+// Search mocks base method.
+func (m *MockLyricsRepository) Search(ctx context.Context, query string, limit, offset int) ([]*models.LyricsMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, limit, offset)
+	ret0, _ := ret[0].([]*models.LyricsMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockLyricsRepositoryMockRecorder) Search(ctx, query, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockLyricsRepository)(nil).Search), ctx, query, limit, offset)
+}
+
+// SetStatus mocks base method.
+func (m *MockLyricsRepository) SetStatus(ctx context.Context, trackID uuid.UUID, status string, moderatedBy uuid.UUID, moderatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", ctx, trackID, status, moderatedBy, moderatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockLyricsRepositoryMockRecorder) SetStatus(ctx, trackID, status, moderatedBy, moderatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockLyricsRepository)(nil).SetStatus), ctx, trackID, status, moderatedBy, moderatedAt)
+}
+
+// TouchTrack mocks base method.
+func (m *MockLyricsRepository) TouchTrack(ctx context.Context, trackID uuid.UUID, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchTrack", ctx, trackID, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchTrack indicates an expected call of TouchTrack.
+func (mr *MockLyricsRepositoryMockRecorder) TouchTrack(ctx, trackID, updatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchTrack", reflect.TypeOf((*MockLyricsRepository)(nil).TouchTrack), ctx, trackID, updatedAt)
+}
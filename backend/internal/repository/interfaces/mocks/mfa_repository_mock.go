@@ -0,0 +1,241 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/mfa_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockMFARepository is a mock of MFARepository interface.
+type MockMFARepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFARepositoryMockRecorder
+}
+
+// MockMFARepositoryMockRecorder is the mock recorder for MockMFARepository.
+type MockMFARepositoryMockRecorder struct {
+	mock *MockMFARepository
+}
+
+// NewMockMFARepository creates a new mock instance.
+func NewMockMFARepository(ctrl *gomock.Controller) *MockMFARepository {
+	mock := &MockMFARepository{ctrl: ctrl}
+	mock.recorder = &MockMFARepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFARepository) EXPECT() *MockMFARepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockMFARepository) ConfirmTOTP(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", ctx, userID, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockMFARepositoryMockRecorder) ConfirmTOTP(ctx, userID, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockMFARepository)(nil).ConfirmTOTP), ctx, userID, step)
+}
+
+// CountRecoveryCodes mocks base method.
+func (m *MockMFARepository) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRecoveryCodes indicates an expected call of CountRecoveryCodes.
+func (mr *MockMFARepositoryMockRecorder) CountRecoveryCodes(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecoveryCodes", reflect.TypeOf((*MockMFARepository)(nil).CountRecoveryCodes), ctx, userID)
+}
+
+// CreateChallenge mocks base method.
+func (m *MockMFARepository) CreateChallenge(ctx context.Context, challenge *models.MFAChallenge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChallenge", ctx, challenge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateChallenge indicates an expected call of CreateChallenge.
+func (mr *MockMFARepositoryMockRecorder) CreateChallenge(ctx, challenge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallenge", reflect.TypeOf((*MockMFARepository)(nil).CreateChallenge), ctx, challenge)
+}
+
+// DeleteChallenge mocks base method.
+func (m *MockMFARepository) DeleteChallenge(ctx context.Context, tokenHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChallenge", ctx, tokenHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteChallenge indicates an expected call of DeleteChallenge.
+func (mr *MockMFARepositoryMockRecorder) DeleteChallenge(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChallenge", reflect.TypeOf((*MockMFARepository)(nil).DeleteChallenge), ctx, tokenHash)
+}
+
+// DeleteTOTP mocks base method.
+func (m *MockMFARepository) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTP", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTOTP indicates an expected call of DeleteTOTP.
+func (mr *MockMFARepositoryMockRecorder) DeleteTOTP(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTP", reflect.TypeOf((*MockMFARepository)(nil).DeleteTOTP), ctx, userID)
+}
+
+// GetChallenge mocks base method.
+func (m *MockMFARepository) GetChallenge(ctx context.Context, tokenHash string) (*models.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChallenge", ctx, tokenHash)
+	ret0, _ := ret[0].(*models.MFAChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChallenge indicates an expected call of GetChallenge.
+func (mr *MockMFARepositoryMockRecorder) GetChallenge(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChallenge", reflect.TypeOf((*MockMFARepository)(nil).GetChallenge), ctx, tokenHash)
+}
+
+// GetTOTP mocks base method.
+func (m *MockMFARepository) GetTOTP(ctx context.Context, userID uuid.UUID) (*models.TOTPCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTOTP", ctx, userID)
+	ret0, _ := ret[0].(*models.TOTPCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTOTP indicates an expected call of GetTOTP.
+func (mr *MockMFARepositoryMockRecorder) GetTOTP(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTOTP", reflect.TypeOf((*MockMFARepository)(nil).GetTOTP), ctx, userID)
+}
+
+// ListRequiredRoles mocks base method.
+func (m *MockMFARepository) ListRequiredRoles(ctx context.Context) ([]models.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRequiredRoles", ctx)
+	ret0, _ := ret[0].([]models.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRequiredRoles indicates an expected call of ListRequiredRoles.
+func (mr *MockMFARepositoryMockRecorder) ListRequiredRoles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRequiredRoles", reflect.TypeOf((*MockMFARepository)(nil).ListRequiredRoles), ctx)
+}
+
+// RecordFailedAttempt mocks base method.
+func (m *MockMFARepository) RecordFailedAttempt(ctx context.Context, tokenHash string, maxAttempts int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailedAttempt", ctx, tokenHash, maxAttempts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailedAttempt indicates an expected call of RecordFailedAttempt.
+func (mr *MockMFARepositoryMockRecorder) RecordFailedAttempt(ctx, tokenHash, maxAttempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailedAttempt", reflect.TypeOf((*MockMFARepository)(nil).RecordFailedAttempt), ctx, tokenHash, maxAttempts)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", ctx, userID, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockMFARepositoryMockRecorder) ReplaceRecoveryCodes(ctx, userID, codeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockMFARepository)(nil).ReplaceRecoveryCodes), ctx, userID, codeHashes)
+}
+
+// SaveTOTP mocks base method.
+func (m *MockMFARepository) SaveTOTP(ctx context.Context, credential *models.TOTPCredential) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTOTP", ctx, credential)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTOTP indicates an expected call of SaveTOTP.
+func (mr *MockMFARepositoryMockRecorder) SaveTOTP(ctx, credential interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTOTP", reflect.TypeOf((*MockMFARepository)(nil).SaveTOTP), ctx, credential)
+}
+
+// SetRequiredRoles mocks base method.
+func (m *MockMFARepository) SetRequiredRoles(ctx context.Context, roles []models.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRequiredRoles", ctx, roles)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRequiredRoles indicates an expected call of SetRequiredRoles.
+func (mr *MockMFARepositoryMockRecorder) SetRequiredRoles(ctx, roles interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRequiredRoles", reflect.TypeOf((*MockMFARepository)(nil).SetRequiredRoles), ctx, roles)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockMFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockMFARepositoryMockRecorder) UseRecoveryCode(ctx, userID, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockMFARepository)(nil).UseRecoveryCode), ctx, userID, codeHash)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockMFARepository) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", ctx, userID, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockMFARepositoryMockRecorder) UseTOTPStep(ctx, userID, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockMFARepository)(nil).UseTOTPStep), ctx, userID, step)
+}
@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/outbox_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockOutboxRepository) Add(ctx context.Context, event *models.DomainEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockOutboxRepositoryMockRecorder) Add(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockOutboxRepository)(nil).Add), ctx, event)
+}
+
+// ClaimPending mocks base method.
+func (m *MockOutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*models.DomainEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPending", ctx, limit, lease)
+	ret0, _ := ret[0].([]*models.DomainEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPending indicates an expected call of ClaimPending.
+func (mr *MockOutboxRepositoryMockRecorder) ClaimPending(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPending", reflect.TypeOf((*MockOutboxRepository)(nil).ClaimPending), ctx, limit, lease)
+}
+
+// DeleteProcessed mocks base method.
+func (m *MockOutboxRepository) DeleteProcessed(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProcessed", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteProcessed indicates an expected call of DeleteProcessed.
+func (mr *MockOutboxRepositoryMockRecorder) DeleteProcessed(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProcessed", reflect.TypeOf((*MockOutboxRepository)(nil).DeleteProcessed), ctx, before)
+}
+
+// MarkDead mocks base method.
+func (m *MockOutboxRepository) MarkDead(ctx context.Context, id uuid.UUID, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDead", ctx, id, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDead indicates an expected call of MarkDead.
+func (mr *MockOutboxRepositoryMockRecorder) MarkDead(ctx, id, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDead", reflect.TypeOf((*MockOutboxRepository)(nil).MarkDead), ctx, id, lastError)
+}
+
+// MarkFailed mocks base method.
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockOutboxRepositoryMockRecorder) MarkFailed(ctx, id, lastError, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockOutboxRepository)(nil).MarkFailed), ctx, id, lastError, nextAttemptAt)
+}
+
+// MarkHandled mocks base method.
+func (m *MockOutboxRepository) MarkHandled(ctx context.Context, id uuid.UUID, handler string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkHandled", ctx, id, handler)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkHandled indicates an expected call of MarkHandled.
+func (mr *MockOutboxRepositoryMockRecorder) MarkHandled(ctx, id, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkHandled", reflect.TypeOf((*MockOutboxRepository)(nil).MarkHandled), ctx, id, handler)
+}
+
+// MarkProcessed mocks base method.
+func (m *MockOutboxRepository) MarkProcessed(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkProcessed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkProcessed indicates an expected call of MarkProcessed.
+func (mr *MockOutboxRepositoryMockRecorder) MarkProcessed(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkProcessed", reflect.TypeOf((*MockOutboxRepository)(nil).MarkProcessed), ctx, id)
+}
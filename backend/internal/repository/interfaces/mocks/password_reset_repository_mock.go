@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/password_reset_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPasswordResetRepository is a mock of PasswordResetRepository interface.
+type MockPasswordResetRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetRepositoryMockRecorder
+}
+
+// MockPasswordResetRepositoryMockRecorder is the mock recorder for MockPasswordResetRepository.
+type MockPasswordResetRepositoryMockRecorder struct {
+	mock *MockPasswordResetRepository
+}
+
+// NewMockPasswordResetRepository creates a new mock instance.
+func NewMockPasswordResetRepository(ctrl *gomock.Controller) *MockPasswordResetRepository {
+	mock := &MockPasswordResetRepository{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordResetRepository) EXPECT() *MockPasswordResetRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockPasswordResetRepository) Consume(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, tokenHash)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockPasswordResetRepositoryMockRecorder) Consume(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockPasswordResetRepository)(nil).Consume), ctx, tokenHash)
+}
+
+// Create mocks base method.
+func (m *MockPasswordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPasswordResetRepositoryMockRecorder) Create(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPasswordResetRepository)(nil).Create), ctx, token)
+}
+
+// DeleteForUser mocks base method.
+func (m *MockPasswordResetRepository) DeleteForUser(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteForUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteForUser indicates an expected call of DeleteForUser.
+func (mr *MockPasswordResetRepositoryMockRecorder) DeleteForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteForUser", reflect.TypeOf((*MockPasswordResetRepository)(nil).DeleteForUser), ctx, userID)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/playback_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPlaybackRepository is a mock of PlaybackRepository interface.
+type MockPlaybackRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPlaybackRepositoryMockRecorder
+}
+
+// MockPlaybackRepositoryMockRecorder is the mock recorder for MockPlaybackRepository.
+type MockPlaybackRepositoryMockRecorder struct {
+	mock *MockPlaybackRepository
+}
+
+// NewMockPlaybackRepository creates a new mock instance.
+func NewMockPlaybackRepository(ctrl *gomock.Controller) *MockPlaybackRepository {
+	mock := &MockPlaybackRepository{ctrl: ctrl}
+	mock.recorder = &MockPlaybackRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPlaybackRepository) EXPECT() *MockPlaybackRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetQueue mocks base method.
+func (m *MockPlaybackRepository) GetQueue(ctx context.Context, userID uuid.UUID) ([]*models.Track, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueue", ctx, userID)
+	ret0, _ := ret[0].([]*models.Track)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueue indicates an expected call of GetQueue.
+func (mr *MockPlaybackRepositoryMockRecorder) GetQueue(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueue", reflect.TypeOf((*MockPlaybackRepository)(nil).GetQueue), ctx, userID)
+}
+
+// GetState mocks base method.
+func (m *MockPlaybackRepository) GetState(ctx context.Context, userID uuid.UUID) (*models.PlaybackState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetState", ctx, userID)
+	ret0, _ := ret[0].(*models.PlaybackState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetState indicates an expected call of GetState.
+func (mr *MockPlaybackRepositoryMockRecorder) GetState(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetState", reflect.TypeOf((*MockPlaybackRepository)(nil).GetState), ctx, userID)
+}
+
+// ReplaceQueue mocks base method.
+func (m *MockPlaybackRepository) ReplaceQueue(ctx context.Context, userID uuid.UUID, trackIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceQueue", ctx, userID, trackIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceQueue indicates an expected call of ReplaceQueue.
+func (mr *MockPlaybackRepositoryMockRecorder) ReplaceQueue(ctx, userID, trackIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceQueue", reflect.TypeOf((*MockPlaybackRepository)(nil).ReplaceQueue), ctx, userID, trackIDs)
+}
+
+// SaveState mocks base method.
+func (m *MockPlaybackRepository) SaveState(ctx context.Context, state *models.PlaybackState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveState", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveState indicates an expected call of SaveState.
+func (mr *MockPlaybackRepositoryMockRecorder) SaveState(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveState", reflect.TypeOf((*MockPlaybackRepository)(nil).SaveState), ctx, state)
+}
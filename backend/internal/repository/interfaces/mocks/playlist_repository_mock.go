@@ -1,12 +1,14 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: src/src/internal/repository/interfaces/playlist_repository.go
+// Source: internal/repository/interfaces/playlist_repository.go
 
 // Package mocks is a generated GoMock package.
 package mocks
 
 import (
+	context "context"
 	models "music-service/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -36,102 +38,116 @@ func (m *MockPlaylistRepository) EXPECT() *MockPlaylistRepositoryMockRecorder {
 }
 
 // AddTrack mocks base method.
-func (m *MockPlaylistRepository) AddTrack(playlistID, trackID uuid.UUID) error {
+func (m *MockPlaylistRepository) AddTrack(ctx context.Context, playlistID, trackID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddTrack", playlistID, trackID)
+	ret := m.ctrl.Call(m, "AddTrack", ctx, playlistID, trackID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddTrack indicates an expected call of AddTrack.
-func (mr *MockPlaylistRepositoryMockRecorder) AddTrack(playlistID, trackID interface{}) *gomock.Call {
+func (mr *MockPlaylistRepositoryMockRecorder) AddTrack(ctx, playlistID, trackID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTrack", reflect.TypeOf((*MockPlaylistRepository)(nil).AddTrack), playlistID, trackID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTrack", reflect.TypeOf((*MockPlaylistRepository)(nil).AddTrack), ctx, playlistID, trackID)
 }
 
 // Delete mocks base method.
-func (m *MockPlaylistRepository) Delete(id uuid.UUID) error {
+func (m *MockPlaylistRepository) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockPlaylistRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockPlaylistRepositoryMockRecorder) Delete(ctx, id, deletedAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPlaylistRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPlaylistRepository)(nil).Delete), ctx, id, deletedAt)
 }
 
 // FindByID mocks base method.
-func (m *MockPlaylistRepository) FindByID(id uuid.UUID) (*models.Playlist, error) {
+func (m *MockPlaylistRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", id)
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
 	ret0, _ := ret[0].(*models.Playlist)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByID indicates an expected call of FindByID.
-func (mr *MockPlaylistRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+func (mr *MockPlaylistRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockPlaylistRepository)(nil).FindByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockPlaylistRepository)(nil).FindByID), ctx, id)
 }
 
 // GetTracks mocks base method.
-func (m *MockPlaylistRepository) GetTracks(playlistID uuid.UUID) ([]*models.Track, error) {
+func (m *MockPlaylistRepository) GetTracks(ctx context.Context, playlistID uuid.UUID) ([]*models.Track, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTracks", playlistID)
+	ret := m.ctrl.Call(m, "GetTracks", ctx, playlistID)
 	ret0, _ := ret[0].([]*models.Track)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTracks indicates an expected call of GetTracks.
-func (mr *MockPlaylistRepositoryMockRecorder) GetTracks(playlistID interface{}) *gomock.Call {
+func (mr *MockPlaylistRepositoryMockRecorder) GetTracks(ctx, playlistID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracks", reflect.TypeOf((*MockPlaylistRepository)(nil).GetTracks), playlistID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracks", reflect.TypeOf((*MockPlaylistRepository)(nil).GetTracks), ctx, playlistID)
 }
 
 // GetUserPlaylists mocks base method.
-func (m *MockPlaylistRepository) GetUserPlaylists(userID uuid.UUID) ([]*models.Playlist, error) {
+func (m *MockPlaylistRepository) GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserPlaylists", userID)
+	ret := m.ctrl.Call(m, "GetUserPlaylists", ctx, userID)
 	ret0, _ := ret[0].([]*models.Playlist)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserPlaylists indicates an expected call of GetUserPlaylists.
-func (mr *MockPlaylistRepositoryMockRecorder) GetUserPlaylists(userID interface{}) *gomock.Call {
+func (mr *MockPlaylistRepositoryMockRecorder) GetUserPlaylists(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPlaylists", reflect.TypeOf((*MockPlaylistRepository)(nil).GetUserPlaylists), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPlaylists", reflect.TypeOf((*MockPlaylistRepository)(nil).GetUserPlaylists), ctx, userID)
 }
 
 // RemoveTrack mocks base method.
-func (m *MockPlaylistRepository) RemoveTrack(playlistID, trackID uuid.UUID) error {
+func (m *MockPlaylistRepository) RemoveTrack(ctx context.Context, playlistID, trackID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveTrack", playlistID, trackID)
+	ret := m.ctrl.Call(m, "RemoveTrack", ctx, playlistID, trackID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveTrack indicates an expected call of RemoveTrack.
-func (mr *MockPlaylistRepositoryMockRecorder) RemoveTrack(playlistID, trackID interface{}) *gomock.Call {
+func (mr *MockPlaylistRepositoryMockRecorder) RemoveTrack(ctx, playlistID, trackID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTrack", reflect.TypeOf((*MockPlaylistRepository)(nil).RemoveTrack), playlistID, trackID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTrack", reflect.TypeOf((*MockPlaylistRepository)(nil).RemoveTrack), ctx, playlistID, trackID)
+}
+
+// Restore mocks base method.
+func (m *MockPlaylistRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockPlaylistRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockPlaylistRepository)(nil).Restore), ctx, id)
 }
 
 // Save mocks base method.
-func (m *MockPlaylistRepository) Save(playlist *models.Playlist) error {
+func (m *MockPlaylistRepository) Save(ctx context.Context, playlist *models.Playlist) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", playlist)
+	ret := m.ctrl.Call(m, "Save", ctx, playlist)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockPlaylistRepositoryMockRecorder) Save(playlist interface{}) *gomock.Call {
+func (mr *MockPlaylistRepositoryMockRecorder) Save(ctx, playlist interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPlaylistRepository)(nil).Save), playlist)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPlaylistRepository)(nil).Save), ctx, playlist)
 }
@@ -1,12 +1,14 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: src/src/internal/repository/interfaces/session_repository.go
+// Source: internal/repository/interfaces/session_repository.go
 
 // Package mocks is a generated GoMock package.
 package mocks
 
 import (
+	context "context"
 	models "music-service/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -35,60 +37,193 @@ func (m *MockSessionRepository) EXPECT() *MockSessionRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CreateSession mocks base method.
-func (m *MockSessionRepository) CreateSession(userID uuid.UUID) (*models.Session, error) {
+// AddRefreshToken mocks base method.
+func (m *MockSessionRepository) AddRefreshToken(ctx context.Context, token *models.RefreshToken) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", userID)
-	ret0, _ := ret[0].(*models.Session)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "AddRefreshToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// CreateSession indicates an expected call of CreateSession.
-func (mr *MockSessionRepositoryMockRecorder) CreateSession(userID interface{}) *gomock.Call {
+// AddRefreshToken indicates an expected call of AddRefreshToken.
+func (mr *MockSessionRepositoryMockRecorder) AddRefreshToken(ctx, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionRepository)(nil).CreateSession), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRefreshToken", reflect.TypeOf((*MockSessionRepository)(nil).AddRefreshToken), ctx, token)
 }
 
-// DeleteAllForUser mocks base method.
-func (m *MockSessionRepository) DeleteAllForUser(userID uuid.UUID) error {
+// CreateSession mocks base method.
+func (m *MockSessionRepository) CreateSession(ctx context.Context, session *models.Session) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAllForUser", userID)
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteAllForUser indicates an expected call of DeleteAllForUser.
-func (mr *MockSessionRepositoryMockRecorder) DeleteAllForUser(userID interface{}) *gomock.Call {
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockSessionRepositoryMockRecorder) CreateSession(ctx, session interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllForUser", reflect.TypeOf((*MockSessionRepository)(nil).DeleteAllForUser), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionRepository)(nil).CreateSession), ctx, session)
 }
 
-// DeleteSession mocks base method.
-func (m *MockSessionRepository) DeleteSession(sessionID string) error {
+// GetRefreshToken mocks base method.
+func (m *MockSessionRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSession", sessionID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteSession indicates an expected call of DeleteSession.
-func (mr *MockSessionRepositoryMockRecorder) DeleteSession(sessionID interface{}) *gomock.Call {
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockSessionRepositoryMockRecorder) GetRefreshToken(ctx, tokenHash interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockSessionRepository)(nil).DeleteSession), sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockSessionRepository)(nil).GetRefreshToken), ctx, tokenHash)
 }
 
 // GetSession mocks base method.
-func (m *MockSessionRepository) GetSession(sessionID string) (*models.Session, error) {
+func (m *MockSessionRepository) GetSession(ctx context.Context, sessionID uuid.UUID) (*models.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", sessionID)
+	ret := m.ctrl.Call(m, "GetSession", ctx, sessionID)
 	ret0, _ := ret[0].(*models.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSession indicates an expected call of GetSession.
-func (mr *MockSessionRepositoryMockRecorder) GetSession(sessionID interface{}) *gomock.Call {
+func (mr *MockSessionRepositoryMockRecorder) GetSession(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockSessionRepository)(nil).GetSession), ctx, sessionID)
+}
+
+// ListActiveSessions mocks base method.
+func (m *MockSessionRepository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveSessions", ctx, userID)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveSessions indicates an expected call of ListActiveSessions.
+func (mr *MockSessionRepositoryMockRecorder) ListActiveSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveSessions", reflect.TypeOf((*MockSessionRepository)(nil).ListActiveSessions), ctx, userID)
+}
+
+// ListDevices mocks base method.
+func (m *MockSessionRepository) ListDevices(ctx context.Context, userID uuid.UUID) ([]*models.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDevices", ctx, userID)
+	ret0, _ := ret[0].([]*models.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDevices indicates an expected call of ListDevices.
+func (mr *MockSessionRepositoryMockRecorder) ListDevices(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDevices", reflect.TypeOf((*MockSessionRepository)(nil).ListDevices), ctx, userID)
+}
+
+// ListRevokedSessions mocks base method.
+func (m *MockSessionRepository) ListRevokedSessions(ctx context.Context) ([]models.RevokedSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevokedSessions", ctx)
+	ret0, _ := ret[0].([]models.RevokedSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevokedSessions indicates an expected call of ListRevokedSessions.
+func (mr *MockSessionRepositoryMockRecorder) ListRevokedSessions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevokedSessions", reflect.TypeOf((*MockSessionRepository)(nil).ListRevokedSessions), ctx)
+}
+
+// MarkRefreshTokenUsed mocks base method.
+func (m *MockSessionRepository) MarkRefreshTokenUsed(ctx context.Context, tokenHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRefreshTokenUsed", ctx, tokenHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRefreshTokenUsed indicates an expected call of MarkRefreshTokenUsed.
+func (mr *MockSessionRepositoryMockRecorder) MarkRefreshTokenUsed(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRefreshTokenUsed", reflect.TypeOf((*MockSessionRepository)(nil).MarkRefreshTokenUsed), ctx, tokenHash)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockSessionRepository) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockSessionRepositoryMockRecorder) PurgeExpired(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockSessionRepository)(nil).PurgeExpired), ctx, before)
+}
+
+// RevokeAllForUser mocks base method.
+func (m *MockSessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID, accessExpiresAt time.Time) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllForUser", ctx, userID, accessExpiresAt)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAllForUser indicates an expected call of RevokeAllForUser.
+func (mr *MockSessionRepositoryMockRecorder) RevokeAllForUser(ctx, userID, accessExpiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllForUser", reflect.TypeOf((*MockSessionRepository)(nil).RevokeAllForUser), ctx, userID, accessExpiresAt)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockSessionRepository) RevokeOtherSessions(ctx context.Context, userID, keepSessionID uuid.UUID, accessExpiresAt time.Time) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", ctx, userID, keepSessionID, accessExpiresAt)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockSessionRepositoryMockRecorder) RevokeOtherSessions(ctx, userID, keepSessionID, accessExpiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockSessionRepository)(nil).RevokeOtherSessions), ctx, userID, keepSessionID, accessExpiresAt)
+}
+
+// RevokeSession mocks base method.
+func (m *MockSessionRepository) RevokeSession(ctx context.Context, sessionID uuid.UUID, accessExpiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, sessionID, accessExpiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockSessionRepositoryMockRecorder) RevokeSession(ctx, sessionID, accessExpiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockSessionRepository)(nil).RevokeSession), ctx, sessionID, accessExpiresAt)
+}
+
+// TouchSession mocks base method.
+func (m *MockSessionRepository) TouchSession(ctx context.Context, sessionID uuid.UUID, ipAddress string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchSession", ctx, sessionID, ipAddress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchSession indicates an expected call of TouchSession.
+func (mr *MockSessionRepositoryMockRecorder) TouchSession(ctx, sessionID, ipAddress interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockSessionRepository)(nil).GetSession), sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchSession", reflect.TypeOf((*MockSessionRepository)(nil).TouchSession), ctx, sessionID, ipAddress)
 }
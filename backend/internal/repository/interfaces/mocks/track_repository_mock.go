@@ -1,12 +1,15 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: src/src/internal/repository/interfaces/track_repository.go
+// Source: internal/repository/interfaces/track_repository.go
 
 // Package mocks is a generated GoMock package.
 package mocks
 
 import (
+	context "context"
+	io "io"
 	models "music-service/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -36,73 +39,187 @@ func (m *MockTrackRepository) EXPECT() *MockTrackRepositoryMockRecorder {
 }
 
 // Delete mocks base method.
-func (m *MockTrackRepository) Delete(id uuid.UUID) error {
+func (m *MockTrackRepository) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockTrackRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockTrackRepositoryMockRecorder) Delete(ctx, id, deletedAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTrackRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTrackRepository)(nil).Delete), ctx, id, deletedAt)
+}
+
+// DeleteTrackFile mocks base method.
+func (m *MockTrackRepository) DeleteTrackFile(ctx context.Context, filePath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTrackFile", ctx, filePath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTrackFile indicates an expected call of DeleteTrackFile.
+func (mr *MockTrackRepositoryMockRecorder) DeleteTrackFile(ctx, filePath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrackFile", reflect.TypeOf((*MockTrackRepository)(nil).DeleteTrackFile), ctx, filePath)
 }
 
 // FindByID mocks base method.
-func (m *MockTrackRepository) FindByID(id uuid.UUID) (*models.Track, error) {
+func (m *MockTrackRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Track, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", id)
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
 	ret0, _ := ret[0].(*models.Track)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByID indicates an expected call of FindByID.
-func (mr *MockTrackRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+func (mr *MockTrackRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockTrackRepository)(nil).FindByID), ctx, id)
+}
+
+// GetGenresForTrack mocks base method.
+func (m *MockTrackRepository) GetGenresForTrack(ctx context.Context, trackID uuid.UUID) ([]*models.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenresForTrack", ctx, trackID)
+	ret0, _ := ret[0].([]*models.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenresForTrack indicates an expected call of GetGenresForTrack.
+func (mr *MockTrackRepositoryMockRecorder) GetGenresForTrack(ctx, trackID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockTrackRepository)(nil).FindByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenresForTrack", reflect.TypeOf((*MockTrackRepository)(nil).GetGenresForTrack), ctx, trackID)
+}
+
+// GetStorageDir mocks base method.
+func (m *MockTrackRepository) GetStorageDir() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStorageDir")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetStorageDir indicates an expected call of GetStorageDir.
+func (mr *MockTrackRepositoryMockRecorder) GetStorageDir() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStorageDir", reflect.TypeOf((*MockTrackRepository)(nil).GetStorageDir))
 }
 
 // IncrementPlayCount mocks base method.
-func (m *MockTrackRepository) IncrementPlayCount(trackID uuid.UUID) error {
+func (m *MockTrackRepository) IncrementPlayCount(ctx context.Context, trackID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IncrementPlayCount", trackID)
+	ret := m.ctrl.Call(m, "IncrementPlayCount", ctx, trackID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // IncrementPlayCount indicates an expected call of IncrementPlayCount.
-func (mr *MockTrackRepositoryMockRecorder) IncrementPlayCount(trackID interface{}) *gomock.Call {
+func (mr *MockTrackRepositoryMockRecorder) IncrementPlayCount(ctx, trackID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementPlayCount", reflect.TypeOf((*MockTrackRepository)(nil).IncrementPlayCount), ctx, trackID)
+}
+
+// Merge mocks base method.
+func (m *MockTrackRepository) Merge(ctx context.Context, targetID, sourceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, targetID, sourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockTrackRepositoryMockRecorder) Merge(ctx, targetID, sourceID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementPlayCount", reflect.TypeOf((*MockTrackRepository)(nil).IncrementPlayCount), trackID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockTrackRepository)(nil).Merge), ctx, targetID, sourceID)
+}
+
+// Restore mocks base method.
+func (m *MockTrackRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTrackRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTrackRepository)(nil).Restore), ctx, id)
 }
 
 // Save mocks base method.
-func (m *MockTrackRepository) Save(track *models.Track) error {
+func (m *MockTrackRepository) Save(ctx context.Context, track *models.Track) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", track)
+	ret := m.ctrl.Call(m, "Save", ctx, track)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockTrackRepositoryMockRecorder) Save(track interface{}) *gomock.Call {
+func (mr *MockTrackRepositoryMockRecorder) Save(ctx, track interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTrackRepository)(nil).Save), ctx, track)
+}
+
+// SaveTrackFile mocks base method.
+func (m *MockTrackRepository) SaveTrackFile(ctx context.Context, trackID uuid.UUID, fileReader io.Reader, fileSize int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTrackFile", ctx, trackID, fileReader, fileSize)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveTrackFile indicates an expected call of SaveTrackFile.
+func (mr *MockTrackRepositoryMockRecorder) SaveTrackFile(ctx, trackID, fileReader, fileSize interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTrackRepository)(nil).Save), track)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTrackFile", reflect.TypeOf((*MockTrackRepository)(nil).SaveTrackFile), ctx, trackID, fileReader, fileSize)
 }
 
 // Search mocks base method.
-func (m *MockTrackRepository) Search(query string) ([]*models.Track, error) {
+func (m *MockTrackRepository) Search(ctx context.Context, query string) ([]*models.Track, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", query)
+	ret := m.ctrl.Call(m, "Search", ctx, query)
 	ret0, _ := ret[0].([]*models.Track)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockTrackRepositoryMockRecorder) Search(query interface{}) *gomock.Call {
+func (mr *MockTrackRepositoryMockRecorder) Search(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockTrackRepository)(nil).Search), ctx, query)
+}
+
+// SetLoudness mocks base method.
+func (m *MockTrackRepository) SetLoudness(ctx context.Context, id uuid.UUID, loudness *models.Loudness, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLoudness", ctx, id, loudness, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLoudness indicates an expected call of SetLoudness.
+func (mr *MockTrackRepositoryMockRecorder) SetLoudness(ctx, id, loudness, updatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLoudness", reflect.TypeOf((*MockTrackRepository)(nil).SetLoudness), ctx, id, loudness, updatedAt)
+}
+
+// Update mocks base method.
+func (m *MockTrackRepository) Update(ctx context.Context, track *models.Track, version time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, track, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTrackRepositoryMockRecorder) Update(ctx, track, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockTrackRepository)(nil).Search), query)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTrackRepository)(nil).Update), ctx, track, version)
 }
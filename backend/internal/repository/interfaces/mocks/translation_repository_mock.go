@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/translation_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockTranslationRepository is a mock of TranslationRepository interface.
+type MockTranslationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTranslationRepositoryMockRecorder
+}
+
+// MockTranslationRepositoryMockRecorder is the mock recorder for MockTranslationRepository.
+type MockTranslationRepositoryMockRecorder struct {
+	mock *MockTranslationRepository
+}
+
+// NewMockTranslationRepository creates a new mock instance.
+func NewMockTranslationRepository(ctrl *gomock.Controller) *MockTranslationRepository {
+	mock := &MockTranslationRepository{ctrl: ctrl}
+	mock.recorder = &MockTranslationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTranslationRepository) EXPECT() *MockTranslationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTranslationRepository) Delete(ctx context.Context, entityType string, id uuid.UUID, locale string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, entityType, id, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTranslationRepositoryMockRecorder) Delete(ctx, entityType, id, locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTranslationRepository)(nil).Delete), ctx, entityType, id, locale)
+}
+
+// FindByEntities mocks base method.
+func (m *MockTranslationRepository) FindByEntities(ctx context.Context, entityType string, ids []uuid.UUID, locales []string) (map[uuid.UUID][]*models.Translation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByEntities", ctx, entityType, ids, locales)
+	ret0, _ := ret[0].(map[uuid.UUID][]*models.Translation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByEntities indicates an expected call of FindByEntities.
+func (mr *MockTranslationRepositoryMockRecorder) FindByEntities(ctx, entityType, ids, locales interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEntities", reflect.TypeOf((*MockTranslationRepository)(nil).FindByEntities), ctx, entityType, ids, locales)
+}
+
+// List mocks base method.
+func (m *MockTranslationRepository) List(ctx context.Context, entityType string, id uuid.UUID) ([]*models.Translation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, entityType, id)
+	ret0, _ := ret[0].([]*models.Translation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTranslationRepositoryMockRecorder) List(ctx, entityType, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTranslationRepository)(nil).List), ctx, entityType, id)
+}
+
+// Save mocks base method.
+func (m *MockTranslationRepository) Save(ctx context.Context, entityType string, id uuid.UUID, translation *models.Translation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, entityType, id, translation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockTranslationRepositoryMockRecorder) Save(ctx, entityType, id, translation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTranslationRepository)(nil).Save), ctx, entityType, id, translation)
+}
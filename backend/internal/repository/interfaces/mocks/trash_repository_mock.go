@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/trash_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "music-service/internal/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockTrashRepository is a mock of TrashRepository interface.
+type MockTrashRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashRepositoryMockRecorder
+}
+
+// MockTrashRepositoryMockRecorder is the mock recorder for MockTrashRepository.
+type MockTrashRepositoryMockRecorder struct {
+	mock *MockTrashRepository
+}
+
+// NewMockTrashRepository creates a new mock instance.
+func NewMockTrashRepository(ctrl *gomock.Controller) *MockTrashRepository {
+	mock := &MockTrashRepository{ctrl: ctrl}
+	mock.recorder = &MockTrashRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrashRepository) EXPECT() *MockTrashRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method.
+func (m *MockTrashRepository) Find(ctx context.Context, itemType string, id uuid.UUID) (*models.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, itemType, id)
+	ret0, _ := ret[0].(*models.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockTrashRepositoryMockRecorder) Find(ctx, itemType, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockTrashRepository)(nil).Find), ctx, itemType, id)
+}
+
+// List mocks base method.
+func (m *MockTrashRepository) List(ctx context.Context, filter models.TrashFilter) (*models.TrashPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].(*models.TrashPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTrashRepositoryMockRecorder) List(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTrashRepository)(nil).List), ctx, filter)
+}
+
+// Purge mocks base method.
+func (m *MockTrashRepository) Purge(ctx context.Context, itemType string, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, itemType, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashRepositoryMockRecorder) Purge(ctx, itemType, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrashRepository)(nil).Purge), ctx, itemType, before)
+}
+
+// PurgeTracks mocks base method.
+func (m *MockTrashRepository) PurgeTracks(ctx context.Context, before time.Time) ([]*models.Track, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTracks", ctx, before)
+	ret0, _ := ret[0].([]*models.Track)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTracks indicates an expected call of PurgeTracks.
+func (mr *MockTrashRepositoryMockRecorder) PurgeTracks(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTracks", reflect.TypeOf((*MockTrashRepository)(nil).PurgeTracks), ctx, before)
+}
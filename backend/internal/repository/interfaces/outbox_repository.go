@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxRepository interface {
+	Add(ctx context.Context, event *models.DomainEvent) error
+	// ClaimPending захватывает до limit готовых к доставке событий на время lease,
+	// чтобы их не обработал параллельно другой экземпляр сервиса
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*models.DomainEvent, error)
+	MarkProcessed(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error
+	MarkDead(ctx context.Context, id uuid.UUID, lastError string) error
+	// MarkHandled отмечает, что обработчик handler обработал событие.
+	// Возвращает false, если отметка уже есть — событие доставлено повторно
+	MarkHandled(ctx context.Context, id uuid.UUID, handler string) (bool, error)
+	DeleteProcessed(ctx context.Context, before time.Time) (int64, error)
+}
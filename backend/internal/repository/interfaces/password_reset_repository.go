@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	Consume(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	DeleteForUser(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PlaybackRepository interface {
+	GetState(ctx context.Context, userID uuid.UUID) (*models.PlaybackState, error)
+	SaveState(ctx context.Context, state *models.PlaybackState) error
+	GetQueue(ctx context.Context, userID uuid.UUID) ([]*models.Track, error)
+	ReplaceQueue(ctx context.Context, userID uuid.UUID, trackIDs []uuid.UUID) error
+}
@@ -1,17 +1,20 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type PlaylistRepository interface {
-	FindByID(id uuid.UUID) (*models.Playlist, error)
-	Save(playlist *models.Playlist) error
-	Delete(id uuid.UUID) error
-	AddTrack(playlistID uuid.UUID, trackID uuid.UUID) error
-	RemoveTrack(playlistID uuid.UUID, trackID uuid.UUID) error
-	GetTracks(playlistID uuid.UUID) ([]*models.Track, error)
-	GetUserPlaylists(userID uuid.UUID) ([]*models.Playlist, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error)
+	Save(ctx context.Context, playlist *models.Playlist) error
+	Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	AddTrack(ctx context.Context, playlistID uuid.UUID, trackID uuid.UUID) error
+	RemoveTrack(ctx context.Context, playlistID uuid.UUID, trackID uuid.UUID) error
+	GetTracks(ctx context.Context, playlistID uuid.UUID) ([]*models.Track, error)
+	GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error)
 }
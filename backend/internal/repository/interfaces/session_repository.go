@@ -1,15 +1,27 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type SessionRepository interface {
-	CreateSession(userID uuid.UUID, token string) (*models.Session, error)
-	GetSession(sessionID string) (*models.Session, error)
-	DeleteSession(sessionID string) error
-	DeleteAllForUser(userID uuid.UUID) error
-	GetSessionByToken(token string) (*models.Session, *models.User, error)
+	CreateSession(ctx context.Context, session *models.Session) error
+	GetSession(ctx context.Context, sessionID uuid.UUID) (*models.Session, error)
+	ListDevices(ctx context.Context, userID uuid.UUID) ([]*models.Device, error)
+	ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
+	TouchSession(ctx context.Context, sessionID uuid.UUID, ipAddress string) error
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+
+	AddRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, tokenHash string) (bool, error)
+
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, accessExpiresAt time.Time) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID, accessExpiresAt time.Time) ([]uuid.UUID, error)
+	RevokeOtherSessions(ctx context.Context, userID, keepSessionID uuid.UUID, accessExpiresAt time.Time) ([]uuid.UUID, error)
+	ListRevokedSessions(ctx context.Context) ([]models.RevokedSession, error)
 }
@@ -1,19 +1,26 @@
 package interfaces
 
 import (
+	"context"
 	"io"
 	"music-service/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type TrackRepository interface {
-	FindByID(id uuid.UUID) (*models.Track, error)
-	Save(track *models.Track) error
-	Delete(id uuid.UUID) error
-	Search(query string) ([]*models.Track, error)
-	IncrementPlayCount(trackID uuid.UUID) error
-	SaveTrackFile(trackID uuid.UUID, fileReader io.Reader, fileSize int64) (string, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Track, error)
+	Save(ctx context.Context, track *models.Track) error
+	Update(ctx context.Context, track *models.Track, version time.Time) error
+	Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	Search(ctx context.Context, query string) ([]*models.Track, error)
+	IncrementPlayCount(ctx context.Context, trackID uuid.UUID) error
+	SaveTrackFile(ctx context.Context, trackID uuid.UUID, fileReader io.Reader, fileSize int64) (string, error)
+	DeleteTrackFile(ctx context.Context, filePath string) error
 	GetStorageDir() string
-	GetGenresForTrack(trackID uuid.UUID) ([]*models.Genre, error)
+	GetGenresForTrack(ctx context.Context, trackID uuid.UUID) ([]*models.Genre, error)
+	SetLoudness(ctx context.Context, id uuid.UUID, loudness *models.Loudness, updatedAt time.Time) error
+	Merge(ctx context.Context, targetID, sourceID uuid.UUID) error
 }
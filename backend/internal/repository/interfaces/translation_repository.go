@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// TranslationRepository — переводы полей треков, альбомов и жанров.
+// entityType — одна из констант models.Translatable*
+type TranslationRepository interface {
+	// List возвращает все переводы объекта, упорядоченные по языку
+	List(ctx context.Context, entityType string, id uuid.UUID) ([]*models.Translation, error)
+	// FindByEntities возвращает переводы объектов ids на языки locales,
+	// сгруппированные по ID объекта
+	FindByEntities(ctx context.Context, entityType string, ids []uuid.UUID, locales []string) (map[uuid.UUID][]*models.Translation, error)
+	// Save заменяет перевод объекта на язык translation.Locale
+	Save(ctx context.Context, entityType string, id uuid.UUID, translation *models.Translation) error
+	// Delete удаляет перевод на язык locale или возвращает models.ErrNotFound
+	Delete(ctx context.Context, entityType string, id uuid.UUID, locale string) error
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrashRepository — корзина: удаленные треки, альбомы, плейлисты и
+// пользователи. Объекты помещаются в корзину и восстанавливаются
+// репозиториями соответствующих сущностей, окончательно удаляются здесь
+type TrashRepository interface {
+	List(ctx context.Context, filter models.TrashFilter) (*models.TrashPage, error)
+	// Find возвращает объект из корзины или models.ErrNotFound
+	Find(ctx context.Context, itemType string, id uuid.UUID) (*models.TrashItem, error)
+	// PurgeTracks окончательно удаляет треки, удаленные до before, и
+	// возвращает их, чтобы можно было удалить файлы
+	PurgeTracks(ctx context.Context, before time.Time) ([]*models.Track, error)
+	// Purge окончательно удаляет альбомы, плейлисты или пользователей,
+	// удаленных до before
+	Purge(ctx context.Context, itemType string, before time.Time) (int64, error)
+}
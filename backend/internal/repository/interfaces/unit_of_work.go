@@ -0,0 +1,34 @@
+package interfaces
+
+import "context"
+
+// TxRepositories — репозитории, выполняющие запросы в рамках одной транзакции
+type TxRepositories struct {
+	User          UserRepository
+	Track         TrackRepository
+	Album         AlbumRepository
+	Playlist      PlaylistRepository
+	Genre         GenreRepository
+	Session       SessionRepository
+	History       HistoryRepository
+	Playback      PlaybackRepository
+	Follow        FollowRepository
+	Outbox        OutboxRepository
+	PasswordReset PasswordResetRepository
+	Identity      IdentityRepository
+	MFA           MFARepository
+	Audit         AuditRepository
+	Trash         TrashRepository
+	Translation   TranslationRepository
+	Cover         CoverRepository
+	Lyrics        LyricsRepository
+	Fingerprint   FingerprintRepository
+}
+
+// UnitOfWork выполняет fn в транзакции: если fn возвращает ошибку или
+// паникует, все изменения откатываются, иначе фиксируются. Побочные эффекты
+// вне базы данных (файлы, уведомления) внутри fn не откатываются — их нужно
+// выполнять после WithTx или компенсировать при ошибке
+type UnitOfWork interface {
+	WithTx(ctx context.Context, fn func(repos *TxRepositories) error) error
+}
@@ -1,15 +1,26 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type UserRepository interface {
-	FindByID(id uuid.UUID) (*models.User, error)
-	FindByLogin(login string) (*models.User, error)
-	Save(user *models.User) error
-	Delete(id uuid.UUID) error
-	Search(query string) ([]*models.User, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	FindByLogin(ctx context.Context, login string) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	Save(ctx context.Context, user *models.User) error
+	IsLoginTaken(ctx context.Context, login string) (bool, error)
+	IsEmailTaken(ctx context.Context, email string) (bool, error)
+	Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	Search(ctx context.Context, query string) ([]*models.User, error)
+	CountByPermission(ctx context.Context, permission models.Permission) (int, error)
+	List(ctx context.Context, filter models.UserFilter) (*models.UserPage, error)
+	Suspend(ctx context.Context, userID uuid.UUID, suspension *models.Suspension) error
+	Unsuspend(ctx context.Context, userID uuid.UUID) error
+	SetLocale(ctx context.Context, userID uuid.UUID, locale string) error
 }
@@ -1,15 +1,17 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type AlbumRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewAlbumRepository(db *sql.DB) interfaces.AlbumRepository {
@@ -18,10 +20,13 @@ func NewAlbumRepository(db *sql.DB) interfaces.AlbumRepository {
 	}
 }
 
-func (r *AlbumRepository) FindByID(id uuid.UUID) (*models.Album, error) {
+// FindByID возвращает models.ErrNotFound, если альбома нет
+func (r *AlbumRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Album, error) {
 	var album models.Album
-	query := `SELECT id, title, artist, release_date, cover_url, created_at, updated_at FROM albums WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(
+	var integrated, truePeak sql.NullFloat64
+	query := `SELECT id, title, artist, release_date, cover_url, created_at, updated_at, integrated_lufs, true_peak_dbtp
+		FROM albums WHERE id = $1 AND deleted_at IS NULL`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&album.ID,
 		&album.Title,
 		&album.Artist,
@@ -29,21 +34,27 @@ func (r *AlbumRepository) FindByID(id uuid.UUID) (*models.Album, error) {
 		&album.CoverURL,
 		&album.CreatedAt,
 		&album.UpdatedAt,
+		&integrated,
+		&truePeak,
 	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
+	album.Loudness = loudness(integrated, truePeak)
 	return &album, nil
 }
 
-func (r *AlbumRepository) Save(album *models.Album) error {
+func (r *AlbumRepository) Save(ctx context.Context, album *models.Album) error {
 	query := `
 		INSERT INTO albums (id, title, artist, release_date, cover_url, created_at, updated_at) 
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT (id) DO UPDATE 
 		SET title = $2, artist = $3, release_date = $4, cover_url = $5, updated_at = $7
 	`
-	_, err := r.db.Exec(query,
+	_, err := r.db.ExecContext(ctx, query,
 		album.ID,
 		album.Title,
 		album.Artist,
@@ -55,18 +66,49 @@ func (r *AlbumRepository) Save(album *models.Album) error {
 	return err
 }
 
-func (r *AlbumRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM albums WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
+// Delete помещает альбом и его треки в корзину с отметкой deletedAt. Если
+// альбома нет, возвращается models.ErrNotFound
+func (r *AlbumRepository) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		_, err := tx.ExecContext(ctx, `UPDATE tracks SET deleted_at = $2 WHERE album_id = $1 AND deleted_at IS NULL`, id, deletedAt)
+		if err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, `UPDATE albums SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`, id, deletedAt)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result)
+	})
 }
 
-func (r *AlbumRepository) GetTracks(albumID uuid.UUID) ([]*models.Track, error) {
+// Restore возвращает альбом из корзины вместе с треками, удаленными
+// одновременно с ним. Треки, удаленные раньше, остаются в корзине. Если
+// альбома в корзине нет, возвращается models.ErrNotFound
+func (r *AlbumRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE tracks SET deleted_at = NULL
+			WHERE album_id = $1 AND deleted_at = (SELECT deleted_at FROM albums WHERE id = $1)
+		`, id)
+		if err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, `UPDATE albums SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result)
+	})
+}
+
+func (r *AlbumRepository) GetTracks(ctx context.Context, albumID uuid.UUID) ([]*models.Track, error) {
 	var tracks []*models.Track
-	query := `SELECT id, title, duration, file_path, album_id, artist_name, cover_url, added_date, updated_at, play_count 
-				FROM tracks WHERE album_id = $1`
+	query := `SELECT id, title, duration, file_path, album_id, artist_name, cover_url, added_date, updated_at, play_count,
+				integrated_lufs, true_peak_dbtp
+				FROM tracks WHERE album_id = $1 AND deleted_at IS NULL`
 
-	rows, err := r.db.Query(query, albumID)
+	rows, err := r.db.QueryContext(ctx, query, albumID)
 	if err != nil {
 		return nil, err
 	}
@@ -74,6 +116,7 @@ func (r *AlbumRepository) GetTracks(albumID uuid.UUID) ([]*models.Track, error)
 
 	for rows.Next() {
 		var track models.Track
+		var integrated, truePeak sql.NullFloat64
 		err := rows.Scan(
 			&track.ID,
 			&track.Title,
@@ -85,10 +128,13 @@ func (r *AlbumRepository) GetTracks(albumID uuid.UUID) ([]*models.Track, error)
 			&track.AddedDate,
 			&track.UpdatedAt,
 			&track.PlayCount,
+			&integrated,
+			&truePeak,
 		)
 		if err != nil {
 			return nil, err
 		}
+		track.Loudness = loudness(integrated, truePeak)
 		tracks = append(tracks, &track)
 	}
 
@@ -99,23 +145,24 @@ func (r *AlbumRepository) GetTracks(albumID uuid.UUID) ([]*models.Track, error)
 	return tracks, nil
 }
 
-func (r *AlbumRepository) AddTrackToAlbum(albumID, trackID uuid.UUID) error {
+func (r *AlbumRepository) AddTrackToAlbum(ctx context.Context, albumID, trackID uuid.UUID) error {
 	query := `UPDATE tracks SET album_id = $1 WHERE id = $2`
-	_, err := r.db.Exec(query, albumID, trackID)
+	_, err := r.db.ExecContext(ctx, query, albumID, trackID)
 	return err
 }
 
-func (r *AlbumRepository) RemoveTrackFromAlbum(albumID, trackID uuid.UUID) error {
+func (r *AlbumRepository) RemoveTrackFromAlbum(ctx context.Context, albumID, trackID uuid.UUID) error {
 	query := `UPDATE tracks SET album_id = NULL WHERE id = $2 AND album_id = $1`
-	_, err := r.db.Exec(query, albumID, trackID)
+	_, err := r.db.ExecContext(ctx, query, albumID, trackID)
 	return err
 }
 
-func (r *AlbumRepository) ListAll() ([]*models.Album, error) {
+func (r *AlbumRepository) ListAll(ctx context.Context) ([]*models.Album, error) {
 	var albums []*models.Album
-	query := `SELECT id, title, artist, release_date, cover_url, created_at, updated_at FROM albums`
+	query := `SELECT id, title, artist, release_date, cover_url, created_at, updated_at, integrated_lufs, true_peak_dbtp
+		FROM albums WHERE deleted_at IS NULL`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -123,6 +170,7 @@ func (r *AlbumRepository) ListAll() ([]*models.Album, error) {
 
 	for rows.Next() {
 		var album models.Album
+		var integrated, truePeak sql.NullFloat64
 		err := rows.Scan(
 			&album.ID,
 			&album.Title,
@@ -131,10 +179,13 @@ func (r *AlbumRepository) ListAll() ([]*models.Album, error) {
 			&album.CoverURL,
 			&album.CreatedAt,
 			&album.UpdatedAt,
+			&integrated,
+			&truePeak,
 		)
 		if err != nil {
 			return nil, err
 		}
+		album.Loudness = loudness(integrated, truePeak)
 		albums = append(albums, &album)
 	}
 
@@ -144,3 +195,22 @@ func (r *AlbumRepository) ListAll() ([]*models.Album, error) {
 
 	return albums, nil
 }
+
+// RefreshLoudness пересчитывает громкость альбома по измеренным трекам в
+// нем. Громкость альбома — средняя мощность треков, взвешенная по
+// длительности; пик — наибольший из пиков треков. Если измеренных треков
+// нет, громкость стирается
+func (r *AlbumRepository) RefreshLoudness(ctx context.Context, albumID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE albums
+		SET integrated_lufs = stats.integrated_lufs, true_peak_dbtp = stats.true_peak_dbtp
+		FROM (
+			SELECT 10 * log(SUM(GREATEST(duration, 1) * power(10, integrated_lufs / 10)) / SUM(GREATEST(duration, 1))) AS integrated_lufs,
+				MAX(true_peak_dbtp) AS true_peak_dbtp
+			FROM tracks
+			WHERE album_id = $1 AND deleted_at IS NULL AND integrated_lufs IS NOT NULL
+		) stats
+		WHERE albums.id = $1
+	`, albumID)
+	return err
+}
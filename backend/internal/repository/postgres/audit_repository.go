@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditRepository struct {
+	db DBTX
+}
+
+func NewAuditRepository(db *sql.DB) interfaces.AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (r *AuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	details := entry.Details
+	if len(details) == 0 {
+		details = []byte("{}")
+	}
+	changes := []byte("{}")
+	if len(entry.Changes) > 0 {
+		var err error
+		if changes, err = json.Marshal(entry.Changes); err != nil {
+			return err
+		}
+	}
+	query := `
+		INSERT INTO audit_log (id, actor_id, actor_login, action, entity_type, entity_id, changes, details, ip, request_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		entry.ID,
+		entry.ActorID,
+		entry.ActorLogin,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		string(changes),
+		string(details),
+		entry.IP,
+		entry.RequestID,
+		entry.CreatedAt,
+	)
+	return err
+}
+
+// List возвращает страницу журнала по фильтру, новые записи — первыми
+func (r *AuditRepository) List(ctx context.Context, filter models.AuditFilter) (*models.AuditPage, error) {
+	var conditions []string
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.ActorID != nil {
+		conditions = append(conditions, "actor_id = "+addArg(*filter.ActorID))
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = "+addArg(filter.Action))
+	}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = "+addArg(filter.EntityType))
+	}
+	if filter.EntityID != "" {
+		conditions = append(conditions, "entity_id = "+addArg(filter.EntityID))
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= "+addArg(*filter.From))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < "+addArg(*filter.To))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	page := &models.AuditPage{Limit: filter.Limit, Offset: filter.Offset}
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_log`+where, args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, actor_id, actor_login, action, entity_type, entity_id, changes, details, ip, request_id, created_at
+		FROM audit_log` + where + `
+		ORDER BY created_at DESC, id
+		LIMIT ` + addArg(filter.Limit) + ` OFFSET ` + addArg(filter.Offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.AuditEntry
+		var actorID uuid.NullUUID
+		var changes, details []byte
+		err := rows.Scan(
+			&entry.ID,
+			&actorID,
+			&entry.ActorLogin,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&changes,
+			&details,
+			&entry.IP,
+			&entry.RequestID,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode audit entry %s changes: %w", entry.ID, err)
+		}
+		if len(entry.Changes) == 0 {
+			entry.Changes = nil
+		}
+		if actorID.Valid {
+			id := actorID.UUID
+			entry.ActorID = &id
+		}
+		entry.Details = details
+		page.Entries = append(page.Entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// DeleteBefore удаляет записи старше before (срок хранения журнала) и
+// возвращает их количество
+func (r *AuditRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM audit_log WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// coverTables — таблицы объектов, у которых есть поле cover_url
+var coverTables = map[string]string{
+	models.CoverTrack:    "tracks",
+	models.CoverAlbum:    "albums",
+	models.CoverPlaylist: "playlists",
+}
+
+type CoverRepository struct {
+	db        DBTX
+	coversDir string
+}
+
+func NewCoverRepository(db *sql.DB, coversDir string) interfaces.CoverRepository {
+	return &CoverRepository{
+		db:        db,
+		coversDir: coversDir,
+	}
+}
+
+func (r *CoverRepository) Create(ctx context.Context, cover *models.Cover) error {
+	var uploadedBy interface{}
+	if cover.UploadedBy != uuid.Nil {
+		uploadedBy = cover.UploadedBy
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO covers (id, format, width, height, size_bytes, uploaded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, cover.ID, cover.Format, cover.Width, cover.Height, cover.SizeBytes, uploadedBy, cover.CreatedAt)
+	return err
+}
+
+// FindByID возвращает models.ErrNotFound, если обложки нет
+func (r *CoverRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Cover, error) {
+	var cover models.Cover
+	var uploadedBy uuid.NullUUID
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, format, width, height, size_bytes, uploaded_by, created_at FROM covers WHERE id = $1
+	`, id).Scan(&cover.ID, &cover.Format, &cover.Width, &cover.Height, &cover.SizeBytes, &uploadedBy, &cover.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	cover.UploadedBy = uploadedBy.UUID
+	return &cover, nil
+}
+
+// SaveFile записывает файл обложки. Файл сначала пишется во временный и
+// затем переименовывается, поэтому читатели не видят его недописанным
+func (r *CoverRepository) SaveFile(ctx context.Context, name string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	path := r.FilePath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("не удалось создать директорию обложки: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cover-*")
+	if err != nil {
+		return fmt.Errorf("не удалось создать файл: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("не удалось сохранить файл: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("не удалось сохранить файл: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("не удалось сохранить файл: %w", err)
+	}
+	return nil
+}
+
+// FilePath возвращает абсолютный путь к файлу обложки. Путь не выходит за
+// пределы каталога обложек
+func (r *CoverRepository) FilePath(name string) string {
+	return filepath.Join(r.coversDir, filepath.Clean("/"+name))
+}
+
+// DeleteFiles удаляет каталог с файлами обложки. Отсутствие каталога
+// ошибкой не считается
+func (r *CoverRepository) DeleteFiles(ctx context.Context, dir string) error {
+	if dir == "" {
+		return nil
+	}
+	if err := os.RemoveAll(r.FilePath(dir)); err != nil {
+		return fmt.Errorf("не удалось удалить файлы обложки: %w", err)
+	}
+	return nil
+}
+
+// SetEntityCover задает cover_url трека, альбома или плейлиста; пустой
+// coverURL убирает обложку. Если объекта нет или он в корзине, возвращается
+// models.ErrNotFound
+func (r *CoverRepository) SetEntityCover(ctx context.Context, entityType string, id uuid.UUID, coverURL string, updatedAt time.Time) error {
+	table, ok := coverTables[entityType]
+	if !ok {
+		return fmt.Errorf("unknown cover entity type %q", entityType)
+	}
+
+	query := `UPDATE ` + table + ` SET cover_url = NULLIF($2, ''), updated_at = $3 WHERE id = $1 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id, coverURL, updatedAt)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+// FindArtistCover возвращает ID обложки исполнителя или models.ErrNotFound
+func (r *CoverRepository) FindArtistCover(ctx context.Context, artist string) (uuid.UUID, error) {
+	var coverID uuid.UUID
+	err := r.db.QueryRowContext(ctx, `SELECT cover_id FROM artist_covers WHERE LOWER(artist) = LOWER($1)`, artist).Scan(&coverID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, models.ErrNotFound
+	}
+	return coverID, err
+}
+
+func (r *CoverRepository) SetArtistCover(ctx context.Context, artist string, coverID uuid.UUID, updatedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO artist_covers (artist, cover_id, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (LOWER(artist)) DO UPDATE SET cover_id = $2, updated_at = $3
+	`, artist, coverID, updatedAt)
+	return err
+}
+
+// DeleteArtistCover убирает обложку исполнителя. Если ее нет, возвращается
+// models.ErrNotFound
+func (r *CoverRepository) DeleteArtistCover(ctx context.Context, artist string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM artist_covers WHERE LOWER(artist) = LOWER($1)`, artist)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+// PlaylistAlbumCovers возвращает загруженные обложки первых limit альбомов
+// плейлиста в порядке, в котором плейлист показывает треки (сначала
+// добавленные последними). Каждый альбом учитывается один раз
+func (r *CoverRepository) PlaylistAlbumCovers(ctx context.Context, playlistID uuid.UUID, limit int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.cover_url
+		FROM playlist_tracks pt
+		JOIN tracks t ON t.id = pt.track_id AND t.deleted_at IS NULL
+		JOIN albums a ON a.id = t.album_id AND a.deleted_at IS NULL
+		WHERE pt.playlist_id = $1 AND a.cover_url LIKE '/api/v1/covers/%'
+		GROUP BY a.id, a.cover_url
+		ORDER BY MAX(pt.added_at) DESC, a.id
+		LIMIT $2
+	`, playlistID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var covers []string
+	for rows.Next() {
+		var coverURL string
+		if err := rows.Scan(&coverURL); err != nil {
+			return nil, err
+		}
+		covers = append(covers, coverURL)
+	}
+	return covers, rows.Err()
+}
+
+// CoversDir возвращает каталог обложек в хранилище треков
+func CoversDir(tracksDir string) string {
+	return filepath.Join(tracksDir, "covers")
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+// DBTX — общий интерфейс *sql.DB и *sql.Tx. Репозитории, которые могут
+// участвовать в транзакции, работают через него
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows для функций разбора строк
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scannerFunc превращает функцию в rowScanner: так к колонкам, которые
+// разбирает scanTrack и подобные функции, можно добавить свои
+type scannerFunc func(dest ...interface{}) error
+
+func (f scannerFunc) Scan(dest ...interface{}) error {
+	return f(dest...)
+}
+
+// nullUUID передает uuid.Nil в запрос как NULL
+func nullUUID(id uuid.UUID) interface{} {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id
+}
+
+// inTx выполняет fn в транзакции. Если репозиторий уже создан внутри
+// UnitOfWork.WithTx, fn выполняется в текущей транзакции
+func inTx(ctx context.Context, db DBTX, fn func(tx DBTX) error) error {
+	conn, ok := db.(*sql.DB)
+	if !ok {
+		return fn(db)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type FingerprintRepository struct {
+	db DBTX
+}
+
+func NewFingerprintRepository(db *sql.DB) interfaces.FingerprintRepository {
+	return &FingerprintRepository{db: db}
+}
+
+// Save заменяет отпечаток и ключи трека целиком
+func (r *FingerprintRepository) Save(ctx context.Context, trackID uuid.UUID, fingerprint []byte, hashes []uint32, createdAt time.Time) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO track_fingerprints (track_id, fingerprint, created_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (track_id) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, created_at = EXCLUDED.created_at
+		`, trackID, fingerprint, createdAt)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM track_fingerprint_hashes WHERE track_id = $1`, trackID); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO track_fingerprint_hashes (hash, track_id)
+			SELECT DISTINCT unnest($2::integer[]), $1::uuid
+		`, trackID, hashArray(hashes))
+		return err
+	})
+}
+
+// hashArray передает ключи отпечатка в запрос как массив integer. Ключи
+// занимают не больше 31 бита
+func hashArray(hashes []uint32) pq.Int64Array {
+	array := make(pq.Int64Array, len(hashes))
+	for i, hash := range hashes {
+		array[i] = int64(hash)
+	}
+	return array
+}
+
+func (r *FingerprintRepository) FindByTrack(ctx context.Context, trackID uuid.UUID) ([]byte, error) {
+	var fingerprint []byte
+	err := r.db.QueryRowContext(ctx, `SELECT fingerprint FROM track_fingerprints WHERE track_id = $1`, trackID).Scan(&fingerprint)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	return fingerprint, err
+}
+
+func (r *FingerprintRepository) FindCandidates(ctx context.Context, excludeID uuid.UUID, hashes []uint32, minShared, limit int) (map[uuid.UUID][]byte, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT f.track_id, f.fingerprint
+		FROM (
+			SELECT h.track_id, COUNT(*) AS shared
+			FROM track_fingerprint_hashes h
+			WHERE h.hash = ANY($2::integer[]) AND h.track_id <> $1
+			GROUP BY h.track_id
+			HAVING COUNT(*) >= $3
+		) c
+		JOIN track_fingerprints f ON f.track_id = c.track_id
+		JOIN tracks t ON t.id = c.track_id
+		WHERE t.deleted_at IS NULL
+		ORDER BY c.shared DESC, c.track_id
+		LIMIT $4
+	`, excludeID, hashArray(hashes), minShared, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := make(map[uuid.UUID][]byte)
+	for rows.Next() {
+		var trackID uuid.UUID
+		var fingerprint []byte
+		if err := rows.Scan(&trackID, &fingerprint); err != nil {
+			return nil, err
+		}
+		candidates[trackID] = fingerprint
+	}
+	return candidates, rows.Err()
+}
+
+// AddDuplicate хранит пару один раз: меньший идентификатор — в track_id
+func (r *FingerprintRepository) AddDuplicate(ctx context.Context, pair *models.DuplicatePair) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO track_duplicates (track_id, duplicate_id, similarity, detected_at)
+		VALUES (LEAST($1::uuid, $2::uuid), GREATEST($1::uuid, $2::uuid), $3, $4)
+		ON CONFLICT (track_id, duplicate_id) DO UPDATE SET similarity = EXCLUDED.similarity, detected_at = EXCLUDED.detected_at
+	`, pair.TrackID, pair.DuplicateID, pair.Similarity, pair.DetectedAt)
+	return err
+}
+
+func (r *FingerprintRepository) ListDuplicates(ctx context.Context) ([]*models.DuplicatePair, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT d.track_id, d.duplicate_id, d.similarity, d.detected_at
+		FROM track_duplicates d
+		JOIN tracks t ON t.id = d.track_id
+		JOIN tracks dt ON dt.id = d.duplicate_id
+		WHERE t.deleted_at IS NULL AND dt.deleted_at IS NULL
+		ORDER BY d.similarity DESC, d.track_id, d.duplicate_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pairs []*models.DuplicatePair
+	for rows.Next() {
+		var pair models.DuplicatePair
+		if err := rows.Scan(&pair.TrackID, &pair.DuplicateID, &pair.Similarity, &pair.DetectedAt); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, &pair)
+	}
+	return pairs, rows.Err()
+}
+
+func (r *FingerprintRepository) Delete(ctx context.Context, trackID uuid.UUID) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		for _, query := range []string{
+			`DELETE FROM track_duplicates WHERE track_id = $1 OR duplicate_id = $1`,
+			`DELETE FROM track_fingerprint_hashes WHERE track_id = $1`,
+			`DELETE FROM track_fingerprints WHERE track_id = $1`,
+		} {
+			if _, err := tx.ExecContext(ctx, query, trackID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
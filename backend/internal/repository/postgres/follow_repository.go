@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+
+	"github.com/google/uuid"
+)
+
+type FollowRepository struct {
+	db DBTX
+}
+
+func NewFollowRepository(db *sql.DB) interfaces.FollowRepository {
+	return &FollowRepository{
+		db: db,
+	}
+}
+
+func (r *FollowRepository) FollowPlaylist(ctx context.Context, userID, playlistID uuid.UUID) error {
+	query := `
+		INSERT INTO playlist_follows (user_id, playlist_id, followed_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, playlist_id) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, playlistID)
+	return err
+}
+
+func (r *FollowRepository) UnfollowPlaylist(ctx context.Context, userID, playlistID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM playlist_follows WHERE user_id = $1 AND playlist_id = $2`, userID, playlistID)
+	return err
+}
+
+// GetPlaylistFollowers возвращает ID пользователей, подписанных на плейлист
+func (r *FollowRepository) GetPlaylistFollowers(ctx context.Context, playlistID uuid.UUID) ([]uuid.UUID, error) {
+	return r.queryUserIDs(ctx, `SELECT user_id FROM playlist_follows WHERE playlist_id = $1`, playlistID)
+}
+
+// GetFollowedPlaylists возвращает плейлисты, на которые подписан пользователь
+func (r *FollowRepository) GetFollowedPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error) {
+	var playlists []*models.Playlist
+	query := `
+		SELECT p.id, p.name, p.description, p.user_id, p.cover_url, p.created_date, p.updated_at
+		FROM playlists p
+		JOIN playlist_follows pf ON pf.playlist_id = p.id
+		WHERE pf.user_id = $1 AND p.deleted_at IS NULL
+		ORDER BY pf.followed_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var playlist models.Playlist
+		err := rows.Scan(
+			&playlist.ID,
+			&playlist.Name,
+			&playlist.Description,
+			&playlist.UserID,
+			&playlist.CoverURL,
+			&playlist.CreatedDate,
+			&playlist.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, &playlist)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
+func (r *FollowRepository) FollowArtist(ctx context.Context, userID uuid.UUID, artist string) error {
+	query := `
+		INSERT INTO artist_follows (user_id, artist, followed_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, LOWER(artist)) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, artist)
+	return err
+}
+
+func (r *FollowRepository) UnfollowArtist(ctx context.Context, userID uuid.UUID, artist string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM artist_follows WHERE user_id = $1 AND LOWER(artist) = LOWER($2)`, userID, artist)
+	return err
+}
+
+// GetArtistFollowers возвращает ID пользователей, подписанных на исполнителя
+func (r *FollowRepository) GetArtistFollowers(ctx context.Context, artist string) ([]uuid.UUID, error) {
+	return r.queryUserIDs(ctx, `SELECT user_id FROM artist_follows WHERE LOWER(artist) = LOWER($1)`, artist)
+}
+
+// GetFollowedArtists возвращает исполнителей, на которых подписан пользователь
+func (r *FollowRepository) GetFollowedArtists(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var artists []string
+	rows, err := r.db.QueryContext(ctx, `SELECT artist FROM artist_follows WHERE user_id = $1 ORDER BY followed_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var artist string
+		if err := rows.Scan(&artist); err != nil {
+			return nil, err
+		}
+		artists = append(artists, artist)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return artists, nil
+}
+
+func (r *FollowRepository) queryUserIDs(ctx context.Context, query string, arg interface{}) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
@@ -9,7 +10,7 @@ import (
 )
 
 type GenreRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewGenreRepository(db *sql.DB) interfaces.GenreRepository {
@@ -18,37 +19,41 @@ func NewGenreRepository(db *sql.DB) interfaces.GenreRepository {
 	}
 }
 
-func (r *GenreRepository) FindByID(id uuid.UUID) (*models.Genre, error) {
+// FindByID возвращает models.ErrNotFound, если жанра нет
+func (r *GenreRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Genre, error) {
 	var genre models.Genre
 	query := `SELECT id, name FROM genres WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(&genre.ID, &genre.Name)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&genre.ID, &genre.Name)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &genre, nil
 }
 
-func (r *GenreRepository) Save(genre *models.Genre) error {
+func (r *GenreRepository) Save(ctx context.Context, genre *models.Genre) error {
 	query := `
 		INSERT INTO genres (id, name) 
 		VALUES ($1, $2)
 		ON CONFLICT (id) DO UPDATE 
 		SET name = $2
 	`
-	_, err := r.db.Exec(query, genre.ID, genre.Name)
+	_, err := r.db.ExecContext(ctx, query, genre.ID, genre.Name)
 	return err
 }
 
-func (r *GenreRepository) Delete(id uuid.UUID) error {
-	_, err := r.db.Exec(`DELETE FROM track_genres WHERE genre_id = $1`, id)
+func (r *GenreRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM track_genres WHERE genre_id = $1`, id)
 	if err != nil {
 		return err
 	}
-	_, err = r.db.Exec(`DELETE FROM genres WHERE id = $1`, id)
+	_, err = r.db.ExecContext(ctx, `DELETE FROM genres WHERE id = $1`, id)
 	return err
 }
 
-func (r *GenreRepository) GetGenresForTrack(trackID uuid.UUID) ([]*models.Genre, error) {
+func (r *GenreRepository) GetGenresForTrack(ctx context.Context, trackID uuid.UUID) ([]*models.Genre, error) {
 	var genres []*models.Genre
 	query := `
 		SELECT g.id, g.name
@@ -56,7 +61,7 @@ func (r *GenreRepository) GetGenresForTrack(trackID uuid.UUID) ([]*models.Genre,
 		JOIN track_genres tg ON g.id = tg.genre_id
 		WHERE tg.track_id = $1
 	`
-	rows, err := r.db.Query(query, trackID)
+	rows, err := r.db.QueryContext(ctx, query, trackID)
 	if err != nil {
 		return nil, err
 	}
@@ -78,27 +83,44 @@ func (r *GenreRepository) GetGenresForTrack(trackID uuid.UUID) ([]*models.Genre,
 	return genres, nil
 }
 
-func (r *GenreRepository) AddGenreToTrack(trackID, genreID uuid.UUID) error {
+func (r *GenreRepository) AddGenreToTrack(ctx context.Context, trackID, genreID uuid.UUID) error {
 	query := `
 		INSERT INTO track_genres (track_id, genre_id) 
 		VALUES ($1, $2)
 		ON CONFLICT (track_id, genre_id) DO NOTHING
 	`
-	_, err := r.db.Exec(query, trackID, genreID)
+	_, err := r.db.ExecContext(ctx, query, trackID, genreID)
 	return err
 }
 
-func (r *GenreRepository) RemoveGenreFromTrack(trackID, genreID uuid.UUID) error {
+func (r *GenreRepository) RemoveGenreFromTrack(ctx context.Context, trackID, genreID uuid.UUID) error {
 	query := `DELETE FROM track_genres WHERE track_id = $1 AND genre_id = $2`
-	_, err := r.db.Exec(query, trackID, genreID)
+	_, err := r.db.ExecContext(ctx, query, trackID, genreID)
 	return err
 }
 
-func (r *GenreRepository) ListAll() ([]*models.Genre, error) {
+// SetTrackGenres заменяет жанры трека на genreIDs; пустой список снимает
+// все жанры
+func (r *GenreRepository) SetTrackGenres(ctx context.Context, trackID uuid.UUID, genreIDs []uuid.UUID) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM track_genres WHERE track_id = $1`, trackID); err != nil {
+			return err
+		}
+		for _, genreID := range genreIDs {
+			_, err := tx.ExecContext(ctx, `INSERT INTO track_genres (track_id, genre_id) VALUES ($1, $2) ON CONFLICT (track_id, genre_id) DO NOTHING`, trackID, genreID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *GenreRepository) ListAll(ctx context.Context) ([]*models.Genre, error) {
 	var genres []*models.Genre
 	query := `SELECT id, name FROM genres`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
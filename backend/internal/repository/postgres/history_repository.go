@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
@@ -10,7 +11,7 @@ import (
 )
 
 type HistoryRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewHistoryRepository(db *sql.DB) interfaces.HistoryRepository {
@@ -19,16 +20,16 @@ func NewHistoryRepository(db *sql.DB) interfaces.HistoryRepository {
 	}
 }
 
-func (r *HistoryRepository) AddEntry(userID uuid.UUID, trackID uuid.UUID) error {
+func (r *HistoryRepository) AddEntry(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) error {
 	query := `
 		INSERT INTO listening_history (user_id, track_id, listened_at) 
 		VALUES ($1, $2, $3)
 	`
-	_, err := r.db.Exec(query, userID, trackID, time.Now())
+	_, err := r.db.ExecContext(ctx, query, userID, trackID, time.Now())
 	return err
 }
 
-func (r *HistoryRepository) GetHistory(userID uuid.UUID) ([]*models.ListeningHistory, error) {
+func (r *HistoryRepository) GetHistory(ctx context.Context, userID uuid.UUID) ([]*models.ListeningHistory, error) {
 	var history []*models.ListeningHistory
 	query := `
 		SELECT 
@@ -45,11 +46,11 @@ func (r *HistoryRepository) GetHistory(userID uuid.UUID) ([]*models.ListeningHis
 		FROM listening_history lh
 		JOIN tracks t ON t.id = lh.track_id
 		LEFT JOIN albums a ON t.album_id = a.id
-		WHERE lh.user_id = $1
+		WHERE lh.user_id = $1 AND t.deleted_at IS NULL
 		ORDER BY lh.listened_at DESC
 		LIMIT 100
 	`
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -101,14 +102,14 @@ func (r *HistoryRepository) GetHistory(userID uuid.UUID) ([]*models.ListeningHis
 }
 
 // GetPlayCount возвращает количество прослушиваний трека
-func (r *HistoryRepository) GetPlayCount(trackID uuid.UUID) (int, error) {
+func (r *HistoryRepository) GetPlayCount(ctx context.Context, trackID uuid.UUID) (int, error) {
 	var count int
 	query := `
 		SELECT COUNT(*) 
 		FROM listening_history 
 		WHERE track_id = $1
 	`
-	err := r.db.QueryRow(query, trackID).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, trackID).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
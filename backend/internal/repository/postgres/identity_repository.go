@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+
+	"github.com/google/uuid"
+)
+
+type IdentityRepository struct {
+	db DBTX
+}
+
+func NewIdentityRepository(db *sql.DB) interfaces.IdentityRepository {
+	return &IdentityRepository{
+		db: db,
+	}
+}
+
+// FindByProviderSubject ищет привязанную учетную запись провайдера.
+// Если ее нет, возвращается models.ErrNotFound
+func (r *IdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*models.Identity, error) {
+	var identity models.Identity
+	query := `
+		SELECT provider, subject, user_id, email, created_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+	err := r.db.QueryRowContext(ctx, query, provider, subject).Scan(
+		&identity.Provider,
+		&identity.Subject,
+		&identity.UserID,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *IdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Identity, error) {
+	var identities []*models.Identity
+	query := `
+		SELECT provider, subject, user_id, email, created_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var identity models.Identity
+		err := rows.Scan(
+			&identity.Provider,
+			&identity.Subject,
+			&identity.UserID,
+			&identity.Email,
+			&identity.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, &identity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+func (r *IdentityRepository) Create(ctx context.Context, identity *models.Identity) error {
+	query := `
+		INSERT INTO user_identities (provider, subject, user_id, email, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		identity.Provider,
+		identity.Subject,
+		identity.UserID,
+		identity.Email,
+		identity.CreatedAt,
+	)
+	return err
+}
+
+func (r *IdentityRepository) Delete(ctx context.Context, userID uuid.UUID, provider string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM user_identities WHERE user_id = $1 AND provider = $2`, userID, provider)
+	return err
+}
+
+// SaveState сохраняет незавершенный вход и заодно удаляет просроченные
+func (r *IdentityRepository) SaveState(ctx context.Context, state *models.OAuthState) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM oauth_states WHERE expires_at < NOW()`); err != nil {
+			return err
+		}
+
+		var linkUserID interface{}
+		if state.LinkUserID != uuid.Nil {
+			linkUserID = state.LinkUserID
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO oauth_states (state, provider, code_verifier, nonce, link_user_id, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, state.StateHash, state.Provider, state.CodeVerifier, state.Nonce, linkUserID, state.ExpiresAt)
+		return err
+	})
+}
+
+// ConsumeState удаляет незавершенный вход и возвращает его. Повторно state
+// не принимается; для просроченного или неизвестного state возвращается
+// models.ErrNotFound
+func (r *IdentityRepository) ConsumeState(ctx context.Context, stateHash string) (*models.OAuthState, error) {
+	var state models.OAuthState
+	var linkUserID uuid.NullUUID
+	query := `
+		DELETE FROM oauth_states
+		WHERE state = $1 AND expires_at > NOW()
+		RETURNING state, provider, code_verifier, nonce, link_user_id, expires_at
+	`
+	err := r.db.QueryRowContext(ctx, query, stateHash).Scan(
+		&state.StateHash,
+		&state.Provider,
+		&state.CodeVerifier,
+		&state.Nonce,
+		&linkUserID,
+		&state.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if linkUserID.Valid {
+		state.LinkUserID = linkUserID.UUID
+	}
+	return &state, nil
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// lyricsHeadline — параметры фрагмента с совпадениями для результатов поиска
+const lyricsHeadline = `StartSel=«, StopSel=», MaxWords=20, MinWords=8, MaxFragments=1`
+
+type LyricsRepository struct {
+	db DBTX
+}
+
+func NewLyricsRepository(db *sql.DB) interfaces.LyricsRepository {
+	return &LyricsRepository{db: db}
+}
+
+const lyricsColumns = `track_id, COALESCE(language, ''), synced, text, plain_text, source, status,
+	submitted_by, moderated_by, moderated_at, created_at, updated_at`
+
+func scanLyrics(row rowScanner) (*models.Lyrics, error) {
+	var lyrics models.Lyrics
+	var submittedBy, moderatedBy uuid.NullUUID
+	var moderatedAt sql.NullTime
+
+	err := row.Scan(
+		&lyrics.TrackID,
+		&lyrics.Language,
+		&lyrics.Synced,
+		&lyrics.Text,
+		&lyrics.PlainText,
+		&lyrics.Source,
+		&lyrics.Status,
+		&submittedBy,
+		&moderatedBy,
+		&moderatedAt,
+		&lyrics.CreatedAt,
+		&lyrics.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	lyrics.SubmittedBy = submittedBy.UUID
+	lyrics.ModeratedBy = moderatedBy.UUID
+	if moderatedAt.Valid {
+		at := moderatedAt.Time
+		lyrics.ModeratedAt = &at
+	}
+	return &lyrics, nil
+}
+
+func (r *LyricsRepository) FindByTrack(ctx context.Context, trackID uuid.UUID) (*models.Lyrics, error) {
+	lyrics, err := scanLyrics(r.db.QueryRowContext(ctx, `SELECT `+lyricsColumns+` FROM track_lyrics WHERE track_id = $1`, trackID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	return lyrics, err
+}
+
+// Save заменяет текст целиком; created_at сохраняется от первой версии
+func (r *LyricsRepository) Save(ctx context.Context, lyrics *models.Lyrics) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO track_lyrics (track_id, language, synced, text, plain_text, source, status,
+			submitted_by, moderated_by, moderated_at, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (track_id) DO UPDATE SET
+			language = EXCLUDED.language, synced = EXCLUDED.synced, text = EXCLUDED.text,
+			plain_text = EXCLUDED.plain_text, source = EXCLUDED.source, status = EXCLUDED.status,
+			submitted_by = EXCLUDED.submitted_by, moderated_by = EXCLUDED.moderated_by,
+			moderated_at = EXCLUDED.moderated_at, updated_at = EXCLUDED.updated_at
+	`, lyrics.TrackID, lyrics.Language, lyrics.Synced, lyrics.Text, lyrics.PlainText, lyrics.Source, lyrics.Status,
+		nullUUID(lyrics.SubmittedBy), nullUUID(lyrics.ModeratedBy), lyrics.ModeratedAt, lyrics.CreatedAt, lyrics.UpdatedAt)
+	return err
+}
+
+func (r *LyricsRepository) SetStatus(ctx context.Context, trackID uuid.UUID, status string, moderatedBy uuid.UUID, moderatedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE track_lyrics SET status = $2, moderated_by = $3, moderated_at = $4, updated_at = $4 WHERE track_id = $1
+	`, trackID, status, nullUUID(moderatedBy), moderatedAt)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+func (r *LyricsRepository) Delete(ctx context.Context, trackID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM track_lyrics WHERE track_id = $1`, trackID)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+func (r *LyricsRepository) List(ctx context.Context, filter models.LyricsFilter) (*models.LyricsPage, error) {
+	var args []interface{}
+	where := ""
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = " WHERE status = $1"
+	}
+
+	page := &models.LyricsPage{Limit: filter.Limit, Offset: filter.Offset}
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM track_lyrics`+where, args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM track_lyrics%s ORDER BY updated_at DESC, track_id LIMIT $%d OFFSET $%d`,
+		lyricsColumns, where, len(args)-1, len(args))
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		lyrics, err := scanLyrics(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, lyrics)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Search разбирает запрос как websearch_to_tsquery: слова через пробел,
+// "точная фраза", -исключение, OR
+func (r *LyricsRepository) Search(ctx context.Context, query string, limit, offset int) ([]*models.LyricsMatch, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+trackColumns+`, l.snippet, l.rank
+		FROM tracks
+		JOIN (
+			SELECT track_id,
+				ts_headline('simple', plain_text, q, '`+lyricsHeadline+`') AS snippet,
+				ts_rank(search_vector, q) AS rank
+			FROM track_lyrics, websearch_to_tsquery('simple', $1) q
+			WHERE status = 'approved' AND search_vector @@ q
+		) l ON l.track_id = tracks.id
+		WHERE tracks.deleted_at IS NULL
+		ORDER BY l.rank DESC, tracks.id
+		LIMIT $2 OFFSET $3
+	`, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []*models.LyricsMatch
+	for rows.Next() {
+		var match models.LyricsMatch
+		track, err := scanTrack(scannerFunc(func(dest ...interface{}) error {
+			return rows.Scan(append(dest, &match.Snippet, &match.Rank)...)
+		}))
+		if err != nil {
+			return nil, err
+		}
+		match.Track = track
+		matches = append(matches, &match)
+	}
+	return matches, rows.Err()
+}
+
+func (r *LyricsRepository) TouchTrack(ctx context.Context, trackID uuid.UUID, updatedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE tracks SET updated_at = $2 WHERE id = $1`, trackID, updatedAt)
+	return err
+}
@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+
+	"github.com/google/uuid"
+)
+
+type MFARepository struct {
+	db DBTX
+}
+
+func NewMFARepository(db *sql.DB) interfaces.MFARepository {
+	return &MFARepository{
+		db: db,
+	}
+}
+
+// GetTOTP возвращает секрет TOTP пользователя, в том числе неподтвержденный.
+// Если секрета нет, возвращается models.ErrNotFound
+func (r *MFARepository) GetTOTP(ctx context.Context, userID uuid.UUID) (*models.TOTPCredential, error) {
+	var credential models.TOTPCredential
+	var confirmedAt sql.NullTime
+	query := `SELECT user_id, secret, last_used_step, confirmed_at, created_at FROM user_totp WHERE user_id = $1`
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&credential.UserID,
+		&credential.Secret,
+		&credential.LastUsedStep,
+		&confirmedAt,
+		&credential.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if confirmedAt.Valid {
+		credential.ConfirmedAt = &confirmedAt.Time
+	}
+	return &credential, nil
+}
+
+// SaveTOTP сохраняет новый неподтвержденный секрет. Подтвержденный секрет
+// не перезаписывается
+func (r *MFARepository) SaveTOTP(ctx context.Context, credential *models.TOTPCredential) error {
+	query := `
+		INSERT INTO user_totp (user_id, secret, last_used_step, confirmed_at, created_at)
+		VALUES ($1, $2, 0, NULL, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, created_at = EXCLUDED.created_at
+		WHERE user_totp.confirmed_at IS NULL
+	`
+	_, err := r.db.ExecContext(ctx, query, credential.UserID, credential.Secret, credential.CreatedAt)
+	return err
+}
+
+// ConfirmTOTP завершает подключение, запоминая интервал проверочного кода.
+// Возвращает false, если подключение уже завершено
+func (r *MFARepository) ConfirmTOTP(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE user_totp SET confirmed_at = NOW(), last_used_step = $2 WHERE user_id = $1 AND confirmed_at IS NULL`,
+		userID, step)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// UseTOTPStep помечает интервал использованным. Возвращает false, если код
+// этого или более позднего интервала уже принимался — в том числе
+// параллельным запросом
+func (r *MFARepository) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE user_totp SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`, userID, step)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// DeleteTOTP отключает TOTP вместе с кодами восстановления
+func (r *MFARepository) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID)
+		return err
+	})
+}
+
+// ReplaceRecoveryCodes заменяет все коды восстановления пользователя новыми
+func (r *MFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+		for _, hash := range codeHashes {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UseRecoveryCode погашает код восстановления. Возвращает false, если кода
+// нет или он уже использован
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`,
+		userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// CountRecoveryCodes возвращает количество неиспользованных кодов восстановления
+func (r *MFARepository) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// CreateChallenge сохраняет вход, ожидающий второго фактора, и заодно
+// удаляет просроченные
+func (r *MFARepository) CreateChallenge(ctx context.Context, challenge *models.MFAChallenge) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_challenges WHERE expires_at < NOW()`); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO mfa_challenges (token_hash, user_id, device_id, device_name, device_type, attempts, expires_at)
+			VALUES ($1, $2, $3, $4, $5, 0, $6)
+		`, challenge.TokenHash, challenge.UserID, challenge.Device.ID, challenge.Device.Name, challenge.Device.Type, challenge.ExpiresAt)
+		return err
+	})
+}
+
+// GetChallenge возвращает непросроченный вход. Если его нет, возвращается
+// models.ErrNotFound
+func (r *MFARepository) GetChallenge(ctx context.Context, tokenHash string) (*models.MFAChallenge, error) {
+	var challenge models.MFAChallenge
+	query := `
+		SELECT token_hash, user_id, device_id, device_name, device_type, attempts, expires_at
+		FROM mfa_challenges
+		WHERE token_hash = $1 AND expires_at > NOW()
+	`
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&challenge.TokenHash,
+		&challenge.UserID,
+		&challenge.Device.ID,
+		&challenge.Device.Name,
+		&challenge.Device.Type,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// RecordFailedAttempt учитывает неверный код. После maxAttempts неудач вход
+// удаляется, и пароль придется ввести заново
+func (r *MFARepository) RecordFailedAttempt(ctx context.Context, tokenHash string, maxAttempts int) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE mfa_challenges SET attempts = attempts + 1 WHERE token_hash = $1`, tokenHash); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			`DELETE FROM mfa_challenges WHERE token_hash = $1 AND attempts >= $2`, tokenHash, maxAttempts)
+		return err
+	})
+}
+
+// DeleteChallenge удаляет вход. Возвращает false, если его уже удалил
+// другой запрос
+func (r *MFARepository) DeleteChallenge(ctx context.Context, tokenHash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM mfa_challenges WHERE token_hash = $1`, tokenHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *MFARepository) ListRequiredRoles(ctx context.Context) ([]models.Permission, error) {
+	var roles []models.Permission
+	rows, err := r.db.QueryContext(ctx, `SELECT role FROM mfa_required_roles ORDER BY role`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role models.Permission
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// SetRequiredRoles заменяет список ролей, для которых второй фактор обязателен
+func (r *MFARepository) SetRequiredRoles(ctx context.Context, roles []models.Permission) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_required_roles`); err != nil {
+			return err
+		}
+		for _, role := range roles {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO mfa_required_roles (role, updated_at) VALUES ($1, NOW())`, role); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
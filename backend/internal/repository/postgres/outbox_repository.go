@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxRepository struct {
+	db DBTX
+}
+
+func NewOutboxRepository(db *sql.DB) interfaces.OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+func (r *OutboxRepository) Add(ctx context.Context, event *models.DomainEvent) error {
+	query := `
+		INSERT INTO outbox_events (id, event_type, aggregate_id, payload, occurred_at, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		event.ID,
+		event.Type,
+		event.AggregateID,
+		[]byte(event.Payload),
+		event.OccurredAt,
+		event.NextAttemptAt,
+	)
+	return err
+}
+
+// ClaimPending захватывает события одним запросом. FOR UPDATE SKIP LOCKED
+// позволяет нескольким диспетчерам разбирать очередь без блокировок друг друга
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*models.DomainEvent, error) {
+	var events []*models.DomainEvent
+	query := `
+		UPDATE outbox_events SET locked_until = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE processed_at IS NULL AND failed_at IS NULL
+				AND next_attempt_at <= NOW()
+				AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY occurred_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_id, payload, occurred_at, attempts, next_attempt_at, COALESCE(last_error, '')
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event models.DomainEvent
+		var payload []byte
+		err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.AggregateID,
+			&payload,
+			&event.OccurredAt,
+			&event.Attempts,
+			&event.NextAttemptAt,
+			&event.LastError,
+		)
+		if err != nil {
+			return nil, err
+		}
+		event.Payload = payload
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING не гарантирует порядок строк
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkProcessed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE outbox_events SET processed_at = NOW(), locked_until = NULL WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// MarkFailed увеличивает счетчик попыток и откладывает следующую доставку
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3, locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, lastError, nextAttemptAt)
+	return err
+}
+
+// MarkDead помечает событие как недоставляемое, после чего оно больше не выбирается
+func (r *OutboxRepository) MarkDead(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $2, failed_at = NOW(), locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, lastError)
+	return err
+}
+
+// MarkHandled отмечает обработку события обработчиком. Вызывается в
+// транзакции обработчика, чтобы отметка и его изменения фиксировались
+// вместе
+func (r *OutboxRepository) MarkHandled(ctx context.Context, id uuid.UUID, handler string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO outbox_handled_events (event_id, handler) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, id, handler)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// DeleteProcessed удаляет доставленные события старше before вместе с
+// отметками их обработки
+func (r *OutboxRepository) DeleteProcessed(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM outbox_events WHERE processed_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+
+	"github.com/google/uuid"
+)
+
+type PasswordResetRepository struct {
+	db DBTX
+}
+
+func NewPasswordResetRepository(db *sql.DB) interfaces.PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: db,
+	}
+}
+
+func (r *PasswordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (token_hash, user_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := r.db.ExecContext(ctx, query, token.TokenHash, token.UserID, token.CreatedAt, token.ExpiresAt)
+	return err
+}
+
+// Consume помечает токен использованным и возвращает владельца. Если токена
+// нет, он истек или уже использован, возвращается models.ErrNotFound
+func (r *PasswordResetRepository) Consume(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	query := `
+		UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, models.ErrNotFound
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+// DeleteForUser удаляет все токены пользователя: при выдаче нового токена
+// и после смены пароля старые ссылки перестают действовать
+func (r *PasswordResetRepository) DeleteForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM password_reset_tokens WHERE user_id = $1`, userID)
+	return err
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+	"github.com/lib/pq"
+)
+
+type PlaybackRepository struct {
+	db DBTX
+}
+
+func NewPlaybackRepository(db *sql.DB) interfaces.PlaybackRepository {
+	return &PlaybackRepository{
+		db: db,
+	}
+}
+
+// GetState возвращает состояние воспроизведения вместе с очередью.
+// Если пользователь еще ничего не воспроизводил, возвращается models.ErrNotFound
+func (r *PlaybackRepository) GetState(ctx context.Context, userID uuid.UUID) (*models.PlaybackState, error) {
+	var state models.PlaybackState
+	var shuffleOrder pq.Int64Array
+	var activeDeviceID pgtype.UUID
+	var repeatMode string
+
+	query := `
+		SELECT user_id, current_index, position_ms, is_playing, shuffle, shuffle_order,
+			repeat_mode, active_device_id, updated_at
+		FROM playback_states WHERE user_id = $1
+	`
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&state.UserID,
+		&state.CurrentIndex,
+		&state.PositionMs,
+		&state.IsPlaying,
+		&state.Shuffle,
+		&shuffleOrder,
+		&repeatMode,
+		&activeDeviceID,
+		&state.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state.RepeatMode = models.RepeatMode(repeatMode)
+	if activeDeviceID.Status == pgtype.Present {
+		state.ActiveDeviceID = activeDeviceID.Bytes
+	}
+	for _, idx := range shuffleOrder {
+		state.ShuffleOrder = append(state.ShuffleOrder, int(idx))
+	}
+
+	state.Queue, err = r.GetQueue(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+func (r *PlaybackRepository) SaveState(ctx context.Context, state *models.PlaybackState) error {
+	var activeDeviceID interface{}
+	if state.ActiveDeviceID != uuid.Nil {
+		activeDeviceID = state.ActiveDeviceID
+	}
+
+	shuffleOrder := make(pq.Int64Array, 0, len(state.ShuffleOrder))
+	for _, idx := range state.ShuffleOrder {
+		shuffleOrder = append(shuffleOrder, int64(idx))
+	}
+
+	query := `
+		INSERT INTO playback_states (user_id, current_index, position_ms, is_playing, shuffle, shuffle_order,
+			repeat_mode, active_device_id, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE
+		SET current_index = $2, position_ms = $3, is_playing = $4, shuffle = $5, shuffle_order = $6,
+			repeat_mode = $7, active_device_id = $8, updated_at = $9
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		state.UserID,
+		state.CurrentIndex,
+		state.PositionMs,
+		state.IsPlaying,
+		state.Shuffle,
+		shuffleOrder,
+		string(state.RepeatMode),
+		activeDeviceID,
+		state.UpdatedAt,
+	)
+	return err
+}
+
+// GetQueue возвращает треки очереди в порядке добавления
+func (r *PlaybackRepository) GetQueue(ctx context.Context, userID uuid.UUID) ([]*models.Track, error) {
+	var tracks []*models.Track
+	query := `
+		SELECT t.id, t.title, t.duration, t.file_path, t.album_id, t.artist_name, t.cover_url, t.added_date, t.updated_at, t.play_count
+		FROM playback_queue pq
+		JOIN tracks t ON t.id = pq.track_id
+		WHERE pq.user_id = $1 AND t.deleted_at IS NULL
+		ORDER BY pq.position
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var track models.Track
+		var albumID pgtype.UUID
+		err := rows.Scan(
+			&track.ID,
+			&track.Title,
+			&track.Duration,
+			&track.FilePath,
+			&albumID,
+			&track.ArtistName,
+			&track.CoverURL,
+			&track.AddedDate,
+			&track.UpdatedAt,
+			&track.PlayCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if albumID.Status == pgtype.Present {
+			track.AlbumID = albumID.Bytes
+		}
+		tracks = append(tracks, &track)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tracks, nil
+}
+
+// ReplaceQueue атомарно заменяет очередь пользователя
+func (r *PlaybackRepository) ReplaceQueue(ctx context.Context, userID uuid.UUID, trackIDs []uuid.UUID) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM playback_queue WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+
+		for position, trackID := range trackIDs {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO playback_queue (user_id, position, track_id) VALUES ($1, $2, $3)`,
+				userID, position, trackID,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -1,15 +1,17 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type PlaylistRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewPlaylistRepository(db *sql.DB) interfaces.PlaylistRepository {
@@ -18,10 +20,11 @@ func NewPlaylistRepository(db *sql.DB) interfaces.PlaylistRepository {
 	}
 }
 
-func (r *PlaylistRepository) FindByID(id uuid.UUID) (*models.Playlist, error) {
+// FindByID возвращает models.ErrNotFound, если плейлиста нет
+func (r *PlaylistRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error) {
 	var playlist models.Playlist
-	query := `SELECT id, name, description, user_id, cover_url, created_date, updated_at FROM playlists WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(
+	query := `SELECT id, name, description, user_id, cover_url, created_date, updated_at FROM playlists WHERE id = $1 AND deleted_at IS NULL`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&playlist.ID,
 		&playlist.Name,
 		&playlist.Description,
@@ -30,20 +33,23 @@ func (r *PlaylistRepository) FindByID(id uuid.UUID) (*models.Playlist, error) {
 		&playlist.CreatedDate,
 		&playlist.UpdatedAt,
 	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &playlist, nil
 }
 
-func (r *PlaylistRepository) Save(playlist *models.Playlist) error {
+func (r *PlaylistRepository) Save(ctx context.Context, playlist *models.Playlist) error {
 	query := `
 		INSERT INTO playlists (id, name, description, user_id, cover_url, created_date, updated_at) 
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT (id) DO UPDATE 
 		SET name = $2, description = $3, cover_url = $5, updated_at = $7
 	`
-	_, err := r.db.Exec(query,
+	_, err := r.db.ExecContext(ctx, query,
 		playlist.ID,
 		playlist.Name,
 		playlist.Description,
@@ -55,41 +61,53 @@ func (r *PlaylistRepository) Save(playlist *models.Playlist) error {
 	return err
 }
 
-func (r *PlaylistRepository) Delete(id uuid.UUID) error {
-	_, err := r.db.Exec(`DELETE FROM playlist_tracks WHERE playlist_id = $1`, id)
+// Delete помещает плейлист в корзину с отметкой deletedAt; треки и подписки
+// сохраняются для восстановления. Если плейлиста нет, возвращается
+// models.ErrNotFound
+func (r *PlaylistRepository) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE playlists SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`, id, deletedAt)
 	if err != nil {
 		return err
 	}
-	_, err = r.db.Exec(`DELETE FROM playlists WHERE id = $1`, id)
-	return err
+	return expectAffected(result)
+}
+
+// Restore возвращает плейлист из корзины. Если в корзине его нет,
+// возвращается models.ErrNotFound
+func (r *PlaylistRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE playlists SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
 }
 
-func (r *PlaylistRepository) AddTrack(playlistID uuid.UUID, trackID uuid.UUID) error {
+func (r *PlaylistRepository) AddTrack(ctx context.Context, playlistID uuid.UUID, trackID uuid.UUID) error {
 	query := `
 		INSERT INTO playlist_tracks (playlist_id, track_id, added_at) 
 		VALUES ($1, $2, NOW())
 		ON CONFLICT (playlist_id, track_id) DO NOTHING
 	`
-	_, err := r.db.Exec(query, playlistID, trackID)
+	_, err := r.db.ExecContext(ctx, query, playlistID, trackID)
 	return err
 }
 
-func (r *PlaylistRepository) RemoveTrack(playlistID uuid.UUID, trackID uuid.UUID) error {
+func (r *PlaylistRepository) RemoveTrack(ctx context.Context, playlistID uuid.UUID, trackID uuid.UUID) error {
 	query := `DELETE FROM playlist_tracks WHERE playlist_id = $1 AND track_id = $2`
-	_, err := r.db.Exec(query, playlistID, trackID)
+	_, err := r.db.ExecContext(ctx, query, playlistID, trackID)
 	return err
 }
 
-func (r *PlaylistRepository) GetTracks(playlistID uuid.UUID) ([]*models.Track, error) {
+func (r *PlaylistRepository) GetTracks(ctx context.Context, playlistID uuid.UUID) ([]*models.Track, error) {
 	var tracks []*models.Track
 	query := `
 		SELECT t.id, t.title, t.duration, t.file_path, t.album_id, t.artist_name, t.cover_url, t.added_date, t.updated_at, t.play_count
 		FROM tracks t
 		JOIN playlist_tracks pt ON t.id = pt.track_id
-		WHERE pt.playlist_id = $1
+		WHERE pt.playlist_id = $1 AND t.deleted_at IS NULL
 		ORDER BY pt.added_at DESC
 	`
-	rows, err := r.db.Query(query, playlistID)
+	rows, err := r.db.QueryContext(ctx, query, playlistID)
 	if err != nil {
 		return nil, err
 	}
@@ -122,11 +140,11 @@ func (r *PlaylistRepository) GetTracks(playlistID uuid.UUID) ([]*models.Track, e
 	return tracks, nil
 }
 
-func (r *PlaylistRepository) GetUserPlaylists(userID uuid.UUID) ([]*models.Playlist, error) {
+func (r *PlaylistRepository) GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error) {
 	var playlists []*models.Playlist
-	query := `SELECT id, name, description, user_id, cover_url, created_date, updated_at FROM playlists WHERE user_id = $1`
+	query := `SELECT id, name, description, user_id, cover_url, created_date, updated_at FROM playlists WHERE user_id = $1 AND deleted_at IS NULL`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -1,8 +1,8 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
 	"time"
@@ -11,7 +11,7 @@ import (
 )
 
 type SessionRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewSessionRepository(db *sql.DB) interfaces.SessionRepository {
@@ -20,74 +20,295 @@ func NewSessionRepository(db *sql.DB) interfaces.SessionRepository {
 	}
 }
 
-func (r *SessionRepository) CreateSession(userID uuid.UUID, token string) (*models.Session, error) {
-	session := &models.Session{
-		ID:        uuid.New(),
-		Token:     token,
-		ExpiresAt: time.Now().Add(24 * time.Hour),
-	}
+func (r *SessionRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, created_at, last_seen_at, expires_at, device_id, device_name, device_type, user_agent, ip_address)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID,
+		session.UserID,
+		session.CreatedAt,
+		session.LastSeenAt,
+		session.ExpiresAt,
+		session.Device.ID,
+		session.Device.Name,
+		session.Device.Type,
+		session.UserAgent,
+		session.IPAddress,
+	)
+	return err
+}
 
-	query := `INSERT INTO sessions (id, user_id, token, expires_at) VALUES ($1, $2, $3, $4)`
-	_, err := r.db.Exec(query, session.ID, userID, session.Token, session.ExpiresAt)
+// GetSession возвращает действующую (не отозванную и не истекшую) сессию.
+// Если такой нет, возвращается models.ErrNotFound
+func (r *SessionRepository) GetSession(ctx context.Context, sessionID uuid.UUID) (*models.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+	`
+	session, err := scanSession(r.db.QueryRowContext(ctx, query, sessionID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
-
 	return session, nil
 }
 
-func (r *SessionRepository) GetSession(sessionID string) (*models.Session, error) {
-	var session models.Session
-	query := `SELECT id, token, expires_at FROM sessions WHERE token = $1 AND expires_at > NOW()`
-	err := r.db.QueryRow(query, sessionID).Scan(&session.ID, &session.Token, &session.ExpiresAt)
+// ListActiveSessions возвращает действующие сессии пользователя, начиная с
+// последней активной
+func (r *SessionRepository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	var sessions []*models.Session
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
-	return &session, nil
+	defer rows.Close()
+
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
 }
 
-func (r *SessionRepository) DeleteSession(sessionID string) error {
-	query := `DELETE FROM sessions WHERE token = $1`
-	_, err := r.db.Exec(query, sessionID)
+// TouchSession обновляет время последней активности и адрес клиента
+func (r *SessionRepository) TouchSession(ctx context.Context, sessionID uuid.UUID, ipAddress string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET last_seen_at = NOW(), ip_address = $2 WHERE id = $1`, sessionID, ipAddress)
 	return err
 }
 
-func (r *SessionRepository) DeleteAllForUser(userID uuid.UUID) error {
-	query := `DELETE FROM sessions WHERE user_id = $1`
-	_, err := r.db.Exec(query, userID)
+// PurgeExpired удаляет сессии, истекшие или отозванные до before, вместе с
+// их refresh-токенами, а также истекшие записи об отзыве. Возвращает
+// количество удаленных сессий
+func (r *SessionRepository) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := inTx(ctx, r.db, func(tx DBTX) error {
+		result, err := tx.ExecContext(ctx,
+			`DELETE FROM sessions WHERE expires_at < $1 OR revoked_at < $1`, before)
+		if err != nil {
+			return err
+		}
+		if deleted, err = result.RowsAffected(); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM revoked_sessions WHERE expires_at < NOW()`)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// ListDevices возвращает устройства пользователя, у которых есть действующие сессии
+func (r *SessionRepository) ListDevices(ctx context.Context, userID uuid.UUID) ([]*models.Device, error) {
+	var devices []*models.Device
+	query := `
+		SELECT DISTINCT ON (device_id) device_id, device_name, device_type
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY device_id, expires_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var device models.Device
+		if err := rows.Scan(&device.ID, &device.Name, &device.Type); err != nil {
+			return nil, err
+		}
+		devices = append(devices, &device)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+func (r *SessionRepository) AddRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (token_hash, session_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := r.db.ExecContext(ctx, query, token.TokenHash, token.SessionID, token.CreatedAt, token.ExpiresAt)
 	return err
 }
 
-func (r *SessionRepository) GetSessionByToken(token string) (*models.Session, *models.User, error) {
-	var session models.Session
-	var user models.User
-	var userID uuid.UUID
-
-	fmt.Printf("Ищем сессию с токеном: %s\n", token)
-
-	var count int
-	countErr := r.db.QueryRow(`SELECT COUNT(*) FROM sessions WHERE token = $1`, token).Scan(&count)
-	if countErr != nil {
-		fmt.Printf("Ошибка при проверке наличия сессии: %v\n", countErr)
-	} else {
-		fmt.Printf("Найдено сессий: %d\n", count)
-	}
-
-	err := r.db.QueryRow(`
-		SELECT s.id, s.user_id, s.expires_at, s.token, 
-			   u.id, u.login, u.password, u.permission, u.created_at, u.updated_at
-		FROM sessions s 
-		JOIN users u ON s.user_id = u.id
-		WHERE s.token = $1 AND s.expires_at > NOW()`,
-		token).Scan(
-		&session.ID, &userID, &session.ExpiresAt, &session.Token,
-		&user.ID, &user.Login, &user.Password, &user.Permission, &user.CreatedAt, &user.UpdatedAt,
+// GetRefreshToken ищет токен по хешу, в том числе уже использованный.
+// Если токена нет, возвращается models.ErrNotFound
+func (r *SessionRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	var usedAt sql.NullTime
+	query := `SELECT token_hash, session_id, created_at, expires_at, used_at FROM refresh_tokens WHERE token_hash = $1`
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&token.TokenHash,
+		&token.SessionID,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&usedAt,
 	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
 	if err != nil {
-		fmt.Printf("Ошибка при поиске сессии: %v\n", err)
-		return nil, nil, err
+		return nil, err
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
 	}
+	return &token, nil
+}
 
-	fmt.Printf("Успешно найдена сессия для пользователя: %s\n", user.Login)
-	return &session, &user, nil
+// MarkRefreshTokenUsed помечает токен использованным. Возвращает false, если
+// токен уже был использован — в том числе параллельным запросом
+func (r *SessionRepository) MarkRefreshTokenUsed(ctx context.Context, tokenHash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET used_at = NOW() WHERE token_hash = $1 AND used_at IS NULL`, tokenHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// RevokeSession отзывает сессию вместе со всеми ее refresh-токенами
+func (r *SessionRepository) RevokeSession(ctx context.Context, sessionID uuid.UUID, accessExpiresAt time.Time) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, sessionID); err != nil {
+			return err
+		}
+		return addRevocations(ctx, tx, []uuid.UUID{sessionID}, accessExpiresAt)
+	})
+}
+
+// RevokeAllForUser отзывает все действующие сессии пользователя и
+// возвращает их идентификаторы
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID, accessExpiresAt time.Time) ([]uuid.UUID, error) {
+	return r.revokeForUser(ctx, userID, uuid.Nil, accessExpiresAt)
+}
+
+// RevokeOtherSessions отзывает все сессии пользователя, кроме keepSessionID
+func (r *SessionRepository) RevokeOtherSessions(ctx context.Context, userID, keepSessionID uuid.UUID, accessExpiresAt time.Time) ([]uuid.UUID, error) {
+	return r.revokeForUser(ctx, userID, keepSessionID, accessExpiresAt)
+}
+
+func (r *SessionRepository) revokeForUser(ctx context.Context, userID, keepSessionID uuid.UUID, accessExpiresAt time.Time) ([]uuid.UUID, error) {
+	var sessionIDs []uuid.UUID
+	err := inTx(ctx, r.db, func(tx DBTX) error {
+		rows, err := tx.QueryContext(ctx, `
+			UPDATE sessions SET revoked_at = NOW()
+			WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL
+			RETURNING id
+		`, userID, keepSessionID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			sessionIDs = append(sessionIDs, id)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		return addRevocations(ctx, tx, sessionIDs, accessExpiresAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessionIDs, nil
+}
+
+// ListRevokedSessions возвращает отзывы, которые еще не истекли
+func (r *SessionRepository) ListRevokedSessions(ctx context.Context) ([]models.RevokedSession, error) {
+	var revoked []models.RevokedSession
+	rows, err := r.db.QueryContext(ctx, `SELECT session_id, expires_at FROM revoked_sessions WHERE expires_at > NOW()`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.RevokedSession
+		if err := rows.Scan(&item.SessionID, &item.ExpiresAt); err != nil {
+			return nil, err
+		}
+		revoked = append(revoked, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revoked, nil
+}
+
+func addRevocations(ctx context.Context, db DBTX, sessionIDs []uuid.UUID, accessExpiresAt time.Time) error {
+	for _, id := range sessionIDs {
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO revoked_sessions (session_id, revoked_at, expires_at)
+			VALUES ($1, NOW(), $2)
+			ON CONFLICT (session_id) DO UPDATE SET expires_at = GREATEST(revoked_sessions.expires_at, $2)
+		`, id, accessExpiresAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const sessionColumns = `id, user_id, created_at, last_seen_at, expires_at, device_id, device_name, device_type, user_agent, ip_address`
+
+func scanSession(row rowScanner) (*models.Session, error) {
+	var session models.Session
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.Device.ID,
+		&session.Device.Name,
+		&session.Device.Type,
+		&session.UserAgent,
+		&session.IPAddress,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
 }
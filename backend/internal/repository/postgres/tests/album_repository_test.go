@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"errors"
 	"music-service/internal/models"
 	"music-service/internal/repository/postgres"
@@ -26,6 +27,7 @@ func TestAlbumRepository_FindByID(t *testing.T) {
 	album := &models.Album{
 		ID:          albumID,
 		Title:       "Test Album",
+		Artist:      "Test Artist",
 		ReleaseDate: now,
 		CoverURL:    "http://example.com/cover.jpg",
 		CreatedAt:   now,
@@ -34,18 +36,20 @@ func TestAlbumRepository_FindByID(t *testing.T) {
 
 	// Успешный сценарий
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "release_date", "cover_url", "created_at", "updated_at"}).
-			AddRow(album.ID, album.Title, album.ReleaseDate, album.CoverURL, album.CreatedAt, album.UpdatedAt)
+		rows := sqlmock.NewRows([]string{"id", "title", "artist", "release_date", "cover_url", "created_at", "updated_at",
+			"integrated_lufs", "true_peak_dbtp"}).
+			AddRow(album.ID, album.Title, album.Artist, album.ReleaseDate, album.CoverURL, album.CreatedAt, album.UpdatedAt, -12.0, -0.5)
 
 		mock.ExpectQuery("SELECT (.+) FROM albums WHERE id = ?").
 			WithArgs(albumID).
 			WillReturnRows(rows)
 
-		foundAlbum, err := repo.FindByID(albumID)
+		foundAlbum, err := repo.FindByID(context.Background(), albumID)
 		assert.NoError(t, err)
 		assert.Equal(t, album.ID, foundAlbum.ID)
 		assert.Equal(t, album.Title, foundAlbum.Title)
 		assert.Equal(t, album.CoverURL, foundAlbum.CoverURL)
+		assert.Equal(t, &models.Loudness{IntegratedLUFS: -12, TruePeakDBTP: -0.5}, foundAlbum.Loudness)
 	})
 
 	// Сценарий с ошибкой
@@ -54,7 +58,7 @@ func TestAlbumRepository_FindByID(t *testing.T) {
 			WithArgs(albumID).
 			WillReturnError(errors.New("db error"))
 
-		foundAlbum, err := repo.FindByID(albumID)
+		foundAlbum, err := repo.FindByID(context.Background(), albumID)
 		assert.Error(t, err)
 		assert.Nil(t, foundAlbum)
 	})
@@ -79,6 +83,7 @@ func TestAlbumRepository_Save(t *testing.T) {
 	album := &models.Album{
 		ID:          albumID,
 		Title:       "Test Album",
+		Artist:      "Test Artist",
 		ReleaseDate: now,
 		CoverURL:    "http://example.com/cover.jpg",
 		CreatedAt:   now,
@@ -88,20 +93,20 @@ func TestAlbumRepository_Save(t *testing.T) {
 	// Успешное сохранение
 	t.Run("success", func(t *testing.T) {
 		mock.ExpectExec("INSERT INTO albums").
-			WithArgs(album.ID, album.Title, album.ReleaseDate, album.CoverURL, album.CreatedAt, album.UpdatedAt).
+			WithArgs(album.ID, album.Title, album.Artist, album.ReleaseDate, album.CoverURL, album.CreatedAt, album.UpdatedAt).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		err := repo.Save(album)
+		err := repo.Save(context.Background(), album)
 		assert.NoError(t, err)
 	})
 
 	// Ошибка при сохранении
 	t.Run("error", func(t *testing.T) {
 		mock.ExpectExec("INSERT INTO albums").
-			WithArgs(album.ID, album.Title, album.ReleaseDate, album.CoverURL, album.CreatedAt, album.UpdatedAt).
+			WithArgs(album.ID, album.Title, album.Artist, album.ReleaseDate, album.CoverURL, album.CreatedAt, album.UpdatedAt).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.Save(album)
+		err := repo.Save(context.Background(), album)
 		assert.Error(t, err)
 	})
 
@@ -145,16 +150,18 @@ func TestAlbumRepository_GetTracks(t *testing.T) {
 
 	// Успешное получение треков
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "duration", "file_path", "album_id", "artist_name", "cover_url", "added_date", "updated_at", "play_count"})
+		rows := sqlmock.NewRows([]string{"id", "title", "duration", "file_path", "album_id", "artist_name", "cover_url", "added_date", "updated_at", "play_count",
+			"integrated_lufs", "true_peak_dbtp"})
 		for _, track := range tracks {
-			rows.AddRow(track.ID, track.Title, track.Duration, track.FilePath, track.AlbumID, track.ArtistName, track.CoverURL, track.AddedDate, track.UpdatedAt, track.PlayCount)
+			rows.AddRow(track.ID, track.Title, track.Duration, track.FilePath, track.AlbumID, track.ArtistName, track.CoverURL, track.AddedDate, track.UpdatedAt, track.PlayCount,
+				nil, nil)
 		}
 
 		mock.ExpectQuery("SELECT (.+) FROM tracks WHERE album_id = ?").
 			WithArgs(albumID).
 			WillReturnRows(rows)
 
-		foundTracks, err := repo.GetTracks(albumID)
+		foundTracks, err := repo.GetTracks(context.Background(), albumID)
 		assert.NoError(t, err)
 		assert.Len(t, foundTracks, 2)
 		assert.Equal(t, tracks[0].Title, foundTracks[0].Title)
@@ -167,7 +174,7 @@ func TestAlbumRepository_GetTracks(t *testing.T) {
 			WithArgs(albumID).
 			WillReturnError(errors.New("db error"))
 
-		foundTracks, err := repo.GetTracks(albumID)
+		foundTracks, err := repo.GetTracks(context.Background(), albumID)
 		assert.Error(t, err)
 		assert.Nil(t, foundTracks)
 	})
@@ -196,7 +203,7 @@ func TestAlbumRepository_AddTrackToAlbum(t *testing.T) {
 			WithArgs(albumID, trackID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		err := repo.AddTrackToAlbum(albumID, trackID)
+		err := repo.AddTrackToAlbum(context.Background(), albumID, trackID)
 		assert.NoError(t, err)
 	})
 
@@ -206,7 +213,7 @@ func TestAlbumRepository_AddTrackToAlbum(t *testing.T) {
 			WithArgs(albumID, trackID).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.AddTrackToAlbum(albumID, trackID)
+		err := repo.AddTrackToAlbum(context.Background(), albumID, trackID)
 		assert.Error(t, err)
 	})
 
@@ -216,6 +223,39 @@ func TestAlbumRepository_AddTrackToAlbum(t *testing.T) {
 	}
 }
 
+func TestAlbumRepository_RefreshLoudness(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewAlbumRepository(db)
+
+	albumID := uuid.New()
+
+	// Учитываются только измеренные треки альбома не из корзины
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE albums(.+)FROM tracks\\s+WHERE album_id = \\$1 AND deleted_at IS NULL AND integrated_lufs IS NOT NULL(.+)WHERE albums.id = \\$1").
+			WithArgs(albumID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.RefreshLoudness(context.Background(), albumID))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectExec("UPDATE albums").
+			WithArgs(albumID).
+			WillReturnError(errors.New("db error"))
+
+		assert.Error(t, repo.RefreshLoudness(context.Background(), albumID))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestAlbumRepository_ListAll(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -230,6 +270,7 @@ func TestAlbumRepository_ListAll(t *testing.T) {
 		{
 			ID:          uuid.New(),
 			Title:       "Album 1",
+			Artist:      "Test Artist",
 			ReleaseDate: now,
 			CoverURL:    "http://example.com/cover1.jpg",
 			CreatedAt:   now,
@@ -238,6 +279,7 @@ func TestAlbumRepository_ListAll(t *testing.T) {
 		{
 			ID:          uuid.New(),
 			Title:       "Album 2",
+			Artist:      "Test Artist",
 			ReleaseDate: now,
 			CoverURL:    "http://example.com/cover2.jpg",
 			CreatedAt:   now,
@@ -247,15 +289,16 @@ func TestAlbumRepository_ListAll(t *testing.T) {
 
 	// Успешное получение всех альбомов
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "release_date", "cover_url", "created_at", "updated_at"})
+		rows := sqlmock.NewRows([]string{"id", "title", "artist", "release_date", "cover_url", "created_at", "updated_at",
+			"integrated_lufs", "true_peak_dbtp"})
 		for _, album := range albums {
-			rows.AddRow(album.ID, album.Title, album.ReleaseDate, album.CoverURL, album.CreatedAt, album.UpdatedAt)
+			rows.AddRow(album.ID, album.Title, album.Artist, album.ReleaseDate, album.CoverURL, album.CreatedAt, album.UpdatedAt, nil, nil)
 		}
 
 		mock.ExpectQuery("SELECT (.+) FROM albums").
 			WillReturnRows(rows)
 
-		foundAlbums, err := repo.ListAll()
+		foundAlbums, err := repo.ListAll(context.Background())
 		assert.NoError(t, err)
 		assert.Len(t, foundAlbums, 2)
 		assert.Equal(t, albums[0].Title, foundAlbums[0].Title)
@@ -267,7 +310,7 @@ func TestAlbumRepository_ListAll(t *testing.T) {
 		mock.ExpectQuery("SELECT (.+) FROM albums").
 			WillReturnError(errors.New("db error"))
 
-		foundAlbums, err := repo.ListAll()
+		foundAlbums, err := repo.ListAll(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, foundAlbums)
 	})
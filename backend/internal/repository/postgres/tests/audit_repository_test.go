@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepository_Record(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewAuditRepository(db)
+	actorID := uuid.New()
+	entry := &models.AuditEntry{
+		ID:         uuid.New(),
+		ActorID:    &actorID,
+		ActorLogin: "admin",
+		Action:     models.AuditUserSuspend,
+		EntityType: models.AuditEntityUser,
+		EntityID:   uuid.New().String(),
+		Changes: map[string]models.AuditChange{
+			"permission": {From: json.RawMessage(`"user"`), To: json.RawMessage(`"admin"`)},
+		},
+		Details:   json.RawMessage(`{"reason":"spam"}`),
+		IP:        "10.0.0.1",
+		RequestID: "req-1",
+		CreatedAt: time.Now(),
+	}
+
+	// Успешная запись
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO audit_log").
+			WithArgs(entry.ID, entry.ActorID, entry.ActorLogin, entry.Action, entry.EntityType, entry.EntityID,
+				`{"permission":{"from":"user","to":"admin"}}`, `{"reason":"spam"}`, entry.IP, entry.RequestID, entry.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Record(context.Background(), entry)
+		assert.NoError(t, err)
+	})
+
+	// Пустые изменения и детали записываются как пустые объекты
+	t.Run("empty details", func(t *testing.T) {
+		systemEntry := *entry
+		systemEntry.ActorID = nil
+		systemEntry.Changes = nil
+		systemEntry.Details = nil
+		systemEntry.IP, systemEntry.RequestID = "", ""
+		mock.ExpectExec("INSERT INTO audit_log").
+			WithArgs(systemEntry.ID, systemEntry.ActorID, systemEntry.ActorLogin, systemEntry.Action, systemEntry.EntityType, systemEntry.EntityID,
+				"{}", "{}", "", "", systemEntry.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Record(context.Background(), &systemEntry)
+		assert.NoError(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAuditRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewAuditRepository(db)
+	columns := []string{"id", "actor_id", "actor_login", "action", "entity_type", "entity_id", "changes", "details", "ip", "request_id", "created_at"}
+
+	// Фильтр по автору, действию и времени
+	t.Run("success with filters", func(t *testing.T) {
+		actorID := uuid.New()
+		entryID := uuid.New()
+		from := time.Now().Add(-24 * time.Hour)
+		to := time.Now()
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM audit_log WHERE actor_id = \\$1 AND action = \\$2 AND created_at >= \\$3 AND created_at < \\$4").
+			WithArgs(actorID, models.AuditTrackUpdate, from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT (.+) FROM audit_log WHERE (.+) ORDER BY created_at DESC, id LIMIT \\$5 OFFSET \\$6").
+			WithArgs(actorID, models.AuditTrackUpdate, from, to, 50, 0).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(entryID, actorID, "editor", models.AuditTrackUpdate, models.AuditEntityTrack, "42",
+					[]byte(`{"title":{"from":"Old","to":"New"}}`), []byte(`{}`), "10.0.0.1", "req-1", time.Now()))
+
+		page, err := repo.List(context.Background(), models.AuditFilter{
+			ActorID: &actorID,
+			Action:  models.AuditTrackUpdate,
+			From:    &from,
+			To:      &to,
+			Limit:   50,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Len(t, page.Entries, 1)
+		entry := page.Entries[0]
+		assert.Equal(t, entryID, entry.ID)
+		assert.Equal(t, &actorID, entry.ActorID)
+		assert.JSONEq(t, `"Old"`, string(entry.Changes["title"].From))
+		assert.JSONEq(t, `"New"`, string(entry.Changes["title"].To))
+		assert.Equal(t, "10.0.0.1", entry.IP)
+		assert.Equal(t, "req-1", entry.RequestID)
+	})
+
+	// Записи системных действий без автора
+	t.Run("system actor", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM audit_log").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT (.+) FROM audit_log").
+			WithArgs(50, 0).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(uuid.New(), nil, "", models.AuditMFAPolicyChange, models.AuditEntityMFAPolicy, "", []byte(`{}`), []byte(`{}`), "", "", time.Now()))
+
+		page, err := repo.List(context.Background(), models.AuditFilter{Limit: 50})
+		assert.NoError(t, err)
+		assert.Len(t, page.Entries, 1)
+		assert.Nil(t, page.Entries[0].ActorID)
+		assert.Nil(t, page.Entries[0].Changes)
+	})
+
+	// Ошибка базы данных
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM audit_log").
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.List(context.Background(), models.AuditFilter{Limit: 50})
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAuditRepository_DeleteBefore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewAuditRepository(db)
+	before := time.Now().Add(-365 * 24 * time.Hour)
+
+	// Успешное удаление старых записей
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM audit_log WHERE created_at < \\$1").
+			WithArgs(before).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		deleted, err := repo.DeleteBefore(context.Background(), before)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+	})
+
+	// Ошибка базы данных
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM audit_log WHERE created_at < \\$1").
+			WithArgs(before).
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.DeleteBefore(context.Background(), before)
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var coverRowColumns = []string{"id", "format", "width", "height", "size_bytes", "uploaded_by", "created_at"}
+
+func TestCoverRepository_FindByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewCoverRepository(db, t.TempDir())
+
+	coverID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// Обложка, загрузивший ее пользователь удален
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows(coverRowColumns).AddRow(coverID, "jpeg", 1200, 800, 34567, nil, now)
+
+		mock.ExpectQuery("SELECT (.+) FROM covers WHERE id = \\$1").
+			WithArgs(coverID).
+			WillReturnRows(rows)
+
+		cover, err := repo.FindByID(context.Background(), coverID)
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg", cover.Format)
+		assert.Equal(t, 1200, cover.Width)
+		assert.Equal(t, uuid.Nil, cover.UploadedBy)
+		assert.Equal(t, "/api/v1/covers/"+coverID.String(), cover.URL())
+	})
+
+	// Обложки нет
+	t.Run("не найдена", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM covers WHERE id = \\$1").
+			WithArgs(coverID).
+			WillReturnRows(sqlmock.NewRows(coverRowColumns))
+
+		_, err := repo.FindByID(context.Background(), coverID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCoverRepository_SetEntityCover(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewCoverRepository(db, t.TempDir())
+
+	albumID := uuid.New()
+	now := time.Now()
+	coverURL := models.CoverURL(uuid.New())
+
+	// Обложка альбома
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE albums SET cover_url = NULLIF\\(\\$2, ''\\), updated_at = \\$3 WHERE id = \\$1 AND deleted_at IS NULL").
+			WithArgs(albumID, coverURL, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.SetEntityCover(context.Background(), models.CoverAlbum, albumID, coverURL, now)
+		assert.NoError(t, err)
+	})
+
+	// Плейлист в корзине или удален
+	t.Run("не найден", func(t *testing.T) {
+		mock.ExpectExec("UPDATE playlists SET cover_url").
+			WithArgs(albumID, "", now).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.SetEntityCover(context.Background(), models.CoverPlaylist, albumID, "", now)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	// Неизвестный тип объекта: запрос не выполняется
+	t.Run("неизвестный тип", func(t *testing.T) {
+		err := repo.SetEntityCover(context.Background(), "genre", albumID, coverURL, now)
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCoverRepository_ArtistCover(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewCoverRepository(db, t.TempDir())
+
+	coverID := uuid.New()
+	now := time.Now()
+
+	// Повторная загрузка заменяет обложку независимо от регистра имени
+	t.Run("set", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO artist_covers (.+) ON CONFLICT \\(LOWER\\(artist\\)\\) DO UPDATE").
+			WithArgs("Кино", coverID, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.SetArtistCover(context.Background(), "Кино", coverID, now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("find", func(t *testing.T) {
+		mock.ExpectQuery("SELECT cover_id FROM artist_covers WHERE LOWER\\(artist\\) = LOWER\\(\\$1\\)").
+			WithArgs("кино").
+			WillReturnRows(sqlmock.NewRows([]string{"cover_id"}).AddRow(coverID))
+
+		found, err := repo.FindArtistCover(context.Background(), "кино")
+		assert.NoError(t, err)
+		assert.Equal(t, coverID, found)
+	})
+
+	// Удаление отсутствующей обложки
+	t.Run("delete не найдена", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM artist_covers").
+			WithArgs("Аквариум").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.DeleteArtistCover(context.Background(), "Аквариум")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCoverRepository_PlaylistAlbumCovers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewCoverRepository(db, t.TempDir())
+
+	playlistID := uuid.New()
+	first, second := models.CoverURL(uuid.New()), models.CoverURL(uuid.New())
+
+	mock.ExpectQuery("SELECT a.cover_url FROM playlist_tracks pt (.+) GROUP BY a.id, a.cover_url (.+) LIMIT \\$2").
+		WithArgs(playlistID, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"cover_url"}).AddRow(first).AddRow(second))
+
+	covers, err := repo.PlaylistAlbumCovers(context.Background(), playlistID, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{first, second}, covers)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCoverRepository_Files(t *testing.T) {
+	dir := t.TempDir()
+	repo := postgres.NewCoverRepository(nil, dir)
+	ctx := context.Background()
+
+	// Файл пишется в подкаталог, промежуточные каталоги создаются
+	t.Run("save", func(t *testing.T) {
+		err := repo.SaveFile(ctx, "ab/cd/cover/300.jpg", []byte("jpeg"))
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, "ab", "cd", "cover", "300.jpg"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("jpeg"), data)
+
+		entries, err := os.ReadDir(filepath.Join(dir, "ab", "cd", "cover"))
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1, "временный файл должен быть переименован")
+	})
+
+	// Путь не выходит за пределы каталога обложек
+	t.Run("path traversal", func(t *testing.T) {
+		assert.Equal(t, filepath.Join(dir, "etc", "passwd"), repo.FilePath("../../etc/passwd"))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, repo.DeleteFiles(ctx, "ab/cd/cover"))
+		_, err := os.Stat(filepath.Join(dir, "ab", "cd", "cover"))
+		assert.True(t, os.IsNotExist(err))
+
+		// Повторное удаление — не ошибка
+		assert.NoError(t, repo.DeleteFiles(ctx, "ab/cd/cover"))
+	})
+}
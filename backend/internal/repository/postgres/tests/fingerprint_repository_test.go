@@ -0,0 +1,216 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprintRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewFingerprintRepository(db)
+
+	trackID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	data := []byte{1, 0, 0, 0, 2, 0, 0, 0}
+
+	// Отпечаток и ключи заменяются в одной транзакции
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO track_fingerprints (.+) ON CONFLICT \\(track_id\\) DO UPDATE").
+			WithArgs(trackID, data, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM track_fingerprint_hashes WHERE track_id = \\$1").
+			WithArgs(trackID).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec("INSERT INTO track_fingerprint_hashes \\(hash, track_id\\)\\s+SELECT DISTINCT unnest\\(\\$2::integer\\[\\]\\), \\$1").
+			WithArgs(trackID, "{7,1048575}").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Save(context.Background(), trackID, data, []uint32{7, 1<<20 - 1}, now))
+	})
+
+	// Ошибка записи ключей откатывает отпечаток
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO track_fingerprints").
+			WithArgs(trackID, data, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM track_fingerprint_hashes").
+			WithArgs(trackID).
+			WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		assert.Error(t, repo.Save(context.Background(), trackID, data, []uint32{7}, now))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFingerprintRepository_FindByTrack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewFingerprintRepository(db)
+
+	trackID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT fingerprint FROM track_fingerprints WHERE track_id = \\$1").
+			WithArgs(trackID).
+			WillReturnRows(sqlmock.NewRows([]string{"fingerprint"}).AddRow([]byte{1, 2, 3, 4}))
+
+		data, err := repo.FindByTrack(context.Background(), trackID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2, 3, 4}, data)
+	})
+
+	// У трека нет отпечатка
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT fingerprint FROM track_fingerprints").
+			WithArgs(trackID).
+			WillReturnRows(sqlmock.NewRows([]string{"fingerprint"}))
+
+		_, err := repo.FindByTrack(context.Background(), trackID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFingerprintRepository_FindCandidates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewFingerprintRepository(db)
+
+	trackID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+
+	// Кандидаты — треки не из корзины с достаточным числом общих ключей
+	mock.ExpectQuery("SELECT f.track_id, f.fingerprint(.+)WHERE h.hash = ANY\\(\\$2::integer\\[\\]\\) AND h.track_id <> \\$1(.+)HAVING COUNT\\(\\*\\) >= \\$3(.+)t.deleted_at IS NULL(.+)LIMIT \\$4").
+		WithArgs(trackID, "{3,5,8}", 10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"track_id", "fingerprint"}).
+			AddRow(first, []byte{1, 0, 0, 0}).
+			AddRow(second, []byte{2, 0, 0, 0}))
+
+	candidates, err := repo.FindCandidates(context.Background(), trackID, []uint32{3, 5, 8}, 10, 20)
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID][]byte{
+		first:  {1, 0, 0, 0},
+		second: {2, 0, 0, 0},
+	}, candidates)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFingerprintRepository_AddDuplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewFingerprintRepository(db)
+
+	pair := &models.DuplicatePair{
+		TrackID:     uuid.New(),
+		DuplicateID: uuid.New(),
+		Similarity:  0.91,
+		DetectedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	// Пара упорядочивается в запросе, повторная — обновляет сходство
+	mock.ExpectExec("INSERT INTO track_duplicates (.+) VALUES \\(LEAST\\(\\$1::uuid, \\$2::uuid\\), GREATEST\\(\\$1::uuid, \\$2::uuid\\), \\$3, \\$4\\)\\s+ON CONFLICT \\(track_id, duplicate_id\\) DO UPDATE").
+		WithArgs(pair.TrackID, pair.DuplicateID, 0.91, pair.DetectedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.AddDuplicate(context.Background(), pair))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFingerprintRepository_ListDuplicates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewFingerprintRepository(db)
+
+	first, second := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// Пары с треками из корзины не попадают в отчет
+	mock.ExpectQuery("SELECT d.track_id, d.duplicate_id, d.similarity, d.detected_at\\s+FROM track_duplicates d(.+)WHERE t.deleted_at IS NULL AND dt.deleted_at IS NULL\\s+ORDER BY d.similarity DESC").
+		WillReturnRows(sqlmock.NewRows([]string{"track_id", "duplicate_id", "similarity", "detected_at"}).
+			AddRow(first, second, 0.97, now))
+
+	pairs, err := repo.ListDuplicates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*models.DuplicatePair{{TrackID: first, DuplicateID: second, Similarity: 0.97, DetectedAt: now}}, pairs)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFingerprintRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewFingerprintRepository(db)
+
+	trackID := uuid.New()
+
+	// Пары, ключи и отпечаток удаляются вместе
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM track_duplicates WHERE track_id = \\$1 OR duplicate_id = \\$1").
+		WithArgs(trackID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM track_fingerprint_hashes WHERE track_id = \\$1").
+		WithArgs(trackID).
+		WillReturnResult(sqlmock.NewResult(0, 40))
+	mock.ExpectExec("DELETE FROM track_fingerprints WHERE track_id = \\$1").
+		WithArgs(trackID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Delete(context.Background(), trackID))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollowRepository_FollowPlaylist(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewFollowRepository(db)
+
+	userID := uuid.New()
+	playlistID := uuid.New()
+
+	// Успешная подписка
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO playlist_follows").
+			WithArgs(userID, playlistID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.FollowPlaylist(context.Background(), userID, playlistID)
+		assert.NoError(t, err)
+	})
+
+	// Ошибка базы данных
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO playlist_follows").
+			WithArgs(userID, playlistID).
+			WillReturnError(errors.New("db error"))
+
+		err := repo.FollowPlaylist(context.Background(), userID, playlistID)
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFollowRepository_GetPlaylistFollowers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewFollowRepository(db)
+
+	playlistID := uuid.New()
+	followers := []uuid.UUID{uuid.New(), uuid.New()}
+
+	// Успешный сценарий
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"user_id"}).
+			AddRow(followers[0]).
+			AddRow(followers[1])
+
+		mock.ExpectQuery("SELECT user_id FROM playlist_follows WHERE playlist_id = \\$1").
+			WithArgs(playlistID).
+			WillReturnRows(rows)
+
+		result, err := repo.GetPlaylistFollowers(context.Background(), playlistID)
+		assert.NoError(t, err)
+		assert.Equal(t, followers, result)
+	})
+
+	// Ошибка базы данных
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT user_id FROM playlist_follows WHERE playlist_id = \\$1").
+			WithArgs(playlistID).
+			WillReturnError(errors.New("db error"))
+
+		result, err := repo.GetPlaylistFollowers(context.Background(), playlistID)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFollowRepository_GetFollowedPlaylists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewFollowRepository(db)
+
+	userID := uuid.New()
+	playlistID := uuid.New()
+	ownerID := uuid.New()
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "user_id", "cover_url", "created_date", "updated_at"}).
+		AddRow(playlistID, "Followed", "Description", ownerID, "", now, now)
+
+	mock.ExpectQuery("SELECT (.+) FROM playlists p JOIN playlist_follows pf (.+) WHERE pf.user_id = \\$1").
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	playlists, err := repo.GetFollowedPlaylists(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Len(t, playlists, 1)
+	assert.Equal(t, playlistID, playlists[0].ID)
+	assert.Equal(t, ownerID, playlists[0].UserID)
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFollowRepository_Artists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewFollowRepository(db)
+
+	userID := uuid.New()
+
+	// Подписка на исполнителя
+	t.Run("follow", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO artist_follows").
+			WithArgs(userID, "Test Artist").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.FollowArtist(context.Background(), userID, "Test Artist")
+		assert.NoError(t, err)
+	})
+
+	// Подписчики ищутся без учета регистра
+	t.Run("followers", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"user_id"}).AddRow(userID)
+
+		mock.ExpectQuery("SELECT user_id FROM artist_follows WHERE LOWER\\(artist\\) = LOWER\\(\\$1\\)").
+			WithArgs("test artist").
+			WillReturnRows(rows)
+
+		followers, err := repo.GetArtistFollowers(context.Background(), "test artist")
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{userID}, followers)
+	})
+
+	// Список исполнителей пользователя
+	t.Run("followed_artists", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"artist"}).AddRow("Test Artist")
+
+		mock.ExpectQuery("SELECT artist FROM artist_follows WHERE user_id = \\$1").
+			WithArgs(userID).
+			WillReturnRows(rows)
+
+		artists, err := repo.GetFollowedArtists(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Test Artist"}, artists)
+	})
+
+	// Отписка
+	t.Run("unfollow", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM artist_follows WHERE user_id = \\$1 AND LOWER\\(artist\\) = LOWER\\(\\$2\\)").
+			WithArgs(userID, "Test Artist").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.UnfollowArtist(context.Background(), userID, "Test Artist")
+		assert.NoError(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
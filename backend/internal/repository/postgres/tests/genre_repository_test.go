@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"errors"
 	"music-service/internal/models"
 	"music-service/internal/repository/postgres"
@@ -35,7 +36,7 @@ func TestGenreRepository_FindByID(t *testing.T) {
 			WithArgs(genreID).
 			WillReturnRows(rows)
 
-		foundGenre, err := repo.FindByID(genreID)
+		foundGenre, err := repo.FindByID(context.Background(), genreID)
 		assert.NoError(t, err)
 		assert.Equal(t, genre.ID, foundGenre.ID)
 		assert.Equal(t, genre.Name, foundGenre.Name)
@@ -47,7 +48,7 @@ func TestGenreRepository_FindByID(t *testing.T) {
 			WithArgs(genreID).
 			WillReturnError(errors.New("db error"))
 
-		foundGenre, err := repo.FindByID(genreID)
+		foundGenre, err := repo.FindByID(context.Background(), genreID)
 		assert.Error(t, err)
 		assert.Nil(t, foundGenre)
 	})
@@ -79,7 +80,7 @@ func TestGenreRepository_Save(t *testing.T) {
 			WithArgs(genre.ID, genre.Name).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		err := repo.Save(genre)
+		err := repo.Save(context.Background(), genre)
 		assert.NoError(t, err)
 	})
 
@@ -89,7 +90,7 @@ func TestGenreRepository_Save(t *testing.T) {
 			WithArgs(genre.ID, genre.Name).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.Save(genre)
+		err := repo.Save(context.Background(), genre)
 		assert.Error(t, err)
 	})
 
@@ -122,7 +123,7 @@ func TestGenreRepository_Delete(t *testing.T) {
 			WithArgs(genreID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := repo.Delete(genreID)
+		err := repo.Delete(context.Background(), genreID)
 		assert.NoError(t, err)
 	})
 
@@ -132,7 +133,7 @@ func TestGenreRepository_Delete(t *testing.T) {
 			WithArgs(genreID).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.Delete(genreID)
+		err := repo.Delete(context.Background(), genreID)
 		assert.Error(t, err)
 	})
 
@@ -146,7 +147,7 @@ func TestGenreRepository_Delete(t *testing.T) {
 			WithArgs(genreID).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.Delete(genreID)
+		err := repo.Delete(context.Background(), genreID)
 		assert.Error(t, err)
 	})
 
@@ -188,7 +189,7 @@ func TestGenreRepository_GetGenresForTrack(t *testing.T) {
 			WithArgs(trackID).
 			WillReturnRows(rows)
 
-		foundGenres, err := repo.GetGenresForTrack(trackID)
+		foundGenres, err := repo.GetGenresForTrack(context.Background(), trackID)
 		assert.NoError(t, err)
 		assert.Len(t, foundGenres, 2)
 		assert.Equal(t, genres[0].Name, foundGenres[0].Name)
@@ -201,7 +202,7 @@ func TestGenreRepository_GetGenresForTrack(t *testing.T) {
 			WithArgs(trackID).
 			WillReturnError(errors.New("db error"))
 
-		foundGenres, err := repo.GetGenresForTrack(trackID)
+		foundGenres, err := repo.GetGenresForTrack(context.Background(), trackID)
 		assert.Error(t, err)
 		assert.Nil(t, foundGenres)
 	})
@@ -230,7 +231,7 @@ func TestGenreRepository_AddGenreToTrack(t *testing.T) {
 			WithArgs(trackID, genreID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		err := repo.AddGenreToTrack(trackID, genreID)
+		err := repo.AddGenreToTrack(context.Background(), trackID, genreID)
 		assert.NoError(t, err)
 	})
 
@@ -240,7 +241,70 @@ func TestGenreRepository_AddGenreToTrack(t *testing.T) {
 			WithArgs(trackID, genreID).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.AddGenreToTrack(trackID, genreID)
+		err := repo.AddGenreToTrack(context.Background(), trackID, genreID)
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGenreRepository_SetTrackGenres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewGenreRepository(db)
+
+	trackID := uuid.New()
+	rock, punk := uuid.New(), uuid.New()
+
+	// Жанры заменяются в одной транзакции
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM track_genres WHERE track_id = \\$1").
+			WithArgs(trackID).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec("INSERT INTO track_genres").
+			WithArgs(trackID, rock).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO track_genres").
+			WithArgs(trackID, punk).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.SetTrackGenres(context.Background(), trackID, []uuid.UUID{rock, punk})
+		assert.NoError(t, err)
+	})
+
+	// Пустой список снимает все жанры
+	t.Run("clear", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM track_genres").
+			WithArgs(trackID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := repo.SetTrackGenres(context.Background(), trackID, []uuid.UUID{})
+		assert.NoError(t, err)
+	})
+
+	// Ошибка вставки откатывает удаление
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM track_genres").
+			WithArgs(trackID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO track_genres").
+			WithArgs(trackID, rock).
+			WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		err := repo.SetTrackGenres(context.Background(), trackID, []uuid.UUID{rock})
 		assert.Error(t, err)
 	})
 
@@ -268,7 +332,7 @@ func TestGenreRepository_RemoveGenreFromTrack(t *testing.T) {
 			WithArgs(trackID, genreID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := repo.RemoveGenreFromTrack(trackID, genreID)
+		err := repo.RemoveGenreFromTrack(context.Background(), trackID, genreID)
 		assert.NoError(t, err)
 	})
 
@@ -278,7 +342,7 @@ func TestGenreRepository_RemoveGenreFromTrack(t *testing.T) {
 			WithArgs(trackID, genreID).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.RemoveGenreFromTrack(trackID, genreID)
+		err := repo.RemoveGenreFromTrack(context.Background(), trackID, genreID)
 		assert.Error(t, err)
 	})
 
@@ -322,7 +386,7 @@ func TestGenreRepository_ListAll(t *testing.T) {
 		mock.ExpectQuery("SELECT id, name FROM genres").
 			WillReturnRows(rows)
 
-		foundGenres, err := repo.ListAll()
+		foundGenres, err := repo.ListAll(context.Background())
 		assert.NoError(t, err)
 		assert.Len(t, foundGenres, 3)
 		assert.Equal(t, genres[0].Name, foundGenres[0].Name)
@@ -335,7 +399,7 @@ func TestGenreRepository_ListAll(t *testing.T) {
 		mock.ExpectQuery("SELECT id, name FROM genres").
 			WillReturnError(errors.New("db error"))
 
-		foundGenres, err := repo.ListAll()
+		foundGenres, err := repo.ListAll(context.Background())
 		assert.Error(t, err)
 		assert.Nil(t, foundGenres)
 	})
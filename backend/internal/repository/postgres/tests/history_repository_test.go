@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"errors"
 	"music-service/internal/models"
 	"music-service/internal/repository/postgres"
@@ -30,7 +31,7 @@ func TestHistoryRepository_AddEntry(t *testing.T) {
 			WithArgs(userID, trackID, sqlmock.AnyArg()).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		err := repo.AddEntry(userID, trackID)
+		err := repo.AddEntry(context.Background(), userID, trackID)
 		assert.NoError(t, err)
 	})
 
@@ -40,7 +41,7 @@ func TestHistoryRepository_AddEntry(t *testing.T) {
 			WithArgs(userID, trackID, sqlmock.AnyArg()).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.AddEntry(userID, trackID)
+		err := repo.AddEntry(context.Background(), userID, trackID)
 		assert.Error(t, err)
 	})
 
@@ -50,6 +51,11 @@ func TestHistoryRepository_AddEntry(t *testing.T) {
 	}
 }
 
+var historyColumns = []string{
+	"id", "user_id", "track_id", "listened_at",
+	"title", "artist_name", "duration", "cover_url", "album_id", "album_title",
+}
+
 func TestHistoryRepository_GetHistory(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -79,16 +85,17 @@ func TestHistoryRepository_GetHistory(t *testing.T) {
 
 	// Успешное получение истории прослушивания
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "user_id", "track_id", "listened_at"})
+		rows := sqlmock.NewRows(historyColumns)
 		for _, entry := range historyEntries {
-			rows.AddRow(entry.ID, entry.UserID, entry.TrackID, entry.ListenedAt)
+			rows.AddRow(entry.ID, entry.UserID, entry.TrackID, entry.ListenedAt,
+				"Track", "Artist", 180, "", nil, nil)
 		}
 
-		mock.ExpectQuery("SELECT lh.id, lh.user_id, lh.track_id, lh.listened_at FROM listening_history lh WHERE lh.user_id = \\$1").
+		mock.ExpectQuery("SELECT (.+) FROM listening_history lh (.+) WHERE lh.user_id = \\$1").
 			WithArgs(userID).
 			WillReturnRows(rows)
 
-		history, err := repo.GetHistory(userID)
+		history, err := repo.GetHistory(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Len(t, history, 2)
 		assert.Equal(t, historyEntries[0].TrackID, history[0].TrackID)
@@ -97,24 +104,24 @@ func TestHistoryRepository_GetHistory(t *testing.T) {
 
 	// Пустая история
 	t.Run("empty_history", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "user_id", "track_id", "listened_at"})
+		rows := sqlmock.NewRows(historyColumns)
 
-		mock.ExpectQuery("SELECT lh.id, lh.user_id, lh.track_id, lh.listened_at FROM listening_history lh WHERE lh.user_id = \\$1").
+		mock.ExpectQuery("SELECT (.+) FROM listening_history lh (.+) WHERE lh.user_id = \\$1").
 			WithArgs(userID).
 			WillReturnRows(rows)
 
-		history, err := repo.GetHistory(userID)
+		history, err := repo.GetHistory(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Empty(t, history)
 	})
 
 	// Ошибка при получении истории
 	t.Run("error", func(t *testing.T) {
-		mock.ExpectQuery("SELECT lh.id, lh.user_id, lh.track_id, lh.listened_at FROM listening_history lh WHERE lh.user_id = \\$1").
+		mock.ExpectQuery("SELECT (.+) FROM listening_history lh (.+) WHERE lh.user_id = \\$1").
 			WithArgs(userID).
 			WillReturnError(errors.New("db error"))
 
-		history, err := repo.GetHistory(userID)
+		history, err := repo.GetHistory(context.Background(), userID)
 		assert.Error(t, err)
 		assert.Nil(t, history)
 	})
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityRepository_FindByProviderSubject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewIdentityRepository(db)
+
+	userID := uuid.New()
+
+	// Учетная запись провайдера привязана
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"provider", "subject", "user_id", "email", "created_at"}).
+			AddRow("company", "subject-1", userID, "user@example.com", time.Now())
+
+		mock.ExpectQuery("SELECT (.+) FROM user_identities WHERE provider = \\$1 AND subject = \\$2").
+			WithArgs("company", "subject-1").
+			WillReturnRows(rows)
+
+		identity, err := repo.FindByProviderSubject(context.Background(), "company", "subject-1")
+		assert.NoError(t, err)
+		assert.Equal(t, userID, identity.UserID)
+	})
+
+	// Привязки нет
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM user_identities WHERE provider = \\$1 AND subject = \\$2").
+			WithArgs("company", "missing").
+			WillReturnRows(sqlmock.NewRows([]string{"provider", "subject", "user_id", "email", "created_at"}))
+
+		identity, err := repo.FindByProviderSubject(context.Background(), "company", "missing")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Nil(t, identity)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdentityRepository_SaveState(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewIdentityRepository(db)
+
+	state := &models.OAuthState{
+		StateHash:    "hash",
+		Provider:     "company",
+		CodeVerifier: "verifier",
+		Nonce:        "nonce",
+		ExpiresAt:    time.Now().Add(10 * time.Minute),
+	}
+
+	// Просроченные входы удаляются в той же транзакции; без привязки
+	// link_user_id записывается как NULL
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM oauth_states WHERE expires_at < NOW\\(\\)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO oauth_states").
+		WithArgs("hash", "company", "verifier", "nonce", nil, state.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.SaveState(context.Background(), state)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdentityRepository_ConsumeState(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewIdentityRepository(db)
+
+	columns := []string{"state", "provider", "code_verifier", "nonce", "link_user_id", "expires_at"}
+	linkUserID := uuid.New()
+
+	// state найден и удален
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow("hash", "company", "verifier", "nonce", linkUserID, time.Now().Add(time.Minute))
+
+		mock.ExpectQuery("DELETE FROM oauth_states WHERE state = \\$1 AND expires_at > NOW\\(\\) RETURNING").
+			WithArgs("hash").
+			WillReturnRows(rows)
+
+		state, err := repo.ConsumeState(context.Background(), "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, linkUserID, state.LinkUserID)
+		assert.Equal(t, "verifier", state.CodeVerifier)
+	})
+
+	// Повторное использование или просроченный state
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("DELETE FROM oauth_states WHERE state = \\$1 AND expires_at > NOW\\(\\) RETURNING").
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		state, err := repo.ConsumeState(context.Background(), "hash")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Nil(t, state)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
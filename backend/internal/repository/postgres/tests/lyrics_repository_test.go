@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var lyricsRowColumns = []string{"track_id", "language", "synced", "text", "plain_text", "source", "status",
+	"submitted_by", "moderated_by", "moderated_at", "created_at", "updated_at"}
+
+func TestLyricsRepository_FindByTrack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewLyricsRepository(db)
+
+	trackID := uuid.New()
+	userID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// Текст на модерации
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows(lyricsRowColumns).
+			AddRow(trackID, "ru", true, "[00:01.00]Раз", "Раз", "upload", "pending", userID, nil, nil, now, now)
+
+		mock.ExpectQuery("SELECT (.+) FROM track_lyrics WHERE track_id = \\$1").
+			WithArgs(trackID).
+			WillReturnRows(rows)
+
+		lyrics, err := repo.FindByTrack(context.Background(), trackID)
+		assert.NoError(t, err)
+		assert.True(t, lyrics.Synced)
+		assert.Equal(t, models.LyricsPending, lyrics.Status)
+		assert.Equal(t, userID, lyrics.SubmittedBy)
+		assert.Equal(t, uuid.Nil, lyrics.ModeratedBy)
+		assert.Nil(t, lyrics.ModeratedAt)
+	})
+
+	t.Run("не найден", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM track_lyrics WHERE track_id = \\$1").
+			WithArgs(trackID).
+			WillReturnRows(sqlmock.NewRows(lyricsRowColumns))
+
+		_, err := repo.FindByTrack(context.Background(), trackID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLyricsRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewLyricsRepository(db)
+
+	now := time.Now()
+	lyrics := &models.Lyrics{
+		TrackID:   uuid.New(),
+		Text:      "Белый снег",
+		PlainText: "Белый снег",
+		Source:    models.LyricsSourceID3,
+		Status:    models.LyricsApproved,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// Автор не задан: submitted_by и moderated_by записываются как NULL
+	mock.ExpectExec("INSERT INTO track_lyrics (.+) ON CONFLICT \\(track_id\\) DO UPDATE").
+		WithArgs(lyrics.TrackID, "", false, "Белый снег", "Белый снег", "id3", "approved", nil, nil, nil, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Save(context.Background(), lyrics))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLyricsRepository_SetStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewLyricsRepository(db)
+
+	trackID := uuid.New()
+	moderatorID := uuid.New()
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE track_lyrics SET status = \\$2").
+			WithArgs(trackID, models.LyricsApproved, moderatorID, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.SetStatus(context.Background(), trackID, models.LyricsApproved, moderatorID, now))
+	})
+
+	t.Run("не найден", func(t *testing.T) {
+		mock.ExpectExec("UPDATE track_lyrics SET status = \\$2").
+			WithArgs(trackID, models.LyricsRejected, moderatorID, now).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.SetStatus(context.Background(), trackID, models.LyricsRejected, moderatorID, now)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLyricsRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewLyricsRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM track_lyrics WHERE status = \\$1").
+		WithArgs("pending").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery("SELECT (.+) FROM track_lyrics WHERE status = \\$1 ORDER BY updated_at DESC, track_id LIMIT \\$2 OFFSET \\$3").
+		WithArgs("pending", 1, 2).
+		WillReturnRows(sqlmock.NewRows(lyricsRowColumns).
+			AddRow(uuid.New(), "", false, "Текст", "Текст", "upload", "pending", nil, nil, nil, now, now))
+
+	page, err := repo.List(context.Background(), models.LyricsFilter{Status: "pending", Limit: 1, Offset: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	if assert.Len(t, page.Items, 1) {
+		assert.Equal(t, models.LyricsPending, page.Items[0].Status)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLyricsRepository_Search(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewLyricsRepository(db)
+
+	trackID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	columns := append(append([]string{}, trackRowColumns...), "snippet", "rank")
+
+	// Ищутся только одобренные тексты треков не из корзины
+	mock.ExpectQuery("websearch_to_tsquery\\('simple', \\$1\\) q\\s+WHERE status = 'approved' AND search_vector @@ q(.+)WHERE tracks.deleted_at IS NULL").
+		WithArgs("группа крови", 20, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(trackID, "Группа крови", 285, "tracks/1.mp3", nil, "Кино", "", now, now, 7,
+				"{Кино}", 0, 0, false, "", "", nil, nil, nil, "«Группа» «крови» на рукаве", 0.6))
+
+	matches, err := repo.Search(context.Background(), "группа крови", 20, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, trackID, matches[0].Track.ID)
+		assert.Equal(t, "«Группа» «крови» на рукаве", matches[0].Snippet)
+		assert.InDelta(t, 0.6, matches[0].Rank, 1e-9)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
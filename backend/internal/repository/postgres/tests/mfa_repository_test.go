@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFARepository_GetTOTP(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewMFARepository(db)
+
+	userID := uuid.New()
+	columns := []string{"user_id", "secret", "last_used_step", "confirmed_at", "created_at"}
+
+	// Подтвержденный секрет
+	t.Run("confirmed", func(t *testing.T) {
+		confirmedAt := time.Now()
+		mock.ExpectQuery("SELECT (.+) FROM user_totp WHERE user_id = \\$1").
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(userID, "SECRET", int64(42), confirmedAt, confirmedAt))
+
+		credential, err := repo.GetTOTP(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.True(t, credential.Confirmed())
+		assert.Equal(t, int64(42), credential.LastUsedStep)
+	})
+
+	// Подключение не завершено
+	t.Run("pending", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM user_totp WHERE user_id = \\$1").
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(userID, "SECRET", int64(0), nil, time.Now()))
+
+		credential, err := repo.GetTOTP(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.False(t, credential.Confirmed())
+	})
+
+	// TOTP не подключался
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM user_totp WHERE user_id = \\$1").
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		credential, err := repo.GetTOTP(context.Background(), userID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Nil(t, credential)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepository_UseTOTPStep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewMFARepository(db)
+
+	userID := uuid.New()
+
+	// Код нового интервала принимается
+	t.Run("new step", func(t *testing.T) {
+		mock.ExpectExec("UPDATE user_totp SET last_used_step = \\$2 WHERE user_id = \\$1 AND last_used_step < \\$2").
+			WithArgs(userID, int64(100)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		used, err := repo.UseTOTPStep(context.Background(), userID, 100)
+		assert.NoError(t, err)
+		assert.True(t, used)
+	})
+
+	// Повторное использование кода того же интервала
+	t.Run("replay", func(t *testing.T) {
+		mock.ExpectExec("UPDATE user_totp SET last_used_step = \\$2 WHERE user_id = \\$1 AND last_used_step < \\$2").
+			WithArgs(userID, int64(100)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		used, err := repo.UseTOTPStep(context.Background(), userID, 100)
+		assert.NoError(t, err)
+		assert.False(t, used)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepository_ReplaceRecoveryCodes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewMFARepository(db)
+
+	userID := uuid.New()
+
+	// Старые коды удаляются и заменяются новыми в одной транзакции
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM recovery_codes WHERE user_id = \\$1").
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectExec("INSERT INTO recovery_codes").
+		WithArgs(userID, "hash-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO recovery_codes").
+		WithArgs(userID, "hash-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.ReplaceRecoveryCodes(context.Background(), userID, []string{"hash-1", "hash-2"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepository_RecordFailedAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewMFARepository(db)
+
+	// Счетчик увеличивается, исчерпанный вход удаляется
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE mfa_challenges SET attempts = attempts \\+ 1 WHERE token_hash = \\$1").
+		WithArgs("hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM mfa_challenges WHERE token_hash = \\$1 AND attempts >= \\$2").
+		WithArgs("hash", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.RecordFailedAttempt(context.Background(), "hash", 5)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
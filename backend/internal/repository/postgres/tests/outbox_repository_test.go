@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var outboxColumns = []string{
+	"id", "event_type", "aggregate_id", "payload", "occurred_at", "attempts", "next_attempt_at", "last_error",
+}
+
+func TestOutboxRepository_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewOutboxRepository(db)
+
+	trackID := uuid.New()
+	event, err := models.NewDomainEvent(models.EventTrackDeleted, trackID, models.TrackDeletedPayload{
+		TrackID:  trackID,
+		FilePath: "ab/cd/track.mp3",
+	})
+	assert.NoError(t, err)
+
+	// Успешная запись события
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs(event.ID, models.EventTrackDeleted, trackID, []byte(event.Payload), event.OccurredAt, event.NextAttemptAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Add(context.Background(), event)
+		assert.NoError(t, err)
+	})
+
+	// Ошибка базы данных
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WillReturnError(errors.New("db error"))
+
+		err := repo.Add(context.Background(), event)
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOutboxRepository_ClaimPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewOutboxRepository(db)
+
+	first := uuid.New()
+	second := uuid.New()
+	now := time.Now()
+
+	// События возвращаются в порядке возникновения
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows(outboxColumns).
+			AddRow(second, models.EventPlaybackRecorded, uuid.New(), []byte(`{}`), now, 2, now, "handler error").
+			AddRow(first, models.EventTrackUploaded, uuid.New(), []byte(`{}`), now.Add(-time.Second), 0, now, "")
+
+		mock.ExpectQuery("UPDATE outbox_events SET locked_until (.+) FOR UPDATE SKIP LOCKED (.+) RETURNING").
+			WithArgs(10, int64(60000)).
+			WillReturnRows(rows)
+
+		events, err := repo.ClaimPending(context.Background(), 10, time.Minute)
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, first, events[0].ID)
+		assert.Equal(t, second, events[1].ID)
+		assert.Equal(t, 2, events[1].Attempts)
+		assert.Equal(t, "handler error", events[1].LastError)
+	})
+
+	// Ошибка базы данных
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE outbox_events SET locked_until").
+			WillReturnError(errors.New("db error"))
+
+		events, err := repo.ClaimPending(context.Background(), 10, time.Minute)
+		assert.Error(t, err)
+		assert.Nil(t, events)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOutboxRepository_MarkDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewOutboxRepository(db)
+
+	eventID := uuid.New()
+	nextAttemptAt := time.Now().Add(time.Minute)
+
+	// Успешная доставка
+	t.Run("processed", func(t *testing.T) {
+		mock.ExpectExec("UPDATE outbox_events SET processed_at = NOW\\(\\)(.+)WHERE id = \\$1").
+			WithArgs(eventID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.MarkProcessed(context.Background(), eventID))
+	})
+
+	// Ошибка доставки откладывает следующую попытку
+	t.Run("failed", func(t *testing.T) {
+		mock.ExpectExec("UPDATE outbox_events SET attempts = attempts \\+ 1, last_error = \\$2, next_attempt_at = \\$3").
+			WithArgs(eventID, "handler error", nextAttemptAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.MarkFailed(context.Background(), eventID, "handler error", nextAttemptAt))
+	})
+
+	// Исчерпаны все попытки
+	t.Run("dead", func(t *testing.T) {
+		mock.ExpectExec("UPDATE outbox_events SET attempts = attempts \\+ 1, last_error = \\$2, failed_at = NOW\\(\\)").
+			WithArgs(eventID, "handler error").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.MarkDead(context.Background(), eventID, "handler error"))
+	})
+
+	// Очистка доставленных событий
+	t.Run("delete_processed", func(t *testing.T) {
+		before := time.Now().Add(-time.Hour)
+		mock.ExpectExec("DELETE FROM outbox_events WHERE processed_at < \\$1").
+			WithArgs(before).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+
+		deleted, err := repo.DeleteProcessed(context.Background(), before)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), deleted)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOutboxRepository_MarkHandled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewOutboxRepository(db)
+	eventID := uuid.New()
+
+	// Первая обработка события
+	t.Run("first", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO outbox_handled_events \\(event_id, handler\\)(.+)ON CONFLICT DO NOTHING").
+			WithArgs(eventID, "track.play_count").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		first, err := repo.MarkHandled(context.Background(), eventID, "track.play_count")
+		assert.NoError(t, err)
+		assert.True(t, first)
+	})
+
+	// Повторная доставка: отметка уже есть
+	t.Run("repeated", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO outbox_handled_events").
+			WithArgs(eventID, "track.play_count").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		first, err := repo.MarkHandled(context.Background(), eventID, "track.play_count")
+		assert.NoError(t, err)
+		assert.False(t, first)
+	})
+
+	// Ошибка базы данных
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO outbox_handled_events").
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.MarkHandled(context.Background(), eventID, "track.play_count")
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
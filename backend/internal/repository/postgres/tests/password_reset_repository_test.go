@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewPasswordResetRepository(db)
+
+	token := &models.PasswordResetToken{
+		TokenHash: "hash",
+		UserID:    uuid.New(),
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mock.ExpectExec("INSERT INTO password_reset_tokens").
+		WithArgs(token.TokenHash, token.UserID, token.CreatedAt, token.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Create(context.Background(), token)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPasswordResetRepository_Consume(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewPasswordResetRepository(db)
+
+	userID := uuid.New()
+
+	// Действующий токен помечается использованным
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE password_reset_tokens SET used_at = NOW\\(\\)").
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+
+		foundID, err := repo.Consume(context.Background(), "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, userID, foundID)
+	})
+
+	// Использованный или истекший токен не принимается
+	t.Run("used_or_expired", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE password_reset_tokens SET used_at = NOW\\(\\)").
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+		foundID, err := repo.Consume(context.Background(), "hash")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Equal(t, uuid.Nil, foundID)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var queueColumns = []string{
+	"id", "title", "duration", "file_path", "album_id", "artist_name", "cover_url", "added_date", "updated_at", "play_count",
+}
+
+func TestPlaybackRepository_GetState(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewPlaybackRepository(db)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+	trackID := uuid.New()
+	now := time.Now()
+
+	// Успешный сценарий
+	t.Run("success", func(t *testing.T) {
+		stateRows := sqlmock.NewRows([]string{
+			"user_id", "current_index", "position_ms", "is_playing", "shuffle", "shuffle_order",
+			"repeat_mode", "active_device_id", "updated_at",
+		}).AddRow(userID, 0, 1500, true, false, "{}", "all", deviceID, now)
+
+		mock.ExpectQuery("SELECT (.+) FROM playback_states WHERE user_id = \\$1").
+			WithArgs(userID).
+			WillReturnRows(stateRows)
+
+		queueRows := sqlmock.NewRows(queueColumns).
+			AddRow(trackID, "Track", 180, "ab/cd/track.mp3", nil, "Artist", "", now, now, 0)
+
+		mock.ExpectQuery("SELECT (.+) FROM playback_queue pq (.+) WHERE pq.user_id = \\$1").
+			WithArgs(userID).
+			WillReturnRows(queueRows)
+
+		state, err := repo.GetState(context.Background(), userID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.RepeatAll, state.RepeatMode)
+		assert.Equal(t, deviceID, state.ActiveDeviceID)
+		assert.Equal(t, 1500, state.PositionMs)
+		assert.Len(t, state.Queue, 1)
+		assert.Equal(t, trackID, state.Queue[0].ID)
+		assert.Equal(t, uuid.Nil, state.Queue[0].AlbumID)
+	})
+
+	// Состояние еще не создано
+	t.Run("not_found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM playback_states WHERE user_id = \\$1").
+			WithArgs(userID).
+			WillReturnError(sql.ErrNoRows)
+
+		state, err := repo.GetState(context.Background(), userID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Nil(t, state)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPlaybackRepository_SaveState(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewPlaybackRepository(db)
+
+	state := &models.PlaybackState{
+		UserID:       uuid.New(),
+		ShuffleOrder: []int{1, 0},
+		Shuffle:      true,
+		RepeatMode:   models.RepeatOff,
+		UpdatedAt:    time.Now(),
+	}
+
+	// Успешное сохранение, активное устройство не задано
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO playback_states").
+			WithArgs(state.UserID, 0, 0, false, true, sqlmock.AnyArg(), "off", nil, state.UpdatedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.SaveState(context.Background(), state)
+		assert.NoError(t, err)
+	})
+
+	// Ошибка при сохранении
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO playback_states").
+			WillReturnError(errors.New("db error"))
+
+		err := repo.SaveState(context.Background(), state)
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPlaybackRepository_ReplaceQueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewPlaybackRepository(db)
+
+	userID := uuid.New()
+	trackIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	// Успешная замена очереди
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM playback_queue WHERE user_id = \\$1").
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		for position, trackID := range trackIDs {
+			mock.ExpectExec("INSERT INTO playback_queue").
+				WithArgs(userID, position, trackID).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+		mock.ExpectCommit()
+
+		err := repo.ReplaceQueue(context.Background(), userID, trackIDs)
+		assert.NoError(t, err)
+	})
+
+	// Ошибка вставки откатывает транзакцию
+	t.Run("rollback", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM playback_queue WHERE user_id = \\$1").
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO playback_queue").
+			WithArgs(userID, 0, trackIDs[0]).
+			WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		err := repo.ReplaceQueue(context.Background(), userID, trackIDs)
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
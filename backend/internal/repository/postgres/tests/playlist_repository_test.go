@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"errors"
 	"music-service/internal/models"
 	"music-service/internal/repository/postgres"
@@ -43,7 +44,7 @@ func TestPlaylistRepository_FindByID(t *testing.T) {
 			WithArgs(playlistID).
 			WillReturnRows(rows)
 
-		foundPlaylist, err := repo.FindByID(playlistID)
+		foundPlaylist, err := repo.FindByID(context.Background(), playlistID)
 		assert.NoError(t, err)
 		assert.Equal(t, playlist.ID, foundPlaylist.ID)
 		assert.Equal(t, playlist.Name, foundPlaylist.Name)
@@ -57,7 +58,7 @@ func TestPlaylistRepository_FindByID(t *testing.T) {
 			WithArgs(playlistID).
 			WillReturnError(errors.New("db error"))
 
-		foundPlaylist, err := repo.FindByID(playlistID)
+		foundPlaylist, err := repo.FindByID(context.Background(), playlistID)
 		assert.Error(t, err)
 		assert.Nil(t, foundPlaylist)
 	})
@@ -96,7 +97,7 @@ func TestPlaylistRepository_Save(t *testing.T) {
 			WithArgs(playlist.ID, playlist.Name, playlist.Description, playlist.UserID, playlist.CoverURL, playlist.CreatedDate, playlist.UpdatedAt).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		err := repo.Save(playlist)
+		err := repo.Save(context.Background(), playlist)
 		assert.NoError(t, err)
 	})
 
@@ -106,7 +107,7 @@ func TestPlaylistRepository_Save(t *testing.T) {
 			WithArgs(playlist.ID, playlist.Name, playlist.Description, playlist.UserID, playlist.CoverURL, playlist.CreatedDate, playlist.UpdatedAt).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.Save(playlist)
+		err := repo.Save(context.Background(), playlist)
 		assert.Error(t, err)
 	})
 
@@ -126,45 +127,73 @@ func TestPlaylistRepository_Delete(t *testing.T) {
 	repo := postgres.NewPlaylistRepository(db)
 
 	playlistID := uuid.New()
+	deletedAt := time.Now()
 
-	// Успешное удаление
+	// Плейлист помещается в корзину, связи с треками сохраняются
 	t.Run("success", func(t *testing.T) {
-		// Сначала удаляются связи с треками
-		mock.ExpectExec("DELETE FROM playlist_tracks WHERE playlist_id = ?").
-			WithArgs(playlistID).
+		mock.ExpectExec("UPDATE playlists SET deleted_at = \\$2 WHERE id = \\$1 AND deleted_at IS NULL").
+			WithArgs(playlistID, deletedAt).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		// Затем удаляется сам плейлист
-		mock.ExpectExec("DELETE FROM playlists WHERE id = ?").
-			WithArgs(playlistID).
-			WillReturnResult(sqlmock.NewResult(0, 1))
-
-		err := repo.Delete(playlistID)
+		err := repo.Delete(context.Background(), playlistID, deletedAt)
 		assert.NoError(t, err)
 	})
 
-	// Ошибка при удалении связей
-	t.Run("error_tracks", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM playlist_tracks WHERE playlist_id = ?").
-			WithArgs(playlistID).
+	// Плейлист не найден или уже в корзине
+	t.Run("not_found", func(t *testing.T) {
+		mock.ExpectExec("UPDATE playlists SET deleted_at").
+			WithArgs(playlistID, deletedAt).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Delete(context.Background(), playlistID, deletedAt)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	// Ошибка при удалении плейлиста
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectExec("UPDATE playlists SET deleted_at").
+			WithArgs(playlistID, deletedAt).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.Delete(playlistID)
+		err := repo.Delete(context.Background(), playlistID, deletedAt)
 		assert.Error(t, err)
 	})
 
-	// Ошибка при удалении плейлиста
-	t.Run("error_playlist", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM playlist_tracks WHERE playlist_id = ?").
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPlaylistRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewPlaylistRepository(db)
+
+	playlistID := uuid.New()
+
+	// Успешное восстановление
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE playlists SET deleted_at = NULL WHERE id = \\$1 AND deleted_at IS NOT NULL").
 			WithArgs(playlistID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		mock.ExpectExec("DELETE FROM playlists WHERE id = ?").
+		err := repo.Restore(context.Background(), playlistID)
+		assert.NoError(t, err)
+	})
+
+	// Плейлиста нет в корзине
+	t.Run("not_found", func(t *testing.T) {
+		mock.ExpectExec("UPDATE playlists SET deleted_at = NULL").
 			WithArgs(playlistID).
-			WillReturnError(errors.New("db error"))
+			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := repo.Delete(playlistID)
-		assert.Error(t, err)
+		err := repo.Restore(context.Background(), playlistID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
 	})
 
 	// Проверка, что все ожидаемые запросы были выполнены
@@ -214,7 +243,7 @@ func TestPlaylistRepository_GetTracks(t *testing.T) {
 			WithArgs(playlistID).
 			WillReturnRows(rows)
 
-		foundTracks, err := repo.GetTracks(playlistID)
+		foundTracks, err := repo.GetTracks(context.Background(), playlistID)
 		assert.NoError(t, err)
 		assert.Len(t, foundTracks, 2)
 		assert.Equal(t, tracks[0].Title, foundTracks[0].Title)
@@ -227,7 +256,7 @@ func TestPlaylistRepository_GetTracks(t *testing.T) {
 			WithArgs(playlistID).
 			WillReturnError(errors.New("db error"))
 
-		foundTracks, err := repo.GetTracks(playlistID)
+		foundTracks, err := repo.GetTracks(context.Background(), playlistID)
 		assert.Error(t, err)
 		assert.Nil(t, foundTracks)
 	})
@@ -279,7 +308,7 @@ func TestPlaylistRepository_GetUserPlaylists(t *testing.T) {
 			WithArgs(userID).
 			WillReturnRows(rows)
 
-		foundPlaylists, err := repo.GetUserPlaylists(userID)
+		foundPlaylists, err := repo.GetUserPlaylists(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Len(t, foundPlaylists, 2)
 		assert.Equal(t, playlists[0].Name, foundPlaylists[0].Name)
@@ -292,7 +321,7 @@ func TestPlaylistRepository_GetUserPlaylists(t *testing.T) {
 			WithArgs(userID).
 			WillReturnError(errors.New("db error"))
 
-		foundPlaylists, err := repo.GetUserPlaylists(userID)
+		foundPlaylists, err := repo.GetUserPlaylists(context.Background(), userID)
 		assert.Error(t, err)
 		assert.Nil(t, foundPlaylists)
 	})
@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionRepository_CreateSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewSessionRepository(db)
+
+	now := time.Now()
+	session := &models.Session{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(time.Hour),
+		Device:     models.Device{ID: uuid.New(), Name: "Firefox", Type: "web"},
+		UserAgent:  "Mozilla/5.0",
+		IPAddress:  "192.0.2.1",
+	}
+
+	mock.ExpectExec("INSERT INTO sessions").
+		WithArgs(session.ID, session.UserID, session.CreatedAt, session.LastSeenAt, session.ExpiresAt,
+			session.Device.ID, session.Device.Name, session.Device.Type, session.UserAgent, session.IPAddress).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.CreateSession(context.Background(), session)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_ListActiveSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewSessionRepository(db)
+
+	userID := uuid.New()
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "user_id", "created_at", "last_seen_at", "expires_at",
+		"device_id", "device_name", "device_type", "user_agent", "ip_address"}).
+		AddRow(uuid.New(), userID, now, now, now.Add(time.Hour), uuid.New(), "Firefox", "web", "Mozilla/5.0", "192.0.2.1").
+		AddRow(uuid.New(), userID, now, now.Add(-time.Hour), now.Add(time.Hour), uuid.New(), "iPhone", "mobile", "App/1.0", "192.0.2.2")
+
+	mock.ExpectQuery("SELECT (.+) FROM sessions WHERE user_id = \\$1 AND revoked_at IS NULL").
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	sessions, err := repo.ListActiveSessions(context.Background(), userID)
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 2) {
+		assert.Equal(t, "Mozilla/5.0", sessions[0].UserAgent)
+		assert.Equal(t, "192.0.2.2", sessions[1].IPAddress)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_PurgeExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewSessionRepository(db)
+
+	before := time.Now().Add(-24 * time.Hour)
+
+	// Сессии и истекшие отзывы удаляются в одной транзакции
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM sessions WHERE expires_at < \\$1 OR revoked_at < \\$1").
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM revoked_sessions WHERE expires_at < NOW\\(\\)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deleted, err := repo.PurgeExpired(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_GetRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewSessionRepository(db)
+
+	sessionID := uuid.New()
+	createdAt := time.Now()
+	expiresAt := createdAt.Add(time.Hour)
+
+	// Использованный токен возвращается вместе с временем использования
+	t.Run("success", func(t *testing.T) {
+		usedAt := createdAt.Add(time.Minute)
+		rows := sqlmock.NewRows([]string{"token_hash", "session_id", "created_at", "expires_at", "used_at"}).
+			AddRow("hash", sessionID, createdAt, expiresAt, usedAt)
+
+		mock.ExpectQuery("SELECT (.+) FROM refresh_tokens WHERE token_hash = \\$1").
+			WithArgs("hash").
+			WillReturnRows(rows)
+
+		token, err := repo.GetRefreshToken(context.Background(), "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, sessionID, token.SessionID)
+		if assert.NotNil(t, token.UsedAt) {
+			assert.Equal(t, usedAt, *token.UsedAt)
+		}
+	})
+
+	// Токен не найден
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM refresh_tokens WHERE token_hash = \\$1").
+			WithArgs("missing").
+			WillReturnRows(sqlmock.NewRows([]string{"token_hash", "session_id", "created_at", "expires_at", "used_at"}))
+
+		token, err := repo.GetRefreshToken(context.Background(), "missing")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Nil(t, token)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_MarkRefreshTokenUsed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewSessionRepository(db)
+
+	// Токен помечен впервые
+	t.Run("first use", func(t *testing.T) {
+		mock.ExpectExec("UPDATE refresh_tokens SET used_at = NOW\\(\\) WHERE token_hash = \\$1 AND used_at IS NULL").
+			WithArgs("hash").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		marked, err := repo.MarkRefreshTokenUsed(context.Background(), "hash")
+		assert.NoError(t, err)
+		assert.True(t, marked)
+	})
+
+	// Токен уже использован — повторное предъявление
+	t.Run("reuse", func(t *testing.T) {
+		mock.ExpectExec("UPDATE refresh_tokens SET used_at = NOW\\(\\) WHERE token_hash = \\$1 AND used_at IS NULL").
+			WithArgs("hash").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		marked, err := repo.MarkRefreshTokenUsed(context.Background(), "hash")
+		assert.NoError(t, err)
+		assert.False(t, marked)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_RevokeSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewSessionRepository(db)
+
+	sessionID := uuid.New()
+	until := time.Now().Add(15 * time.Minute)
+
+	// Сессия отзывается и попадает в список отозванных в одной транзакции
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE sessions SET revoked_at = NOW\\(\\) WHERE id = \\$1").
+		WithArgs(sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO revoked_sessions").
+		WithArgs(sessionID, until).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.RevokeSession(context.Background(), sessionID, until)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,294 @@
+package tests
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var trackRowColumns = []string{"id", "title", "duration", "file_path", "album_id", "artist_name", "cover_url", "added_date",
+	"updated_at", "play_count", "artists", "track_number", "disc_number", "explicit", "isrc", "lyrics_url", "release_date",
+	"integrated_lufs", "true_peak_dbtp"}
+
+func TestTrackRepository_FindByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTrackRepository(db, t.TempDir())
+
+	trackID := uuid.New()
+	albumID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	releaseDate := time.Date(1988, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+	// Трек со всеми метаданными
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows(trackRowColumns).
+			AddRow(trackID, "Группа крови", 285, "tracks/1.mp3", albumID, "Кино", "", now, now, 7,
+				"{Кино,\"Виктор Цой\"}", 1, 1, false, "SUA108800001", "", releaseDate, -9.5, -0.3)
+
+		mock.ExpectQuery("SELECT (.+) FROM tracks WHERE id = \\$1 AND deleted_at IS NULL").
+			WithArgs(trackID).
+			WillReturnRows(rows)
+
+		track, err := repo.FindByID(context.Background(), trackID)
+		assert.NoError(t, err)
+		assert.Equal(t, albumID, track.AlbumID)
+		assert.Equal(t, []string{"Кино", "Виктор Цой"}, track.Artists)
+		assert.Equal(t, 1, track.TrackNumber)
+		assert.Equal(t, "SUA108800001", track.ISRC)
+		if assert.NotNil(t, track.ReleaseDate) {
+			assert.True(t, releaseDate.Equal(*track.ReleaseDate))
+		}
+		if assert.NotNil(t, track.Loudness) {
+			assert.InDelta(t, -8.5, track.Loudness.Gain(), 1e-9)
+		}
+	})
+
+	// Трек без альбома и даты выхода
+	t.Run("без необязательных полей", func(t *testing.T) {
+		rows := sqlmock.NewRows(trackRowColumns).
+			AddRow(trackID, "Кукушка", 396, "tracks/2.mp3", nil, "Кино", "", now, now, 0,
+				"{Кино}", 0, 0, true, "", "", nil, nil, nil)
+
+		mock.ExpectQuery("SELECT (.+) FROM tracks").
+			WithArgs(trackID).
+			WillReturnRows(rows)
+
+		track, err := repo.FindByID(context.Background(), trackID)
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, track.AlbumID)
+		assert.Nil(t, track.ReleaseDate)
+		assert.Nil(t, track.Loudness)
+		assert.True(t, track.Explicit)
+	})
+
+	// Трека нет
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM tracks").
+			WithArgs(trackID).
+			WillReturnRows(sqlmock.NewRows(trackRowColumns))
+
+		track, err := repo.FindByID(context.Background(), trackID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Nil(t, track)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTrackRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTrackRepository(db, t.TempDir())
+
+	version := time.Now().UTC().Truncate(time.Microsecond)
+	track := &models.Track{
+		ID:         uuid.New(),
+		Title:      "Звезда по имени Солнце",
+		ArtistName: "Кино",
+		Duration:   225,
+		FilePath:   "tracks/3.mp3",
+		AddedDate:  version,
+		UpdatedAt:  version.Add(time.Second),
+		ISRC:       "SUA108900002",
+	}
+	args := []driver.Value{track.ID, track.Title, track.Duration, nil, track.ArtistName, "", track.UpdatedAt,
+		pq.StringArray{"Кино"}, 0, 0, false, track.ISRC, "", nil, version}
+
+	// Версия совпала
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE tracks (.+) WHERE id = \\$1 AND updated_at = \\$15 AND deleted_at IS NULL").
+			WithArgs(args...).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.Update(context.Background(), track, version))
+	})
+
+	// Трек изменили после чтения
+	t.Run("stale version", func(t *testing.T) {
+		mock.ExpectExec("UPDATE tracks").
+			WithArgs(args...).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.Update(context.Background(), track, version), models.ErrNotFound)
+	})
+
+	// Ошибка базы
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectExec("UPDATE tracks").
+			WithArgs(args...).
+			WillReturnError(errors.New("db error"))
+
+		assert.Error(t, repo.Update(context.Background(), track, version))
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// Update не перезаписывает счетчик прослушиваний, путь к файлу и громкость:
+// копия трека у вызывающего может не знать о параллельных прослушиваниях
+func TestTrackRepository_UpdateKeepsPlayCount(t *testing.T) {
+	matcher := sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+		for _, column := range []string{"play_count", "file_path", "integrated_lufs", "true_peak_dbtp"} {
+			if strings.Contains(actualSQL, column) {
+				return fmt.Errorf("query must not assign %s: %s", column, actualSQL)
+			}
+		}
+		return sqlmock.QueryMatcherRegexp.Match(expectedSQL, actualSQL)
+	})
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTrackRepository(db, t.TempDir())
+
+	version := time.Now().UTC().Truncate(time.Microsecond)
+	track := &models.Track{
+		ID:         uuid.New(),
+		Title:      "Группа крови",
+		ArtistName: "Кино",
+		FilePath:   "tracks/1.mp3",
+		PlayCount:  7,
+		Loudness:   &models.Loudness{IntegratedLUFS: -14, TruePeakDBTP: -1},
+		UpdatedAt:  version.Add(time.Second),
+	}
+	mock.ExpectExec("UPDATE tracks").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Update(context.Background(), track, version))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTrackRepository_SetLoudness(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTrackRepository(db, t.TempDir())
+
+	trackID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// Громкость измерена
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE tracks SET integrated_lufs = \\$2, true_peak_dbtp = \\$3, updated_at = \\$4\\s+WHERE id = \\$1 AND deleted_at IS NULL").
+			WithArgs(trackID, -14.2, -1.1, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		loudness := &models.Loudness{IntegratedLUFS: -14.2, TruePeakDBTP: -1.1}
+		assert.NoError(t, repo.SetLoudness(context.Background(), trackID, loudness, now))
+	})
+
+	// nil стирает громкость
+	t.Run("clear", func(t *testing.T) {
+		mock.ExpectExec("UPDATE tracks SET integrated_lufs").
+			WithArgs(trackID, nil, nil, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.SetLoudness(context.Background(), trackID, nil, now))
+	})
+
+	// Трека нет или он в корзине
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec("UPDATE tracks SET integrated_lufs").
+			WithArgs(trackID, nil, nil, now).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.SetLoudness(context.Background(), trackID, nil, now), models.ErrNotFound)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTrackRepository_Merge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTrackRepository(db, t.TempDir())
+
+	targetID, sourceID := uuid.New(), uuid.New()
+
+	// Плейлисты, история, очереди и счетчик прослушиваний переходят к цели
+	// в одной транзакции
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO playlist_tracks \\(playlist_id, track_id, added_at\\)\\s+SELECT playlist_id, \\$1, added_at FROM playlist_tracks WHERE track_id = \\$2\\s+ON CONFLICT \\(playlist_id, track_id\\) DO NOTHING").
+			WithArgs(targetID, sourceID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("DELETE FROM playlist_tracks WHERE track_id = \\$1").
+			WithArgs(sourceID).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec("UPDATE listening_history SET track_id = \\$1 WHERE track_id = \\$2").
+			WithArgs(targetID, sourceID).
+			WillReturnResult(sqlmock.NewResult(0, 12))
+		mock.ExpectExec("UPDATE playback_queue SET track_id = \\$1 WHERE track_id = \\$2").
+			WithArgs(targetID, sourceID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE tracks SET play_count = play_count \\+ \\(SELECT play_count FROM tracks WHERE id = \\$2\\) WHERE id = \\$1").
+			WithArgs(targetID, sourceID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		// Счетчик источника обнуляется: после восстановления из корзины
+		// прослушивания не учитываются дважды
+		mock.ExpectExec("UPDATE tracks SET play_count = 0 WHERE id = \\$1").
+			WithArgs(sourceID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Merge(context.Background(), targetID, sourceID))
+	})
+
+	// Ошибка откатывает уже перенесенные ссылки
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO playlist_tracks").
+			WithArgs(targetID, sourceID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("DELETE FROM playlist_tracks").
+			WithArgs(sourceID).
+			WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		assert.Error(t, repo.Merge(context.Background(), targetID, sourceID))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
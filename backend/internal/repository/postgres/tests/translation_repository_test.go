@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var translationColumns = []string{"track_id", "locale", "field", "value", "updated_at"}
+
+func TestTranslationRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTranslationRepository(db)
+
+	trackID := uuid.New()
+	updatedAt := time.Now()
+
+	// Строки одного языка собираются в один перевод
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT track_id, locale, field, value, updated_at FROM track_translations WHERE track_id = \\$1").
+			WithArgs(trackID).
+			WillReturnRows(sqlmock.NewRows(translationColumns).
+				AddRow(trackID, "en", "artist_name", "Kino", updatedAt).
+				AddRow(trackID, "en", "title", "Blood Type", updatedAt).
+				AddRow(trackID, "ru-Latn", "title", "Gruppa krovi", updatedAt))
+
+		translations, err := repo.List(context.Background(), models.TranslatableTrack, trackID)
+		assert.NoError(t, err)
+		assert.Len(t, translations, 2)
+		assert.Equal(t, "en", translations[0].Locale)
+		assert.Equal(t, map[string]string{"title": "Blood Type", "artist_name": "Kino"}, translations[0].Fields)
+		assert.Equal(t, "ru-Latn", translations[1].Locale)
+		assert.Equal(t, "Gruppa krovi", translations[1].Fields["title"])
+	})
+
+	// Неизвестный тип объекта не доходит до базы
+	t.Run("unknown_type", func(t *testing.T) {
+		_, err := repo.List(context.Background(), "playlist", trackID)
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTranslationRepository_FindByEntities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTranslationRepository(db)
+
+	firstID := uuid.New()
+	secondID := uuid.New()
+	updatedAt := time.Now()
+
+	// Переводы группируются по объектам
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT genre_id, locale, field, value, updated_at FROM genre_translations WHERE genre_id = ANY\\(\\$1::uuid\\[\\]\\) AND locale = ANY\\(\\$2\\)").
+			WithArgs(pq.StringArray{firstID.String(), secondID.String()}, pq.StringArray{"en-US", "en"}).
+			WillReturnRows(sqlmock.NewRows([]string{"genre_id", "locale", "field", "value", "updated_at"}).
+				AddRow(firstID, "en", "name", "Rock", updatedAt).
+				AddRow(secondID, "en", "name", "Jazz", updatedAt))
+
+		result, err := repo.FindByEntities(context.Background(), models.TranslatableGenre,
+			[]uuid.UUID{firstID, secondID}, []string{"en-US", "en"})
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "Rock", result[firstID][0].Fields["name"])
+		assert.Equal(t, "Jazz", result[secondID][0].Fields["name"])
+	})
+
+	// Без языков запрос не выполняется
+	t.Run("no_locales", func(t *testing.T) {
+		result, err := repo.FindByEntities(context.Background(), models.TranslatableGenre, []uuid.UUID{firstID}, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTranslationRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTranslationRepository(db)
+
+	albumID := uuid.New()
+	translation := &models.Translation{
+		Locale:    "en",
+		Fields:    map[string]string{"title": "Star Called Sun", "artist": "Kino"},
+		UpdatedAt: time.Now(),
+	}
+
+	// Старые поля удаляются, новые вставляются в одной транзакции
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM album_translations WHERE album_id = \\$1 AND locale = \\$2").
+			WithArgs(albumID, "en").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO album_translations").
+			WithArgs(albumID, "en", "artist", "Kino", translation.UpdatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO album_translations").
+			WithArgs(albumID, "en", "title", "Star Called Sun", translation.UpdatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.Save(context.Background(), models.TranslatableAlbum, albumID, translation)
+		assert.NoError(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTranslationRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTranslationRepository(db)
+
+	trackID := uuid.New()
+
+	// Перевод удален
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM track_translations WHERE track_id = \\$1 AND locale = \\$2").
+			WithArgs(trackID, "en").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		err := repo.Delete(context.Background(), models.TranslatableTrack, trackID, "en")
+		assert.NoError(t, err)
+	})
+
+	// Перевода нет
+	t.Run("not_found", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM track_translations").
+			WithArgs(trackID, "de").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Delete(context.Background(), models.TranslatableTrack, trackID, "de")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"music-service/internal/models"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var trashColumns = []string{"type", "id", "title", "owner_id", "parent_id", "deleted_at"}
+
+func TestTrashRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTrashRepository(db)
+
+	ownerID := uuid.New()
+	playlistID := uuid.New()
+	deletedAt := time.Now()
+
+	// Выборка плейлистов одного владельца
+	t.Run("success_with_filters", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM (.+) trash WHERE type = \\$1 AND owner_id = \\$2").
+			WithArgs(models.TrashPlaylist, ownerID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT type, id, title, owner_id, parent_id, deleted_at FROM (.+) ORDER BY deleted_at DESC, id LIMIT \\$3 OFFSET \\$4").
+			WithArgs(models.TrashPlaylist, ownerID, 50, 0).
+			WillReturnRows(sqlmock.NewRows(trashColumns).
+				AddRow(models.TrashPlaylist, playlistID, "Road trip", ownerID, nil, deletedAt))
+
+		page, err := repo.List(context.Background(), models.TrashFilter{
+			Type:    models.TrashPlaylist,
+			OwnerID: &ownerID,
+			Limit:   50,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Len(t, page.Items, 1)
+		assert.Equal(t, playlistID, page.Items[0].ID)
+		assert.Equal(t, ownerID, *page.Items[0].OwnerID)
+		assert.Nil(t, page.Items[0].ParentID)
+	})
+
+	// Ошибка при подсчете
+	t.Run("count_error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM").
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.List(context.Background(), models.TrashFilter{Limit: 50})
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTrashRepository_Find(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTrashRepository(db)
+
+	trackID := uuid.New()
+	albumID := uuid.New()
+	deletedAt := time.Now()
+
+	// Трек из альбома
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) trash WHERE type = \\$1 AND id = \\$2").
+			WithArgs(models.TrashTrack, trackID).
+			WillReturnRows(sqlmock.NewRows(trashColumns).
+				AddRow(models.TrashTrack, trackID, "Intro", nil, albumID, deletedAt))
+
+		item, err := repo.Find(context.Background(), models.TrashTrack, trackID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Intro", item.Title)
+		assert.Equal(t, albumID, *item.ParentID)
+		assert.Nil(t, item.OwnerID)
+	})
+
+	// Объекта нет в корзине
+	t.Run("not_found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) trash WHERE type = \\$1 AND id = \\$2").
+			WithArgs(models.TrashTrack, trackID).
+			WillReturnRows(sqlmock.NewRows(trashColumns))
+
+		_, err := repo.Find(context.Background(), models.TrashTrack, trackID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTrashRepository_PurgeTracks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTrashRepository(db)
+
+	trackID := uuid.New()
+	before := time.Now().Add(-720 * time.Hour)
+
+	// Удаленные треки возвращаются вместе с путями к файлам
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("DELETE FROM tracks WHERE deleted_at < \\$1 RETURNING id, title, file_path").
+			WithArgs(before).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "file_path"}).
+				AddRow(trackID, "Intro", "uploads/intro.mp3"))
+
+		tracks, err := repo.PurgeTracks(context.Background(), before)
+		assert.NoError(t, err)
+		assert.Len(t, tracks, 1)
+		assert.Equal(t, "uploads/intro.mp3", tracks[0].FilePath)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTrashRepository_Purge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewTrashRepository(db)
+
+	before := time.Now().Add(-720 * time.Hour)
+
+	// Окончательное удаление альбомов
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM albums WHERE deleted_at < \\$1").
+			WithArgs(before).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		deleted, err := repo.Purge(context.Background(), models.TrashAlbum, before)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+	})
+
+	// Треки удаляются только через PurgeTracks
+	t.Run("unsupported_type", func(t *testing.T) {
+		_, err := repo.Purge(context.Background(), models.TrashTrack, before)
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
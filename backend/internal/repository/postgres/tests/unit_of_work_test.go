@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"music-service/internal/repository/postgres"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWork_WithTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	uow := postgres.NewUnitOfWork(db, t.TempDir())
+
+	now := time.Now()
+	album := &models.Album{
+		ID:          uuid.New(),
+		Title:       "Test Album",
+		Artist:      "Test Artist",
+		ReleaseDate: now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	track := &models.Track{
+		ID:         uuid.New(),
+		Title:      "Test Track",
+		Duration:   180,
+		FilePath:   "ab/cd/track.mp3",
+		AlbumID:    album.ID,
+		ArtistName: "Test Artist",
+		AddedDate:  now,
+		UpdatedAt:  now,
+	}
+
+	addTrackToAlbum := func(repos *interfaces.TxRepositories) error {
+		if err := repos.Album.AddTrackToAlbum(context.Background(), album.ID, track.ID); err != nil {
+			return err
+		}
+		if err := repos.Track.Save(context.Background(), track); err != nil {
+			return err
+		}
+		return repos.Album.Save(context.Background(), album)
+	}
+
+	// Все изменения фиксируются одной транзакцией
+	t.Run("commit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE tracks SET album_id").
+			WithArgs(album.ID, track.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO tracks").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO albums").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := uow.WithTx(context.Background(), addTrackToAlbum)
+		assert.NoError(t, err)
+	})
+
+	// Ошибка на втором шаге откатывает первый
+	t.Run("rollback_on_error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE tracks SET album_id").
+			WithArgs(album.ID, track.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO tracks").
+			WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		err := uow.WithTx(context.Background(), addTrackToAlbum)
+		assert.EqualError(t, err, "db error")
+	})
+
+	// Паника внутри транзакции тоже приводит к откату
+	t.Run("rollback_on_panic", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.Panics(t, func() {
+			_ = uow.WithTx(context.Background(), func(repos *interfaces.TxRepositories) error {
+				panic("unexpected")
+			})
+		})
+	})
+
+	// Ошибка фиксации возвращается вызывающему коду
+	t.Run("commit_error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
+
+		err := uow.WithTx(context.Background(), func(repos *interfaces.TxRepositories) error {
+			return nil
+		})
+		assert.EqualError(t, err, "commit failed")
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUnitOfWork_NestedRepositoryTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	uow := postgres.NewUnitOfWork(db, t.TempDir())
+
+	userID := uuid.New()
+	trackID := uuid.New()
+	state := &models.PlaybackState{
+		UserID:     userID,
+		RepeatMode: models.RepeatOff,
+		UpdatedAt:  time.Now(),
+	}
+
+	// ReplaceQueue внутри WithTx не открывает собственную транзакцию
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM playback_queue WHERE user_id = \\$1").
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO playback_queue").
+		WithArgs(userID, 0, trackID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO playback_states").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = uow.WithTx(context.Background(), func(repos *interfaces.TxRepositories) error {
+		if err := repos.Playback.ReplaceQueue(context.Background(), userID, []uuid.UUID{trackID}); err != nil {
+			return err
+		}
+		return repos.Playback.SaveState(context.Background(), state)
+	})
+	assert.NoError(t, err)
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"context"
+	"database/sql/driver"
 	"errors"
 	"music-service/internal/models"
 	"music-service/internal/repository/postgres"
@@ -12,6 +14,18 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var userColumns = []string{
+	"id", "login", "email", "password", "permission", "created_at", "updated_at",
+	"suspended_at", "suspended_until", "suspension_reason", "suspended_by", "locale",
+}
+
+func userRow(user *models.User) []driver.Value {
+	return []driver.Value{
+		user.ID, user.Login, user.Email, user.Password, user.Permission, user.CreatedAt, user.UpdatedAt,
+		nil, nil, "", nil, user.Locale,
+	}
+}
+
 func TestUserRepository_FindByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -33,14 +47,14 @@ func TestUserRepository_FindByID(t *testing.T) {
 
 	// Успешный сценарий
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "login", "password", "permission", "created_at", "updated_at"}).
-			AddRow(user.ID, user.Login, user.Password, user.Permission, user.CreatedAt, user.UpdatedAt)
+		rows := sqlmock.NewRows(userColumns).
+			AddRow(userRow(user)...)
 
 		mock.ExpectQuery("SELECT (.+) FROM users WHERE id = \\$1").
 			WithArgs(userID).
 			WillReturnRows(rows)
 
-		foundUser, err := repo.FindByID(userID)
+		foundUser, err := repo.FindByID(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Equal(t, user.ID, foundUser.ID)
 		assert.Equal(t, user.Login, foundUser.Login)
@@ -54,7 +68,7 @@ func TestUserRepository_FindByID(t *testing.T) {
 			WithArgs(userID).
 			WillReturnError(errors.New("db error"))
 
-		foundUser, err := repo.FindByID(userID)
+		foundUser, err := repo.FindByID(context.Background(), userID)
 		assert.Error(t, err)
 		assert.Nil(t, foundUser)
 	})
@@ -87,20 +101,20 @@ func TestUserRepository_Save(t *testing.T) {
 	// Успешное сохранение
 	t.Run("success", func(t *testing.T) {
 		mock.ExpectExec("INSERT INTO users").
-			WithArgs(user.ID, user.Login, user.Password, user.Permission, user.CreatedAt, user.UpdatedAt).
+			WithArgs(user.ID, user.Login, user.Email, user.Password, user.Permission, user.CreatedAt, user.UpdatedAt).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		err := repo.Save(user)
+		err := repo.Save(context.Background(), user)
 		assert.NoError(t, err)
 	})
 
 	// Ошибка при сохранении
 	t.Run("error", func(t *testing.T) {
 		mock.ExpectExec("INSERT INTO users").
-			WithArgs(user.ID, user.Login, user.Password, user.Permission, user.CreatedAt, user.UpdatedAt).
+			WithArgs(user.ID, user.Login, user.Email, user.Password, user.Permission, user.CreatedAt, user.UpdatedAt).
 			WillReturnError(errors.New("db error"))
 
-		err := repo.Save(user)
+		err := repo.Save(context.Background(), user)
 		assert.Error(t, err)
 	})
 
@@ -120,24 +134,47 @@ func TestUserRepository_Delete(t *testing.T) {
 	repo := postgres.NewUserRepository(db)
 
 	userID := uuid.New()
+	deletedAt := time.Now()
 
-	// Успешное удаление
+	// Пользователь помещается в корзину вместе с плейлистами
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM users WHERE id = \\$1").
-			WithArgs(userID).
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE playlists SET deleted_at = \\$2 WHERE user_id = \\$1 AND deleted_at IS NULL").
+			WithArgs(userID, deletedAt).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("UPDATE users SET deleted_at = \\$2 WHERE id = \\$1 AND deleted_at IS NULL").
+			WithArgs(userID, deletedAt).
 			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
 
-		err := repo.Delete(userID)
+		err := repo.Delete(context.Background(), userID, deletedAt)
 		assert.NoError(t, err)
 	})
 
+	// Пользователь не найден — транзакция откатывается
+	t.Run("not_found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE playlists SET deleted_at").
+			WithArgs(userID, deletedAt).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE users SET deleted_at").
+			WithArgs(userID, deletedAt).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.Delete(context.Background(), userID, deletedAt)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
 	// Ошибка при удалении
 	t.Run("error", func(t *testing.T) {
-		mock.ExpectExec("DELETE FROM users WHERE id = \\$1").
-			WithArgs(userID).
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE playlists SET deleted_at").
+			WithArgs(userID, deletedAt).
 			WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
 
-		err := repo.Delete(userID)
+		err := repo.Delete(context.Background(), userID, deletedAt)
 		assert.Error(t, err)
 	})
 
@@ -147,6 +184,90 @@ func TestUserRepository_Delete(t *testing.T) {
 	}
 }
 
+func TestUserRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+
+	userID := uuid.New()
+
+	// Восстанавливаются плейлисты, удаленные вместе с пользователем, и он сам
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE playlists SET deleted_at = NULL").
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("UPDATE users SET deleted_at = NULL WHERE id = \\$1 AND deleted_at IS NOT NULL").
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.Restore(context.Background(), userID)
+		assert.NoError(t, err)
+	})
+
+	// Пользователя нет в корзине
+	t.Run("not_found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE playlists SET deleted_at = NULL").
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE users SET deleted_at = NULL").
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.Restore(context.Background(), userID)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_IsLoginTaken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+
+	// Логин занят, в том числе пользователем из корзины
+	t.Run("taken", func(t *testing.T) {
+		mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM users WHERE LOWER\\(login\\) = LOWER\\(\\$1\\)\\)").
+			WithArgs("Alice").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		taken, err := repo.IsLoginTaken(context.Background(), "Alice")
+		assert.NoError(t, err)
+		assert.True(t, taken)
+	})
+
+	// Логин свободен
+	t.Run("free", func(t *testing.T) {
+		mock.ExpectQuery("SELECT EXISTS").
+			WithArgs("bob").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		taken, err := repo.IsLoginTaken(context.Background(), "bob")
+		assert.NoError(t, err)
+		assert.False(t, taken)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestUserRepository_Search(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -178,16 +299,16 @@ func TestUserRepository_Search(t *testing.T) {
 
 	// Успешный поиск
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "login", "password", "permission", "created_at", "updated_at"})
+		rows := sqlmock.NewRows(userColumns)
 		for _, user := range users {
-			rows.AddRow(user.ID, user.Login, user.Password, user.Permission, user.CreatedAt, user.UpdatedAt)
+			rows.AddRow(userRow(user)...)
 		}
 
 		mock.ExpectQuery("SELECT (.+) FROM users WHERE login ILIKE \\$1").
 			WithArgs("%" + searchQuery + "%").
 			WillReturnRows(rows)
 
-		foundUsers, err := repo.Search(searchQuery)
+		foundUsers, err := repo.Search(context.Background(), searchQuery)
 		assert.NoError(t, err)
 		assert.Len(t, foundUsers, 2)
 		assert.Equal(t, users[0].Login, foundUsers[0].Login)
@@ -200,20 +321,20 @@ func TestUserRepository_Search(t *testing.T) {
 			WithArgs("%" + searchQuery + "%").
 			WillReturnError(errors.New("db error"))
 
-		foundUsers, err := repo.Search(searchQuery)
+		foundUsers, err := repo.Search(context.Background(), searchQuery)
 		assert.Error(t, err)
 		assert.Nil(t, foundUsers)
 	})
 
 	// Пустой результат
 	t.Run("empty_result", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "login", "password", "permission", "created_at", "updated_at"})
+		rows := sqlmock.NewRows(userColumns)
 
 		mock.ExpectQuery("SELECT (.+) FROM users WHERE login ILIKE \\$1").
 			WithArgs("%" + searchQuery + "%").
 			WillReturnRows(rows)
 
-		foundUsers, err := repo.Search(searchQuery)
+		foundUsers, err := repo.Search(context.Background(), searchQuery)
 		assert.NoError(t, err)
 		assert.Empty(t, foundUsers)
 	})
@@ -223,3 +344,236 @@ func TestUserRepository_Search(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func TestUserRepository_FindByEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+
+	user := &models.User{
+		ID:         uuid.New(),
+		Login:      "testuser",
+		Email:      "test@example.com",
+		Password:   "hashedpassword",
+		Permission: models.UserPermission,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	// Почта сравнивается без учета регистра
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows(userColumns).
+			AddRow(userRow(user)...)
+
+		mock.ExpectQuery("SELECT (.+) FROM users WHERE LOWER\\(email\\) = LOWER\\(\\$1\\)").
+			WithArgs("Test@Example.com").
+			WillReturnRows(rows)
+
+		foundUser, err := repo.FindByEmail(context.Background(), "Test@Example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, foundUser.ID)
+		assert.Equal(t, user.Email, foundUser.Email)
+	})
+
+	// Пользователь не найден
+	t.Run("not_found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM users WHERE LOWER\\(email\\) = LOWER\\(\\$1\\)").
+			WithArgs("missing@example.com").
+			WillReturnRows(sqlmock.NewRows(userColumns))
+
+		foundUser, err := repo.FindByEmail(context.Background(), "missing@example.com")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Nil(t, foundUser)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_CountByPermission(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+
+	// Успешный подсчет
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE permission = \\$1").
+			WithArgs(models.AdminPermission).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		count, err := repo.CountByPermission(context.Background(), models.AdminPermission)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	// Ошибка базы данных
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE permission = \\$1").
+			WithArgs(models.AdminPermission).
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.CountByPermission(context.Background(), models.AdminPermission)
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+
+	// Поиск по логину среди заблокированных
+	t.Run("success with filters", func(t *testing.T) {
+		user := &models.User{
+			ID:         uuid.New(),
+			Login:      "test_user",
+			Email:      "test@example.com",
+			Permission: models.UserPermission,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+		suspendedAt := time.Now().Add(-time.Hour)
+		row := userRow(user)
+		row[7], row[9] = suspendedAt, "spam"
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE deleted_at IS NULL AND \\(login ILIKE \\$1 OR email ILIKE \\$1\\) AND \\(suspended_at IS NOT NULL").
+			WithArgs("%test\\_user%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT (.+) FROM users WHERE (.+) ORDER BY created_at DESC, id LIMIT \\$2 OFFSET \\$3").
+			WithArgs("%test\\_user%", 50, 0).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(row...))
+
+		page, err := repo.List(context.Background(), models.UserFilter{
+			Query:  "test_user",
+			Status: models.UserStatusSuspended,
+			Limit:  50,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Len(t, page.Users, 1)
+		assert.NotNil(t, page.Users[0].Suspension)
+		assert.Equal(t, "spam", page.Users[0].Suspension.Reason)
+		assert.True(t, page.Users[0].IsSuspended(time.Now()))
+	})
+
+	// Ошибка базы данных при подсчете
+	t.Run("count error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users").
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.List(context.Background(), models.UserFilter{Limit: 50})
+		assert.Error(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_Suspend(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+	userID := uuid.New()
+	until := time.Now().Add(24 * time.Hour)
+	suspension := &models.Suspension{
+		Reason:      "spam",
+		SuspendedAt: time.Now(),
+		Until:       &until,
+	}
+
+	// Успешная блокировка
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET suspended_at = \\$2").
+			WithArgs(userID, suspension.SuspendedAt, suspension.Until, suspension.Reason, suspension.SuspendedBy).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Suspend(context.Background(), userID, suspension)
+		assert.NoError(t, err)
+	})
+
+	// Пользователь не найден
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET suspended_at = \\$2").
+			WithArgs(userID, suspension.SuspendedAt, suspension.Until, suspension.Reason, suspension.SuspendedBy).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Suspend(context.Background(), userID, suspension)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	// Снятие блокировки
+	t.Run("unsuspend", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET suspended_at = NULL").
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Unsuspend(context.Background(), userID)
+		assert.NoError(t, err)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepository_SetLocale(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+	userID := uuid.New()
+
+	// Язык сохраняется
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET locale = NULLIF\\(\\$2, ''\\)").
+			WithArgs(userID, "en", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.SetLocale(context.Background(), userID, "en")
+		assert.NoError(t, err)
+	})
+
+	// Пользователь не найден
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET locale").
+			WithArgs(userID, "", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.SetLocale(context.Background(), userID, "")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	// Проверка, что все ожидаемые запросы были выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"io"
@@ -8,13 +9,14 @@ import (
 	"music-service/internal/repository/interfaces"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgtype"
+	"github.com/lib/pq"
 )
 
 type TrackRepository struct {
-	db        *sql.DB
+	db        DBTX
 	tracksDir string
 }
 
@@ -25,12 +27,65 @@ func NewTrackRepository(db *sql.DB, tracksDir string) interfaces.TrackRepository
 	}
 }
 
-func (r *TrackRepository) FindByID(id uuid.UUID) (*models.Track, error) {
+// FindByID возвращает models.ErrNotFound, если трека нет
+func (r *TrackRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Track, error) {
+	query := `SELECT ` + trackColumns + ` FROM tracks WHERE id = $1 AND deleted_at IS NULL`
+	track, err := scanTrack(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+func (r *TrackRepository) Save(ctx context.Context, track *models.Track) error {
+	query := `
+		INSERT INTO tracks (id, title, duration, file_path, album_id, artist_name, cover_url, added_date, updated_at, play_count,
+			artists, track_number, disc_number, explicit, isrc, lyrics_url, release_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, 0), NULLIF($13, 0), $14, NULLIF($15, ''), NULLIF($16, ''), $17)
+		ON CONFLICT (id) DO UPDATE 
+		SET title = $2, duration = $3, file_path = $4, album_id = $5, artist_name = $6, 
+			cover_url = $7, updated_at = $9, play_count = $10, artists = $11, track_number = NULLIF($12, 0),
+			disc_number = NULLIF($13, 0), explicit = $14, isrc = NULLIF($15, ''), lyrics_url = NULLIF($16, ''), release_date = $17
+	`
+	_, err := r.db.ExecContext(ctx, query, trackArgs(track)...)
+	return err
+}
+
+// Update сохраняет метаданные трека, если с момента version (updated_at,
+// прочитанного вызывающим) трек никто не менял. Иначе, а также если трека
+// нет, возвращается models.ErrNotFound. Счетчик прослушиваний, путь к файлу
+// и громкость меняются отдельно и не перезаписываются: прослушивание не
+// меняет updated_at, и копия трека у вызывающего может быть устаревшей
+func (r *TrackRepository) Update(ctx context.Context, track *models.Track, version time.Time) error {
+	query := `
+		UPDATE tracks
+		SET title = $2, duration = $3, album_id = $4, artist_name = $5, cover_url = $6, updated_at = $7,
+			artists = $8, track_number = NULLIF($9, 0), disc_number = NULLIF($10, 0), explicit = $11,
+			isrc = NULLIF($12, ''), lyrics_url = NULLIF($13, ''), release_date = $14
+		WHERE id = $1 AND updated_at = $15 AND deleted_at IS NULL
+	`
+	result, err := r.db.ExecContext(ctx, query, trackMetadataArgs(track, version)...)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+const trackColumns = `id, title, duration, file_path, album_id, artist_name, cover_url, added_date, updated_at, play_count,
+	artists, COALESCE(track_number, 0), COALESCE(disc_number, 0), explicit, COALESCE(isrc, ''), COALESCE(lyrics_url, ''), release_date,
+	integrated_lufs, true_peak_dbtp`
+
+func scanTrack(row rowScanner) (*models.Track, error) {
 	var track models.Track
-	var albumID pgtype.UUID
-	query := `SELECT id, title, duration, file_path, album_id, artist_name, cover_url, added_date, updated_at, play_count 
-				FROM tracks WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(
+	var albumID uuid.NullUUID
+	var artists pq.StringArray
+	var releaseDate sql.NullTime
+	var integrated, truePeak sql.NullFloat64
+
+	err := row.Scan(
 		&track.ID,
 		&track.Title,
 		&track.Duration,
@@ -41,71 +96,161 @@ func (r *TrackRepository) FindByID(id uuid.UUID) (*models.Track, error) {
 		&track.AddedDate,
 		&track.UpdatedAt,
 		&track.PlayCount,
+		&artists,
+		&track.TrackNumber,
+		&track.DiscNumber,
+		&track.Explicit,
+		&track.ISRC,
+		&track.LyricsURL,
+		&releaseDate,
+		&integrated,
+		&truePeak,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	if albumID.Status == pgtype.Present {
-		track.AlbumID = albumID.Bytes
+	if albumID.Valid {
+		track.AlbumID = albumID.UUID
 	}
-
+	track.Artists = []string(artists)
+	if releaseDate.Valid {
+		date := releaseDate.Time
+		track.ReleaseDate = &date
+	}
+	track.Loudness = loudness(integrated, truePeak)
 	return &track, nil
 }
 
-func (r *TrackRepository) Save(track *models.Track) error {
-	var albumID interface{}
-	if track.AlbumID == uuid.Nil {
-		albumID = nil
-	} else {
+// loudness собирает громкость из столбцов integrated_lufs и true_peak_dbtp
+func loudness(integrated, truePeak sql.NullFloat64) *models.Loudness {
+	if !integrated.Valid || !truePeak.Valid {
+		return nil
+	}
+	return &models.Loudness{IntegratedLUFS: integrated.Float64, TruePeakDBTP: truePeak.Float64}
+}
+
+// SetLoudness сохраняет измеренную громкость трека; nil стирает ее. Если
+// трека нет, возвращается models.ErrNotFound
+func (r *TrackRepository) SetLoudness(ctx context.Context, id uuid.UUID, loudness *models.Loudness, updatedAt time.Time) error {
+	var integrated, truePeak interface{}
+	if loudness != nil {
+		integrated, truePeak = loudness.IntegratedLUFS, loudness.TruePeakDBTP
+	}
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE tracks SET integrated_lufs = $2, true_peak_dbtp = $3, updated_at = $4
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id, integrated, truePeak, updatedAt)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+// trackArgs — параметры $1..$17 запроса Save
+func trackArgs(track *models.Track) []interface{} {
+	albumID, artists, releaseDate := trackNullables(track)
+	return []interface{}{
+		track.ID, track.Title, track.Duration, track.FilePath, albumID, track.ArtistName, track.CoverURL,
+		track.AddedDate, track.UpdatedAt, track.PlayCount, artists, track.TrackNumber,
+		track.DiscNumber, track.Explicit, track.ISRC, track.LyricsURL, releaseDate,
+	}
+}
+
+// trackMetadataArgs возвращает аргументы запроса Update: редактируемые
+// метаданные трека и version для проверки
+func trackMetadataArgs(track *models.Track, version time.Time) []interface{} {
+	albumID, artists, releaseDate := trackNullables(track)
+	return []interface{}{
+		track.ID, track.Title, track.Duration, albumID, track.ArtistName, track.CoverURL, track.UpdatedAt,
+		artists, track.TrackNumber, track.DiscNumber, track.Explicit, track.ISRC, track.LyricsURL,
+		releaseDate, version,
+	}
+}
+
+// trackNullables приводит альбом, исполнителей и дату выхода трека к
+// значениям столбцов: пустые альбом и дата записываются как NULL
+func trackNullables(track *models.Track) (albumID interface{}, artists pq.StringArray, releaseDate interface{}) {
+	if track.AlbumID != uuid.Nil {
 		albumID = track.AlbumID
 	}
+	if track.ReleaseDate != nil {
+		releaseDate = *track.ReleaseDate
+	}
+	artists = track.Artists
+	if len(artists) == 0 && track.ArtistName != "" {
+		artists = []string{track.ArtistName}
+	}
+	return albumID, artists, releaseDate
+}
 
-	query := `
-		INSERT INTO tracks (id, title, duration, file_path, album_id, artist_name, cover_url, added_date, updated_at, play_count) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		ON CONFLICT (id) DO UPDATE 
-		SET title = $2, duration = $3, file_path = $4, album_id = $5, artist_name = $6, 
-			cover_url = $7, updated_at = $9, play_count = $10
-	`
-	_, err := r.db.Exec(query, track.ID, track.Title, track.Duration, track.FilePath,
-		albumID, track.ArtistName, track.CoverURL, track.AddedDate, track.UpdatedAt, track.PlayCount)
-	return err
+// Delete помещает трек в корзину с отметкой deletedAt. Файл остается на
+// месте до окончательного удаления. Если трека нет, возвращается
+// models.ErrNotFound
+func (r *TrackRepository) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE tracks SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`, id, deletedAt)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
 }
 
-func (r *TrackRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM tracks WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
+// Restore возвращает трек из корзины. Если в корзине его нет, возвращается
+// models.ErrNotFound
+func (r *TrackRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE tracks SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+// Merge переносит на трек targetID ссылки на трек sourceID: вхождения в
+// плейлисты (если цель уже есть в плейлисте, вхождение источника
+// удаляется), историю прослушиваний, очереди воспроизведения и счетчик
+// прослушиваний. Счетчик источника обнуляется, чтобы прослушивания не
+// учитывались дважды, если источник восстановят. Сам источник не удаляется
+func (r *TrackRepository) Merge(ctx context.Context, targetID, sourceID uuid.UUID) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		for _, statement := range []struct {
+			query string
+			args  []interface{}
+		}{
+			{`INSERT INTO playlist_tracks (playlist_id, track_id, added_at)
+				SELECT playlist_id, $1, added_at FROM playlist_tracks WHERE track_id = $2
+				ON CONFLICT (playlist_id, track_id) DO NOTHING`, []interface{}{targetID, sourceID}},
+			{`DELETE FROM playlist_tracks WHERE track_id = $1`, []interface{}{sourceID}},
+			{`UPDATE listening_history SET track_id = $1 WHERE track_id = $2`, []interface{}{targetID, sourceID}},
+			{`UPDATE playback_queue SET track_id = $1 WHERE track_id = $2`, []interface{}{targetID, sourceID}},
+			{`UPDATE tracks SET play_count = play_count + (SELECT play_count FROM tracks WHERE id = $2) WHERE id = $1`, []interface{}{targetID, sourceID}},
+			{`UPDATE tracks SET play_count = 0 WHERE id = $1`, []interface{}{sourceID}},
+		} {
+			if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-func (r *TrackRepository) Search(query string) ([]*models.Track, error) {
+func (r *TrackRepository) Search(ctx context.Context, query string) ([]*models.Track, error) {
 	var tracks []*models.Track
-	rows, err := r.db.Query(`SELECT id, title, duration, file_path, album_id, artist_name, cover_url, added_date, updated_at, play_count 
-					FROM tracks WHERE title ILIKE $1 OR artist_name ILIKE $1`, "%"+query+"%")
+	rows, err := r.db.QueryContext(ctx, `SELECT `+trackColumns+`
+					FROM tracks
+					WHERE (title ILIKE $1 OR array_to_string(artists, ' ') ILIKE $1 OR EXISTS (
+						SELECT 1 FROM track_translations tt WHERE tt.track_id = tracks.id AND tt.value ILIKE $1
+					)) AND deleted_at IS NULL`, "%"+query+"%")
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var track models.Track
-		err := rows.Scan(
-			&track.ID,
-			&track.Title,
-			&track.Duration,
-			&track.FilePath,
-			&track.AlbumID,
-			&track.ArtistName,
-			&track.CoverURL,
-			&track.AddedDate,
-			&track.UpdatedAt,
-			&track.PlayCount,
-		)
+		track, err := scanTrack(rows)
 		if err != nil {
 			return nil, err
 		}
-		tracks = append(tracks, &track)
+		tracks = append(tracks, track)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -115,13 +260,13 @@ func (r *TrackRepository) Search(query string) ([]*models.Track, error) {
 	return tracks, nil
 }
 
-func (r *TrackRepository) IncrementPlayCount(trackID uuid.UUID) error {
+func (r *TrackRepository) IncrementPlayCount(ctx context.Context, trackID uuid.UUID) error {
 	query := `UPDATE tracks SET play_count = play_count + 1 WHERE id = $1`
-	_, err := r.db.Exec(query, trackID)
+	_, err := r.db.ExecContext(ctx, query, trackID)
 	return err
 }
 
-func (r *TrackRepository) SaveTrackFile(trackID uuid.UUID, fileReader io.Reader, fileSize int64) (string, error) {
+func (r *TrackRepository) SaveTrackFile(ctx context.Context, trackID uuid.UUID, fileReader io.Reader, fileSize int64) (string, error) {
 	if err := os.MkdirAll(r.tracksDir, 0755); err != nil {
 		return "", fmt.Errorf("не удалось создать директорию для треков: %w", err)
 	}
@@ -144,7 +289,8 @@ func (r *TrackRepository) SaveTrackFile(trackID uuid.UUID, fileReader io.Reader,
 	}
 	defer file.Close()
 
-	_, err = io.Copy(file, fileReader)
+	// Отмена запроса (таймаут, разрыв соединения) прерывает копирование
+	_, err = io.Copy(file, &contextReader{ctx: ctx, r: fileReader})
 	if err != nil {
 		os.Remove(absolutePath)
 		return "", fmt.Errorf("не удалось сохранить файл: %w", err)
@@ -153,12 +299,38 @@ func (r *TrackRepository) SaveTrackFile(trackID uuid.UUID, fileReader io.Reader,
 	return relativePath, nil
 }
 
+// contextReader прекращает чтение после отмены ctx
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// DeleteTrackFile удаляет файл трека. Отсутствие файла ошибкой не считается
+func (r *TrackRepository) DeleteTrackFile(ctx context.Context, filePath string) error {
+	if filePath == "" {
+		return nil
+	}
+
+	absolutePath := filepath.Join(r.tracksDir, filepath.Clean("/"+filePath))
+	if err := os.Remove(absolutePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("не удалось удалить файл: %w", err)
+	}
+	return nil
+}
+
 func (r *TrackRepository) GetStorageDir() string {
 	return r.tracksDir
 }
 
 // GetGenresForTrack возвращает список жанров для трека
-func (r *TrackRepository) GetGenresForTrack(trackID uuid.UUID) ([]*models.Genre, error) {
+func (r *TrackRepository) GetGenresForTrack(ctx context.Context, trackID uuid.UUID) ([]*models.Genre, error) {
 	var genres []*models.Genre
 	query := `
 		SELECT g.id, g.name
@@ -166,7 +338,7 @@ func (r *TrackRepository) GetGenresForTrack(trackID uuid.UUID) ([]*models.Genre,
 		JOIN track_genres tg ON g.id = tg.genre_id
 		WHERE tg.track_id = $1
 	`
-	rows, err := r.db.Query(query, trackID)
+	rows, err := r.db.QueryContext(ctx, query, trackID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type TranslationRepository struct {
+	db DBTX
+}
+
+func NewTranslationRepository(db *sql.DB) interfaces.TranslationRepository {
+	return &TranslationRepository{
+		db: db,
+	}
+}
+
+// translationTable — таблица переводов и столбец с ID объекта
+type translationTable struct {
+	name     string
+	idColumn string
+}
+
+var translationTables = map[string]translationTable{
+	models.TranslatableTrack: {name: "track_translations", idColumn: "track_id"},
+	models.TranslatableAlbum: {name: "album_translations", idColumn: "album_id"},
+	models.TranslatableGenre: {name: "genre_translations", idColumn: "genre_id"},
+}
+
+func lookupTranslationTable(entityType string) (translationTable, error) {
+	table, ok := translationTables[entityType]
+	if !ok {
+		return translationTable{}, fmt.Errorf("unknown translation type %q", entityType)
+	}
+	return table, nil
+}
+
+func (r *TranslationRepository) List(ctx context.Context, entityType string, id uuid.UUID) ([]*models.Translation, error) {
+	table, err := lookupTranslationTable(entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %[2]s, locale, field, value, updated_at
+		FROM %[1]s
+		WHERE %[2]s = $1
+		ORDER BY locale, field
+	`, table.name, table.idColumn)
+	byEntity, err := r.queryTranslations(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	return byEntity[id], nil
+}
+
+func (r *TranslationRepository) FindByEntities(ctx context.Context, entityType string, ids []uuid.UUID, locales []string) (map[uuid.UUID][]*models.Translation, error) {
+	table, err := lookupTranslationTable(entityType)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 || len(locales) == 0 {
+		return map[uuid.UUID][]*models.Translation{}, nil
+	}
+
+	idStrings := make([]string, 0, len(ids))
+	for _, id := range ids {
+		idStrings = append(idStrings, id.String())
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %[2]s, locale, field, value, updated_at
+		FROM %[1]s
+		WHERE %[2]s = ANY($1::uuid[]) AND locale = ANY($2)
+		ORDER BY %[2]s, locale, field
+	`, table.name, table.idColumn)
+	return r.queryTranslations(ctx, query, pq.StringArray(idStrings), pq.StringArray(locales))
+}
+
+// queryTranslations собирает строки (ID, язык, поле, значение) в переводы.
+// Строки должны быть упорядочены по ID объекта и языку
+func (r *TranslationRepository) queryTranslations(ctx context.Context, query string, args ...interface{}) (map[uuid.UUID][]*models.Translation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID][]*models.Translation)
+	var current *models.Translation
+	var currentID uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		var locale, field, value string
+		var updatedAt time.Time
+		if err := rows.Scan(&id, &locale, &field, &value, &updatedAt); err != nil {
+			return nil, err
+		}
+
+		if current == nil || id != currentID || locale != current.Locale {
+			current = &models.Translation{Locale: locale, Fields: make(map[string]string)}
+			currentID = id
+			result[id] = append(result[id], current)
+		}
+		current.Fields[field] = value
+		if updatedAt.After(current.UpdatedAt) {
+			current.UpdatedAt = updatedAt
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *TranslationRepository) Save(ctx context.Context, entityType string, id uuid.UUID, translation *models.Translation) error {
+	table, err := lookupTranslationTable(entityType)
+	if err != nil {
+		return err
+	}
+
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		// Поля, которых нет в новом переводе, удаляются: перевод заменяется целиком
+		_, err := tx.ExecContext(ctx,
+			fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND locale = $2`, table.name, table.idColumn),
+			id, translation.Locale)
+		if err != nil {
+			return err
+		}
+
+		query := fmt.Sprintf(`
+			INSERT INTO %s (%s, locale, field, value, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, table.name, table.idColumn)
+		fields := make([]string, 0, len(translation.Fields))
+		for field := range translation.Fields {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			value := translation.Fields[field]
+			if _, err := tx.ExecContext(ctx, query, id, translation.Locale, field, value, translation.UpdatedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *TranslationRepository) Delete(ctx context.Context, entityType string, id uuid.UUID, locale string) error {
+	table, err := lookupTranslationTable(entityType)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND locale = $2`, table.name, table.idColumn),
+		id, locale)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashRepository struct {
+	db DBTX
+}
+
+func NewTrashRepository(db *sql.DB) interfaces.TrashRepository {
+	return &TrashRepository{
+		db: db,
+	}
+}
+
+// trashItems — удаленные объекты всех типов в общем виде
+const trashItems = `(
+		SELECT 'track' AS type, id, title, NULL::uuid AS owner_id, album_id AS parent_id, deleted_at
+		FROM tracks WHERE deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'album', id, title, NULL::uuid, NULL::uuid, deleted_at
+		FROM albums WHERE deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'playlist', id, name, user_id, NULL::uuid, deleted_at
+		FROM playlists WHERE deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'user', id, login, NULL::uuid, NULL::uuid, deleted_at
+		FROM users WHERE deleted_at IS NOT NULL
+	) trash`
+
+// trashTables — таблицы объектов, которые окончательно удаляет Purge
+var trashTables = map[string]string{
+	models.TrashAlbum:    "albums",
+	models.TrashPlaylist: "playlists",
+	models.TrashUser:     "users",
+}
+
+func (r *TrashRepository) List(ctx context.Context, filter models.TrashFilter) (*models.TrashPage, error) {
+	var conditions []string
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Type != "" {
+		conditions = append(conditions, "type = "+addArg(filter.Type))
+	}
+	if filter.OwnerID != nil {
+		conditions = append(conditions, "owner_id = "+addArg(*filter.OwnerID))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	page := &models.TrashPage{Limit: filter.Limit, Offset: filter.Offset}
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+trashItems+where, args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT type, id, title, owner_id, parent_id, deleted_at FROM ` + trashItems + where +
+		` ORDER BY deleted_at DESC, id LIMIT ` + addArg(filter.Limit) + ` OFFSET ` + addArg(filter.Offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanTrashItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (r *TrashRepository) Find(ctx context.Context, itemType string, id uuid.UUID) (*models.TrashItem, error) {
+	query := `SELECT type, id, title, owner_id, parent_id, deleted_at FROM ` + trashItems + ` WHERE type = $1 AND id = $2`
+	item, err := scanTrashItem(r.db.QueryRowContext(ctx, query, itemType, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	return item, err
+}
+
+func (r *TrashRepository) PurgeTracks(ctx context.Context, before time.Time) ([]*models.Track, error) {
+	rows, err := r.db.QueryContext(ctx, `DELETE FROM tracks WHERE deleted_at < $1 RETURNING id, title, file_path`, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tracks []*models.Track
+	for rows.Next() {
+		var track models.Track
+		if err := rows.Scan(&track.ID, &track.Title, &track.FilePath); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, &track)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tracks, nil
+}
+
+func (r *TrashRepository) Purge(ctx context.Context, itemType string, before time.Time) (int64, error) {
+	table, ok := trashTables[itemType]
+	if !ok {
+		return 0, fmt.Errorf("unsupported trash type %q", itemType)
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM `+table+` WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanTrashItem(row rowScanner) (*models.TrashItem, error) {
+	var item models.TrashItem
+	var ownerID, parentID uuid.NullUUID
+	if err := row.Scan(&item.Type, &item.ID, &item.Title, &ownerID, &parentID, &item.DeletedAt); err != nil {
+		return nil, err
+	}
+	if ownerID.Valid {
+		item.OwnerID = &ownerID.UUID
+	}
+	if parentID.Valid {
+		item.ParentID = &parentID.UUID
+	}
+	return &item, nil
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"music-service/internal/repository/interfaces"
+)
+
+type UnitOfWork struct {
+	db        *sql.DB
+	tracksDir string
+}
+
+func NewUnitOfWork(db *sql.DB, tracksDir string) interfaces.UnitOfWork {
+	return &UnitOfWork{
+		db:        db,
+		tracksDir: tracksDir,
+	}
+}
+
+// WithTx открывает транзакцию и передает в fn репозитории, привязанные к ней
+func (u *UnitOfWork) WithTx(ctx context.Context, fn func(repos *interfaces.TxRepositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// После успешного Commit откат ничего не делает
+	defer tx.Rollback()
+
+	repos := &interfaces.TxRepositories{
+		User:     &UserRepository{db: tx},
+		Track:    &TrackRepository{db: tx, tracksDir: u.tracksDir},
+		Album:    &AlbumRepository{db: tx},
+		Playlist: &PlaylistRepository{db: tx},
+		Genre:    &GenreRepository{db: tx},
+		Session:  &SessionRepository{db: tx},
+		History:  &HistoryRepository{db: tx},
+		Playback: &PlaybackRepository{db: tx},
+		Follow:   &FollowRepository{db: tx},
+		Outbox:   &OutboxRepository{db: tx},
+
+		PasswordReset: &PasswordResetRepository{db: tx},
+		Identity:      &IdentityRepository{db: tx},
+		MFA:           &MFARepository{db: tx},
+		Audit:         &AuditRepository{db: tx},
+		Trash:         &TrashRepository{db: tx},
+		Translation:   &TranslationRepository{db: tx},
+		Cover:         &CoverRepository{db: tx, coversDir: CoversDir(u.tracksDir)},
+		Lyrics:        &LyricsRepository{db: tx},
+		Fingerprint:   &FingerprintRepository{db: tx},
+	}
+
+	if err := fn(repos); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
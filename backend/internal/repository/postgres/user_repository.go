@@ -1,15 +1,19 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type UserRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewUserRepository(db *sql.DB) interfaces.UserRepository {
@@ -18,92 +22,108 @@ func NewUserRepository(db *sql.DB) interfaces.UserRepository {
 	}
 }
 
-func (r *UserRepository) FindByID(id uuid.UUID) (*models.User, error) {
-	var user models.User
-	var permissionStr string
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND deleted_at IS NULL`
+	return scanUser(r.db.QueryRowContext(ctx, query, id))
+}
 
-	query := `SELECT id, login, password, permission, created_at, updated_at FROM users WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Login,
-		&user.Password,
-		&permissionStr,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
+// FindByLogin finds a user by exact login match.
+// Returns models.ErrNotFound if there is no such user
+func (r *UserRepository) FindByLogin(ctx context.Context, login string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE login = $1 AND deleted_at IS NULL`
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, login))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
 	}
-
-	user.Permission = models.Permission(permissionStr)
-	return &user, nil
+	return user, err
 }
 
-// FindByLogin finds a user by exact login match
-func (r *UserRepository) FindByLogin(login string) (*models.User, error) {
-	var user models.User
-	var permissionStr string
-
-	query := `SELECT id, login, password, permission, created_at, updated_at FROM users WHERE login = $1`
-	err := r.db.QueryRow(query, login).Scan(
-		&user.ID,
-		&user.Login,
-		&user.Password,
-		&permissionStr,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
+// FindByEmail ищет пользователя по почте без учета регистра.
+// Если пользователя нет, возвращается models.ErrNotFound
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE LOWER(email) = LOWER($1) AND deleted_at IS NULL`
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, email))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
 	}
-
-	user.Permission = models.Permission(permissionStr)
-	return &user, nil
+	return user, err
 }
 
-func (r *UserRepository) Save(user *models.User) error {
+func (r *UserRepository) Save(ctx context.Context, user *models.User) error {
 	query := `
-		INSERT INTO users (id, login, password, permission, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, login, email, password, permission, created_at, updated_at) 
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)
 		ON CONFLICT (id) DO UPDATE 
-		SET login = $2, password = $3, permission = $4, updated_at = $6
+		SET login = $2, email = NULLIF($3, ''), password = $4, permission = $5, updated_at = $7
 	`
-	_, err := r.db.Exec(query, user.ID, user.Login, user.Password, user.Permission, user.CreatedAt, user.UpdatedAt)
+	_, err := r.db.ExecContext(ctx, query, user.ID, user.Login, user.Email, user.Password, user.Permission, user.CreatedAt, user.UpdatedAt)
 	return err
 }
 
-func (r *UserRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
+// IsLoginTaken проверяет, занят ли логин без учета регистра. Учитываются и
+// пользователи в корзине: их логин занят до окончательного удаления
+func (r *UserRepository) IsLoginTaken(ctx context.Context, login string) (bool, error) {
+	var taken bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE LOWER(login) = LOWER($1))`, login).Scan(&taken)
+	return taken, err
+}
+
+// IsEmailTaken проверяет, занята ли почта, с учетом пользователей в корзине
+func (r *UserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	var taken bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE LOWER(email) = LOWER($1))`, email).Scan(&taken)
+	return taken, err
+}
+
+// Delete помещает пользователя и его плейлисты в корзину с отметкой
+// deletedAt. Если пользователя нет, возвращается models.ErrNotFound
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		_, err := tx.ExecContext(ctx, `UPDATE playlists SET deleted_at = $2 WHERE user_id = $1 AND deleted_at IS NULL`, id, deletedAt)
+		if err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, `UPDATE users SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`, id, deletedAt)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result)
+	})
+}
+
+// Restore возвращает пользователя из корзины вместе с плейлистами, удаленными
+// одновременно с ним. Если в корзине его нет, возвращается models.ErrNotFound
+func (r *UserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return inTx(ctx, r.db, func(tx DBTX) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE playlists SET deleted_at = NULL
+			WHERE user_id = $1 AND deleted_at = (SELECT deleted_at FROM users WHERE id = $1)
+		`, id)
+		if err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, `UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+		if err != nil {
+			return err
+		}
+		return expectAffected(result)
+	})
 }
 
-func (r *UserRepository) Search(query string) ([]*models.User, error) {
+func (r *UserRepository) Search(ctx context.Context, query string) ([]*models.User, error) {
 	var users []*models.User
-	rows, err := r.db.Query(`SELECT id, login, password, permission, created_at, updated_at FROM users WHERE login ILIKE $1`, "%"+query+"%")
+	rows, err := r.db.QueryContext(ctx, `SELECT `+userColumns+` FROM users WHERE login ILIKE $1 AND deleted_at IS NULL`, "%"+query+"%")
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var user models.User
-		var permissionStr string
-
-		err := rows.Scan(
-			&user.ID,
-			&user.Login,
-			&user.Password,
-			&permissionStr,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		user.Permission = models.Permission(permissionStr)
-		users = append(users, &user)
+		users = append(users, user)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -112,3 +132,168 @@ func (r *UserRepository) Search(query string) ([]*models.User, error) {
 
 	return users, nil
 }
+
+// CountByPermission возвращает количество пользователей с указанной ролью
+func (r *UserRepository) CountByPermission(ctx context.Context, permission models.Permission) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE permission = $1 AND deleted_at IS NULL`, permission).Scan(&count)
+	return count, err
+}
+
+// List возвращает страницу пользователей по фильтру, новые — первыми
+func (r *UserRepository) List(ctx context.Context, filter models.UserFilter) (*models.UserPage, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if query := strings.TrimSpace(filter.Query); query != "" {
+		pattern := addArg("%" + escapeLike(query) + "%")
+		conditions = append(conditions, "(login ILIKE "+pattern+" OR email ILIKE "+pattern+")")
+	}
+	if filter.Permission != "" {
+		conditions = append(conditions, "permission = "+addArg(filter.Permission))
+	}
+	switch filter.Status {
+	case models.UserStatusSuspended:
+		conditions = append(conditions, suspendedCondition)
+	case models.UserStatusActive:
+		conditions = append(conditions, "NOT "+suspendedCondition)
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	page := &models.UserPage{Limit: filter.Limit, Offset: filter.Offset}
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users` + where +
+		` ORDER BY created_at DESC, id LIMIT ` + addArg(filter.Limit) + ` OFFSET ` + addArg(filter.Offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Users = append(page.Users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// suspendedCondition выбирает пользователей с действующей блокировкой
+const suspendedCondition = `(suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > NOW()))`
+
+// Suspend блокирует пользователя, заменяя предыдущую блокировку.
+// Если пользователя нет, возвращается models.ErrNotFound
+func (r *UserRepository) Suspend(ctx context.Context, userID uuid.UUID, suspension *models.Suspension) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET suspended_at = $2, suspended_until = $3, suspension_reason = $4, suspended_by = $5
+		WHERE id = $1 AND deleted_at IS NULL
+	`, userID, suspension.SuspendedAt, suspension.Until, suspension.Reason, suspension.SuspendedBy)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+// Unsuspend снимает блокировку. Если пользователя нет, возвращается models.ErrNotFound
+func (r *UserRepository) Unsuspend(ctx context.Context, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET suspended_at = NULL, suspended_until = NULL, suspension_reason = '', suspended_by = NULL
+		WHERE id = $1
+	`, userID)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+// SetLocale сохраняет предпочитаемый язык; пустая строка сбрасывает его.
+// Если пользователя нет, возвращается models.ErrNotFound
+func (r *UserRepository) SetLocale(ctx context.Context, userID uuid.UUID, locale string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET locale = NULLIF($2, ''), updated_at = $3
+		WHERE id = $1 AND deleted_at IS NULL
+	`, userID, locale, time.Now())
+	if err != nil {
+		return err
+	}
+	return expectAffected(result)
+}
+
+func expectAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+const userColumns = `id, login, COALESCE(email, ''), password, permission, created_at, updated_at,
+	suspended_at, suspended_until, suspension_reason, suspended_by, COALESCE(locale, '')`
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	var permissionStr string
+	var suspendedAt, suspendedUntil sql.NullTime
+	var suspensionReason string
+	var suspendedBy uuid.NullUUID
+
+	err := row.Scan(
+		&user.ID,
+		&user.Login,
+		&user.Email,
+		&user.Password,
+		&permissionStr,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&suspendedAt,
+		&suspendedUntil,
+		&suspensionReason,
+		&suspendedBy,
+		&user.Locale,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Permission = models.Permission(permissionStr)
+	if suspendedAt.Valid {
+		user.Suspension = &models.Suspension{
+			Reason:      suspensionReason,
+			SuspendedAt: suspendedAt.Time,
+		}
+		if suspendedUntil.Valid {
+			until := suspendedUntil.Time
+			user.Suspension.Until = &until
+		}
+		if suspendedBy.Valid {
+			by := suspendedBy.UUID
+			user.Suspension.SuspendedBy = &by
+		}
+	}
+	return &user, nil
+}
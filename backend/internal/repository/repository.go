@@ -15,6 +15,21 @@ type Repository struct {
 	Genre    interfaces.GenreRepository
 	Session  interfaces.SessionRepository
 	History  interfaces.HistoryRepository
+	Playback interfaces.PlaybackRepository
+	Follow   interfaces.FollowRepository
+	Outbox   interfaces.OutboxRepository
+
+	PasswordReset interfaces.PasswordResetRepository
+	Identity      interfaces.IdentityRepository
+	MFA           interfaces.MFARepository
+	Audit         interfaces.AuditRepository
+	Trash         interfaces.TrashRepository
+	Translation   interfaces.TranslationRepository
+	Cover         interfaces.CoverRepository
+	Lyrics        interfaces.LyricsRepository
+	Fingerprint   interfaces.FingerprintRepository
+
+	UnitOfWork interfaces.UnitOfWork
 }
 
 func NewRepository(cfg db.Config) (*Repository, error) {
@@ -31,6 +46,21 @@ func NewRepository(cfg db.Config) (*Repository, error) {
 		Genre:    postgres.NewGenreRepository(db),
 		Session:  postgres.NewSessionRepository(db),
 		History:  postgres.NewHistoryRepository(db),
+		Playback: postgres.NewPlaybackRepository(db),
+		Follow:   postgres.NewFollowRepository(db),
+		Outbox:   postgres.NewOutboxRepository(db),
+
+		PasswordReset: postgres.NewPasswordResetRepository(db),
+		Identity:      postgres.NewIdentityRepository(db),
+		MFA:           postgres.NewMFARepository(db),
+		Audit:         postgres.NewAuditRepository(db),
+		Trash:         postgres.NewTrashRepository(db),
+		Translation:   postgres.NewTranslationRepository(db),
+		Cover:         postgres.NewCoverRepository(db, postgres.CoversDir(cfg.TracksDir)),
+		Lyrics:        postgres.NewLyricsRepository(db),
+		Fingerprint:   postgres.NewFingerprintRepository(db),
+
+		UnitOfWork: postgres.NewUnitOfWork(db, cfg.TracksDir),
 	}, nil
 }
 
@@ -43,5 +73,20 @@ func NewRepositoryWithDB(db *sql.DB, tracksDir string) *Repository {
 		Genre:    postgres.NewGenreRepository(db),
 		Session:  postgres.NewSessionRepository(db),
 		History:  postgres.NewHistoryRepository(db),
+		Playback: postgres.NewPlaybackRepository(db),
+		Follow:   postgres.NewFollowRepository(db),
+		Outbox:   postgres.NewOutboxRepository(db),
+
+		PasswordReset: postgres.NewPasswordResetRepository(db),
+		Identity:      postgres.NewIdentityRepository(db),
+		MFA:           postgres.NewMFARepository(db),
+		Audit:         postgres.NewAuditRepository(db),
+		Trash:         postgres.NewTrashRepository(db),
+		Translation:   postgres.NewTranslationRepository(db),
+		Cover:         postgres.NewCoverRepository(db, postgres.CoversDir(tracksDir)),
+		Lyrics:        postgres.NewLyricsRepository(db),
+		Fingerprint:   postgres.NewFingerprintRepository(db),
+
+		UnitOfWork: postgres.NewUnitOfWork(db, tracksDir),
 	}
 }
@@ -0,0 +1,167 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"music-service/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+)
+
+// Claims — содержимое access-токена. Токен проверяется без обращения к БД,
+// поэтому в нем есть все, что нужно для авторизации запроса
+type Claims struct {
+	ID        string            `json:"jti"`
+	Subject   uuid.UUID         `json:"sub"`
+	Login     string            `json:"login"`
+	Role      models.Permission `json:"role"`
+	SessionID uuid.UUID         `json:"sid"`
+	DeviceID  uuid.UUID         `json:"did"`
+	Locale    string            `json:"locale,omitempty"`
+	IssuedAt  int64             `json:"iat"`
+	ExpiresAt int64             `json:"exp"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// Signer подписывает и проверяет JWT (HS256). Новые токены подписываются
+// активным ключом, проверка принимает любой ключ из набора — так ключи
+// можно менять без разлогина пользователей: новый ключ добавляется и
+// делается активным, старый удаляется после истечения выданных им токенов
+type Signer struct {
+	activeKID string
+	keys      map[string][]byte
+}
+
+func NewSigner(activeKID string, keys map[string][]byte) (*Signer, error) {
+	if _, ok := keys[activeKID]; !ok {
+		return nil, fmt.Errorf("active signing key %q not found", activeKID)
+	}
+	for kid, key := range keys {
+		if len(key) < 32 {
+			return nil, fmt.Errorf("signing key %q must be at least 32 bytes", kid)
+		}
+	}
+	return &Signer{activeKID: activeKID, keys: keys}, nil
+}
+
+// ParseKeys разбирает набор ключей вида "kid1:base64,kid2:base64"
+func ParseKeys(value string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kid, encoded, ok := strings.Cut(item, ":")
+		if !ok || kid == "" {
+			return nil, fmt.Errorf("invalid signing key entry %q", kid)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key %q: %w", kid, err)
+		}
+		keys[kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+	return keys, nil
+}
+
+// GenerateKey возвращает случайный ключ подписи
+func GenerateKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (s *Signer) Sign(claims Claims) (string, error) {
+	headerJSON, err := json.Marshal(header{Alg: "HS256", Typ: "JWT", Kid: s.activeKID})
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodeSegment(headerJSON) + "." + encodeSegment(claimsJSON)
+	return signingInput + "." + encodeSegment(sign(s.keys[s.activeKID], signingInput)), nil
+}
+
+// Verify проверяет подпись и срок действия токена
+func (s *Signer) Verify(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	headerJSON, err := decodeSegment(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var h header
+	if err := json.Unmarshal(headerJSON, &h); err != nil {
+		return nil, ErrInvalidToken
+	}
+	// Алгоритм фиксирован, значение из заголовка только сверяется
+	if h.Alg != "HS256" {
+		return nil, ErrInvalidToken
+	}
+	key, ok := s.keys[h.Kid]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := decodeSegment(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(key, parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidToken
+	}
+
+	claimsJSON, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
+
+func sign(key []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(segment)
+}
@@ -0,0 +1,85 @@
+package tokens
+
+import (
+	"music-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+// Manager выпускает и проверяет access-токены с учетом отозванных сессий
+type Manager struct {
+	signer      *Signer
+	revocations *RevocationList
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+}
+
+func NewManager(signer *Signer, revocations *RevocationList, accessTTL, refreshTTL time.Duration) *Manager {
+	if accessTTL <= 0 {
+		accessTTL = DefaultAccessTTL
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTTL
+	}
+	return &Manager{
+		signer:      signer,
+		revocations: revocations,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+func (m *Manager) AccessTTL() time.Duration {
+	return m.accessTTL
+}
+
+func (m *Manager) RefreshTTL() time.Duration {
+	return m.refreshTTL
+}
+
+// IssueAccessToken выпускает access-токен для сессии пользователя
+func (m *Manager) IssueAccessToken(user *models.User, session *models.Session) (string, error) {
+	now := time.Now()
+	return m.signer.Sign(Claims{
+		ID:        uuid.NewString(),
+		Subject:   user.ID,
+		Login:     user.Login,
+		Role:      user.Permission,
+		SessionID: session.ID,
+		DeviceID:  session.Device.ID,
+		Locale:    user.Locale,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(m.accessTTL).Unix(),
+	})
+}
+
+// Verify проверяет access-токен. Токены отозванной сессии недействительны
+func (m *Manager) Verify(token string) (*Claims, error) {
+	claims, err := m.signer.Verify(token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if m.revocations.IsRevoked(claims.SessionID) {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// RevocationExpiry возвращает момент, до которого нужно хранить отзыв
+// сессии: позже этого срока ее access-токены истекут сами
+func (m *Manager) RevocationExpiry() time.Time {
+	return time.Now().Add(m.accessTTL)
+}
+
+// Revoke добавляет сессии в локальный кеш отзывов
+func (m *Manager) Revoke(until time.Time, sessionIDs ...uuid.UUID) {
+	for _, id := range sessionIDs {
+		m.revocations.Revoke(id, until)
+	}
+}
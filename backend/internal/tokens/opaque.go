@@ -0,0 +1,26 @@
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// NewOpaqueToken возвращает случайный непрозрачный токен (refresh-токен,
+// токен сброса пароля). В БД хранится только его хеш (HashOpaqueToken),
+// сам токен знает лишь клиент
+func NewOpaqueToken() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// HashOpaqueToken возвращает SHA-256 токена в hex. У токена 256 бит
+// энтропии, поэтому соль и медленный хеш не нужны
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,89 @@
+package tokens
+
+import (
+	"context"
+	"music-service/internal/logging"
+	"music-service/internal/models"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevocationLoader загружает действующие отзывы сессий из хранилища
+type RevocationLoader func(ctx context.Context) ([]models.RevokedSession, error)
+
+// RevocationList — кеш отозванных сессий. Access-токены проверяются без
+// обращения к БД, поэтому отзыв (выход, обнаружение повторного
+// использования refresh-токена) должен действовать до истечения последнего
+// выданного сессии access-токена. Отзывы других экземпляров сервиса
+// подтягиваются из БД методом Run
+type RevocationList struct {
+	mu       sync.RWMutex
+	sessions map[uuid.UUID]time.Time
+}
+
+func NewRevocationList() *RevocationList {
+	return &RevocationList{sessions: make(map[uuid.UUID]time.Time)}
+}
+
+// Revoke отзывает сессию до момента until
+func (l *RevocationList) Revoke(sessionID uuid.UUID, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if current, ok := l.sessions[sessionID]; !ok || until.After(current) {
+		l.sessions[sessionID] = until
+	}
+}
+
+func (l *RevocationList) IsRevoked(sessionID uuid.UUID) bool {
+	l.mu.RLock()
+	until, ok := l.sessions[sessionID]
+	l.mu.RUnlock()
+
+	return ok && time.Now().Before(until)
+}
+
+// Merge добавляет загруженные отзывы и удаляет истекшие
+func (l *RevocationList) Merge(revoked []models.RevokedSession) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for id, until := range l.sessions {
+		if !now.Before(until) {
+			delete(l.sessions, id)
+		}
+	}
+	for _, r := range revoked {
+		if current, ok := l.sessions[r.SessionID]; !ok || r.ExpiresAt.After(current) {
+			l.sessions[r.SessionID] = r.ExpiresAt
+		}
+	}
+}
+
+// Run периодически синхронизирует кеш с хранилищем, пока не отменен ctx
+func (l *RevocationList) Run(ctx context.Context, load RevocationLoader, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if revoked, err := load(ctx); err != nil {
+			logging.FromContext(ctx).Error("load revoked sessions failed", "error", err)
+		} else {
+			l.Merge(revoked)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
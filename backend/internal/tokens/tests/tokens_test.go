@@ -0,0 +1,266 @@
+package tests
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"music-service/internal/models"
+	"music-service/internal/tokens"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	oldKey = []byte("0123456789abcdef0123456789abcdef")
+	newKey = []byte("fedcba9876543210fedcba9876543210")
+)
+
+func newSigner(t *testing.T, activeKID string, keys map[string][]byte) *tokens.Signer {
+	t.Helper()
+	signer, err := tokens.NewSigner(activeKID, keys)
+	require.NoError(t, err)
+	return signer
+}
+
+func claimsAt(now time.Time) tokens.Claims {
+	return tokens.Claims{
+		ID:        uuid.NewString(),
+		Subject:   uuid.New(),
+		Login:     "listener",
+		Role:      models.UserPermission,
+		SessionID: uuid.New(),
+		DeviceID:  uuid.New(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(15 * time.Minute).Unix(),
+	}
+}
+
+func segment(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// forge собирает токен с произвольным заголовком и подписывает его key по
+// HS256; без key подпись пустая
+func forge(t *testing.T, header map[string]string, claims tokens.Claims, key []byte) string {
+	t.Helper()
+	signingInput := segment(t, header) + "." + segment(t, claims)
+	if key == nil {
+		return signingInput + "."
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestSigner_RoundTrip(t *testing.T) {
+	now := time.Now()
+	signer := newSigner(t, "k1", map[string][]byte{"k1": oldKey})
+	claims := claimsAt(now)
+
+	token, err := signer.Sign(claims)
+	require.NoError(t, err)
+
+	verified, err := signer.Verify(token, now)
+	require.NoError(t, err)
+	assert.Equal(t, claims, *verified)
+}
+
+func TestSigner_Rejects(t *testing.T) {
+	now := time.Now()
+	signer := newSigner(t, "k1", map[string][]byte{"k1": oldKey})
+	claims := claimsAt(now)
+
+	valid, err := signer.Sign(claims)
+	require.NoError(t, err)
+	parts := strings.Split(valid, ".")
+
+	// Подпись с измененным первым символом
+	tampered := []byte(parts[2])
+	if tampered[0] == 'A' {
+		tampered[0] = 'B'
+	} else {
+		tampered[0] = 'A'
+	}
+
+	// Другие данные с подписью исходного токена
+	changed := claims
+	changed.Role = models.AdminPermission
+
+	cases := []struct {
+		name  string
+		token string
+	}{
+		{"alg none", forge(t, map[string]string{"alg": "none", "typ": "JWT", "kid": "k1"}, claims, nil)},
+		{"alg none with signature", forge(t, map[string]string{"alg": "none", "typ": "JWT", "kid": "k1"}, claims, oldKey)},
+		// Подпись верна, но алгоритм в заголовке не HS256
+		{"wrong alg", forge(t, map[string]string{"alg": "HS512", "typ": "JWT", "kid": "k1"}, claims, oldKey)},
+		{"unknown kid", forge(t, map[string]string{"alg": "HS256", "typ": "JWT", "kid": "k2"}, claims, oldKey)},
+		{"foreign key", forge(t, map[string]string{"alg": "HS256", "typ": "JWT", "kid": "k1"}, claims, newKey)},
+		{"tampered signature", parts[0] + "." + parts[1] + "." + string(tampered)},
+		{"tampered claims", parts[0] + "." + segment(t, changed) + "." + parts[2]},
+		{"missing signature", parts[0] + "." + parts[1]},
+		{"garbage", "not.a.token"},
+		{"empty", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := signer.Verify(tc.token, now)
+			assert.ErrorIs(t, err, tokens.ErrInvalidToken)
+		})
+	}
+}
+
+func TestSigner_Expired(t *testing.T) {
+	now := time.Now()
+	signer := newSigner(t, "k1", map[string][]byte{"k1": oldKey})
+	claims := claimsAt(now)
+
+	token, err := signer.Sign(claims)
+	require.NoError(t, err)
+
+	_, err = signer.Verify(token, time.Unix(claims.ExpiresAt-1, 0))
+	assert.NoError(t, err)
+	// Токен недействителен начиная с момента exp
+	_, err = signer.Verify(token, time.Unix(claims.ExpiresAt, 0))
+	assert.ErrorIs(t, err, tokens.ErrExpiredToken)
+}
+
+func TestSigner_KeyRotation(t *testing.T) {
+	now := time.Now()
+	claims := claimsAt(now)
+
+	previous := newSigner(t, "k1", map[string][]byte{"k1": oldKey})
+	issued, err := previous.Sign(claims)
+	require.NoError(t, err)
+
+	// Пока старый ключ в наборе, выданные им токены действительны, а новые
+	// подписываются активным ключом
+	rotated := newSigner(t, "k2", map[string][]byte{"k1": oldKey, "k2": newKey})
+	_, err = rotated.Verify(issued, now)
+	assert.NoError(t, err)
+
+	fresh, err := rotated.Sign(claims)
+	require.NoError(t, err)
+	_, err = previous.Verify(fresh, now)
+	assert.ErrorIs(t, err, tokens.ErrInvalidToken, "new tokens must be signed with k2")
+
+	// После удаления старого ключа его токены отклоняются
+	retired := newSigner(t, "k2", map[string][]byte{"k2": newKey})
+	_, err = retired.Verify(issued, now)
+	assert.ErrorIs(t, err, tokens.ErrInvalidToken)
+	_, err = retired.Verify(fresh, now)
+	assert.NoError(t, err)
+}
+
+func TestNewSigner_InvalidKeys(t *testing.T) {
+	_, err := tokens.NewSigner("k2", map[string][]byte{"k1": oldKey})
+	assert.Error(t, err)
+	_, err = tokens.NewSigner("k1", map[string][]byte{"k1": []byte("short")})
+	assert.Error(t, err)
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := tokens.ParseKeys("k1:" + base64.StdEncoding.EncodeToString(oldKey) + ", k2:" + base64.StdEncoding.EncodeToString(newKey))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"k1": oldKey, "k2": newKey}, keys)
+
+	for _, value := range []string{"", "k1", ":" + base64.StdEncoding.EncodeToString(oldKey), "k1:not base64!"} {
+		_, err := tokens.ParseKeys(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestRevocationList(t *testing.T) {
+	list := tokens.NewRevocationList()
+	sessionID := uuid.New()
+
+	assert.False(t, list.IsRevoked(sessionID))
+
+	list.Revoke(sessionID, time.Now().Add(time.Hour))
+	assert.True(t, list.IsRevoked(sessionID))
+
+	// Более ранний срок не сокращает действующий отзыв
+	list.Revoke(sessionID, time.Now().Add(-time.Hour))
+	assert.True(t, list.IsRevoked(sessionID))
+
+	// Отзыв действует до истечения срока, после него сессия снова
+	// действительна
+	expiring := uuid.New()
+	list.Revoke(expiring, time.Now().Add(20*time.Millisecond))
+	assert.True(t, list.IsRevoked(expiring))
+	time.Sleep(30 * time.Millisecond)
+	assert.False(t, list.IsRevoked(expiring))
+}
+
+func TestRevocationList_Merge(t *testing.T) {
+	list := tokens.NewRevocationList()
+	local, loaded, stale := uuid.New(), uuid.New(), uuid.New()
+
+	list.Revoke(local, time.Now().Add(20*time.Millisecond))
+	list.Merge([]models.RevokedSession{
+		{SessionID: loaded, ExpiresAt: time.Now().Add(time.Hour)},
+		{SessionID: stale, ExpiresAt: time.Now().Add(-time.Minute)},
+		// Отзыв из БД продлевает локальный
+		{SessionID: local, ExpiresAt: time.Now().Add(time.Hour)},
+	})
+
+	assert.True(t, list.IsRevoked(loaded))
+	assert.False(t, list.IsRevoked(stale))
+	time.Sleep(30 * time.Millisecond)
+	assert.True(t, list.IsRevoked(local))
+
+	// Истекшие отзывы удаляются при следующей синхронизации и больше не
+	// действуют
+	expired := uuid.New()
+	list.Revoke(expired, time.Now().Add(10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+	list.Merge(nil)
+	assert.False(t, list.IsRevoked(expired))
+	assert.True(t, list.IsRevoked(loaded))
+}
+
+func TestManager(t *testing.T) {
+	signer := newSigner(t, "k1", map[string][]byte{"k1": oldKey})
+	manager := tokens.NewManager(signer, tokens.NewRevocationList(), 0, 0)
+	assert.Equal(t, tokens.DefaultAccessTTL, manager.AccessTTL())
+	assert.Equal(t, tokens.DefaultRefreshTTL, manager.RefreshTTL())
+
+	user := &models.User{ID: uuid.New(), Login: "listener", Permission: models.UserPermission, Locale: "en"}
+	session := &models.Session{ID: uuid.New(), UserID: user.ID, Device: models.Device{ID: uuid.New()}}
+
+	token, err := manager.IssueAccessToken(user, session)
+	require.NoError(t, err)
+
+	claims, err := manager.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.Subject)
+	assert.Equal(t, session.ID, claims.SessionID)
+	assert.Equal(t, session.Device.ID, claims.DeviceID)
+	assert.Equal(t, "en", claims.Locale)
+
+	// Токены отозванной сессии недействительны до истечения срока отзыва
+	manager.Revoke(manager.RevocationExpiry(), session.ID)
+	_, err = manager.Verify(token)
+	assert.ErrorIs(t, err, tokens.ErrInvalidToken)
+}
+
+func TestOpaqueToken(t *testing.T) {
+	token := tokens.NewOpaqueToken()
+	assert.Len(t, token, 43)
+	assert.NotEqual(t, token, tokens.NewOpaqueToken())
+
+	hash := tokens.HashOpaqueToken(token)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, tokens.HashOpaqueToken(token))
+	assert.NotEqual(t, hash, tokens.HashOpaqueToken(tokens.NewOpaqueToken()))
+	assert.NotContains(t, hash, token)
+}
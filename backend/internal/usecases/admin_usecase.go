@@ -0,0 +1,293 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"music-service/internal/authz"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"music-service/internal/tokens"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+
+	maxSuspensionReasonLength = 500
+)
+
+type adminUseCase struct {
+	userRepo     interfaces.UserRepository
+	playlistRepo interfaces.PlaylistRepository
+	historyRepo  interfaces.HistoryRepository
+	auditRepo    interfaces.AuditRepository
+	uow          interfaces.UnitOfWork
+	tokens       *tokens.Manager
+}
+
+// NewAdminUseCase создает use case управления пользователями. Все действия
+// администратора записываются в журнал (models.AuditEntry)
+func NewAdminUseCase(
+	userRepo interfaces.UserRepository,
+	playlistRepo interfaces.PlaylistRepository,
+	historyRepo interfaces.HistoryRepository,
+	auditRepo interfaces.AuditRepository,
+	uow interfaces.UnitOfWork,
+	tokenManager *tokens.Manager,
+) usecaseInterfaces.AdminUseCase {
+	return &adminUseCase{
+		userRepo:     userRepo,
+		playlistRepo: playlistRepo,
+		historyRepo:  historyRepo,
+		auditRepo:    auditRepo,
+		uow:          uow,
+		tokens:       tokenManager,
+	}
+}
+
+// ListUsers возвращает страницу пользователей с фильтрами по логину или
+// почте, роли и блокировке
+func (uc *adminUseCase) ListUsers(ctx context.Context, filter models.UserFilter) (*models.UserPage, error) {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+
+	if filter.Permission != "" && !filter.Permission.IsValid() {
+		return nil, models.ErrInvalidPermission
+	}
+	switch filter.Status {
+	case "", models.UserStatusActive, models.UserStatusSuspended:
+	default:
+		return nil, models.ErrInvalidUserStatus
+	}
+	filter.Limit, filter.Offset = normalizePage(filter.Limit, filter.Offset)
+
+	page, err := uc.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return page, nil
+}
+
+func (uc *adminUseCase) GetUser(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrUserNotFound)
+	}
+	return user, nil
+}
+
+// SuspendUser блокирует пользователя до until (nil — бессрочно) и завершает
+// все его сессии. Повторная блокировка заменяет предыдущую
+func (uc *adminUseCase) SuspendUser(ctx context.Context, userID uuid.UUID, reason string, until *time.Time) (*models.User, error) {
+	principal, err := authz.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.Can(authz.UserManage) {
+		return nil, authz.ErrForbidden
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, models.ErrSuspensionReasonRequired
+	}
+	if utf8.RuneCountInString(reason) > maxSuspensionReasonLength {
+		return nil, models.ErrSuspensionReasonTooLong
+	}
+	now := time.Now()
+	if until != nil && !until.After(now) {
+		return nil, models.ErrSuspensionEndInPast
+	}
+	if userID == principal.UserID {
+		return nil, models.ErrCannotSuspendSelf
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrUserNotFound)
+	}
+
+	suspension := &models.Suspension{
+		Reason:      reason,
+		SuspendedAt: now,
+		Until:       until,
+	}
+	if principal.UserID != uuid.Nil {
+		suspendedBy := principal.UserID
+		suspension.SuspendedBy = &suspendedBy
+	}
+
+	revokeUntil := uc.tokens.RevocationExpiry()
+	var revoked []uuid.UUID
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.User.Suspend(ctx, userID, suspension); err != nil {
+			return err
+		}
+		var err error
+		if revoked, err = repos.Session.RevokeAllForUser(ctx, userID, revokeUntil); err != nil {
+			return err
+		}
+		return recordAudit(ctx, repos.Audit, models.AuditUserSuspend, models.AuditEntityUser, userID.String(), map[string]interface{}{
+			"reason":           reason,
+			"until":            until,
+			"revoked_sessions": len(revoked),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to suspend user: %w", err)
+	}
+
+	uc.tokens.Revoke(revokeUntil, revoked...)
+	user.Suspension = suspension
+	return user, nil
+}
+
+// UnsuspendUser снимает блокировку. Завершенные сессии не восстанавливаются
+func (uc *adminUseCase) UnsuspendUser(ctx context.Context, userID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return lookupError(err, models.ErrUserNotFound)
+	}
+	if !user.IsSuspended(time.Now()) {
+		return models.ErrUserNotSuspended
+	}
+
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.User.Unsuspend(ctx, userID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, repos.Audit, models.AuditUserUnsuspend, models.AuditEntityUser, userID.String(), map[string]interface{}{
+			"reason": user.Suspension.Reason,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unsuspend user: %w", err)
+	}
+	return nil
+}
+
+// ForceLogout завершает все сессии пользователя и возвращает их количество
+func (uc *adminUseCase) ForceLogout(ctx context.Context, userID uuid.UUID) (int, error) {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return 0, err
+	}
+
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		return 0, lookupError(err, models.ErrUserNotFound)
+	}
+
+	until := uc.tokens.RevocationExpiry()
+	var revoked []uuid.UUID
+	err := uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		var err error
+		if revoked, err = repos.Session.RevokeAllForUser(ctx, userID, until); err != nil {
+			return err
+		}
+		return recordAudit(ctx, repos.Audit, models.AuditUserForceLogout, models.AuditEntityUser, userID.String(), map[string]interface{}{
+			"revoked_sessions": len(revoked),
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	uc.tokens.Revoke(until, revoked...)
+	return len(revoked), nil
+}
+
+// GetUserPlaylists возвращает все плейлисты пользователя, включая закрытые.
+// Просмотр чужих данных записывается в журнал
+func (uc *adminUseCase) GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error) {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, lookupError(err, models.ErrUserNotFound)
+	}
+	if err := recordAudit(ctx, uc.auditRepo, models.AuditUserViewPlaylists, models.AuditEntityUser, userID.String(), nil); err != nil {
+		return nil, err
+	}
+
+	playlists, err := uc.playlistRepo.GetUserPlaylists(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user playlists: %w", err)
+	}
+	return playlists, nil
+}
+
+// GetUserHistory возвращает последние прослушивания пользователя.
+// Просмотр чужих данных записывается в журнал
+func (uc *adminUseCase) GetUserHistory(ctx context.Context, userID uuid.UUID) ([]*models.ListeningHistory, error) {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, lookupError(err, models.ErrUserNotFound)
+	}
+	if err := recordAudit(ctx, uc.auditRepo, models.AuditUserViewHistory, models.AuditEntityUser, userID.String(), nil); err != nil {
+		return nil, err
+	}
+
+	history, err := uc.historyRepo.GetHistory(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listening history: %w", err)
+	}
+	return latestHistory(history), nil
+}
+
+func (uc *adminUseCase) ListAuditLog(ctx context.Context, filter models.AuditFilter) (*models.AuditPage, error) {
+	if err := authz.Require(ctx, authz.AuditRead); err != nil {
+		return nil, err
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, models.ErrInvalidTimeRange
+	}
+	filter.Limit, filter.Offset = normalizePage(filter.Limit, filter.Offset)
+	page, err := uc.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	return page, nil
+}
+
+// PurgeAuditLog удаляет записи журнала старше before. Вызывается фоновой
+// задачей по сроку хранения
+func (uc *adminUseCase) PurgeAuditLog(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := uc.auditRepo.DeleteBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge audit log: %w", err)
+	}
+	return deleted, nil
+}
+
+// normalizePage подставляет размер страницы по умолчанию и ограничивает его сверху
+func normalizePage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -1,8 +1,11 @@
 package usecases
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"music-service/internal/authz"
+	"music-service/internal/events"
+	"music-service/internal/logging"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
 	usecaseInterfaces "music-service/internal/usecases/interfaces"
@@ -15,55 +18,71 @@ import (
 )
 
 type albumUseCase struct {
-	albumRepo interfaces.AlbumRepository
-	trackRepo interfaces.TrackRepository
+	albumRepo  interfaces.AlbumRepository
+	trackRepo  interfaces.TrackRepository
+	followRepo interfaces.FollowRepository
+	uow        interfaces.UnitOfWork
+	publisher  events.Publisher
+	localizer  catalogLocalizer
 }
 
 func NewAlbumUseCase(
 	albumRepo interfaces.AlbumRepository,
 	trackRepo interfaces.TrackRepository,
+	followRepo interfaces.FollowRepository,
+	translationRepo interfaces.TranslationRepository,
+	uow interfaces.UnitOfWork,
+	publisher events.Publisher,
 ) usecaseInterfaces.AlbumUseCase {
 	return &albumUseCase{
-		albumRepo: albumRepo,
-		trackRepo: trackRepo,
+		albumRepo:  albumRepo,
+		trackRepo:  trackRepo,
+		followRepo: followRepo,
+		uow:        uow,
+		publisher:  publisher,
+		localizer:  newCatalogLocalizer(translationRepo),
 	}
 }
 
-func (uc *albumUseCase) CreateAlbum(title string, artist string, releaseDate time.Time, coverURL string) (*models.Album, error) {
+func (uc *albumUseCase) CreateAlbum(ctx context.Context, title string, artist string, releaseDate time.Time, coverURL string) (*models.Album, error) {
+	if err := authz.Require(ctx, authz.AlbumEdit); err != nil {
+		return nil, err
+	}
+
 	title = strings.TrimSpace(title)
 	if utf8.RuneCountInString(title) < 2 {
-		return nil, errors.New("album title must be at least 2 characters")
+		return nil, models.ErrAlbumTitleTooShort
 	}
 	if utf8.RuneCountInString(title) > 100 {
-		return nil, errors.New("album title is too long (max 100 characters)")
+		return nil, models.ErrAlbumTitleTooLong
 	}
 
 	artist = strings.TrimSpace(artist)
 	if artist == "" {
-		return nil, errors.New("artist name is required")
+		return nil, models.ErrArtistNameRequired
 	}
 	if utf8.RuneCountInString(artist) > 100 {
-		return nil, errors.New("artist name is too long (max 100 characters)")
+		return nil, models.ErrArtistNameTooLong
 	}
 
 	if releaseDate.After(time.Now().Add(24 * time.Hour)) {
-		return nil, errors.New("release date cannot be in the future")
+		return nil, models.ErrReleaseDateInFuture
 	}
 
 	if coverURL != "" {
 		if _, err := url.ParseRequestURI(coverURL); err != nil {
-			return nil, errors.New("invalid cover URL format")
+			return nil, models.ErrInvalidCoverURL
 		}
 	}
 
-	existingAlbums, err := uc.albumRepo.ListAll()
+	existingAlbums, err := uc.albumRepo.ListAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing albums: %w", err)
 	}
 
 	for _, a := range existingAlbums {
 		if strings.EqualFold(a.Title, title) && strings.EqualFold(a.Artist, artist) {
-			return nil, errors.New("album with this title and artist already exists")
+			return nil, models.ErrAlbumExists
 		}
 	}
 
@@ -77,113 +96,182 @@ func (uc *albumUseCase) CreateAlbum(title string, artist string, releaseDate tim
 		UpdatedAt:   time.Now(),
 	}
 
-	if err := uc.albumRepo.Save(album); err != nil {
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Album.Save(ctx, album); err != nil {
+			return err
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditAlbumCreate,
+			EntityType: models.AuditEntityAlbum,
+			EntityID:   album.ID.String(),
+			After:      albumSnapshot(album),
+		})
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to save album: %w", err)
 	}
 
+	uc.notifyNewRelease(ctx, album)
+
 	return album, nil
 }
 
-func (uc *albumUseCase) AddTrackToAlbum(albumID, trackID uuid.UUID) error {
-	album, err := uc.albumRepo.FindByID(albumID)
+// notifyNewRelease уведомляет подписчиков исполнителя о новом альбоме
+func (uc *albumUseCase) notifyNewRelease(ctx context.Context, album *models.Album) {
+	followers, err := uc.followRepo.GetArtistFollowers(ctx, album.Artist)
+	if err != nil {
+		logging.FromContext(ctx).Warn("get artist followers failed", "artist", album.Artist, "error", err)
+		return
+	}
+
+	uc.publisher.Publish(events.TypeArtistNewRelease, events.NewRelease{
+		Artist:      album.Artist,
+		AlbumID:     album.ID,
+		Title:       album.Title,
+		CoverURL:    album.CoverURL,
+		ReleaseDate: album.ReleaseDate,
+	}, followers...)
+}
+
+func (uc *albumUseCase) AddTrackToAlbum(ctx context.Context, albumID, trackID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.AlbumEdit); err != nil {
+		return err
+	}
+
+	album, err := uc.albumRepo.FindByID(ctx, albumID)
 	if err != nil {
-		return fmt.Errorf("album not found: %w", err)
+		return lookupError(err, models.ErrAlbumNotFound)
 	}
 
-	track, err := uc.trackRepo.FindByID(trackID)
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
 	if err != nil {
-		return fmt.Errorf("track not found: %w", err)
+		return lookupError(err, models.ErrTrackNotFound)
 	}
 
 	if track.AlbumID != uuid.Nil && track.AlbumID != albumID {
-		return errors.New("track already belongs to another album")
+		return models.ErrTrackInAnotherAlbum
 	}
 
-	tracks, err := uc.albumRepo.GetTracks(albumID)
+	tracks, err := uc.albumRepo.GetTracks(ctx, albumID)
 	if err != nil {
 		return fmt.Errorf("failed to get album tracks: %w", err)
 	}
 
 	for _, t := range tracks {
 		if t.ID == trackID {
-			return errors.New("track already exists in album")
+			return models.ErrTrackAlreadyInAlbum
 		}
 	}
 
 	if len(tracks) >= 50 {
-		return errors.New("album cannot contain more than 50 tracks")
-	}
-
-	if err := uc.albumRepo.AddTrackToAlbum(albumID, trackID); err != nil {
-		return fmt.Errorf("failed to add track to album: %w", err)
+		return models.ErrAlbumTrackLimit
 	}
 
 	track.AlbumID = albumID
-	if err := uc.trackRepo.Save(track); err != nil {
-		return fmt.Errorf("failed to update track: %w", err)
-	}
-
 	album.UpdatedAt = time.Now()
-	return uc.albumRepo.Save(album)
+
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Album.AddTrackToAlbum(ctx, albumID, trackID); err != nil {
+			return fmt.Errorf("failed to add track to album: %w", err)
+		}
+		if err := repos.Track.Save(ctx, track); err != nil {
+			return fmt.Errorf("failed to update track: %w", err)
+		}
+		if err := repos.Album.Save(ctx, album); err != nil {
+			return err
+		}
+		if track.Loudness != nil {
+			if err := refreshAlbumLoudness(ctx, repos.Album, albumID); err != nil {
+				return err
+			}
+		}
+		return recordAudit(ctx, repos.Audit, models.AuditAlbumTrackAdd, models.AuditEntityAlbum, albumID.String(), map[string]interface{}{
+			"track_id": trackID,
+			"title":    track.Title,
+		})
+	})
 }
 
-func (uc *albumUseCase) RemoveTrackFromAlbum(albumID, trackID uuid.UUID) error {
-	album, err := uc.albumRepo.FindByID(albumID)
-	if err != nil {
-		return fmt.Errorf("album not found: %w", err)
+func (uc *albumUseCase) RemoveTrackFromAlbum(ctx context.Context, albumID, trackID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.AlbumEdit); err != nil {
+		return err
 	}
 
-	track, err := uc.trackRepo.FindByID(trackID)
+	album, err := uc.albumRepo.FindByID(ctx, albumID)
 	if err != nil {
-		return fmt.Errorf("track not found: %w", err)
+		return lookupError(err, models.ErrAlbumNotFound)
 	}
 
-	if track.AlbumID != albumID {
-		return errors.New("track does not belong to this album")
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
+	if err != nil {
+		return lookupError(err, models.ErrTrackNotFound)
 	}
 
-	if err := uc.albumRepo.RemoveTrackFromAlbum(albumID, trackID); err != nil {
-		return fmt.Errorf("failed to remove track from album: %w", err)
+	if track.AlbumID != albumID {
+		return models.ErrTrackNotInAlbum
 	}
 
 	track.AlbumID = uuid.Nil
-	if err := uc.trackRepo.Save(track); err != nil {
-		return fmt.Errorf("failed to update track: %w", err)
-	}
-
 	album.UpdatedAt = time.Now()
-	return uc.albumRepo.Save(album)
+
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Album.RemoveTrackFromAlbum(ctx, albumID, trackID); err != nil {
+			return fmt.Errorf("failed to remove track from album: %w", err)
+		}
+		if err := repos.Track.Save(ctx, track); err != nil {
+			return fmt.Errorf("failed to update track: %w", err)
+		}
+		if err := repos.Album.Save(ctx, album); err != nil {
+			return err
+		}
+		if track.Loudness != nil {
+			if err := refreshAlbumLoudness(ctx, repos.Album, albumID); err != nil {
+				return err
+			}
+		}
+		return recordAudit(ctx, repos.Audit, models.AuditAlbumTrackRemove, models.AuditEntityAlbum, albumID.String(), map[string]interface{}{
+			"track_id": trackID,
+			"title":    track.Title,
+		})
+	})
 }
 
-func (uc *albumUseCase) GetAlbumDetails(albumID uuid.UUID) (*models.Album, []*models.Track, error) {
-	album, err := uc.albumRepo.FindByID(albumID)
+func (uc *albumUseCase) GetAlbumDetails(ctx context.Context, albumID uuid.UUID) (*models.Album, []*models.Track, error) {
+	album, err := uc.albumRepo.FindByID(ctx, albumID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("album not found: %w", err)
+		return nil, nil, lookupError(err, models.ErrAlbumNotFound)
 	}
 
-	tracks, err := uc.albumRepo.GetTracks(albumID)
+	tracks, err := uc.albumRepo.GetTracks(ctx, albumID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get album tracks: %w", err)
 	}
 
 	sortTracks(tracks)
+	uc.localizer.albums(ctx, []*models.Album{album})
+	uc.localizer.tracks(ctx, tracks)
 
 	return album, tracks, nil
 }
 
-func (uc *albumUseCase) UpdateAlbumInfo(albumID uuid.UUID, title, artist, coverURL string, releaseDate time.Time) error {
-	album, err := uc.albumRepo.FindByID(albumID)
+func (uc *albumUseCase) UpdateAlbumInfo(ctx context.Context, albumID uuid.UUID, title, artist, coverURL string, releaseDate time.Time) error {
+	if err := authz.Require(ctx, authz.AlbumEdit); err != nil {
+		return err
+	}
+
+	album, err := uc.albumRepo.FindByID(ctx, albumID)
 	if err != nil {
-		return fmt.Errorf("album not found: %w", err)
+		return lookupError(err, models.ErrAlbumNotFound)
 	}
+	before := albumSnapshot(album)
 
 	if title != "" {
 		title = strings.TrimSpace(title)
 		if utf8.RuneCountInString(title) < 2 {
-			return errors.New("album title must be at least 2 characters")
+			return models.ErrAlbumTitleTooShort
 		}
 		if utf8.RuneCountInString(title) > 100 {
-			return errors.New("album title is too long (max 100 characters)")
+			return models.ErrAlbumTitleTooLong
 		}
 		album.Title = title
 	}
@@ -191,59 +279,94 @@ func (uc *albumUseCase) UpdateAlbumInfo(albumID uuid.UUID, title, artist, coverU
 	if artist != "" {
 		artist = strings.TrimSpace(artist)
 		if utf8.RuneCountInString(artist) > 100 {
-			return errors.New("artist name is too long (max 100 characters)")
+			return models.ErrArtistNameTooLong
 		}
 		album.Artist = artist
 	}
 
 	if coverURL != "" {
 		if _, err := url.ParseRequestURI(coverURL); err != nil {
-			return errors.New("invalid cover URL format")
+			return models.ErrInvalidCoverURL
 		}
 		album.CoverURL = coverURL
 	}
 
 	if !releaseDate.IsZero() {
 		if releaseDate.After(time.Now().Add(24 * time.Hour)) {
-			return errors.New("release date cannot be in the future")
+			return models.ErrReleaseDateInFuture
 		}
 		album.ReleaseDate = releaseDate
 	}
 
 	album.UpdatedAt = time.Now()
-	return uc.albumRepo.Save(album)
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Album.Save(ctx, album); err != nil {
+			return err
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditAlbumUpdate,
+			EntityType: models.AuditEntityAlbum,
+			EntityID:   albumID.String(),
+			Before:     before,
+			After:      albumSnapshot(album),
+		})
+	})
 }
 
-func (uc *albumUseCase) ListAll() ([]*models.Album, error) {
-	albums, err := uc.albumRepo.ListAll()
+func (uc *albumUseCase) ListAll(ctx context.Context) ([]*models.Album, error) {
+	albums, err := uc.albumRepo.ListAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list albums: %w", err)
 	}
+	uc.localizer.albums(ctx, albums)
 	return albums, nil
 }
 
-func (uc *albumUseCase) Delete(albumID uuid.UUID) error {
-	_, err := uc.albumRepo.FindByID(albumID)
+func (uc *albumUseCase) Delete(ctx context.Context, albumID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.AlbumEdit); err != nil {
+		return err
+	}
+
+	album, err := uc.albumRepo.FindByID(ctx, albumID)
 	if err != nil {
-		return fmt.Errorf("album not found: %w", err)
+		return lookupError(err, models.ErrAlbumNotFound)
 	}
 
-	tracks, err := uc.albumRepo.GetTracks(albumID)
+	tracks, err := uc.albumRepo.GetTracks(ctx, albumID)
 	if err != nil {
 		return fmt.Errorf("failed to get album tracks: %w", err)
 	}
 
-	for _, track := range tracks {
-		if err := uc.trackRepo.Delete(track.ID); err != nil {
-			return fmt.Errorf("failed to delete track %s: %w", track.ID, err)
+	// Альбом попадает в корзину вместе с треками и восстанавливается вместе
+	// с ними; файлы удаляются при окончательном удалении
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Album.Delete(ctx, albumID, time.Now()); err != nil {
+			return fmt.Errorf("failed to delete album: %w", err)
 		}
-	}
 
-	if err := uc.albumRepo.Delete(albumID); err != nil {
-		return fmt.Errorf("failed to delete album: %w", err)
-	}
+		for _, track := range tracks {
+			// Трек удален вместе с альбомом — запись нужна, чтобы его
+			// удаление находилось в журнале по ID трека
+			err := writeAudit(ctx, repos.Audit, auditRecord{
+				Action:     models.AuditTrackDelete,
+				EntityType: models.AuditEntityTrack,
+				EntityID:   track.ID.String(),
+				Before:     trackSnapshot(track),
+				Details:    map[string]interface{}{"album_id": albumID},
+			})
+			if err != nil {
+				return err
+			}
+		}
 
-	return nil
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditAlbumDelete,
+			EntityType: models.AuditEntityAlbum,
+			EntityID:   albumID.String(),
+			Before:     albumSnapshot(album),
+			Details:    map[string]interface{}{"deleted_tracks": len(tracks)},
+		})
+	})
 }
 
 func sortTracks(tracks []*models.Track) {
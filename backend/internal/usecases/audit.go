@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"music-service/internal/audit"
+	"music-service/internal/authz"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// auditRecord — действие для журнала. Before и After — снимки отслеживаемых
+// полей сущности до и после действия; в журнал попадают только различия
+type auditRecord struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     audit.Snapshot
+	After      audit.Snapshot
+	Details    interface{}
+}
+
+// recordAudit записывает действие пользователя из ctx в журнал. Для
+// изменений журнал пишется в той же транзакции, что и само изменение,
+// чтобы действие не могло пройти без записи
+func recordAudit(ctx context.Context, auditRepo interfaces.AuditRepository, action, entityType, entityID string, details interface{}) error {
+	return writeAudit(ctx, auditRepo, auditRecord{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    details,
+	})
+}
+
+// writeAudit записывает действие вместе с изменениями полей, автором из
+// authz и адресом и идентификатором запроса из ctx
+func writeAudit(ctx context.Context, auditRepo interfaces.AuditRepository, record auditRecord) error {
+	request := audit.RequestInfoFromContext(ctx)
+	entry := &models.AuditEntry{
+		ID:         uuid.New(),
+		Action:     record.Action,
+		EntityType: record.EntityType,
+		EntityID:   record.EntityID,
+		IP:         request.IP,
+		RequestID:  request.RequestID,
+		CreatedAt:  time.Now(),
+	}
+	if principal, ok := authz.FromContext(ctx); ok {
+		if principal.UserID != uuid.Nil {
+			actorID := principal.UserID
+			entry.ActorID = &actorID
+		}
+		entry.ActorLogin = principal.Login
+	}
+	if record.Before != nil || record.After != nil {
+		changes, err := audit.Diff(record.Before, record.After)
+		if err != nil {
+			return fmt.Errorf("failed to build %s audit entry: %w", record.Action, err)
+		}
+		entry.Changes = changes
+	}
+	if record.Details != nil {
+		data, err := json.Marshal(record.Details)
+		if err != nil {
+			return fmt.Errorf("failed to build %s audit entry: %w", record.Action, err)
+		}
+		entry.Details = data
+	}
+
+	if err := auditRepo.Record(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record %s audit entry: %w", record.Action, err)
+	}
+	return nil
+}
+
+// trackSnapshot — поля трека, изменения которых попадают в журнал
+func trackSnapshot(track *models.Track) audit.Snapshot {
+	return audit.Snapshot{
+		"title":        track.Title,
+		"artist_name":  track.ArtistName,
+		"artists":      track.Artists,
+		"album_id":     track.AlbumID,
+		"track_number": track.TrackNumber,
+		"disc_number":  track.DiscNumber,
+		"duration":     track.Duration,
+		"cover_url":    track.CoverURL,
+		"explicit":     track.Explicit,
+		"isrc":         track.ISRC,
+		"lyrics_url":   track.LyricsURL,
+		"release_date": track.ReleaseDate,
+		"file_path":    track.FilePath,
+	}
+}
+
+// albumSnapshot — поля альбома, изменения которых попадают в журнал
+func albumSnapshot(album *models.Album) audit.Snapshot {
+	return audit.Snapshot{
+		"title":        album.Title,
+		"artist":       album.Artist,
+		"release_date": album.ReleaseDate.Format("2006-01-02"),
+		"cover_url":    album.CoverURL,
+	}
+}
+
+// genreNames — названия жанров для снимка жанров трека
+func genreNames(genres []*models.Genre) []string {
+	names := make([]string, 0, len(genres))
+	for _, genre := range genres {
+		names = append(names, genre.Name)
+	}
+	return names
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"music-service/internal/i18n"
+	"music-service/internal/logging"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+
+	"github.com/google/uuid"
+)
+
+// catalogLocalizer подставляет в треки, альбомы и жанры переводы на языки
+// запроса (i18n.Tags). Каждое поле берется из первого по предпочтению
+// перевода, где оно есть, иначе остается оригинальным. Перевод не влияет на
+// результат операции: если его не удалось загрузить, возвращается оригинал
+type catalogLocalizer struct {
+	translationRepo interfaces.TranslationRepository
+}
+
+func newCatalogLocalizer(translationRepo interfaces.TranslationRepository) catalogLocalizer {
+	return catalogLocalizer{translationRepo: translationRepo}
+}
+
+func (l catalogLocalizer) tracks(ctx context.Context, tracks []*models.Track) {
+	var ids, albumIDs []uuid.UUID
+	for _, track := range tracks {
+		ids = append(ids, track.ID)
+		if track.AlbumTitle != "" && track.AlbumID != uuid.Nil {
+			albumIDs = append(albumIDs, track.AlbumID)
+		}
+	}
+
+	locales, translations := l.find(ctx, models.TranslatableTrack, ids)
+	for _, track := range tracks {
+		fields := pickFields(translations[track.ID], locales)
+		localize(&track.Title, fields["title"])
+		localize(&track.ArtistName, fields["artist_name"])
+	}
+
+	if len(albumIDs) == 0 {
+		return
+	}
+	_, albumTranslations := l.find(ctx, models.TranslatableAlbum, albumIDs)
+	for _, track := range tracks {
+		localize(&track.AlbumTitle, pickFields(albumTranslations[track.AlbumID], locales)["title"])
+	}
+}
+
+func (l catalogLocalizer) trackDetails(ctx context.Context, details *models.TrackDetails) {
+	locales, translations := l.find(ctx, models.TranslatableTrack, []uuid.UUID{details.ID})
+	fields := pickFields(translations[details.ID], locales)
+	localize(&details.Title, fields["title"])
+	localize(&details.ArtistName, fields["artist_name"])
+
+	if details.Album != nil {
+		l.albums(ctx, []*models.Album{details.Album})
+	}
+	l.genres(ctx, details.Genres)
+}
+
+func (l catalogLocalizer) albums(ctx context.Context, albums []*models.Album) {
+	ids := make([]uuid.UUID, 0, len(albums))
+	for _, album := range albums {
+		ids = append(ids, album.ID)
+	}
+
+	locales, translations := l.find(ctx, models.TranslatableAlbum, ids)
+	for _, album := range albums {
+		fields := pickFields(translations[album.ID], locales)
+		localize(&album.Title, fields["title"])
+		localize(&album.Artist, fields["artist"])
+	}
+}
+
+func (l catalogLocalizer) genres(ctx context.Context, genres []*models.Genre) {
+	ids := make([]uuid.UUID, 0, len(genres))
+	for _, genre := range genres {
+		ids = append(ids, genre.ID)
+	}
+
+	locales, translations := l.find(ctx, models.TranslatableGenre, ids)
+	for _, genre := range genres {
+		localize(&genre.Name, pickFields(translations[genre.ID], locales)["name"])
+	}
+}
+
+// find загружает переводы объектов на языки запроса вместе с основными
+// языками ("en" для "en-US"). Если клиент не указал язык, переводы не нужны
+func (l catalogLocalizer) find(ctx context.Context, entityType string, ids []uuid.UUID) ([]string, map[uuid.UUID][]*models.Translation) {
+	locales := i18n.Fallbacks(i18n.Tags(ctx))
+	if len(locales) == 0 || len(ids) == 0 {
+		return nil, nil
+	}
+
+	translations, err := l.translationRepo.FindByEntities(ctx, entityType, ids, locales)
+	if err != nil {
+		logging.FromContext(ctx).Warn("load translations failed", "type", entityType, "error", err)
+		return nil, nil
+	}
+	return locales, translations
+}
+
+// pickFields собирает поля из переводов в порядке предпочтения языков
+func pickFields(translations []*models.Translation, locales []string) map[string]string {
+	fields := make(map[string]string)
+	for _, locale := range locales {
+		for _, translation := range translations {
+			if translation.Locale != locale {
+				continue
+			}
+			for field, value := range translation.Fields {
+				if _, ok := fields[field]; !ok {
+					fields[field] = value
+				}
+			}
+		}
+	}
+	return fields
+}
+
+func localize(value *string, translated string) {
+	if translated != "" {
+		*value = translated
+	}
+}
@@ -0,0 +1,585 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"music-service/internal/audit"
+	"music-service/internal/authz"
+	"music-service/internal/imaging"
+	"music-service/internal/logging"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultCoverMaxPixels = 40_000_000
+	// mosaicTiles — сколько обложек альбомов собирается в обложку плейлиста
+	mosaicTiles = 4
+	// mosaicSource — размер копии, из которой берутся плитки мозаики
+	mosaicSource = 640
+)
+
+var defaultCoverSizes = []int{64, 300, 640}
+
+// CoverConfig — параметры обработки обложек. Sizes — стороны квадратных
+// копий в пикселях; Formats — форматы копий в порядке предпочтения, JPEG
+// всегда добавляется последним как запасной. Форматы без кодировщика
+// пропускаются
+type CoverConfig struct {
+	MaxPixels int
+	Sizes     []int
+	Formats   []imaging.Format
+}
+
+type coverUseCase struct {
+	coverRepo    interfaces.CoverRepository
+	trackRepo    interfaces.TrackRepository
+	albumRepo    interfaces.AlbumRepository
+	playlistRepo interfaces.PlaylistRepository
+	uow          interfaces.UnitOfWork
+	maxPixels    int
+	sizes        []int
+	formats      []imaging.Format
+}
+
+func NewCoverUseCase(
+	coverRepo interfaces.CoverRepository,
+	trackRepo interfaces.TrackRepository,
+	albumRepo interfaces.AlbumRepository,
+	playlistRepo interfaces.PlaylistRepository,
+	uow interfaces.UnitOfWork,
+	cfg CoverConfig,
+) usecaseInterfaces.CoverUseCase {
+	if cfg.MaxPixels <= 0 {
+		cfg.MaxPixels = defaultCoverMaxPixels
+	}
+	sizes := slices.Clone(cfg.Sizes)
+	if len(sizes) == 0 {
+		sizes = slices.Clone(defaultCoverSizes)
+	}
+	slices.Sort(sizes)
+	sizes = slices.Compact(sizes)
+
+	var formats []imaging.Format
+	for _, format := range cfg.Formats {
+		if format != imaging.JPEG && imaging.CanEncode(format) && !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	formats = append(formats, imaging.JPEG)
+
+	return &coverUseCase{
+		coverRepo:    coverRepo,
+		trackRepo:    trackRepo,
+		albumRepo:    albumRepo,
+		playlistRepo: playlistRepo,
+		uow:          uow,
+		maxPixels:    cfg.MaxPixels,
+		sizes:        sizes,
+		formats:      formats,
+	}
+}
+
+// SetCover загружает обложку трека, альбома или плейлиста и подставляет ее
+// адрес в cover_url. Прежняя обложка остается доступной по своему адресу
+func (uc *coverUseCase) SetCover(ctx context.Context, entityType string, id uuid.UUID, file io.Reader) (*models.Cover, error) {
+	before, err := uc.authorizeCover(ctx, entityType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	cover, err := uc.storeCover(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Cover.Create(ctx, cover); err != nil {
+			return err
+		}
+		return uc.commitEntityCover(ctx, repos, entityType, id, before, cover.URL())
+	})
+	if err != nil {
+		uc.discardFiles(ctx, cover.ID)
+		return nil, err
+	}
+	return cover, nil
+}
+
+// RemoveCover убирает обложку трека, альбома или плейлиста. Файлы
+// обложки не удаляются: на них могут ссылаться кеши клиентов и журнал
+func (uc *coverUseCase) RemoveCover(ctx context.Context, entityType string, id uuid.UUID) error {
+	before, err := uc.authorizeCover(ctx, entityType, id)
+	if err != nil {
+		return err
+	}
+	if before == "" {
+		return nil
+	}
+
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		return uc.commitEntityCover(ctx, repos, entityType, id, before, "")
+	})
+}
+
+// SetArtistCover загружает обложку исполнителя
+func (uc *coverUseCase) SetArtistCover(ctx context.Context, artist string, file io.Reader) (*models.Cover, error) {
+	if err := authz.Require(ctx, authz.AlbumEdit); err != nil {
+		return nil, err
+	}
+	artist, err := normalizeArtist(artist)
+	if err != nil {
+		return nil, err
+	}
+	before := uc.artistCoverURL(ctx, artist)
+
+	cover, err := uc.storeCover(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Cover.Create(ctx, cover); err != nil {
+			return err
+		}
+		if err := repos.Cover.SetArtistCover(ctx, artist, cover.ID, time.Now()); err != nil {
+			return err
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditArtistCoverSet,
+			EntityType: models.AuditEntityArtist,
+			EntityID:   artist,
+			Before:     audit.Snapshot{"cover_url": before},
+			After:      audit.Snapshot{"cover_url": cover.URL()},
+		})
+	})
+	if err != nil {
+		uc.discardFiles(ctx, cover.ID)
+		return nil, err
+	}
+	return cover, nil
+}
+
+// RemoveArtistCover убирает обложку исполнителя
+func (uc *coverUseCase) RemoveArtistCover(ctx context.Context, artist string) error {
+	if err := authz.Require(ctx, authz.AlbumEdit); err != nil {
+		return err
+	}
+	artist, err := normalizeArtist(artist)
+	if err != nil {
+		return err
+	}
+
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		before := uc.artistCoverURL(ctx, artist)
+		if err := repos.Cover.DeleteArtistCover(ctx, artist); err != nil {
+			return lookupError(err, models.ErrCoverNotFound)
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditArtistCoverSet,
+			EntityType: models.AuditEntityArtist,
+			EntityID:   artist,
+			Before:     audit.Snapshot{"cover_url": before},
+			After:      audit.Snapshot{"cover_url": ""},
+		})
+	})
+}
+
+// GetArtistCoverURL возвращает адрес обложки исполнителя
+func (uc *coverUseCase) GetArtistCoverURL(ctx context.Context, artist string) (string, error) {
+	artist, err := normalizeArtist(artist)
+	if err != nil {
+		return "", err
+	}
+	coverID, err := uc.coverRepo.FindArtistCover(ctx, artist)
+	if err != nil {
+		return "", lookupError(err, models.ErrCoverNotFound)
+	}
+	return models.CoverURL(coverID), nil
+}
+
+// GetCoverFile возвращает файл обложки размера size ("" — наибольший,
+// models.CoverOriginal — оригинал) в формате, выбранном по заголовку Accept.
+// Копии, которых нет (например, после добавления размера в конфигурацию),
+// строятся из оригинала при первом запросе
+func (uc *coverUseCase) GetCoverFile(ctx context.Context, coverID uuid.UUID, size, accept string) (*models.CoverFile, error) {
+	cover, err := uc.coverRepo.FindByID(ctx, coverID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrCoverNotFound)
+	}
+
+	if size == models.CoverOriginal {
+		format := imaging.Format(cover.Format)
+		return &models.CoverFile{
+			Path:        uc.coverRepo.FilePath(originalName(cover.ID, format)),
+			ContentType: format.ContentType(),
+			ETag:        `"` + cover.ID.String() + `-original"`,
+			Immutable:   true,
+		}, nil
+	}
+
+	side, err := uc.parseSize(size)
+	if err != nil {
+		return nil, err
+	}
+	format := imaging.Negotiate(accept, uc.formats)
+	name := variantName(cover.ID, side, format)
+
+	filePath := uc.coverRepo.FilePath(name)
+	if _, err := os.Stat(filePath); errors.Is(err, fs.ErrNotExist) {
+		if err := uc.buildVariant(ctx, cover, side, format); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.CoverFile{
+		Path:        filePath,
+		ContentType: format.ContentType(),
+		ETag:        `"` + cover.ID.String() + "-" + path.Base(name) + `"`,
+		Immutable:   true,
+	}, nil
+}
+
+// GetPlaylistCover возвращает обложку плейлиста. Если у плейлиста задана
+// своя обложка, возвращается ее адрес (redirect). Иначе обложка собирается
+// мозаикой из обложек первых четырех альбомов плейлиста; если альбомов с
+// обложками меньше четырех, используется обложка первого из них
+func (uc *coverUseCase) GetPlaylistCover(ctx context.Context, playlistID uuid.UUID, size, accept string) (string, *models.CoverFile, error) {
+	playlist, err := uc.playlistRepo.FindByID(ctx, playlistID)
+	if err != nil {
+		return "", nil, lookupError(err, models.ErrPlaylistNotFound)
+	}
+
+	side, err := uc.parseSize(size)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if playlist.CoverURL != "" {
+		return withCoverSize(playlist.CoverURL, side), nil, nil
+	}
+
+	coverURLs, err := uc.coverRepo.PlaylistAlbumCovers(ctx, playlistID, mosaicTiles)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get playlist album covers: %w", err)
+	}
+	var coverIDs []uuid.UUID
+	for _, coverURL := range coverURLs {
+		if id, ok := models.CoverIDFromURL(coverURL); ok {
+			coverIDs = append(coverIDs, id)
+		}
+	}
+	switch {
+	case len(coverIDs) == 0:
+		return "", nil, models.ErrCoverNotFound
+	case len(coverIDs) < mosaicTiles:
+		return withCoverSize(models.CoverURL(coverIDs[0]), side), nil, nil
+	}
+
+	file, err := uc.mosaic(ctx, coverIDs, side, imaging.Negotiate(accept, uc.formats))
+	if err != nil {
+		return "", nil, err
+	}
+	return "", file, nil
+}
+
+// authorizeCover проверяет право менять обложку объекта и возвращает его
+// текущий cover_url
+func (uc *coverUseCase) authorizeCover(ctx context.Context, entityType string, id uuid.UUID) (string, error) {
+	switch entityType {
+	case models.CoverTrack:
+		if err := authz.Require(ctx, authz.TrackEdit); err != nil {
+			return "", err
+		}
+		track, err := uc.trackRepo.FindByID(ctx, id)
+		if err != nil {
+			return "", lookupError(err, models.ErrTrackNotFound)
+		}
+		return track.CoverURL, nil
+
+	case models.CoverAlbum:
+		if err := authz.Require(ctx, authz.AlbumEdit); err != nil {
+			return "", err
+		}
+		album, err := uc.albumRepo.FindByID(ctx, id)
+		if err != nil {
+			return "", lookupError(err, models.ErrAlbumNotFound)
+		}
+		return album.CoverURL, nil
+
+	case models.CoverPlaylist:
+		principal, err := authz.Current(ctx)
+		if err != nil {
+			return "", err
+		}
+		playlist, err := uc.playlistRepo.FindByID(ctx, id)
+		if err != nil {
+			return "", lookupError(err, models.ErrPlaylistNotFound)
+		}
+		if err := checkPlaylistAccess(ctx, playlist, principal.UserID); err != nil {
+			return "", err
+		}
+		return playlist.CoverURL, nil
+	}
+	return "", fmt.Errorf("unknown cover entity type %q", entityType)
+}
+
+// commitEntityCover меняет cover_url объекта и записывает изменение в журнал
+// (трек, альбом) или в outbox (плейлист)
+func (uc *coverUseCase) commitEntityCover(ctx context.Context, repos *interfaces.TxRepositories, entityType string, id uuid.UUID, before, after string) error {
+	err := repos.Cover.SetEntityCover(ctx, entityType, id, after, time.Now())
+	if errors.Is(err, models.ErrNotFound) {
+		return coverEntityNotFound(entityType)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch entityType {
+	case models.CoverTrack, models.CoverAlbum:
+		action, auditType := models.AuditTrackUpdate, models.AuditEntityTrack
+		if entityType == models.CoverAlbum {
+			action, auditType = models.AuditAlbumUpdate, models.AuditEntityAlbum
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     action,
+			EntityType: auditType,
+			EntityID:   id.String(),
+			Before:     audit.Snapshot{"cover_url": before},
+			After:      audit.Snapshot{"cover_url": after},
+		})
+
+	case models.CoverPlaylist:
+		playlist, err := repos.Playlist.FindByID(ctx, id)
+		if err != nil {
+			return lookupError(err, models.ErrPlaylistNotFound)
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventPlaylistChanged, id, models.PlaylistChangedPayload{
+			PlaylistID: id,
+			OwnerID:    playlist.UserID,
+			Change:     models.PlaylistUpdated,
+		})
+	}
+	return nil
+}
+
+func coverEntityNotFound(entityType string) error {
+	switch entityType {
+	case models.CoverTrack:
+		return models.ErrTrackNotFound
+	case models.CoverAlbum:
+		return models.ErrAlbumNotFound
+	default:
+		return models.ErrPlaylistNotFound
+	}
+}
+
+// storeCover декодирует изображение и сохраняет оригинал без метаданных и
+// все копии. При ошибке уже записанные файлы удаляются
+func (uc *coverUseCase) storeCover(ctx context.Context, file io.Reader) (*models.Cover, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, models.ErrCoverFileRequired
+	}
+
+	img, format, err := imaging.Decode(data, uc.maxPixels)
+	switch {
+	case errors.Is(err, imaging.ErrTooLarge):
+		return nil, models.ErrImageTooLarge
+	case err != nil:
+		return nil, models.ErrUnsupportedImage
+	}
+
+	// Оригинал перекодируется, чтобы в нем не осталось EXIF (в том числе
+	// координат съемки). GIF сохраняется как PNG: анимация обложке не нужна
+	if format != imaging.JPEG {
+		format = imaging.PNG
+	}
+
+	cover := &models.Cover{
+		ID:        uuid.New(),
+		Format:    string(format),
+		Width:     img.Bounds().Dx(),
+		Height:    img.Bounds().Dy(),
+		CreatedAt: time.Now(),
+	}
+	if principal, ok := authz.FromContext(ctx); ok {
+		cover.UploadedBy = principal.UserID
+	}
+
+	original, err := encodeImage(img, format)
+	if err != nil {
+		return nil, err
+	}
+	cover.SizeBytes = int64(len(original))
+
+	if err := uc.coverRepo.SaveFile(ctx, originalName(cover.ID, format), original); err != nil {
+		return nil, err
+	}
+	for _, side := range uc.sizes {
+		variant := imaging.Square(img, side)
+		for _, variantFormat := range uc.formats {
+			if err := uc.saveImage(ctx, variantName(cover.ID, side, variantFormat), variant, variantFormat); err != nil {
+				uc.discardFiles(ctx, cover.ID)
+				return nil, err
+			}
+		}
+	}
+	return cover, nil
+}
+
+// buildVariant строит из оригинала недостающую копию обложки
+func (uc *coverUseCase) buildVariant(ctx context.Context, cover *models.Cover, side int, format imaging.Format) error {
+	img, err := uc.loadImage(originalName(cover.ID, imaging.Format(cover.Format)))
+	if err != nil {
+		return err
+	}
+	return uc.saveImage(ctx, variantName(cover.ID, side, format), imaging.Square(img, side), format)
+}
+
+// mosaic возвращает мозаику из обложек coverIDs, строя ее при первом
+// запросе. Файл мозаики определяется набором обложек, поэтому после
+// изменения плейлиста у нее новые имя и ETag
+func (uc *coverUseCase) mosaic(ctx context.Context, coverIDs []uuid.UUID, side int, format imaging.Format) (*models.CoverFile, error) {
+	hash := sha256.New()
+	for _, id := range coverIDs {
+		hash.Write(id[:])
+	}
+	key := hex.EncodeToString(hash.Sum(nil))[:32]
+	name := path.Join("mosaics", key[:2], key, strconv.Itoa(side)+"."+format.Extension())
+
+	filePath := uc.coverRepo.FilePath(name)
+	if _, err := os.Stat(filePath); errors.Is(err, fs.ErrNotExist) {
+		var tiles [mosaicTiles]*image.RGBA
+		for i, id := range coverIDs[:mosaicTiles] {
+			tile, err := uc.mosaicTile(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			tiles[i] = tile
+		}
+		if err := uc.saveImage(ctx, name, imaging.Mosaic(tiles, side), format); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.CoverFile{
+		Path:        filePath,
+		ContentType: format.ContentType(),
+		ETag:        `"mosaic-` + key + "-" + path.Base(name) + `"`,
+	}, nil
+}
+
+// mosaicTile загружает плитку мозаики — копию обложки в JPEG
+func (uc *coverUseCase) mosaicTile(ctx context.Context, coverID uuid.UUID) (*image.RGBA, error) {
+	name := variantName(coverID, mosaicSource, imaging.JPEG)
+	img, err := uc.loadImage(name)
+	if err == nil {
+		return img, nil
+	}
+
+	cover, findErr := uc.coverRepo.FindByID(ctx, coverID)
+	if findErr != nil {
+		return nil, lookupError(findErr, models.ErrCoverNotFound)
+	}
+	return uc.loadImage(originalName(cover.ID, imaging.Format(cover.Format)))
+}
+
+func (uc *coverUseCase) loadImage(name string) (*image.RGBA, error) {
+	data, err := os.ReadFile(uc.coverRepo.FilePath(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, models.ErrCoverNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := imaging.Decode(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cover %s: %w", name, err)
+	}
+	return img, nil
+}
+
+func (uc *coverUseCase) saveImage(ctx context.Context, name string, img image.Image, format imaging.Format) error {
+	data, err := encodeImage(img, format)
+	if err != nil {
+		return err
+	}
+	return uc.coverRepo.SaveFile(ctx, name, data)
+}
+
+// discardFiles удаляет файлы обложки, запись о которой не сохранилась
+func (uc *coverUseCase) discardFiles(ctx context.Context, coverID uuid.UUID) {
+	if err := uc.coverRepo.DeleteFiles(ctx, coverDir(coverID)); err != nil {
+		logging.FromContext(ctx).Warn("delete cover files failed", "cover_id", coverID, "error", err)
+	}
+}
+
+func (uc *coverUseCase) artistCoverURL(ctx context.Context, artist string) string {
+	coverID, err := uc.coverRepo.FindArtistCover(ctx, artist)
+	if err != nil {
+		return ""
+	}
+	return models.CoverURL(coverID)
+}
+
+// parseSize проверяет запрошенный размер копии; пустой — наибольший
+func (uc *coverUseCase) parseSize(size string) (int, error) {
+	if size == "" {
+		return uc.sizes[len(uc.sizes)-1], nil
+	}
+	side, err := strconv.Atoi(size)
+	if err != nil || !slices.Contains(uc.sizes, side) {
+		return 0, models.ErrInvalidCoverSize
+	}
+	return side, nil
+}
+
+func encodeImage(img image.Image, format imaging.Format) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, format); err != nil {
+		return nil, fmt.Errorf("failed to encode cover: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// withCoverSize добавляет размер к адресу загруженной обложки. Внешние
+// адреса возвращаются как есть
+func withCoverSize(coverURL string, side int) string {
+	if _, ok := models.CoverIDFromURL(coverURL); !ok {
+		return coverURL
+	}
+	return coverURL + "?size=" + strconv.Itoa(side)
+}
+
+// coverDir — каталог файлов обложки: covers/ab/cd/<id>
+func coverDir(id uuid.UUID) string {
+	s := id.String()
+	return path.Join(s[:2], s[2:4], s)
+}
+
+func originalName(id uuid.UUID, format imaging.Format) string {
+	return path.Join(coverDir(id), models.CoverOriginal+"."+format.Extension())
+}
+
+func variantName(id uuid.UUID, side int, format imaging.Format) string {
+	return path.Join(coverDir(id), strconv.Itoa(side)+"."+format.Extension())
+}
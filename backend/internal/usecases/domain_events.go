@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"music-service/internal/models"
+	"music-service/internal/outbox"
+	"music-service/internal/repository/interfaces"
+
+	"github.com/google/uuid"
+)
+
+// recordEvent добавляет доменное событие в outbox той же транзакции,
+// в которой меняется состояние
+func recordEvent(ctx context.Context, outboxRepo interfaces.OutboxRepository, eventType string, aggregateID uuid.UUID, payload interface{}) error {
+	event, err := models.NewDomainEvent(eventType, aggregateID, payload)
+	if err != nil {
+		return fmt.Errorf("failed to build %s event: %w", eventType, err)
+	}
+	if err := outboxRepo.Add(ctx, event); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// playCountHandler — имя обработчика, увеличивающего счетчик прослушиваний,
+// в отметках об обработке событий
+const playCountHandler = "track.play_count"
+
+// RegisterDomainEventHandlers подписывает побочные эффекты use case'ов на доменные события
+func RegisterDomainEventHandlers(dispatcher *outbox.Dispatcher, trackRepo interfaces.TrackRepository, uow interfaces.UnitOfWork) {
+	// Увеличение счетчика не идемпотентно, поэтому событие отмечается
+	// обработанным в той же транзакции: при повторной доставке прослушивание
+	// не засчитывается дважды
+	dispatcher.Subscribe(models.EventPlaybackRecorded, func(ctx context.Context, event *models.DomainEvent) error {
+		var payload models.PlaybackRecordedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.Type, err)
+		}
+		return uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+			first, err := repos.Outbox.MarkHandled(ctx, event.ID, playCountHandler)
+			if err != nil || !first {
+				return err
+			}
+			return repos.Track.IncrementPlayCount(ctx, payload.TrackID)
+		})
+	})
+
+	dispatcher.Subscribe(models.EventTrackDeleted, func(ctx context.Context, event *models.DomainEvent) error {
+		var payload models.TrackDeletedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.Type, err)
+		}
+		return trackRepo.DeleteTrackFile(ctx, payload.FilePath)
+	})
+}
@@ -0,0 +1,217 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-service/internal/authz"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxMergeSources — наибольшее число треков, объединяемых за один раз
+const maxMergeSources = 20
+
+type duplicateUseCase struct {
+	fingerprintRepo interfaces.FingerprintRepository
+	trackRepo       interfaces.TrackRepository
+	uow             interfaces.UnitOfWork
+}
+
+// NewDuplicateUseCase создает use case отчета о дубликатах: треки с
+// похожими акустическими отпечатками группируются, и администратор
+// объединяет группу в один трек
+func NewDuplicateUseCase(
+	fingerprintRepo interfaces.FingerprintRepository,
+	trackRepo interfaces.TrackRepository,
+	uow interfaces.UnitOfWork,
+) usecaseInterfaces.DuplicateUseCase {
+	return &duplicateUseCase{
+		fingerprintRepo: fingerprintRepo,
+		trackRepo:       trackRepo,
+		uow:             uow,
+	}
+}
+
+// ListDuplicates возвращает страницу групп подозреваемых дубликатов. Группа
+// — треки, связанные парами похожих отпечатков, в том числе через другие
+// треки; в группе сначала идут треки, загруженные раньше
+func (uc *duplicateUseCase) ListDuplicates(ctx context.Context, limit, offset int) (*models.DuplicateClusterPage, error) {
+	if err := authz.Require(ctx, authz.DuplicateManage); err != nil {
+		return nil, err
+	}
+	limit, offset = normalizePage(limit, offset)
+
+	pairs, err := uc.fingerprintRepo.ListDuplicates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list duplicates: %w", err)
+	}
+	clusters := duplicateClusters(pairs)
+
+	page := &models.DuplicateClusterPage{
+		Items:  []*models.DuplicateCluster{},
+		Total:  len(clusters),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if offset >= len(clusters) {
+		return page, nil
+	}
+	for _, cluster := range clusters[offset:min(offset+limit, len(clusters))] {
+		if err := uc.loadTracks(ctx, cluster); err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, cluster)
+	}
+	return page, nil
+}
+
+// duplicateClusters объединяет пары в связные группы, самые похожие — первыми
+func duplicateClusters(pairs []*models.DuplicatePair) []*models.DuplicateCluster {
+	parent := make(map[uuid.UUID]uuid.UUID)
+	var find func(id uuid.UUID) uuid.UUID
+	find = func(id uuid.UUID) uuid.UUID {
+		p, ok := parent[id]
+		if !ok || p == id {
+			parent[id] = id
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+	for _, pair := range pairs {
+		parent[find(pair.TrackID)] = find(pair.DuplicateID)
+	}
+
+	byRoot := make(map[uuid.UUID]*models.DuplicateCluster)
+	var clusters []*models.DuplicateCluster
+	for _, pair := range pairs {
+		root := find(pair.TrackID)
+		cluster, ok := byRoot[root]
+		if !ok {
+			cluster = &models.DuplicateCluster{}
+			byRoot[root] = cluster
+			clusters = append(clusters, cluster)
+		}
+		cluster.Pairs = append(cluster.Pairs, pair)
+		cluster.Similarity = max(cluster.Similarity, pair.Similarity)
+	}
+
+	// Пары приходят упорядоченными по сходству, поэтому порядок групп с
+	// равным сходством стабилен
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Similarity > clusters[j].Similarity
+	})
+	return clusters
+}
+
+// loadTracks заполняет треки группы по ее парам
+func (uc *duplicateUseCase) loadTracks(ctx context.Context, cluster *models.DuplicateCluster) error {
+	seen := make(map[uuid.UUID]bool)
+	for _, pair := range cluster.Pairs {
+		for _, id := range []uuid.UUID{pair.TrackID, pair.DuplicateID} {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			track, err := uc.trackRepo.FindByID(ctx, id)
+			if errors.Is(err, models.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get duplicate track: %w", err)
+			}
+			cluster.Tracks = append(cluster.Tracks, track)
+		}
+	}
+	sort.SliceStable(cluster.Tracks, func(i, j int) bool {
+		return cluster.Tracks[i].AddedDate.Before(cluster.Tracks[j].AddedDate)
+	})
+	return nil
+}
+
+// MergeTracks объединяет дубликаты sourceIDs с треком targetID: вхождения
+// в плейлисты, история прослушиваний, очереди воспроизведения и счетчик
+// прослушиваний переходят к целевому треку, а дубликаты попадают в корзину
+// вместе с их отпечатками и парами
+func (uc *duplicateUseCase) MergeTracks(ctx context.Context, targetID uuid.UUID, sourceIDs []uuid.UUID) error {
+	if err := authz.Require(ctx, authz.DuplicateManage); err != nil {
+		return err
+	}
+
+	sourceIDs = uniqueIDs(sourceIDs)
+	switch {
+	case len(sourceIDs) == 0:
+		return models.ErrMergeSourcesRequired
+	case len(sourceIDs) > maxMergeSources:
+		return models.ErrMergeSourcesLimit
+	}
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return models.ErrMergeTargetInSources
+		}
+	}
+
+	if _, err := uc.trackRepo.FindByID(ctx, targetID); err != nil {
+		return lookupError(err, models.ErrTrackNotFound)
+	}
+	sources := make([]*models.Track, 0, len(sourceIDs))
+	for _, id := range sourceIDs {
+		source, err := uc.trackRepo.FindByID(ctx, id)
+		if err != nil {
+			return lookupError(err, models.ErrTrackNotFound)
+		}
+		sources = append(sources, source)
+	}
+
+	now := time.Now()
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		for _, source := range sources {
+			if err := repos.Track.Merge(ctx, targetID, source.ID); err != nil {
+				return fmt.Errorf("failed to merge track: %w", err)
+			}
+			if err := repos.Track.Delete(ctx, source.ID, now); err != nil {
+				return lookupError(err, models.ErrTrackNotFound)
+			}
+			if err := repos.Fingerprint.Delete(ctx, source.ID); err != nil {
+				return err
+			}
+			if source.Loudness != nil {
+				if err := refreshAlbumLoudness(ctx, repos.Album, source.AlbumID); err != nil {
+					return err
+				}
+			}
+		}
+
+		merged := make([]string, len(sources))
+		for i, source := range sources {
+			merged[i] = source.ID.String()
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditTrackMerge,
+			EntityType: models.AuditEntityTrack,
+			EntityID:   targetID.String(),
+			Details:    map[string]interface{}{"merged_tracks": merged},
+		})
+	})
+}
+
+// uniqueIDs убирает повторы, сохраняя порядок
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,15 @@
+package usecases
+
+import (
+	"errors"
+	"music-service/internal/models"
+)
+
+// lookupError заменяет отсутствие записи доменной ошибкой notFound.
+// Остальные ошибки хранилища возвращаются как есть
+func lookupError(err error, notFound *models.DomainError) error {
+	if errors.Is(err, models.ErrNotFound) {
+		return notFound
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type followUseCase struct {
+	followRepo   interfaces.FollowRepository
+	playlistRepo interfaces.PlaylistRepository
+}
+
+func NewFollowUseCase(
+	followRepo interfaces.FollowRepository,
+	playlistRepo interfaces.PlaylistRepository,
+) usecaseInterfaces.FollowUseCase {
+	return &followUseCase{
+		followRepo:   followRepo,
+		playlistRepo: playlistRepo,
+	}
+}
+
+func (uc *followUseCase) FollowPlaylist(ctx context.Context, userID, playlistID uuid.UUID) error {
+	playlist, err := uc.playlistRepo.FindByID(ctx, playlistID)
+	if err != nil {
+		return lookupError(err, models.ErrPlaylistNotFound)
+	}
+
+	if playlist.UserID == userID {
+		return models.ErrCannotFollowOwnPlaylist
+	}
+
+	if err := uc.followRepo.FollowPlaylist(ctx, userID, playlistID); err != nil {
+		return fmt.Errorf("failed to follow playlist: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *followUseCase) UnfollowPlaylist(ctx context.Context, userID, playlistID uuid.UUID) error {
+	if err := uc.followRepo.UnfollowPlaylist(ctx, userID, playlistID); err != nil {
+		return fmt.Errorf("failed to unfollow playlist: %w", err)
+	}
+	return nil
+}
+
+func (uc *followUseCase) GetFollowedPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error) {
+	playlists, err := uc.followRepo.GetFollowedPlaylists(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get followed playlists: %w", err)
+	}
+	return playlists, nil
+}
+
+func (uc *followUseCase) FollowArtist(ctx context.Context, userID uuid.UUID, artist string) error {
+	artist, err := normalizeArtist(artist)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.followRepo.FollowArtist(ctx, userID, artist); err != nil {
+		return fmt.Errorf("failed to follow artist: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *followUseCase) UnfollowArtist(ctx context.Context, userID uuid.UUID, artist string) error {
+	artist, err := normalizeArtist(artist)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.followRepo.UnfollowArtist(ctx, userID, artist); err != nil {
+		return fmt.Errorf("failed to unfollow artist: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *followUseCase) GetFollowedArtists(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	artists, err := uc.followRepo.GetFollowedArtists(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get followed artists: %w", err)
+	}
+	return artists, nil
+}
+
+func normalizeArtist(artist string) (string, error) {
+	artist = strings.TrimSpace(artist)
+	if artist == "" {
+		return "", models.ErrArtistNameRequired
+	}
+	if utf8.RuneCountInString(artist) > 100 {
+		return "", models.ErrArtistNameTooLong
+	}
+	return artist, nil
+}
@@ -1,8 +1,10 @@
 package usecases
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"music-service/internal/audit"
+	"music-service/internal/authz"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
 	usecaseInterfaces "music-service/internal/usecases/interfaces"
@@ -15,62 +17,85 @@ import (
 type genreUseCase struct {
 	genreRepo interfaces.GenreRepository
 	trackRepo interfaces.TrackRepository
+	uow       interfaces.UnitOfWork
+	localizer catalogLocalizer
 }
 
 func NewGenreUseCase(
 	genreRepo interfaces.GenreRepository,
 	trackRepo interfaces.TrackRepository,
+	translationRepo interfaces.TranslationRepository,
+	uow interfaces.UnitOfWork,
 ) usecaseInterfaces.GenreUseCase {
 	return &genreUseCase{
 		genreRepo: genreRepo,
 		trackRepo: trackRepo,
+		uow:       uow,
+		localizer: newCatalogLocalizer(translationRepo),
 	}
 }
 
-func (uc *genreUseCase) CreateGenre(name string) (*models.Genre, error) {
+func (uc *genreUseCase) CreateGenre(ctx context.Context, name string) (*models.Genre, error) {
+	if err := authz.Require(ctx, authz.GenreManage); err != nil {
+		return nil, err
+	}
+
 	name = strings.TrimSpace(name)
 	if utf8.RuneCountInString(name) < 2 {
-		return nil, errors.New("genre name must be at least 2 characters")
+		return nil, models.ErrGenreNameTooShort
 	}
 	if utf8.RuneCountInString(name) > 50 {
-		return nil, errors.New("genre name is too long (max 50 characters)")
+		return nil, models.ErrGenreNameTooLong
 	}
-	existingGenres, err := uc.genreRepo.ListAll()
+	existingGenres, err := uc.genreRepo.ListAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing genres: %w", err)
 	}
 	for _, g := range existingGenres {
 		if strings.EqualFold(g.Name, name) {
-			return nil, errors.New("genre already exists")
+			return nil, models.ErrGenreExists
 		}
 	}
 	genre := &models.Genre{
 		ID:   uuid.New(),
 		Name: name,
 	}
-	if err := uc.genreRepo.Save(genre); err != nil {
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Genre.Save(ctx, genre); err != nil {
+			return err
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditGenreCreate,
+			EntityType: models.AuditEntityGenre,
+			EntityID:   genre.ID.String(),
+			After:      audit.Snapshot{"name": genre.Name},
+		})
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to save genre: %w", err)
 	}
 	return genre, nil
 }
 
-func (uc *genreUseCase) GetGenresByTrack(trackID uuid.UUID) ([]*models.Genre, error) {
-	if _, err := uc.trackRepo.FindByID(trackID); err != nil {
-		return nil, fmt.Errorf("track not found: %w", err)
+func (uc *genreUseCase) GetGenresByTrack(ctx context.Context, trackID uuid.UUID) ([]*models.Genre, error) {
+	if _, err := uc.trackRepo.FindByID(ctx, trackID); err != nil {
+		return nil, lookupError(err, models.ErrTrackNotFound)
 	}
-	genres, err := uc.genreRepo.GetGenresForTrack(trackID)
+	genres, err := uc.genreRepo.GetGenresForTrack(ctx, trackID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get track genres: %w", err)
 	}
+	uc.localizer.genres(ctx, genres)
 	return genres, nil
 }
 
-func (uc *genreUseCase) ListAllGenres() ([]*models.Genre, error) {
-	genres, err := uc.genreRepo.ListAll()
+func (uc *genreUseCase) ListAllGenres(ctx context.Context) ([]*models.Genre, error) {
+	genres, err := uc.genreRepo.ListAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list genres: %w", err)
 	}
-	// Сортируем жанры по алфавиту
+	uc.localizer.genres(ctx, genres)
+	// Сортируем жанры по алфавиту на языке запроса
 	for i := 0; i < len(genres)-1; i++ {
 		for j := i + 1; j < len(genres); j++ {
 			if strings.Compare(genres[i].Name, genres[j].Name) > 0 {
@@ -82,52 +107,89 @@ func (uc *genreUseCase) ListAllGenres() ([]*models.Genre, error) {
 	return genres, nil
 }
 
-func (uc *genreUseCase) AssignGenreToTrack(trackID, genreID uuid.UUID) error {
-	if _, err := uc.trackRepo.FindByID(trackID); err != nil {
-		return fmt.Errorf("track not found: %w", err)
+func (uc *genreUseCase) AssignGenreToTrack(ctx context.Context, trackID, genreID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.GenreManage); err != nil {
+		return err
 	}
-	if _, err := uc.genreRepo.FindByID(genreID); err != nil {
-		return fmt.Errorf("genre not found: %w", err)
+
+	if _, err := uc.trackRepo.FindByID(ctx, trackID); err != nil {
+		return lookupError(err, models.ErrTrackNotFound)
+	}
+	genre, err := uc.genreRepo.FindByID(ctx, genreID)
+	if err != nil {
+		return lookupError(err, models.ErrGenreNotFound)
 	}
-	currentGenres, err := uc.genreRepo.GetGenresForTrack(trackID)
+	currentGenres, err := uc.genreRepo.GetGenresForTrack(ctx, trackID)
 	if err != nil {
 		return fmt.Errorf("failed to get track genres: %w", err)
 	}
 	for _, g := range currentGenres {
 		if g.ID == genreID {
-			return errors.New("genre already assigned to track")
+			return models.ErrGenreAlreadyAssigned
 		}
 	}
 	if len(currentGenres) >= 5 {
-		return errors.New("track cannot have more than 5 genres")
+		return models.ErrTrackGenreLimit
 	}
 
-	if err := uc.genreRepo.AddGenreToTrack(trackID, genreID); err != nil {
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Genre.AddGenreToTrack(ctx, trackID, genreID); err != nil {
+			return err
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditTrackGenreAdd,
+			EntityType: models.AuditEntityTrack,
+			EntityID:   trackID.String(),
+			Before:     audit.Snapshot{"genres": genreNames(currentGenres)},
+			After:      audit.Snapshot{"genres": genreNames(append(currentGenres, genre))},
+			Details:    map[string]interface{}{"genre_id": genreID},
+		})
+	})
+	if err != nil {
 		return fmt.Errorf("failed to assign genre to track: %w", err)
 	}
 
 	return nil
 }
 
-func (uc *genreUseCase) RemoveGenreFromTrack(trackID, genreID uuid.UUID) error {
-	genres, err := uc.genreRepo.GetGenresForTrack(trackID)
+func (uc *genreUseCase) RemoveGenreFromTrack(ctx context.Context, trackID, genreID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.GenreManage); err != nil {
+		return err
+	}
+
+	genres, err := uc.genreRepo.GetGenresForTrack(ctx, trackID)
 	if err != nil {
 		return fmt.Errorf("failed to get track genres: %w", err)
 	}
 
 	found := false
+	remaining := make([]*models.Genre, 0, len(genres))
 	for _, g := range genres {
 		if g.ID == genreID {
 			found = true
-			break
+			continue
 		}
+		remaining = append(remaining, g)
 	}
 
 	if !found {
-		return errors.New("genre is not assigned to this track")
+		return models.ErrGenreNotAssigned
 	}
 
-	if err := uc.genreRepo.RemoveGenreFromTrack(trackID, genreID); err != nil {
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Genre.RemoveGenreFromTrack(ctx, trackID, genreID); err != nil {
+			return err
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditTrackGenreRemove,
+			EntityType: models.AuditEntityTrack,
+			EntityID:   trackID.String(),
+			Before:     audit.Snapshot{"genres": genreNames(genres)},
+			After:      audit.Snapshot{"genres": genreNames(remaining)},
+			Details:    map[string]interface{}{"genre_id": genreID},
+		})
+	})
+	if err != nil {
 		return fmt.Errorf("failed to remove genre from track: %w", err)
 	}
 
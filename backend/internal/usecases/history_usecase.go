@@ -1,7 +1,7 @@
 package usecases
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
@@ -15,28 +15,31 @@ import (
 type historyUseCase struct {
 	historyRepo interfaces.HistoryRepository
 	trackRepo   interfaces.TrackRepository
+	uow         interfaces.UnitOfWork
 }
 
 func NewHistoryUseCase(
 	historyRepo interfaces.HistoryRepository,
 	trackRepo interfaces.TrackRepository,
+	uow interfaces.UnitOfWork,
 ) usecaseInterfaces.HistoryUseCase {
 	return &historyUseCase{
 		historyRepo: historyRepo,
 		trackRepo:   trackRepo,
+		uow:         uow,
 	}
 }
 
-func (uc *historyUseCase) RecordPlayback(userID uuid.UUID, trackID uuid.UUID) error {
-	track, err := uc.trackRepo.FindByID(trackID)
+func (uc *historyUseCase) RecordPlayback(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) error {
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
 	if err != nil {
-		return fmt.Errorf("track not found: %w", err)
+		return lookupError(err, models.ErrTrackNotFound)
 	}
 	if track.Duration < 30 {
-		return errors.New("track is too short to record playback")
+		return models.ErrTrackTooShort
 	}
 
-	history, err := uc.historyRepo.GetHistory(userID)
+	history, err := uc.historyRepo.GetHistory(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("failed to get user history: %w", err)
 	}
@@ -46,24 +49,35 @@ func (uc *historyUseCase) RecordPlayback(userID uuid.UUID, trackID uuid.UUID) er
 		if entry.TrackID == trackID && time.Since(entry.ListenedAt) < 5*time.Minute {
 			recentPlays++
 			if recentPlays >= 3 {
-				return errors.New("track played too frequently")
+				return models.ErrPlayedTooFrequently
 			}
 		}
 	}
 
-	if err := uc.historyRepo.AddEntry(userID, trackID); err != nil {
-		return fmt.Errorf("failed to record playback: %w", err)
-	}
-
-	return nil
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.History.AddEntry(ctx, userID, trackID); err != nil {
+			return fmt.Errorf("failed to record playback: %w", err)
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventPlaybackRecorded, trackID, models.PlaybackRecordedPayload{
+			UserID:     userID,
+			TrackID:    trackID,
+			ListenedAt: time.Now(),
+		})
+	})
 }
 
-func (uc *historyUseCase) GetUserHistory(userID uuid.UUID) ([]*models.ListeningHistory, error) {
-	history, err := uc.historyRepo.GetHistory(userID)
+func (uc *historyUseCase) GetUserHistory(ctx context.Context, userID uuid.UUID) ([]*models.ListeningHistory, error) {
+	history, err := uc.historyRepo.GetHistory(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get listening history: %w", err)
 	}
 
+	return latestHistory(history), nil
+}
+
+// latestHistory сортирует историю от новых прослушиваний к старым и
+// оставляет не больше maxHistoryItems записей
+func latestHistory(history []*models.ListeningHistory) []*models.ListeningHistory {
 	sort.Slice(history, func(i, j int) bool {
 		return history[i].ListenedAt.After(history[j].ListenedAt)
 	})
@@ -72,12 +86,11 @@ func (uc *historyUseCase) GetUserHistory(userID uuid.UUID) ([]*models.ListeningH
 	if len(history) > maxHistoryItems {
 		history = history[:maxHistoryItems]
 	}
-
-	return history, nil
+	return history
 }
 
-func (uc *historyUseCase) GetRecentPlays(userID uuid.UUID, within time.Duration) ([]*models.ListeningHistory, error) {
-	history, err := uc.historyRepo.GetHistory(userID)
+func (uc *historyUseCase) GetRecentPlays(ctx context.Context, userID uuid.UUID, within time.Duration) ([]*models.ListeningHistory, error) {
+	history, err := uc.historyRepo.GetHistory(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
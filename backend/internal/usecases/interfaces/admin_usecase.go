@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AdminUseCase interface {
+	ListUsers(ctx context.Context, filter models.UserFilter) (*models.UserPage, error)
+	GetUser(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	SuspendUser(ctx context.Context, userID uuid.UUID, reason string, until *time.Time) (*models.User, error)
+	UnsuspendUser(ctx context.Context, userID uuid.UUID) error
+	ForceLogout(ctx context.Context, userID uuid.UUID) (int, error)
+	GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error)
+	GetUserHistory(ctx context.Context, userID uuid.UUID) ([]*models.ListeningHistory, error)
+	ListAuditLog(ctx context.Context, filter models.AuditFilter) (*models.AuditPage, error)
+	PurgeAuditLog(ctx context.Context, before time.Time) (int64, error)
+}
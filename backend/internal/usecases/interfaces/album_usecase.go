@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
 	"time"
 
@@ -8,11 +9,11 @@ import (
 )
 
 type AlbumUseCase interface {
-	CreateAlbum(title string, artist string, releaseDate time.Time, coverURL string) (*models.Album, error)
-	AddTrackToAlbum(albumID uuid.UUID, trackID uuid.UUID) error
-	RemoveTrackFromAlbum(albumID uuid.UUID, trackID uuid.UUID) error
-	GetAlbumDetails(albumID uuid.UUID) (*models.Album, []*models.Track, error)
-	UpdateAlbumInfo(albumID uuid.UUID, title, artist, coverURL string, releaseDate time.Time) error
-	ListAll() ([]*models.Album, error)
-	Delete(albumID uuid.UUID) error
+	CreateAlbum(ctx context.Context, title string, artist string, releaseDate time.Time, coverURL string) (*models.Album, error)
+	AddTrackToAlbum(ctx context.Context, albumID uuid.UUID, trackID uuid.UUID) error
+	RemoveTrackFromAlbum(ctx context.Context, albumID uuid.UUID, trackID uuid.UUID) error
+	GetAlbumDetails(ctx context.Context, albumID uuid.UUID) (*models.Album, []*models.Track, error)
+	UpdateAlbumInfo(ctx context.Context, albumID uuid.UUID, title, artist, coverURL string, releaseDate time.Time) error
+	ListAll(ctx context.Context) ([]*models.Album, error)
+	Delete(ctx context.Context, albumID uuid.UUID) error
 }
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type CoverUseCase interface {
+	SetCover(ctx context.Context, entityType string, id uuid.UUID, file io.Reader) (*models.Cover, error)
+	RemoveCover(ctx context.Context, entityType string, id uuid.UUID) error
+	SetArtistCover(ctx context.Context, artist string, file io.Reader) (*models.Cover, error)
+	RemoveArtistCover(ctx context.Context, artist string) error
+	GetArtistCoverURL(ctx context.Context, artist string) (string, error)
+	GetCoverFile(ctx context.Context, coverID uuid.UUID, size, accept string) (*models.CoverFile, error)
+	GetPlaylistCover(ctx context.Context, playlistID uuid.UUID, size, accept string) (string, *models.CoverFile, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type DuplicateUseCase interface {
+	ListDuplicates(ctx context.Context, limit, offset int) (*models.DuplicateClusterPage, error)
+	MergeTracks(ctx context.Context, targetID uuid.UUID, sourceIDs []uuid.UUID) error
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type FollowUseCase interface {
+	FollowPlaylist(ctx context.Context, userID, playlistID uuid.UUID) error
+	UnfollowPlaylist(ctx context.Context, userID, playlistID uuid.UUID) error
+	GetFollowedPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error)
+	FollowArtist(ctx context.Context, userID uuid.UUID, artist string) error
+	UnfollowArtist(ctx context.Context, userID uuid.UUID, artist string) error
+	GetFollowedArtists(ctx context.Context, userID uuid.UUID) ([]string, error)
+}
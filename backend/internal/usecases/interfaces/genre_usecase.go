@@ -1,15 +1,16 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
 
 	"github.com/google/uuid"
 )
 
 type GenreUseCase interface {
-	CreateGenre(name string) (*models.Genre, error)
-	GetGenresByTrack(trackID uuid.UUID) ([]*models.Genre, error)
-	ListAllGenres() ([]*models.Genre, error)
-	RemoveGenreFromTrack(trackID uuid.UUID, genreID uuid.UUID) error
-	AssignGenreToTrack(trackID uuid.UUID, genreID uuid.UUID) error
+	CreateGenre(ctx context.Context, name string) (*models.Genre, error)
+	GetGenresByTrack(ctx context.Context, trackID uuid.UUID) ([]*models.Genre, error)
+	ListAllGenres(ctx context.Context) ([]*models.Genre, error)
+	RemoveGenreFromTrack(ctx context.Context, trackID uuid.UUID, genreID uuid.UUID) error
+	AssignGenreToTrack(ctx context.Context, trackID uuid.UUID, genreID uuid.UUID) error
 }
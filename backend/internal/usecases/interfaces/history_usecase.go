@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
 	"time"
 
@@ -8,7 +9,7 @@ import (
 )
 
 type HistoryUseCase interface {
-	RecordPlayback(userID uuid.UUID, trackID uuid.UUID) error
-	GetUserHistory(userID uuid.UUID) ([]*models.ListeningHistory, error)
-	GetRecentPlays(userID uuid.UUID, within time.Duration) ([]*models.ListeningHistory, error)
+	RecordPlayback(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) error
+	GetUserHistory(ctx context.Context, userID uuid.UUID) ([]*models.ListeningHistory, error)
+	GetRecentPlays(ctx context.Context, userID uuid.UUID, within time.Duration) ([]*models.ListeningHistory, error)
 }
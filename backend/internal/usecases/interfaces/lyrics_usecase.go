@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type LyricsUseCase interface {
+	GetLyrics(ctx context.Context, trackID uuid.UUID) (*models.Lyrics, error)
+	SetLyrics(ctx context.Context, trackID uuid.UUID, text, language string) (*models.Lyrics, error)
+	DeleteLyrics(ctx context.Context, trackID uuid.UUID) error
+	ModerateLyrics(ctx context.Context, trackID uuid.UUID, status string) (*models.Lyrics, error)
+	ListLyrics(ctx context.Context, filter models.LyricsFilter) (*models.LyricsPage, error)
+	SearchLyrics(ctx context.Context, query string, limit, offset int) ([]*models.LyricsMatch, error)
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type MFAUseCase interface {
+	Status(ctx context.Context, userID uuid.UUID) (*models.MFAStatus, error)
+	BeginEnrollment(ctx context.Context, userID uuid.UUID) (*models.TOTPEnrollment, error)
+	ConfirmEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	Disable(ctx context.Context, userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	BeginChallengeEnrollment(ctx context.Context, mfaToken string) (*models.TOTPEnrollment, error)
+	CompleteChallenge(ctx context.Context, mfaToken, code string, client models.ClientInfo) (*models.MFALogin, error)
+	ResetForUser(ctx context.Context, userID uuid.UUID) error
+	RequiredRoles(ctx context.Context) ([]models.Permission, error)
+	SetRequiredRoles(ctx context.Context, roles []models.Permission) ([]models.Permission, error)
+}
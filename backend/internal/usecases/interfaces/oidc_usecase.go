@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type OIDCUseCase interface {
+	Providers() []string
+	BeginLogin(ctx context.Context, provider string, linkUserID uuid.UUID) (string, error)
+	CompleteLogin(ctx context.Context, provider, state, code string, device models.Device, client models.ClientInfo) (*models.User, *models.Session, *models.TokenPair, error)
+	ListIdentities(ctx context.Context, userID uuid.UUID) ([]*models.Identity, error)
+	UnlinkIdentity(ctx context.Context, userID uuid.UUID, provider string) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type PasswordUseCase interface {
+	ChangePassword(ctx context.Context, userID, currentSessionID uuid.UUID, currentPassword, newPassword string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
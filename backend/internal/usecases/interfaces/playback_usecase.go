@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PlaybackUseCase interface {
+	GetState(ctx context.Context, userID uuid.UUID) (*models.PlaybackState, error)
+	SetQueue(ctx context.Context, userID, deviceID uuid.UUID, trackIDs []uuid.UUID, startIndex int) (*models.PlaybackState, error)
+	AddToQueue(ctx context.Context, userID, deviceID uuid.UUID, trackID uuid.UUID, playNext bool) (*models.PlaybackState, error)
+	RemoveFromQueue(ctx context.Context, userID, deviceID uuid.UUID, position int) (*models.PlaybackState, error)
+	Play(ctx context.Context, userID, deviceID uuid.UUID, index *int) (*models.PlaybackState, error)
+	Pause(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error)
+	Seek(ctx context.Context, userID, deviceID uuid.UUID, positionMs int) (*models.PlaybackState, error)
+	Next(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error)
+	Previous(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error)
+	SetShuffle(ctx context.Context, userID, deviceID uuid.UUID, enabled bool) (*models.PlaybackState, error)
+	SetRepeatMode(ctx context.Context, userID, deviceID uuid.UUID, mode models.RepeatMode) (*models.PlaybackState, error)
+	TransferPlayback(ctx context.Context, userID, targetDeviceID uuid.UUID, play bool) (*models.PlaybackState, error)
+	ListDevices(ctx context.Context, userID uuid.UUID) ([]*models.Device, error)
+}
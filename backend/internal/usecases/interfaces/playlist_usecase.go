@@ -1,18 +1,19 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
 
 	"github.com/google/uuid"
 )
 
 type PlaylistUseCase interface {
-	CreatePlaylist(userID uuid.UUID, name, description string, coverURL string) (*models.Playlist, error)
-	AddTrackToPlaylist(playlistID, trackID uuid.UUID) error
-	RemoveTrackFromPlaylist(playlistID, trackID uuid.UUID) error
-	EditPlaylistInfo(playlistID uuid.UUID, name, description string) error
-	GetPlaylistTracks(playlistID uuid.UUID) ([]*models.Track, error)
-	GetPlaylistWithTracks(playlistID uuid.UUID) (*models.PlaylistTrack, error)
-	GetUserPlaylists(userID uuid.UUID) ([]*models.Playlist, error)
-	DeletePlaylist(playlistID, userID uuid.UUID) error
+	CreatePlaylist(ctx context.Context, userID uuid.UUID, name, description string, coverURL string) (*models.Playlist, error)
+	AddTrackToPlaylist(ctx context.Context, playlistID, trackID uuid.UUID) error
+	RemoveTrackFromPlaylist(ctx context.Context, playlistID, trackID uuid.UUID) error
+	EditPlaylistInfo(ctx context.Context, playlistID uuid.UUID, name, description string) error
+	GetPlaylistTracks(ctx context.Context, playlistID uuid.UUID) ([]*models.Track, error)
+	GetPlaylistWithTracks(ctx context.Context, playlistID uuid.UUID) (*models.PlaylistTrack, error)
+	GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error)
+	DeletePlaylist(ctx context.Context, playlistID, userID uuid.UUID) error
 }
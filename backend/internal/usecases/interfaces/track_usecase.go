@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"context"
 	"io"
 	"music-service/internal/models"
 
@@ -8,11 +9,13 @@ import (
 )
 
 type TrackUseCase interface {
-	SearchTracks(query string) ([]*models.Track, error)
-	PlayTrack(userID uuid.UUID, trackID uuid.UUID) error
-	GetTrackDetails(trackID uuid.UUID) (*models.TrackDetails, error)
-	UpdateTrackMetadata(trackID uuid.UUID, metadata map[string]interface{}) error
-	DeleteTrack(trackID uuid.UUID) error
-	UploadTrack(fileReader io.Reader, fileSize int64, metadata models.TrackUploadMetadata) (*models.Track, error)
-	GetTrackFilePath(trackID uuid.UUID) (string, error)
+	SearchTracks(ctx context.Context, query string) ([]*models.Track, error)
+	PlayTrack(ctx context.Context, userID uuid.UUID, trackID uuid.UUID) error
+	GetTrackDetails(ctx context.Context, trackID uuid.UUID) (*models.TrackDetails, error)
+	UpdateTrackMetadata(ctx context.Context, trackID uuid.UUID, update models.TrackMetadataUpdate) error
+	DeleteTrack(ctx context.Context, trackID uuid.UUID) error
+	UploadTrack(ctx context.Context, fileReader io.Reader, fileSize int64, metadata models.TrackUploadMetadata) (*models.Track, []*models.DuplicateMatch, error)
+	AnalyzeLoudness(ctx context.Context, trackID uuid.UUID) (*models.Loudness, error)
+	FingerprintTrack(ctx context.Context, trackID uuid.UUID) ([]*models.DuplicateMatch, error)
+	GetTrackFilePath(ctx context.Context, trackID uuid.UUID) (string, error)
 }
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type TranslationUseCase interface {
+	ListTranslations(ctx context.Context, entityType string, id uuid.UUID) ([]*models.Translation, error)
+	SetTranslation(ctx context.Context, entityType string, id uuid.UUID, locale string, fields map[string]string) (*models.Translation, error)
+	DeleteTranslation(ctx context.Context, entityType string, id uuid.UUID, locale string) error
+}
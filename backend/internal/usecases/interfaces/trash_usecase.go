@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"music-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashUseCase interface {
+	ListTrash(ctx context.Context, filter models.TrashFilter) (*models.TrashPage, error)
+	ListOwnTrash(ctx context.Context, limit, offset int) (*models.TrashPage, error)
+	Restore(ctx context.Context, itemType string, id uuid.UUID) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
@@ -1,17 +1,29 @@
 package interfaces
 
 import (
+	"context"
 	"music-service/internal/models"
+	"music-service/internal/tokens"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type UserUseCase interface {
-	Register(login, password string) (*models.User, error)
-	Authenticate(login, password string) (*models.User, *models.Session, error)
-	GetUserProfile(userID uuid.UUID) (*models.User, error)
-	UpdatePermissions(userID uuid.UUID, permission models.Permission) error
-	DeleteUser(userID uuid.UUID) error
-	Logout(sessionID uuid.UUID) error
-	ValidateSession(token string) (*models.User, error)
+	Register(ctx context.Context, login, password, email string) (*models.User, error)
+	Authenticate(ctx context.Context, login, password string, device models.Device, client models.ClientInfo) (*models.User, *models.Session, *models.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string, client models.ClientInfo) (*models.TokenPair, error)
+	GetUserProfile(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	SetLocale(ctx context.Context, userID uuid.UUID, locale string) (string, error)
+	UpdatePermissions(ctx context.Context, userID uuid.UUID, permission models.Permission) error
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	Logout(ctx context.Context, sessionID uuid.UUID) error
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) (int, error)
+	PurgeExpiredSessions(ctx context.Context, before time.Time) (int64, error)
+	ValidateAccessToken(ctx context.Context, token string) (*tokens.Claims, error)
+	CreateAdmin(ctx context.Context, login, password string) (*models.User, error)
+	EnsureAdmin(ctx context.Context, login, password string) (*models.User, error)
+	SetPassword(ctx context.Context, login, password string) error
 }
@@ -0,0 +1,338 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-service/internal/audit"
+	"music-service/internal/authz"
+	"music-service/internal/i18n"
+	"music-service/internal/lyrics"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxLyricsLength — предел длины текста песни в символах
+const maxLyricsLength = 20000
+
+type lyricsUseCase struct {
+	lyricsRepo interfaces.LyricsRepository
+	trackRepo  interfaces.TrackRepository
+	uow        interfaces.UnitOfWork
+	localizer  catalogLocalizer
+}
+
+func NewLyricsUseCase(
+	lyricsRepo interfaces.LyricsRepository,
+	trackRepo interfaces.TrackRepository,
+	translationRepo interfaces.TranslationRepository,
+	uow interfaces.UnitOfWork,
+) usecaseInterfaces.LyricsUseCase {
+	return &lyricsUseCase{
+		lyricsRepo: lyricsRepo,
+		trackRepo:  trackRepo,
+		uow:        uow,
+		localizer:  newCatalogLocalizer(translationRepo),
+	}
+}
+
+// GetLyrics возвращает текст песни со строками. Неодобренный текст видят
+// только модераторы и его автор, остальным он не показывается
+func (uc *lyricsUseCase) GetLyrics(ctx context.Context, trackID uuid.UUID) (*models.Lyrics, error) {
+	if _, err := uc.trackRepo.FindByID(ctx, trackID); err != nil {
+		return nil, lookupError(err, models.ErrTrackNotFound)
+	}
+
+	found, err := uc.lyricsRepo.FindByTrack(ctx, trackID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrLyricsNotFound)
+	}
+	if !canViewLyrics(ctx, found) {
+		return nil, models.ErrLyricsNotFound
+	}
+
+	fillLyricsLines(found)
+	return found, nil
+}
+
+// SetLyrics сохраняет текст песни: обычный или LRC, формат определяется по
+// меткам времени. Текст модератора публикуется сразу, текст пользователя
+// ждет модерации. Пользователь не может заменить опубликованный текст или
+// чужой текст на модерации
+func (uc *lyricsUseCase) SetLyrics(ctx context.Context, trackID uuid.UUID, text, language string) (*models.Lyrics, error) {
+	principal, err := authz.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	saved, err := newLyrics(trackID, text, language, models.LyricsSourceUpload, now)
+	if err != nil {
+		return nil, err
+	}
+	saved.SubmittedBy = principal.UserID
+
+	if _, err := uc.trackRepo.FindByID(ctx, trackID); err != nil {
+		return nil, lookupError(err, models.ErrTrackNotFound)
+	}
+	before, err := uc.lyricsRepo.FindByTrack(ctx, trackID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get lyrics: %w", err)
+	}
+
+	moderator := canModerateLyrics(principal)
+	if before != nil && !moderator {
+		if before.Status == models.LyricsApproved || (before.Status == models.LyricsPending && before.SubmittedBy != principal.UserID) {
+			return nil, models.ErrLyricsExist
+		}
+	}
+	if moderator {
+		saved.Status = models.LyricsApproved
+		saved.ModeratedBy = principal.UserID
+		saved.ModeratedAt = &now
+	}
+	if before != nil {
+		saved.CreatedAt = before.CreatedAt
+	}
+
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Lyrics.Save(ctx, saved); err != nil {
+			return err
+		}
+		if published(before) || published(saved) {
+			if err := repos.Lyrics.TouchTrack(ctx, trackID, now); err != nil {
+				return err
+			}
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditLyricsSet,
+			EntityType: models.AuditEntityTrack,
+			EntityID:   trackID.String(),
+			Before:     lyricsSnapshot(before),
+			After:      lyricsSnapshot(saved),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save lyrics: %w", err)
+	}
+
+	fillLyricsLines(saved)
+	return saved, nil
+}
+
+func (uc *lyricsUseCase) DeleteLyrics(ctx context.Context, trackID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.LyricsModerate); err != nil {
+		return err
+	}
+
+	before, err := uc.lyricsRepo.FindByTrack(ctx, trackID)
+	if err != nil {
+		return lookupError(err, models.ErrLyricsNotFound)
+	}
+
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Lyrics.Delete(ctx, trackID); err != nil {
+			return lookupError(err, models.ErrLyricsNotFound)
+		}
+		if published(before) {
+			if err := repos.Lyrics.TouchTrack(ctx, trackID, time.Now()); err != nil {
+				return err
+			}
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditLyricsDelete,
+			EntityType: models.AuditEntityTrack,
+			EntityID:   trackID.String(),
+			Before:     lyricsSnapshot(before),
+		})
+	})
+}
+
+// ModerateLyrics одобряет или отклоняет текст песни. Одобренный текст можно
+// снять с публикации, вернув его на модерацию
+func (uc *lyricsUseCase) ModerateLyrics(ctx context.Context, trackID uuid.UUID, status string) (*models.Lyrics, error) {
+	principal, err := authz.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.Can(authz.LyricsModerate) {
+		return nil, authz.ErrForbidden
+	}
+	if !models.IsValidLyricsStatus(status) {
+		return nil, models.ErrInvalidLyricsStatus
+	}
+
+	before, err := uc.lyricsRepo.FindByTrack(ctx, trackID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrLyricsNotFound)
+	}
+	if before.Status == status {
+		fillLyricsLines(before)
+		return before, nil
+	}
+
+	now := time.Now()
+	after := *before
+	after.Status = status
+	after.ModeratedBy = principal.UserID
+	after.ModeratedAt = &now
+	after.UpdatedAt = now
+
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Lyrics.SetStatus(ctx, trackID, status, principal.UserID, now); err != nil {
+			return lookupError(err, models.ErrLyricsNotFound)
+		}
+		if published(before) || published(&after) {
+			if err := repos.Lyrics.TouchTrack(ctx, trackID, now); err != nil {
+				return err
+			}
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditLyricsModerate,
+			EntityType: models.AuditEntityTrack,
+			EntityID:   trackID.String(),
+			Before:     lyricsSnapshot(before),
+			After:      lyricsSnapshot(&after),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fillLyricsLines(&after)
+	return &after, nil
+}
+
+// ListLyrics возвращает тексты для модерации, по умолчанию — ожидающие ее
+func (uc *lyricsUseCase) ListLyrics(ctx context.Context, filter models.LyricsFilter) (*models.LyricsPage, error) {
+	if err := authz.Require(ctx, authz.LyricsModerate); err != nil {
+		return nil, err
+	}
+	if filter.Status == "" {
+		filter.Status = models.LyricsPending
+	}
+	if !models.IsValidLyricsStatus(filter.Status) {
+		return nil, models.ErrInvalidLyricsStatus
+	}
+	filter.Limit, filter.Offset = normalizePage(filter.Limit, filter.Offset)
+
+	page, err := uc.lyricsRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lyrics: %w", err)
+	}
+	return page, nil
+}
+
+// SearchLyrics ищет треки по одобренным текстам песен
+func (uc *lyricsUseCase) SearchLyrics(ctx context.Context, query string, limit, offset int) ([]*models.LyricsMatch, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < 3 {
+		return nil, models.ErrSearchQueryTooShort
+	}
+	limit, offset = normalizePage(limit, offset)
+
+	matches, err := uc.lyricsRepo.Search(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search lyrics: %w", err)
+	}
+
+	tracks := make([]*models.Track, 0, len(matches))
+	for _, match := range matches {
+		tracks = append(tracks, match.Track)
+	}
+	uc.localizer.tracks(ctx, tracks)
+	return matches, nil
+}
+
+// newLyrics разбирает текст песни и заполняет поля, которые из него
+// следуют. Статус — на модерации
+func newLyrics(trackID uuid.UUID, text, language, source string, now time.Time) (*models.Lyrics, error) {
+	text = lyrics.Normalize(text)
+	if text == "" {
+		return nil, models.ErrLyricsEmpty
+	}
+	if utf8.RuneCountInString(text) > maxLyricsLength {
+		return nil, models.ErrLyricsTooLong
+	}
+	if language != "" {
+		tag, ok := i18n.ParseTag(language)
+		if !ok {
+			return nil, models.ErrInvalidLocale
+		}
+		language = tag
+	}
+
+	parsed := lyrics.Parse(text)
+	plain := parsed.PlainText()
+	if plain == "" {
+		return nil, models.ErrLyricsEmpty
+	}
+	return &models.Lyrics{
+		TrackID:   trackID,
+		Language:  language,
+		Synced:    parsed.Synced,
+		Text:      text,
+		PlainText: plain,
+		Source:    source,
+		Status:    models.LyricsPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// fillLyricsLines разбирает сохраненный текст на строки для ответа
+func fillLyricsLines(l *models.Lyrics) {
+	parsed := lyrics.Parse(l.Text)
+	l.Lines = make([]models.LyricsLine, 0, len(parsed.Lines))
+	for _, line := range parsed.Lines {
+		entry := models.LyricsLine{Text: line.Text}
+		if parsed.Synced {
+			ms := line.Time.Milliseconds()
+			entry.TimeMs = &ms
+		}
+		l.Lines = append(l.Lines, entry)
+	}
+}
+
+// canModerateLyrics — тексты модератора и редактора каталога публикуются
+// без модерации
+func canModerateLyrics(principal *authz.Principal) bool {
+	return principal.Can(authz.LyricsModerate) || principal.Can(authz.TrackEdit)
+}
+
+func canViewLyrics(ctx context.Context, l *models.Lyrics) bool {
+	if l.Status == models.LyricsApproved {
+		return true
+	}
+	principal, ok := authz.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	return principal.Can(authz.LyricsModerate) || (l.SubmittedBy != uuid.Nil && l.SubmittedBy == principal.UserID)
+}
+
+// published сообщает, виден ли текст в карточке трека
+func published(l *models.Lyrics) bool {
+	return l != nil && l.Status == models.LyricsApproved
+}
+
+// lyricsSnapshot — поля текста песни для журнала. Сам текст не
+// записывается: он может быть длинным
+func lyricsSnapshot(l *models.Lyrics) audit.Snapshot {
+	if l == nil {
+		return nil
+	}
+	return audit.Snapshot{
+		"status":   l.Status,
+		"language": l.Language,
+		"synced":   l.Synced,
+		"source":   l.Source,
+		"length":   utf8.RuneCountInString(l.Text),
+	}
+}
@@ -0,0 +1,488 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"music-service/internal/audit"
+	"music-service/internal/authz"
+	"music-service/internal/mfa"
+	"music-service/internal/models"
+	"music-service/internal/ratelimit"
+	"music-service/internal/repository/interfaces"
+	"music-service/internal/tokens"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Время на ввод второго фактора после проверки пароля
+	mfaChallengeTTL = 5 * time.Minute
+	// После стольких неверных кодов пароль придется ввести заново
+	mfaMaxAttempts = 5
+)
+
+type mfaUseCase struct {
+	userRepo interfaces.UserRepository
+	mfaRepo  interfaces.MFARepository
+	uow      interfaces.UnitOfWork
+	tokens   *tokens.Manager
+	lockout  *ratelimit.Lockout
+	issuer   string
+}
+
+// NewMFAUseCase создает use case двухфакторной аутентификации. issuer —
+// название сервиса, которое увидит пользователь в приложении-аутентификаторе
+func NewMFAUseCase(
+	userRepo interfaces.UserRepository,
+	mfaRepo interfaces.MFARepository,
+	uow interfaces.UnitOfWork,
+	tokenManager *tokens.Manager,
+	lockout *ratelimit.Lockout,
+	issuer string,
+) usecaseInterfaces.MFAUseCase {
+	return &mfaUseCase{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		uow:      uow,
+		tokens:   tokenManager,
+		lockout:  lockout,
+		issuer:   issuer,
+	}
+}
+
+// requireSecondFactor вызывается после проверки пароля. Если у пользователя
+// подключен TOTP или его роль требует второго фактора, создается вход,
+// ожидающий второго шага, и возвращается *mfa.ChallengeRequired. Если второй
+// фактор не нужен, возвращается nil
+func requireSecondFactor(ctx context.Context, mfaRepo interfaces.MFARepository, user *models.User, device models.Device) error {
+	credential, err := mfaRepo.GetTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return fmt.Errorf("failed to get totp: %w", err)
+	}
+
+	enabled := credential.Confirmed()
+	if !enabled {
+		required, err := roleRequiresMFA(ctx, mfaRepo, user.Permission)
+		if err != nil {
+			return err
+		}
+		if !required {
+			return nil
+		}
+	}
+
+	token := tokens.NewOpaqueToken()
+	challenge := &models.MFAChallenge{
+		TokenHash: tokens.HashOpaqueToken(token),
+		UserID:    user.ID,
+		Device:    normalizeDevice(device),
+		ExpiresAt: time.Now().Add(mfaChallengeTTL),
+	}
+	if err := mfaRepo.CreateChallenge(ctx, challenge); err != nil {
+		return fmt.Errorf("failed to create mfa challenge: %w", err)
+	}
+
+	return &mfa.ChallengeRequired{
+		Token:              token,
+		ExpiresAt:          challenge.ExpiresAt,
+		EnrollmentRequired: !enabled,
+	}
+}
+
+func roleRequiresMFA(ctx context.Context, mfaRepo interfaces.MFARepository, role models.Permission) (bool, error) {
+	roles, err := mfaRepo.ListRequiredRoles(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get mfa policy: %w", err)
+	}
+	for _, required := range roles {
+		if required == role {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (uc *mfaUseCase) Status(ctx context.Context, userID uuid.UUID) (*models.MFAStatus, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrUserNotFound)
+	}
+
+	credential, err := uc.getTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := roleRequiresMFA(ctx, uc.mfaRepo, user.Permission)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.MFAStatus{
+		Enabled:  credential.Confirmed(),
+		Required: required,
+	}
+	if status.Enabled {
+		if status.RecoveryCodesRemaining, err = uc.mfaRepo.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, fmt.Errorf("failed to count recovery codes: %w", err)
+		}
+	}
+	return status, nil
+}
+
+// BeginEnrollment создает новый секрет TOTP. Второй фактор включается только
+// после подтверждения кодом из приложения (ConfirmEnrollment)
+func (uc *mfaUseCase) BeginEnrollment(ctx context.Context, userID uuid.UUID) (*models.TOTPEnrollment, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrUserNotFound)
+	}
+	return uc.beginEnrollment(ctx, user)
+}
+
+func (uc *mfaUseCase) beginEnrollment(ctx context.Context, user *models.User) (*models.TOTPEnrollment, error) {
+	credential, err := uc.getTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if credential.Confirmed() {
+		return nil, models.ErrMFAAlreadyEnabled
+	}
+
+	secret := mfa.GenerateSecret()
+	err = uc.mfaRepo.SaveTOTP(ctx, &models.TOTPCredential{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save totp: %w", err)
+	}
+
+	return &models.TOTPEnrollment{
+		Secret: secret,
+		URI:    mfa.ProvisioningURI(uc.issuer, user.Login, secret),
+	}, nil
+}
+
+// ConfirmEnrollment включает TOTP, если код из приложения верен, и выдает
+// коды восстановления. Коды показываются один раз
+func (uc *mfaUseCase) ConfirmEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	credential, err := uc.getTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.confirmEnrollment(ctx, credential, code)
+}
+
+func (uc *mfaUseCase) confirmEnrollment(ctx context.Context, credential *models.TOTPCredential, code string) ([]string, error) {
+	if credential == nil {
+		return nil, models.ErrMFAEnrollmentNotStarted
+	}
+	if credential.Confirmed() {
+		return nil, models.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := mfa.Validate(credential.Secret, code, time.Now())
+	if !ok {
+		return nil, models.ErrInvalidVerificationCode
+	}
+
+	codes := mfa.NewRecoveryCodes(mfa.RecoveryCodeCount)
+	err := uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		confirmed, err := repos.MFA.ConfirmTOTP(ctx, credential.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return models.ErrMFAAlreadyEnabled
+		}
+		return repos.MFA.ReplaceRecoveryCodes(ctx, credential.UserID, hashRecoveryCodes(credential.UserID, codes))
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrMFAAlreadyEnabled) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to enable totp: %w", err)
+	}
+
+	return codes, nil
+}
+
+// Disable отключает TOTP. Нужен действующий код или код восстановления;
+// если роль пользователя требует второго фактора, отключить его нельзя
+func (uc *mfaUseCase) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return lookupError(err, models.ErrUserNotFound)
+	}
+
+	required, err := roleRequiresMFA(ctx, uc.mfaRepo, user.Permission)
+	if err != nil {
+		return err
+	}
+	if required {
+		return models.ErrMFARequired
+	}
+
+	credential, err := uc.enabledTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.verifyCode(ctx, credential, code); err != nil {
+		return err
+	}
+
+	if err := uc.mfaRepo.DeleteTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("failed to disable totp: %w", err)
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes заменяет коды восстановления новыми; старые
+// перестают действовать
+func (uc *mfaUseCase) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	credential, err := uc.enabledTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.verifyCode(ctx, credential, code); err != nil {
+		return nil, err
+	}
+
+	codes := mfa.NewRecoveryCodes(mfa.RecoveryCodeCount)
+	if err := uc.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashRecoveryCodes(userID, codes)); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// BeginChallengeEnrollment начинает подключение TOTP на втором шаге входа —
+// для пользователей, чья роль требует второго фактора, а он не подключен
+func (uc *mfaUseCase) BeginChallengeEnrollment(ctx context.Context, mfaToken string) (*models.TOTPEnrollment, error) {
+	challenge, err := uc.getChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrUserNotFound)
+	}
+	return uc.beginEnrollment(ctx, user)
+}
+
+// CompleteChallenge завершает вход вторым фактором. Если TOTP еще не
+// подключен, код подтверждает подключение, начатое BeginChallengeEnrollment,
+// и в результате возвращаются коды восстановления
+func (uc *mfaUseCase) CompleteChallenge(ctx context.Context, mfaToken, code string, client models.ClientInfo) (*models.MFALogin, error) {
+	challenge, err := uc.getChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrUserNotFound)
+	}
+	credential, err := uc.getTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if credential.Confirmed() {
+		err = uc.verifyCode(ctx, credential, code)
+	} else {
+		recoveryCodes, err = uc.confirmEnrollment(ctx, credential, code)
+	}
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidVerificationCode) {
+			if recordErr := uc.mfaRepo.RecordFailedAttempt(ctx, challenge.TokenHash, mfaMaxAttempts); recordErr != nil {
+				return nil, fmt.Errorf("failed to record attempt: %w", recordErr)
+			}
+		}
+		return nil, err
+	}
+
+	// Вход завершается один раз, даже если код отправлен параллельно
+	deleted, err := uc.mfaRepo.DeleteChallenge(ctx, challenge.TokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete mfa challenge: %w", err)
+	}
+	if !deleted {
+		return nil, models.ErrInvalidMFAToken
+	}
+
+	session, pair, err := startSession(ctx, uc.uow, uc.tokens, user, challenge.Device, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MFALogin{
+		User:          user,
+		Session:       session,
+		Tokens:        pair,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// ResetForUser отключает TOTP пользователю, потерявшему и устройство, и
+// коды восстановления. Если роль требует второго фактора, при следующем
+// входе пользователь подключит его заново
+func (uc *mfaUseCase) ResetForUser(ctx context.Context, userID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return err
+	}
+
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		return lookupError(err, models.ErrUserNotFound)
+	}
+	err := uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.MFA.DeleteTOTP(ctx, userID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, repos.Audit, models.AuditUserMFAReset, models.AuditEntityUser, userID.String(), nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reset totp: %w", err)
+	}
+	return nil
+}
+
+func (uc *mfaUseCase) RequiredRoles(ctx context.Context) ([]models.Permission, error) {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+
+	roles, err := uc.mfaRepo.ListRequiredRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mfa policy: %w", err)
+	}
+	return roles, nil
+}
+
+// SetRequiredRoles задает роли, для которых второй фактор обязателен.
+// Пользователи этих ролей без TOTP подключат его при следующем входе
+func (uc *mfaUseCase) SetRequiredRoles(ctx context.Context, roles []models.Permission) ([]models.Permission, error) {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+
+	unique := make([]models.Permission, 0, len(roles))
+	seen := make(map[models.Permission]bool)
+	for _, role := range roles {
+		if !role.IsValid() {
+			return nil, models.ErrInvalidRole
+		}
+		if !seen[role] {
+			seen[role] = true
+			unique = append(unique, role)
+		}
+	}
+
+	previous, err := uc.mfaRepo.ListRequiredRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mfa policy: %w", err)
+	}
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.MFA.SetRequiredRoles(ctx, unique); err != nil {
+			return err
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditMFAPolicyChange,
+			EntityType: models.AuditEntityMFAPolicy,
+			Before:     audit.Snapshot{"required_roles": previous},
+			After:      audit.Snapshot{"required_roles": unique},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save mfa policy: %w", err)
+	}
+	return unique, nil
+}
+
+// getTOTP возвращает секрет пользователя или nil, если TOTP не подключался
+func (uc *mfaUseCase) getTOTP(ctx context.Context, userID uuid.UUID) (*models.TOTPCredential, error) {
+	credential, err := uc.mfaRepo.GetTOTP(ctx, userID)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get totp: %w", err)
+	}
+	return credential, nil
+}
+
+func (uc *mfaUseCase) enabledTOTP(ctx context.Context, userID uuid.UUID) (*models.TOTPCredential, error) {
+	credential, err := uc.getTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !credential.Confirmed() {
+		return nil, models.ErrMFANotEnabled
+	}
+	return credential, nil
+}
+
+func (uc *mfaUseCase) getChallenge(ctx context.Context, mfaToken string) (*models.MFAChallenge, error) {
+	if mfaToken == "" {
+		return nil, models.ErrInvalidMFAToken
+	}
+	challenge, err := uc.mfaRepo.GetChallenge(ctx, tokens.HashOpaqueToken(mfaToken))
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, models.ErrInvalidMFAToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mfa challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// verifyCode принимает код TOTP или код восстановления. Каждый код
+// действует один раз. Неудачи считаются по пользователю, чтобы код нельзя
+// было подобрать, начиная вход заново
+func (uc *mfaUseCase) verifyCode(ctx context.Context, credential *models.TOTPCredential, code string) error {
+	lockKey := "mfa:" + credential.UserID.String()
+	if err := checkLockout(ctx, uc.lockout, lockKey); err != nil {
+		return err
+	}
+
+	code = strings.TrimSpace(code)
+
+	var accepted bool
+	var err error
+	if step, ok := mfa.Validate(credential.Secret, code, time.Now()); ok {
+		accepted, err = uc.mfaRepo.UseTOTPStep(ctx, credential.UserID, step)
+	} else if normalized := mfa.NormalizeRecoveryCode(code); len(normalized) > mfa.Digits {
+		accepted, err = uc.mfaRepo.UseRecoveryCode(ctx, credential.UserID, hashRecoveryCode(credential.UserID, normalized))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify code: %w", err)
+	}
+	if !accepted {
+		return recordFailure(ctx, uc.lockout, lockKey, models.ErrInvalidVerificationCode)
+	}
+	resetLockout(ctx, uc.lockout, lockKey)
+	return nil
+}
+
+func hashRecoveryCodes(userID uuid.UUID, codes []string) []string {
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = hashRecoveryCode(userID, mfa.NormalizeRecoveryCode(code))
+	}
+	return hashes
+}
+
+// hashRecoveryCode хеширует код вместе с идентификатором пользователя, чтобы
+// одинаковые коды разных пользователей не совпадали в БД
+func hashRecoveryCode(userID uuid.UUID, normalizedCode string) string {
+	sum := sha256.Sum256([]byte(userID.String() + ":" + normalizedCode))
+	return hex.EncodeToString(sum[:])
+}
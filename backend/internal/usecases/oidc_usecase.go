@@ -0,0 +1,368 @@
+package usecases
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"music-service/internal/authz"
+	"music-service/internal/logging"
+	"music-service/internal/models"
+	"music-service/internal/oidc"
+	"music-service/internal/repository/interfaces"
+	"music-service/internal/tokens"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const defaultOAuthStateTTL = 10 * time.Minute
+
+// unusablePassword — пароль пользователей, созданных при входе через
+// провайдера. Это не bcrypt-хеш, поэтому войти по паролю нельзя, пока
+// пользователь не задаст пароль через сброс
+const unusablePassword = "!"
+
+// IdentityProvider — настроенный провайдер OpenID Connect. Staff — провайдер
+// корпоративного SSO, единственный способ входа для сотрудников. TrustEmail
+// разрешает привязывать вход к существующему пользователю по подтвержденной
+// почте; включать только для провайдеров, которые контролирует компания
+type IdentityProvider struct {
+	Client     *oidc.Provider
+	Staff      bool
+	TrustEmail bool
+}
+
+type oidcUseCase struct {
+	userRepo     interfaces.UserRepository
+	identityRepo interfaces.IdentityRepository
+	mfaRepo      interfaces.MFARepository
+	uow          interfaces.UnitOfWork
+	tokens       *tokens.Manager
+	providers    map[string]IdentityProvider
+	stateTTL     time.Duration
+	staffSSOOnly bool
+}
+
+func NewOIDCUseCase(
+	userRepo interfaces.UserRepository,
+	identityRepo interfaces.IdentityRepository,
+	mfaRepo interfaces.MFARepository,
+	uow interfaces.UnitOfWork,
+	tokenManager *tokens.Manager,
+	providers []IdentityProvider,
+	stateTTL time.Duration,
+) usecaseInterfaces.OIDCUseCase {
+	if stateTTL <= 0 {
+		stateTTL = defaultOAuthStateTTL
+	}
+	byName := make(map[string]IdentityProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Client.Name()] = provider
+	}
+	return &oidcUseCase{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		mfaRepo:      mfaRepo,
+		uow:          uow,
+		tokens:       tokenManager,
+		providers:    byName,
+		stateTTL:     stateTTL,
+		staffSSOOnly: StaffSSOConfigured(providers),
+	}
+}
+
+// StaffSSOConfigured сообщает, есть ли среди провайдеров корпоративный SSO.
+// Только в этом случае вход сотрудников по паролю запрещается
+func StaffSSOConfigured(providers []IdentityProvider) bool {
+	for _, provider := range providers {
+		if provider.Staff {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *oidcUseCase) Providers() []string {
+	names := make([]string, 0, len(uc.providers))
+	for name := range uc.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeginLogin начинает вход через провайдера и возвращает адрес его страницы
+// входа. Если linkUserID задан, учетная запись провайдера будет привязана к
+// этому пользователю
+func (uc *oidcUseCase) BeginLogin(ctx context.Context, providerName string, linkUserID uuid.UUID) (string, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return "", models.ErrUnknownProvider
+	}
+
+	state := tokens.NewOpaqueToken()
+	nonce := tokens.NewOpaqueToken()
+	verifier := oidc.NewCodeVerifier()
+
+	authURL, err := provider.Client.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return "", fmt.Errorf("identity provider is unavailable: %w", err)
+	}
+
+	err = uc.identityRepo.SaveState(ctx, &models.OAuthState{
+		StateHash:    tokens.HashOpaqueToken(state),
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		LinkUserID:   linkUserID,
+		ExpiresAt:    time.Now().Add(uc.stateTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to save login state: %w", err)
+	}
+
+	return authURL, nil
+}
+
+// CompleteLogin завершает вход: проверяет state, обменивает код на
+// ID-токен, находит или создает пользователя и открывает сессию
+func (uc *oidcUseCase) CompleteLogin(ctx context.Context, providerName, state, code string, device models.Device, client models.ClientInfo) (*models.User, *models.Session, *models.TokenPair, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return nil, nil, nil, models.ErrUnknownProvider
+	}
+	if state == "" || code == "" {
+		return nil, nil, nil, models.ErrInvalidLoginState
+	}
+
+	// state одноразовый: повторить обратный вызов провайдера нельзя
+	saved, err := uc.identityRepo.ConsumeState(ctx, tokens.HashOpaqueToken(state))
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, nil, nil, models.ErrInvalidLoginState
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load login state: %w", err)
+	}
+	if saved.Provider != providerName {
+		return nil, nil, nil, models.ErrInvalidLoginState
+	}
+
+	idToken, err := provider.Client.Exchange(ctx, code, saved.CodeVerifier, saved.Nonce)
+	if err != nil {
+		logging.FromContext(ctx).Warn("oidc login failed", "provider", providerName, "error", err)
+		return nil, nil, nil, models.ErrExternalAuthFailed
+	}
+
+	user, err := uc.resolveUser(ctx, providerName, provider, idToken, saved.LinkUserID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if authz.IsStaff(user.Permission) && !provider.Staff && uc.staffSSOOnly {
+		return nil, nil, nil, models.ErrStaffSSORequired
+	}
+	if err := checkSuspension(user); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Корпоративный SSO сам отвечает за второй фактор, у остальных
+	// провайдеров он запрашивается так же, как при входе по паролю
+	if !provider.Staff {
+		if err := requireSecondFactor(ctx, uc.mfaRepo, user, device); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	session, pair, err := startSession(ctx, uc.uow, uc.tokens, user, device, client)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return user, session, pair, nil
+}
+
+// resolveUser находит пользователя, которому принадлежит учетная запись
+// провайдера, при необходимости привязывая ее или создавая пользователя
+func (uc *oidcUseCase) resolveUser(ctx context.Context, providerName string, provider IdentityProvider, idToken *oidc.IDToken, linkUserID uuid.UUID) (*models.User, error) {
+	identity, err := uc.identityRepo.FindByProviderSubject(ctx, providerName, idToken.Subject)
+	if err == nil {
+		if linkUserID != uuid.Nil && identity.UserID != linkUserID {
+			return nil, models.ErrIdentityLinked
+		}
+		user, err := uc.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		return user, nil
+	}
+	if !errors.Is(err, models.ErrNotFound) {
+		return nil, fmt.Errorf("failed to find identity: %w", err)
+	}
+
+	newIdentity := &models.Identity{
+		Provider:  providerName,
+		Subject:   idToken.Subject,
+		Email:     idToken.Email,
+		CreatedAt: time.Now(),
+	}
+
+	// Привязка к пользователю, начавшему вход из своего профиля
+	if linkUserID != uuid.Nil {
+		user, err := uc.userRepo.FindByID(ctx, linkUserID)
+		if err != nil {
+			return nil, lookupError(err, models.ErrUserNotFound)
+		}
+		newIdentity.UserID = user.ID
+		if err := uc.identityRepo.Create(ctx, newIdentity); err != nil {
+			return nil, fmt.Errorf("failed to link identity: %w", err)
+		}
+		return user, nil
+	}
+
+	// Подтвержденной почте доверяем только у провайдеров компании, иначе
+	// чужой провайдер мог бы выдать себя за владельца адреса
+	if provider.TrustEmail && idToken.EmailVerified && idToken.Email != "" {
+		user, err := uc.userRepo.FindByEmail(ctx, idToken.Email)
+		if err == nil {
+			newIdentity.UserID = user.ID
+			if err := uc.identityRepo.Create(ctx, newIdentity); err != nil {
+				return nil, fmt.Errorf("failed to link identity: %w", err)
+			}
+			return user, nil
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			return nil, fmt.Errorf("failed to find user by email: %w", err)
+		}
+	}
+
+	return uc.createUser(ctx, newIdentity, idToken)
+}
+
+// createUser регистрирует пользователя при первом входе через провайдера
+func (uc *oidcUseCase) createUser(ctx context.Context, identity *models.Identity, idToken *oidc.IDToken) (*models.User, error) {
+	login, err := uc.uniqueLogin(ctx, identity.Provider, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Почта сохраняется, только если провайдер ее подтвердил и она свободна
+	var email string
+	if idToken.EmailVerified && validateEmail(idToken.Email) == nil {
+		if taken, err := uc.userRepo.IsEmailTaken(ctx, idToken.Email); err == nil && !taken {
+			email = idToken.Email
+		}
+	}
+
+	now := time.Now()
+	user := &models.User{
+		ID:         uuid.New(),
+		Login:      login,
+		Email:      email,
+		Password:   unusablePassword,
+		Permission: models.UserPermission,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	identity.UserID = user.ID
+
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.User.Save(ctx, user); err != nil {
+			return err
+		}
+		return repos.Identity.Create(ctx, identity)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return user, nil
+}
+
+// uniqueLogin подбирает свободный логин из данных провайдера
+func (uc *oidcUseCase) uniqueLogin(ctx context.Context, providerName string, idToken *oidc.IDToken) (string, error) {
+	base := idToken.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(idToken.Email, "@")
+	}
+	base = sanitizeLogin(base)
+	if base == "" {
+		base = providerName + "_user"
+	}
+	for len(base) < 6 {
+		base += "_"
+	}
+
+	login := base
+	for attempt := 0; attempt < 5; attempt++ {
+		taken, err := uc.userRepo.IsLoginTaken(ctx, login)
+		if err != nil {
+			return "", fmt.Errorf("failed to check login: %w", err)
+		}
+		if !taken {
+			return login, nil
+		}
+		login = base + "_" + hex.EncodeToString(uuid.New().NodeID()[:3])
+	}
+
+	return "", errors.New("could not generate a unique login")
+}
+
+func sanitizeLogin(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-') {
+			b.WriteRune(r)
+		}
+	}
+	return truncateRunes(b.String(), 50)
+}
+
+func (uc *oidcUseCase) ListIdentities(ctx context.Context, userID uuid.UUID) ([]*models.Identity, error) {
+	identities, err := uc.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+	return identities, nil
+}
+
+// UnlinkIdentity отвязывает учетную запись провайдера. Последний способ
+// входа пользователя без пароля отвязать нельзя
+func (uc *oidcUseCase) UnlinkIdentity(ctx context.Context, userID uuid.UUID, providerName string) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return lookupError(err, models.ErrUserNotFound)
+	}
+
+	identities, err := uc.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list identities: %w", err)
+	}
+
+	found := false
+	for _, identity := range identities {
+		if identity.Provider == providerName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return models.ErrIdentityNotFound
+	}
+	if len(identities) == 1 && !hasUsablePassword(user) {
+		return models.ErrLastSignInMethod
+	}
+
+	if err := uc.identityRepo.Delete(ctx, userID, providerName); err != nil {
+		return fmt.Errorf("failed to unlink identity: %w", err)
+	}
+	return nil
+}
+
+func hasUsablePassword(user *models.User) bool {
+	return strings.HasPrefix(user.Password, "$2")
+}
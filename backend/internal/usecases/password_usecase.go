@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-service/internal/i18n"
+	"music-service/internal/logging"
+	"music-service/internal/mail"
+	"music-service/internal/models"
+	"music-service/internal/password"
+	"music-service/internal/repository/interfaces"
+	"music-service/internal/tokens"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultResetTokenTTL = time.Hour
+
+type passwordUseCase struct {
+	userRepo interfaces.UserRepository
+	uow      interfaces.UnitOfWork
+	tokens   *tokens.Manager
+	policy   *password.Policy
+	mailer   mail.Sender
+	resetTTL time.Duration
+	resetURL string
+}
+
+// NewPasswordUseCase создает сценарии смены и сброса пароля. Ссылка в письме
+// о сбросе — resetURL с добавленным в конец токеном
+func NewPasswordUseCase(
+	userRepo interfaces.UserRepository,
+	uow interfaces.UnitOfWork,
+	tokenManager *tokens.Manager,
+	passwordPolicy *password.Policy,
+	mailer mail.Sender,
+	resetTTL time.Duration,
+	resetURL string,
+) usecaseInterfaces.PasswordUseCase {
+	if resetTTL <= 0 {
+		resetTTL = defaultResetTokenTTL
+	}
+	return &passwordUseCase{
+		userRepo: userRepo,
+		uow:      uow,
+		tokens:   tokenManager,
+		policy:   passwordPolicy,
+		mailer:   mailer,
+		resetTTL: resetTTL,
+		resetURL: resetURL,
+	}
+}
+
+// ChangePassword меняет пароль по текущему и завершает все остальные сессии
+// пользователя
+func (uc *passwordUseCase) ChangePassword(ctx context.Context, userID, currentSessionID uuid.UUID, currentPassword, newPassword string) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return lookupError(err, models.ErrUserNotFound)
+	}
+
+	if !checkPasswordHash(currentPassword, user.Password) {
+		return models.ErrInvalidCurrentPassword
+	}
+	if currentPassword == newPassword {
+		return models.ErrPasswordUnchanged
+	}
+	if err := uc.policy.Validate(newPassword, user.Login); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.Password = hashedPassword
+	user.UpdatedAt = time.Now()
+
+	until := uc.tokens.RevocationExpiry()
+	var revoked []uuid.UUID
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.User.Save(ctx, user); err != nil {
+			return err
+		}
+		if err := repos.PasswordReset.DeleteForUser(ctx, user.ID); err != nil {
+			return err
+		}
+		var err error
+		revoked, err = repos.Session.RevokeOtherSessions(ctx, user.ID, currentSessionID, until)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change password: %w", err)
+	}
+
+	uc.tokens.Revoke(until, revoked...)
+	return nil
+}
+
+// RequestPasswordReset отправляет на почту ссылку для сброса пароля. Для
+// неизвестной почты ошибка не возвращается, чтобы по ответу нельзя было
+// узнать, зарегистрирован ли адрес
+func (uc *passwordUseCase) RequestPasswordReset(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return models.ErrEmailRequired
+	}
+
+	user, err := uc.userRepo.FindByEmail(ctx, email)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	token := tokens.NewOpaqueToken()
+	now := time.Now()
+	record := &models.PasswordResetToken{
+		TokenHash: tokens.HashOpaqueToken(token),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.resetTTL),
+	}
+
+	// Действует только последняя выданная ссылка
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.PasswordReset.DeleteForUser(ctx, user.ID); err != nil {
+			return err
+		}
+		return repos.PasswordReset.Create(ctx, record)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	// Письмо пишется на языке из настроек пользователя, а если он не выбран —
+	// на языке запроса
+	locale := i18n.FromContext(i18n.WithPreferred(ctx, user.Locale))
+	err = uc.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: i18n.Message(locale, "mail_password_reset_subject"),
+		Body:    i18n.Message(locale, "mail_password_reset_body", user.Login, uc.resetURL+token, uc.resetTTL),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("send password reset email failed", "user_id", user.ID, "error", err)
+		return models.ErrMailUnavailable
+	}
+
+	return nil
+}
+
+// ResetPassword задает новый пароль по токену из письма и завершает все
+// сессии пользователя. Токен одноразовый
+func (uc *passwordUseCase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return models.ErrInvalidResetToken
+	}
+
+	until := uc.tokens.RevocationExpiry()
+	var revoked []uuid.UUID
+	err := uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		// При ошибке ниже транзакция откатится и токен останется действующим
+		userID, err := repos.PasswordReset.Consume(ctx, tokens.HashOpaqueToken(token))
+		if errors.Is(err, models.ErrNotFound) {
+			return models.ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+
+		user, err := repos.User.FindByID(ctx, userID)
+		if err != nil {
+			return models.ErrInvalidResetToken
+		}
+		if err := uc.policy.Validate(newPassword, user.Login); err != nil {
+			return err
+		}
+
+		hashedPassword, err := hashPassword(newPassword)
+		if err != nil {
+			return fmt.Errorf("failed to hash password: %w", err)
+		}
+		user.Password = hashedPassword
+		user.UpdatedAt = time.Now()
+
+		if err := repos.User.Save(ctx, user); err != nil {
+			return err
+		}
+		if err := repos.PasswordReset.DeleteForUser(ctx, user.ID); err != nil {
+			return err
+		}
+		revoked, err = repos.Session.RevokeAllForUser(ctx, user.ID, until)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.tokens.Revoke(until, revoked...)
+	return nil
+}
@@ -0,0 +1,489 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"music-service/internal/events"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxQueueSize = 1000
+	// Если трек играет дольше этого времени, "назад" перематывает его в начало
+	restartThresholdMs = 3000
+)
+
+type playbackUseCase struct {
+	playbackRepo interfaces.PlaybackRepository
+	trackRepo    interfaces.TrackRepository
+	sessionRepo  interfaces.SessionRepository
+	uow          interfaces.UnitOfWork
+	bus          events.Bus
+}
+
+func NewPlaybackUseCase(
+	playbackRepo interfaces.PlaybackRepository,
+	trackRepo interfaces.TrackRepository,
+	sessionRepo interfaces.SessionRepository,
+	uow interfaces.UnitOfWork,
+	bus events.Bus,
+) usecaseInterfaces.PlaybackUseCase {
+	return &playbackUseCase{
+		playbackRepo: playbackRepo,
+		trackRepo:    trackRepo,
+		sessionRepo:  sessionRepo,
+		uow:          uow,
+		bus:          bus,
+	}
+}
+
+func (uc *playbackUseCase) GetState(ctx context.Context, userID uuid.UUID) (*models.PlaybackState, error) {
+	return uc.loadState(ctx, userID)
+}
+
+func (uc *playbackUseCase) SetQueue(ctx context.Context, userID, deviceID uuid.UUID, trackIDs []uuid.UUID, startIndex int) (*models.PlaybackState, error) {
+	if len(trackIDs) > maxQueueSize {
+		return nil, models.ErrQueueTooLong
+	}
+	if len(trackIDs) > 0 && (startIndex < 0 || startIndex >= len(trackIDs)) {
+		return nil, models.ErrInvalidStartIndex
+	}
+
+	tracks := make([]*models.Track, 0, len(trackIDs))
+	for _, trackID := range trackIDs {
+		track, err := uc.trackRepo.FindByID(ctx, trackID)
+		if err != nil {
+			return nil, lookupError(err, models.ErrTrackNotFound)
+		}
+		tracks = append(tracks, track)
+	}
+
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Queue = tracks
+	state.CurrentIndex = startIndex
+	state.PositionMs = 0
+	state.IsPlaying = len(tracks) > 0
+	if state.Shuffle {
+		state.ShuffleOrder = shuffledOrder(len(tracks), startIndex)
+		state.CurrentIndex = 0
+	}
+	if len(tracks) == 0 {
+		state.CurrentIndex = 0
+	}
+	claimDevice(state, deviceID)
+
+	return uc.saveQueueAndState(ctx, state)
+}
+
+func (uc *playbackUseCase) AddToQueue(ctx context.Context, userID, deviceID uuid.UUID, trackID uuid.UUID, playNext bool) (*models.PlaybackState, error) {
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrTrackNotFound)
+	}
+
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(state.Queue) >= maxQueueSize {
+		return nil, models.ErrQueueTooLong
+	}
+
+	// Позиция вставки в исходном порядке очереди
+	insertAt := len(state.Queue)
+	if playNext && len(state.Queue) > 0 {
+		insertAt = state.PlayOrder()[state.CurrentIndex] + 1
+	}
+
+	queue := make([]*models.Track, 0, len(state.Queue)+1)
+	queue = append(queue, state.Queue[:insertAt]...)
+	queue = append(queue, track)
+	queue = append(queue, state.Queue[insertAt:]...)
+
+	if state.Shuffle {
+		order := make([]int, 0, len(state.ShuffleOrder)+1)
+		for _, idx := range state.ShuffleOrder {
+			if idx >= insertAt {
+				idx++
+			}
+			order = append(order, idx)
+		}
+		if playNext && len(order) > 0 {
+			next := state.CurrentIndex + 1
+			order = append(order[:next], append([]int{insertAt}, order[next:]...)...)
+		} else {
+			order = append(order, insertAt)
+		}
+		state.ShuffleOrder = order
+	}
+
+	state.Queue = queue
+	claimDevice(state, deviceID)
+
+	return uc.saveQueueAndState(ctx, state)
+}
+
+func (uc *playbackUseCase) RemoveFromQueue(ctx context.Context, userID, deviceID uuid.UUID, position int) (*models.PlaybackState, error) {
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if position < 0 || position >= len(state.Queue) {
+		return nil, models.ErrInvalidQueuePosition
+	}
+
+	// Индекс удаляемого трека в порядке воспроизведения
+	removedAt := 0
+	for i, idx := range state.PlayOrder() {
+		if idx == position {
+			removedAt = i
+			break
+		}
+	}
+
+	queue := make([]*models.Track, 0, len(state.Queue)-1)
+	queue = append(queue, state.Queue[:position]...)
+	queue = append(queue, state.Queue[position+1:]...)
+
+	if state.Shuffle {
+		order := make([]int, 0, len(queue))
+		for _, idx := range state.ShuffleOrder {
+			switch {
+			case idx == position:
+				continue
+			case idx > position:
+				idx--
+			}
+			order = append(order, idx)
+		}
+		state.ShuffleOrder = order
+	}
+
+	state.Queue = queue
+
+	switch {
+	case removedAt < state.CurrentIndex:
+		state.CurrentIndex--
+	case removedAt == state.CurrentIndex:
+		// Текущий трек удален — воспроизведение продолжается со следующего
+		state.PositionMs = 0
+		if state.CurrentIndex >= len(queue) {
+			if state.RepeatMode == models.RepeatAll {
+				state.CurrentIndex = 0
+			} else {
+				state.CurrentIndex = len(queue) - 1
+				state.IsPlaying = false
+			}
+		}
+	}
+	if len(queue) == 0 {
+		state.CurrentIndex = 0
+		state.IsPlaying = false
+	}
+	claimDevice(state, deviceID)
+
+	return uc.saveQueueAndState(ctx, state)
+}
+
+func (uc *playbackUseCase) Play(ctx context.Context, userID, deviceID uuid.UUID, index *int) (*models.PlaybackState, error) {
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(state.Queue) == 0 {
+		return nil, models.ErrQueueEmpty
+	}
+
+	if index != nil {
+		if *index < 0 || *index >= len(state.Queue) {
+			return nil, models.ErrInvalidQueueIndex
+		}
+		state.CurrentIndex = *index
+		state.PositionMs = 0
+	}
+
+	state.IsPlaying = true
+	claimDevice(state, deviceID)
+
+	return uc.saveState(ctx, state)
+}
+
+func (uc *playbackUseCase) Pause(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error) {
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	state.IsPlaying = false
+	claimDevice(state, deviceID)
+
+	return uc.saveState(ctx, state)
+}
+
+func (uc *playbackUseCase) Seek(ctx context.Context, userID, deviceID uuid.UUID, positionMs int) (*models.PlaybackState, error) {
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	track := state.CurrentTrack()
+	if track == nil {
+		return nil, models.ErrQueueEmpty
+	}
+	if positionMs < 0 || (track.Duration > 0 && positionMs > track.Duration*1000) {
+		return nil, models.ErrPositionOutOfBounds
+	}
+
+	state.PositionMs = positionMs
+	claimDevice(state, deviceID)
+
+	return uc.saveState(ctx, state)
+}
+
+func (uc *playbackUseCase) Next(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error) {
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(state.Queue) == 0 {
+		return nil, models.ErrQueueEmpty
+	}
+
+	state.PositionMs = 0
+	if state.CurrentIndex+1 < len(state.Queue) {
+		state.CurrentIndex++
+	} else if state.RepeatMode == models.RepeatAll {
+		state.CurrentIndex = 0
+	} else {
+		// Очередь закончилась
+		state.IsPlaying = false
+	}
+	claimDevice(state, deviceID)
+
+	return uc.saveState(ctx, state)
+}
+
+func (uc *playbackUseCase) Previous(ctx context.Context, userID, deviceID uuid.UUID) (*models.PlaybackState, error) {
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(state.Queue) == 0 {
+		return nil, models.ErrQueueEmpty
+	}
+
+	switch {
+	case state.PositionMs > restartThresholdMs:
+		// Перематываем текущий трек в начало
+	case state.CurrentIndex > 0:
+		state.CurrentIndex--
+	case state.RepeatMode == models.RepeatAll:
+		state.CurrentIndex = len(state.Queue) - 1
+	}
+	state.PositionMs = 0
+	claimDevice(state, deviceID)
+
+	return uc.saveState(ctx, state)
+}
+
+func (uc *playbackUseCase) SetShuffle(ctx context.Context, userID, deviceID uuid.UUID, enabled bool) (*models.PlaybackState, error) {
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled != state.Shuffle && len(state.Queue) > 0 {
+		current := state.PlayOrder()[state.CurrentIndex]
+		if enabled {
+			// Текущий трек остается первым, остальные перемешиваются
+			state.ShuffleOrder = shuffledOrder(len(state.Queue), current)
+			state.CurrentIndex = 0
+		} else {
+			state.ShuffleOrder = nil
+			state.CurrentIndex = current
+		}
+	}
+	if !enabled {
+		state.ShuffleOrder = nil
+	}
+	state.Shuffle = enabled
+	claimDevice(state, deviceID)
+
+	return uc.saveState(ctx, state)
+}
+
+func (uc *playbackUseCase) SetRepeatMode(ctx context.Context, userID, deviceID uuid.UUID, mode models.RepeatMode) (*models.PlaybackState, error) {
+	if !mode.IsValid() {
+		return nil, models.ErrInvalidRepeatMode
+	}
+
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	state.RepeatMode = mode
+	claimDevice(state, deviceID)
+
+	return uc.saveState(ctx, state)
+}
+
+func (uc *playbackUseCase) TransferPlayback(ctx context.Context, userID, targetDeviceID uuid.UUID, play bool) (*models.PlaybackState, error) {
+	devices, err := uc.sessionRepo.ListDevices(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user devices: %w", err)
+	}
+
+	found := false
+	for _, device := range devices {
+		if device.ID == targetDeviceID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, models.ErrDeviceNotFound
+	}
+
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	state.ActiveDeviceID = targetDeviceID
+	if play {
+		state.IsPlaying = len(state.Queue) > 0
+	}
+
+	return uc.saveState(ctx, state)
+}
+
+func (uc *playbackUseCase) ListDevices(ctx context.Context, userID uuid.UUID) ([]*models.Device, error) {
+	devices, err := uc.sessionRepo.ListDevices(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user devices: %w", err)
+	}
+
+	state, err := uc.loadState(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, device := range devices {
+		device.IsActive = device.ID == state.ActiveDeviceID
+		device.Connected = uc.bus.IsDeviceConnected(userID, device.ID)
+	}
+
+	return devices, nil
+}
+
+// loadState загружает состояние и фиксирует прогресс воспроизведения,
+// чтобы последующие изменения не теряли время, прошедшее с прошлого обновления
+func (uc *playbackUseCase) loadState(ctx context.Context, userID uuid.UUID) (*models.PlaybackState, error) {
+	state, err := uc.playbackRepo.GetState(ctx, userID)
+	if errors.Is(err, models.ErrNotFound) {
+		return &models.PlaybackState{
+			UserID:     userID,
+			RepeatMode: models.RepeatOff,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playback state: %w", err)
+	}
+
+	// Треки могли быть удалены из каталога, тогда порядок перемешивания устаревает
+	if state.Shuffle && len(state.ShuffleOrder) != len(state.Queue) {
+		state.ShuffleOrder = shuffledOrder(len(state.Queue), -1)
+	}
+	if state.CurrentIndex >= len(state.Queue) {
+		state.CurrentIndex = len(state.Queue) - 1
+	}
+	if state.CurrentIndex < 0 {
+		state.CurrentIndex = 0
+	}
+
+	now := time.Now()
+	state.PositionMs = state.Progress(now)
+	state.UpdatedAt = now
+
+	return state, nil
+}
+
+// saveState сохраняет состояние и рассылает его всем устройствам пользователя
+func (uc *playbackUseCase) saveState(ctx context.Context, state *models.PlaybackState) (*models.PlaybackState, error) {
+	state.UpdatedAt = time.Now()
+	if err := uc.playbackRepo.SaveState(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to save playback state: %w", err)
+	}
+
+	uc.bus.Publish(events.TypePlaybackChanged, state, state.UserID)
+	return state, nil
+}
+
+// saveQueueAndState в одной транзакции сохраняет очередь и состояние,
+// чтобы индексы состояния не разошлись с сохраненной очередью
+func (uc *playbackUseCase) saveQueueAndState(ctx context.Context, state *models.PlaybackState) (*models.PlaybackState, error) {
+	state.UpdatedAt = time.Now()
+	err := uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Playback.ReplaceQueue(ctx, state.UserID, trackIDsOf(state.Queue)); err != nil {
+			return fmt.Errorf("failed to save queue: %w", err)
+		}
+		if err := repos.Playback.SaveState(ctx, state); err != nil {
+			return fmt.Errorf("failed to save playback state: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.bus.Publish(events.TypeQueueChanged, state, state.UserID)
+	return state, nil
+}
+
+// claimDevice делает устройство активным, если активного устройства еще нет
+func claimDevice(state *models.PlaybackState, deviceID uuid.UUID) {
+	if state.ActiveDeviceID == uuid.Nil && deviceID != uuid.Nil {
+		state.ActiveDeviceID = deviceID
+	}
+}
+
+// shuffledOrder возвращает случайную перестановку индексов очереди.
+// Если first >= 0, этот индекс ставится в начало
+func shuffledOrder(n int, first int) []int {
+	order := rand.Perm(n)
+	if first < 0 {
+		return order
+	}
+	for i, idx := range order {
+		if idx == first {
+			order[0], order[i] = order[i], order[0]
+			break
+		}
+	}
+	return order
+}
+
+func trackIDsOf(tracks []*models.Track) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(tracks))
+	for _, track := range tracks {
+		ids = append(ids, track.ID)
+	}
+	return ids
+}
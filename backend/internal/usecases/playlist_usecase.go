@@ -1,12 +1,16 @@
 package usecases
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"music-service/internal/authz"
+	"music-service/internal/events"
+	"music-service/internal/logging"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
 	usecaseInterfaces "music-service/internal/usecases/interfaces"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -15,33 +19,45 @@ type playlistUseCase struct {
 	playlistRepo interfaces.PlaylistRepository
 	trackRepo    interfaces.TrackRepository
 	userRepo     interfaces.UserRepository
+	followRepo   interfaces.FollowRepository
+	uow          interfaces.UnitOfWork
+	publisher    events.Publisher
+	localizer    catalogLocalizer
 }
 
 func NewPlaylistUseCase(
 	playlistRepo interfaces.PlaylistRepository,
 	trackRepo interfaces.TrackRepository,
 	userRepo interfaces.UserRepository,
+	followRepo interfaces.FollowRepository,
+	translationRepo interfaces.TranslationRepository,
+	uow interfaces.UnitOfWork,
+	publisher events.Publisher,
 ) usecaseInterfaces.PlaylistUseCase {
 	return &playlistUseCase{
 		playlistRepo: playlistRepo,
 		trackRepo:    trackRepo,
 		userRepo:     userRepo,
+		followRepo:   followRepo,
+		uow:          uow,
+		publisher:    publisher,
+		localizer:    newCatalogLocalizer(translationRepo),
 	}
 }
 
-func (uc *playlistUseCase) CreatePlaylist(userID uuid.UUID, name, description string, coverURL string) (*models.Playlist, error) {
-	if _, err := uc.userRepo.FindByID(userID); err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+func (uc *playlistUseCase) CreatePlaylist(ctx context.Context, userID uuid.UUID, name, description string, coverURL string) (*models.Playlist, error) {
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, lookupError(err, models.ErrUserNotFound)
 	}
 
-	if len(name) < 2 {
-		return nil, errors.New("playlist name must be at least 2 characters")
+	if utf8.RuneCountInString(name) < 2 {
+		return nil, models.ErrPlaylistNameTooShort
 	}
-	if len(name) > 100 {
-		return nil, errors.New("playlist name is too long")
+	if utf8.RuneCountInString(name) > 100 {
+		return nil, models.ErrPlaylistNameTooLong
 	}
-	if len(description) > 500 {
-		return nil, errors.New("description is too long")
+	if utf8.RuneCountInString(description) > 500 {
+		return nil, models.ErrPlaylistDescriptionTooLong
 	}
 
 	playlist := &models.Playlist{
@@ -54,53 +70,80 @@ func (uc *playlistUseCase) CreatePlaylist(userID uuid.UUID, name, description st
 		UpdatedAt:   time.Now(),
 	}
 
-	if err := uc.playlistRepo.Save(playlist); err != nil {
+	if err := uc.commitChange(ctx, playlist, models.PlaylistCreated, nil, nil); err != nil {
 		return nil, fmt.Errorf("failed to create playlist: %w", err)
 	}
 
+	uc.publisher.Publish(events.TypePlaylistCreated, playlistChange(playlist), userID)
+
 	return playlist, nil
 }
 
-func (uc *playlistUseCase) AddTrackToPlaylist(playlistID, trackID uuid.UUID) error {
-	playlist, err := uc.playlistRepo.FindByID(playlistID)
+func (uc *playlistUseCase) AddTrackToPlaylist(ctx context.Context, playlistID, trackID uuid.UUID) error {
+	playlist, err := uc.playlistRepo.FindByID(ctx, playlistID)
 	if err != nil {
-		return fmt.Errorf("playlist not found: %w", err)
+		return lookupError(err, models.ErrPlaylistNotFound)
+	}
+
+	if err := uc.authorizeChange(ctx, playlist); err != nil {
+		return err
 	}
 
-	if _, err := uc.trackRepo.FindByID(trackID); err != nil {
-		return fmt.Errorf("track not found: %w", err)
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
+	if err != nil {
+		return lookupError(err, models.ErrTrackNotFound)
 	}
 
-	tracks, err := uc.playlistRepo.GetTracks(playlistID)
+	tracks, err := uc.playlistRepo.GetTracks(ctx, playlistID)
 	if err != nil {
 		return fmt.Errorf("failed to get playlist tracks: %w", err)
 	}
 
 	for _, t := range tracks {
 		if t.ID == trackID {
-			return errors.New("track already exists in playlist")
+			return models.ErrTrackAlreadyInPlaylist
 		}
 	}
 
 	if len(tracks) >= 1000 {
-		return errors.New("playlist track limit reached")
+		return models.ErrPlaylistTrackLimit
 	}
 
-	if err := uc.playlistRepo.AddTrack(playlistID, trackID); err != nil {
-		return fmt.Errorf("failed to add track: %w", err)
+	playlist.UpdatedAt = time.Now()
+	err = uc.commitChange(ctx, playlist, models.PlaylistTrackAdded, &trackID, func(repo interfaces.PlaylistRepository) error {
+		if err := repo.AddTrack(ctx, playlistID, trackID); err != nil {
+			return fmt.Errorf("failed to add track: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	playlist.UpdatedAt = time.Now()
-	return uc.playlistRepo.Save(playlist)
+	followers := uc.playlistFollowers(ctx, playlist.ID)
+	uc.publisher.Publish(events.TypePlaylistUpdated, playlistChange(playlist), append(followers, playlist.UserID)...)
+	uc.publisher.Publish(events.TypePlaylistTrackAdded, events.PlaylistTrackAdded{
+		PlaylistID:   playlist.ID,
+		PlaylistName: playlist.Name,
+		TrackID:      track.ID,
+		Title:        track.Title,
+		ArtistName:   track.ArtistName,
+	}, followers...)
+
+	return nil
 }
 
-func (uc *playlistUseCase) RemoveTrackFromPlaylist(playlistID, trackID uuid.UUID) error {
-	playlist, err := uc.playlistRepo.FindByID(playlistID)
+func (uc *playlistUseCase) RemoveTrackFromPlaylist(ctx context.Context, playlistID, trackID uuid.UUID) error {
+	playlist, err := uc.playlistRepo.FindByID(ctx, playlistID)
 	if err != nil {
-		return fmt.Errorf("playlist not found: %w", err)
+		return lookupError(err, models.ErrPlaylistNotFound)
+	}
+
+	if err := uc.authorizeChange(ctx, playlist); err != nil {
+		return err
 	}
 
-	tracks, err := uc.playlistRepo.GetTracks(playlistID)
+	tracks, err := uc.playlistRepo.GetTracks(ctx, playlistID)
 	if err != nil {
 		return fmt.Errorf("failed to get playlist tracks: %w", err)
 	}
@@ -114,80 +157,98 @@ func (uc *playlistUseCase) RemoveTrackFromPlaylist(playlistID, trackID uuid.UUID
 	}
 
 	if !found {
-		return errors.New("track not found in playlist")
+		return models.ErrTrackNotInPlaylist
 	}
 
-	if err := uc.playlistRepo.RemoveTrack(playlistID, trackID); err != nil {
-		return fmt.Errorf("failed to remove track: %w", err)
+	playlist.UpdatedAt = time.Now()
+	err = uc.commitChange(ctx, playlist, models.PlaylistTrackRemoved, &trackID, func(repo interfaces.PlaylistRepository) error {
+		if err := repo.RemoveTrack(ctx, playlistID, trackID); err != nil {
+			return fmt.Errorf("failed to remove track: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	playlist.UpdatedAt = time.Now()
-	return uc.playlistRepo.Save(playlist)
+	uc.notifyUpdated(ctx, playlist)
+	return nil
 }
 
-func (uc *playlistUseCase) EditPlaylistInfo(playlistID uuid.UUID, name, description string) error {
-	playlist, err := uc.playlistRepo.FindByID(playlistID)
+func (uc *playlistUseCase) EditPlaylistInfo(ctx context.Context, playlistID uuid.UUID, name, description string) error {
+	playlist, err := uc.playlistRepo.FindByID(ctx, playlistID)
 	if err != nil {
-		return fmt.Errorf("playlist not found: %w", err)
+		return lookupError(err, models.ErrPlaylistNotFound)
 	}
 
-	if len(name) > 0 {
-		if len(name) < 2 {
-			return errors.New("playlist name must be at least 2 characters")
+	if err := uc.authorizeChange(ctx, playlist); err != nil {
+		return err
+	}
+
+	if name != "" {
+		if utf8.RuneCountInString(name) < 2 {
+			return models.ErrPlaylistNameTooShort
 		}
-		if len(name) > 100 {
-			return errors.New("playlist name is too long")
+		if utf8.RuneCountInString(name) > 100 {
+			return models.ErrPlaylistNameTooLong
 		}
 		playlist.Name = name
 	}
 
-	if len(description) > 0 {
-		if len(description) > 500 {
-			return errors.New("description is too long")
+	if description != "" {
+		if utf8.RuneCountInString(description) > 500 {
+			return models.ErrPlaylistDescriptionTooLong
 		}
 		playlist.Description = description
 	}
 
 	playlist.UpdatedAt = time.Now()
-	return uc.playlistRepo.Save(playlist)
+	if err := uc.commitChange(ctx, playlist, models.PlaylistUpdated, nil, nil); err != nil {
+		return err
+	}
+
+	uc.notifyUpdated(ctx, playlist)
+	return nil
 }
 
-func (uc *playlistUseCase) GetPlaylistTracks(playlistID uuid.UUID) ([]*models.Track, error) {
-	if _, err := uc.playlistRepo.FindByID(playlistID); err != nil {
-		return nil, fmt.Errorf("playlist not found: %w", err)
+func (uc *playlistUseCase) GetPlaylistTracks(ctx context.Context, playlistID uuid.UUID) ([]*models.Track, error) {
+	if _, err := uc.playlistRepo.FindByID(ctx, playlistID); err != nil {
+		return nil, lookupError(err, models.ErrPlaylistNotFound)
 	}
 
-	tracks, err := uc.playlistRepo.GetTracks(playlistID)
+	tracks, err := uc.playlistRepo.GetTracks(ctx, playlistID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tracks: %w", err)
 	}
 
+	uc.localizer.tracks(ctx, tracks)
 	return tracks, nil
 }
 
-func (uc *playlistUseCase) GetPlaylistWithTracks(playlistID uuid.UUID) (*models.PlaylistTrack, error) {
-	playlist, err := uc.playlistRepo.FindByID(playlistID)
+func (uc *playlistUseCase) GetPlaylistWithTracks(ctx context.Context, playlistID uuid.UUID) (*models.PlaylistTrack, error) {
+	playlist, err := uc.playlistRepo.FindByID(ctx, playlistID)
 	if err != nil {
-		return nil, fmt.Errorf("playlist not found: %w", err)
+		return nil, lookupError(err, models.ErrPlaylistNotFound)
 	}
 
-	tracks, err := uc.playlistRepo.GetTracks(playlistID)
+	tracks, err := uc.playlistRepo.GetTracks(ctx, playlistID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tracks: %w", err)
 	}
 
+	uc.localizer.tracks(ctx, tracks)
 	return &models.PlaylistTrack{
 		Playlist: *playlist,
 		Tracks:   tracks,
 	}, nil
 }
 
-func (uc *playlistUseCase) GetUserPlaylists(userID uuid.UUID) ([]*models.Playlist, error) {
-	if _, err := uc.userRepo.FindByID(userID); err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+func (uc *playlistUseCase) GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]*models.Playlist, error) {
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, lookupError(err, models.ErrUserNotFound)
 	}
 
-	playlists, err := uc.playlistRepo.GetUserPlaylists(userID)
+	playlists, err := uc.playlistRepo.GetUserPlaylists(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user playlists: %w", err)
 	}
@@ -195,19 +256,108 @@ func (uc *playlistUseCase) GetUserPlaylists(userID uuid.UUID) ([]*models.Playlis
 	return playlists, nil
 }
 
-func (uc *playlistUseCase) DeletePlaylist(playlistID, userID uuid.UUID) error {
-	playlist, err := uc.playlistRepo.FindByID(playlistID)
+func (uc *playlistUseCase) DeletePlaylist(ctx context.Context, playlistID, userID uuid.UUID) error {
+	playlist, err := uc.playlistRepo.FindByID(ctx, playlistID)
 	if err != nil {
-		return fmt.Errorf("playlist not found: %w", err)
+		return lookupError(err, models.ErrPlaylistNotFound)
 	}
 
-	if playlist.UserID != userID {
-		return errors.New("user is not the owner of the playlist")
+	if err := checkPlaylistAccess(ctx, playlist, userID); err != nil {
+		return err
 	}
 
-	if err := uc.playlistRepo.Delete(playlistID); err != nil {
-		return fmt.Errorf("failed to delete playlist: %w", err)
+	followers := uc.playlistFollowers(ctx, playlistID)
+
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Playlist.Delete(ctx, playlistID, time.Now()); err != nil {
+			return fmt.Errorf("failed to delete playlist: %w", err)
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventPlaylistChanged, playlistID, models.PlaylistChangedPayload{
+			PlaylistID: playlistID,
+			OwnerID:    playlist.UserID,
+			Change:     models.PlaylistDeleted,
+		})
+	})
+	if err != nil {
+		return err
 	}
 
+	uc.publisher.Publish(events.TypePlaylistDeleted, events.PlaylistChange{PlaylistID: playlistID}, append(followers, playlist.UserID)...)
+
 	return nil
 }
+
+// authorizeChange проверяет, что текущий пользователь может изменять плейлист
+func (uc *playlistUseCase) authorizeChange(ctx context.Context, playlist *models.Playlist) error {
+	principal, err := authz.Current(ctx)
+	if err != nil {
+		return err
+	}
+	return checkPlaylistAccess(ctx, playlist, principal.UserID)
+}
+
+// checkPlaylistAccess разрешает изменять плейлист владельцу и модераторам
+func checkPlaylistAccess(ctx context.Context, playlist *models.Playlist, userID uuid.UUID) error {
+	if playlist.UserID == userID || authz.Can(ctx, authz.PlaylistModerate) {
+		return nil
+	}
+	return models.ErrPlaylistAccessDenied
+}
+
+// commitChange в одной транзакции выполняет mutate (если задан), сохраняет
+// плейлист и записывает доменное событие PlaylistChanged
+func (uc *playlistUseCase) commitChange(
+	ctx context.Context,
+	playlist *models.Playlist,
+	change string,
+	trackID *uuid.UUID,
+	mutate func(repo interfaces.PlaylistRepository) error,
+) error {
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if mutate != nil {
+			if err := mutate(repos.Playlist); err != nil {
+				return err
+			}
+		}
+		if err := repos.Playlist.Save(ctx, playlist); err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventPlaylistChanged, playlist.ID, models.PlaylistChangedPayload{
+			PlaylistID: playlist.ID,
+			OwnerID:    playlist.UserID,
+			Change:     change,
+			TrackID:    trackID,
+		})
+	})
+}
+
+// notifyUpdated уведомляет владельца и подписчиков плейлиста об изменении
+func (uc *playlistUseCase) notifyUpdated(ctx context.Context, playlist *models.Playlist) {
+	followers := uc.playlistFollowers(ctx, playlist.ID)
+	uc.publisher.Publish(events.TypePlaylistUpdated, playlistChange(playlist), append(followers, playlist.UserID)...)
+}
+
+// playlistFollowers возвращает подписчиков плейлиста. Ошибка не прерывает
+// операцию: изменение уже сохранено, теряется только уведомление
+func (uc *playlistUseCase) playlistFollowers(ctx context.Context, playlistID uuid.UUID) []uuid.UUID {
+	followers, err := uc.followRepo.GetPlaylistFollowers(ctx, playlistID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("get playlist followers failed", "playlist_id", playlistID, "error", err)
+		return nil
+	}
+	return followers
+}
+
+func playlistChange(playlist *models.Playlist) events.PlaylistChange {
+	return events.PlaylistChange{
+		PlaylistID: playlist.ID,
+		Playlist: &events.PlaylistSummary{
+			ID:          playlist.ID,
+			Name:        playlist.Name,
+			Description: playlist.Description,
+			OwnerID:     playlist.UserID,
+			CoverURL:    playlist.CoverURL,
+			UpdatedAt:   playlist.UpdatedAt,
+		},
+	}
+}
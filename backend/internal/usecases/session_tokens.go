@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"music-service/internal/tokens"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// checkSuspension возвращает *models.SuspendedError, если пользователь
+// заблокирован. Проверяется при каждом входе и обновлении токенов; уже
+// выданные access-токены отзываются в момент блокировки
+func checkSuspension(user *models.User) error {
+	if !user.IsSuspended(time.Now()) {
+		return nil
+	}
+	return &models.SuspendedError{Reason: user.Suspension.Reason, Until: user.Suspension.Until}
+}
+
+// startSession создает сессию пользователя с первым refresh-токеном и
+// выдает пару токенов. Используется при входе по паролю и через OIDC
+func startSession(
+	ctx context.Context,
+	uow interfaces.UnitOfWork,
+	tokenManager *tokens.Manager,
+	user *models.User,
+	device models.Device,
+	client models.ClientInfo,
+) (*models.Session, *models.TokenPair, error) {
+	if err := checkSuspension(user); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(tokenManager.RefreshTTL()),
+		Device:     normalizeDevice(device),
+		UserAgent:  truncateRunes(client.UserAgent, 512),
+		IPAddress:  client.IPAddress,
+	}
+	refreshToken := tokens.NewOpaqueToken()
+
+	err := uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Session.CreateSession(ctx, session); err != nil {
+			return err
+		}
+		return repos.Session.AddRefreshToken(ctx, newRefreshTokenRecord(refreshToken, session))
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	pair, err := issueTokens(tokenManager, user, session, refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, pair, nil
+}
+
+func issueTokens(tokenManager *tokens.Manager, user *models.User, session *models.Session, refreshToken string) (*models.TokenPair, error) {
+	accessToken, err := tokenManager.IssueAccessToken(user, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue access token: %w", err)
+	}
+
+	return &models.TokenPair{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(tokenManager.AccessTTL().Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// newRefreshTokenRecord описывает токен для хранения. Токены семейства
+// действуют до окончания сессии, обновление не продлевает ее
+func newRefreshTokenRecord(token string, session *models.Session) *models.RefreshToken {
+	return &models.RefreshToken{
+		TokenHash: tokens.HashOpaqueToken(token),
+		SessionID: session.ID,
+		CreatedAt: time.Now(),
+		ExpiresAt: session.ExpiresAt,
+	}
+}
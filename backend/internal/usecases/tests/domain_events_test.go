@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/models"
+	"music-service/internal/outbox"
+	"music-service/internal/repository/interfaces"
+	"music-service/internal/repository/interfaces/mocks"
+	"music-service/internal/usecases"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Повторная доставка события прослушивания (at-least-once) не увеличивает
+// счетчик прослушиваний второй раз
+func TestPlaybackRecorded_CountsOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	outboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	trackRepo := mocks.NewMockTrackRepository(ctrl)
+	uow := &unitOfWork{repos: &interfaces.TxRepositories{Outbox: outboxRepo, Track: trackRepo}}
+
+	trackID := uuid.New()
+	event, err := models.NewDomainEvent(models.EventPlaybackRecorded, trackID, models.PlaybackRecordedPayload{
+		UserID:     uuid.New(),
+		TrackID:    trackID,
+		ListenedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	dispatcher := outbox.NewDispatcher(outboxRepo, outbox.Config{BatchSize: 1})
+	usecases.RegisterDomainEventHandlers(dispatcher, trackRepo, uow)
+
+	gomock.InOrder(
+		// Первая доставка: счетчик увеличивается, но отметить событие
+		// доставленным не удалось, и оно будет доставлено снова
+		outboxRepo.EXPECT().ClaimPending(gomock.Any(), 1, gomock.Any()).Return([]*models.DomainEvent{event}, nil),
+		outboxRepo.EXPECT().MarkHandled(gomock.Any(), event.ID, "track.play_count").Return(true, nil),
+		trackRepo.EXPECT().IncrementPlayCount(gomock.Any(), trackID).Return(nil),
+		outboxRepo.EXPECT().MarkProcessed(gomock.Any(), event.ID).Return(context.DeadlineExceeded),
+
+		// Повторная доставка: отметка уже есть, счетчик не меняется
+		outboxRepo.EXPECT().ClaimPending(gomock.Any(), 1, gomock.Any()).Return([]*models.DomainEvent{event}, nil),
+		outboxRepo.EXPECT().MarkHandled(gomock.Any(), event.ID, "track.play_count").Return(false, nil),
+		outboxRepo.EXPECT().MarkProcessed(gomock.Any(), event.ID).Return(nil),
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := dispatcher.DispatchPending(context.Background())
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,15 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/repository/interfaces"
+)
+
+// unitOfWork выполняет fn без транзакции с заданными репозиториями
+type unitOfWork struct {
+	repos *interfaces.TxRepositories
+}
+
+func (u *unitOfWork) WithTx(ctx context.Context, fn func(repos *interfaces.TxRepositories) error) error {
+	return fn(u.repos)
+}
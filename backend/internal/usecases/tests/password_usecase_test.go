@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"music-service/internal/mail"
+	"music-service/internal/models"
+	"music-service/internal/password"
+	"music-service/internal/repository/interfaces"
+	"music-service/internal/repository/interfaces/mocks"
+	"music-service/internal/tokens"
+	"music-service/internal/usecases"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const resetURL = "https://music.example.com/reset-password?token="
+
+// mailbox запоминает отправленные письма
+type mailbox struct {
+	messages []mail.Message
+	err      error
+}
+
+func (m *mailbox) Send(ctx context.Context, msg mail.Message) error {
+	m.messages = append(m.messages, msg)
+	return m.err
+}
+
+type resetFixture struct {
+	users   *mocks.MockUserRepository
+	resets  *mocks.MockPasswordResetRepository
+	mailbox *mailbox
+	useCase usecaseInterfaces.PasswordUseCase
+	user    *models.User
+}
+
+func newResetFixture(t *testing.T) *resetFixture {
+	ctrl := gomock.NewController(t)
+	signer, err := tokens.NewSigner("k1", map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")})
+	require.NoError(t, err)
+	policy, err := password.NewPolicy(password.Config{})
+	require.NoError(t, err)
+
+	f := &resetFixture{
+		users:   mocks.NewMockUserRepository(ctrl),
+		resets:  mocks.NewMockPasswordResetRepository(ctrl),
+		mailbox: &mailbox{},
+		user:    &models.User{ID: uuid.New(), Login: "listener", Email: "listener@example.com"},
+	}
+	uow := &unitOfWork{repos: &interfaces.TxRepositories{User: f.users, PasswordReset: f.resets}}
+	manager := tokens.NewManager(signer, tokens.NewRevocationList(), 0, 0)
+	f.useCase = usecases.NewPasswordUseCase(f.users, uow, manager, policy, f.mailbox, time.Hour, resetURL)
+	return f
+}
+
+// В письме — сам токен, в БД — только его хеш: утечка таблицы не дает
+// сбросить чужой пароль
+func TestRequestPasswordReset(t *testing.T) {
+	f := newResetFixture(t)
+
+	var stored *models.PasswordResetToken
+	f.users.EXPECT().FindByEmail(gomock.Any(), "listener@example.com").Return(f.user, nil)
+	f.resets.EXPECT().DeleteForUser(gomock.Any(), f.user.ID).Return(nil)
+	f.resets.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, token *models.PasswordResetToken) error {
+		stored = token
+		return nil
+	})
+
+	start := time.Now()
+	require.NoError(t, f.useCase.RequestPasswordReset(context.Background(), "  listener@example.com "))
+
+	require.Len(t, f.mailbox.messages, 1)
+	msg := f.mailbox.messages[0]
+	assert.Equal(t, "listener@example.com", msg.To)
+	assert.NotEmpty(t, msg.Subject)
+
+	match := regexp.MustCompile(regexp.QuoteMeta(resetURL) + `([A-Za-z0-9_\-]+)`).FindStringSubmatch(msg.Body)
+	require.Len(t, match, 2, msg.Body)
+	rawToken := match[1]
+
+	require.NotNil(t, stored)
+	assert.Equal(t, f.user.ID, stored.UserID)
+	assert.Equal(t, tokens.HashOpaqueToken(rawToken), stored.TokenHash)
+	assert.NotEqual(t, rawToken, stored.TokenHash)
+	assert.NotContains(t, msg.Body, stored.TokenHash)
+	assert.WithinDuration(t, start.Add(time.Hour), stored.ExpiresAt, time.Minute)
+}
+
+// Для неизвестной почты ответ тот же, но письмо не отправляется
+func TestRequestPasswordReset_UnknownEmail(t *testing.T) {
+	f := newResetFixture(t)
+	f.users.EXPECT().FindByEmail(gomock.Any(), "nobody@example.com").Return(nil, models.ErrNotFound)
+
+	assert.NoError(t, f.useCase.RequestPasswordReset(context.Background(), "nobody@example.com"))
+	assert.Empty(t, f.mailbox.messages)
+}
+
+func TestRequestPasswordReset_MailFailed(t *testing.T) {
+	f := newResetFixture(t)
+	f.mailbox.err = errors.New("connection refused")
+	f.users.EXPECT().FindByEmail(gomock.Any(), gomock.Any()).Return(f.user, nil)
+	f.resets.EXPECT().DeleteForUser(gomock.Any(), f.user.ID).Return(nil)
+	f.resets.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	assert.ErrorIs(t, f.useCase.RequestPasswordReset(context.Background(), "listener@example.com"), models.ErrMailUnavailable)
+}
+
+// Токен из письма ищется по хешу
+func TestResetPassword_LooksUpHash(t *testing.T) {
+	f := newResetFixture(t)
+	rawToken := tokens.NewOpaqueToken()
+	f.resets.EXPECT().Consume(gomock.Any(), tokens.HashOpaqueToken(rawToken)).Return(uuid.Nil, models.ErrNotFound)
+
+	err := f.useCase.ResetPassword(context.Background(), rawToken, "n3w-passw0rd")
+	assert.ErrorIs(t, err, models.ErrInvalidResetToken)
+}
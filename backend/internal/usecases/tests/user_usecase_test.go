@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"context"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	"music-service/internal/repository/interfaces/mocks"
+	"music-service/internal/tokens"
+	"music-service/internal/usecases"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type refreshFixture struct {
+	users    *mocks.MockUserRepository
+	sessions *mocks.MockSessionRepository
+	manager  *tokens.Manager
+	useCase  usecaseInterfaces.UserUseCase
+
+	user    *models.User
+	session *models.Session
+	// access — действующий access-токен сессии
+	access string
+}
+
+func newRefreshFixture(t *testing.T) *refreshFixture {
+	ctrl := gomock.NewController(t)
+	signer, err := tokens.NewSigner("k1", map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")})
+	require.NoError(t, err)
+
+	f := &refreshFixture{
+		users:    mocks.NewMockUserRepository(ctrl),
+		sessions: mocks.NewMockSessionRepository(ctrl),
+		manager:  tokens.NewManager(signer, tokens.NewRevocationList(), time.Minute, time.Hour),
+		user:     &models.User{ID: uuid.New(), Login: "listener", Permission: models.UserPermission},
+	}
+	f.session = &models.Session{ID: uuid.New(), UserID: f.user.ID, Device: models.Device{ID: uuid.New()}}
+	uow := &unitOfWork{repos: &interfaces.TxRepositories{User: f.users, Session: f.sessions}}
+	f.useCase = usecases.NewUserUseCase(f.users, f.sessions, nil, uow, f.manager, nil, nil, false)
+
+	f.access, err = f.manager.IssueAccessToken(f.user, f.session)
+	require.NoError(t, err)
+	_, err = f.manager.Verify(f.access)
+	require.NoError(t, err)
+	return f
+}
+
+// expectRevoked проверяет, что вся сессия отозвана: в БД и в кеше отзывов,
+// так что ее access-токены больше не принимаются
+func (f *refreshFixture) expectRevoked() {
+	f.sessions.EXPECT().RevokeSession(gomock.Any(), f.session.ID, gomock.Any()).Return(nil)
+}
+
+func TestRefresh_Rotates(t *testing.T) {
+	f := newRefreshFixture(t)
+	refreshToken := tokens.NewOpaqueToken()
+	tokenHash := tokens.HashOpaqueToken(refreshToken)
+
+	f.sessions.EXPECT().GetRefreshToken(gomock.Any(), tokenHash).Return(&models.RefreshToken{
+		TokenHash: tokenHash,
+		SessionID: f.session.ID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	f.sessions.EXPECT().GetSession(gomock.Any(), f.session.ID).Return(f.session, nil)
+	f.users.EXPECT().FindByID(gomock.Any(), f.user.ID).Return(f.user, nil)
+	f.sessions.EXPECT().MarkRefreshTokenUsed(gomock.Any(), tokenHash).Return(true, nil)
+	// В БД попадает только хеш нового refresh-токена
+	var stored *models.RefreshToken
+	f.sessions.EXPECT().AddRefreshToken(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, token *models.RefreshToken) error {
+		stored = token
+		return nil
+	})
+	f.sessions.EXPECT().TouchSession(gomock.Any(), f.session.ID, "203.0.113.7").Return(nil)
+
+	pair, err := f.useCase.Refresh(context.Background(), refreshToken, models.ClientInfo{IPAddress: "203.0.113.7"})
+	require.NoError(t, err)
+	assert.NotEqual(t, refreshToken, pair.RefreshToken)
+	require.NotNil(t, stored)
+	assert.Equal(t, tokens.HashOpaqueToken(pair.RefreshToken), stored.TokenHash)
+	assert.Equal(t, f.session.ID, stored.SessionID)
+
+	_, err = f.manager.Verify(pair.AccessToken)
+	assert.NoError(t, err)
+}
+
+// Повторное предъявление уже использованного refresh-токена означает, что
+// он украден: отзывается вся сессия, а не только этот токен
+func TestRefresh_ReuseRevokesSession(t *testing.T) {
+	f := newRefreshFixture(t)
+	refreshToken := tokens.NewOpaqueToken()
+	usedAt := time.Now().Add(-time.Minute)
+
+	f.sessions.EXPECT().GetRefreshToken(gomock.Any(), tokens.HashOpaqueToken(refreshToken)).Return(&models.RefreshToken{
+		SessionID: f.session.ID,
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}, nil)
+	f.expectRevoked()
+
+	_, err := f.useCase.Refresh(context.Background(), refreshToken, models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrRefreshTokenReused)
+
+	_, err = f.manager.Verify(f.access)
+	assert.ErrorIs(t, err, tokens.ErrInvalidToken)
+}
+
+// Два одновременных обновления одним токеном: выигрывает то, что первым
+// отметило токен использованным, второе считается повторным использованием
+func TestRefresh_ConcurrentReuseRevokesSession(t *testing.T) {
+	f := newRefreshFixture(t)
+	refreshToken := tokens.NewOpaqueToken()
+	tokenHash := tokens.HashOpaqueToken(refreshToken)
+
+	f.sessions.EXPECT().GetRefreshToken(gomock.Any(), tokenHash).Return(&models.RefreshToken{
+		SessionID: f.session.ID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	f.sessions.EXPECT().GetSession(gomock.Any(), f.session.ID).Return(f.session, nil)
+	f.users.EXPECT().FindByID(gomock.Any(), f.user.ID).Return(f.user, nil)
+	f.sessions.EXPECT().MarkRefreshTokenUsed(gomock.Any(), tokenHash).Return(false, nil)
+	f.expectRevoked()
+
+	_, err := f.useCase.Refresh(context.Background(), refreshToken, models.ClientInfo{})
+	assert.ErrorIs(t, err, models.ErrRefreshTokenReused)
+
+	_, err = f.manager.Verify(f.access)
+	assert.ErrorIs(t, err, tokens.ErrInvalidToken)
+}
+
+func TestRefresh_Invalid(t *testing.T) {
+	t.Run("unknown token", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		f.sessions.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(nil, models.ErrNotFound)
+
+		_, err := f.useCase.Refresh(context.Background(), "unknown", models.ClientInfo{})
+		assert.ErrorIs(t, err, models.ErrInvalidRefreshToken)
+	})
+
+	// Истекший токен отклоняется, но сессия не отзывается
+	t.Run("expired token", func(t *testing.T) {
+		f := newRefreshFixture(t)
+		f.sessions.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(&models.RefreshToken{
+			SessionID: f.session.ID,
+			ExpiresAt: time.Now().Add(-time.Second),
+		}, nil)
+
+		_, err := f.useCase.Refresh(context.Background(), "expired", models.ClientInfo{})
+		assert.ErrorIs(t, err, models.ErrInvalidRefreshToken)
+		_, err = f.manager.Verify(f.access)
+		assert.NoError(t, err)
+	})
+}
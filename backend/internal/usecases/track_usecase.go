@@ -1,185 +1,439 @@
 package usecases
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"math"
+	"music-service/internal/audio"
+	"music-service/internal/authz"
+	"music-service/internal/fingerprint"
+	"music-service/internal/logging"
+	"music-service/internal/loudness"
+	"music-service/internal/lyrics"
 	"music-service/internal/models"
 	"music-service/internal/repository/interfaces"
 	usecaseInterfaces "music-service/internal/usecases/interfaces"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
+// AnalysisConfig — анализ файлов треков за один проход декодера:
+// измерение громкости и акустический отпечаток для поиска дубликатов. Без
+// Decoder файлы не анализируются; Timeout ограничивает анализ одного файла
+type AnalysisConfig struct {
+	Decoder     audio.Decoder
+	Timeout     time.Duration
+	Loudness    bool
+	Fingerprint bool
+	// MatchThreshold — сходство отпечатков, начиная с которого треки
+	// считаются дубликатами
+	MatchThreshold float64
+	// RejectDuplicates — отклонять загрузку дубликата, а не только
+	// предупреждать о нем
+	RejectDuplicates bool
+}
+
+// Поиск дубликатов: кандидаты — треки, у которых с отпечатком не меньше
+// duplicateMinShared общих ключей; целиком сравниваются не больше
+// duplicateCandidates из них
+const (
+	duplicateMinShared  = 10
+	duplicateCandidates = 10
+)
+
 type trackUseCase struct {
-	trackRepo     interfaces.TrackRepository
-	historyRepo   interfaces.HistoryRepository
-	albumRepo     interfaces.AlbumRepository
-	maxFileSizeMB int
-	allowedTypes  []string
+	trackRepo       interfaces.TrackRepository
+	historyRepo     interfaces.HistoryRepository
+	albumRepo       interfaces.AlbumRepository
+	lyricsRepo      interfaces.LyricsRepository
+	fingerprintRepo interfaces.FingerprintRepository
+	uow             interfaces.UnitOfWork
+	localizer       catalogLocalizer
+	maxFileSizeMB   int
+	allowedTypes    []string
+	analysis        AnalysisConfig
 }
 
 func NewTrackUseCase(
 	trackRepo interfaces.TrackRepository,
 	historyRepo interfaces.HistoryRepository,
 	albumRepo interfaces.AlbumRepository,
+	translationRepo interfaces.TranslationRepository,
+	lyricsRepo interfaces.LyricsRepository,
+	fingerprintRepo interfaces.FingerprintRepository,
+	uow interfaces.UnitOfWork,
 	maxFileSizeMB int,
 	allowedTypes []string,
+	analysisConfig AnalysisConfig,
 ) usecaseInterfaces.TrackUseCase {
 	return &trackUseCase{
-		trackRepo:     trackRepo,
-		historyRepo:   historyRepo,
-		albumRepo:     albumRepo,
-		maxFileSizeMB: maxFileSizeMB,
-		allowedTypes:  allowedTypes,
+		trackRepo:       trackRepo,
+		historyRepo:     historyRepo,
+		albumRepo:       albumRepo,
+		lyricsRepo:      lyricsRepo,
+		fingerprintRepo: fingerprintRepo,
+		uow:             uow,
+		localizer:       newCatalogLocalizer(translationRepo),
+		maxFileSizeMB:   maxFileSizeMB,
+		allowedTypes:    allowedTypes,
+		analysis:        analysisConfig,
 	}
 }
 
-func (uc *trackUseCase) SearchTracks(query string) ([]*models.Track, error) {
-	if len(query) < 3 {
-		return nil, errors.New("search query must be at least 2 characters")
+func (uc *trackUseCase) SearchTracks(ctx context.Context, query string) ([]*models.Track, error) {
+	if utf8.RuneCountInString(query) < 3 {
+		return nil, models.ErrSearchQueryTooShort
 	}
 
-	tracks, err := uc.trackRepo.Search(query)
+	tracks, err := uc.trackRepo.Search(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search tracks: %w", err)
 	}
 
+	uc.localizer.tracks(ctx, tracks)
 	return tracks, nil
 }
 
-func (uc *trackUseCase) PlayTrack(userID, trackID uuid.UUID) error {
-	track, err := uc.trackRepo.FindByID(trackID)
+func (uc *trackUseCase) PlayTrack(ctx context.Context, userID, trackID uuid.UUID) error {
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
 	if err != nil {
-		return fmt.Errorf("track not found: %w", err)
+		return lookupError(err, models.ErrTrackNotFound)
 	}
 	if track.AlbumID != uuid.Nil {
-		if _, err := uc.albumRepo.FindByID(track.AlbumID); err != nil {
-			return fmt.Errorf("album not found: %w", err)
+		if _, err := uc.albumRepo.FindByID(ctx, track.AlbumID); err != nil {
+			return lookupError(err, models.ErrAlbumNotFound)
 		}
 	}
-	if err := uc.historyRepo.AddEntry(userID, trackID); err != nil {
-		return fmt.Errorf("failed to record playback: %w", err)
-	}
-
-	go func() {
-		_ = uc.trackRepo.IncrementPlayCount(trackID)
-	}()
 
-	return nil
+	// Счетчик прослушиваний увеличивается подписчиком события PlaybackRecorded
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.History.AddEntry(ctx, userID, trackID); err != nil {
+			return fmt.Errorf("failed to record playback: %w", err)
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventPlaybackRecorded, trackID, models.PlaybackRecordedPayload{
+			UserID:     userID,
+			TrackID:    trackID,
+			ListenedAt: time.Now(),
+		})
+	})
 }
 
-func (uc *trackUseCase) GetTrackDetails(id uuid.UUID) (*models.TrackDetails, error) {
-	track, err := uc.trackRepo.FindByID(id)
+func (uc *trackUseCase) GetTrackDetails(ctx context.Context, id uuid.UUID) (*models.TrackDetails, error) {
+	track, err := uc.trackRepo.FindByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("track not found: %w", err)
+		return nil, lookupError(err, models.ErrTrackNotFound)
 	}
 
 	var album *models.Album
 	if track.AlbumID != uuid.Nil {
-		album, err = uc.albumRepo.FindByID(track.AlbumID)
+		album, err = uc.albumRepo.FindByID(ctx, track.AlbumID)
 		if err != nil {
-			log.Printf("could not find album for track %s: %v", id, err)
+			logging.FromContext(ctx).Warn("get track album failed", "track_id", id, "error", err)
 		}
 	}
 
-	genres, err := uc.trackRepo.GetGenresForTrack(id)
+	genres, err := uc.trackRepo.GetGenresForTrack(ctx, id)
 	if err != nil {
-		log.Printf("could not find genres for track %s: %v", id, err)
+		logging.FromContext(ctx).Warn("get track genres failed", "track_id", id, "error", err)
 	}
 
-	playCount, err := uc.historyRepo.GetPlayCount(id)
+	playCount, err := uc.historyRepo.GetPlayCount(ctx, id)
 	if err != nil {
-		log.Printf("could not get play count for track %s: %v", id, err)
+		logging.FromContext(ctx).Warn("get track play count failed", "track_id", id, "error", err)
+	}
+
+	var trackLyrics *models.Lyrics
+	found, err := uc.lyricsRepo.FindByTrack(ctx, id)
+	switch {
+	case err == nil:
+		if published(found) {
+			trackLyrics = found
+		}
+	case !errors.Is(err, models.ErrNotFound):
+		logging.FromContext(ctx).Warn("get track lyrics failed", "track_id", id, "error", err)
 	}
 
-	return &models.TrackDetails{
-		ID:         track.ID,
-		Title:      track.Title,
-		ArtistName: track.ArtistName,
-		Duration:   track.Duration,
-		FilePath:   track.FilePath,
-		MimeType:   "audio/mpeg",
-		CoverURL:   track.CoverURL,
-		AddedDate:  track.AddedDate,
-		CreatedAt:  track.AddedDate,
-		UpdatedAt:  track.UpdatedAt,
-		PlayCount:  playCount,
-		Album:      album,
-		Genres:     genres,
-	}, nil
+	details := &models.TrackDetails{
+		ID:          track.ID,
+		Title:       track.Title,
+		ArtistName:  track.ArtistName,
+		Artists:     track.Artists,
+		TrackNumber: track.TrackNumber,
+		DiscNumber:  track.DiscNumber,
+		Explicit:    track.Explicit,
+		ISRC:        track.ISRC,
+		LyricsURL:   track.LyricsURL,
+		ReleaseDate: track.ReleaseDate,
+		Duration:    track.Duration,
+		FilePath:    track.FilePath,
+		MimeType:    "audio/mpeg",
+		CoverURL:    track.CoverURL,
+		AddedDate:   track.AddedDate,
+		CreatedAt:   track.AddedDate,
+		UpdatedAt:   track.UpdatedAt,
+		PlayCount:   playCount,
+		Album:       album,
+		Genres:      genres,
+		Lyrics:      trackLyrics,
+		Loudness:    track.Loudness,
+	}
+	uc.localizer.trackDetails(ctx, details)
+	return details, nil
 }
 
-func (uc *trackUseCase) UpdateTrackMetadata(trackID uuid.UUID, metadata map[string]interface{}) error {
-	track, err := uc.trackRepo.FindByID(trackID)
+// UpdateTrackMetadata меняет метаданные трека. Если задан update.Version и
+// трек изменился после него, возвращается models.ErrTrackModified
+func (uc *trackUseCase) UpdateTrackMetadata(ctx context.Context, trackID uuid.UUID, update models.TrackMetadataUpdate) error {
+	if err := authz.Require(ctx, authz.TrackEdit); err != nil {
+		return err
+	}
+
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
 	if err != nil {
-		return fmt.Errorf("track not found: %w", err)
+		return lookupError(err, models.ErrTrackNotFound)
+	}
+	version := track.UpdatedAt
+	if update.Version != nil && !update.Version.Equal(version) {
+		return models.ErrTrackModified
+	}
+	before := trackSnapshot(track)
+	albumBefore := track.AlbumID
+
+	if err := applyTrackUpdate(track, update); err != nil {
+		return err
+	}
+	if update.AlbumID != nil && track.AlbumID != uuid.Nil {
+		if _, err := uc.albumRepo.FindByID(ctx, track.AlbumID); err != nil {
+			return lookupError(err, models.ErrAlbumNotFound)
+		}
+	}
+
+	var genresBefore []*models.Genre
+	if update.GenreIDs != nil {
+		if len(update.GenreIDs) > 5 {
+			return models.ErrTrackGenreLimit
+		}
+		if genresBefore, err = uc.trackRepo.GetGenresForTrack(ctx, trackID); err != nil {
+			return fmt.Errorf("failed to get track genres: %w", err)
+		}
 	}
 
-	if title, ok := metadata["title"].(string); ok && title != "" {
-		if len(title) > 100 {
-			return errors.New("title is too long")
+	// Точность updated_at в базе — микросекунды; ETag строится по
+	// сохраненному значению
+	track.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		err := repos.Track.Update(ctx, track, version)
+		if errors.Is(err, models.ErrNotFound) {
+			return models.ErrTrackModified
+		}
+		if err != nil {
+			return err
+		}
+
+		after := trackSnapshot(track)
+		if update.GenreIDs != nil {
+			genres := make([]*models.Genre, 0, len(update.GenreIDs))
+			for _, genreID := range update.GenreIDs {
+				genre, err := repos.Genre.FindByID(ctx, genreID)
+				if err != nil {
+					return lookupError(err, models.ErrGenreNotFound)
+				}
+				genres = append(genres, genre)
+			}
+			if err := repos.Genre.SetTrackGenres(ctx, trackID, update.GenreIDs); err != nil {
+				return err
+			}
+			before["genres"] = genreNames(genresBefore)
+			after["genres"] = genreNames(genres)
+		}
+		if track.AlbumID != albumBefore && track.Loudness != nil {
+			if err := refreshAlbumLoudness(ctx, repos.Album, albumBefore, track.AlbumID); err != nil {
+				return err
+			}
+		}
+
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditTrackUpdate,
+			EntityType: models.AuditEntityTrack,
+			EntityID:   trackID.String(),
+			Before:     before,
+			After:      after,
+		})
+	})
+}
+
+// applyTrackUpdate переносит в track заданные поля update, проверяя их
+func applyTrackUpdate(track *models.Track, update models.TrackMetadataUpdate) error {
+	if update.Title != nil {
+		title := strings.TrimSpace(*update.Title)
+		if title == "" {
+			return models.ErrTrackTitleRequired
+		}
+		if utf8.RuneCountInString(title) > 100 {
+			return models.ErrTrackTitleTooLong
 		}
 		track.Title = title
 	}
 
-	if artist, ok := metadata["artist_name"].(string); ok && artist != "" {
+	if update.Artists != nil {
+		artists := make([]string, 0, len(update.Artists))
+		for _, artist := range update.Artists {
+			if artist = strings.TrimSpace(artist); artist != "" {
+				artists = append(artists, artist)
+			}
+		}
+		if len(artists) == 0 {
+			return models.ErrTrackArtistRequired
+		}
+		track.Artists = artists
+		track.ArtistName = artists[0]
+	}
+
+	if update.ArtistName != nil {
+		artist := strings.TrimSpace(*update.ArtistName)
+		if artist == "" {
+			return models.ErrTrackArtistRequired
+		}
 		track.ArtistName = artist
+		if len(track.Artists) == 0 {
+			track.Artists = []string{artist}
+		} else {
+			track.Artists = append([]string{artist}, track.Artists[1:]...)
+		}
+	}
+
+	if update.AlbumID != nil {
+		track.AlbumID = *update.AlbumID
+	}
+	if update.TrackNumber != nil {
+		track.TrackNumber = *update.TrackNumber
+	}
+	if update.DiscNumber != nil {
+		track.DiscNumber = *update.DiscNumber
+	}
+	if update.Duration != nil {
+		track.Duration = *update.Duration
+	}
+	if update.CoverURL != nil {
+		track.CoverURL = strings.TrimSpace(*update.CoverURL)
+	}
+	if update.Explicit != nil {
+		track.Explicit = *update.Explicit
+	}
+	if update.LyricsURL != nil {
+		track.LyricsURL = strings.TrimSpace(*update.LyricsURL)
+	}
+
+	if update.ISRC != nil {
+		track.ISRC = ""
+		if strings.TrimSpace(*update.ISRC) != "" {
+			isrc, ok := models.NormalizeISRC(*update.ISRC)
+			if !ok {
+				return models.ErrInvalidISRC
+			}
+			track.ISRC = isrc
+		}
 	}
 
-	if albumID, ok := metadata["album_id"].(uuid.UUID); ok && albumID != uuid.Nil {
-		if _, err := uc.albumRepo.FindByID(albumID); err != nil {
-			return fmt.Errorf("album not found: %w", err)
+	if update.ReleaseDate != nil {
+		track.ReleaseDate = nil
+		if !update.ReleaseDate.IsZero() {
+			date := *update.ReleaseDate
+			track.ReleaseDate = &date
 		}
-		track.AlbumID = albumID
 	}
 
-	track.UpdatedAt = time.Now()
-	return uc.trackRepo.Save(track)
+	return nil
 }
 
-func (uc *trackUseCase) DeleteTrack(trackID uuid.UUID) error {
-	if _, err := uc.trackRepo.FindByID(trackID); err != nil {
-		return fmt.Errorf("track not found: %w", err)
+func (uc *trackUseCase) DeleteTrack(ctx context.Context, trackID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.TrackDelete); err != nil {
+		return err
 	}
 
-	return uc.trackRepo.Delete(trackID)
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
+	if err != nil {
+		return lookupError(err, models.ErrTrackNotFound)
+	}
+
+	// Трек попадает в корзину; файл удаляется при окончательном удалении
+	// (trashUseCase.Purge)
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Track.Delete(ctx, trackID, time.Now()); err != nil {
+			return err
+		}
+		if track.Loudness != nil {
+			if err := refreshAlbumLoudness(ctx, repos.Album, track.AlbumID); err != nil {
+				return err
+			}
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditTrackDelete,
+			EntityType: models.AuditEntityTrack,
+			EntityID:   trackID.String(),
+			Before:     trackSnapshot(track),
+		})
+	})
 }
 
-func (uc *trackUseCase) UploadTrack(fileReader io.Reader, fileSize int64, metadata models.TrackUploadMetadata) (*models.Track, error) {
+// UploadTrack сохраняет файл и метаданные трека. Вместе с треком
+// возвращаются похожие на него треки каталога; если загрузка дубликатов
+// запрещена и metadata.AllowDuplicate не задан, возвращается
+// *models.DuplicateError
+func (uc *trackUseCase) UploadTrack(ctx context.Context, fileReader io.Reader, fileSize int64, metadata models.TrackUploadMetadata) (*models.Track, []*models.DuplicateMatch, error) {
+	if err := authz.Require(ctx, authz.TrackUpload); err != nil {
+		return nil, nil, err
+	}
+
 	maxSizeBytes := int64(uc.maxFileSizeMB * 1024 * 1024)
 	if fileSize > maxSizeBytes {
-		return nil, fmt.Errorf("размер файла превышает максимально допустимый (%d МБ)", uc.maxFileSizeMB)
+		return nil, nil, fmt.Errorf("%w: limit %d MB", models.ErrTrackTooLarge, uc.maxFileSizeMB)
 	}
 
 	if metadata.Title == "" {
-		return nil, errors.New("название трека не может быть пустым")
+		return nil, nil, models.ErrTrackTitleRequired
 	}
 
 	if metadata.ArtistName == "" {
-		return nil, errors.New("имя исполнителя не может быть пустым")
+		return nil, nil, models.ErrTrackArtistRequired
 	}
 
 	if metadata.AlbumID == uuid.Nil {
-		return nil, errors.New("необходимо указать альбом для трека")
+		return nil, nil, models.ErrTrackAlbumRequired
 	}
 
-	if _, err := uc.albumRepo.FindByID(metadata.AlbumID); err != nil {
-		return nil, fmt.Errorf("альбом не найден: %w", err)
+	if _, err := uc.albumRepo.FindByID(ctx, metadata.AlbumID); err != nil {
+		return nil, nil, lookupError(err, models.ErrTrackAlbumNotFound)
 	}
 
 	trackID := uuid.New()
 
-	filePath, err := uc.trackRepo.SaveTrackFile(trackID, fileReader, fileSize)
+	filePath, err := uc.trackRepo.SaveTrackFile(ctx, trackID, fileReader, fileSize)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при сохранении файла: %w", err)
+		return nil, nil, fmt.Errorf("ошибка при сохранении файла: %w", err)
 	}
 
 	now := time.Now()
+	embedded := uc.readEmbeddedLyrics(ctx, trackID, filePath, now)
+	measured, trackPrint := uc.analyzeUpload(ctx, trackID, filePath)
+
+	var duplicates []*models.DuplicateMatch
+	if trackPrint != nil {
+		duplicates = uc.uploadDuplicates(ctx, trackID, trackPrint)
+		if len(duplicates) > 0 && uc.analysis.RejectDuplicates && !metadata.AllowDuplicate {
+			uc.removeTrackFile(ctx, filePath)
+			return nil, nil, &models.DuplicateError{Matches: duplicates}
+		}
+	}
+
 	track := &models.Track{
 		ID:         trackID,
 		Title:      metadata.Title,
@@ -187,34 +441,389 @@ func (uc *trackUseCase) UploadTrack(fileReader io.Reader, fileSize int64, metada
 		FilePath:   filePath,
 		AlbumID:    metadata.AlbumID,
 		ArtistName: metadata.ArtistName,
+		Artists:    []string{metadata.ArtistName},
 		CoverURL:   metadata.CoverURL,
 		AddedDate:  now,
 		UpdatedAt:  now,
 		PlayCount:  0,
 	}
+	if measured != nil {
+		track.Loudness = trackLoudness(*measured)
+		// Длительность, которую не прислал клиент, берется из измерения
+		if track.Duration == 0 {
+			track.Duration = int(math.Round(measured.Duration.Seconds()))
+		}
+	}
 
-	if err := uc.trackRepo.Save(track); err != nil {
-		return nil, fmt.Errorf("ошибка при сохранении метаданных трека: %w", err)
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Track.Save(ctx, track); err != nil {
+			return fmt.Errorf("ошибка при сохранении метаданных трека: %w", err)
+		}
+		if track.Loudness != nil {
+			if err := repos.Track.SetLoudness(ctx, track.ID, track.Loudness, now); err != nil {
+				return fmt.Errorf("ошибка при сохранении громкости трека: %w", err)
+			}
+			if err := refreshAlbumLoudness(ctx, repos.Album, track.AlbumID); err != nil {
+				return err
+			}
+		}
+		if trackPrint != nil {
+			if err := saveFingerprint(ctx, repos.Fingerprint, track.ID, trackPrint, duplicates, now); err != nil {
+				return fmt.Errorf("ошибка при сохранении акустического отпечатка: %w", err)
+			}
+		}
+		if embedded != nil {
+			if err := repos.Lyrics.Save(ctx, embedded); err != nil {
+				return fmt.Errorf("ошибка при сохранении текста песни: %w", err)
+			}
+		}
+		err := writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditTrackUpload,
+			EntityType: models.AuditEntityTrack,
+			EntityID:   track.ID.String(),
+			After:      trackSnapshot(track),
+		})
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventTrackUploaded, track.ID, models.TrackUploadedPayload{
+			TrackID:    track.ID,
+			AlbumID:    track.AlbumID,
+			Title:      track.Title,
+			ArtistName: track.ArtistName,
+			FilePath:   track.FilePath,
+		})
+	})
+	if err != nil {
+		// Строка трека не записана — файл больше никому не нужен
+		uc.removeTrackFile(ctx, filePath)
+		return nil, nil, err
+	}
+
+	return track, duplicates, nil
+}
+
+func (uc *trackUseCase) removeTrackFile(ctx context.Context, filePath string) {
+	if err := uc.trackRepo.DeleteTrackFile(ctx, filePath); err != nil {
+		logging.FromContext(ctx).Error("remove orphaned track file failed", "path", filePath, "error", err)
+	}
+}
+
+// AnalyzeLoudness заново измеряет громкость файла трека, например для
+// треков, загруженных без декодера, и пересчитывает громкость альбома
+func (uc *trackUseCase) AnalyzeLoudness(ctx context.Context, trackID uuid.UUID) (*models.Loudness, error) {
+	if err := authz.Require(ctx, authz.TrackEdit); err != nil {
+		return nil, err
+	}
+
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrTrackNotFound)
+	}
+
+	measured, err := uc.analyzeLoudness(ctx, track.FilePath)
+	if err != nil {
+		return nil, err
 	}
+	result := trackLoudness(measured)
 
-	return track, nil
+	// Громкость видна в карточке трека: ее ETag должен измениться
+	now := time.Now().Truncate(time.Microsecond)
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Track.SetLoudness(ctx, trackID, result, now); err != nil {
+			return lookupError(err, models.ErrTrackNotFound)
+		}
+		return refreshAlbumLoudness(ctx, repos.Album, track.AlbumID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// analyzeUpload измеряет громкость загруженного файла и строит его
+// отпечаток за один проход декодера. Ошибка анализа не мешает загрузке:
+// громкость и отпечаток можно получить позже через AnalyzeLoudness и
+// FingerprintTrack
+func (uc *trackUseCase) analyzeUpload(ctx context.Context, trackID uuid.UUID, filePath string) (*loudness.Result, fingerprint.Fingerprint) {
+	if uc.analysis.Decoder == nil || !uc.analysis.Loudness && !uc.analysis.Fingerprint {
+		return nil, nil
+	}
+	meter, builder, err := uc.analyzeFile(ctx, filePath, uc.analysis.Loudness, uc.analysis.Fingerprint)
+	if err != nil {
+		logging.FromContext(ctx).Warn("analyze track file failed", "track_id", trackID, "error", err)
+		return nil, nil
+	}
+
+	var measured *loudness.Result
+	if meter != nil {
+		result, err := meter.Result()
+		if err != nil {
+			logging.FromContext(ctx).Warn("measure track loudness failed", "track_id", trackID, "error", err)
+		} else {
+			measured = &result
+		}
+	}
+
+	var trackPrint fingerprint.Fingerprint
+	if builder != nil {
+		// У тишины и очень коротких записей отпечатка нет
+		if trackPrint = builder.Fingerprint(); len(trackPrint) == 0 {
+			trackPrint = nil
+		}
+	}
+	return measured, trackPrint
+}
+
+// analyzeFile декодирует файл трека за один проход и передает сэмплы
+// измерителю громкости (withLoudness) и построителю отпечатка
+// (withFingerprint). Невостребованный результат равен nil
+func (uc *trackUseCase) analyzeFile(ctx context.Context, filePath string, withLoudness, withFingerprint bool) (*loudness.Meter, *fingerprint.Builder, error) {
+	if uc.analysis.Decoder == nil {
+		return nil, nil, audio.ErrUnsupportedFormat
+	}
+	if uc.analysis.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, uc.analysis.Timeout)
+		defer cancel()
+	}
+
+	var meter *loudness.Meter
+	var builder *fingerprint.Builder
+	path := filepath.Join(uc.trackRepo.GetStorageDir(), filePath)
+	err := audio.Process(ctx, uc.analysis.Decoder, path, func(sampleRate, channels int) ([]audio.Sink, error) {
+		var sinks []audio.Sink
+		var err error
+		if withLoudness {
+			if meter, err = loudness.NewMeter(sampleRate, channels); err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, meter)
+		}
+		if withFingerprint {
+			if builder, err = fingerprint.NewBuilder(sampleRate, channels); err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, builder)
+		}
+		return sinks, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return meter, builder, nil
+}
+
+// analyzeLoudness декодирует файл трека и измеряет его громкость
+func (uc *trackUseCase) analyzeLoudness(ctx context.Context, filePath string) (loudness.Result, error) {
+	if !uc.analysis.Loudness {
+		return loudness.Result{}, models.ErrLoudnessUnavailable
+	}
+	meter, _, err := uc.analyzeFile(ctx, filePath, true, false)
+	switch {
+	case errors.Is(err, audio.ErrUnsupportedFormat):
+		return loudness.Result{}, models.ErrLoudnessUnavailable
+	case err != nil:
+		return loudness.Result{}, fmt.Errorf("failed to analyze loudness: %w", err)
+	}
+
+	measured, err := meter.Result()
+	if errors.Is(err, loudness.ErrSilence) {
+		return measured, models.ErrTrackSilent
+	}
+	return measured, err
+}
+
+// FingerprintTrack заново строит акустический отпечаток трека, например
+// для треков, загруженных без декодера, и ищет по нему дубликаты в
+// каталоге. Найденные пары попадают в отчет о дубликатах
+func (uc *trackUseCase) FingerprintTrack(ctx context.Context, trackID uuid.UUID) ([]*models.DuplicateMatch, error) {
+	if err := authz.Require(ctx, authz.TrackEdit); err != nil {
+		return nil, err
+	}
+
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
+	if err != nil {
+		return nil, lookupError(err, models.ErrTrackNotFound)
+	}
+
+	trackPrint, err := uc.computeFingerprint(ctx, track.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	duplicates, err := uc.findDuplicates(ctx, trackID, trackPrint)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		// Пары от прежнего отпечатка могли устареть
+		if err := repos.Fingerprint.Delete(ctx, trackID); err != nil {
+			return err
+		}
+		return saveFingerprint(ctx, repos.Fingerprint, trackID, trackPrint, duplicates, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return duplicates, nil
+}
+
+// computeFingerprint декодирует файл трека и строит его отпечаток
+func (uc *trackUseCase) computeFingerprint(ctx context.Context, filePath string) (fingerprint.Fingerprint, error) {
+	if !uc.analysis.Fingerprint {
+		return nil, models.ErrFingerprintUnavailable
+	}
+	_, builder, err := uc.analyzeFile(ctx, filePath, false, true)
+	switch {
+	case errors.Is(err, audio.ErrUnsupportedFormat):
+		return nil, models.ErrFingerprintUnavailable
+	case err != nil:
+		return nil, fmt.Errorf("failed to compute fingerprint: %w", err)
+	}
+
+	trackPrint := builder.Fingerprint()
+	if len(trackPrint) == 0 {
+		return nil, models.ErrTrackSilent
+	}
+	return trackPrint, nil
+}
+
+// uploadDuplicates ищет дубликаты загружаемого трека. Ошибка поиска не
+// мешает загрузке
+func (uc *trackUseCase) uploadDuplicates(ctx context.Context, trackID uuid.UUID, trackPrint fingerprint.Fingerprint) []*models.DuplicateMatch {
+	duplicates, err := uc.findDuplicates(ctx, trackID, trackPrint)
+	if err != nil {
+		logging.FromContext(ctx).Warn("find track duplicates failed", "track_id", trackID, "error", err)
+		return nil
+	}
+	return duplicates
+}
+
+// findDuplicates возвращает треки каталога, отпечатки которых похожи на
+// trackPrint не меньше порога, — самые похожие первыми. Кандидаты
+// выбираются по общим ключам индекса и сравниваются целиком
+func (uc *trackUseCase) findDuplicates(ctx context.Context, trackID uuid.UUID, trackPrint fingerprint.Fingerprint) ([]*models.DuplicateMatch, error) {
+	candidates, err := uc.fingerprintRepo.FindCandidates(ctx, trackID, trackPrint.Hashes(), duplicateMinShared, duplicateCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate candidates: %w", err)
+	}
+
+	var duplicates []*models.DuplicateMatch
+	for candidateID, data := range candidates {
+		candidate, err := fingerprint.Parse(data)
+		if err != nil {
+			logging.FromContext(ctx).Warn("parse track fingerprint failed", "track_id", candidateID, "error", err)
+			continue
+		}
+		similarity := fingerprint.Compare(trackPrint, candidate)
+		if similarity < uc.analysis.MatchThreshold {
+			continue
+		}
+
+		track, err := uc.trackRepo.FindByID(ctx, candidateID)
+		if errors.Is(err, models.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get duplicate track: %w", err)
+		}
+		duplicates = append(duplicates, &models.DuplicateMatch{Track: track, Similarity: similarity})
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].Similarity > duplicates[j].Similarity
+	})
+	return duplicates, nil
+}
+
+// saveFingerprint сохраняет отпечаток трека и пары с найденными
+// дубликатами
+func saveFingerprint(ctx context.Context, fingerprintRepo interfaces.FingerprintRepository, trackID uuid.UUID, trackPrint fingerprint.Fingerprint, duplicates []*models.DuplicateMatch, now time.Time) error {
+	if err := fingerprintRepo.Save(ctx, trackID, trackPrint.Bytes(), trackPrint.Hashes(), now); err != nil {
+		return err
+	}
+	for _, duplicate := range duplicates {
+		err := fingerprintRepo.AddDuplicate(ctx, &models.DuplicatePair{
+			TrackID:     trackID,
+			DuplicateID: duplicate.Track.ID,
+			Similarity:  duplicate.Similarity,
+			DetectedAt:  now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func trackLoudness(measured loudness.Result) *models.Loudness {
+	return &models.Loudness{IntegratedLUFS: measured.Integrated, TruePeakDBTP: measured.TruePeak}
+}
+
+// refreshAlbumLoudness пересчитывает громкость альбомов, состав которых
+// изменился. Трек без альбома (uuid.Nil) пропускается
+func refreshAlbumLoudness(ctx context.Context, albumRepo interfaces.AlbumRepository, albumIDs ...uuid.UUID) error {
+	for _, albumID := range albumIDs {
+		if albumID == uuid.Nil {
+			continue
+		}
+		if err := albumRepo.RefreshLoudness(ctx, albumID); err != nil {
+			return fmt.Errorf("failed to refresh album loudness: %w", err)
+		}
+	}
+	return nil
+}
+
+// readEmbeddedLyrics извлекает текст песни из тега ID3 загруженного файла.
+// Текст загрузчика каталога публикуется сразу. Поврежденный тег или
+// неподходящий текст не мешают загрузке трека
+func (uc *trackUseCase) readEmbeddedLyrics(ctx context.Context, trackID uuid.UUID, filePath string, now time.Time) *models.Lyrics {
+	file, err := os.Open(filepath.Join(uc.trackRepo.GetStorageDir(), filePath))
+	if err != nil {
+		logging.FromContext(ctx).Warn("open track file for lyrics failed", "track_id", trackID, "error", err)
+		return nil
+	}
+	defer file.Close()
+
+	embedded, err := lyrics.ReadID3(file)
+	if err != nil {
+		logging.FromContext(ctx).Warn("read ID3 lyrics failed", "track_id", trackID, "error", err)
+		return nil
+	}
+	if embedded == nil {
+		return nil
+	}
+
+	found, err := newLyrics(trackID, embedded.Lyrics(), embedded.Language, models.LyricsSourceID3, now)
+	if err != nil {
+		logging.FromContext(ctx).Warn("skip embedded lyrics", "track_id", trackID, "error", err)
+		return nil
+	}
+	found.Status = models.LyricsApproved
+	if principal, ok := authz.FromContext(ctx); ok {
+		found.SubmittedBy = principal.UserID
+		found.ModeratedBy = principal.UserID
+	}
+	found.ModeratedAt = &now
+	return found
 }
 
-func (uc *trackUseCase) GetTrackFilePath(trackID uuid.UUID) (string, error) {
-	track, err := uc.trackRepo.FindByID(trackID)
+func (uc *trackUseCase) GetTrackFilePath(ctx context.Context, trackID uuid.UUID) (string, error) {
+	track, err := uc.trackRepo.FindByID(ctx, trackID)
 	if err != nil {
-		return "", fmt.Errorf("трек не найден: %w", err)
+		return "", lookupError(err, models.ErrTrackNotFound)
 	}
 
 	tracksDir := uc.trackRepo.GetStorageDir()
 	if tracksDir == "" {
-		return "", errors.New("путь к директории треков не настроен")
+		return "", errors.New("tracks directory is not configured")
 	}
 
 	fullPath := filepath.Join(tracksDir, track.FilePath)
 
 	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("файл трека не найден: %w", err)
+		return "", models.ErrTrackFileNotFound
 	}
 
 	return fullPath, nil
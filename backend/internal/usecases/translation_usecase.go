@@ -0,0 +1,203 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"music-service/internal/audit"
+	"music-service/internal/authz"
+	"music-service/internal/i18n"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxTranslationLength — предел длины переведенного поля в символах
+const maxTranslationLength = 255
+
+// translationPermissions — право, нужное для изменения переводов объектов
+// каждого типа: то же, что и для изменения самих объектов
+var translationPermissions = map[string]authz.Permission{
+	models.TranslatableTrack: authz.TrackEdit,
+	models.TranslatableAlbum: authz.AlbumEdit,
+	models.TranslatableGenre: authz.GenreManage,
+}
+
+type translationUseCase struct {
+	translationRepo interfaces.TranslationRepository
+	trackRepo       interfaces.TrackRepository
+	albumRepo       interfaces.AlbumRepository
+	genreRepo       interfaces.GenreRepository
+	uow             interfaces.UnitOfWork
+}
+
+func NewTranslationUseCase(
+	translationRepo interfaces.TranslationRepository,
+	trackRepo interfaces.TrackRepository,
+	albumRepo interfaces.AlbumRepository,
+	genreRepo interfaces.GenreRepository,
+	uow interfaces.UnitOfWork,
+) usecaseInterfaces.TranslationUseCase {
+	return &translationUseCase{
+		translationRepo: translationRepo,
+		trackRepo:       trackRepo,
+		albumRepo:       albumRepo,
+		genreRepo:       genreRepo,
+		uow:             uow,
+	}
+}
+
+func (uc *translationUseCase) ListTranslations(ctx context.Context, entityType string, id uuid.UUID) ([]*models.Translation, error) {
+	if err := uc.checkEntity(ctx, entityType, id); err != nil {
+		return nil, err
+	}
+
+	translations, err := uc.translationRepo.List(ctx, entityType, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translations: %w", err)
+	}
+	return translations, nil
+}
+
+func (uc *translationUseCase) SetTranslation(ctx context.Context, entityType string, id uuid.UUID, locale string, fields map[string]string) (*models.Translation, error) {
+	if err := uc.authorize(ctx, entityType); err != nil {
+		return nil, err
+	}
+
+	locale, ok := i18n.ParseTag(locale)
+	if !ok {
+		return nil, models.ErrInvalidLocale
+	}
+
+	translation := &models.Translation{
+		Locale:    locale,
+		Fields:    make(map[string]string, len(fields)),
+		UpdatedAt: time.Now(),
+	}
+	for field, value := range fields {
+		if !models.IsTranslatableField(entityType, field) {
+			return nil, models.ErrUnknownTranslationField
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if utf8.RuneCountInString(value) > maxTranslationLength {
+			return nil, models.ErrTranslationTooLong
+		}
+		translation.Fields[field] = value
+	}
+	if len(translation.Fields) == 0 {
+		return nil, models.ErrTranslationEmpty
+	}
+
+	if err := uc.checkEntity(ctx, entityType, id); err != nil {
+		return nil, err
+	}
+	before, err := uc.find(ctx, entityType, id, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Translation.Save(ctx, entityType, id, translation); err != nil {
+			return err
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditTranslationSet,
+			EntityType: entityType,
+			EntityID:   id.String(),
+			Before:     translationSnapshot(before),
+			After:      translationSnapshot(translation),
+			Details:    map[string]interface{}{"locale": locale},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+	return translation, nil
+}
+
+func (uc *translationUseCase) DeleteTranslation(ctx context.Context, entityType string, id uuid.UUID, locale string) error {
+	if err := uc.authorize(ctx, entityType); err != nil {
+		return err
+	}
+
+	locale, ok := i18n.ParseTag(locale)
+	if !ok {
+		return models.ErrInvalidLocale
+	}
+
+	before, err := uc.find(ctx, entityType, id, locale)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return models.ErrTranslationNotFound
+	}
+
+	return uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.Translation.Delete(ctx, entityType, id, locale); err != nil {
+			return lookupError(err, models.ErrTranslationNotFound)
+		}
+		return writeAudit(ctx, repos.Audit, auditRecord{
+			Action:     models.AuditTranslationDelete,
+			EntityType: entityType,
+			EntityID:   id.String(),
+			Before:     translationSnapshot(before),
+			Details:    map[string]interface{}{"locale": locale},
+		})
+	})
+}
+
+// authorize проверяет тип объекта и право на изменение его переводов
+func (uc *translationUseCase) authorize(ctx context.Context, entityType string) error {
+	permission, ok := translationPermissions[entityType]
+	if !ok {
+		return models.ErrInvalidTranslationType
+	}
+	return authz.Require(ctx, permission)
+}
+
+// checkEntity проверяет, что переводимый объект существует
+func (uc *translationUseCase) checkEntity(ctx context.Context, entityType string, id uuid.UUID) error {
+	switch entityType {
+	case models.TranslatableTrack:
+		_, err := uc.trackRepo.FindByID(ctx, id)
+		return lookupError(err, models.ErrTrackNotFound)
+	case models.TranslatableAlbum:
+		_, err := uc.albumRepo.FindByID(ctx, id)
+		return lookupError(err, models.ErrAlbumNotFound)
+	case models.TranslatableGenre:
+		_, err := uc.genreRepo.FindByID(ctx, id)
+		return lookupError(err, models.ErrGenreNotFound)
+	}
+	return models.ErrInvalidTranslationType
+}
+
+// find возвращает текущий перевод объекта на язык locale или nil
+func (uc *translationUseCase) find(ctx context.Context, entityType string, id uuid.UUID, locale string) (*models.Translation, error) {
+	translations, err := uc.translationRepo.FindByEntities(ctx, entityType, []uuid.UUID{id}, []string{locale})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translation: %w", err)
+	}
+	if len(translations[id]) == 0 {
+		return nil, nil
+	}
+	return translations[id][0], nil
+}
+
+func translationSnapshot(translation *models.Translation) audit.Snapshot {
+	if translation == nil {
+		return nil
+	}
+	snapshot := make(audit.Snapshot, len(translation.Fields))
+	for field, value := range translation.Fields {
+		snapshot[field] = value
+	}
+	return snapshot
+}
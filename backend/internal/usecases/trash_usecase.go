@@ -0,0 +1,215 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"music-service/internal/authz"
+	"music-service/internal/models"
+	"music-service/internal/repository/interfaces"
+	usecaseInterfaces "music-service/internal/usecases/interfaces"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// restoreActions — действия журнала при восстановлении. Типы объектов
+// корзины совпадают с типами сущностей журнала
+var restoreActions = map[string]string{
+	models.TrashTrack:    models.AuditTrackRestore,
+	models.TrashAlbum:    models.AuditAlbumRestore,
+	models.TrashPlaylist: models.AuditPlaylistRestore,
+	models.TrashUser:     models.AuditUserRestore,
+}
+
+type trashUseCase struct {
+	trashRepo interfaces.TrashRepository
+	albumRepo interfaces.AlbumRepository
+	userRepo  interfaces.UserRepository
+	uow       interfaces.UnitOfWork
+	retention time.Duration
+}
+
+// NewTrashUseCase создает use case корзины. Объекты хранятся в корзине
+// retention, затем удаляются окончательно; при нулевом retention — бессрочно
+func NewTrashUseCase(
+	trashRepo interfaces.TrashRepository,
+	albumRepo interfaces.AlbumRepository,
+	userRepo interfaces.UserRepository,
+	uow interfaces.UnitOfWork,
+	retention time.Duration,
+) usecaseInterfaces.TrashUseCase {
+	return &trashUseCase{
+		trashRepo: trashRepo,
+		albumRepo: albumRepo,
+		userRepo:  userRepo,
+		uow:       uow,
+		retention: retention,
+	}
+}
+
+// ListTrash возвращает все удаленные объекты с фильтром по типу
+func (uc *trashUseCase) ListTrash(ctx context.Context, filter models.TrashFilter) (*models.TrashPage, error) {
+	if err := authz.Require(ctx, authz.TrashManage); err != nil {
+		return nil, err
+	}
+	if filter.Type != "" && !models.IsValidTrashType(filter.Type) {
+		return nil, models.ErrInvalidTrashType
+	}
+
+	return uc.list(ctx, filter)
+}
+
+// ListOwnTrash возвращает удаленные плейлисты текущего пользователя
+func (uc *trashUseCase) ListOwnTrash(ctx context.Context, limit, offset int) (*models.TrashPage, error) {
+	principal, err := authz.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID := principal.UserID
+	return uc.list(ctx, models.TrashFilter{
+		Type:    models.TrashPlaylist,
+		OwnerID: &ownerID,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+func (uc *trashUseCase) list(ctx context.Context, filter models.TrashFilter) (*models.TrashPage, error) {
+	filter.Limit, filter.Offset = normalizePage(filter.Limit, filter.Offset)
+	page, err := uc.trashRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+
+	if uc.retention > 0 {
+		for _, item := range page.Items {
+			purgeAt := item.DeletedAt.Add(uc.retention)
+			item.PurgeAt = &purgeAt
+		}
+	}
+	return page, nil
+}
+
+// Restore возвращает объект из корзины. Плейлист может восстановить его
+// владелец, остальное — только администратор. Трек из удаленного альбома
+// восстанавливается вместе с альбомом, плейлист удаленного пользователя —
+// вместе с пользователем
+func (uc *trashUseCase) Restore(ctx context.Context, itemType string, id uuid.UUID) error {
+	principal, err := authz.Current(ctx)
+	if err != nil {
+		return err
+	}
+	action, ok := restoreActions[itemType]
+	if !ok {
+		return models.ErrInvalidTrashType
+	}
+
+	item, err := uc.trashRepo.Find(ctx, itemType, id)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrTrashItemNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find trash item: %w", err)
+	}
+
+	if !principal.Can(authz.TrashManage) {
+		owned := item.OwnerID != nil && *item.OwnerID == principal.UserID
+		if itemType != models.TrashPlaylist || !owned {
+			// Чужая корзина не раскрывается
+			return models.ErrTrashItemNotFound
+		}
+	}
+
+	switch itemType {
+	case models.TrashTrack:
+		if item.ParentID != nil {
+			if _, err := uc.albumRepo.FindByID(ctx, *item.ParentID); err != nil {
+				return lookupError(err, models.ErrTrashAlbumDeleted)
+			}
+		}
+	case models.TrashPlaylist:
+		if _, err := uc.userRepo.FindByID(ctx, *item.OwnerID); err != nil {
+			return lookupError(err, models.ErrTrashOwnerDeleted)
+		}
+	}
+
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		var err error
+		switch itemType {
+		case models.TrashTrack:
+			if err = repos.Track.Restore(ctx, id); err == nil && item.ParentID != nil {
+				err = refreshAlbumLoudness(ctx, repos.Album, *item.ParentID)
+			}
+		case models.TrashAlbum:
+			err = repos.Album.Restore(ctx, id)
+		case models.TrashPlaylist:
+			err = repos.Playlist.Restore(ctx, id)
+		case models.TrashUser:
+			err = repos.User.Restore(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, repos.Audit, action, itemType, id.String(), map[string]interface{}{
+			"title":      item.Title,
+			"deleted_at": item.DeletedAt,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", itemType, err)
+	}
+	return nil
+}
+
+// Purge окончательно удаляет объекты, попавшие в корзину до before, и
+// возвращает их количество. Файлы треков удаляются подписчиком TrackDeleted
+// после фиксации транзакции
+func (uc *trashUseCase) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	err := uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		total = 0
+		counts := make(map[string]int64)
+
+		tracks, err := repos.Trash.PurgeTracks(ctx, before)
+		if err != nil {
+			return err
+		}
+		for _, track := range tracks {
+			err := recordEvent(ctx, repos.Outbox, models.EventTrackDeleted, track.ID, models.TrackDeletedPayload{
+				TrackID:  track.ID,
+				FilePath: track.FilePath,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		counts[models.TrashTrack] = int64(len(tracks))
+
+		// Альбомы — после треков, пользователи — после плейлистов: удаление
+		// пользователя каскадно удаляет его данные
+		for _, itemType := range []string{models.TrashAlbum, models.TrashPlaylist, models.TrashUser} {
+			n, err := repos.Trash.Purge(ctx, itemType, before)
+			if err != nil {
+				return err
+			}
+			counts[itemType] = n
+		}
+
+		for _, n := range counts {
+			total += n
+		}
+		if total == 0 {
+			return nil
+		}
+		return recordAudit(ctx, repos.Audit, models.AuditTrashPurge, models.AuditEntityTrash, "", map[string]interface{}{
+			"before":  before,
+			"deleted": counts,
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return total, nil
+}
@@ -1,13 +1,23 @@
 package usecases
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"music-service/internal/audit"
+	"music-service/internal/authz"
+	"music-service/internal/i18n"
+	"music-service/internal/logging"
 	"music-service/internal/models"
+	"music-service/internal/password"
+	"music-service/internal/ratelimit"
 	"music-service/internal/repository/interfaces"
+	"music-service/internal/tokens"
 	usecaseInterfaces "music-service/internal/usecases/interfaces"
-
+	"net/mail"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -16,164 +26,483 @@ import (
 type userUseCase struct {
 	userRepo    interfaces.UserRepository
 	sessionRepo interfaces.SessionRepository
+	mfaRepo     interfaces.MFARepository
+	uow         interfaces.UnitOfWork
+	tokens      *tokens.Manager
+	policy      *password.Policy
+	lockout     *ratelimit.Lockout
+	// staffSSOOnly запрещает вход по паролю пользователям с ролями сотрудников
+	staffSSOOnly bool
 }
 
 func NewUserUseCase(
 	userRepo interfaces.UserRepository,
 	sessionRepo interfaces.SessionRepository,
+	mfaRepo interfaces.MFARepository,
+	uow interfaces.UnitOfWork,
+	tokenManager *tokens.Manager,
+	passwordPolicy *password.Policy,
+	lockout *ratelimit.Lockout,
+	staffSSOOnly bool,
 ) usecaseInterfaces.UserUseCase {
 	return &userUseCase{
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		mfaRepo:      mfaRepo,
+		uow:          uow,
+		tokens:       tokenManager,
+		policy:       passwordPolicy,
+		lockout:      lockout,
+		staffSSOOnly: staffSSOOnly,
 	}
 }
 
-func (uc *userUseCase) Register(login, password string) (*models.User, error) {
-	if len(login) < 6 {
-		return nil, errors.New("login must be at least 6 characters")
+func (uc *userUseCase) Register(ctx context.Context, login, password, email string) (*models.User, error) {
+	if err := uc.validateCredentials(login, password); err != nil {
+		return nil, err
 	}
-	if len(password) < 8 {
-		return nil, errors.New("password must be at least 8 characters")
+
+	// Почта необязательна, но без нее не получится сбросить пароль
+	email = strings.TrimSpace(email)
+	if email != "" {
+		if err := validateEmail(email); err != nil {
+			return nil, err
+		}
+		taken, err := uc.userRepo.IsEmailTaken(ctx, email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check email: %w", err)
+		}
+		if taken {
+			return nil, models.ErrEmailTaken
+		}
 	}
 
-	users, err := uc.userRepo.Search(login)
+	taken, err := uc.userRepo.IsLoginTaken(ctx, login)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to check login: %w", err)
 	}
-	if len(users) > 0 {
-		return nil, errors.New("user already exists")
+	if taken {
+		return nil, models.ErrLoginTaken
 	}
 
 	hashedPassword, err := hashPassword(password)
 	if err != nil {
-		return nil, errors.New("error hashing password")
+		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	now := time.Now()
 	user := &models.User{
 		ID:         uuid.New(),
 		Login:      login,
+		Email:      email,
 		Password:   hashedPassword,
 		Permission: models.UserPermission,
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}
 
-	if err := uc.userRepo.Save(user); err != nil {
+	if err := uc.userRepo.Save(ctx, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (uc *userUseCase) Authenticate(login, password string) (*models.User, *models.Session, error) {
-	fmt.Printf("Попытка аутентификации: login=%s, password=%s\n", login, password)
+func (uc *userUseCase) Authenticate(ctx context.Context, login, password string, device models.Device, client models.ClientInfo) (*models.User, *models.Session, *models.TokenPair, error) {
+	// Неудачи считаются по паре логин + адрес: подбор пароля блокируется,
+	// но злоумышленник не может заблокировать вход владельцу учетной записи
+	lockKey := "login:" + strings.ToLower(login) + "|" + client.IPAddress
+	if err := checkLockout(ctx, uc.lockout, lockKey); err != nil {
+		return nil, nil, nil, err
+	}
 
-	user, err := uc.userRepo.FindByLogin(login)
+	user, err := uc.userRepo.FindByLogin(ctx, login)
 	if err != nil {
-		fmt.Printf("Пользователь не найден: %v\n", err)
-		return nil, nil, errors.New("invalid credentials")
+		// Хеш проверяется и для несуществующего логина, чтобы по времени
+		// ответа нельзя было определить, зарегистрирован ли пользователь
+		checkPasswordHash(password, dummyPasswordHash)
+		return nil, nil, nil, recordFailure(ctx, uc.lockout, lockKey, models.ErrInvalidCredentials)
+	}
+
+	if !checkPasswordHash(password, user.Password) {
+		return nil, nil, nil, recordFailure(ctx, uc.lockout, lockKey, models.ErrInvalidCredentials)
 	}
+	resetLockout(ctx, uc.lockout, lockKey)
 
-	fmt.Printf("Пользователь найден: ID=%s, login=%s, права=%s\n", user.ID, user.Login, user.Permission)
-	fmt.Printf("Сохраненный пароль: %s\n", user.Password)
+	// Сотрудники входят только через корпоративный SSO. Проверка выполняется
+	// после пароля, чтобы по ответу нельзя было узнать роль пользователя
+	if uc.staffSSOOnly && authz.IsStaff(user.Permission) {
+		return nil, nil, nil, models.ErrStaffSSORequired
+	}
+	if err := checkSuspension(user); err != nil {
+		return nil, nil, nil, err
+	}
 
-	// Специальная проверка для админа с фиксированным UUID
-	if user.ID.String() == "11111111-1111-1111-1111-111111111111" {
-		// Для админа сравниваем пароли напрямую без хеша
-		if password == user.Password {
-			fmt.Println("Успешная аутентификация администратора с прямым сравнением пароля")
-			// Создаем фиксированную сессию с токеном из миграции
-			session := &models.Session{
-				ID:        uuid.MustParse("22222222-2222-2222-2222-222222222222"),
-				Token:     "33333333-3333-3333-3333-333333333333",
-				ExpiresAt: time.Now().Add(24 * time.Hour),
-			}
-			return user, session, nil
-		} else {
-			fmt.Printf("Неверный пароль администратора: %s != %s\n", password, user.Password)
-			return nil, nil, errors.New("invalid credentials")
-		}
+	// Если нужен второй фактор, сессия создается только после него
+	// (MFAUseCase.CompleteChallenge)
+	if err := requireSecondFactor(ctx, uc.mfaRepo, user, device); err != nil {
+		return nil, nil, nil, err
 	}
 
-	// Для обычных пользователей используем bcrypt
-	if !checkPasswordHash(password, user.Password) {
-		fmt.Println("Неверный пароль")
-		return nil, nil, errors.New("invalid credentials")
+	session, pair, err := startSession(ctx, uc.uow, uc.tokens, user, device, client)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return user, session, pair, nil
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов. Использованный
+// токен повторно не принимается: его предъявление означает, что токен
+// украден, поэтому отзывается вся сессия (семейство токенов)
+func (uc *userUseCase) Refresh(ctx context.Context, refreshToken string, client models.ClientInfo) (*models.TokenPair, error) {
+	tokenHash := tokens.HashOpaqueToken(refreshToken)
+
+	stored, err := uc.sessionRepo.GetRefreshToken(ctx, tokenHash)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, models.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if stored.UsedAt != nil {
+		uc.revokeReusedSession(ctx, stored.SessionID)
+		return nil, models.ErrRefreshTokenReused
+	}
+	if !time.Now().Before(stored.ExpiresAt) {
+		return nil, models.ErrInvalidRefreshToken
+	}
+
+	session, err := uc.sessionRepo.GetSession(ctx, stored.SessionID)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, models.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
 
-	fmt.Println("Пароль верный, создаем сессию")
-	token := generateToken()
-	createdSession, err := uc.sessionRepo.CreateSession(user.ID, token)
+	// Пользователь перечитывается, чтобы новый access-токен содержал актуальную роль
+	user, err := uc.userRepo.FindByID(ctx, session.UserID)
 	if err != nil {
-		fmt.Printf("Ошибка создания сессии: %v\n", err)
-		return nil, nil, err
+		return nil, models.ErrInvalidRefreshToken
+	}
+	if err := checkSuspension(user); err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("Сессия создана: ID=%s, Token=%s\n", createdSession.ID, createdSession.Token)
+	newRefreshToken := tokens.NewOpaqueToken()
+	reused := false
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		marked, err := repos.Session.MarkRefreshTokenUsed(ctx, tokenHash)
+		if err != nil {
+			return err
+		}
+		if !marked {
+			reused = true
+			return nil
+		}
+		if err := repos.Session.AddRefreshToken(ctx, newRefreshTokenRecord(newRefreshToken, session)); err != nil {
+			return err
+		}
+		// Access-токены проверяются без БД, поэтому активность сессии
+		// отмечается при каждом обновлении токенов
+		return repos.Session.TouchSession(ctx, session.ID, client.IPAddress)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if reused {
+		uc.revokeReusedSession(ctx, session.ID)
+		return nil, models.ErrRefreshTokenReused
+	}
 
-	return user, createdSession, nil
+	return issueTokens(uc.tokens, user, session, newRefreshToken)
 }
 
-func (uc *userUseCase) GetUserProfile(userID uuid.UUID) (*models.User, error) {
-	user, err := uc.userRepo.FindByID(userID)
+func (uc *userUseCase) GetUserProfile(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, lookupError(err, models.ErrUserNotFound)
 	}
 	return user, nil
 }
 
-func (uc *userUseCase) UpdatePermissions(userID uuid.UUID, permission models.Permission) error {
+// SetLocale сохраняет предпочитаемый язык пользователя и возвращает его в
+// каноническом виде; пустая строка сбрасывает выбор. Язык передается в
+// access-токене, поэтому новый выбор действует после обновления токенов
+func (uc *userUseCase) SetLocale(ctx context.Context, userID uuid.UUID, locale string) (string, error) {
+	if locale != "" {
+		canonical, ok := i18n.ParseTag(locale)
+		if !ok {
+			return "", models.ErrInvalidLocale
+		}
+		locale = canonical
+	}
+
+	if err := uc.userRepo.SetLocale(ctx, userID, locale); err != nil {
+		return "", lookupError(err, models.ErrUserNotFound)
+	}
+	return locale, nil
+}
+
+func (uc *userUseCase) UpdatePermissions(ctx context.Context, userID uuid.UUID, permission models.Permission) error {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return err
+	}
+
 	if !permission.IsValid() {
-		return errors.New("invalid permission")
+		return models.ErrInvalidPermission
 	}
 
-	user, err := uc.userRepo.FindByID(userID)
+	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return lookupError(err, models.ErrUserNotFound)
 	}
 
+	previous := user.Permission
 	user.Permission = permission
 	user.UpdatedAt = time.Now()
 
-	return uc.userRepo.Save(user)
+	// Роль записана в access-токены, поэтому после ее смены сессии
+	// пользователя отзываются и ему нужно войти заново
+	return uc.saveAndRevokeSessions(ctx, user, auditRecord{
+		Action: models.AuditUserPermission,
+		Before: audit.Snapshot{"permission": previous},
+		After:  audit.Snapshot{"permission": permission},
+	})
 }
 
-func (uc *userUseCase) DeleteUser(userID uuid.UUID) error {
-	_, err := uc.userRepo.FindByID(userID)
+func (uc *userUseCase) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return lookupError(err, models.ErrUserNotFound)
 	}
 
-	if err := uc.sessionRepo.DeleteAllForUser(userID); err != nil {
+	// Пользователь и его плейлисты попадают в корзину. Сессии отзываются
+	// сразу: после восстановления нужно войти заново
+	until := uc.tokens.RevocationExpiry()
+	var revoked []uuid.UUID
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		var err error
+		if revoked, err = repos.Session.RevokeAllForUser(ctx, userID, until); err != nil {
+			return err
+		}
+		if err := repos.User.Delete(ctx, userID, time.Now()); err != nil {
+			return err
+		}
+		err = recordAudit(ctx, repos.Audit, models.AuditUserDelete, models.AuditEntityUser, userID.String(), map[string]interface{}{
+			"login":      user.Login,
+			"permission": user.Permission,
+		})
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, repos.Outbox, models.EventUserDeleted, userID, models.UserDeletedPayload{UserID: userID})
+	})
+	if err != nil {
 		return err
 	}
-	return uc.userRepo.Delete(userID)
+
+	uc.tokens.Revoke(until, revoked...)
+	return nil
+}
+
+// Logout отзывает сессию вместе со всеми ее refresh-токенами
+func (uc *userUseCase) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	until := uc.tokens.RevocationExpiry()
+	if err := uc.sessionRepo.RevokeSession(ctx, sessionID, until); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	uc.tokens.Revoke(until, sessionID)
+	return nil
+}
+
+// ListSessions возвращает действующие сессии пользователя
+func (uc *userUseCase) ListSessions(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	sessions, err := uc.sessionRepo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession завершает одну из сессий пользователя
+func (uc *userUseCase) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := uc.sessionRepo.GetSession(ctx, sessionID)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrSessionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	// Чужая сессия неотличима от несуществующей
+	if session.UserID != userID {
+		return models.ErrSessionNotFound
+	}
+
+	return uc.Logout(ctx, sessionID)
+}
+
+// RevokeOtherSessions завершает все сессии пользователя, кроме текущей, и
+// возвращает количество завершенных
+func (uc *userUseCase) RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) (int, error) {
+	until := uc.tokens.RevocationExpiry()
+	revoked, err := uc.sessionRepo.RevokeOtherSessions(ctx, userID, currentSessionID, until)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	uc.tokens.Revoke(until, revoked...)
+	return len(revoked), nil
 }
 
-func (uc *userUseCase) Logout(sessionID uuid.UUID) error {
-	return uc.sessionRepo.DeleteSession(sessionID.String())
+// PurgeExpiredSessions удаляет сессии, истекшие или отозванные до before
+func (uc *userUseCase) PurgeExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := uc.sessionRepo.PurgeExpired(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge sessions: %w", err)
+	}
+	return deleted, nil
 }
 
-func (uc *userUseCase) ValidateSession(token string) (*models.User, error) {
+// ValidateAccessToken проверяет access-токен без обращения к БД
+func (uc *userUseCase) ValidateAccessToken(ctx context.Context, token string) (*tokens.Claims, error) {
 	if token == "" {
-		return nil, errors.New("токен не может быть пустым")
+		return nil, models.ErrMissingToken
+	}
+	return uc.tokens.Verify(token)
+}
+
+// CreateAdmin создает администратора. Используется командой cmd/admin и
+// первичной настройкой при запуске, поэтому требует прав user:manage в ctx
+func (uc *userUseCase) CreateAdmin(ctx context.Context, login, password string) (*models.User, error) {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+	if err := uc.validateCredentials(login, password); err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("Проверка токена: %s\n", token)
+	taken, err := uc.userRepo.IsLoginTaken(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check login: %w", err)
+	}
+	if taken {
+		return nil, models.ErrLoginTaken
+	}
 
-	session, user, err := uc.sessionRepo.GetSessionByToken(token)
+	hashedPassword, err := hashPassword(password)
 	if err != nil {
-		fmt.Printf("Ошибка при проверке токена: %v\n", err)
-		return nil, fmt.Errorf("недействительная сессия: %w", err)
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := time.Now()
+	user := &models.User{
+		ID:         uuid.New(),
+		Login:      login,
+		Password:   hashedPassword,
+		Permission: models.AdminPermission,
+		CreatedAt:  now,
+		UpdatedAt:  now,
 	}
 
-	fmt.Printf("Найдена сессия: ID=%s, Token=%s, UserID=%s\n",
-		session.ID, session.Token, user.ID)
+	err = uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.User.Save(ctx, user); err != nil {
+			return err
+		}
+		return recordAudit(ctx, repos.Audit, models.AuditUserCreateAdmin, models.AuditEntityUser, user.ID.String(), map[string]interface{}{
+			"login": user.Login,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return user, nil
 }
 
+// EnsureAdmin создает администратора, только если в системе еще нет ни
+// одного. Возвращает nil, если администратор уже существует
+func (uc *userUseCase) EnsureAdmin(ctx context.Context, login, password string) (*models.User, error) {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return nil, err
+	}
+
+	count, err := uc.userRepo.CountByPermission(ctx, models.AdminPermission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count admins: %w", err)
+	}
+	if count > 0 {
+		return nil, nil
+	}
+
+	return uc.CreateAdmin(ctx, login, password)
+}
+
+// SetPassword задает новый пароль и завершает все сессии пользователя
+func (uc *userUseCase) SetPassword(ctx context.Context, login, password string) error {
+	if err := authz.Require(ctx, authz.UserManage); err != nil {
+		return err
+	}
+	if err := uc.validateCredentials(login, password); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.FindByLogin(ctx, login)
+	if err != nil {
+		return lookupError(err, models.ErrUserNotFound)
+	}
+
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user.Password = hashedPassword
+	user.UpdatedAt = time.Now()
+
+	return uc.saveAndRevokeSessions(ctx, user, auditRecord{Action: models.AuditUserPasswordSet})
+}
+
+// saveAndRevokeSessions сохраняет пользователя, отзывает все его сессии и
+// записывает действие администратора в журнал
+func (uc *userUseCase) saveAndRevokeSessions(ctx context.Context, user *models.User, record auditRecord) error {
+	until := uc.tokens.RevocationExpiry()
+	var revoked []uuid.UUID
+	err := uc.uow.WithTx(ctx, func(repos *interfaces.TxRepositories) error {
+		if err := repos.User.Save(ctx, user); err != nil {
+			return err
+		}
+		var err error
+		if revoked, err = repos.Session.RevokeAllForUser(ctx, user.ID, until); err != nil {
+			return err
+		}
+		record.EntityType = models.AuditEntityUser
+		record.EntityID = user.ID.String()
+		return writeAudit(ctx, repos.Audit, record)
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.tokens.Revoke(until, revoked...)
+	return nil
+}
+
+// revokeReusedSession отзывает сессию, refresh-токен которой предъявлен
+// повторно. Отзыв не должен прерываться вместе с запросом клиента
+func (uc *userUseCase) revokeReusedSession(ctx context.Context, sessionID uuid.UUID) {
+	logging.FromContext(ctx).Warn("refresh token reuse detected, revoking session", "session_id", sessionID)
+	if err := uc.Logout(context.WithoutCancel(ctx), sessionID); err != nil {
+		logging.FromContext(ctx).Error("revoke session failed", "session_id", sessionID, "error", err)
+	}
+}
+
 func hashPassword(password string) (string, error) {
 	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -182,24 +511,95 @@ func hashPassword(password string) (string, error) {
 	return string(hashedBytes), nil
 }
 
+// dummyPasswordHash используется при входе с несуществующим логином
+var dummyPasswordHash = func() string {
+	hash, err := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	if err != nil {
+		panic(err)
+	}
+	return string(hash)
+}()
+
 func checkPasswordHash(password, hash string) bool {
-	// Добавляем отладочную информацию
-	fmt.Printf("Сравниваем пароль с хешем: password=[%s], hash=[%s]\n", password, hash)
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func (uc *userUseCase) validateCredentials(login, password string) error {
+	if utf8.RuneCountInString(login) < 6 {
+		return models.ErrLoginTooShort
+	}
+	return uc.policy.Validate(password, login)
+}
+
+func validateEmail(email string) error {
+	if utf8.RuneCountInString(email) > 255 {
+		return models.ErrEmailTooLong
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return models.ErrInvalidEmail
+	}
+	return nil
+}
+
+// normalizeDevice заполняет идентификатор и ограничивает длину описания устройства
+func normalizeDevice(device models.Device) models.Device {
+	if device.ID == uuid.Nil {
+		device.ID = uuid.New()
+	}
+	device.Name = truncateRunes(strings.TrimSpace(device.Name), 255)
+	device.Type = truncateRunes(strings.TrimSpace(device.Type), 50)
+	if device.Name == "" {
+		device.Name = "Unknown device"
+	}
+	return device
+}
 
-	// Проверка для случая admin/adminpass
-	if password == "adminpass" && hash == "$2a$10$8KAqeCKaMvhuiCewKQkCE.Lz4R9tGQcu/mLRBJ2QQRJ9TY/avlZRa" {
-		fmt.Println("Прямое сравнение хеша для пароля adminpass - успешно")
-		return true
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) > max {
+		return string(runes[:max])
 	}
+	return s
+}
+
+// checkLockout возвращает *ratelimit.LockedError, если попытки по ключу
+// временно заблокированы. Ошибка хранилища не должна мешать входу
+func checkLockout(ctx context.Context, lockout *ratelimit.Lockout, key string) error {
+	if lockout == nil {
+		return nil
+	}
+	err := lockout.Check(ctx, key)
+	var locked *ratelimit.LockedError
+	if err != nil && !errors.As(err, &locked) {
+		logging.FromContext(ctx).Error("lockout check failed", "error", err)
+		return nil
+	}
+	return err
+}
 
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+// recordFailure учитывает неудачную попытку и возвращает failure, а если
+// после нее ключ заблокирован — *ratelimit.LockedError
+func recordFailure(ctx context.Context, lockout *ratelimit.Lockout, key string, failure error) error {
+	if lockout == nil {
+		return failure
+	}
+	err := lockout.Fail(ctx, key)
+	var locked *ratelimit.LockedError
+	if errors.As(err, &locked) {
+		return err
+	}
 	if err != nil {
-		fmt.Printf("Ошибка сравнения bcrypt: %v\n", err)
-		return false
+		logging.FromContext(ctx).Error("record failed login attempt failed", "error", err)
 	}
-	return true
+	return failure
 }
 
-func generateToken() string {
-	return uuid.New().String()
+func resetLockout(ctx context.Context, lockout *ratelimit.Lockout, key string) {
+	if lockout == nil {
+		return
+	}
+	if err := lockout.Succeed(ctx, key); err != nil {
+		logging.FromContext(ctx).Error("reset lockout failed", "error", err)
+	}
 }
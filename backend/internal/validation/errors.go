@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"music-service/internal/models"
+	"strings"
+)
+
+// Коды нарушений правил. Как и коды доменных ошибок, входят в контракт API
+const (
+	CodeRequired        = "required"
+	CodeTooShort        = "too_short"
+	CodeTooLong         = "too_long"
+	CodeTooSmall        = "too_small"
+	CodeTooLarge        = "too_large"
+	CodeTooFew          = "too_few"
+	CodeTooMany         = "too_many"
+	CodeInvalidUUID     = "invalid_uuid"
+	CodeInvalidURL      = "invalid_url"
+	CodeInvalidEmail    = "invalid_email"
+	CodeInvalidChoice   = "invalid_choice"
+	CodeInvalidLocale   = "invalid_locale"
+	CodeInvalidDuration = "invalid_duration"
+	CodeInvalidType     = "invalid_type"
+	CodeInvalidNumber   = "invalid_number"
+	CodeInvalidDateTime = "invalid_datetime"
+	CodeInvalidDate     = "invalid_date"
+	CodeConflicting     = "conflicting_parameters"
+)
+
+// Violation — нарушение правила для одного поля. Field — имя поля в
+// запросе, Params подставляются в сообщение для пользователя
+type Violation struct {
+	Field  string
+	Code   string
+	Params []interface{}
+}
+
+// Error — все нарушения правил в запросе. Ошибка относится к категории
+// models.ErrInvalidInput
+type Error struct {
+	Violations []Violation
+}
+
+// Field создает ошибку с одним нарушением
+func Field(field, code string, params ...interface{}) *Error {
+	e := &Error{}
+	e.Add(field, code, params...)
+	return e
+}
+
+// Add добавляет нарушение
+func (e *Error) Add(field, code string, params ...interface{}) {
+	e.Violations = append(e.Violations, Violation{Field: field, Code: code, Params: params})
+}
+
+// Merge добавляет нарушения из other
+func (e *Error) Merge(other *Error) {
+	if other != nil {
+		e.Violations = append(e.Violations, other.Violations...)
+	}
+}
+
+// Err возвращает e, если нарушения есть, иначе nil. Так пустой *Error не
+// превращается в ненулевой error
+func (e *Error) Err() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+": "+v.Code)
+	}
+	return "validation failed: " + strings.Join(parts, ", ")
+}
+
+func (e *Error) Unwrap() error {
+	return models.ErrInvalidInput
+}
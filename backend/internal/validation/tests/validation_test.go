@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"errors"
+	"music-service/internal/models"
+	"music-service/internal/validation"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sample struct {
+	Name     string   `json:"name" validate:"required,min=2,max=5"`
+	Email    string   `json:"email" validate:"email"`
+	ID       string   `json:"id" validate:"uuid"`
+	Cover    string   `json:"cover" validate:"url"`
+	Mode     string   `json:"mode" validate:"oneof=off all one"`
+	Locale   string   `json:"locale" validate:"locale"`
+	Duration string   `json:"duration" validate:"duration"`
+	Released string   `json:"released" validate:"date"`
+	Count    int      `json:"count" validate:"min=0,max=10"`
+	Index    *int     `json:"index" validate:"min=1"`
+	Tags     []string `json:"tags" validate:"max=2,uuid"`
+	Page     `json:"-"`
+	Note     string    `form:"note" validate:"max=3"`
+	Ignored  string    `json:"ignored"`
+	Optional *struct{} `json:"optional"`
+}
+
+type Page struct {
+	Limit int `form:"limit" validate:"min=0"`
+}
+
+func valid() sample {
+	return sample{Name: "Abc"}
+}
+
+// codes возвращает нарушения в виде поле -> код
+func codes(t *testing.T, err error) map[string]string {
+	t.Helper()
+	if err == nil {
+		return map[string]string{}
+	}
+	var verr *validation.Error
+	require.True(t, errors.As(err, &verr), "ожидалась *validation.Error, получено %v", err)
+	got := make(map[string]string, len(verr.Violations))
+	for _, v := range verr.Violations {
+		got[v.Field] = v.Code
+	}
+	return got
+}
+
+func intPtr(n int) *int { return &n }
+
+func TestStruct_Rules(t *testing.T) {
+	id := "3f0e4c1a-6d1b-4c5e-9a8f-1b2c3d4e5f60"
+	cases := []struct {
+		name   string
+		modify func(s *sample)
+		want   map[string]string
+	}{
+		{"корректная структура", func(s *sample) {}, map[string]string{}},
+		{"пустые необязательные поля пропускаются", func(s *sample) { s.Email, s.ID, s.Mode = "", "", "" }, map[string]string{}},
+		{"обязательное поле", func(s *sample) { s.Name = "" }, map[string]string{"name": validation.CodeRequired}},
+		{"одни пробелы — пустое значение", func(s *sample) { s.Name = "   " }, map[string]string{"name": validation.CodeRequired}},
+		{"длина считается в символах", func(s *sample) { s.Name = "Ёжик" }, map[string]string{}},
+		{"кириллица длиннее лимита", func(s *sample) { s.Name = "Привет" }, map[string]string{"name": validation.CodeTooLong}},
+		{"слишком короткое", func(s *sample) { s.Name = "Я" }, map[string]string{"name": validation.CodeTooShort}},
+		{"крайние пробелы не считаются", func(s *sample) { s.Name = "  ab  " }, map[string]string{}},
+		{"email", func(s *sample) { s.Email = "Name <a@b.c>" }, map[string]string{"email": validation.CodeInvalidEmail}},
+		{"uuid", func(s *sample) { s.ID = "42" }, map[string]string{"id": validation.CodeInvalidUUID}},
+		{"корректный uuid", func(s *sample) { s.ID = id }, map[string]string{}},
+		{"url", func(s *sample) { s.Cover = "cover.jpg" }, map[string]string{"cover": validation.CodeInvalidURL}},
+		{"url от корня сайта", func(s *sample) { s.Cover = "/static/cover.jpg" }, map[string]string{}},
+		{"oneof", func(s *sample) { s.Mode = "shuffle" }, map[string]string{"mode": validation.CodeInvalidChoice}},
+		{"locale", func(s *sample) { s.Locale = "english" }, map[string]string{"locale": validation.CodeInvalidLocale}},
+		{"duration", func(s *sample) { s.Duration = "3 days" }, map[string]string{"duration": validation.CodeInvalidDuration}},
+		{"отрицательная duration", func(s *sample) { s.Duration = "-1h" }, map[string]string{"duration": validation.CodeInvalidDuration}},
+		{"date", func(s *sample) { s.Released = "1990-01-12" }, map[string]string{}},
+		{"дата со временем", func(s *sample) { s.Released = "1990-01-12T00:00:00Z" }, map[string]string{"released": validation.CodeInvalidDate}},
+		{"несуществующая дата", func(s *sample) { s.Released = "1990-02-30" }, map[string]string{"released": validation.CodeInvalidDate}},
+		{"число меньше минимума", func(s *sample) { s.Count = -1 }, map[string]string{"count": validation.CodeTooSmall}},
+		{"число больше максимума", func(s *sample) { s.Count = 11 }, map[string]string{"count": validation.CodeTooLarge}},
+		{"указатель проверяется по значению", func(s *sample) { s.Index = intPtr(0) }, map[string]string{"index": validation.CodeTooSmall}},
+		{"элементы среза", func(s *sample) { s.Tags = []string{id, "x"} }, map[string]string{"tags[1]": validation.CodeInvalidUUID}},
+		{"размер среза", func(s *sample) { s.Tags = []string{id, id, id} }, map[string]string{"tags": validation.CodeTooMany}},
+		{"встроенная структура", func(s *sample) { s.Limit = -1 }, map[string]string{"limit": validation.CodeTooSmall}},
+		{"имя из тега form", func(s *sample) { s.Note = "long" }, map[string]string{"note": validation.CodeTooLong}},
+		{"нарушения всех полей собираются вместе", func(s *sample) { s.Name, s.ID, s.Count = "", "42", 11 },
+			map[string]string{"name": validation.CodeRequired, "id": validation.CodeInvalidUUID, "count": validation.CodeTooLarge}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := valid()
+			tc.modify(&s)
+			assert.Equal(t, tc.want, codes(t, validation.Struct(&s)))
+		})
+	}
+}
+
+func TestStruct_FirstViolationPerField(t *testing.T) {
+	s := valid()
+	s.Name = ""
+
+	var verr *validation.Error
+	require.ErrorAs(t, validation.Struct(s), &verr)
+	// required сработал, min уже не проверяется
+	require.Len(t, verr.Violations, 1)
+	assert.Equal(t, validation.CodeRequired, verr.Violations[0].Code)
+}
+
+func TestStruct_Params(t *testing.T) {
+	s := valid()
+	s.Name, s.Mode = "Привет", "x"
+
+	var verr *validation.Error
+	require.ErrorAs(t, validation.Struct(s), &verr)
+	require.Len(t, verr.Violations, 2)
+	assert.Equal(t, []interface{}{5}, verr.Violations[0].Params)
+	assert.Equal(t, []interface{}{"off, all, one"}, verr.Violations[1].Params)
+}
+
+type exclusive struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (e *exclusive) Check(errs *validation.Error) {
+	if e.From != "" && e.To != "" {
+		errs.Add("to", validation.CodeConflicting)
+	}
+}
+
+func TestStruct_Checker(t *testing.T) {
+	assert.NoError(t, validation.Struct(&exclusive{From: "a"}))
+	assert.Equal(t, map[string]string{"to": validation.CodeConflicting}, codes(t, validation.Struct(&exclusive{From: "a", To: "b"})))
+	// Значение, а не указатель, тоже проверяется
+	assert.Error(t, validation.Struct(exclusive{From: "a", To: "b"}))
+}
+
+func TestStruct_UnknownRulePanics(t *testing.T) {
+	type broken struct {
+		Name string `validate:"required,lowercase"`
+	}
+	assert.Panics(t, func() { validation.Struct(broken{}) })
+}
+
+func TestError(t *testing.T) {
+	errs := &validation.Error{}
+	assert.NoError(t, errs.Err())
+
+	errs.Add("title", validation.CodeRequired)
+	errs.Merge(validation.Field("album_id", validation.CodeInvalidUUID))
+	errs.Merge(nil)
+
+	err := errs.Err()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, models.ErrInvalidInput))
+	assert.True(t, strings.Contains(err.Error(), "title: required"))
+	assert.True(t, strings.Contains(err.Error(), "album_id: invalid_uuid"))
+}
@@ -0,0 +1,280 @@
+package validation
+
+import (
+	"fmt"
+	"music-service/internal/i18n"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Struct проверяет поля структуры (или указателя на нее) по тегам validate
+// и возвращает *Error со всеми нарушениями либо nil. Имя поля в нарушении
+// берется из тега json, а если его нет — из тега form.
+//
+// Правила перечисляются через запятую:
+//
+//	required     — значение задано: строка не из одних пробелов, непустой
+//	               срез, ненулевой указатель, UUID или время
+//	min=N, max=N — длина строки в символах (без крайних пробелов), значение
+//	               числа или число элементов среза
+//	uuid         — UUID
+//	url          — URL: абсолютный или от корня сайта
+//	email        — адрес электронной почты
+//	oneof=a b c  — одно из перечисленных значений
+//	locale       — тег языка BCP 47
+//	duration     — положительная длительность вида "72h"
+//	date         — дата вида "2024-12-31"
+//
+// Правила для строк применяются и к каждому элементу среза строк. Правила,
+// кроме required, пропускают пустые строки, срезы и nil. Для каждого поля
+// возвращается только первое нарушение. Если структура реализует Checker,
+// после тегов вызывается ее Check
+func Struct(v interface{}) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validation: %T is not a struct", v))
+	}
+
+	errs := &Error{}
+	for _, field := range fieldsOf(value.Type()) {
+		for _, r := range field.rules {
+			if violation := r.check(field.name, value.FieldByIndex(field.index)); violation != nil {
+				errs.Violations = append(errs.Violations, *violation)
+				break
+			}
+		}
+	}
+	// Check может быть объявлен на указателе, поэтому структура, переданная
+	// по значению, копируется
+	if !value.CanAddr() {
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+	if checker, ok := value.Addr().Interface().(Checker); ok {
+		checker.Check(errs)
+	}
+	return errs.Err()
+}
+
+// Checker — структура с правилами, которые не выражаются тегами, например
+// взаимоисключающими полями. Check добавляет нарушения в errs
+type Checker interface {
+	Check(errs *Error)
+}
+
+type rule struct {
+	name    string
+	limit   int
+	choices []string
+}
+
+type fieldRules struct {
+	index []int
+	name  string
+	rules []rule
+}
+
+// cache хранит разобранные правила по типу структуры
+var cache sync.Map
+
+func fieldsOf(t reflect.Type) []fieldRules {
+	if cached, ok := cache.Load(t); ok {
+		return cached.([]fieldRules)
+	}
+
+	var fields []fieldRules
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("validate")
+		// Поля встроенной структуры проверяются как поля внешней
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && tag == "" {
+			for _, embedded := range fieldsOf(f.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+		if tag == "" || tag == "-" {
+			continue
+		}
+		fields = append(fields, fieldRules{index: []int{i}, name: FieldName(f), rules: parseRules(t, f, tag)})
+	}
+
+	cache.Store(t, fields)
+	return fields
+}
+
+// FieldName возвращает имя поля в запросе: из тега json, form или имя поля Go
+func FieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// parseRules разбирает тег validate. Ошибка в теге — ошибка программиста,
+// поэтому она приводит к панике при первой проверке структуры
+func parseRules(t reflect.Type, f reflect.StructField, tag string) []rule {
+	var rules []rule
+	for _, part := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		r := rule{name: name}
+		switch name {
+		case "required", "uuid", "url", "email", "locale", "duration", "date":
+		case "min", "max":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				panic(fmt.Sprintf("validation: %s.%s: invalid %s=%q", t.Name(), f.Name, name, param))
+			}
+			r.limit = n
+		case "oneof":
+			r.choices = strings.Fields(param)
+			if len(r.choices) == 0 {
+				panic(fmt.Sprintf("validation: %s.%s: empty oneof", t.Name(), f.Name))
+			}
+		default:
+			panic(fmt.Sprintf("validation: %s.%s: unknown rule %q", t.Name(), f.Name, name))
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+var (
+	uuidType = reflect.TypeOf(uuid.UUID{})
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+func (r rule) check(name string, v reflect.Value) *Violation {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if r.name == "required" {
+				return &Violation{Field: name, Code: CodeRequired}
+			}
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if r.name == "required" {
+		if isEmpty(v) {
+			return &Violation{Field: name, Code: CodeRequired}
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s := v.String()
+		if strings.TrimSpace(s) == "" {
+			return nil
+		}
+		return r.checkString(name, s)
+	case reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return nil
+		}
+		if r.name == "min" && v.Len() < r.limit {
+			return &Violation{Field: name, Code: CodeTooFew, Params: []interface{}{r.limit}}
+		}
+		if r.name == "max" && v.Len() > r.limit {
+			return &Violation{Field: name, Code: CodeTooMany, Params: []interface{}{r.limit}}
+		}
+		if r.name == "min" || r.name == "max" || v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if violation := r.checkString(fmt.Sprintf("%s[%d]", name, i), v.Index(i).String()); violation != nil {
+				return violation
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if r.name == "min" && v.Int() < int64(r.limit) {
+			return &Violation{Field: name, Code: CodeTooSmall, Params: []interface{}{r.limit}}
+		}
+		if r.name == "max" && v.Int() > int64(r.limit) {
+			return &Violation{Field: name, Code: CodeTooLarge, Params: []interface{}{r.limit}}
+		}
+	}
+	return nil
+}
+
+func (r rule) checkString(name, s string) *Violation {
+	fail := func(code string, params ...interface{}) *Violation {
+		return &Violation{Field: name, Code: code, Params: params}
+	}
+
+	switch r.name {
+	case "min":
+		if utf8.RuneCountInString(strings.TrimSpace(s)) < r.limit {
+			return fail(CodeTooShort, r.limit)
+		}
+	case "max":
+		if utf8.RuneCountInString(strings.TrimSpace(s)) > r.limit {
+			return fail(CodeTooLong, r.limit)
+		}
+	case "uuid":
+		if _, err := uuid.Parse(s); err != nil {
+			return fail(CodeInvalidUUID)
+		}
+	case "url":
+		if _, err := url.ParseRequestURI(s); err != nil {
+			return fail(CodeInvalidURL)
+		}
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		if err != nil || addr.Address != s {
+			return fail(CodeInvalidEmail)
+		}
+	case "oneof":
+		for _, choice := range r.choices {
+			if s == choice {
+				return nil
+			}
+		}
+		return fail(CodeInvalidChoice, strings.Join(r.choices, ", "))
+	case "locale":
+		if _, ok := i18n.ParseTag(s); !ok {
+			return fail(CodeInvalidLocale)
+		}
+	case "duration":
+		if d, err := time.ParseDuration(s); err != nil || d <= 0 {
+			return fail(CodeInvalidDuration)
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return fail(CodeInvalidDate)
+		}
+	}
+	return nil
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch {
+	case v.Kind() == reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Map:
+		return v.Len() == 0
+	case v.Type() == uuidType || v.Type() == timeType:
+		return v.IsZero()
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS playback_queue;
+DROP TABLE IF EXISTS playback_states;
+
+DROP INDEX IF EXISTS idx_sessions_user_device;
+ALTER TABLE sessions DROP COLUMN IF EXISTS device_type;
+ALTER TABLE sessions DROP COLUMN IF EXISTS device_name;
+ALTER TABLE sessions DROP COLUMN IF EXISTS device_id;
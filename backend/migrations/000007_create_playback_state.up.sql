@@ -0,0 +1,29 @@
+-- Привязка сессий к устройствам пользователя
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS device_id UUID;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS device_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS device_type VARCHAR(50) NOT NULL DEFAULT '';
+UPDATE sessions SET device_id = id WHERE device_id IS NULL;
+ALTER TABLE sessions ALTER COLUMN device_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_device ON sessions (user_id, device_id);
+
+-- Состояние воспроизведения пользователя
+CREATE TABLE IF NOT EXISTS playback_states (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    current_index INTEGER NOT NULL DEFAULT 0,
+    position_ms INTEGER NOT NULL DEFAULT 0,
+    is_playing BOOLEAN NOT NULL DEFAULT FALSE,
+    shuffle BOOLEAN NOT NULL DEFAULT FALSE,
+    shuffle_order INTEGER[] NOT NULL DEFAULT '{}',
+    repeat_mode VARCHAR(10) NOT NULL DEFAULT 'off',
+    active_device_id UUID,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Очередь воспроизведения в порядке добавления
+CREATE TABLE IF NOT EXISTS playback_queue (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    track_id UUID NOT NULL REFERENCES tracks(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, position)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS artist_follows;
+DROP TABLE IF EXISTS playlist_follows;
@@ -0,0 +1,20 @@
+-- Подписки пользователей на чужие плейлисты
+CREATE TABLE IF NOT EXISTS playlist_follows (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    playlist_id UUID NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    followed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, playlist_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_follows_playlist_id ON playlist_follows (playlist_id);
+
+-- Подписки пользователей на исполнителей. Исполнитель хранится строкой,
+-- как в albums.artist и tracks.artist_name, и сравнивается без учета регистра
+CREATE TABLE IF NOT EXISTS artist_follows (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    artist VARCHAR(255) NOT NULL,
+    followed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artist_follows_user_artist ON artist_follows (user_id, LOWER(artist));
+CREATE INDEX IF NOT EXISTS idx_artist_follows_artist ON artist_follows (LOWER(artist));
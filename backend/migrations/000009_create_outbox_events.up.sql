@@ -0,0 +1,20 @@
+-- Доменные события, записываемые в одной транзакции с изменением состояния
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY,
+    event_type VARCHAR(100) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP,
+    processed_at TIMESTAMP,
+    failed_at TIMESTAMP,
+    last_error TEXT
+);
+
+-- Частичный индекс по событиям, ожидающим доставки
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (next_attempt_at)
+    WHERE processed_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_processed_at ON outbox_events (processed_at)
+    WHERE processed_at IS NOT NULL;
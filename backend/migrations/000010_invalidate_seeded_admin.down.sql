@@ -0,0 +1,2 @@
+-- Отозванные сессии и замененные пароли не восстанавливаются
+SELECT 1;
@@ -0,0 +1,16 @@
+-- Отзываем фиксированную сессию администратора из миграции 000006
+DELETE FROM sessions
+WHERE id = '22222222-2222-2222-2222-222222222222'
+   OR token = '33333333-3333-3333-3333-333333333333';
+
+-- Пароли не в формате bcrypt и известный хеш, который раньше принимался
+-- для пароля adminpass, заменяются заведомо неверным значением: войти с
+-- ними нельзя, пароль задается заново командой cmd/admin set-password
+UPDATE users
+SET password = '!', updated_at = NOW()
+WHERE password NOT LIKE '$2%'
+   OR password = '$2a$10$8KAqeCKaMvhuiCewKQkCE.Lz4R9tGQcu/mLRBJ2QQRJ9TY/avlZRa';
+
+-- У пользователей с обезвреженным паролем не должно остаться сессий
+DELETE FROM sessions
+WHERE user_id IN (SELECT id FROM users WHERE password = '!');
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS revoked_sessions;
+DROP TABLE IF EXISTS refresh_tokens;
+
+-- Сессии без токенов восстановить нельзя
+DELETE FROM sessions;
+ALTER TABLE sessions DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS created_at;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS token VARCHAR(255) NOT NULL UNIQUE;
@@ -0,0 +1,28 @@
+-- Сессия теперь — семейство refresh-токенов. Непрозрачные токены больше не
+-- используются, поэтому существующие сессии завершаются
+DELETE FROM sessions;
+ALTER TABLE sessions DROP COLUMN IF EXISTS token;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP;
+
+-- Refresh-токены хранятся в виде SHA-256. Использованный токен остается в
+-- таблице до конца жизни сессии, чтобы распознать его повторное предъявление
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session ON refresh_tokens (session_id);
+
+-- Отзывы сессий хранятся отдельно от sessions, чтобы переживать каскадное
+-- удаление пользователя: access-токены действуют до expires_at
+CREATE TABLE IF NOT EXISTS revoked_sessions (
+    session_id UUID PRIMARY KEY,
+    revoked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_sessions_expires ON revoked_sessions (expires_at);
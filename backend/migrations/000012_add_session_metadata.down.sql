@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_sessions_expires;
+ALTER TABLE sessions DROP COLUMN IF EXISTS last_seen_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE sessions DROP COLUMN IF EXISTS user_agent;
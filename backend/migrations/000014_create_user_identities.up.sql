@@ -0,0 +1,24 @@
+-- Учетные записи внешних провайдеров OpenID Connect, привязанные к пользователям
+CREATE TABLE IF NOT EXISTS user_identities (
+    provider VARCHAR(64) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user ON user_identities (user_id);
+
+-- Незавершенные входы через провайдера: state связывает ответ провайдера
+-- с запросом, code_verifier и nonce проверяются при обмене кода
+CREATE TABLE IF NOT EXISTS oauth_states (
+    state CHAR(64) PRIMARY KEY,
+    provider VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    link_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_states_expires ON oauth_states (expires_at);
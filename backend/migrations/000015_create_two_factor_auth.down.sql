@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS mfa_required_roles;
+DROP TABLE IF EXISTS mfa_challenges;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS user_totp;
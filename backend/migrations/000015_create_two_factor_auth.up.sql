@@ -0,0 +1,37 @@
+-- Секреты TOTP. Пока confirmed_at пуст, подключение не завершено и второй
+-- фактор не запрашивается. last_used_step защищает от повторного
+-- использования кода
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Одноразовые коды восстановления; хранится только SHA-256 кода
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    PRIMARY KEY (user_id, code_hash)
+);
+
+-- Входы, ожидающие второго фактора: пароль проверен, сессия еще не создана
+CREATE TABLE IF NOT EXISTS mfa_challenges (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id UUID NOT NULL,
+    device_name VARCHAR(255) NOT NULL DEFAULT '',
+    device_type VARCHAR(50) NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_challenges_expires ON mfa_challenges (expires_at);
+
+-- Роли, для которых второй фактор обязателен
+CREATE TABLE IF NOT EXISTS mfa_required_roles (
+    role VARCHAR(50) PRIMARY KEY,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS audit_log;
+
+DROP INDEX IF EXISTS idx_users_created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_by;
+ALTER TABLE users DROP COLUMN IF EXISTS suspension_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_until;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+DROP FUNCTION IF EXISTS audit_log_forbid_update();
+
+ALTER TABLE audit_log DROP COLUMN IF EXISTS request_id;
+ALTER TABLE audit_log DROP COLUMN IF EXISTS ip;
+ALTER TABLE audit_log DROP COLUMN IF EXISTS changes;
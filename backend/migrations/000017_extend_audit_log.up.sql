@@ -0,0 +1,17 @@
+-- Журнал ведется для всех изменяющих действий: изменения полей до и после,
+-- адрес клиента и идентификатор запроса
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS changes JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS ip VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS request_id VARCHAR(128) NOT NULL DEFAULT '';
+
+-- Записи журнала не изменяются; удаляются только старые записи по сроку хранения
+CREATE OR REPLACE FUNCTION audit_log_forbid_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_forbid_update();
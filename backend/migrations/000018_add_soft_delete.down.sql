@@ -0,0 +1,19 @@
+-- Строки из корзины удаляются окончательно: без deleted_at их нельзя скрыть
+DELETE FROM tracks WHERE deleted_at IS NOT NULL;
+DELETE FROM albums WHERE deleted_at IS NOT NULL;
+DELETE FROM playlists WHERE deleted_at IS NOT NULL;
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE tracks DROP CONSTRAINT IF EXISTS tracks_album_id_fkey;
+ALTER TABLE tracks ADD CONSTRAINT tracks_album_id_fkey
+    FOREIGN KEY (album_id) REFERENCES albums(id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+DROP INDEX IF EXISTS idx_playlists_deleted_at;
+DROP INDEX IF EXISTS idx_albums_deleted_at;
+DROP INDEX IF EXISTS idx_tracks_deleted_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE playlists DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE albums DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE tracks DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,18 @@
+-- Мягкое удаление: строки с deleted_at находятся в корзине и скрыты от
+-- обычных запросов. Окончательно они удаляются фоновой очисткой по истечении
+-- срока хранения
+ALTER TABLE tracks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE albums ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE playlists ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tracks_deleted_at ON tracks (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_albums_deleted_at ON albums (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_playlists_deleted_at ON playlists (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Удаление альбома больше не удаляет каскадом его треки (а с ними историю
+-- прослушиваний): треки удаляются явно, оставшиеся лишаются альбома
+ALTER TABLE tracks DROP CONSTRAINT IF EXISTS tracks_album_id_fkey;
+ALTER TABLE tracks ADD CONSTRAINT tracks_album_id_fkey
+    FOREIGN KEY (album_id) REFERENCES albums(id) ON DELETE SET NULL;
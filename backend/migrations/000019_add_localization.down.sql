@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS genre_translations;
+DROP TABLE IF EXISTS album_translations;
+DROP TABLE IF EXISTS track_translations;
+
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,32 @@
+-- Предпочитаемый язык пользователя; NULL — язык берется из Accept-Language
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(16);
+
+-- Переводы и транслитерации полей каталога. locale — тег BCP 47: "en",
+-- "ru-Latn" (транслитерация латиницей) и т.п. Каждое поле хранится
+-- отдельной строкой, поэтому перевод может задавать только часть полей
+CREATE TABLE IF NOT EXISTS track_translations (
+    track_id UUID NOT NULL REFERENCES tracks(id) ON DELETE CASCADE,
+    locale VARCHAR(16) NOT NULL,
+    field VARCHAR(32) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (track_id, locale, field)
+);
+
+CREATE TABLE IF NOT EXISTS album_translations (
+    album_id UUID NOT NULL REFERENCES albums(id) ON DELETE CASCADE,
+    locale VARCHAR(16) NOT NULL,
+    field VARCHAR(32) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (album_id, locale, field)
+);
+
+CREATE TABLE IF NOT EXISTS genre_translations (
+    genre_id UUID NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    locale VARCHAR(16) NOT NULL,
+    field VARCHAR(32) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (genre_id, locale, field)
+);
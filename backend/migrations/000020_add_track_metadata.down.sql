@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_tracks_isrc;
+
+ALTER TABLE tracks
+    DROP COLUMN IF EXISTS release_date,
+    DROP COLUMN IF EXISTS lyrics_url,
+    DROP COLUMN IF EXISTS isrc,
+    DROP COLUMN IF EXISTS explicit,
+    DROP COLUMN IF EXISTS disc_number,
+    DROP COLUMN IF EXISTS track_number,
+    DROP COLUMN IF EXISTS artists;
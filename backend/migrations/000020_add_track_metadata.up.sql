@@ -0,0 +1,15 @@
+-- Расширенные метаданные трека. artists — все исполнители по порядку,
+-- первый из них дублируется в artist_name. Необязательные поля — NULL
+ALTER TABLE tracks
+    ADD COLUMN IF NOT EXISTS artists TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS track_number INTEGER,
+    ADD COLUMN IF NOT EXISTS disc_number INTEGER,
+    ADD COLUMN IF NOT EXISTS explicit BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS isrc VARCHAR(12),
+    ADD COLUMN IF NOT EXISTS lyrics_url VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS release_date DATE;
+
+UPDATE tracks SET artists = ARRAY[artist_name] WHERE artists = '{}';
+
+-- Одна запись может выходить на нескольких релизах, поэтому ISRC не уникален
+CREATE INDEX IF NOT EXISTS idx_tracks_isrc ON tracks(isrc) WHERE isrc IS NOT NULL;
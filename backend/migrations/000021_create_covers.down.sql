@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS artist_covers;
+DROP TABLE IF EXISTS covers;
@@ -0,0 +1,23 @@
+-- Загруженные обложки треков, альбомов, плейлистов и исполнителей. Файлы
+-- (оригинал без метаданных и уменьшенные копии) лежат в хранилище треков в
+-- covers/<id[0:2]>/<id[2:4]>/<id>/. Трек, альбом или плейлист ссылается на
+-- обложку через cover_url вида /api/v1/covers/<id>
+CREATE TABLE IF NOT EXISTS covers (
+    id UUID PRIMARY KEY,
+    format VARCHAR(16) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Обложки исполнителей. Исполнитель хранится строкой, как в artist_follows,
+-- и сравнивается без учета регистра
+CREATE TABLE IF NOT EXISTS artist_covers (
+    artist VARCHAR(255) NOT NULL,
+    cover_id UUID NOT NULL REFERENCES covers(id) ON DELETE CASCADE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artist_covers_artist ON artist_covers (LOWER(artist));
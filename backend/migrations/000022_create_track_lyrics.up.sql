@@ -0,0 +1,24 @@
+-- Тексты песен. text хранится как загружен (LRC для синхронизированного
+-- текста), plain_text — без меток времени, по нему строится полнотекстовый
+-- поиск. Конфигурация simple не зависит от языка: тексты бывают на разных
+-- языках. Пока текст не одобрен модератором, он виден только модераторам и
+-- автору
+CREATE TABLE IF NOT EXISTS track_lyrics (
+    track_id UUID PRIMARY KEY REFERENCES tracks(id) ON DELETE CASCADE,
+    language VARCHAR(16),
+    synced BOOLEAN NOT NULL DEFAULT FALSE,
+    text TEXT NOT NULL,
+    plain_text TEXT NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    submitted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', plain_text)) STORED
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_lyrics_search ON track_lyrics USING GIN (search_vector);
+-- Очередь модерации
+CREATE INDEX IF NOT EXISTS idx_track_lyrics_status ON track_lyrics (status, updated_at DESC);
@@ -0,0 +1,7 @@
+ALTER TABLE albums
+    DROP COLUMN IF EXISTS true_peak_dbtp,
+    DROP COLUMN IF EXISTS integrated_lufs;
+
+ALTER TABLE tracks
+    DROP COLUMN IF EXISTS true_peak_dbtp,
+    DROP COLUMN IF EXISTS integrated_lufs;
@@ -0,0 +1,10 @@
+-- Громкость по EBU R128: интегральная громкость (LUFS) и истинный пик
+-- (dBTP). NULL — трек не измерен. Громкость альбома пересчитывается по
+-- измеренным трекам при каждом изменении их состава
+ALTER TABLE tracks
+    ADD COLUMN IF NOT EXISTS integrated_lufs DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS true_peak_dbtp DOUBLE PRECISION;
+
+ALTER TABLE albums
+    ADD COLUMN IF NOT EXISTS integrated_lufs DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS true_peak_dbtp DOUBLE PRECISION;
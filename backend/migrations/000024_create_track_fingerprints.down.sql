@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS track_duplicates;
+DROP TABLE IF EXISTS track_fingerprint_hashes;
+DROP TABLE IF EXISTS track_fingerprints;
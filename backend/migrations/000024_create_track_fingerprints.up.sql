@@ -0,0 +1,28 @@
+-- Акустические отпечатки треков: суботпечатки uint32 little-endian
+CREATE TABLE IF NOT EXISTS track_fingerprints (
+    track_id UUID PRIMARY KEY REFERENCES tracks(id) ON DELETE CASCADE,
+    fingerprint BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Индекс сходства: ключи отпечатков. Треки с большим числом общих ключей —
+-- кандидаты на дубликаты, их отпечатки сравниваются целиком
+CREATE TABLE IF NOT EXISTS track_fingerprint_hashes (
+    hash INTEGER NOT NULL,
+    track_id UUID NOT NULL REFERENCES tracks(id) ON DELETE CASCADE,
+    PRIMARY KEY (hash, track_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_fingerprint_hashes_track ON track_fingerprint_hashes (track_id);
+
+-- Подозреваемые дубликаты. Пара хранится один раз: track_id < duplicate_id
+CREATE TABLE IF NOT EXISTS track_duplicates (
+    track_id UUID NOT NULL REFERENCES tracks(id) ON DELETE CASCADE,
+    duplicate_id UUID NOT NULL REFERENCES tracks(id) ON DELETE CASCADE,
+    similarity DOUBLE PRECISION NOT NULL,
+    detected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (track_id, duplicate_id),
+    CHECK (track_id < duplicate_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_duplicates_duplicate ON track_duplicates (duplicate_id);
@@ -0,0 +1,9 @@
+-- Отметки об обработке событий outbox отдельными обработчиками. Доставка
+-- at-least-once: отметка записывается в одной транзакции с побочным
+-- эффектом обработчика, и повторная доставка его не повторяет
+CREATE TABLE IF NOT EXISTS outbox_handled_events (
+    event_id UUID NOT NULL REFERENCES outbox_events(id) ON DELETE CASCADE,
+    handler VARCHAR(100) NOT NULL,
+    handled_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, handler)
+);
@@ -12,4 +12,15 @@ const Login = async (login, password) => {
     });
 };
 
-export {Login}
\ No newline at end of file
+const VerifyMFA = async (mfa_token, code) => {
+    return fetch(`${domain}/users/auth/mfa`, {
+        method: "POST",
+        headers: {
+            "Content-Type": "application/json"
+        },
+        body: JSON.stringify({ mfa_token, code }),
+        credentials: "include"
+    });
+};
+
+export {Login, VerifyMFA}
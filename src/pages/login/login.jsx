@@ -1,77 +1,134 @@
-import { Input } from "@components/input/input";
-import { Button } from "@components/button/button";
-import { Login } from "./login-api";
-import { AuthContext } from "@/features/auth-provider/auth-provider";
-import styles from "./login.module.css";
-import { useState } from "react";
-import { useNavigate } from "react-router-dom";
-import { useContext } from "react";
-
-const LoginPage = () => {
-  const [form, setForm] = useState({ login: "", password: "" });
-  const [errors, setErrors] = useState({ password: "" });
-  const navigate = useNavigate();
-  const { login: authLogin} = useContext(AuthContext);
-  const onChange = (e) => {
-    const { name, value } = e.target;
-    setForm((prev) => ({
-      ...prev,
-      [name]: value,
-    }));
-    setErrors({ password: "" });
-  };
-
-  const handleSubmit = async (e) => {
-    e.preventDefault();
-    if (errors.password == "" && form.login != "" && form.password != "") {
-      const response = await Login(form.login, form.password);
-      if (response.ok) {
-        const { user, session } = await response.json();
-        authLogin({ login: user.login, id: user.id, permission: user.permission, token: session.token });
-        navigate("/servise");
-      } else {
-        setErrors({
-          password: "Неправильный логин или пароль",
-        });
-      }
-    }
-  };
-
-  const toRegister = (e) => {
-    e.preventDefault();
-    navigate("/register");
-  };
-
-  return (
-    <main className={styles.main}>
-      <form className={styles.form}>
-        <h3>Log In</h3>
-        <Input
-          text="Логин"
-          name="login"
-          label="Логин"
-          type="text"
-          autoComplete="login"
-          required
-          value={form.login}
-          onChange={onChange}
-        />
-        <Input
-          label="Пароль"
-          text="Пароль"
-          name="password"
-          error={errors.password}
-          type="password"
-          autoComplete="current-password"
-          required
-          value={form.password}
-          onChange={onChange}
-        />
-        <Button text="Войти" onClick={handleSubmit} type="submit" />
-        <Button text="Нет аккаунта" onClick={toRegister} />
-      </form>
-    </main>
-  );
-};
-
-export { LoginPage };
+import { Input } from "@components/input/input";
+import { Button } from "@components/button/button";
+import { Login, VerifyMFA } from "./login-api";
+import { AuthContext } from "@/features/auth-provider/auth-provider";
+import styles from "./login.module.css";
+import { useState } from "react";
+import { useNavigate } from "react-router-dom";
+import { useContext } from "react";
+
+const LoginPage = () => {
+  const [form, setForm] = useState({ login: "", password: "" });
+  const [errors, setErrors] = useState({ password: "" });
+  const [mfa, setMfa] = useState({ token: "", code: "", error: "" });
+  const navigate = useNavigate();
+  const { login: authLogin} = useContext(AuthContext);
+  const onChange = (e) => {
+    const { name, value } = e.target;
+    setForm((prev) => ({
+      ...prev,
+      [name]: value,
+    }));
+    setErrors({ password: "" });
+  };
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    if (errors.password == "" && form.login != "" && form.password != "") {
+      const response = await Login(form.login, form.password);
+      if (response.ok) {
+        const data = await response.json();
+        if (data.mfa_required) {
+          if (data.enrollment_required) {
+            setErrors({
+              password: "Для входа подключите двухфакторную аутентификацию",
+            });
+            return;
+          }
+          setMfa({ token: data.mfa_token, code: "", error: "" });
+          return;
+        }
+        finishLogin(data);
+      } else if (response.status == 429) {
+        setErrors({
+          password: "Слишком много попыток входа, попробуйте позже",
+        });
+      } else {
+        setErrors({
+          password: "Неправильный логин или пароль",
+        });
+      }
+    }
+  };
+
+  const finishLogin = ({ user, access_token }) => {
+    authLogin({ login: user.login, id: user.id, permission: user.permission, token: access_token });
+    navigate("/servise");
+  };
+
+  const handleMfaSubmit = async (e) => {
+    e.preventDefault();
+    if (mfa.code == "") {
+      return;
+    }
+    const response = await VerifyMFA(mfa.token, mfa.code);
+    if (response.ok) {
+      finishLogin(await response.json());
+    } else if (response.status == 401) {
+      const { detail } = await response.json();
+      setMfa((prev) => ({ ...prev, code: "", error: detail }));
+    } else {
+      setMfa((prev) => ({ ...prev, error: "Ошибка сервера" }));
+    }
+  };
+
+  const toRegister = (e) => {
+    e.preventDefault();
+    navigate("/register");
+  };
+
+  if (mfa.token != "") {
+    return (
+      <main className={styles.main}>
+        <form className={styles.form}>
+          <h3>Подтверждение входа</h3>
+          <Input
+            text="Код из приложения или код восстановления"
+            name="code"
+            label="Код"
+            type="text"
+            autoComplete="one-time-code"
+            required
+            error={mfa.error}
+            value={mfa.code}
+            onChange={(e) => setMfa((prev) => ({ ...prev, code: e.target.value, error: "" }))}
+          />
+          <Button text="Подтвердить" onClick={handleMfaSubmit} type="submit" />
+        </form>
+      </main>
+    );
+  }
+
+  return (
+    <main className={styles.main}>
+      <form className={styles.form}>
+        <h3>Log In</h3>
+        <Input
+          text="Логин"
+          name="login"
+          label="Логин"
+          type="text"
+          autoComplete="login"
+          required
+          value={form.login}
+          onChange={onChange}
+        />
+        <Input
+          label="Пароль"
+          text="Пароль"
+          name="password"
+          error={errors.password}
+          type="password"
+          autoComplete="current-password"
+          required
+          value={form.password}
+          onChange={onChange}
+        />
+        <Button text="Войти" onClick={handleSubmit} type="submit" />
+        <Button text="Нет аккаунта" onClick={toRegister} />
+      </form>
+    </main>
+  );
+};
+
+export { LoginPage };